@@ -65,7 +65,12 @@ func run() error {
 	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
 	// api server
-	shutdown, err := api.NewAPI(&cfg, repo).Start(ctx)
+	apiSrv, err := api.NewAPI(&cfg, repo)
+	if err != nil {
+		return fmt.Errorf("failed to setup api: %w", err)
+	}
+
+	shutdown, err := apiSrv.Start(ctx)
 	if err != nil {
 		return fmt.Errorf("server failed to start: %w", err)
 	}
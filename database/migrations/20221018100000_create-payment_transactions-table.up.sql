@@ -0,0 +1,15 @@
+CREATE TABLE "payment_transactions" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "currency" currency not null,
+    "amount" decimal(32, 16) check(amount > 0) not null,
+    "processor_reference" varchar(255) not null,
+    "paid_at" timestamp not null,
+    "payment_installment_id" uuid not null,
+    CONSTRAINT fk_payment_installments
+        FOREIGN KEY(payment_installment_id)
+        REFERENCES payment_installments(id)
+);
+
+CREATE INDEX payment_transactions_payment_installment_id_idx ON payment_transactions (payment_installment_id);
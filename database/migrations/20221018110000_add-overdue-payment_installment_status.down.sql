@@ -0,0 +1,14 @@
+UPDATE payment_installments SET status = 'due' WHERE status = 'overdue';
+
+ALTER TYPE payment_installment_status RENAME TO payment_installment_status_old;
+
+CREATE TYPE "payment_installment_status" AS ENUM (
+    'pending',
+    'paid',
+    'due'
+);
+
+ALTER TABLE payment_installments
+    ALTER COLUMN status TYPE payment_installment_status USING status::text::payment_installment_status;
+
+DROP TYPE payment_installment_status_old;
@@ -0,0 +1 @@
+ALTER TYPE payment_installment_status ADD VALUE 'overdue';
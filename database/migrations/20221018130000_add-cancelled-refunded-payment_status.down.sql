@@ -0,0 +1,29 @@
+UPDATE payment_plans SET status = 'pending' WHERE status = 'cancelled';
+UPDATE payment_plans SET status = 'complete' WHERE status = 'refunded';
+UPDATE payment_installments SET status = 'pending' WHERE status = 'void';
+
+ALTER TYPE payment_status RENAME TO payment_status_old;
+
+CREATE TYPE "payment_status" AS ENUM (
+    'pending',
+    'complete'
+);
+
+ALTER TABLE payment_plans
+    ALTER COLUMN status TYPE payment_status USING status::text::payment_status;
+
+DROP TYPE payment_status_old;
+
+ALTER TYPE payment_installment_status RENAME TO payment_installment_status_old;
+
+CREATE TYPE "payment_installment_status" AS ENUM (
+    'pending',
+    'paid',
+    'due',
+    'overdue'
+);
+
+ALTER TABLE payment_installments
+    ALTER COLUMN status TYPE payment_installment_status USING status::text::payment_installment_status;
+
+DROP TYPE payment_installment_status_old;
@@ -0,0 +1,4 @@
+ALTER TYPE payment_status ADD VALUE 'cancelled';
+ALTER TYPE payment_status ADD VALUE 'refunded';
+
+ALTER TYPE payment_installment_status ADD VALUE 'void';
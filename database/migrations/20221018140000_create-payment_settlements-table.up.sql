@@ -0,0 +1,15 @@
+CREATE TABLE "payment_settlements" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "currency" currency not null,
+    "amount" decimal(32, 16) check(amount > 0) not null,
+    "processor_reference" varchar(255) not null,
+    "paid_at" timestamp not null,
+    "payment_plan_id" uuid not null,
+    CONSTRAINT fk_payment_plans
+        FOREIGN KEY(payment_plan_id)
+        REFERENCES payment_plans(id)
+);
+
+CREATE INDEX payment_settlements_payment_plan_id_idx ON payment_settlements (payment_plan_id);
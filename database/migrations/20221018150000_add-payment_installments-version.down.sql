@@ -0,0 +1,19 @@
+ALTER TABLE payment_installments DROP COLUMN "version";
+
+-- a superseded installment is no longer payable, it is kept as void
+UPDATE payment_installments SET status = 'void' WHERE status = 'superseded';
+
+ALTER TYPE payment_installment_status RENAME TO payment_installment_status_old;
+
+CREATE TYPE "payment_installment_status" AS ENUM (
+    'pending',
+    'paid',
+    'due',
+    'overdue',
+    'void'
+);
+
+ALTER TABLE payment_installments
+    ALTER COLUMN status TYPE payment_installment_status USING status::text::payment_installment_status;
+
+DROP TYPE payment_installment_status_old;
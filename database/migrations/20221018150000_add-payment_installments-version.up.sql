@@ -0,0 +1,3 @@
+ALTER TYPE payment_installment_status ADD VALUE 'superseded';
+
+ALTER TABLE payment_installments ADD COLUMN "version" integer not null default 1 check(version > 0);
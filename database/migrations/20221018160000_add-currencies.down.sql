@@ -0,0 +1,21 @@
+-- amounts cannot be converted back to usdc, the migration fails while a plan in another currency is stored
+ALTER TYPE currency RENAME TO currency_old;
+
+CREATE TYPE "currency" AS ENUM (
+    'usdc'
+);
+
+ALTER TABLE payment_plans
+    ALTER COLUMN currency TYPE currency USING currency::text::currency;
+ALTER TABLE payment_installments
+    ALTER COLUMN currency TYPE currency USING currency::text::currency;
+ALTER TABLE payment_transactions
+    ALTER COLUMN currency TYPE currency USING currency::text::currency;
+ALTER TABLE payment_late_fees
+    ALTER COLUMN currency TYPE currency USING currency::text::currency;
+ALTER TABLE payment_refunds
+    ALTER COLUMN currency TYPE currency USING currency::text::currency;
+ALTER TABLE payment_settlements
+    ALTER COLUMN currency TYPE currency USING currency::text::currency;
+
+DROP TYPE currency_old;
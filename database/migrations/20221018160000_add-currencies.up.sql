@@ -0,0 +1,4 @@
+ALTER TYPE currency ADD VALUE 'usdt';
+ALTER TYPE currency ADD VALUE 'usd';
+ALTER TYPE currency ADD VALUE 'eur';
+ALTER TYPE currency ADD VALUE 'sgd';
@@ -0,0 +1,5 @@
+DROP TRIGGER payment_installments_status_transition ON payment_installments;
+DROP FUNCTION check_payment_installment_status_transition();
+
+DROP TRIGGER payment_plans_status_transition ON payment_plans;
+DROP FUNCTION check_payment_plan_status_transition();
@@ -0,0 +1,54 @@
+-- the transitions allowed by the statemachine package, a status can be written again unchanged
+CREATE FUNCTION check_payment_plan_status_transition() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    IF (OLD.status::text, NEW.status::text) NOT IN (
+        ('pending', 'complete'),
+        ('pending', 'cancelled'),
+        ('complete', 'refunded')
+    ) THEN
+        RAISE EXCEPTION 'payment plan % cannot go from % to %', OLD.id, OLD.status, NEW.status
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payment_plans_status_transition
+    BEFORE UPDATE OF status ON payment_plans
+    FOR EACH ROW EXECUTE FUNCTION check_payment_plan_status_transition();
+
+CREATE FUNCTION check_payment_installment_status_transition() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    IF (OLD.status::text, NEW.status::text) NOT IN (
+        ('pending', 'due'),
+        ('pending', 'paid'),
+        ('pending', 'void'),
+        ('pending', 'superseded'),
+        ('due', 'overdue'),
+        ('due', 'paid'),
+        ('due', 'void'),
+        ('due', 'superseded'),
+        ('overdue', 'paid'),
+        ('overdue', 'void'),
+        ('overdue', 'superseded')
+    ) THEN
+        RAISE EXCEPTION 'payment installment % cannot go from % to %', OLD.id, OLD.status, NEW.status
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payment_installments_status_transition
+    BEFORE UPDATE OF status ON payment_installments
+    FOR EACH ROW EXECUTE FUNCTION check_payment_installment_status_transition();
@@ -0,0 +1,5 @@
+DROP INDEX payment_plans_user_id_idx;
+
+DROP TABLE "credit_lines";
+
+DROP TYPE "credit_line_status";
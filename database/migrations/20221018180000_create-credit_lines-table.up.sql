@@ -0,0 +1,20 @@
+CREATE TYPE "credit_line_status" AS ENUM (
+    'active',
+    'frozen',
+    'closed'
+);
+
+CREATE TABLE "credit_lines" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "user_id" uuid not null,
+    "currency" currency not null,
+    "credit_limit" decimal(32, 16) check(credit_limit >= 0) not null,
+    "status" credit_line_status not null
+);
+
+CREATE UNIQUE INDEX credit_lines_user_id_idx ON credit_lines (user_id);
+
+-- the unpaid installments of a user are summed when the available credit is computed
+CREATE INDEX payment_plans_user_id_idx ON payment_plans (user_id);
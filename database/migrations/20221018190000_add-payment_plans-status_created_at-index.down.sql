@@ -0,0 +1 @@
+DROP INDEX payment_plans_status_created_at_idx;
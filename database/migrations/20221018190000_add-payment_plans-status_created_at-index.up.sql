@@ -0,0 +1,2 @@
+-- the pending plans are expired oldest first
+CREATE INDEX payment_plans_status_created_at_idx ON payment_plans (status, created_at);
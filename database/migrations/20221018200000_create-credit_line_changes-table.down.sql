@@ -0,0 +1,8 @@
+DROP INDEX credit_line_changes_credit_line_id_idx;
+
+DROP TABLE "credit_line_changes";
+
+DROP TYPE "credit_line_action";
+
+DROP TRIGGER credit_lines_status_transition ON credit_lines;
+DROP FUNCTION check_credit_line_status_transition();
@@ -0,0 +1,50 @@
+-- the transitions allowed by the statemachine package, a status can be written again unchanged
+CREATE FUNCTION check_credit_line_status_transition() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    IF (OLD.status::text, NEW.status::text) NOT IN (
+        ('active', 'frozen'),
+        ('active', 'closed'),
+        ('frozen', 'active'),
+        ('frozen', 'closed')
+    ) THEN
+        RAISE EXCEPTION 'credit line % cannot go from % to %', OLD.id, OLD.status, NEW.status
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER credit_lines_status_transition
+    BEFORE UPDATE OF status ON credit_lines
+    FOR EACH ROW EXECUTE FUNCTION check_credit_line_status_transition();
+
+CREATE TYPE "credit_line_action" AS ENUM (
+    'create',
+    'update_limit',
+    'freeze',
+    'unfreeze',
+    'close'
+);
+
+-- every change made to a credit line with who made it and why, the limit and status are the ones it left
+CREATE TABLE "credit_line_changes" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "credit_line_id" uuid not null,
+    "currency" currency not null,
+    "action" credit_line_action not null,
+    "actor" varchar(255) not null,
+    "reason" varchar(255) not null,
+    "credit_limit" decimal(32, 16) check(credit_limit >= 0) not null,
+    "status" credit_line_status not null,
+    CONSTRAINT fk_credit_lines
+        FOREIGN KEY(credit_line_id)
+        REFERENCES credit_lines(id)
+);
+
+CREATE INDEX credit_line_changes_credit_line_id_idx ON credit_line_changes (credit_line_id);
@@ -0,0 +1,20 @@
+DROP TRIGGER journal_postings_balance ON journal_postings;
+DROP FUNCTION check_journal_entry_balance();
+
+DROP TRIGGER journal_postings_append_only ON journal_postings;
+DROP TRIGGER journal_entries_append_only ON journal_entries;
+DROP FUNCTION reject_ledger_change();
+
+DROP INDEX journal_postings_journal_entry_id_idx;
+
+DROP TABLE "journal_postings";
+
+DROP INDEX journal_entries_payment_plan_id_idx;
+
+DROP TABLE "journal_entries";
+
+DROP TYPE "journal_entry_kind";
+
+DROP TYPE "posting_direction";
+
+DROP TYPE "ledger_account";
@@ -0,0 +1,88 @@
+CREATE TYPE "ledger_account" AS ENUM (
+    'cash',
+    'user_receivable',
+    'merchant_payable',
+    'fee_income'
+);
+
+CREATE TYPE "posting_direction" AS ENUM (
+    'debit',
+    'credit'
+);
+
+CREATE TYPE "journal_entry_kind" AS ENUM (
+    'plan_created',
+    'installment_paid',
+    'plan_paid_off',
+    'late_fee_assessed',
+    'refunded',
+    'receivable_written_off'
+);
+
+-- seq keeps the order entries were written in, those of one transaction share their created_at
+CREATE TABLE "journal_entries" (
+    "id" uuid PRIMARY KEY,
+    "seq" bigserial not null,
+    "created_at" timestamp not null default current_timestamp,
+    "payment_plan_id" uuid not null,
+    "kind" journal_entry_kind not null,
+    CONSTRAINT fk_payment_plans
+        FOREIGN KEY(payment_plan_id)
+        REFERENCES payment_plans(id)
+);
+
+CREATE INDEX journal_entries_payment_plan_id_idx ON journal_entries (payment_plan_id);
+
+CREATE TABLE "journal_postings" (
+    "id" uuid PRIMARY KEY,
+    "seq" bigserial not null,
+    "created_at" timestamp not null default current_timestamp,
+    "journal_entry_id" uuid not null,
+    "account" ledger_account not null,
+    "direction" posting_direction not null,
+    "currency" currency not null,
+    "amount" decimal(32, 16) check(amount > 0) not null,
+    CONSTRAINT fk_journal_entries
+        FOREIGN KEY(journal_entry_id)
+        REFERENCES journal_entries(id)
+);
+
+CREATE INDEX journal_postings_journal_entry_id_idx ON journal_postings (journal_entry_id);
+
+-- the ledger is append-only, a mistake is corrected by posting another entry
+CREATE FUNCTION reject_ledger_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER journal_entries_append_only
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_change();
+
+CREATE TRIGGER journal_postings_append_only
+    BEFORE UPDATE OR DELETE ON journal_postings
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_change();
+
+-- checked when the transaction commits, once every posting of the entry is written
+CREATE FUNCTION check_journal_entry_balance() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM journal_postings
+        WHERE journal_entry_id = NEW.journal_entry_id
+        GROUP BY currency
+        HAVING SUM(CASE WHEN direction = 'debit' THEN amount ELSE -amount END) <> 0
+    ) THEN
+        RAISE EXCEPTION 'journal entry % does not balance', NEW.journal_entry_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER journal_postings_balance
+    AFTER INSERT ON journal_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balance();
@@ -0,0 +1,5 @@
+DROP INDEX outbox_events_unpublished_seq_idx;
+
+DROP TABLE "outbox_events";
+
+DROP TYPE "outbox_event_type";
@@ -0,0 +1,23 @@
+CREATE TYPE "outbox_event_type" AS ENUM (
+    'plan_created',
+    'plan_completed',
+    'installment_paid',
+    'installment_overdue'
+);
+
+-- the events of the payment domain, written in the transaction of the change they announce
+-- and published by the relay in seq order, published_at stays null until they are
+CREATE TABLE "outbox_events" (
+    "id" uuid PRIMARY KEY,
+    "seq" bigserial not null,
+    "created_at" timestamp not null default current_timestamp,
+    "payment_plan_id" uuid not null,
+    "event_type" outbox_event_type not null,
+    "payload" jsonb not null,
+    "published_at" timestamp,
+    CONSTRAINT fk_payment_plans
+        FOREIGN KEY(payment_plan_id)
+        REFERENCES payment_plans(id)
+);
+
+CREATE INDEX outbox_events_unpublished_seq_idx ON outbox_events (seq) WHERE published_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE payment_plans DROP COLUMN "merchant_id";
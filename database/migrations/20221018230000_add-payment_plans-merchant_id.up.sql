@@ -0,0 +1,2 @@
+-- the merchant the purchase was made at, null for the plans created before merchants were recorded
+ALTER TABLE payment_plans ADD COLUMN "merchant_id" uuid;
@@ -0,0 +1,15 @@
+DELETE FROM outbox_events WHERE event_type IN ('plan_paid_off', 'plan_refunded');
+
+ALTER TYPE outbox_event_type RENAME TO outbox_event_type_old;
+
+CREATE TYPE "outbox_event_type" AS ENUM (
+    'plan_created',
+    'plan_completed',
+    'installment_paid',
+    'installment_overdue'
+);
+
+ALTER TABLE outbox_events
+    ALTER COLUMN event_type TYPE outbox_event_type USING event_type::text::outbox_event_type;
+
+DROP TYPE outbox_event_type_old;
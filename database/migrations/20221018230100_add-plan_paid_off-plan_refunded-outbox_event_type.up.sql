@@ -0,0 +1,2 @@
+ALTER TYPE outbox_event_type ADD VALUE 'plan_paid_off';
+ALTER TYPE outbox_event_type ADD VALUE 'plan_refunded';
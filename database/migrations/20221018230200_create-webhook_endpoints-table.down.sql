@@ -0,0 +1,11 @@
+DROP INDEX webhook_deliveries_status_created_at_idx;
+
+DROP INDEX webhook_deliveries_pending_next_attempt_at_idx;
+
+DROP TABLE "webhook_deliveries";
+
+DROP TYPE "webhook_delivery_status";
+
+DROP INDEX webhook_endpoints_merchant_id_idx;
+
+DROP TABLE "webhook_endpoints";
@@ -0,0 +1,42 @@
+-- the urls a merchant receives the events of its plans on, the payloads are signed with secret
+CREATE TABLE "webhook_endpoints" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "merchant_id" uuid not null,
+    "url" text not null,
+    "secret" text not null
+);
+
+CREATE INDEX webhook_endpoints_merchant_id_idx ON webhook_endpoints (merchant_id);
+
+CREATE TYPE "webhook_delivery_status" AS ENUM (
+    'pending',
+    'delivered',
+    'dead'
+);
+
+-- one delivery per event and endpoint, a pending delivery is attempted again at next_attempt_at
+-- until it is delivered or dead once it ran out of attempts.
+-- outbox_event_id has no foreign key, the deliveries are created while the relay holds the event locked
+CREATE TABLE "webhook_deliveries" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "webhook_endpoint_id" uuid not null,
+    "outbox_event_id" uuid not null,
+    "event_type" outbox_event_type not null,
+    "payload" jsonb not null,
+    "status" webhook_delivery_status not null,
+    "attempts" int not null default 0,
+    "next_attempt_at" timestamp not null,
+    "last_error" text not null default '',
+    "delivered_at" timestamp,
+    CONSTRAINT fk_webhook_endpoints
+        FOREIGN KEY(webhook_endpoint_id)
+        REFERENCES webhook_endpoints(id),
+    CONSTRAINT webhook_deliveries_endpoint_event_key UNIQUE (webhook_endpoint_id, outbox_event_id)
+);
+
+CREATE INDEX webhook_deliveries_pending_next_attempt_at_idx ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_status_created_at_idx ON webhook_deliveries (status, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX payment_installments_due_at_idx;
+
+DROP TABLE "reminders_sent";
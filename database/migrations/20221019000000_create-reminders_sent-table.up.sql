@@ -0,0 +1,14 @@
+-- the reminders sent for each installment, a reminder is only sent once per installment
+CREATE TABLE "reminders_sent" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "payment_installment_id" uuid not null,
+    "reminder" text not null,
+    "sent_at" timestamp not null,
+    CONSTRAINT fk_payment_installments
+        FOREIGN KEY(payment_installment_id)
+        REFERENCES payment_installments(id),
+    CONSTRAINT reminders_sent_installment_reminder_key UNIQUE (payment_installment_id, reminder)
+);
+
+CREATE INDEX payment_installments_due_at_idx ON payment_installments (due_at);
@@ -0,0 +1,16 @@
+DROP INDEX collections_case_events_collections_case_id_idx;
+
+DROP TABLE "collections_case_events";
+
+DROP TYPE "collections_case_event_kind";
+
+DROP TRIGGER collections_cases_status_transition ON collections_cases;
+DROP FUNCTION check_collections_case_status_transition();
+
+DROP INDEX collections_cases_status_created_at_idx;
+DROP INDEX collections_cases_next_action_at_idx;
+DROP INDEX collections_cases_active_payment_plan_id_idx;
+
+DROP TABLE "collections_cases";
+
+DROP TYPE "collections_case_status";
@@ -0,0 +1,80 @@
+CREATE TYPE "collections_case_status" AS ENUM (
+    'open',
+    'paused',
+    'closed'
+);
+
+CREATE TYPE "collections_case_event_kind" AS ENUM (
+    'opened',
+    'escalated',
+    'paused',
+    'resumed',
+    'closed'
+);
+
+-- a delinquent payment plan being chased, stage is the latest stage of the ladder the case reached
+CREATE TABLE "collections_cases" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "payment_plan_id" uuid not null,
+    "user_id" uuid not null,
+    "status" collections_case_status not null,
+    "stage" text not null default '',
+    "next_action_at" timestamp,
+    "opened_at" timestamp not null,
+    "closed_at" timestamp,
+    CONSTRAINT fk_payment_plans
+        FOREIGN KEY(payment_plan_id)
+        REFERENCES payment_plans(id)
+);
+
+-- a plan has one case at most which is not closed
+CREATE UNIQUE INDEX collections_cases_active_payment_plan_id_idx ON collections_cases (payment_plan_id)
+    WHERE status <> 'closed';
+
+CREATE INDEX collections_cases_next_action_at_idx ON collections_cases (next_action_at)
+    WHERE status = 'open';
+
+CREATE INDEX collections_cases_status_created_at_idx ON collections_cases (status, created_at);
+
+-- the transitions allowed by the statemachine package, a status can be written again unchanged
+CREATE FUNCTION check_collections_case_status_transition() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    IF (OLD.status::text, NEW.status::text) NOT IN (
+        ('open', 'paused'),
+        ('open', 'closed'),
+        ('paused', 'open'),
+        ('paused', 'closed')
+    ) THEN
+        RAISE EXCEPTION 'collections case % cannot go from % to %', OLD.id, OLD.status, NEW.status
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER collections_cases_status_transition
+    BEFORE UPDATE OF status ON collections_cases
+    FOR EACH ROW EXECUTE FUNCTION check_collections_case_status_transition();
+
+-- the history of a case with who made each step and why
+CREATE TABLE "collections_case_events" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "collections_case_id" uuid not null,
+    "kind" collections_case_event_kind not null,
+    "stage" text not null,
+    "actor" varchar(255) not null,
+    "note" text not null,
+    CONSTRAINT fk_collections_cases
+        FOREIGN KEY(collections_case_id)
+        REFERENCES collections_cases(id)
+);
+
+CREATE INDEX collections_case_events_collections_case_id_idx ON collections_case_events (collections_case_id);
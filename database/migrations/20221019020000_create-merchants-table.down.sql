@@ -0,0 +1,7 @@
+DROP INDEX payment_plans_merchant_id_created_at_idx;
+
+ALTER TABLE webhook_endpoints DROP CONSTRAINT fk_merchants;
+
+ALTER TABLE payment_plans DROP CONSTRAINT fk_merchants;
+
+DROP TABLE "merchants";
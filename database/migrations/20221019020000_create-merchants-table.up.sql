@@ -0,0 +1,24 @@
+CREATE TABLE "merchants" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "name" varchar(255) not null
+);
+
+-- the merchants already known from the plans and the webhook endpoints are named after their id
+INSERT INTO merchants (id, name)
+SELECT merchant_id, merchant_id::text FROM payment_plans WHERE merchant_id IS NOT NULL
+UNION
+SELECT merchant_id, merchant_id::text FROM webhook_endpoints;
+
+-- merchant_id stays nullable, the plans created before merchants were recorded have none
+ALTER TABLE payment_plans ADD CONSTRAINT fk_merchants
+    FOREIGN KEY(merchant_id)
+    REFERENCES merchants(id);
+
+ALTER TABLE webhook_endpoints ADD CONSTRAINT fk_merchants
+    FOREIGN KEY(merchant_id)
+    REFERENCES merchants(id);
+
+-- the plans of a merchant are listed and totalled by creation date
+CREATE INDEX payment_plans_merchant_id_created_at_idx ON payment_plans (merchant_id, created_at);
@@ -0,0 +1,4 @@
+DROP INDEX payment_plans_merchant_id_order_reference_idx;
+
+ALTER TABLE payment_plans DROP COLUMN "description";
+ALTER TABLE payment_plans DROP COLUMN "order_reference";
@@ -0,0 +1,6 @@
+-- the order of the merchant the plan pays for, both are null for the plans created without one
+ALTER TABLE payment_plans ADD COLUMN "order_reference" varchar(255);
+ALTER TABLE payment_plans ADD COLUMN "description" text;
+
+-- an order of a merchant is paid by a single plan, the nulls do not collide
+CREATE UNIQUE INDEX payment_plans_merchant_id_order_reference_idx ON payment_plans (merchant_id, order_reference);
@@ -0,0 +1 @@
+DROP TABLE "payment_plan_line_items";
@@ -0,0 +1,17 @@
+CREATE TABLE "payment_plan_line_items" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "updated_at" timestamp not null default current_timestamp,
+    "payment_plan_id" uuid not null,
+    "position" integer check(position >= 0) not null,
+    "sku" varchar(255) not null,
+    "name" varchar(255) not null,
+    "quantity" integer check(quantity > 0) not null,
+    "currency" currency not null,
+    "unit_price" decimal(32, 16) check(unit_price >= 0) not null,
+    CONSTRAINT fk_payment_plans
+        FOREIGN KEY(payment_plan_id)
+        REFERENCES payment_plans(id)
+);
+
+CREATE UNIQUE INDEX payment_plan_line_items_payment_plan_id_position_idx ON payment_plan_line_items (payment_plan_id, position);
@@ -0,0 +1,9 @@
+DROP TRIGGER audit_entries_append_only ON audit_entries;
+
+DROP INDEX audit_entries_payment_plan_id_sequence_idx;
+
+DROP TABLE "audit_entries";
+
+DROP TYPE "audit_action";
+
+DROP TYPE "audit_actor_type";
@@ -0,0 +1,46 @@
+CREATE TYPE "audit_actor_type" AS ENUM (
+    'internal',
+    'user',
+    'system'
+);
+
+CREATE TYPE "audit_action" AS ENUM (
+    'plan_created',
+    'plan_completed',
+    'plan_cancelled',
+    'plan_expired',
+    'plan_refunded',
+    'plan_paid_off',
+    'plan_rescheduled',
+    'installment_payment_recorded',
+    'installments_past_due',
+    'late_fee_assessed'
+);
+
+-- the trail of the changes made to payment plans, the entries of a plan are numbered from 1 and each one
+-- holds the hash of the entry before it, prev_hash is empty for the first one
+CREATE TABLE "audit_entries" (
+    "id" uuid PRIMARY KEY,
+    "created_at" timestamp not null default current_timestamp,
+    "payment_plan_id" uuid not null,
+    "sequence" bigint check(sequence > 0) not null,
+    "actor_type" audit_actor_type not null,
+    "actor_id" varchar(255) not null,
+    "action" audit_action not null,
+    "before_snapshot" jsonb not null,
+    "after_snapshot" jsonb not null,
+    "request_id" varchar(255),
+    "occurred_at" timestamp not null,
+    "prev_hash" varchar(64) not null,
+    "hash" varchar(64) not null,
+    CONSTRAINT fk_payment_plans
+        FOREIGN KEY(payment_plan_id)
+        REFERENCES payment_plans(id)
+);
+
+-- two changes of a plan chained to the same entry cannot both be written
+CREATE UNIQUE INDEX audit_entries_payment_plan_id_sequence_idx ON audit_entries (payment_plan_id, sequence);
+
+CREATE TRIGGER audit_entries_append_only
+    BEFORE UPDATE OR DELETE ON audit_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_change();
@@ -0,0 +1,3 @@
+DROP INDEX outbox_events_unpublished_payment_plan_id_seq_idx;
+
+ALTER TABLE outbox_events DROP COLUMN "failed_at";
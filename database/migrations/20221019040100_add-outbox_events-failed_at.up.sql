@@ -0,0 +1,5 @@
+-- when the last publication of an event failed, the later events of its plan are not relayed until it is published
+ALTER TABLE outbox_events ADD COLUMN "failed_at" timestamp;
+
+CREATE INDEX outbox_events_unpublished_payment_plan_id_seq_idx ON outbox_events (payment_plan_id, seq)
+    WHERE published_at IS NULL;
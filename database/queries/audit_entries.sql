@@ -0,0 +1,24 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_entries (
+    id, payment_plan_id, sequence, actor_type, actor_id, action, before_snapshot, after_snapshot,
+    request_id, occurred_at, prev_hash, hash
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING id, payment_plan_id, sequence, actor_type, actor_id, action, before_snapshot, after_snapshot,
+    request_id, occurred_at, prev_hash, hash;
+
+-- name: GetLatestAuditEntryByPlanID :one
+SELECT id, payment_plan_id, sequence, actor_type, actor_id, action, before_snapshot, after_snapshot,
+    request_id, occurred_at, prev_hash, hash
+FROM audit_entries
+WHERE payment_plan_id = $1
+ORDER BY sequence DESC
+LIMIT 1;
+
+-- name: ListAuditEntriesByPlanID :many
+SELECT id, payment_plan_id, sequence, actor_type, actor_id, action, before_snapshot, after_snapshot,
+    request_id, occurred_at, prev_hash, hash
+FROM audit_entries
+WHERE payment_plan_id = $1
+ORDER BY sequence;
@@ -0,0 +1,79 @@
+-- name: CreateCollectionsCase :one
+INSERT INTO collections_cases (id, payment_plan_id, user_id, status, opened_at, next_action_at) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at;
+
+-- name: GetCollectionsCaseByID :one
+SELECT id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at
+FROM collections_cases
+WHERE id = $1;
+
+-- name: GetCollectionsCaseByIDForUpdate :one
+SELECT id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at
+FROM collections_cases
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetActiveCollectionsCaseByPaymentPlanID :one
+SELECT id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at
+FROM collections_cases
+WHERE payment_plan_id = $1 AND status <> 'closed';
+
+-- name: ListDelinquentPaymentPlans :many
+SELECT p.id, p.user_id, p.merchant_id, p.currency, p.amount, p.status, p.created_at, p.updated_at,
+    p.order_reference, p.description
+FROM payment_plans p
+WHERE p.status = 'complete'
+    AND EXISTS (
+        SELECT 1 FROM payment_installments i
+        WHERE i.payment_plan_id = p.id AND i.status = 'overdue'
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM collections_cases c
+        WHERE c.payment_plan_id = p.id AND c.status <> 'closed'
+    )
+ORDER BY p.created_at
+LIMIT $1;
+
+-- name: ListDueCollectionsCases :many
+SELECT id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at
+FROM collections_cases
+WHERE status = 'open' AND next_action_at <= @before::timestamp
+ORDER BY next_action_at
+LIMIT sqlc.arg('limit');
+
+-- name: ListSettledCollectionsCases :many
+SELECT c.id, c.payment_plan_id, c.user_id, c.status, c.stage, c.next_action_at, c.opened_at, c.closed_at,
+    c.created_at, c.updated_at
+FROM collections_cases c
+WHERE c.status <> 'closed'
+    AND NOT EXISTS (
+        SELECT 1 FROM payment_installments i
+        WHERE i.payment_plan_id = c.payment_plan_id AND i.status = 'overdue'
+    )
+ORDER BY c.created_at
+LIMIT $1;
+
+-- name: ListCollectionsCasesByStatus :many
+SELECT id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at
+FROM collections_cases
+WHERE status = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateCollectionsCase :one
+UPDATE collections_cases
+SET status = $2, stage = $3, next_action_at = $4, closed_at = $5, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, payment_plan_id, user_id, status, stage, next_action_at, opened_at, closed_at, created_at, updated_at;
+
+-- name: CreateCollectionsCaseEvent :one
+INSERT INTO collections_case_events (id, collections_case_id, kind, stage, actor, note) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, collections_case_id, kind, stage, actor, note, created_at;
+
+-- name: ListCollectionsCaseEventsByCaseID :many
+SELECT id, collections_case_id, kind, stage, actor, note, created_at FROM collections_case_events
+WHERE collections_case_id = $1
+ORDER BY created_at;
@@ -0,0 +1,51 @@
+-- name: CreateCreditLine :one
+INSERT INTO credit_lines (id, user_id, currency, credit_limit, status) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, user_id, currency, credit_limit, status, created_at, updated_at;
+
+-- name: GetCreditLineByUserID :one
+SELECT id, user_id, currency, credit_limit, status, created_at, updated_at FROM credit_lines
+WHERE user_id = $1;
+
+-- name: GetCreditLineByUserIDForUpdate :one
+SELECT id, user_id, currency, credit_limit, status, created_at, updated_at FROM credit_lines
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: GetUserOutstandingAmount :one
+SELECT COALESCE(SUM(i.amount + COALESCE(f.amount, 0) - COALESCE(t.amount, 0)), 0)::decimal AS outstanding_amount
+FROM payment_installments i
+JOIN payment_plans p ON p.id = i.payment_plan_id
+LEFT JOIN LATERAL (
+    SELECT SUM(payment_late_fees.amount) AS amount FROM payment_late_fees
+    WHERE payment_late_fees.payment_installment_id = i.id
+) f ON true
+LEFT JOIN LATERAL (
+    SELECT SUM(payment_transactions.amount) AS amount FROM payment_transactions
+    WHERE payment_transactions.payment_installment_id = i.id
+) t ON true
+WHERE p.user_id = $1
+    AND i.currency = $2
+    AND i.status IN ('pending', 'due', 'overdue');
+
+-- name: UpdateCreditLineLimit :one
+UPDATE credit_lines SET credit_limit = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, user_id, currency, credit_limit, status, created_at, updated_at;
+
+-- name: UpdateCreditLineStatus :one
+UPDATE credit_lines SET status = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, user_id, currency, credit_limit, status, created_at, updated_at;
+
+-- name: CreateCreditLineChange :one
+INSERT INTO credit_line_changes (id, credit_line_id, currency, action, actor, reason, credit_limit, status) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, credit_line_id, currency, action, actor, reason, credit_limit, status, created_at;
+
+-- name: ListCreditLineChangesByCreditLineID :many
+SELECT id, credit_line_id, currency, action, actor, reason, credit_limit, status, created_at FROM credit_line_changes
+WHERE credit_line_id = $1
+ORDER BY created_at;
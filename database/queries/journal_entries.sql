@@ -0,0 +1,20 @@
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (id, payment_plan_id, kind) VALUES (
+    $1, $2, $3
+)
+RETURNING id, payment_plan_id, kind, created_at;
+
+-- name: CreateJournalPosting :one
+INSERT INTO journal_postings (id, journal_entry_id, account, direction, currency, amount) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, journal_entry_id, account, direction, currency, amount, created_at;
+
+-- name: ListJournalPostingsByPlanID :many
+SELECT journal_postings.id, journal_postings.journal_entry_id, journal_entries.payment_plan_id, journal_entries.kind,
+    journal_entries.created_at AS entry_created_at, journal_postings.account, journal_postings.direction,
+    journal_postings.currency, journal_postings.amount
+FROM journal_postings
+JOIN journal_entries ON journal_entries.id = journal_postings.journal_entry_id
+WHERE journal_entries.payment_plan_id = $1
+ORDER BY journal_entries.seq, journal_postings.seq;
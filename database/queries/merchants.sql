@@ -0,0 +1,43 @@
+-- name: CreateMerchant :one
+INSERT INTO merchants (id, name) VALUES (
+    $1, $2
+)
+RETURNING id, name, created_at, updated_at;
+
+-- name: GetMerchantByID :one
+SELECT id, name, created_at, updated_at FROM merchants
+WHERE id = $1;
+
+-- name: GetMerchantTotals :many
+SELECT p.currency,
+    SUM(p.amount)::decimal AS originated_amount,
+    COALESCE(SUM(o.amount), 0)::decimal AS outstanding_amount,
+    COALESCE(SUM(r.amount), 0)::decimal AS refunded_amount
+FROM payment_plans p
+LEFT JOIN LATERAL (
+    SELECT SUM(i.amount + COALESCE(f.amount, 0) - COALESCE(t.amount, 0)) AS amount
+    FROM payment_installments i
+    LEFT JOIN LATERAL (
+        SELECT SUM(payment_late_fees.amount) AS amount FROM payment_late_fees
+        WHERE payment_late_fees.payment_installment_id = i.id
+    ) f ON true
+    LEFT JOIN LATERAL (
+        SELECT SUM(payment_transactions.amount) AS amount FROM payment_transactions
+        WHERE payment_transactions.payment_installment_id = i.id
+    ) t ON true
+    WHERE i.payment_plan_id = p.id
+        AND i.status IN ('pending', 'due', 'overdue')
+) o ON true
+LEFT JOIN LATERAL (
+    SELECT SUM(journal_postings.amount) AS amount FROM journal_postings
+    JOIN journal_entries ON journal_entries.id = journal_postings.journal_entry_id
+    WHERE journal_entries.payment_plan_id = p.id
+        AND journal_postings.account = 'merchant_payable'
+        AND journal_postings.direction = 'debit'
+) r ON true
+WHERE p.merchant_id = @merchant_id
+    AND p.status IN ('complete', 'refunded')
+    AND p.created_at >= @created_from::timestamp
+    AND p.created_at < @created_to::timestamp
+GROUP BY p.currency
+ORDER BY p.currency;
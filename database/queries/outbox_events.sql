@@ -0,0 +1,29 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (id, payment_plan_id, event_type, payload) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, payment_plan_id, event_type, payload, created_at, published_at;
+
+-- name: ListUnpublishedOutboxEventsForUpdate :many
+SELECT id, payment_plan_id, event_type, payload, created_at, published_at FROM outbox_events o
+WHERE o.published_at IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM outbox_events f
+    WHERE f.payment_plan_id = o.payment_plan_id
+    AND f.published_at IS NULL
+    AND f.failed_at IS NOT NULL
+    AND f.seq < o.seq
+)
+ORDER BY o.seq
+LIMIT $1
+FOR UPDATE;
+
+-- name: UpdateOutboxEventPublishedAt :one
+UPDATE outbox_events SET published_at = $2
+WHERE id = $1
+RETURNING id, payment_plan_id, event_type, payload, created_at, published_at;
+
+-- name: UpdateOutboxEventFailedAt :one
+UPDATE outbox_events SET failed_at = $2
+WHERE id = $1
+RETURNING id, payment_plan_id, event_type, payload, created_at, published_at;
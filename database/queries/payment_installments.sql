@@ -1,10 +1,45 @@
 -- name: CreatePaymentInstallments :one
-INSERT INTO payment_installments (id, payment_plan_id, currency, amount, due_at, status) VALUES (
-    $1, $2, $3, $4, $5, $6
+INSERT INTO payment_installments (id, payment_plan_id, currency, amount, due_at, status, version) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, payment_plan_id, currency, amount, due_at, status, created_at, updated_at;
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at;
 
 -- name: ListPaymentInstallmentsByPlanID :many
-SELECT id, payment_plan_id, currency, amount, due_at, status, created_at, updated_at FROM payment_installments
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
 WHERE payment_plan_id = $1
 ORDER BY due_at;
+
+-- name: UpdatePaymentInstallmentStatus :one
+UPDATE payment_installments SET status = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at;
+
+-- name: UpdatePaymentInstallmentAmount :one
+UPDATE payment_installments SET amount = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at;
+
+-- name: GetPaymentInstallmentByIDForUpdate :one
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdatePaymentInstallmentsStatusDueBefore :many
+UPDATE payment_installments SET status = @to_status, updated_at = current_timestamp
+WHERE payment_plan_id = @payment_plan_id
+    AND status = @from_status
+    AND due_at < @due_before
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at;
+
+-- name: ListPaymentInstallmentsByStatus :many
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
+WHERE status = $1
+ORDER BY due_at;
+
+-- name: ListUnpaidPaymentInstallmentsDueBetween :many
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
+WHERE status IN ('pending', 'due', 'overdue')
+    AND due_at > @due_after
+    AND due_at <= @due_before
+    AND payment_plan_id IN (SELECT payment_plans.id FROM payment_plans WHERE payment_plans.status = @plan_status)
+ORDER BY due_at;
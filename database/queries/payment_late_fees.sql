@@ -0,0 +1,18 @@
+-- name: CreatePaymentLateFee :one
+INSERT INTO payment_late_fees (id, payment_installment_id, currency, amount, assessed_at) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, payment_installment_id, currency, amount, assessed_at, created_at, updated_at;
+
+-- name: ListPaymentLateFeesByInstallmentID :many
+SELECT id, payment_installment_id, currency, amount, assessed_at, created_at, updated_at FROM payment_late_fees
+WHERE payment_installment_id = $1
+ORDER BY assessed_at;
+
+-- name: ListPaymentLateFeesByPlanID :many
+SELECT payment_late_fees.id, payment_late_fees.payment_installment_id, payment_late_fees.currency,
+    payment_late_fees.amount, payment_late_fees.assessed_at, payment_late_fees.created_at, payment_late_fees.updated_at
+FROM payment_late_fees
+JOIN payment_installments ON payment_installments.id = payment_late_fees.payment_installment_id
+WHERE payment_installments.payment_plan_id = $1
+ORDER BY payment_late_fees.assessed_at;
@@ -0,0 +1,11 @@
+-- name: CreatePaymentPlanLineItem :one
+INSERT INTO payment_plan_line_items (id, payment_plan_id, position, sku, name, quantity, currency, unit_price) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, payment_plan_id, position, sku, name, quantity, currency, unit_price, created_at, updated_at;
+
+-- name: ListPaymentPlanLineItemsByPlanID :many
+SELECT id, payment_plan_id, position, sku, name, quantity, currency, unit_price, created_at, updated_at
+FROM payment_plan_line_items
+WHERE payment_plan_id = $1
+ORDER BY position;
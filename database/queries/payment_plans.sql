@@ -1,10 +1,63 @@
 -- name: CreatePaymentPlan :one
-INSERT INTO payment_plans (id, user_id, currency, amount, status) VALUES (
-    $1, $2, $3, $4, $5
+INSERT INTO payment_plans (id, user_id, merchant_id, currency, amount, status, order_reference, description) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING id, user_id, currency, amount, status, created_at, updated_at;
+RETURNING id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description;
+
+-- name: GetPaymentPlanByID :one
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE id = $1;
+
+-- name: GetPaymentPlanByIDForUpdate :one
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPaymentPlanIDsWithInstallmentsPastDueForUpdate :many
+SELECT id FROM payment_plans
+WHERE status = @plan_status
+    AND id IN (
+        SELECT payment_installments.payment_plan_id FROM payment_installments
+        WHERE (payment_installments.status = 'pending' AND payment_installments.due_at < @due_before)
+            OR (payment_installments.status = 'due' AND payment_installments.due_at < @overdue_before)
+    )
+ORDER BY id
+FOR UPDATE;
 
 -- name: ListPaymentPlansByUserID :many
-SELECT id, user_id, currency, amount, status, created_at, updated_at FROM payment_plans
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
 WHERE user_id = $1
 ORDER BY created_at DESC;
+
+-- name: ListPaymentPlansByStatusCreatedBefore :many
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE status = $1 AND created_at < $2
+ORDER BY created_at;
+
+-- name: UpdatePaymentPlanStatus :one
+UPDATE payment_plans SET status = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description;
+
+-- name: ListPaymentPlansByMerchantIDOldestFirst :many
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE merchant_id = $1
+ORDER BY created_at, id
+LIMIT $2 OFFSET $3;
+
+-- name: ListPaymentPlansByMerchantIDLatestFirst :many
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE merchant_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetPaymentPlanByMerchantIDOrderReference :one
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE merchant_id = $1 AND order_reference = $2;
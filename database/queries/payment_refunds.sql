@@ -0,0 +1,13 @@
+-- name: CreatePaymentRefund :one
+INSERT INTO payment_refunds (id, payment_installment_id, currency, amount, reason, refunded_at) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, payment_installment_id, currency, amount, reason, refunded_at, created_at, updated_at;
+
+-- name: ListPaymentRefundsByPlanID :many
+SELECT payment_refunds.id, payment_refunds.payment_installment_id, payment_refunds.currency, payment_refunds.amount,
+    payment_refunds.reason, payment_refunds.refunded_at, payment_refunds.created_at, payment_refunds.updated_at
+FROM payment_refunds
+JOIN payment_installments ON payment_installments.id = payment_refunds.payment_installment_id
+WHERE payment_installments.payment_plan_id = $1
+ORDER BY payment_refunds.refunded_at;
@@ -0,0 +1,5 @@
+-- name: CreatePaymentSettlement :one
+INSERT INTO payment_settlements (id, payment_plan_id, currency, amount, processor_reference, paid_at) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, payment_plan_id, currency, amount, processor_reference, paid_at, created_at, updated_at;
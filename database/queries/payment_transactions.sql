@@ -0,0 +1,10 @@
+-- name: CreatePaymentTransaction :one
+INSERT INTO payment_transactions (id, payment_installment_id, currency, amount, processor_reference, paid_at) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, payment_installment_id, currency, amount, processor_reference, paid_at, created_at, updated_at;
+
+-- name: ListPaymentTransactionsByInstallmentID :many
+SELECT id, payment_installment_id, currency, amount, processor_reference, paid_at, created_at, updated_at FROM payment_transactions
+WHERE payment_installment_id = $1
+ORDER BY paid_at;
@@ -0,0 +1,5 @@
+-- name: CreateReminderSent :execrows
+INSERT INTO reminders_sent (id, payment_installment_id, reminder, sent_at) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (payment_installment_id, reminder) DO NOTHING;
@@ -0,0 +1,46 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (id, merchant_id, url, secret) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, merchant_id, url, secret, created_at;
+
+-- name: GetWebhookEndpointByID :one
+SELECT id, merchant_id, url, secret, created_at FROM webhook_endpoints
+WHERE id = $1;
+
+-- name: ListWebhookEndpointsByMerchantID :many
+SELECT id, merchant_id, url, secret, created_at FROM webhook_endpoints
+WHERE merchant_id = $1
+ORDER BY created_at;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (id, webhook_endpoint_id, outbox_event_id, event_type, payload, status, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (webhook_endpoint_id, outbox_event_id) DO NOTHING;
+
+-- name: GetWebhookDeliveryByIDForUpdate :one
+SELECT id, webhook_endpoint_id, outbox_event_id, event_type, payload, status, attempts, next_attempt_at, last_error,
+    delivered_at, created_at, updated_at FROM webhook_deliveries
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListDueWebhookDeliveriesForUpdate :many
+SELECT id, webhook_endpoint_id, outbox_event_id, event_type, payload, status, attempts, next_attempt_at, last_error,
+    delivered_at, created_at, updated_at FROM webhook_deliveries
+WHERE status = 'pending' AND next_attempt_at <= $1
+ORDER BY next_attempt_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED;
+
+-- name: ListWebhookDeliveriesByStatus :many
+SELECT id, webhook_endpoint_id, outbox_event_id, event_type, payload, status, attempts, next_attempt_at, last_error,
+    delivered_at, created_at, updated_at FROM webhook_deliveries
+WHERE status = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, delivered_at = $6, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, webhook_endpoint_id, outbox_event_id, event_type, payload, status, attempts, next_attempt_at, last_error,
+    delivered_at, created_at, updated_at;
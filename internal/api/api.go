@@ -9,6 +9,7 @@ import (
 
 	"golangreferenceapi/internal/api/configuration"
 	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/scheduler"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
@@ -28,20 +29,55 @@ type shutdownFunc struct {
 }
 
 type API struct {
-	httpServer    *http.Server
-	grpcServer    *grpc.Server
-	cfg           configuration.Config
-	shutdownFuncs []*shutdownFunc
+	httpServer        *http.Server
+	grpcServer        *grpc.Server
+	internalGRPC      *grpc.Server
+	scheduler         *scheduler.InstallmentScheduler
+	outboxRelay       *scheduler.OutboxRelay
+	webhookDispatcher *scheduler.WebhookDispatcher
+	reminderScheduler *scheduler.ReminderScheduler
+	collectionsWorker *scheduler.CollectionsWorker
+	cfg               configuration.Config
+	shutdownFuncs     []*shutdownFunc
 }
 
-func NewAPI(cfg *configuration.Config, repository repo.Repository) *API {
+func NewAPI(cfg *configuration.Config, repository repo.Repository) (*API, error) {
 	srv := &API{cfg: *cfg}
 	srv.setupLog()
-	srv.setupHTTPServer(repository)
-	srv.setupGRPCServer()
+
+	webhookService, err := srv.setupWebhookService(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentService, err := srv.setupPaymentService(repository, webhookService)
+	if err != nil {
+		return nil, err
+	}
+
+	creditLineService := srv.setupCreditLineService(repository)
+	merchantService := srv.setupMerchantService(repository)
+
+	reminderService, err := srv.setupReminderService(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionsService, err := srv.setupCollectionsService(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	srv.setupHTTPServer(paymentService, creditLineService, webhookService, collectionsService, merchantService)
+	srv.setupGRPCServer(creditLineService)
+	srv.setupScheduler(paymentService)
+	srv.setupOutboxRelay(paymentService)
+	srv.setupWebhookDispatcher(webhookService)
+	srv.setupReminderScheduler(reminderService)
+	srv.setupCollectionsWorker(collectionsService)
 	srv.setupSwagger()
 
-	return srv
+	return srv, nil
 }
 
 func (s *API) Start(ctx context.Context) (func(), error) {
@@ -60,6 +96,12 @@ func (s *API) Start(ctx context.Context) (func(), error) {
 		return nil, fmt.Errorf("failed to start grpc server: %w", err)
 	}
 
+	s.startScheduler(ctx)
+	s.startOutboxRelay(ctx)
+	s.startWebhookDispatcher(ctx)
+	s.startReminderScheduler(ctx)
+	s.startCollectionsWorker(ctx)
+
 	s.shutdownFuncs = append(s.shutdownFuncs, &shutdownFunc{
 		f: func() error {
 			serverStopCtx()
@@ -102,4 +144,5 @@ func (s *API) shutdown(serverCtx context.Context) {
 	}
 
 	s.grpcServer.GracefulStop()
+	s.internalGRPC.GracefulStop()
 }
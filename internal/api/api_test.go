@@ -26,10 +26,15 @@ func TestStart(t *testing.T) {
 	cfg.Application.Timeouts.WriteTimeout = 2 * time.Second
 	cfg.Application.Timeouts.IdleTimeout = 1 * time.Minute
 	cfg.Grpc.Port = 9000
+	cfg.Grpc.InternalPort = 9001
 	cfg.Observability.Collector.Host = "opentelemetry-collector.otel-collector"
 	cfg.Observability.Collector.Port = 4317
+	cfg.Webhooks = webhooksConfig()
 
-	apiSrv := NewAPI(&cfg, &repomock.MockRepository{})
+	apiSrv, err := NewAPI(&cfg, &repomock.MockRepository{})
+	if err != nil {
+		t.Fatalf("api failed to setup: %v", err)
+	}
 
 	// append mock err to test handling of shutdownFuncs which return err
 	apiSrv.shutdownFuncs = append(apiSrv.shutdownFuncs, &shutdownFunc{
@@ -46,3 +51,144 @@ func TestStart(t *testing.T) {
 
 	shutdown()
 }
+
+func TestNewAPI_InvalidLateFeeRule(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.LateFees.Kind = "flat"
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an invalid late fee rule error but nil returned")
+	}
+}
+
+func TestNewAPI_UnknownEventPublisher(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Outbox.Publisher = "kafka"
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an unknown event publisher error but nil returned")
+	}
+}
+
+func TestNewAPI_InvalidWebhookRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Webhooks.MaxAttempts = 0
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an invalid webhook retry policy error but nil returned")
+	}
+}
+
+func TestNewAPI_WebhookEventPublisher(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Application.URL.Schemes = []string{"https"}
+	cfg.Outbox.Publisher = "webhook"
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err != nil {
+		t.Errorf("api failed to setup: %v", err)
+	}
+}
+
+func TestNewAPI_InvalidReminder(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Reminders.Offsets = []configuration.ReminderOffset{{Name: "upcoming", Offset: -72 * time.Hour}}
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an invalid reminder error but nil returned")
+	}
+}
+
+func TestNewAPI_UnknownReminderNotifier(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Reminders.Notifier = "sms"
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an unknown reminder notifier error but nil returned")
+	}
+}
+
+func TestNewAPI_LogReminderNotifier(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Application.URL.Schemes = []string{"https"}
+	cfg.Reminders.Notifier = "log"
+	cfg.Reminders.Offsets = []configuration.ReminderOffset{
+		{Name: "upcoming", Offset: -72 * time.Hour, Template: "{{.Amount}} {{.Currency}} is due on {{.DueAt}}"},
+	}
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err != nil {
+		t.Errorf("api failed to setup: %v", err)
+	}
+}
+
+func TestNewAPI_InvalidCollectionsStage(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Collections.Stages = []configuration.CollectionsStage{{Name: "first_notice", Action: "call"}}
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an invalid collections stage error but nil returned")
+	}
+}
+
+func TestNewAPI_CollectionsNotifierWithoutStage(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Collections.Notifier = "log"
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected a collections ladder without stage error but nil returned")
+	}
+}
+
+func TestNewAPI_UnknownCollectionsNotifier(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Collections.Notifier = "sms"
+	cfg.Collections.Stages = []configuration.CollectionsStage{{Name: "handover", Action: "handover"}}
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err == nil {
+		t.Error("expected an unknown collections notifier error but nil returned")
+	}
+}
+
+func TestNewAPI_LogCollectionsNotifier(t *testing.T) {
+	t.Parallel()
+
+	cfg := configuration.Config{Webhooks: webhooksConfig()}
+	cfg.Application.URL.Schemes = []string{"https"}
+	cfg.Collections.Notifier = "log"
+	cfg.Collections.Stages = []configuration.CollectionsStage{
+		{Name: "first_notice", Action: "notify", Template: "{{.Amount}} {{.Currency}} is overdue"},
+		{Name: "restriction", Delay: 168 * time.Hour, Action: "restrict"},
+	}
+
+	if _, err := NewAPI(&cfg, &repomock.MockRepository{}); err != nil {
+		t.Errorf("api failed to setup: %v", err)
+	}
+}
+
+func webhooksConfig() configuration.Webhooks {
+	return configuration.Webhooks{
+		Timeout:        5 * time.Second,
+		RetryBaseDelay: 30 * time.Second,
+		RetryMaxDelay:  6 * time.Hour,
+		MaxAttempts:    10,
+	}
+}
@@ -18,8 +18,10 @@ type Config struct {
 			IdleTimeout       time.Duration `yaml:"idleTimeout"`
 		}
 	} `yaml:"application"`
+	// Grpc the user facing services listen on Port, the internal ones on InternalPort which is not to be exposed
 	Grpc struct {
-		Port int `yaml:"port"`
+		Port         int `yaml:"port"`
+		InternalPort int `yaml:"internalPort"`
 	} `yaml:"grpc"`
 	Observability struct {
 		Collector struct {
@@ -27,7 +29,89 @@ type Config struct {
 			Port int    `yaml:"port"`
 		} `yaml:"collector"`
 	} `yaml:"observability"`
-	DB Database `yaml:"db"`
+	DB          Database    `yaml:"db"`
+	Scheduler   Scheduler   `yaml:"scheduler"`
+	LateFees    LateFees    `yaml:"lateFees"`
+	Outbox      Outbox      `yaml:"outbox"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	Reminders   Reminders   `yaml:"reminders"`
+	Collections Collections `yaml:"collections"`
+}
+
+// Scheduler a non positive Interval disables it, installments stay due for GracePeriod before being overdue
+// and plans still pending PendingPlanTTL after their creation are cancelled, a non positive one keeps them pending
+type Scheduler struct {
+	Interval       time.Duration `yaml:"interval"`
+	GracePeriod    time.Duration `yaml:"gracePeriod"`
+	PendingPlanTTL time.Duration `yaml:"pendingPlanTTL"`
+}
+
+// LateFees Kind is "fixed" (Amount in the installment currency) or "percentage" (Amount percent of the installment),
+// an empty Kind disables them. A fee is assessed at most every Interval, MaxAssessments times per installment,
+// and the fees of an installment never total more than its currency cap in Caps.
+type LateFees struct {
+	Kind           string            `yaml:"kind"`
+	Amount         string            `yaml:"amount"`
+	Interval       time.Duration     `yaml:"interval"`
+	MaxAssessments int               `yaml:"maxAssessments"`
+	Caps           map[string]string `yaml:"caps"`
+}
+
+// Outbox Publisher is "log", "webhook" or empty to keep the events in the outbox, the relay publishes
+// up to BatchSize events every RelayInterval and a non positive one disables it
+type Outbox struct {
+	Publisher     string        `yaml:"publisher"`
+	RelayInterval time.Duration `yaml:"relayInterval"`
+	BatchSize     int           `yaml:"batchSize"`
+}
+
+// Webhooks up to BatchSize deliveries are sent every DispatchInterval, a non positive one disables the dispatch.
+// A request fails after Timeout, a failed delivery waits RetryBaseDelay doubled after each attempt up to
+// RetryMaxDelay and is dead after MaxAttempts
+type Webhooks struct {
+	DispatchInterval time.Duration `yaml:"dispatchInterval"`
+	BatchSize        int           `yaml:"batchSize"`
+	Timeout          time.Duration `yaml:"timeout"`
+	RetryBaseDelay   time.Duration `yaml:"retryBaseDelay"`
+	RetryMaxDelay    time.Duration `yaml:"retryMaxDelay"`
+	MaxAttempts      int           `yaml:"maxAttempts"`
+}
+
+// Reminders Notifier is "log" or empty to send no reminder, they are sent every Interval and a non positive
+// one disables them. A reminder is sent Offset after the due date of an installment, a negative Offset ahead of it,
+// and dropped once it is more than Window late.
+type Reminders struct {
+	Notifier string           `yaml:"notifier"`
+	Interval time.Duration    `yaml:"interval"`
+	Window   time.Duration    `yaml:"window"`
+	Offsets  []ReminderOffset `yaml:"offsets"`
+}
+
+// ReminderOffset Name tells the reminders of an installment apart, Template is a text/template
+// of the message rendered with the Amount, Currency, DueAt and Status of the installment
+type ReminderOffset struct {
+	Name     string        `yaml:"name"`
+	Offset   time.Duration `yaml:"offset"`
+	Template string        `yaml:"template"`
+}
+
+// Collections Notifier is "log" or empty to open no case, the cases are run every Interval, up to BatchSize of them
+// per pass, and a non positive one disables them. A delinquent plan gets a case which climbs Stages in order.
+type Collections struct {
+	Notifier  string             `yaml:"notifier"`
+	Interval  time.Duration      `yaml:"interval"`
+	BatchSize int                `yaml:"batchSize"`
+	Stages    []CollectionsStage `yaml:"stages"`
+}
+
+// CollectionsStage is reached Delay after the previous stage, the first one Delay after the case opened. Action is
+// "notify", "restrict" or "handover", Template is a text/template of the notice of a "notify" stage rendered
+// with the Stage, Amount, Currency and Installments overdue of the plan
+type CollectionsStage struct {
+	Name     string        `yaml:"name"`
+	Delay    time.Duration `yaml:"delay"`
+	Action   string        `yaml:"action"`
+	Template string        `yaml:"template"`
 }
 
 type Database struct {
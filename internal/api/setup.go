@@ -5,14 +5,20 @@ import (
 	"net/http"
 	"os"
 
+	"golangreferenceapi/internal/payments/collections"
 	"golangreferenceapi/internal/payments/docs"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/reminder"
 	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/scheduler"
 	"golangreferenceapi/internal/payments/service"
 	"golangreferenceapi/internal/payments/transport/grpc/bnplapi/creditline/v1"
+	grpcinternalfacing "golangreferenceapi/internal/payments/transport/grpc/internalfacing"
 	grpcuserfacing "golangreferenceapi/internal/payments/transport/grpc/userfacing"
 	"golangreferenceapi/internal/payments/transport/rest"
 	"golangreferenceapi/internal/payments/transport/rest/internalfacing"
 	"golangreferenceapi/internal/payments/transport/rest/userfacing"
+	"golangreferenceapi/internal/payments/webhook"
 
 	"github.com/monacohq/golang-common/transport/http/middleware/requestlogger"
 
@@ -32,9 +38,155 @@ func (s *API) setupLog() {
 	}
 }
 
-func (s *API) setupHTTPServer(repository repo.Repository) {
+// setupPaymentService a late fee rule is only used when its kind is configured
+func (s *API) setupPaymentService(
+	repository repo.Repository,
+	webhookService *service.WebhookServiceImp,
+) (*service.PaymentServiceImp, error) {
+	paymentService := service.NewPaymentPlanService()
+	paymentService.UseRepo(repository)
+
+	switch s.cfg.Outbox.Publisher {
+	case "":
+	case "log":
+		paymentService.UseEventPublisher(outbox.NewLogPublisher(&log.Logger))
+	case "webhook":
+		paymentService.UseEventPublisher(webhookService)
+	default:
+		return nil, fmt.Errorf("failed to setup event publisher: unknown publisher %q", s.cfg.Outbox.Publisher)
+	}
+
+	lateFees := s.cfg.LateFees
+	if lateFees.Kind == "" {
+		return paymentService, nil
+	}
+
+	lateFeeRule, err := service.NewLateFeeRule(
+		lateFees.Kind,
+		lateFees.Amount,
+		lateFees.Interval,
+		lateFees.MaxAssessments,
+		lateFees.Caps,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup late fee rule: %w", err)
+	}
+
+	paymentService.UseLateFeeRule(lateFeeRule)
+
+	return paymentService, nil
+}
+
+func (s *API) setupCreditLineService(repository repo.Repository) *service.CreditLineServiceImp {
+	creditLineService := service.NewCreditLineService()
+	creditLineService.UseRepo(repository)
+
+	return creditLineService
+}
+
+func (s *API) setupMerchantService(repository repo.Repository) *service.MerchantServiceImp {
+	merchantService := service.NewMerchantService()
+	merchantService.UseRepo(repository)
+
+	return merchantService
+}
+
+func (s *API) setupWebhookService(repository repo.Repository) (*service.WebhookServiceImp, error) {
+	webhooks := s.cfg.Webhooks
+
+	retryPolicy, err := webhook.NewRetryPolicy(webhooks.RetryBaseDelay, webhooks.RetryMaxDelay, webhooks.MaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup webhook retry policy: %w", err)
+	}
+
+	webhookService := service.NewWebhookService(webhook.NewHTTPSender(webhooks.Timeout), retryPolicy, webhooks.Timeout)
+	webhookService.UseRepo(repository)
+
+	return webhookService, nil
+}
+
+// setupReminderService the reminders are only sent when a notifier is configured
+func (s *API) setupReminderService(repository repo.Repository) (*service.ReminderServiceImp, error) {
+	cfg := s.cfg.Reminders
+
+	reminders := make([]*reminder.Reminder, 0, len(cfg.Offsets))
+
+	for _, offset := range cfg.Offsets {
+		rem, err := reminder.New(offset.Name, offset.Offset, offset.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup reminders: %w", err)
+		}
+
+		reminders = append(reminders, rem)
+	}
+
+	reminderService := service.NewReminderService(reminders, cfg.Window)
+	reminderService.UseRepo(repository)
+
+	switch cfg.Notifier {
+	case "":
+	case "log":
+		reminderService.UseNotifier(reminder.NewLogNotifier(&log.Logger))
+	default:
+		return nil, fmt.Errorf("failed to setup reminder notifier: unknown notifier %q", cfg.Notifier)
+	}
+
+	return reminderService, nil
+}
+
+// setupCollectionsService the cases are only opened when a notifier is configured, which needs at least one stage
+func (s *API) setupCollectionsService(repository repo.Repository) (*service.CollectionsServiceImp, error) {
+	cfg := s.cfg.Collections
+
+	var ladder *collections.Ladder
+
+	if len(cfg.Stages) > 0 {
+		stages := make([]*collections.Stage, 0, len(cfg.Stages))
+
+		for _, stageCfg := range cfg.Stages {
+			stage, err := collections.NewStage(stageCfg.Name, stageCfg.Delay, stageCfg.Action, stageCfg.Template)
+			if err != nil {
+				return nil, fmt.Errorf("failed to setup collections: %w", err)
+			}
+
+			stages = append(stages, stage)
+		}
+
+		var err error
+
+		if ladder, err = collections.NewLadder(stages...); err != nil {
+			return nil, fmt.Errorf("failed to setup collections: %w", err)
+		}
+	}
+
+	collectionsService := service.NewCollectionsService(ladder)
+	collectionsService.UseRepo(repository)
+
+	switch cfg.Notifier {
+	case "":
+	case "log":
+		if ladder == nil {
+			return nil, fmt.Errorf("failed to setup collections: %w: no stage", collections.ErrInvalidLadder)
+		}
+
+		collectionsService.UseNotifier(reminder.NewLogNotifier(&log.Logger))
+	default:
+		return nil, fmt.Errorf("failed to setup collections notifier: unknown notifier %q", cfg.Notifier)
+	}
+
+	return collectionsService, nil
+}
+
+func (s *API) setupHTTPServer(
+	paymentService service.PaymentPlanService,
+	creditLineService service.CreditLineService,
+	webhookService service.WebhookService,
+	collectionsService service.CollectionsService,
+	merchantService service.MerchantService,
+) {
 	// main router
 	httpRouter := chi.NewRouter()
+	httpRouter.Use(middleware.RequestID)
 	httpRouter.Use(requestlogger.RequestLogger(&log.Logger))
 
 	httpRouter.Mount("/debug", middleware.Profiler())
@@ -51,12 +203,13 @@ func (s *API) setupHTTPServer(repository repo.Repository) {
 		),
 	))
 
-	paymentService := service.NewPaymentPlanService()
-	paymentService.UseRepo(repository)
-
 	httpRouter.Route("/", func(r chi.Router) {
-		userfacing.AddRoutes(r, &log.Logger, paymentService, s.cfg.Application.Version)
-		internalfacing.AddRoutes(r, &log.Logger, rest.ChiNamedURLParamsGetter, paymentService, s.cfg.Application.Version)
+		userfacing.AddRoutes(r, &log.Logger, rest.ChiNamedURLParamsGetter, paymentService, s.cfg.Application.Version)
+		internalfacing.AddRoutes(
+			r, &log.Logger, rest.ChiNamedURLParamsGetter,
+			paymentService, creditLineService, webhookService, collectionsService, merchantService,
+			s.cfg.Application.Version,
+		)
 	})
 
 	s.httpServer = &http.Server{
@@ -69,14 +222,69 @@ func (s *API) setupHTTPServer(repository repo.Repository) {
 	}
 }
 
-func (s *API) setupGRPCServer() {
+// setupGRPCServer the credit line administration is only served on the internal listener
+func (s *API) setupGRPCServer(creditLineService service.CreditLineService) {
 	// grpc
 	s.grpcServer = grpc.NewServer(
 		grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor()),
 		grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor()),
 	)
-	payLaterServer := grpcuserfacing.NewPayLaterServer()
+
+	payLaterServer := grpcuserfacing.NewPayLaterServer(creditLineService)
 	creditline.RegisterPayLaterServiceServer(s.grpcServer, payLaterServer)
+
+	s.internalGRPC = grpc.NewServer(
+		grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor()),
+	)
+
+	internalPayLaterServer := grpcinternalfacing.NewPayLaterServer(creditLineService)
+	creditline.RegisterPayLaterServiceServer(s.internalGRPC, internalPayLaterServer)
+}
+
+func (s *API) setupScheduler(paymentService service.PaymentPlanService) {
+	s.scheduler = scheduler.NewInstallmentScheduler(
+		paymentService,
+		&log.Logger,
+		s.cfg.Scheduler.Interval,
+		s.cfg.Scheduler.GracePeriod,
+		s.cfg.Scheduler.PendingPlanTTL,
+	)
+}
+
+func (s *API) setupOutboxRelay(paymentService service.PaymentPlanService) {
+	s.outboxRelay = scheduler.NewOutboxRelay(
+		paymentService,
+		&log.Logger,
+		s.cfg.Outbox.RelayInterval,
+		s.cfg.Outbox.BatchSize,
+	)
+}
+
+func (s *API) setupWebhookDispatcher(webhookService service.WebhookService) {
+	s.webhookDispatcher = scheduler.NewWebhookDispatcher(
+		webhookService,
+		&log.Logger,
+		s.cfg.Webhooks.DispatchInterval,
+		s.cfg.Webhooks.BatchSize,
+	)
+}
+
+func (s *API) setupReminderScheduler(reminderService service.ReminderService) {
+	s.reminderScheduler = scheduler.NewReminderScheduler(
+		reminderService,
+		&log.Logger,
+		s.cfg.Reminders.Interval,
+	)
+}
+
+func (s *API) setupCollectionsWorker(collectionsService service.CollectionsService) {
+	s.collectionsWorker = scheduler.NewCollectionsWorker(
+		collectionsService,
+		&log.Logger,
+		s.cfg.Collections.Interval,
+		s.cfg.Collections.BatchSize,
+	)
 }
 
 func (s *API) setupSwagger() {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/monacohq/golang-common/monitoring/otelinit"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 )
 
 func (s *API) startOtel(ctx context.Context) error {
@@ -59,13 +60,20 @@ func (s *API) startHTTPServer() (context.Context, context.CancelFunc) {
 }
 
 func (s *API) startGRPCServer() error {
-	// start grpc server
-	addr := fmt.Sprintf(":%d", s.cfg.Grpc.Port)
+	if err := serveGRPC(s.grpcServer, "gRPC server", s.cfg.Grpc.Port); err != nil {
+		return err
+	}
+
+	return serveGRPC(s.internalGRPC, "internal gRPC server", s.cfg.Grpc.InternalPort)
+}
+
+func serveGRPC(server *grpc.Server, name string, port int) error {
+	addr := fmt.Sprintf(":%d", port)
 
 	log.Info().
-		Int("grpc port", s.cfg.Grpc.Port).
+		Int("grpc port", port).
 		Str("tcp", addr).
-		Msg("start gRPC server")
+		Msgf("start %s", name)
 
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -73,13 +81,75 @@ func (s *API) startGRPCServer() error {
 	}
 
 	go func() {
-		log.Info().Msgf("grpc server started listening on %s", addr)
+		log.Info().Msgf("%s started listening on %s", name, addr)
 
-		err = s.grpcServer.Serve(lis)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to serve grpc server")
+		if err := server.Serve(lis); err != nil {
+			log.Error().Err(err).Msgf("failed to serve %s", name)
 		}
 	}()
 
 	return nil
 }
+
+func (s *API) startScheduler(ctx context.Context) {
+	log.Info().
+		Str("interval", s.cfg.Scheduler.Interval.String()).
+		Str("gracePeriod", s.cfg.Scheduler.GracePeriod.String()).
+		Str("pendingPlanTTL", s.cfg.Scheduler.PendingPlanTTL.String()).
+		Msg("start installment scheduler")
+
+	s.scheduler.Start(ctx)
+
+	s.shutdownFuncs = append(s.shutdownFuncs, &shutdownFunc{f: s.scheduler.Stop, msg: "stop installment scheduler"})
+}
+
+func (s *API) startOutboxRelay(ctx context.Context) {
+	log.Info().
+		Str("publisher", s.cfg.Outbox.Publisher).
+		Str("relayInterval", s.cfg.Outbox.RelayInterval.String()).
+		Int("batchSize", s.cfg.Outbox.BatchSize).
+		Msg("start outbox relay")
+
+	s.outboxRelay.Start(ctx)
+
+	s.shutdownFuncs = append(s.shutdownFuncs, &shutdownFunc{f: s.outboxRelay.Stop, msg: "stop outbox relay"})
+}
+
+func (s *API) startWebhookDispatcher(ctx context.Context) {
+	log.Info().
+		Str("dispatchInterval", s.cfg.Webhooks.DispatchInterval.String()).
+		Int("batchSize", s.cfg.Webhooks.BatchSize).
+		Str("timeout", s.cfg.Webhooks.Timeout.String()).
+		Int("maxAttempts", s.cfg.Webhooks.MaxAttempts).
+		Msg("start webhook dispatcher")
+
+	s.webhookDispatcher.Start(ctx)
+
+	s.shutdownFuncs = append(s.shutdownFuncs, &shutdownFunc{f: s.webhookDispatcher.Stop, msg: "stop webhook dispatcher"})
+}
+
+func (s *API) startReminderScheduler(ctx context.Context) {
+	log.Info().
+		Str("notifier", s.cfg.Reminders.Notifier).
+		Str("interval", s.cfg.Reminders.Interval.String()).
+		Str("window", s.cfg.Reminders.Window.String()).
+		Int("reminders", len(s.cfg.Reminders.Offsets)).
+		Msg("start reminder scheduler")
+
+	s.reminderScheduler.Start(ctx)
+
+	s.shutdownFuncs = append(s.shutdownFuncs, &shutdownFunc{f: s.reminderScheduler.Stop, msg: "stop reminder scheduler"})
+}
+
+func (s *API) startCollectionsWorker(ctx context.Context) {
+	log.Info().
+		Str("notifier", s.cfg.Collections.Notifier).
+		Str("interval", s.cfg.Collections.Interval.String()).
+		Int("batchSize", s.cfg.Collections.BatchSize).
+		Int("stages", len(s.cfg.Collections.Stages)).
+		Msg("start collections worker")
+
+	s.collectionsWorker.Start(ctx)
+
+	s.shutdownFuncs = append(s.shutdownFuncs, &shutdownFunc{f: s.collectionsWorker.Stop, msg: "stop collections worker"})
+}
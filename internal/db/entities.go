@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -12,10 +14,275 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+type AuditAction string
+
+const (
+	AuditActionPlanCreated                AuditAction = "plan_created"
+	AuditActionPlanCompleted              AuditAction = "plan_completed"
+	AuditActionPlanCancelled              AuditAction = "plan_cancelled"
+	AuditActionPlanExpired                AuditAction = "plan_expired"
+	AuditActionPlanRefunded               AuditAction = "plan_refunded"
+	AuditActionPlanPaidOff                AuditAction = "plan_paid_off"
+	AuditActionPlanRescheduled            AuditAction = "plan_rescheduled"
+	AuditActionInstallmentPaymentRecorded AuditAction = "installment_payment_recorded"
+	AuditActionInstallmentsPastDue        AuditAction = "installments_past_due"
+	AuditActionLateFeeAssessed            AuditAction = "late_fee_assessed"
+)
+
+func (e *AuditAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AuditAction(s)
+	case string:
+		*e = AuditAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AuditAction: %T", src)
+	}
+	return nil
+}
+
+func (e AuditAction) Valid() bool {
+	switch e {
+	case AuditActionPlanCreated,
+		AuditActionPlanCompleted,
+		AuditActionPlanCancelled,
+		AuditActionPlanExpired,
+		AuditActionPlanRefunded,
+		AuditActionPlanPaidOff,
+		AuditActionPlanRescheduled,
+		AuditActionInstallmentPaymentRecorded,
+		AuditActionInstallmentsPastDue,
+		AuditActionLateFeeAssessed:
+		return true
+	}
+	return false
+}
+
+func AllAuditActionValues() []AuditAction {
+	return []AuditAction{
+		AuditActionPlanCreated,
+		AuditActionPlanCompleted,
+		AuditActionPlanCancelled,
+		AuditActionPlanExpired,
+		AuditActionPlanRefunded,
+		AuditActionPlanPaidOff,
+		AuditActionPlanRescheduled,
+		AuditActionInstallmentPaymentRecorded,
+		AuditActionInstallmentsPastDue,
+		AuditActionLateFeeAssessed,
+	}
+}
+
+type AuditActorType string
+
+const (
+	AuditActorTypeInternal AuditActorType = "internal"
+	AuditActorTypeUser     AuditActorType = "user"
+	AuditActorTypeSystem   AuditActorType = "system"
+)
+
+func (e *AuditActorType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AuditActorType(s)
+	case string:
+		*e = AuditActorType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AuditActorType: %T", src)
+	}
+	return nil
+}
+
+func (e AuditActorType) Valid() bool {
+	switch e {
+	case AuditActorTypeInternal,
+		AuditActorTypeUser,
+		AuditActorTypeSystem:
+		return true
+	}
+	return false
+}
+
+func AllAuditActorTypeValues() []AuditActorType {
+	return []AuditActorType{
+		AuditActorTypeInternal,
+		AuditActorTypeUser,
+		AuditActorTypeSystem,
+	}
+}
+
+type CollectionsCaseEventKind string
+
+const (
+	CollectionsCaseEventKindOpened    CollectionsCaseEventKind = "opened"
+	CollectionsCaseEventKindEscalated CollectionsCaseEventKind = "escalated"
+	CollectionsCaseEventKindPaused    CollectionsCaseEventKind = "paused"
+	CollectionsCaseEventKindResumed   CollectionsCaseEventKind = "resumed"
+	CollectionsCaseEventKindClosed    CollectionsCaseEventKind = "closed"
+)
+
+func (e *CollectionsCaseEventKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CollectionsCaseEventKind(s)
+	case string:
+		*e = CollectionsCaseEventKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CollectionsCaseEventKind: %T", src)
+	}
+	return nil
+}
+
+func (e CollectionsCaseEventKind) Valid() bool {
+	switch e {
+	case CollectionsCaseEventKindOpened,
+		CollectionsCaseEventKindEscalated,
+		CollectionsCaseEventKindPaused,
+		CollectionsCaseEventKindResumed,
+		CollectionsCaseEventKindClosed:
+		return true
+	}
+	return false
+}
+
+func AllCollectionsCaseEventKindValues() []CollectionsCaseEventKind {
+	return []CollectionsCaseEventKind{
+		CollectionsCaseEventKindOpened,
+		CollectionsCaseEventKindEscalated,
+		CollectionsCaseEventKindPaused,
+		CollectionsCaseEventKindResumed,
+		CollectionsCaseEventKindClosed,
+	}
+}
+
+type CollectionsCaseStatus string
+
+const (
+	CollectionsCaseStatusOpen   CollectionsCaseStatus = "open"
+	CollectionsCaseStatusPaused CollectionsCaseStatus = "paused"
+	CollectionsCaseStatusClosed CollectionsCaseStatus = "closed"
+)
+
+func (e *CollectionsCaseStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CollectionsCaseStatus(s)
+	case string:
+		*e = CollectionsCaseStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CollectionsCaseStatus: %T", src)
+	}
+	return nil
+}
+
+func (e CollectionsCaseStatus) Valid() bool {
+	switch e {
+	case CollectionsCaseStatusOpen,
+		CollectionsCaseStatusPaused,
+		CollectionsCaseStatusClosed:
+		return true
+	}
+	return false
+}
+
+func AllCollectionsCaseStatusValues() []CollectionsCaseStatus {
+	return []CollectionsCaseStatus{
+		CollectionsCaseStatusOpen,
+		CollectionsCaseStatusPaused,
+		CollectionsCaseStatusClosed,
+	}
+}
+
+type CreditLineAction string
+
+const (
+	CreditLineActionCreate      CreditLineAction = "create"
+	CreditLineActionUpdateLimit CreditLineAction = "update_limit"
+	CreditLineActionFreeze      CreditLineAction = "freeze"
+	CreditLineActionUnfreeze    CreditLineAction = "unfreeze"
+	CreditLineActionClose       CreditLineAction = "close"
+)
+
+func (e *CreditLineAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CreditLineAction(s)
+	case string:
+		*e = CreditLineAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CreditLineAction: %T", src)
+	}
+	return nil
+}
+
+func (e CreditLineAction) Valid() bool {
+	switch e {
+	case CreditLineActionCreate,
+		CreditLineActionUpdateLimit,
+		CreditLineActionFreeze,
+		CreditLineActionUnfreeze,
+		CreditLineActionClose:
+		return true
+	}
+	return false
+}
+
+func AllCreditLineActionValues() []CreditLineAction {
+	return []CreditLineAction{
+		CreditLineActionCreate,
+		CreditLineActionUpdateLimit,
+		CreditLineActionFreeze,
+		CreditLineActionUnfreeze,
+		CreditLineActionClose,
+	}
+}
+
+type CreditLineStatus string
+
+const (
+	CreditLineStatusActive CreditLineStatus = "active"
+	CreditLineStatusFrozen CreditLineStatus = "frozen"
+	CreditLineStatusClosed CreditLineStatus = "closed"
+)
+
+func (e *CreditLineStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CreditLineStatus(s)
+	case string:
+		*e = CreditLineStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CreditLineStatus: %T", src)
+	}
+	return nil
+}
+
+func (e CreditLineStatus) Valid() bool {
+	switch e {
+	case CreditLineStatusActive,
+		CreditLineStatusFrozen,
+		CreditLineStatusClosed:
+		return true
+	}
+	return false
+}
+
+func AllCreditLineStatusValues() []CreditLineStatus {
+	return []CreditLineStatus{
+		CreditLineStatusActive,
+		CreditLineStatusFrozen,
+		CreditLineStatusClosed,
+	}
+}
+
 type Currency string
 
 const (
 	CurrencyUsdc Currency = "usdc"
+	CurrencyUsdt Currency = "usdt"
+	CurrencyUsd  Currency = "usd"
+	CurrencyEur  Currency = "eur"
+	CurrencySgd  Currency = "sgd"
 )
 
 func (e *Currency) Scan(src interface{}) error {
@@ -32,7 +299,11 @@ func (e *Currency) Scan(src interface{}) error {
 
 func (e Currency) Valid() bool {
 	switch e {
-	case CurrencyUsdc:
+	case CurrencyUsdc,
+		CurrencyUsdt,
+		CurrencyUsd,
+		CurrencyEur,
+		CurrencySgd:
 		return true
 	}
 	return false
@@ -41,15 +312,157 @@ func (e Currency) Valid() bool {
 func AllCurrencyValues() []Currency {
 	return []Currency{
 		CurrencyUsdc,
+		CurrencyUsdt,
+		CurrencyUsd,
+		CurrencyEur,
+		CurrencySgd,
+	}
+}
+
+type JournalEntryKind string
+
+const (
+	JournalEntryKindPlanCreated          JournalEntryKind = "plan_created"
+	JournalEntryKindInstallmentPaid      JournalEntryKind = "installment_paid"
+	JournalEntryKindPlanPaidOff          JournalEntryKind = "plan_paid_off"
+	JournalEntryKindLateFeeAssessed      JournalEntryKind = "late_fee_assessed"
+	JournalEntryKindRefunded             JournalEntryKind = "refunded"
+	JournalEntryKindReceivableWrittenOff JournalEntryKind = "receivable_written_off"
+)
+
+func (e *JournalEntryKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JournalEntryKind(s)
+	case string:
+		*e = JournalEntryKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JournalEntryKind: %T", src)
+	}
+	return nil
+}
+
+func (e JournalEntryKind) Valid() bool {
+	switch e {
+	case JournalEntryKindPlanCreated,
+		JournalEntryKindInstallmentPaid,
+		JournalEntryKindPlanPaidOff,
+		JournalEntryKindLateFeeAssessed,
+		JournalEntryKindRefunded,
+		JournalEntryKindReceivableWrittenOff:
+		return true
+	}
+	return false
+}
+
+func AllJournalEntryKindValues() []JournalEntryKind {
+	return []JournalEntryKind{
+		JournalEntryKindPlanCreated,
+		JournalEntryKindInstallmentPaid,
+		JournalEntryKindPlanPaidOff,
+		JournalEntryKindLateFeeAssessed,
+		JournalEntryKindRefunded,
+		JournalEntryKindReceivableWrittenOff,
+	}
+}
+
+type LedgerAccount string
+
+const (
+	LedgerAccountCash            LedgerAccount = "cash"
+	LedgerAccountUserReceivable  LedgerAccount = "user_receivable"
+	LedgerAccountMerchantPayable LedgerAccount = "merchant_payable"
+	LedgerAccountFeeIncome       LedgerAccount = "fee_income"
+)
+
+func (e *LedgerAccount) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LedgerAccount(s)
+	case string:
+		*e = LedgerAccount(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LedgerAccount: %T", src)
+	}
+	return nil
+}
+
+func (e LedgerAccount) Valid() bool {
+	switch e {
+	case LedgerAccountCash,
+		LedgerAccountUserReceivable,
+		LedgerAccountMerchantPayable,
+		LedgerAccountFeeIncome:
+		return true
+	}
+	return false
+}
+
+func AllLedgerAccountValues() []LedgerAccount {
+	return []LedgerAccount{
+		LedgerAccountCash,
+		LedgerAccountUserReceivable,
+		LedgerAccountMerchantPayable,
+		LedgerAccountFeeIncome,
+	}
+}
+
+type OutboxEventType string
+
+const (
+	OutboxEventTypePlanCreated        OutboxEventType = "plan_created"
+	OutboxEventTypePlanCompleted      OutboxEventType = "plan_completed"
+	OutboxEventTypeInstallmentPaid    OutboxEventType = "installment_paid"
+	OutboxEventTypeInstallmentOverdue OutboxEventType = "installment_overdue"
+	OutboxEventTypePlanPaidOff        OutboxEventType = "plan_paid_off"
+	OutboxEventTypePlanRefunded       OutboxEventType = "plan_refunded"
+)
+
+func (e *OutboxEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OutboxEventType(s)
+	case string:
+		*e = OutboxEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OutboxEventType: %T", src)
+	}
+	return nil
+}
+
+func (e OutboxEventType) Valid() bool {
+	switch e {
+	case OutboxEventTypePlanCreated,
+		OutboxEventTypePlanCompleted,
+		OutboxEventTypeInstallmentPaid,
+		OutboxEventTypeInstallmentOverdue,
+		OutboxEventTypePlanPaidOff,
+		OutboxEventTypePlanRefunded:
+		return true
+	}
+	return false
+}
+
+func AllOutboxEventTypeValues() []OutboxEventType {
+	return []OutboxEventType{
+		OutboxEventTypePlanCreated,
+		OutboxEventTypePlanCompleted,
+		OutboxEventTypeInstallmentPaid,
+		OutboxEventTypeInstallmentOverdue,
+		OutboxEventTypePlanPaidOff,
+		OutboxEventTypePlanRefunded,
 	}
 }
 
 type PaymentInstallmentStatus string
 
 const (
-	PaymentInstallmentStatusPending PaymentInstallmentStatus = "pending"
-	PaymentInstallmentStatusPaid    PaymentInstallmentStatus = "paid"
-	PaymentInstallmentStatusDue     PaymentInstallmentStatus = "due"
+	PaymentInstallmentStatusPending    PaymentInstallmentStatus = "pending"
+	PaymentInstallmentStatusPaid       PaymentInstallmentStatus = "paid"
+	PaymentInstallmentStatusDue        PaymentInstallmentStatus = "due"
+	PaymentInstallmentStatusOverdue    PaymentInstallmentStatus = "overdue"
+	PaymentInstallmentStatusVoid       PaymentInstallmentStatus = "void"
+	PaymentInstallmentStatusSuperseded PaymentInstallmentStatus = "superseded"
 )
 
 func (e *PaymentInstallmentStatus) Scan(src interface{}) error {
@@ -68,7 +481,10 @@ func (e PaymentInstallmentStatus) Valid() bool {
 	switch e {
 	case PaymentInstallmentStatusPending,
 		PaymentInstallmentStatusPaid,
-		PaymentInstallmentStatusDue:
+		PaymentInstallmentStatusDue,
+		PaymentInstallmentStatusOverdue,
+		PaymentInstallmentStatusVoid,
+		PaymentInstallmentStatusSuperseded:
 		return true
 	}
 	return false
@@ -79,14 +495,19 @@ func AllPaymentInstallmentStatusValues() []PaymentInstallmentStatus {
 		PaymentInstallmentStatusPending,
 		PaymentInstallmentStatusPaid,
 		PaymentInstallmentStatusDue,
+		PaymentInstallmentStatusOverdue,
+		PaymentInstallmentStatusVoid,
+		PaymentInstallmentStatusSuperseded,
 	}
 }
 
 type PaymentStatus string
 
 const (
-	PaymentStatusPending  PaymentStatus = "pending"
-	PaymentStatusComplete PaymentStatus = "complete"
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusComplete  PaymentStatus = "complete"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
 )
 
 func (e *PaymentStatus) Scan(src interface{}) error {
@@ -104,7 +525,9 @@ func (e *PaymentStatus) Scan(src interface{}) error {
 func (e PaymentStatus) Valid() bool {
 	switch e {
 	case PaymentStatusPending,
-		PaymentStatusComplete:
+		PaymentStatusComplete,
+		PaymentStatusCancelled,
+		PaymentStatusRefunded:
 		return true
 	}
 	return false
@@ -114,7 +537,180 @@ func AllPaymentStatusValues() []PaymentStatus {
 	return []PaymentStatus{
 		PaymentStatusPending,
 		PaymentStatusComplete,
+		PaymentStatusCancelled,
+		PaymentStatusRefunded,
+	}
+}
+
+type PostingDirection string
+
+const (
+	PostingDirectionDebit  PostingDirection = "debit"
+	PostingDirectionCredit PostingDirection = "credit"
+)
+
+func (e *PostingDirection) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PostingDirection(s)
+	case string:
+		*e = PostingDirection(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PostingDirection: %T", src)
+	}
+	return nil
+}
+
+func (e PostingDirection) Valid() bool {
+	switch e {
+	case PostingDirectionDebit,
+		PostingDirectionCredit:
+		return true
+	}
+	return false
+}
+
+func AllPostingDirectionValues() []PostingDirection {
+	return []PostingDirection{
+		PostingDirectionDebit,
+		PostingDirectionCredit,
+	}
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusDelivered,
+		WebhookDeliveryStatusDead:
+		return true
 	}
+	return false
+}
+
+func AllWebhookDeliveryStatusValues() []WebhookDeliveryStatus {
+	return []WebhookDeliveryStatus{
+		WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusDelivered,
+		WebhookDeliveryStatusDead,
+	}
+}
+
+type AuditEntry struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	PaymentPlanID  uuid.UUID
+	Sequence       int64
+	ActorType      AuditActorType
+	ActorID        string
+	Action         AuditAction
+	BeforeSnapshot json.RawMessage
+	AfterSnapshot  json.RawMessage
+	RequestID      sql.NullString
+	OccurredAt     time.Time
+	PrevHash       string
+	Hash           string
+}
+
+type CollectionsCase struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	PaymentPlanID uuid.UUID
+	UserID        uuid.UUID
+	Status        CollectionsCaseStatus
+	Stage         string
+	NextActionAt  sql.NullTime
+	OpenedAt      time.Time
+	ClosedAt      sql.NullTime
+}
+
+type CollectionsCaseEvent struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	CollectionsCaseID uuid.UUID
+	Kind              CollectionsCaseEventKind
+	Stage             string
+	Actor             string
+	Note              string
+}
+
+type CreditLine struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	Currency    Currency
+	CreditLimit decimal.Big
+	Status      CreditLineStatus
+}
+
+type CreditLineChange struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	CreditLineID uuid.UUID
+	Currency     Currency
+	Action       CreditLineAction
+	Actor        string
+	Reason       string
+	CreditLimit  decimal.Big
+	Status       CreditLineStatus
+}
+
+type JournalEntry struct {
+	ID            uuid.UUID
+	Seq           int64
+	CreatedAt     time.Time
+	PaymentPlanID uuid.UUID
+	Kind          JournalEntryKind
+}
+
+type JournalPosting struct {
+	ID             uuid.UUID
+	Seq            int64
+	CreatedAt      time.Time
+	JournalEntryID uuid.UUID
+	Account        LedgerAccount
+	Direction      PostingDirection
+	Currency       Currency
+	Amount         decimal.Big
+}
+
+type Merchant struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+}
+
+type OutboxEvent struct {
+	ID            uuid.UUID
+	Seq           int64
+	CreatedAt     time.Time
+	PaymentPlanID uuid.UUID
+	EventType     OutboxEventType
+	Payload       json.RawMessage
+	PublishedAt   sql.NullTime
+	FailedAt      sql.NullTime
 }
 
 type PaymentInstallment struct {
@@ -126,14 +722,105 @@ type PaymentInstallment struct {
 	DueAt         time.Time
 	Status        PaymentInstallmentStatus
 	PaymentPlanID uuid.UUID
+	Version       int32
+}
+
+type PaymentLateFee struct {
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Currency             Currency
+	Amount               decimal.Big
+	AssessedAt           time.Time
+	PaymentInstallmentID uuid.UUID
 }
 
 type PaymentPlan struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Currency  Currency
-	UserID    uuid.UUID
-	Amount    decimal.Big
-	Status    PaymentStatus
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Currency       Currency
+	UserID         uuid.UUID
+	Amount         decimal.Big
+	Status         PaymentStatus
+	MerchantID     uuid.NullUUID
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+type PaymentPlanLineItem struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	PaymentPlanID uuid.UUID
+	Position      int32
+	Sku           string
+	Name          string
+	Quantity      int32
+	Currency      Currency
+	UnitPrice     decimal.Big
+}
+
+type PaymentRefund struct {
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Currency             Currency
+	Amount               decimal.Big
+	Reason               string
+	RefundedAt           time.Time
+	PaymentInstallmentID uuid.UUID
+}
+
+type PaymentSettlement struct {
+	ID                 uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Currency           Currency
+	Amount             decimal.Big
+	ProcessorReference string
+	PaidAt             time.Time
+	PaymentPlanID      uuid.UUID
+}
+
+type PaymentTransaction struct {
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Currency             Currency
+	Amount               decimal.Big
+	ProcessorReference   string
+	PaidAt               time.Time
+	PaymentInstallmentID uuid.UUID
+}
+
+type RemindersSent struct {
+	ID                   uuid.UUID
+	CreatedAt            time.Time
+	PaymentInstallmentID uuid.UUID
+	Reminder             string
+	SentAt               time.Time
+}
+
+type WebhookDelivery struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	WebhookEndpointID uuid.UUID
+	OutboxEventID     uuid.UUID
+	EventType         OutboxEventType
+	Payload           json.RawMessage
+	Status            WebhookDeliveryStatus
+	Attempts          int32
+	NextAttemptAt     time.Time
+	LastError         string
+	DeliveredAt       sql.NullTime
+}
+
+type WebhookEndpoint struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	MerchantID uuid.UUID
+	Url        string
+	Secret     string
 }
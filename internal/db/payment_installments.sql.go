@@ -14,10 +14,10 @@ import (
 )
 
 const CreatePaymentInstallments = `-- name: CreatePaymentInstallments :one
-INSERT INTO payment_installments (id, payment_plan_id, currency, amount, due_at, status) VALUES (
-    $1, $2, $3, $4, $5, $6
+INSERT INTO payment_installments (id, payment_plan_id, currency, amount, due_at, status, version) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, payment_plan_id, currency, amount, due_at, status, created_at, updated_at
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at
 `
 
 type CreatePaymentInstallmentsParams struct {
@@ -27,6 +27,7 @@ type CreatePaymentInstallmentsParams struct {
 	Amount        decimal.Big
 	DueAt         time.Time
 	Status        PaymentInstallmentStatus
+	Version       int32
 }
 
 type CreatePaymentInstallmentsRow struct {
@@ -36,6 +37,7 @@ type CreatePaymentInstallmentsRow struct {
 	Amount        decimal.Big
 	DueAt         time.Time
 	Status        PaymentInstallmentStatus
+	Version       int32
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -48,6 +50,7 @@ func (q *Queries) CreatePaymentInstallments(ctx context.Context, arg *CreatePaym
 		arg.Amount,
 		arg.DueAt,
 		arg.Status,
+		arg.Version,
 	)
 	var i CreatePaymentInstallmentsRow
 	err := row.Scan(
@@ -57,6 +60,7 @@ func (q *Queries) CreatePaymentInstallments(ctx context.Context, arg *CreatePaym
 		&i.Amount,
 		&i.DueAt,
 		&i.Status,
+		&i.Version,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -64,7 +68,7 @@ func (q *Queries) CreatePaymentInstallments(ctx context.Context, arg *CreatePaym
 }
 
 const ListPaymentInstallmentsByPlanID = `-- name: ListPaymentInstallmentsByPlanID :many
-SELECT id, payment_plan_id, currency, amount, due_at, status, created_at, updated_at FROM payment_installments
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
 WHERE payment_plan_id = $1
 ORDER BY due_at
 `
@@ -76,6 +80,7 @@ type ListPaymentInstallmentsByPlanIDRow struct {
 	Amount        decimal.Big
 	DueAt         time.Time
 	Status        PaymentInstallmentStatus
+	Version       int32
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -96,6 +101,289 @@ func (q *Queries) ListPaymentInstallmentsByPlanID(ctx context.Context, paymentPl
 			&i.Amount,
 			&i.DueAt,
 			&i.Status,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const UpdatePaymentInstallmentStatus = `-- name: UpdatePaymentInstallmentStatus :one
+UPDATE payment_installments SET status = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at
+`
+
+type UpdatePaymentInstallmentStatusParams struct {
+	ID     uuid.UUID
+	Status PaymentInstallmentStatus
+}
+
+type UpdatePaymentInstallmentStatusRow struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Currency      Currency
+	Amount        decimal.Big
+	DueAt         time.Time
+	Status        PaymentInstallmentStatus
+	Version       int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpdatePaymentInstallmentStatus(ctx context.Context, arg *UpdatePaymentInstallmentStatusParams) (*UpdatePaymentInstallmentStatusRow, error) {
+	row := q.db.QueryRow(ctx, UpdatePaymentInstallmentStatus, arg.ID, arg.Status)
+	var i UpdatePaymentInstallmentStatusRow
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentPlanID,
+		&i.Currency,
+		&i.Amount,
+		&i.DueAt,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const UpdatePaymentInstallmentAmount = `-- name: UpdatePaymentInstallmentAmount :one
+UPDATE payment_installments SET amount = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at
+`
+
+type UpdatePaymentInstallmentAmountParams struct {
+	ID     uuid.UUID
+	Amount decimal.Big
+}
+
+type UpdatePaymentInstallmentAmountRow struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Currency      Currency
+	Amount        decimal.Big
+	DueAt         time.Time
+	Status        PaymentInstallmentStatus
+	Version       int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpdatePaymentInstallmentAmount(ctx context.Context, arg *UpdatePaymentInstallmentAmountParams) (*UpdatePaymentInstallmentAmountRow, error) {
+	row := q.db.QueryRow(ctx, UpdatePaymentInstallmentAmount, arg.ID, arg.Amount)
+	var i UpdatePaymentInstallmentAmountRow
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentPlanID,
+		&i.Currency,
+		&i.Amount,
+		&i.DueAt,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const GetPaymentInstallmentByIDForUpdate = `-- name: GetPaymentInstallmentByIDForUpdate :one
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
+WHERE id = $1
+FOR UPDATE
+`
+
+type GetPaymentInstallmentByIDForUpdateRow struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Currency      Currency
+	Amount        decimal.Big
+	DueAt         time.Time
+	Status        PaymentInstallmentStatus
+	Version       int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) GetPaymentInstallmentByIDForUpdate(ctx context.Context, id uuid.UUID) (*GetPaymentInstallmentByIDForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, GetPaymentInstallmentByIDForUpdate, id)
+	var i GetPaymentInstallmentByIDForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentPlanID,
+		&i.Currency,
+		&i.Amount,
+		&i.DueAt,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const UpdatePaymentInstallmentsStatusDueBefore = `-- name: UpdatePaymentInstallmentsStatusDueBefore :many
+UPDATE payment_installments SET status = $1, updated_at = current_timestamp
+WHERE payment_plan_id = $2
+    AND status = $3
+    AND due_at < $4
+RETURNING id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at
+`
+
+type UpdatePaymentInstallmentsStatusDueBeforeParams struct {
+	ToStatus      PaymentInstallmentStatus
+	PaymentPlanID uuid.UUID
+	FromStatus    PaymentInstallmentStatus
+	DueBefore     time.Time
+}
+
+type UpdatePaymentInstallmentsStatusDueBeforeRow struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Currency      Currency
+	Amount        decimal.Big
+	DueAt         time.Time
+	Status        PaymentInstallmentStatus
+	Version       int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpdatePaymentInstallmentsStatusDueBefore(ctx context.Context, arg *UpdatePaymentInstallmentsStatusDueBeforeParams) ([]*UpdatePaymentInstallmentsStatusDueBeforeRow, error) {
+	rows, err := q.db.Query(ctx, UpdatePaymentInstallmentsStatusDueBefore,
+		arg.ToStatus,
+		arg.PaymentPlanID,
+		arg.FromStatus,
+		arg.DueBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*UpdatePaymentInstallmentsStatusDueBeforeRow
+	for rows.Next() {
+		var i UpdatePaymentInstallmentsStatusDueBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentPlanID,
+			&i.Currency,
+			&i.Amount,
+			&i.DueAt,
+			&i.Status,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListPaymentInstallmentsByStatus = `-- name: ListPaymentInstallmentsByStatus :many
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
+WHERE status = $1
+ORDER BY due_at
+`
+
+type ListPaymentInstallmentsByStatusRow struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Currency      Currency
+	Amount        decimal.Big
+	DueAt         time.Time
+	Status        PaymentInstallmentStatus
+	Version       int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) ListPaymentInstallmentsByStatus(ctx context.Context, status PaymentInstallmentStatus) ([]*ListPaymentInstallmentsByStatusRow, error) {
+	rows, err := q.db.Query(ctx, ListPaymentInstallmentsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListPaymentInstallmentsByStatusRow
+	for rows.Next() {
+		var i ListPaymentInstallmentsByStatusRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentPlanID,
+			&i.Currency,
+			&i.Amount,
+			&i.DueAt,
+			&i.Status,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListUnpaidPaymentInstallmentsDueBetween = `-- name: ListUnpaidPaymentInstallmentsDueBetween :many
+SELECT id, payment_plan_id, currency, amount, due_at, status, version, created_at, updated_at FROM payment_installments
+WHERE status IN ('pending', 'due', 'overdue')
+    AND due_at > $1
+    AND due_at <= $2
+    AND payment_plan_id IN (SELECT payment_plans.id FROM payment_plans WHERE payment_plans.status = $3)
+ORDER BY due_at
+`
+
+type ListUnpaidPaymentInstallmentsDueBetweenParams struct {
+	DueAfter   time.Time
+	DueBefore  time.Time
+	PlanStatus PaymentStatus
+}
+
+type ListUnpaidPaymentInstallmentsDueBetweenRow struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Currency      Currency
+	Amount        decimal.Big
+	DueAt         time.Time
+	Status        PaymentInstallmentStatus
+	Version       int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) ListUnpaidPaymentInstallmentsDueBetween(ctx context.Context, arg *ListUnpaidPaymentInstallmentsDueBetweenParams) ([]*ListUnpaidPaymentInstallmentsDueBetweenRow, error) {
+	rows, err := q.db.Query(ctx, ListUnpaidPaymentInstallmentsDueBetween, arg.DueAfter, arg.DueBefore, arg.PlanStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListUnpaidPaymentInstallmentsDueBetweenRow
+	for rows.Next() {
+		var i ListUnpaidPaymentInstallmentsDueBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentPlanID,
+			&i.Currency,
+			&i.Amount,
+			&i.DueAt,
+			&i.Status,
+			&i.Version,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
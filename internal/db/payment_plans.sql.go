@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/ericlagergren/decimal"
@@ -14,65 +15,408 @@ import (
 )
 
 const CreatePaymentPlan = `-- name: CreatePaymentPlan :one
-INSERT INTO payment_plans (id, user_id, currency, amount, status) VALUES (
-    $1, $2, $3, $4, $5
+INSERT INTO payment_plans (id, user_id, merchant_id, currency, amount, status, order_reference, description) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING id, user_id, currency, amount, status, created_at, updated_at
+RETURNING id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
 `
 
 type CreatePaymentPlanParams struct {
-	ID       uuid.UUID
-	UserID   uuid.UUID
-	Currency Currency
-	Amount   decimal.Big
-	Status   PaymentStatus
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	OrderReference sql.NullString
+	Description    sql.NullString
 }
 
 type CreatePaymentPlanRow struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Currency  Currency
-	Amount    decimal.Big
-	Status    PaymentStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
 }
 
 func (q *Queries) CreatePaymentPlan(ctx context.Context, arg *CreatePaymentPlanParams) (*CreatePaymentPlanRow, error) {
 	row := q.db.QueryRow(ctx, CreatePaymentPlan,
 		arg.ID,
 		arg.UserID,
+		arg.MerchantID,
 		arg.Currency,
 		arg.Amount,
 		arg.Status,
+		arg.OrderReference,
+		arg.Description,
 	)
 	var i CreatePaymentPlanRow
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.MerchantID,
+		&i.Currency,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrderReference,
+		&i.Description,
+	)
+	return &i, err
+}
+
+const GetPaymentPlanByID = `-- name: GetPaymentPlanByID :one
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE id = $1
+`
+
+type GetPaymentPlanByIDRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) GetPaymentPlanByID(ctx context.Context, id uuid.UUID) (*GetPaymentPlanByIDRow, error) {
+	row := q.db.QueryRow(ctx, GetPaymentPlanByID, id)
+	var i GetPaymentPlanByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MerchantID,
+		&i.Currency,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrderReference,
+		&i.Description,
+	)
+	return &i, err
+}
+
+const GetPaymentPlanByIDForUpdate = `-- name: GetPaymentPlanByIDForUpdate :one
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE id = $1
+FOR UPDATE
+`
+
+type GetPaymentPlanByIDForUpdateRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) GetPaymentPlanByIDForUpdate(ctx context.Context, id uuid.UUID) (*GetPaymentPlanByIDForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, GetPaymentPlanByIDForUpdate, id)
+	var i GetPaymentPlanByIDForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MerchantID,
 		&i.Currency,
 		&i.Amount,
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrderReference,
+		&i.Description,
 	)
 	return &i, err
 }
 
+const GetPaymentPlanByMerchantIDOrderReference = `-- name: GetPaymentPlanByMerchantIDOrderReference :one
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE merchant_id = $1 AND order_reference = $2
+`
+
+type GetPaymentPlanByMerchantIDOrderReferenceParams struct {
+	MerchantID     uuid.NullUUID
+	OrderReference sql.NullString
+}
+
+type GetPaymentPlanByMerchantIDOrderReferenceRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) GetPaymentPlanByMerchantIDOrderReference(ctx context.Context, arg *GetPaymentPlanByMerchantIDOrderReferenceParams) (*GetPaymentPlanByMerchantIDOrderReferenceRow, error) {
+	row := q.db.QueryRow(ctx, GetPaymentPlanByMerchantIDOrderReference, arg.MerchantID, arg.OrderReference)
+	var i GetPaymentPlanByMerchantIDOrderReferenceRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MerchantID,
+		&i.Currency,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrderReference,
+		&i.Description,
+	)
+	return &i, err
+}
+
+const ListPaymentPlanIDsWithInstallmentsPastDueForUpdate = `-- name: ListPaymentPlanIDsWithInstallmentsPastDueForUpdate :many
+SELECT id FROM payment_plans
+WHERE status = $1
+    AND id IN (
+        SELECT payment_installments.payment_plan_id FROM payment_installments
+        WHERE (payment_installments.status = 'pending' AND payment_installments.due_at < $2)
+            OR (payment_installments.status = 'due' AND payment_installments.due_at < $3)
+    )
+ORDER BY id
+FOR UPDATE
+`
+
+type ListPaymentPlanIDsWithInstallmentsPastDueForUpdateParams struct {
+	PlanStatus    PaymentStatus
+	DueBefore     time.Time
+	OverdueBefore time.Time
+}
+
+func (q *Queries) ListPaymentPlanIDsWithInstallmentsPastDueForUpdate(ctx context.Context, arg *ListPaymentPlanIDsWithInstallmentsPastDueForUpdateParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, ListPaymentPlanIDsWithInstallmentsPastDueForUpdate, arg.PlanStatus, arg.DueBefore, arg.OverdueBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListPaymentPlansByMerchantIDLatestFirst = `-- name: ListPaymentPlansByMerchantIDLatestFirst :many
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE merchant_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListPaymentPlansByMerchantIDLatestFirstParams struct {
+	MerchantID uuid.NullUUID
+	Limit      int32
+	Offset     int32
+}
+
+type ListPaymentPlansByMerchantIDLatestFirstRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) ListPaymentPlansByMerchantIDLatestFirst(ctx context.Context, arg *ListPaymentPlansByMerchantIDLatestFirstParams) ([]*ListPaymentPlansByMerchantIDLatestFirstRow, error) {
+	rows, err := q.db.Query(ctx, ListPaymentPlansByMerchantIDLatestFirst, arg.MerchantID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListPaymentPlansByMerchantIDLatestFirstRow
+	for rows.Next() {
+		var i ListPaymentPlansByMerchantIDLatestFirstRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.MerchantID,
+			&i.Currency,
+			&i.Amount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrderReference,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListPaymentPlansByMerchantIDOldestFirst = `-- name: ListPaymentPlansByMerchantIDOldestFirst :many
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE merchant_id = $1
+ORDER BY created_at, id
+LIMIT $2 OFFSET $3
+`
+
+type ListPaymentPlansByMerchantIDOldestFirstParams struct {
+	MerchantID uuid.NullUUID
+	Limit      int32
+	Offset     int32
+}
+
+type ListPaymentPlansByMerchantIDOldestFirstRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) ListPaymentPlansByMerchantIDOldestFirst(ctx context.Context, arg *ListPaymentPlansByMerchantIDOldestFirstParams) ([]*ListPaymentPlansByMerchantIDOldestFirstRow, error) {
+	rows, err := q.db.Query(ctx, ListPaymentPlansByMerchantIDOldestFirst, arg.MerchantID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListPaymentPlansByMerchantIDOldestFirstRow
+	for rows.Next() {
+		var i ListPaymentPlansByMerchantIDOldestFirstRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.MerchantID,
+			&i.Currency,
+			&i.Amount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrderReference,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListPaymentPlansByStatusCreatedBefore = `-- name: ListPaymentPlansByStatusCreatedBefore :many
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
+WHERE status = $1 AND created_at < $2
+ORDER BY created_at
+`
+
+type ListPaymentPlansByStatusCreatedBeforeParams struct {
+	Status    PaymentStatus
+	CreatedAt time.Time
+}
+
+type ListPaymentPlansByStatusCreatedBeforeRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) ListPaymentPlansByStatusCreatedBefore(ctx context.Context, arg *ListPaymentPlansByStatusCreatedBeforeParams) ([]*ListPaymentPlansByStatusCreatedBeforeRow, error) {
+	rows, err := q.db.Query(ctx, ListPaymentPlansByStatusCreatedBefore, arg.Status, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListPaymentPlansByStatusCreatedBeforeRow
+	for rows.Next() {
+		var i ListPaymentPlansByStatusCreatedBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.MerchantID,
+			&i.Currency,
+			&i.Amount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrderReference,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListPaymentPlansByUserID = `-- name: ListPaymentPlansByUserID :many
-SELECT id, user_id, currency, amount, status, created_at, updated_at FROM payment_plans
+SELECT id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+FROM payment_plans
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
 
 type ListPaymentPlansByUserIDRow struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Currency  Currency
-	Amount    decimal.Big
-	Status    PaymentStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
 }
 
 func (q *Queries) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*ListPaymentPlansByUserIDRow, error) {
@@ -87,11 +431,14 @@ func (q *Queries) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.MerchantID,
 			&i.Currency,
 			&i.Amount,
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrderReference,
+			&i.Description,
 		); err != nil {
 			return nil, err
 		}
@@ -102,3 +449,45 @@ func (q *Queries) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID
 	}
 	return items, nil
 }
+
+const UpdatePaymentPlanStatus = `-- name: UpdatePaymentPlanStatus :one
+UPDATE payment_plans SET status = $2, updated_at = current_timestamp
+WHERE id = $1
+RETURNING id, user_id, merchant_id, currency, amount, status, created_at, updated_at, order_reference, description
+`
+
+type UpdatePaymentPlanStatusParams struct {
+	ID     uuid.UUID
+	Status PaymentStatus
+}
+
+type UpdatePaymentPlanStatusRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.NullUUID
+	Currency       Currency
+	Amount         decimal.Big
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrderReference sql.NullString
+	Description    sql.NullString
+}
+
+func (q *Queries) UpdatePaymentPlanStatus(ctx context.Context, arg *UpdatePaymentPlanStatusParams) (*UpdatePaymentPlanStatusRow, error) {
+	row := q.db.QueryRow(ctx, UpdatePaymentPlanStatus, arg.ID, arg.Status)
+	var i UpdatePaymentPlanStatusRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.MerchantID,
+		&i.Currency,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrderReference,
+		&i.Description,
+	)
+	return &i, err
+}
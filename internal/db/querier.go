@@ -7,14 +7,80 @@ package db
 import (
 	"context"
 
+	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
 )
 
 type Querier interface {
+	CreateAuditEntry(ctx context.Context, arg *CreateAuditEntryParams) (*CreateAuditEntryRow, error)
+	CreateCollectionsCase(ctx context.Context, arg *CreateCollectionsCaseParams) (*CreateCollectionsCaseRow, error)
+	CreateCollectionsCaseEvent(ctx context.Context, arg *CreateCollectionsCaseEventParams) (*CreateCollectionsCaseEventRow, error)
+	CreateCreditLine(ctx context.Context, arg *CreateCreditLineParams) (*CreateCreditLineRow, error)
+	CreateCreditLineChange(ctx context.Context, arg *CreateCreditLineChangeParams) (*CreateCreditLineChangeRow, error)
+	CreateJournalEntry(ctx context.Context, arg *CreateJournalEntryParams) (*CreateJournalEntryRow, error)
+	CreateJournalPosting(ctx context.Context, arg *CreateJournalPostingParams) (*CreateJournalPostingRow, error)
+	CreateMerchant(ctx context.Context, arg *CreateMerchantParams) (*CreateMerchantRow, error)
+	CreateOutboxEvent(ctx context.Context, arg *CreateOutboxEventParams) (*CreateOutboxEventRow, error)
 	CreatePaymentInstallments(ctx context.Context, arg *CreatePaymentInstallmentsParams) (*CreatePaymentInstallmentsRow, error)
+	CreatePaymentLateFee(ctx context.Context, arg *CreatePaymentLateFeeParams) (*CreatePaymentLateFeeRow, error)
 	CreatePaymentPlan(ctx context.Context, arg *CreatePaymentPlanParams) (*CreatePaymentPlanRow, error)
+	CreatePaymentPlanLineItem(ctx context.Context, arg *CreatePaymentPlanLineItemParams) (*CreatePaymentPlanLineItemRow, error)
+	CreatePaymentRefund(ctx context.Context, arg *CreatePaymentRefundParams) (*CreatePaymentRefundRow, error)
+	CreatePaymentSettlement(ctx context.Context, arg *CreatePaymentSettlementParams) (*CreatePaymentSettlementRow, error)
+	CreatePaymentTransaction(ctx context.Context, arg *CreatePaymentTransactionParams) (*CreatePaymentTransactionRow, error)
+	CreateReminderSent(ctx context.Context, arg *CreateReminderSentParams) (int64, error)
+	CreateWebhookDelivery(ctx context.Context, arg *CreateWebhookDeliveryParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg *CreateWebhookEndpointParams) (*CreateWebhookEndpointRow, error)
+	GetActiveCollectionsCaseByPaymentPlanID(ctx context.Context, paymentPlanID uuid.UUID) (*GetActiveCollectionsCaseByPaymentPlanIDRow, error)
+	GetCollectionsCaseByID(ctx context.Context, id uuid.UUID) (*GetCollectionsCaseByIDRow, error)
+	GetCollectionsCaseByIDForUpdate(ctx context.Context, id uuid.UUID) (*GetCollectionsCaseByIDForUpdateRow, error)
+	GetCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*GetCreditLineByUserIDRow, error)
+	GetCreditLineByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (*GetCreditLineByUserIDForUpdateRow, error)
+	GetLatestAuditEntryByPlanID(ctx context.Context, paymentPlanID uuid.UUID) (*GetLatestAuditEntryByPlanIDRow, error)
+	GetMerchantByID(ctx context.Context, id uuid.UUID) (*GetMerchantByIDRow, error)
+	GetMerchantTotals(ctx context.Context, arg *GetMerchantTotalsParams) ([]*GetMerchantTotalsRow, error)
+	GetPaymentInstallmentByIDForUpdate(ctx context.Context, id uuid.UUID) (*GetPaymentInstallmentByIDForUpdateRow, error)
+	GetPaymentPlanByID(ctx context.Context, id uuid.UUID) (*GetPaymentPlanByIDRow, error)
+	GetPaymentPlanByIDForUpdate(ctx context.Context, id uuid.UUID) (*GetPaymentPlanByIDForUpdateRow, error)
+	GetPaymentPlanByMerchantIDOrderReference(ctx context.Context, arg *GetPaymentPlanByMerchantIDOrderReferenceParams) (*GetPaymentPlanByMerchantIDOrderReferenceRow, error)
+	GetUserOutstandingAmount(ctx context.Context, arg *GetUserOutstandingAmountParams) (decimal.Big, error)
+	GetWebhookDeliveryByIDForUpdate(ctx context.Context, id uuid.UUID) (*GetWebhookDeliveryByIDForUpdateRow, error)
+	GetWebhookEndpointByID(ctx context.Context, id uuid.UUID) (*GetWebhookEndpointByIDRow, error)
+	ListAuditEntriesByPlanID(ctx context.Context, paymentPlanID uuid.UUID) ([]*ListAuditEntriesByPlanIDRow, error)
+	ListCollectionsCaseEventsByCaseID(ctx context.Context, collectionsCaseID uuid.UUID) ([]*ListCollectionsCaseEventsByCaseIDRow, error)
+	ListCollectionsCasesByStatus(ctx context.Context, status CollectionsCaseStatus) ([]*ListCollectionsCasesByStatusRow, error)
+	ListCreditLineChangesByCreditLineID(ctx context.Context, creditLineID uuid.UUID) ([]*ListCreditLineChangesByCreditLineIDRow, error)
+	ListDelinquentPaymentPlans(ctx context.Context, limit int32) ([]*ListDelinquentPaymentPlansRow, error)
+	ListDueCollectionsCases(ctx context.Context, arg *ListDueCollectionsCasesParams) ([]*ListDueCollectionsCasesRow, error)
+	ListDueWebhookDeliveriesForUpdate(ctx context.Context, arg *ListDueWebhookDeliveriesForUpdateParams) ([]*ListDueWebhookDeliveriesForUpdateRow, error)
+	ListJournalPostingsByPlanID(ctx context.Context, paymentPlanID uuid.UUID) ([]*ListJournalPostingsByPlanIDRow, error)
 	ListPaymentInstallmentsByPlanID(ctx context.Context, paymentPlanID uuid.UUID) ([]*ListPaymentInstallmentsByPlanIDRow, error)
+	ListPaymentInstallmentsByStatus(ctx context.Context, status PaymentInstallmentStatus) ([]*ListPaymentInstallmentsByStatusRow, error)
+	ListPaymentLateFeesByInstallmentID(ctx context.Context, paymentInstallmentID uuid.UUID) ([]*ListPaymentLateFeesByInstallmentIDRow, error)
+	ListPaymentLateFeesByPlanID(ctx context.Context, paymentPlanID uuid.UUID) ([]*ListPaymentLateFeesByPlanIDRow, error)
+	ListPaymentPlanIDsWithInstallmentsPastDueForUpdate(ctx context.Context, arg *ListPaymentPlanIDsWithInstallmentsPastDueForUpdateParams) ([]uuid.UUID, error)
+	ListPaymentPlanLineItemsByPlanID(ctx context.Context, paymentPlanID uuid.UUID) ([]*ListPaymentPlanLineItemsByPlanIDRow, error)
+	ListPaymentPlansByMerchantIDLatestFirst(ctx context.Context, arg *ListPaymentPlansByMerchantIDLatestFirstParams) ([]*ListPaymentPlansByMerchantIDLatestFirstRow, error)
+	ListPaymentPlansByMerchantIDOldestFirst(ctx context.Context, arg *ListPaymentPlansByMerchantIDOldestFirstParams) ([]*ListPaymentPlansByMerchantIDOldestFirstRow, error)
+	ListPaymentPlansByStatusCreatedBefore(ctx context.Context, arg *ListPaymentPlansByStatusCreatedBeforeParams) ([]*ListPaymentPlansByStatusCreatedBeforeRow, error)
 	ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*ListPaymentPlansByUserIDRow, error)
+	ListPaymentRefundsByPlanID(ctx context.Context, paymentPlanID uuid.UUID) ([]*ListPaymentRefundsByPlanIDRow, error)
+	ListPaymentTransactionsByInstallmentID(ctx context.Context, paymentInstallmentID uuid.UUID) ([]*ListPaymentTransactionsByInstallmentIDRow, error)
+	ListSettledCollectionsCases(ctx context.Context, limit int32) ([]*ListSettledCollectionsCasesRow, error)
+	ListUnpaidPaymentInstallmentsDueBetween(ctx context.Context, arg *ListUnpaidPaymentInstallmentsDueBetweenParams) ([]*ListUnpaidPaymentInstallmentsDueBetweenRow, error)
+	ListUnpublishedOutboxEventsForUpdate(ctx context.Context, limit int32) ([]*ListUnpublishedOutboxEventsForUpdateRow, error)
+	ListWebhookDeliveriesByStatus(ctx context.Context, status WebhookDeliveryStatus) ([]*ListWebhookDeliveriesByStatusRow, error)
+	ListWebhookEndpointsByMerchantID(ctx context.Context, merchantID uuid.UUID) ([]*ListWebhookEndpointsByMerchantIDRow, error)
+	UpdateCollectionsCase(ctx context.Context, arg *UpdateCollectionsCaseParams) (*UpdateCollectionsCaseRow, error)
+	UpdateCreditLineLimit(ctx context.Context, arg *UpdateCreditLineLimitParams) (*UpdateCreditLineLimitRow, error)
+	UpdateCreditLineStatus(ctx context.Context, arg *UpdateCreditLineStatusParams) (*UpdateCreditLineStatusRow, error)
+	UpdateOutboxEventFailedAt(ctx context.Context, arg *UpdateOutboxEventFailedAtParams) (*UpdateOutboxEventFailedAtRow, error)
+	UpdateOutboxEventPublishedAt(ctx context.Context, arg *UpdateOutboxEventPublishedAtParams) (*UpdateOutboxEventPublishedAtRow, error)
+	UpdatePaymentInstallmentAmount(ctx context.Context, arg *UpdatePaymentInstallmentAmountParams) (*UpdatePaymentInstallmentAmountRow, error)
+	UpdatePaymentInstallmentStatus(ctx context.Context, arg *UpdatePaymentInstallmentStatusParams) (*UpdatePaymentInstallmentStatusRow, error)
+	UpdatePaymentInstallmentsStatusDueBefore(ctx context.Context, arg *UpdatePaymentInstallmentsStatusDueBeforeParams) ([]*UpdatePaymentInstallmentsStatusDueBeforeRow, error)
+	UpdatePaymentPlanStatus(ctx context.Context, arg *UpdatePaymentPlanStatusParams) (*UpdatePaymentPlanStatusRow, error)
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg *UpdateWebhookDeliveryAttemptParams) (*UpdateWebhookDeliveryAttemptRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
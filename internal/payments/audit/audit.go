@@ -0,0 +1,294 @@
+// Package audit is the trail of the changes made to payment plans. The entries of a plan are chained, each one
+// is hashed with the hash of the entry before it so an entry changed or removed afterwards breaks the chain.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrBrokenChain = errors.New("broken audit chain")
+
+// the types of actors, the audit_actor_type database enum
+const (
+	// ActorInternal is a service calling the internal API, identified by the name it gives
+	ActorInternal = "internal"
+	// ActorUser is a user calling the user facing API, identified by its uuid
+	ActorUser = "user"
+	// ActorSystem is a worker of this service, identified by its name
+	ActorSystem = "system"
+)
+
+// the actions, the audit_action database enum
+const (
+	ActionPlanCreated                = "plan_created"
+	ActionPlanCompleted              = "plan_completed"
+	ActionPlanCancelled              = "plan_cancelled"
+	ActionPlanExpired                = "plan_expired"
+	ActionPlanRefunded               = "plan_refunded"
+	ActionPlanPaidOff                = "plan_paid_off"
+	ActionPlanRescheduled            = "plan_rescheduled"
+	ActionInstallmentPaymentRecorded = "installment_payment_recorded"
+	ActionInstallmentsPastDue        = "installments_past_due"
+	ActionLateFeeAssessed            = "late_fee_assessed"
+)
+
+// UnknownActor is recorded for the changes made with no actor in their context
+var UnknownActor = Actor{Type: ActorSystem, ID: "unknown"}
+
+// Actor is who made a change
+type Actor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func InternalActor(caller string) Actor {
+	return Actor{Type: ActorInternal, ID: caller}
+}
+
+func UserActor(userID uuid.UUID) Actor {
+	return Actor{Type: ActorUser, ID: userID.String()}
+}
+
+func SystemActor(worker string) Actor {
+	return Actor{Type: ActorSystem, ID: worker}
+}
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+// WithActor attributes the changes made with the returned context to actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext is UnknownActor when no actor was set
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+
+	return UnknownActor
+}
+
+// WithRequestID records the changes made with the returned context as made by the request requestID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext is empty for the changes not made by a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+// Snapshot is the part of a payment plan an action changed, Plan is nil when only installments changed
+type Snapshot struct {
+	Plan         *PlanState         `json:"plan,omitempty"`
+	Installments []InstallmentState `json:"installments,omitempty"`
+}
+
+// PlanState Refundable is only known to refunds
+type PlanState struct {
+	Status     string          `json:"status"`
+	Amount     payments.Money  `json:"amount"`
+	Refundable *payments.Money `json:"refundable,omitempty"`
+}
+
+// InstallmentState LateFees and Outstanding are only known to the actions about them
+type InstallmentState struct {
+	ID          uuid.UUID       `json:"id"`
+	Amount      payments.Money  `json:"amount"`
+	DueAt       time.Time       `json:"due_at"`
+	Status      string          `json:"status"`
+	Version     int32           `json:"version"`
+	LateFees    *payments.Money `json:"late_fees,omitempty"`
+	Outstanding *payments.Money `json:"outstanding,omitempty"`
+}
+
+func NewPlanState(plan *payments.Plan) *PlanState {
+	return &PlanState{Status: plan.Status, Amount: plan.Amount}
+}
+
+func NewInstallmentState(inst *payments.Installment) InstallmentState {
+	return InstallmentState{
+		ID:      inst.ID,
+		Amount:  inst.Amount,
+		DueAt:   inst.DueAt,
+		Status:  inst.Status,
+		Version: inst.Version,
+	}
+}
+
+// NewPlanSnapshot is the plan and all of its installments
+func NewPlanSnapshot(plan *payments.Plan, installments []*payments.Installment) *Snapshot {
+	snapshot := &Snapshot{Plan: NewPlanState(plan)}
+
+	for _, inst := range installments {
+		snapshot.Installments = append(snapshot.Installments, NewInstallmentState(inst))
+	}
+
+	return snapshot
+}
+
+// Entry Before is nil for the creation of the plan, Sequence starts at 1 and PrevHash is empty for the first entry
+type Entry struct {
+	ID            uuid.UUID `json:"id"`
+	PaymentPlanID uuid.UUID `json:"payment_plan_id"`
+	Sequence      int64     `json:"sequence"`
+	Actor         Actor     `json:"actor"`
+	Action        string    `json:"action"`
+	Before        *Snapshot `json:"before"`
+	After         *Snapshot `json:"after"`
+	RequestID     string    `json:"request_id,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	PrevHash      string    `json:"prev_hash"`
+	Hash          string    `json:"hash"`
+}
+
+type CreateEntryParams struct {
+	PaymentPlanID uuid.UUID
+	Sequence      int64
+	Actor         Actor
+	Action        string
+	Before        *Snapshot
+	After         *Snapshot
+	RequestID     string
+	OccurredAt    time.Time
+	PrevHash      string
+	Hash          string
+}
+
+// Change is what an entry records
+type Change struct {
+	PaymentPlanID uuid.UUID
+	Actor         Actor
+	Action        string
+	Before        *Snapshot
+	After         *Snapshot
+	RequestID     string
+	OccurredAt    time.Time
+}
+
+// NewEntry chains change to prev, the latest entry of the plan which is nil when the plan has none yet.
+// OccurredAt is truncated to the microseconds the database keeps so the hash still matches once read back.
+func NewEntry(prev *Entry, change *Change) (*CreateEntryParams, error) {
+	entry := &CreateEntryParams{
+		PaymentPlanID: change.PaymentPlanID,
+		Sequence:      1,
+		Actor:         change.Actor,
+		Action:        change.Action,
+		Before:        change.Before,
+		After:         change.After,
+		RequestID:     change.RequestID,
+		OccurredAt:    change.OccurredAt.UTC().Truncate(time.Microsecond),
+	}
+
+	if prev != nil {
+		if prev.PaymentPlanID != change.PaymentPlanID {
+			return nil, fmt.Errorf("%w: entry %d belongs to plan %s", ErrBrokenChain, prev.Sequence, prev.PaymentPlanID)
+		}
+
+		entry.Sequence = prev.Sequence + 1
+		entry.PrevHash = prev.Hash
+	}
+
+	hash, err := entry.computeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Hash = hash
+
+	return entry, nil
+}
+
+// BrokenChainError Sequence is the first entry which does not match its hash or the entry before it
+type BrokenChainError struct {
+	Sequence int64
+}
+
+func (e BrokenChainError) Error() string {
+	return fmt.Sprintf("%s at entry %d", ErrBrokenChain, e.Sequence)
+}
+
+func (e BrokenChainError) Is(target error) bool {
+	return target == ErrBrokenChain
+}
+
+// Verify walks the entries of a plan in sequence order and hashes every one of them again,
+// the latest entries removed together cannot be told from a shorter trail
+func Verify(entries []*Entry) error {
+	prevHash := ""
+
+	for idx, entry := range entries {
+		wantSequence := int64(idx) + 1
+		if entry.Sequence != wantSequence || entry.PrevHash != prevHash {
+			return BrokenChainError{Sequence: wantSequence}
+		}
+
+		hash, err := (&CreateEntryParams{
+			PaymentPlanID: entry.PaymentPlanID,
+			Sequence:      entry.Sequence,
+			Actor:         entry.Actor,
+			Action:        entry.Action,
+			Before:        entry.Before,
+			After:         entry.After,
+			RequestID:     entry.RequestID,
+			OccurredAt:    entry.OccurredAt,
+			PrevHash:      entry.PrevHash,
+		}).computeHash()
+		if err != nil || hash != entry.Hash {
+			return BrokenChainError{Sequence: wantSequence}
+		}
+
+		prevHash = entry.Hash
+	}
+
+	return nil
+}
+
+// hashedEntry is what the hash of an entry covers, every field but the hash itself
+type hashedEntry struct {
+	PaymentPlanID uuid.UUID `json:"payment_plan_id"`
+	Sequence      int64     `json:"sequence"`
+	Actor         Actor     `json:"actor"`
+	Action        string    `json:"action"`
+	Before        *Snapshot `json:"before"`
+	After         *Snapshot `json:"after"`
+	RequestID     string    `json:"request_id"`
+	OccurredAt    string    `json:"occurred_at"`
+	PrevHash      string    `json:"prev_hash"`
+}
+
+func (e *CreateEntryParams) computeHash() (string, error) {
+	encoded, err := json.Marshal(hashedEntry{
+		PaymentPlanID: e.PaymentPlanID,
+		Sequence:      e.Sequence,
+		Actor:         e.Actor,
+		Action:        e.Action,
+		Before:        e.Before,
+		After:         e.After,
+		RequestID:     e.RequestID,
+		OccurredAt:    e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:      e.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(encoded)
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+)
+
+func TestActorFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	if got := ActorFromContext(ctx); got != UnknownActor {
+		t.Errorf("ActorFromContext() = %v, want %v", got, UnknownActor)
+	}
+
+	if got := RequestIDFromContext(ctx); got != "" {
+		t.Errorf("RequestIDFromContext() = %q, want none", got)
+	}
+
+	userID := uuid.Must(uuid.NewV4())
+	ctx = WithRequestID(WithActor(ctx, UserActor(userID)), "req-1")
+
+	if got, want := ActorFromContext(ctx), (Actor{Type: ActorUser, ID: userID.String()}); got != want {
+		t.Errorf("ActorFromContext() = %v, want %v", got, want)
+	}
+
+	if got := RequestIDFromContext(ctx); got != "req-1" {
+		t.Errorf("RequestIDFromContext() = %q, want req-1", got)
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		planID     = uuid.FromStringOrNil("6b9b3c4c-5d2e-4f0a-9d5b-1c2d3e4f5a6b")
+		occurredAt = time.Date(2022, 10, 19, 4, 0, 0, 123456789, time.UTC)
+		amount     = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+	)
+
+	first, err := NewEntry(nil, &Change{
+		PaymentPlanID: planID,
+		Actor:         InternalActor("checkout"),
+		Action:        ActionPlanCreated,
+		After:         &Snapshot{Plan: &PlanState{Status: "pending", Amount: amount}},
+		RequestID:     "req-1",
+		OccurredAt:    occurredAt,
+	})
+	if err != nil {
+		t.Fatalf("NewEntry() error = %v", err)
+	}
+
+	if first.Sequence != 1 || first.PrevHash != "" || len(first.Hash) != 64 {
+		t.Errorf("NewEntry() = %v, want the first entry of the chain", first)
+	}
+
+	if !first.OccurredAt.Equal(occurredAt.Truncate(time.Microsecond)) {
+		t.Errorf("NewEntry() occurred at %v, want it truncated to the microsecond", first.OccurredAt)
+	}
+
+	second, err := NewEntry(newEntry(first), &Change{
+		PaymentPlanID: planID,
+		Actor:         SystemActor("installment_scheduler"),
+		Action:        ActionPlanExpired,
+		Before:        first.After,
+		After:         &Snapshot{Plan: &PlanState{Status: "cancelled", Amount: amount}},
+		OccurredAt:    occurredAt.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("NewEntry() error = %v", err)
+	}
+
+	if second.Sequence != 2 || second.PrevHash != first.Hash || second.Hash == first.Hash {
+		t.Errorf("NewEntry() = %v, want it chained to %v", second, first)
+	}
+
+	if _, err := NewEntry(newEntry(first), &Change{PaymentPlanID: uuid.Must(uuid.NewV4())}); !errors.Is(err, ErrBrokenChain) {
+		t.Errorf("expected an entry of another plan to be refused, got %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		planID     = uuid.Must(uuid.NewV4())
+		occurredAt = time.Date(2022, 10, 19, 4, 0, 0, 0, time.UTC)
+		amount     = payments.MustNewMoney(decimal.New(1098, 2), "usdc")
+	)
+
+	chain := func(t *testing.T) []*Entry {
+		t.Helper()
+
+		entries := make([]*Entry, 0, 3)
+
+		var prev *Entry
+
+		for idx, status := range []string{"pending", "complete", "refunded"} {
+			params, err := NewEntry(prev, &Change{
+				PaymentPlanID: planID,
+				Actor:         InternalActor("checkout"),
+				Action:        ActionPlanCreated,
+				After:         &Snapshot{Plan: &PlanState{Status: status, Amount: amount}},
+				OccurredAt:    occurredAt.Add(time.Duration(idx) * time.Minute),
+			})
+			if err != nil {
+				t.Fatalf("NewEntry() error = %v", err)
+			}
+
+			prev = newEntry(params)
+			entries = append(entries, prev)
+		}
+
+		return entries
+	}
+
+	tests := []struct {
+		name         string
+		tamper       func(entries []*Entry) []*Entry
+		wantSequence int64
+	}{
+		{
+			name:   "intact chain",
+			tamper: func(entries []*Entry) []*Entry { return entries },
+		},
+		{
+			name: "read back from json",
+			tamper: func(entries []*Entry) []*Entry {
+				encoded, _ := json.Marshal(entries)
+
+				var decoded []*Entry
+				_ = json.Unmarshal(encoded, &decoded)
+
+				return decoded
+			},
+		},
+		{
+			name: "changed snapshot",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[1].After.Plan.Status = "cancelled"
+
+				return entries
+			},
+			wantSequence: 2,
+		},
+		{
+			name: "changed actor",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[0].Actor = SystemActor("installment_scheduler")
+
+				return entries
+			},
+			wantSequence: 1,
+		},
+		{
+			name: "removed entry",
+			tamper: func(entries []*Entry) []*Entry {
+				return append(entries[:1], entries[2:]...)
+			},
+			wantSequence: 2,
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(entries []*Entry) []*Entry {
+				entries[1].After.Plan.Status = "cancelled"
+				entries[1].Hash, _ = (&CreateEntryParams{
+					PaymentPlanID: entries[1].PaymentPlanID,
+					Sequence:      entries[1].Sequence,
+					Actor:         entries[1].Actor,
+					Action:        entries[1].Action,
+					After:         entries[1].After,
+					OccurredAt:    entries[1].OccurredAt,
+					PrevHash:      entries[1].PrevHash,
+				}).computeHash()
+
+				return entries
+			},
+			wantSequence: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Verify(tt.tamper(chain(t)))
+
+			if tt.wantSequence == 0 {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want none", err)
+				}
+
+				return
+			}
+
+			var broken BrokenChainError
+			if !errors.As(err, &broken) || !errors.Is(err, ErrBrokenChain) || broken.Sequence != tt.wantSequence {
+				t.Errorf("Verify() error = %v, want the chain broken at entry %d", err, tt.wantSequence)
+			}
+		})
+	}
+}
+
+func newEntry(params *CreateEntryParams) *Entry {
+	return &Entry{
+		ID:            uuid.Must(uuid.NewV4()),
+		PaymentPlanID: params.PaymentPlanID,
+		Sequence:      params.Sequence,
+		Actor:         params.Actor,
+		Action:        params.Action,
+		Before:        params.Before,
+		After:         params.After,
+		RequestID:     params.RequestID,
+		OccurredAt:    params.OccurredAt,
+		PrevHash:      params.PrevHash,
+		Hash:          params.Hash,
+	}
+}
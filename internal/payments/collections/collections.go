@@ -0,0 +1,209 @@
+// Package collections chases the delinquent payment plans, the complete plans with an overdue installment.
+// Each one gets a case which climbs a ladder of stages, a notice to the user, a restriction of their credit
+// line, then a handover to the collections team, until the overdue installments are paid or the case is closed.
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrInvalidLadder = errors.New("invalid collections ladder")
+
+// what a stage does when a case reaches it
+const (
+	// ActionNotify sends the rendered notice to the user
+	ActionNotify = "notify"
+	// ActionRestrict freezes the credit line of the user
+	ActionRestrict = "restrict"
+	// ActionHandover hands the case over to the collections team, nothing is done but record it
+	ActionHandover = "handover"
+)
+
+// the events in the history of a case, the collections_case_event_kind database enum
+const (
+	EventOpened    = "opened"
+	EventEscalated = "escalated"
+	EventPaused    = "paused"
+	EventResumed   = "resumed"
+	EventClosed    = "closed"
+)
+
+// Case Stage is the latest stage the case reached, empty until the first one. NextActionAt is when an open case
+// climbs to its next stage, nil once it reached the last one.
+type Case struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	UserID        uuid.UUID
+	Status        string
+	Stage         string
+	NextActionAt  *time.Time
+	OpenedAt      time.Time
+	ClosedAt      *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Event is a step in the history of a case, Stage is the stage the case was at after it
+type Event struct {
+	ID        uuid.UUID
+	CaseID    uuid.UUID
+	Kind      string
+	Stage     string
+	Actor     string
+	Note      string
+	CreatedAt time.Time
+}
+
+type CreateCaseParams struct {
+	PaymentPlanID uuid.UUID
+	UserID        uuid.UUID
+	Status        string
+	OpenedAt      time.Time
+	NextActionAt  *time.Time
+}
+
+type UpdateCaseParams struct {
+	ID           uuid.UUID
+	Status       string
+	Stage        string
+	NextActionAt *time.Time
+	ClosedAt     *time.Time
+}
+
+type CreateEventParams struct {
+	CaseID uuid.UUID
+	Kind   string
+	Stage  string
+	Actor  string
+	Note   string
+}
+
+// ListDueCasesParams selects up to Limit open cases to escalate at Before or earlier
+type ListDueCasesParams struct {
+	Before time.Time
+	Limit  int32
+}
+
+// Stage is reached Delay after the previous stage of the case, or after the case opened for the first stage
+type Stage struct {
+	Name     string
+	Delay    time.Duration
+	Action   string
+	template *template.Template
+}
+
+// TemplateData is what the notice of a stage is rendered with, Amount is the overdue amount of the plan
+// and Installments the number of its overdue installments
+type TemplateData struct {
+	Stage        string
+	Amount       string
+	Currency     string
+	Installments int
+}
+
+// NewStage only the stages which notify need a template
+func NewStage(name string, delay time.Duration, action, text string) (*Stage, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: empty stage name", ErrInvalidLadder)
+	}
+
+	if delay < 0 {
+		return nil, fmt.Errorf("%w: %s has a negative delay", ErrInvalidLadder, name)
+	}
+
+	stage := &Stage{Name: name, Delay: delay, Action: action}
+
+	switch action {
+	case ActionNotify:
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("%w: %s has an empty template", ErrInvalidLadder, name)
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s template: %v", ErrInvalidLadder, name, err)
+		}
+
+		stage.template = tmpl
+	case ActionRestrict, ActionHandover:
+	default:
+		return nil, fmt.Errorf("%w: %s has an unknown action %q", ErrInvalidLadder, name, action)
+	}
+
+	return stage, nil
+}
+
+func (s *Stage) Render(data TemplateData) (string, error) {
+	if s.template == nil {
+		return "", nil
+	}
+
+	var notice strings.Builder
+
+	if err := s.template.Execute(&notice, data); err != nil {
+		return "", fmt.Errorf("render collections stage %s: %w", s.Name, err)
+	}
+
+	return notice.String(), nil
+}
+
+// Ladder is the stages a case climbs, in order, one at a time
+type Ladder struct {
+	stages []*Stage
+}
+
+func NewLadder(stages ...*Stage) (*Ladder, error) {
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("%w: no stage", ErrInvalidLadder)
+	}
+
+	names := make(map[string]struct{}, len(stages))
+
+	for _, stage := range stages {
+		if _, ok := names[stage.Name]; ok {
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrInvalidLadder, stage.Name)
+		}
+
+		names[stage.Name] = struct{}{}
+	}
+
+	return &Ladder{stages: stages}, nil
+}
+
+// Next returns the stage following current, the first one when current is empty.
+// It reports false when current is the last stage or not a stage of the ladder, a nil Ladder has no stage.
+func (l *Ladder) Next(current string) (*Stage, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	if current == "" {
+		return l.stages[0], true
+	}
+
+	for idx, stage := range l.stages {
+		if stage.Name == current && idx+1 < len(l.stages) {
+			return l.stages[idx+1], true
+		}
+	}
+
+	return nil, false
+}
+
+// NextActionAt is when a case which reached current at reachedAt climbs to its next stage, nil when it will not
+func (l *Ladder) NextActionAt(current string, reachedAt time.Time) *time.Time {
+	next, ok := l.Next(current)
+	if !ok {
+		return nil
+	}
+
+	actionAt := reachedAt.Add(next.Delay)
+
+	return &actionAt
+}
@@ -0,0 +1,244 @@
+package collections
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewStage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stage   string
+		delay   time.Duration
+		action  string
+		text    string
+		wantErr bool
+	}{
+		{
+			name:   "happy path",
+			stage:  "reminder",
+			action: ActionNotify,
+			text:   "{{.Amount}} {{.Currency}} is overdue",
+		},
+		{
+			name:   "restrict without a template",
+			stage:  "restriction",
+			delay:  336 * time.Hour,
+			action: ActionRestrict,
+		},
+		{
+			name:    "empty name",
+			stage:   " ",
+			action:  ActionHandover,
+			wantErr: true,
+		},
+		{
+			name:    "negative delay",
+			stage:   "reminder",
+			delay:   -time.Hour,
+			action:  ActionNotify,
+			text:    "{{.Amount}} is overdue",
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			stage:   "reminder",
+			action:  "call",
+			wantErr: true,
+		},
+		{
+			name:    "notify without a template",
+			stage:   "reminder",
+			action:  ActionNotify,
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			stage:   "reminder",
+			action:  ActionNotify,
+			text:    "{{.Amount",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewStage(tt.stage, tt.delay, tt.action, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewStage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidLadder) {
+				t.Errorf("NewStage() error = %v, want %v", err, ErrInvalidLadder)
+			}
+		})
+	}
+}
+
+func TestStage_Render(t *testing.T) {
+	t.Parallel()
+
+	data := TemplateData{Stage: "second_notice", Amount: "25.00", Currency: "usdc", Installments: 2}
+
+	tests := []struct {
+		name    string
+		action  string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "happy path",
+			action: ActionNotify,
+			text:   "{{.Installments}} installments, {{.Amount}} {{.Currency}}, are overdue",
+			want:   "2 installments, 25.00 usdc, are overdue",
+		},
+		{
+			name:   "no template",
+			action: ActionHandover,
+			want:   "",
+		},
+		{
+			name:    "unknown field",
+			action:  ActionNotify,
+			text:    "{{.Fee}} is overdue",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stage, err := NewStage("second_notice", 0, tt.action, tt.text)
+			if err != nil {
+				t.Fatalf("NewStage() error = %v", err)
+			}
+
+			got, err := stage.Render(data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Stage.Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Stage.Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLadder(t *testing.T) {
+	t.Parallel()
+
+	reminder := mustNewStage(t, "reminder", 0, ActionNotify, "{{.Amount}} is overdue")
+	handover := mustNewStage(t, "collections", 720*time.Hour, ActionHandover, "")
+
+	if _, err := NewLadder(reminder, handover); err != nil {
+		t.Fatalf("NewLadder() error = %v", err)
+	}
+
+	if _, err := NewLadder(); !errors.Is(err, ErrInvalidLadder) {
+		t.Errorf("NewLadder() error = %v, want %v", err, ErrInvalidLadder)
+	}
+
+	if _, err := NewLadder(reminder, handover, reminder); !errors.Is(err, ErrInvalidLadder) {
+		t.Errorf("NewLadder() error = %v, want %v", err, ErrInvalidLadder)
+	}
+
+	var noLadder *Ladder
+	if _, ok := noLadder.Next(""); ok {
+		t.Error("Ladder.Next() of a nil ladder ok = true, want false")
+	}
+}
+
+func TestLadder_Next(t *testing.T) {
+	t.Parallel()
+
+	ladder, err := NewLadder(
+		mustNewStage(t, "reminder", 0, ActionNotify, "{{.Amount}} is overdue"),
+		mustNewStage(t, "restriction", 336*time.Hour, ActionRestrict, ""),
+	)
+	if err != nil {
+		t.Fatalf("NewLadder() error = %v", err)
+	}
+
+	reachedAt := time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		current          string
+		want             string
+		wantOK           bool
+		wantNextActionAt *time.Time
+	}{
+		{
+			name:             "no stage yet",
+			current:          "",
+			want:             "reminder",
+			wantOK:           true,
+			wantNextActionAt: &reachedAt,
+		},
+		{
+			name:             "middle stage",
+			current:          "reminder",
+			want:             "restriction",
+			wantOK:           true,
+			wantNextActionAt: timePtr(time.Date(2022, 7, 24, 12, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:    "last stage",
+			current: "restriction",
+		},
+		{
+			name:    "unknown stage",
+			current: "legal",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ladder.Next(tt.current)
+			if ok != tt.wantOK {
+				t.Fatalf("Ladder.Next() ok = %v, want %v", ok, tt.wantOK)
+			}
+
+			if ok && got.Name != tt.want {
+				t.Errorf("Ladder.Next() = %v, want %v", got.Name, tt.want)
+			}
+
+			gotNextActionAt := ladder.NextActionAt(tt.current, reachedAt)
+			if (gotNextActionAt == nil) != (tt.wantNextActionAt == nil) ||
+				(gotNextActionAt != nil && !gotNextActionAt.Equal(*tt.wantNextActionAt)) {
+				t.Errorf("Ladder.NextActionAt() = %v, want %v", gotNextActionAt, tt.wantNextActionAt)
+			}
+		})
+	}
+}
+
+func mustNewStage(t *testing.T, name string, delay time.Duration, action, text string) *Stage {
+	t.Helper()
+
+	stage, err := NewStage(name, delay, action, text)
+	if err != nil {
+		t.Fatalf("NewStage() error = %v", err)
+	}
+
+	return stage
+}
+
+func timePtr(at time.Time) *time.Time {
+	return &at
+}
@@ -0,0 +1,60 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreditLine is how much a user can owe at once on their payment plans, in the currency of the line
+type CreditLine struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Limit     Money     `json:"limit"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateCreditLineParams struct {
+	UserID uuid.UUID
+	Limit  Money
+	Status string
+}
+
+// UserOutstandingAmountParams selects the unpaid installments of the user in Currency
+type UserOutstandingAmountParams struct {
+	UserID   uuid.UUID
+	Currency string
+}
+
+type UpdateCreditLineLimitParams struct {
+	ID    uuid.UUID
+	Limit Money
+}
+
+type UpdateCreditLineStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+// CreditLineChange records who changed a credit line and why, Limit and Status are the ones the line was left with
+type CreditLineChange struct {
+	ID           uuid.UUID `json:"id"`
+	CreditLineID uuid.UUID `json:"credit_line_id"`
+	Action       string    `json:"action"`
+	Actor        string    `json:"actor"`
+	Reason       string    `json:"reason"`
+	Limit        Money     `json:"limit"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CreateCreditLineChangeParams struct {
+	CreditLineID uuid.UUID
+	Action       string
+	Actor        string
+	Reason       string
+	Limit        Money
+	Status       string
+}
@@ -0,0 +1,73 @@
+package currency
+
+import (
+	"sort"
+
+	"github.com/ericlagergren/decimal"
+)
+
+const (
+	USDC = "usdc"
+	USDT = "usdt"
+	USD  = "usd"
+	EUR  = "eur"
+	SGD  = "sgd"
+)
+
+// Currency an amount in it never has more decimal places than MinorUnits
+type Currency struct {
+	Code       string
+	MinorUnits int
+}
+
+// registry lists the currencies of the currency database enum,
+// stablecoins are divisible like their tokens and fiat like ISO 4217
+var registry = map[string]Currency{
+	USDC: {Code: USDC, MinorUnits: 6},
+	USDT: {Code: USDT, MinorUnits: 6},
+	USD:  {Code: USD, MinorUnits: 2},
+	EUR:  {Code: EUR, MinorUnits: 2},
+	SGD:  {Code: SGD, MinorUnits: 2},
+}
+
+// Lookup reports whether code is a supported currency
+func Lookup(code string) (Currency, bool) {
+	cur, ok := registry[code]
+
+	return cur, ok
+}
+
+// Codes returns the supported currency codes sorted
+func Codes() []string {
+	codes := make([]string, 0, len(registry))
+
+	for code := range registry {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return codes
+}
+
+// IsRounded reports whether amount has no more decimal places than the currency allows
+func (c Currency) IsRounded(amount *decimal.Big) bool {
+	// trailing zeros do not count, 1.50 is a valid amount of a currency with one minor unit
+	reduced := new(decimal.Big).Copy(amount).Reduce()
+
+	return reduced.Scale() <= c.MinorUnits
+}
+
+// Round rounds amount down to the minor unit of the currency, an amount is never rounded up
+// so a computed fee or installment does not exceed what it is derived from.
+// Trailing zeros are dropped without switching to an exponent.
+func (c Currency) Round(amount *decimal.Big) *decimal.Big {
+	amount.Context.RoundingMode = decimal.ToZero
+	amount.Quantize(c.MinorUnits)
+
+	if amount.Reduce().Scale() < 0 {
+		amount.Quantize(0)
+	}
+
+	return amount
+}
@@ -0,0 +1,172 @@
+package currency
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		code   string
+		want   Currency
+		wantOk bool
+	}{
+		{
+			name:   "stablecoin",
+			code:   "usdc",
+			want:   Currency{Code: USDC, MinorUnits: 6},
+			wantOk: true,
+		},
+		{
+			name:   "fiat",
+			code:   "sgd",
+			want:   Currency{Code: SGD, MinorUnits: 2},
+			wantOk: true,
+		},
+		{
+			name: "codes are lowercase",
+			code: "USDC",
+		},
+		{
+			name: "unsupported",
+			code: "btc",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Lookup(tt.code)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Lookup() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCodes(t *testing.T) {
+	t.Parallel()
+
+	want := []string{EUR, SGD, USD, USDC, USDT}
+
+	if got := Codes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Codes() = %v, want %v", got, want)
+	}
+}
+
+func TestCurrency_IsRounded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		amount string
+		want   bool
+	}{
+		{
+			name:   "integer",
+			amount: "10",
+			want:   true,
+		},
+		{
+			name:   "minor units",
+			amount: "10.25",
+			want:   true,
+		},
+		{
+			name:   "trailing zeros",
+			amount: "10.2500",
+			want:   true,
+		},
+		{
+			name:   "beyond the minor units",
+			amount: "10.255",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			amount, _ := new(decimal.Big).SetString(tt.amount)
+			cur, _ := Lookup(USD)
+
+			if got := cur.IsRounded(amount); got != tt.want {
+				t.Errorf("IsRounded(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+
+			if amount.String() != tt.amount {
+				t.Errorf("amount was modified: %v", amount)
+			}
+		})
+	}
+}
+
+func TestCurrency_Round(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		code   string
+		amount string
+		want   string
+	}{
+		{
+			name:   "rounded down to cents",
+			code:   USD,
+			amount: "33.339",
+			want:   "33.33",
+		},
+		{
+			name:   "rounded down to the token decimals",
+			code:   USDC,
+			amount: "33.3333339",
+			want:   "33.333333",
+		},
+		{
+			name:   "trailing zeros are dropped",
+			code:   EUR,
+			amount: "25.000",
+			want:   "25",
+		},
+		{
+			name:   "no exponent",
+			code:   EUR,
+			amount: "1000",
+			want:   "1000",
+		},
+		{
+			name:   "below one minor unit",
+			code:   USD,
+			amount: "0.009",
+			want:   "0",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			amount := &decimal.Big{Context: decimal.Context128}
+			amount.SetString(tt.amount)
+
+			cur, _ := Lookup(tt.code)
+
+			if got := cur.Round(amount).String(); got != tt.want {
+				t.Errorf("Round(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
@@ -66,6 +66,13 @@ const docTemplate = `{
                         "description": "Order by payment.created_at asc  OR desc",
                         "name": "created_at_order",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Also list the installments superseded by reschedules",
+                        "name": "history",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -78,30 +85,95 @@ const docTemplate = `{
                 }
             }
         },
-        "/internal/v1/payment-plans/{uuid}/complete": {
+        "/api/v1/payment-plans/{payment_uuid}/payoff": {
+            "get": {
+                "description": "returns the outstanding amount of every unpaid installment of a payment plan, late fees included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Quotes a payment plan payoff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "X-CRYPTO-USER-UUID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/userfacing.QuotePaymentPlanPayoffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid payment uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is closed or already paid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "completes a payment plan",
+                "description": "records one settlement of the quoted amount, every unpaid installment is marked paid\nand a pending payment plan is completed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "payment_plan"
                 ],
-                "summary": "Completes a payment plan",
+                "summary": "Pays off a payment plan",
                 "parameters": [
                     {
-                        "description": "Complete payment plan reqBody",
-                        "name": "complete_payment_plan_request",
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "X-CRYPTO-USER-UUID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Pay off payment plan reqBody",
+                        "name": "pay_off_payment_plan_request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internalfacing.CompletePaymentPlanRequest"
+                            "$ref": "#/definitions/userfacing.PayOffPaymentPlanRequest"
                         }
                     },
                     {
                         "type": "string",
                         "description": "Payment Plan UUID",
-                        "name": "uuid",
+                        "name": "payment_uuid",
                         "in": "path",
                         "required": true
                     }
@@ -110,29 +182,178 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internalfacing.CompletePaymentPlanResponse"
+                            "$ref": "#/definitions/userfacing.PayOffPaymentPlanResponse"
                         }
                     },
                     "400": {
-                        "description": "bad reqBody",
+                        "description": "bad reqBody, invalid amount or missing processor reference",
                         "schema": {
                             "$ref": "#/definitions/handlerwrap.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "payment plan not belongs to user",
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is closed or already paid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency mismatch or amount does not match the outstanding amount",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/collections-cases": {
+            "get": {
+                "description": "the cases in status, open by default, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Lists collections cases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "open, paused or closed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.ListCollectionsCasesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/collections-cases/{case_uuid}": {
+            "get": {
+                "description": "the case with the stages it went through and who paused, resumed or closed it, the oldest step first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Gets a collections case",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collections case UUID",
+                        "name": "case_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid case uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "collections case not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
                         "schema": {
                             "$ref": "#/definitions/handlerwrap.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/internal/v1/collections-cases/{case_uuid}/close": {
+            "post": {
+                "description": "a closed case cannot be reopened, a credit line frozen by the case stays frozen,\nthe plan gets a new case if it is still delinquent on the next collections run",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Closes a collections case",
+                "parameters": [
+                    {
+                        "description": "Collections case change reqBody",
+                        "name": "collections_case_change_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collections case UUID",
+                        "name": "case_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseResponse"
+                        }
                     },
-                    "403": {
-                        "description": "payment plan is not in pending",
+                    "400": {
+                        "description": "bad reqBody or missing actor or reason",
                         "schema": {
                             "$ref": "#/definitions/handlerwrap.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "payment plan not found",
+                        "description": "collections case not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "collections case is already closed",
                         "schema": {
                             "$ref": "#/definitions/handlerwrap.ErrorResponse"
                         }
@@ -146,36 +367,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/internal/v1/payment_plans": {
+        "/internal/v1/collections-cases/{case_uuid}/pause": {
             "post": {
-                "description": "pre creates a payment plan",
+                "description": "a paused case keeps its stage and is still closed once the overdue installments are paid",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payment_plan"
+                    "collections"
                 ],
-                "summary": "Creates a pending a payment plan",
+                "summary": "Pauses a collections case",
                 "parameters": [
                     {
-                        "description": "Pre create payment plan reqBody",
-                        "name": "pre_create_payment_plan_request",
+                        "description": "Collections case change reqBody",
+                        "name": "collections_case_change_request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internalfacing.CreatePendingPaymentPlanRequest"
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseChangeRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collections case UUID",
+                        "name": "case_uuid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internalfacing.CreatePendingPaymentPlanResponse"
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseResponse"
                         }
                     },
                     "400": {
-                        "description": "bad reqBody",
+                        "description": "bad reqBody or missing actor or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "collections case not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "collections case is not open",
                         "schema": {
                             "$ref": "#/definitions/handlerwrap.ErrorResponse"
                         }
@@ -188,65 +428,2339 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlerwrap.ErrorResponse": {
+        },
+        "/internal/v1/collections-cases/{case_uuid}/resume": {
+            "post": {
+                "description": "the next stage of the case is reached its delay after now",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Resumes a collections case",
+                "parameters": [
+                    {
+                        "description": "Collections case change reqBody",
+                        "name": "collections_case_change_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collections case UUID",
+                        "name": "case_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CollectionsCaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or missing actor or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "collections case not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "collections case is not paused",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/credit-lines/{user_uuid}": {
+            "post": {
+                "description": "opens an active credit line, a user has one credit line at most",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credit_line"
+                ],
+                "summary": "Creates a credit line",
+                "parameters": [
+                    {
+                        "description": "Create credit line reqBody",
+                        "name": "create_credit_line_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreateCreditLineRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service calling the internal API, recorded as the actor of the change",
+                        "name": "X-Internal-Caller",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody, invalid limit or missing X-Internal-Caller or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "the user already has a credit line",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/credit-lines/{user_uuid}/changes": {
+            "get": {
+                "description": "the oldest change comes first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credit_line"
+                ],
+                "summary": "Lists the changes of a credit line",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.ListCreditLineChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid user uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "credit line not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/credit-lines/{user_uuid}/close": {
+            "post": {
+                "description": "a closed credit line takes no new payment plans and cannot be reopened,\nits running payment plans are left as they are",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credit_line"
+                ],
+                "summary": "Closes a credit line",
+                "parameters": [
+                    {
+                        "description": "Credit line change reqBody",
+                        "name": "credit_line_change_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service calling the internal API, recorded as the actor of the change",
+                        "name": "X-Internal-Caller",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or missing X-Internal-Caller or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "credit line not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "credit line is already closed",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/credit-lines/{user_uuid}/freeze": {
+            "post": {
+                "description": "a frozen credit line takes no new payment plans until it is unfrozen",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credit_line"
+                ],
+                "summary": "Freezes a credit line",
+                "parameters": [
+                    {
+                        "description": "Credit line change reqBody",
+                        "name": "credit_line_change_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service calling the internal API, recorded as the actor of the change",
+                        "name": "X-Internal-Caller",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or missing X-Internal-Caller or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "credit line not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "credit line is not active",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/credit-lines/{user_uuid}/limit": {
+            "post": {
+                "description": "a limit lowered below what the user owes leaves nothing available, running payment plans are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credit_line"
+                ],
+                "summary": "Updates a credit line limit",
+                "parameters": [
+                    {
+                        "description": "Update credit line limit reqBody",
+                        "name": "update_credit_line_limit_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.UpdateCreditLineLimitRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service calling the internal API, recorded as the actor of the change",
+                        "name": "X-Internal-Caller",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody, invalid limit or missing X-Internal-Caller or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "credit line not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "credit line is closed",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/credit-lines/{user_uuid}/unfreeze": {
+            "post": {
+                "description": "a frozen credit line is active again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credit_line"
+                ],
+                "summary": "Unfreezes a credit line",
+                "parameters": [
+                    {
+                        "description": "Credit line change reqBody",
+                        "name": "credit_line_change_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service calling the internal API, recorded as the actor of the change",
+                        "name": "X-Internal-Caller",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreditLineResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or missing X-Internal-Caller or reason",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "credit line not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "credit line is not frozen",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/merchants": {
+            "post": {
+                "description": "creates a merchant the payment plans can be created for",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "summary": "Creates a merchant",
+                "parameters": [
+                    {
+                        "description": "Create merchant reqBody",
+                        "name": "create_merchant_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreateMerchantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.MerchantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or missing name",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/merchants/{merchant_uuid}/orders/{order_reference}/payment-plan": {
+            "get": {
+                "description": "the plan created with the order reference, its line items and its current schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "summary": "Gets the payment plan of a merchant order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Merchant UUID",
+                        "name": "merchant_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order reference of the merchant",
+                        "name": "order_reference",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.MerchantOrderPaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid merchant uuid or order reference",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "merchant or order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/merchants/{merchant_uuid}/payment-plans": {
+            "get": {
+                "description": "the plans of the merchant with their current schedule, the latest first by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "summary": "Lists the payment plans of a merchant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Merchant UUID",
+                        "name": "merchant_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of plans to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of plans to return, at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc",
+                        "name": "created_at_order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.ListMerchantPaymentPlansResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid pagination",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "merchant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/merchants/{merchant_uuid}/totals": {
+            "get": {
+                "description": "per currency, the originated, outstanding and refunded amounts of the plans created from from to to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "summary": "Gets the totals of a merchant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Merchant UUID",
+                        "name": "merchant_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start of the range, included",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end of the range, excluded",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.MerchantTotalsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "missing or invalid date range",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "merchant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/merchants/{merchant_uuid}/webhook-endpoints": {
+            "post": {
+                "description": "the events of the plans of the merchant are posted to the url, signed with the returned secret",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Registers a webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Register webhook endpoint reqBody",
+                        "name": "register_webhook_endpoint_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.RegisterWebhookEndpointRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merchant UUID",
+                        "name": "merchant_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.WebhookEndpointResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or invalid url",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "merchant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/audit": {
+            "get": {
+                "description": "returns every change made to a payment plan, oldest first, with who made it\nand the plan before and after. The entries are hash-chained, intact is false and broken_at\nthe first entry not matching its hash or the entry before it when the trail was tampered with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Gets the audit trail of a payment plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.GetPaymentPlanAuditResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid payment uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/cancel": {
+            "post": {
+                "description": "cancels a payment plan which is not completed yet, its installments are voided",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Cancels a payment plan",
+                "parameters": [
+                    {
+                        "description": "Cancel payment plan reqBody",
+                        "name": "cancel_payment_plan_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CancelPaymentPlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CancelPaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/installments/{installment_uuid}/payments": {
+            "post": {
+                "description": "records a full or partial payment of an installment, the installment is paid once fully covered",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Records an installment payment",
+                "parameters": [
+                    {
+                        "description": "Record installment payment reqBody",
+                        "name": "record_installment_payment_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.RecordInstallmentPaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Installment UUID",
+                        "name": "installment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.RecordInstallmentPaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody, invalid amount or missing processor reference",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment installment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment installment is already paid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency mismatch or amount exceeds the outstanding amount",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/ledger": {
+            "get": {
+                "description": "returns every journal entry posted for a payment plan, oldest first, and the balance of each account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Gets the ledger of a payment plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.GetPaymentPlanLedgerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid payment uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/payoff": {
+            "get": {
+                "description": "returns the outstanding amount of every unpaid installment of a payment plan, late fees included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Quotes a payment plan payoff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.QuotePaymentPlanPayoffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid payment or user uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is closed or already paid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "records one settlement of the quoted amount, every unpaid installment is marked paid\nand a pending payment plan is completed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Pays off a payment plan",
+                "parameters": [
+                    {
+                        "description": "Pay off payment plan reqBody",
+                        "name": "pay_off_payment_plan_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.PayOffPaymentPlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.PayOffPaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody, invalid amount or missing processor reference",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is closed or already paid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency mismatch or amount does not match the outstanding amount",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/refunds": {
+            "post": {
+                "description": "refunds a completed payment plan fully when no amount is given or partially otherwise,\na partial refund lowers what is left to pay, the latest installments first, and gives back\nwhat was paid for the rest. A full refund voids the unpaid installments.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Refunds a payment plan",
+                "parameters": [
+                    {
+                        "description": "Refund payment plan reqBody",
+                        "name": "refund_payment_plan_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.RefundPaymentPlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.RefundPaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody or invalid amount",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency mismatch or amount exceeds the refundable amount",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{payment_uuid}/reschedule": {
+            "post": {
+                "description": "replaces the unpaid installments of a completed payment plan with new ones covering\nthe outstanding amount, late fees included. The installments can be listed or generated from a schedule,\nthe replaced installments are kept as superseded history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Reschedules a payment plan",
+                "parameters": [
+                    {
+                        "description": "Reschedule payment plan reqBody",
+                        "name": "reschedule_payment_plan_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.ReschedulePaymentPlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "payment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.ReschedulePaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody, invalid installments or schedule",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is not complete or already paid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "installments do not add up to the outstanding amount",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment-plans/{uuid}/complete": {
+            "post": {
+                "description": "completes a payment plan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Completes a payment plan",
+                "parameters": [
+                    {
+                        "description": "Complete payment plan reqBody",
+                        "name": "complete_payment_plan_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CompletePaymentPlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Plan UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CompletePaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "payment plan not belongs to user",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "payment plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payment_plans": {
+            "post": {
+                "description": "pre creates a payment plan, the installments are either listed or generated from a schedule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment_plan"
+                ],
+                "summary": "Creates a pending a payment plan",
+                "parameters": [
+                    {
+                        "description": "Pre create payment plan reqBody",
+                        "name": "pre_create_payment_plan_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreatePendingPaymentPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.CreatePendingPaymentPlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "bad reqBody, invalid amount, invalid schedule or invalid order",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "the user has no credit line",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "payment plan id or order reference already used",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "installments do not add up to a valid payment plan or exceed the available credit",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/webhook-deliveries": {
+            "get": {
+                "description": "the deliveries in status, dead by default, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Lists webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or dead",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.ListWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/webhook-deliveries/{delivery_uuid}/replay": {
+            "post": {
+                "description": "the dead delivery is pending again with all of its attempts, it is sent by the next dispatch",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Replays a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery UUID",
+                        "name": "delivery_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internalfacing.WebhookDeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid delivery uuid",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "webhook delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "webhook delivery is not dead",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/handlerwrap.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "audit.Actor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.InstallmentState": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "late_fees": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "outstanding": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "audit.PlanState": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "refundable": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.Snapshot": {
+            "type": "object",
+            "properties": {
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/audit.InstallmentState"
+                    }
+                },
+                "plan": {
+                    "$ref": "#/definitions/audit.PlanState"
+                }
+            }
+        },
+        "handlerwrap.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "string"
+                },
+                "error_msg": {
+                    "type": "string"
+                }
+            }
+        },
+        "internalfacing.CancelPaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.CancelPaymentPlanParams"
+                }
+            }
+        },
+        "internalfacing.CancelPaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.PaymentPlans"
+                }
+            }
+        },
+        "internalfacing.CollectionsCaseChangeRequest": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/service.CollectionsCaseChangeParams"
+                }
+            }
+        },
+        "internalfacing.CollectionsCaseResponse": {
+            "type": "object",
+            "properties": {
+                "case": {
+                    "$ref": "#/definitions/service.CollectionsCase"
+                }
+            }
+        },
+        "internalfacing.CompletePaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.CompletePaymentPlanParams"
+                }
+            }
+        },
+        "internalfacing.CompletePaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.PaymentPlans"
+                }
+            }
+        },
+        "internalfacing.CreateCreditLineRequest": {
+            "type": "object",
+            "properties": {
+                "credit_line": {
+                    "$ref": "#/definitions/service.CreateCreditLineParams"
+                }
+            }
+        },
+        "internalfacing.CreateMerchantRequest": {
+            "type": "object",
+            "properties": {
+                "merchant": {
+                    "$ref": "#/definitions/service.CreateMerchantParams"
+                }
+            }
+        },
+        "internalfacing.CreatePendingPaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.CreatePaymentPlanParams"
+                }
+            }
+        },
+        "internalfacing.CreatePendingPaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.PaymentPlans"
+                }
+            }
+        },
+        "internalfacing.CreditLineChangeRequest": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/service.CreditLineChangeParams"
+                }
+            }
+        },
+        "internalfacing.CreditLineResponse": {
+            "type": "object",
+            "properties": {
+                "credit_line": {
+                    "$ref": "#/definitions/service.CreditLine"
+                }
+            }
+        },
+        "internalfacing.GetPaymentPlanAuditResponse": {
+            "type": "object",
+            "properties": {
+                "audit": {
+                    "$ref": "#/definitions/service.PaymentPlanAudit"
+                }
+            }
+        },
+        "internalfacing.GetPaymentPlanLedgerResponse": {
+            "type": "object",
+            "properties": {
+                "ledger": {
+                    "$ref": "#/definitions/service.PaymentPlanLedger"
+                }
+            }
+        },
+        "internalfacing.ListCollectionsCasesResponse": {
+            "type": "object",
+            "properties": {
+                "cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CollectionsCase"
+                    }
+                }
+            }
+        },
+        "internalfacing.ListCreditLineChangesResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CreditLineChange"
+                    }
+                }
+            }
+        },
+        "internalfacing.ListMerchantPaymentPlansResponse": {
+            "type": "object",
+            "properties": {
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlans"
+                    }
+                }
+            }
+        },
+        "internalfacing.ListWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.WebhookDelivery"
+                    }
+                }
+            }
+        },
+        "internalfacing.MerchantOrderPaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.PaymentPlans"
+                }
+            }
+        },
+        "internalfacing.MerchantResponse": {
+            "type": "object",
+            "properties": {
+                "merchant": {
+                    "$ref": "#/definitions/service.Merchant"
+                }
+            }
+        },
+        "internalfacing.MerchantTotalsResponse": {
+            "type": "object",
+            "properties": {
+                "totals": {
+                    "$ref": "#/definitions/service.MerchantTotals"
+                }
+            }
+        },
+        "internalfacing.PayOffPaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "payoff": {
+                    "$ref": "#/definitions/service.PaymentPlanPayoffParams"
+                }
+            }
+        },
+        "internalfacing.PayOffPaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payoff": {
+                    "$ref": "#/definitions/service.PaymentPlanPayoff"
+                }
+            }
+        },
+        "internalfacing.QuotePaymentPlanPayoffResponse": {
+            "type": "object",
+            "properties": {
+                "payoff": {
+                    "$ref": "#/definitions/service.PaymentPlanPayoffQuote"
+                }
+            }
+        },
+        "internalfacing.RecordInstallmentPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.InstallmentPaymentParams"
+                }
+            }
+        },
+        "internalfacing.RecordInstallmentPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.InstallmentPayment"
+                }
+            }
+        },
+        "internalfacing.RefundPaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "refund": {
+                    "$ref": "#/definitions/service.RefundPaymentPlanParams"
+                }
+            }
+        },
+        "internalfacing.RefundPaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "refund": {
+                    "$ref": "#/definitions/service.PaymentPlanRefund"
+                }
+            }
+        },
+        "internalfacing.RegisterWebhookEndpointRequest": {
+            "type": "object",
+            "properties": {
+                "endpoint": {
+                    "$ref": "#/definitions/service.RegisterWebhookEndpointParams"
+                }
+            }
+        },
+        "internalfacing.ReschedulePaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "reschedule": {
+                    "$ref": "#/definitions/service.ReschedulePaymentPlanParams"
+                }
+            }
+        },
+        "internalfacing.ReschedulePaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payment": {
+                    "$ref": "#/definitions/service.PaymentPlans"
+                }
+            }
+        },
+        "internalfacing.UpdateCreditLineLimitRequest": {
+            "type": "object",
+            "properties": {
+                "credit_line": {
+                    "$ref": "#/definitions/service.UpdateCreditLineLimitParams"
+                }
+            }
+        },
+        "internalfacing.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "$ref": "#/definitions/service.WebhookDelivery"
+                }
+            }
+        },
+        "internalfacing.WebhookEndpointResponse": {
+            "type": "object",
+            "properties": {
+                "endpoint": {
+                    "$ref": "#/definitions/service.WebhookEndpoint"
+                }
+            }
+        },
+        "ledger.Balance": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                }
+            }
+        },
+        "payments.moneyJSON": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "$ref": "#/definitions/audit.Actor"
+                },
+                "after": {
+                    "$ref": "#/definitions/audit.Snapshot"
+                },
+                "before": {
+                    "$ref": "#/definitions/audit.Snapshot"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.CancelPaymentPlanParams": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CollectionsCase": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CollectionsCaseEvent"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_action_at": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "payment_plan_id": {
+                    "type": "string"
+                },
+                "stage": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CollectionsCaseChangeParams": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CollectionsCaseEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "stage": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CompletePaymentPlanParams": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreateCreditLineParams": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreateMerchantParams": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreatePaymentPlanParams": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanInstallmentParams"
+                    }
+                },
+                "line_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanLineItemParams"
+                    }
+                },
+                "merchant_id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "$ref": "#/definitions/service.PaymentPlanScheduleParams"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreditLine": {
+            "type": "object",
+            "properties": {
+                "available_amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreditLineChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "limit": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreditLineChangeParams": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.InstallmentPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "installment_id": {
+                    "type": "string"
+                },
+                "installment_status": {
+                    "type": "string"
+                },
+                "outstanding_amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "processor_reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.InstallmentPaymentParams": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "processor_reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.JournalEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "postings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.JournalPosting"
+                    }
+                }
+            }
+        },
+        "service.JournalPosting": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "direction": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.Merchant": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.MerchantCurrencyTotals": {
+            "type": "object",
+            "properties": {
+                "originated": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "outstanding": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "refunded": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                }
+            }
+        },
+        "service.MerchantTotals": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "merchant_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.MerchantCurrencyTotals"
+                    }
+                }
+            }
+        },
+        "service.PaymentInstallmentRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "installment_id": {
+                    "type": "string"
+                },
+                "refunded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PaymentPlanAudit": {
+            "type": "object",
+            "properties": {
+                "broken_at": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.AuditEntry"
+                    }
+                },
+                "intact": {
+                    "type": "boolean"
+                },
+                "payment_plan_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PaymentPlanInstallment": {
             "type": "object",
             "properties": {
-                "error_code": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "due_at": {
                     "type": "string"
                 },
-                "error_msg": {
+                "id": {
+                    "type": "string"
+                },
+                "late_fees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanLateFee"
+                    }
+                },
+                "status": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "internalfacing.CompletePaymentPlanRequest": {
+        "service.PaymentPlanInstallmentParams": {
             "type": "object",
             "properties": {
-                "payment": {
-                    "$ref": "#/definitions/service.CompletePaymentPlanParams"
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "due_at": {
+                    "type": "string"
                 }
             }
         },
-        "internalfacing.CompletePaymentPlanResponse": {
+        "service.PaymentPlanLateFee": {
             "type": "object",
             "properties": {
-                "payment": {
-                    "$ref": "#/definitions/service.PaymentPlans"
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "assessed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "installment_id": {
+                    "type": "string"
                 }
             }
         },
-        "internalfacing.CreatePendingPaymentPlanRequest": {
+        "service.PaymentPlanLedger": {
             "type": "object",
             "properties": {
-                "payment": {
-                    "$ref": "#/definitions/service.CreatePaymentPlanParams"
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ledger.Balance"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.JournalEntry"
+                    }
+                },
+                "payment_plan_id": {
+                    "type": "string"
                 }
             }
         },
-        "internalfacing.CreatePendingPaymentPlanResponse": {
+        "service.PaymentPlanLineItem": {
             "type": "object",
             "properties": {
-                "payment": {
-                    "$ref": "#/definitions/service.PaymentPlans"
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/payments.moneyJSON"
                 }
             }
         },
-        "service.CompletePaymentPlanParams": {
+        "service.PaymentPlanLineItemParams": {
             "type": "object",
             "properties": {
-                "user_id": {
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
                     "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/payments.moneyJSON"
                 }
             }
         },
-        "service.CreatePaymentPlanParams": {
+        "service.PaymentPlanPayoff": {
             "type": "object",
             "properties": {
-                "currency": {
-                    "type": "string"
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
                 },
                 "id": {
                     "type": "string"
@@ -254,47 +2768,99 @@ const docTemplate = `{
                 "installments": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/service.PaymentPlanInstallmentParams"
+                        "$ref": "#/definitions/service.PaymentPlanInstallment"
                     }
                 },
-                "total_amount": {
+                "paid_at": {
                     "type": "string"
                 },
-                "user_id": {
+                "payment_plan_id": {
+                    "type": "string"
+                },
+                "processor_reference": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "service.PaymentPlanInstallment": {
+        "service.PaymentPlanPayoffParams": {
             "type": "object",
             "properties": {
                 "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "paid_at": {
                     "type": "string"
                 },
-                "currency": {
+                "processor_reference": {
                     "type": "string"
                 },
-                "due_at": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "service.PaymentPlanPayoffQuote": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
                 },
-                "id": {
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanInstallment"
+                    }
+                },
+                "payment_plan_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PaymentPlanRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanInstallment"
+                    }
+                },
+                "payment_plan_id": {
+                    "type": "string"
+                },
+                "reason": {
                     "type": "string"
                 },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentInstallmentRefund"
+                    }
+                },
                 "status": {
                     "type": "string"
                 }
             }
         },
-        "service.PaymentPlanInstallmentParams": {
+        "service.PaymentPlanScheduleParams": {
             "type": "object",
             "properties": {
-                "amount": {
+                "count": {
+                    "type": "integer"
+                },
+                "frequency": {
                     "type": "string"
                 },
-                "currency": {
+                "remainder": {
                     "type": "string"
                 },
-                "due_at": {
+                "start_at": {
                     "type": "string"
                 }
             }
@@ -305,9 +2871,15 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
-                "currency": {
+                "description": {
                     "type": "string"
                 },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanInstallment"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
@@ -317,17 +2889,167 @@ const docTemplate = `{
                         "$ref": "#/definitions/service.PaymentPlanInstallment"
                     }
                 },
+                "line_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanLineItem"
+                    }
+                },
+                "merchant_id": {
+                    "type": "string"
+                },
+                "order_reference": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
                 "total_amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "total_late_fees": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.RefundPaymentPlanParams": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.RegisterWebhookEndpointParams": {
+            "type": "object",
+            "properties": {
+                "url": {
                     "type": "string"
+                }
+            }
+        },
+        "service.ReschedulePaymentPlanParams": {
+            "type": "object",
+            "properties": {
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PaymentPlanInstallmentParams"
+                    }
+                },
+                "schedule": {
+                    "$ref": "#/definitions/service.PaymentPlanScheduleParams"
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
+        "service.UpdateCreditLineLimitParams": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "endpoint_id": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.WebhookEndpoint": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "merchant_id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "userfacing.PayOffPaymentPlanParams": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/payments.moneyJSON"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "processor_reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "userfacing.PayOffPaymentPlanRequest": {
+            "type": "object",
+            "properties": {
+                "payoff": {
+                    "$ref": "#/definitions/userfacing.PayOffPaymentPlanParams"
+                }
+            }
+        },
+        "userfacing.PayOffPaymentPlanResponse": {
+            "type": "object",
+            "properties": {
+                "payoff": {
+                    "$ref": "#/definitions/service.PaymentPlanPayoff"
+                }
+            }
+        },
         "userfacing.PaymentPlanResponse": {
             "type": "object",
             "properties": {
@@ -338,6 +3060,14 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "userfacing.QuotePaymentPlanPayoffResponse": {
+            "type": "object",
+            "properties": {
+                "payoff": {
+                    "$ref": "#/definitions/service.PaymentPlanPayoffQuote"
+                }
+            }
         }
     }
 }`
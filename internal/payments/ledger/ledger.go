@@ -0,0 +1,215 @@
+// Package ledger is the double-entry journal of the money moved by payment plans. Every entry
+// debits and credits its accounts by the same amount, entries are only ever appended.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	ErrUnknownAccount  = errors.New("unknown account")
+	ErrInvalidPosting  = errors.New("invalid posting")
+	ErrUnbalancedEntry = errors.New("unbalanced entry")
+)
+
+// the accounts of the ledger, the ledger_account database enum
+const (
+	// AccountCash is the money received from users and paid back to them
+	AccountCash = "cash"
+	// AccountUserReceivable is what users still owe on their plans, late fees included
+	AccountUserReceivable = "user_receivable"
+	// AccountMerchantPayable is what is owed to merchants for the plans of their orders
+	AccountMerchantPayable = "merchant_payable"
+	// AccountFeeIncome is what late fees earned
+	AccountFeeIncome = "fee_income"
+)
+
+// the sides of a posting, the posting_direction database enum
+const (
+	Debit  = "debit"
+	Credit = "credit"
+)
+
+// the kinds of journal entries, the journal_entry_kind database enum
+const (
+	EntryPlanCreated     = "plan_created"
+	EntryInstallmentPaid = "installment_paid"
+	EntryPlanPaidOff     = "plan_paid_off"
+	EntryLateFeeAssessed = "late_fee_assessed"
+	EntryRefunded        = "refunded"
+	// EntryReceivableWrittenOff is what users no longer owe once their unpaid installments are voided
+	EntryReceivableWrittenOff = "receivable_written_off"
+)
+
+// normalSides an account grows with the postings on its normal side, assets with debits
+// and liabilities and income with credits
+var normalSides = map[string]string{
+	AccountCash:            Debit,
+	AccountUserReceivable:  Debit,
+	AccountMerchantPayable: Credit,
+	AccountFeeIncome:       Credit,
+}
+
+// Accounts lists every account of the ledger
+func Accounts() []string {
+	return []string{AccountCash, AccountUserReceivable, AccountMerchantPayable, AccountFeeIncome}
+}
+
+// Entry is a balanced set of postings for one payment plan
+type Entry struct {
+	ID            uuid.UUID `json:"id"`
+	PaymentPlanID uuid.UUID `json:"payment_plan_id"`
+	Kind          string    `json:"kind"`
+	Postings      []Posting `json:"postings"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Posting struct {
+	ID        uuid.UUID      `json:"id"`
+	Account   string         `json:"account"`
+	Direction string         `json:"direction"`
+	Amount    payments.Money `json:"amount"`
+}
+
+type CreateEntryParams struct {
+	PaymentPlanID uuid.UUID
+	Kind          string
+	Postings      []CreatePostingParams
+}
+
+type CreatePostingParams struct {
+	Account   string
+	Direction string
+	Amount    payments.Money
+}
+
+// NewTransfer moves amount from the credited account to the debited one
+func NewTransfer(paymentPlanID uuid.UUID, kind, debited, credited string, amount payments.Money) *CreateEntryParams {
+	return &CreateEntryParams{
+		PaymentPlanID: paymentPlanID,
+		Kind:          kind,
+		Postings: []CreatePostingParams{
+			{Account: debited, Direction: Debit, Amount: amount},
+			{Account: credited, Direction: Credit, Amount: amount},
+		},
+	}
+}
+
+// Validate checks the entry has a debit and a credit at least, positive amounts on known accounts
+// and as much debited as credited in each currency
+func (e *CreateEntryParams) Validate() error {
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("%w: %s has %d postings", ErrUnbalancedEntry, e.Kind, len(e.Postings))
+	}
+
+	net := make(map[string]payments.Money)
+
+	for _, posting := range e.Postings {
+		if _, ok := normalSides[posting.Account]; !ok {
+			return fmt.Errorf("%w %q", ErrUnknownAccount, posting.Account)
+		}
+
+		if posting.Amount.Sign() <= 0 {
+			return fmt.Errorf("%w: %s of %s on %s", ErrInvalidPosting, posting.Direction, posting.Amount, posting.Account)
+		}
+
+		amount, err := signedAmount(posting.Direction, Debit, posting.Amount)
+		if err != nil {
+			return err
+		}
+
+		code := posting.Amount.Currency()
+
+		if sum, ok := net[code]; ok {
+			if amount, err = sum.Add(amount); err != nil {
+				return err
+			}
+		}
+
+		net[code] = amount
+	}
+
+	for code, sum := range net {
+		if sum.Sign() != 0 {
+			return fmt.Errorf("%w: %s is off by %s %s", ErrUnbalancedEntry, e.Kind, sum, code)
+		}
+	}
+
+	return nil
+}
+
+// Balance is what an account holds on its normal side, it is negative when the other side is larger
+type Balance struct {
+	Account string         `json:"account"`
+	Amount  payments.Money `json:"amount"`
+}
+
+// Balances sums the postings of entries in currency by account, in the order of Accounts.
+// Every account is listed, those without postings with a zero balance.
+func Balances(entries []*Entry, currency string) ([]Balance, error) {
+	zero, err := payments.ZeroMoney(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string]payments.Money, len(normalSides))
+
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			normalSide, ok := normalSides[posting.Account]
+			if !ok {
+				return nil, fmt.Errorf("%w %q", ErrUnknownAccount, posting.Account)
+			}
+
+			amount, err := signedAmount(posting.Direction, normalSide, posting.Amount)
+			if err != nil {
+				return nil, err
+			}
+
+			sum, ok := sums[posting.Account]
+			if !ok {
+				sum = zero
+			}
+
+			if sums[posting.Account], err = sum.Add(amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	balances := make([]Balance, 0, len(normalSides))
+
+	for _, account := range Accounts() {
+		sum, ok := sums[account]
+		if !ok {
+			sum = zero
+		}
+
+		balances = append(balances, Balance{Account: account, Amount: sum})
+	}
+
+	return balances, nil
+}
+
+// signedAmount is amount when direction is positiveSide and its opposite otherwise
+func signedAmount(direction, positiveSide string, amount payments.Money) (payments.Money, error) {
+	switch direction {
+	case positiveSide:
+		return amount, nil
+	case Debit, Credit:
+		zero, err := payments.ZeroMoney(amount.Currency())
+		if err != nil {
+			return payments.Money{}, err
+		}
+
+		return zero.Sub(amount)
+	default:
+		return payments.Money{}, fmt.Errorf("%w: unknown direction %q", ErrInvalidPosting, direction)
+	}
+}
@@ -0,0 +1,177 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+)
+
+func TestCreateEntryParams_Validate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		planID = uuid.Must(uuid.NewV4())
+		ten    = payments.MustNewMoney(decimal.New(10, 0), "usdc")
+		four   = payments.MustNewMoney(decimal.New(4, 0), "usdc")
+		six    = payments.MustNewMoney(decimal.New(6, 0), "usdc")
+		tenUSD = payments.MustNewMoney(decimal.New(10, 0), "usd")
+	)
+
+	tests := []struct {
+		name    string
+		entry   *CreateEntryParams
+		wantErr error
+	}{
+		{
+			name:  "transfer",
+			entry: NewTransfer(planID, EntryPlanCreated, AccountUserReceivable, AccountMerchantPayable, ten),
+		},
+		{
+			name: "split credit",
+			entry: &CreateEntryParams{
+				PaymentPlanID: planID,
+				Kind:          EntryReceivableWrittenOff,
+				Postings: []CreatePostingParams{
+					{Account: AccountMerchantPayable, Direction: Debit, Amount: six},
+					{Account: AccountFeeIncome, Direction: Debit, Amount: four},
+					{Account: AccountUserReceivable, Direction: Credit, Amount: ten},
+				},
+			},
+		},
+		{
+			name: "single posting",
+			entry: &CreateEntryParams{
+				PaymentPlanID: planID,
+				Kind:          EntryPlanCreated,
+				Postings:      []CreatePostingParams{{Account: AccountCash, Direction: Debit, Amount: ten}},
+			},
+			wantErr: ErrUnbalancedEntry,
+		},
+		{
+			name: "debits larger than credits",
+			entry: &CreateEntryParams{
+				PaymentPlanID: planID,
+				Kind:          EntryInstallmentPaid,
+				Postings: []CreatePostingParams{
+					{Account: AccountCash, Direction: Debit, Amount: ten},
+					{Account: AccountUserReceivable, Direction: Credit, Amount: six},
+				},
+			},
+			wantErr: ErrUnbalancedEntry,
+		},
+		{
+			name: "amounts in different currencies",
+			entry: &CreateEntryParams{
+				PaymentPlanID: planID,
+				Kind:          EntryInstallmentPaid,
+				Postings: []CreatePostingParams{
+					{Account: AccountCash, Direction: Debit, Amount: ten},
+					{Account: AccountUserReceivable, Direction: Credit, Amount: tenUSD},
+				},
+			},
+			wantErr: ErrUnbalancedEntry,
+		},
+		{
+			name:    "unknown account",
+			entry:   NewTransfer(planID, EntryInstallmentPaid, "bank", AccountUserReceivable, ten),
+			wantErr: ErrUnknownAccount,
+		},
+		{
+			name: "zero amount",
+			entry: NewTransfer(planID, EntryInstallmentPaid, AccountCash, AccountUserReceivable,
+				payments.MustNewMoney(decimal.New(0, 0), "usdc")),
+			wantErr: ErrInvalidPosting,
+		},
+		{
+			name: "unknown direction",
+			entry: &CreateEntryParams{
+				PaymentPlanID: planID,
+				Kind:          EntryInstallmentPaid,
+				Postings: []CreatePostingParams{
+					{Account: AccountCash, Direction: "up", Amount: ten},
+					{Account: AccountUserReceivable, Direction: Credit, Amount: ten},
+				},
+			},
+			wantErr: ErrInvalidPosting,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tt.entry.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBalances(t *testing.T) {
+	t.Parallel()
+
+	var (
+		planID  = uuid.Must(uuid.NewV4())
+		hundred = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+		forty   = payments.MustNewMoney(decimal.New(40, 0), "usdc")
+		five    = payments.MustNewMoney(decimal.New(5, 0), "usdc")
+	)
+
+	newEntry := func(params *CreateEntryParams) *Entry {
+		entry := &Entry{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: params.PaymentPlanID, Kind: params.Kind}
+
+		for _, posting := range params.Postings {
+			entry.Postings = append(entry.Postings, Posting{
+				ID:        uuid.Must(uuid.NewV4()),
+				Account:   posting.Account,
+				Direction: posting.Direction,
+				Amount:    posting.Amount,
+			})
+		}
+
+		return entry
+	}
+
+	entries := []*Entry{
+		newEntry(NewTransfer(planID, EntryPlanCreated, AccountUserReceivable, AccountMerchantPayable, hundred)),
+		newEntry(NewTransfer(planID, EntryLateFeeAssessed, AccountUserReceivable, AccountFeeIncome, five)),
+		newEntry(NewTransfer(planID, EntryInstallmentPaid, AccountCash, AccountUserReceivable, forty)),
+		newEntry(NewTransfer(planID, EntryRefunded, AccountMerchantPayable, AccountCash, forty)),
+	}
+
+	want := map[string]string{
+		AccountCash:            "0",
+		AccountUserReceivable:  "65",
+		AccountMerchantPayable: "60",
+		AccountFeeIncome:       "5",
+	}
+
+	balances, err := Balances(entries, "usdc")
+	if err != nil {
+		t.Fatalf("Balances() error = %v", err)
+	}
+
+	if len(balances) != len(Accounts()) {
+		t.Fatalf("Balances() = %v, want every account", balances)
+	}
+
+	for idx, balance := range balances {
+		if balance.Account != Accounts()[idx] {
+			t.Errorf("Balances()[%d] is %v, want %v", idx, balance.Account, Accounts()[idx])
+		}
+
+		if balance.Amount.String() != want[balance.Account] || balance.Amount.Currency() != "usdc" {
+			t.Errorf("balance of %v = %v, want %v", balance.Account, balance.Amount, want[balance.Account])
+		}
+	}
+
+	if _, err := Balances(entries, "usd"); !errors.Is(err, payments.ErrCurrencyMismatch) {
+		t.Errorf("Balances() in another currency error = %v, want %v", err, payments.ErrCurrencyMismatch)
+	}
+}
@@ -0,0 +1,46 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Merchant sells the purchases the plans pay for
+type Merchant struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CreateMerchantParams struct {
+	ID   uuid.UUID
+	Name string
+}
+
+// ListPlansByMerchantIDParams selects a page of the plans of MerchantID, the latest first
+// unless OldestFirst is set
+type ListPlansByMerchantIDParams struct {
+	MerchantID  uuid.UUID
+	Offset      int32
+	Limit       int32
+	OldestFirst bool
+}
+
+// MerchantTotalsParams selects the complete and refunded plans of MerchantID
+// created from CreatedFrom included to CreatedTo excluded
+type MerchantTotalsParams struct {
+	MerchantID  uuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// MerchantTotals sums the plans of a merchant in one currency: the amounts of the plans,
+// what is left to pay on their unpaid installments late fees included, and what was refunded on them,
+// the merchant payable debited by the refunds whether the money was given back or is no longer owed
+type MerchantTotals struct {
+	Originated  Money
+	Outstanding Money
+	Refunded    Money
+}
@@ -7,6 +7,13 @@ package repomock
 import (
 	context "context"
 	payments "golangreferenceapi/internal/payments"
+	audit "golangreferenceapi/internal/payments/audit"
+	collections "golangreferenceapi/internal/payments/collections"
+	ledger "golangreferenceapi/internal/payments/ledger"
+	outbox "golangreferenceapi/internal/payments/outbox"
+	reminder "golangreferenceapi/internal/payments/reminder"
+	repo "golangreferenceapi/internal/payments/repo"
+	webhook "golangreferenceapi/internal/payments/webhook"
 	reflect "reflect"
 
 	uuid "github.com/gofrs/uuid"
@@ -36,6 +43,126 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateAuditEntry mocks base method.
+func (m *MockRepository) CreateAuditEntry(ctx context.Context, arg *audit.CreateEntryParams) (*audit.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", ctx, arg)
+	ret0, _ := ret[0].(*audit.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockRepositoryMockRecorder) CreateAuditEntry(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockRepository)(nil).CreateAuditEntry), ctx, arg)
+}
+
+// CreateCollectionsCase mocks base method.
+func (m *MockRepository) CreateCollectionsCase(ctx context.Context, arg *collections.CreateCaseParams) (*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollectionsCase", ctx, arg)
+	ret0, _ := ret[0].(*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollectionsCase indicates an expected call of CreateCollectionsCase.
+func (mr *MockRepositoryMockRecorder) CreateCollectionsCase(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollectionsCase", reflect.TypeOf((*MockRepository)(nil).CreateCollectionsCase), ctx, arg)
+}
+
+// CreateCollectionsCaseEvent mocks base method.
+func (m *MockRepository) CreateCollectionsCaseEvent(ctx context.Context, arg *collections.CreateEventParams) (*collections.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollectionsCaseEvent", ctx, arg)
+	ret0, _ := ret[0].(*collections.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollectionsCaseEvent indicates an expected call of CreateCollectionsCaseEvent.
+func (mr *MockRepositoryMockRecorder) CreateCollectionsCaseEvent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollectionsCaseEvent", reflect.TypeOf((*MockRepository)(nil).CreateCollectionsCaseEvent), ctx, arg)
+}
+
+// CreateCreditLine mocks base method.
+func (m *MockRepository) CreateCreditLine(ctx context.Context, arg *payments.CreateCreditLineParams) (*payments.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditLine", ctx, arg)
+	ret0, _ := ret[0].(*payments.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditLine indicates an expected call of CreateCreditLine.
+func (mr *MockRepositoryMockRecorder) CreateCreditLine(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditLine", reflect.TypeOf((*MockRepository)(nil).CreateCreditLine), ctx, arg)
+}
+
+// CreateCreditLineChange mocks base method.
+func (m *MockRepository) CreateCreditLineChange(ctx context.Context, arg *payments.CreateCreditLineChangeParams) (*payments.CreditLineChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditLineChange", ctx, arg)
+	ret0, _ := ret[0].(*payments.CreditLineChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditLineChange indicates an expected call of CreateCreditLineChange.
+func (mr *MockRepositoryMockRecorder) CreateCreditLineChange(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditLineChange", reflect.TypeOf((*MockRepository)(nil).CreateCreditLineChange), ctx, arg)
+}
+
+// CreateJournalEntry mocks base method.
+func (m *MockRepository) CreateJournalEntry(ctx context.Context, arg *ledger.CreateEntryParams) (*ledger.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJournalEntry", ctx, arg)
+	ret0, _ := ret[0].(*ledger.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJournalEntry indicates an expected call of CreateJournalEntry.
+func (mr *MockRepositoryMockRecorder) CreateJournalEntry(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJournalEntry", reflect.TypeOf((*MockRepository)(nil).CreateJournalEntry), ctx, arg)
+}
+
+// CreateMerchant mocks base method.
+func (m *MockRepository) CreateMerchant(ctx context.Context, arg *payments.CreateMerchantParams) (*payments.Merchant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMerchant", ctx, arg)
+	ret0, _ := ret[0].(*payments.Merchant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMerchant indicates an expected call of CreateMerchant.
+func (mr *MockRepositoryMockRecorder) CreateMerchant(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMerchant", reflect.TypeOf((*MockRepository)(nil).CreateMerchant), ctx, arg)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockRepository) CreateOutboxEvent(ctx context.Context, arg *outbox.CreateEventParams) (*outbox.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", ctx, arg)
+	ret0, _ := ret[0].(*outbox.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockRepositoryMockRecorder) CreateOutboxEvent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockRepository)(nil).CreateOutboxEvent), ctx, arg)
+}
+
 // CreatePaymentInstallment mocks base method.
 func (m *MockRepository) CreatePaymentInstallment(ctx context.Context, arg *payments.CreateInstallmentParams) (*payments.Installment, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +178,21 @@ func (mr *MockRepositoryMockRecorder) CreatePaymentInstallment(ctx, arg interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentInstallment", reflect.TypeOf((*MockRepository)(nil).CreatePaymentInstallment), ctx, arg)
 }
 
+// CreatePaymentLateFee mocks base method.
+func (m *MockRepository) CreatePaymentLateFee(ctx context.Context, arg *payments.CreateLateFeeParams) (*payments.LateFee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentLateFee", ctx, arg)
+	ret0, _ := ret[0].(*payments.LateFee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentLateFee indicates an expected call of CreatePaymentLateFee.
+func (mr *MockRepositoryMockRecorder) CreatePaymentLateFee(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentLateFee", reflect.TypeOf((*MockRepository)(nil).CreatePaymentLateFee), ctx, arg)
+}
+
 // CreatePaymentPlan mocks base method.
 func (m *MockRepository) CreatePaymentPlan(ctx context.Context, arg *payments.CreatePlanParams) (*payments.Plan, error) {
 	m.ctrl.T.Helper()
@@ -66,32 +208,855 @@ func (mr *MockRepositoryMockRecorder) CreatePaymentPlan(ctx, arg interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentPlan", reflect.TypeOf((*MockRepository)(nil).CreatePaymentPlan), ctx, arg)
 }
 
-// ListPaymentInstallmentsByPlanID mocks base method.
-func (m *MockRepository) ListPaymentInstallmentsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.Installment, error) {
+// CreatePaymentPlanLineItem mocks base method.
+func (m *MockRepository) CreatePaymentPlanLineItem(ctx context.Context, arg *payments.CreateLineItemParams) (*payments.LineItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPaymentInstallmentsByPlanID", ctx, planID)
-	ret0, _ := ret[0].([]*payments.Installment)
+	ret := m.ctrl.Call(m, "CreatePaymentPlanLineItem", ctx, arg)
+	ret0, _ := ret[0].(*payments.LineItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListPaymentInstallmentsByPlanID indicates an expected call of ListPaymentInstallmentsByPlanID.
-func (mr *MockRepositoryMockRecorder) ListPaymentInstallmentsByPlanID(ctx, planID interface{}) *gomock.Call {
+// CreatePaymentPlanLineItem indicates an expected call of CreatePaymentPlanLineItem.
+func (mr *MockRepositoryMockRecorder) CreatePaymentPlanLineItem(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentInstallmentsByPlanID", reflect.TypeOf((*MockRepository)(nil).ListPaymentInstallmentsByPlanID), ctx, planID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentPlanLineItem", reflect.TypeOf((*MockRepository)(nil).CreatePaymentPlanLineItem), ctx, arg)
 }
 
-// ListPaymentPlansByUserID mocks base method.
-func (m *MockRepository) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*payments.Plan, error) {
+// CreatePaymentRefund mocks base method.
+func (m *MockRepository) CreatePaymentRefund(ctx context.Context, arg *payments.CreateRefundParams) (*payments.Refund, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPaymentPlansByUserID", ctx, userID)
+	ret := m.ctrl.Call(m, "CreatePaymentRefund", ctx, arg)
+	ret0, _ := ret[0].(*payments.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRefund indicates an expected call of CreatePaymentRefund.
+func (mr *MockRepositoryMockRecorder) CreatePaymentRefund(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRefund", reflect.TypeOf((*MockRepository)(nil).CreatePaymentRefund), ctx, arg)
+}
+
+// CreatePaymentSettlement mocks base method.
+func (m *MockRepository) CreatePaymentSettlement(ctx context.Context, arg *payments.CreateSettlementParams) (*payments.Settlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentSettlement", ctx, arg)
+	ret0, _ := ret[0].(*payments.Settlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentSettlement indicates an expected call of CreatePaymentSettlement.
+func (mr *MockRepositoryMockRecorder) CreatePaymentSettlement(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentSettlement", reflect.TypeOf((*MockRepository)(nil).CreatePaymentSettlement), ctx, arg)
+}
+
+// CreatePaymentTransaction mocks base method.
+func (m *MockRepository) CreatePaymentTransaction(ctx context.Context, arg *payments.CreateTransactionParams) (*payments.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentTransaction", ctx, arg)
+	ret0, _ := ret[0].(*payments.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentTransaction indicates an expected call of CreatePaymentTransaction.
+func (mr *MockRepositoryMockRecorder) CreatePaymentTransaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentTransaction", reflect.TypeOf((*MockRepository)(nil).CreatePaymentTransaction), ctx, arg)
+}
+
+// CreateReminderSent mocks base method.
+func (m *MockRepository) CreateReminderSent(ctx context.Context, arg *reminder.CreateSentParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReminderSent", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReminderSent indicates an expected call of CreateReminderSent.
+func (mr *MockRepositoryMockRecorder) CreateReminderSent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReminderSent", reflect.TypeOf((*MockRepository)(nil).CreateReminderSent), ctx, arg)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockRepository) CreateWebhookDelivery(ctx context.Context, arg *webhook.CreateDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockRepositoryMockRecorder) CreateWebhookDelivery(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockRepository)(nil).CreateWebhookDelivery), ctx, arg)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockRepository) CreateWebhookEndpoint(ctx context.Context, arg *webhook.CreateEndpointParams) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", ctx, arg)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockRepositoryMockRecorder) CreateWebhookEndpoint(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockRepository)(nil).CreateWebhookEndpoint), ctx, arg)
+}
+
+// GetActiveCollectionsCaseByPlanID mocks base method.
+func (m *MockRepository) GetActiveCollectionsCaseByPlanID(ctx context.Context, planID uuid.UUID) (*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveCollectionsCaseByPlanID", ctx, planID)
+	ret0, _ := ret[0].(*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveCollectionsCaseByPlanID indicates an expected call of GetActiveCollectionsCaseByPlanID.
+func (mr *MockRepositoryMockRecorder) GetActiveCollectionsCaseByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveCollectionsCaseByPlanID", reflect.TypeOf((*MockRepository)(nil).GetActiveCollectionsCaseByPlanID), ctx, planID)
+}
+
+// GetCollectionsCaseByID mocks base method.
+func (m *MockRepository) GetCollectionsCaseByID(ctx context.Context, id uuid.UUID) (*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionsCaseByID", ctx, id)
+	ret0, _ := ret[0].(*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionsCaseByID indicates an expected call of GetCollectionsCaseByID.
+func (mr *MockRepositoryMockRecorder) GetCollectionsCaseByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionsCaseByID", reflect.TypeOf((*MockRepository)(nil).GetCollectionsCaseByID), ctx, id)
+}
+
+// GetCreditLineByUserID mocks base method.
+func (m *MockRepository) GetCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreditLineByUserID", ctx, userID)
+	ret0, _ := ret[0].(*payments.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreditLineByUserID indicates an expected call of GetCreditLineByUserID.
+func (mr *MockRepositoryMockRecorder) GetCreditLineByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLineByUserID", reflect.TypeOf((*MockRepository)(nil).GetCreditLineByUserID), ctx, userID)
+}
+
+// GetLatestAuditEntryByPlanID mocks base method.
+func (m *MockRepository) GetLatestAuditEntryByPlanID(ctx context.Context, planID uuid.UUID) (*audit.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestAuditEntryByPlanID", ctx, planID)
+	ret0, _ := ret[0].(*audit.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestAuditEntryByPlanID indicates an expected call of GetLatestAuditEntryByPlanID.
+func (mr *MockRepositoryMockRecorder) GetLatestAuditEntryByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestAuditEntryByPlanID", reflect.TypeOf((*MockRepository)(nil).GetLatestAuditEntryByPlanID), ctx, planID)
+}
+
+// GetMerchantByID mocks base method.
+func (m *MockRepository) GetMerchantByID(ctx context.Context, id uuid.UUID) (*payments.Merchant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantByID", ctx, id)
+	ret0, _ := ret[0].(*payments.Merchant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantByID indicates an expected call of GetMerchantByID.
+func (mr *MockRepositoryMockRecorder) GetMerchantByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantByID", reflect.TypeOf((*MockRepository)(nil).GetMerchantByID), ctx, id)
+}
+
+// GetMerchantTotals mocks base method.
+func (m *MockRepository) GetMerchantTotals(ctx context.Context, arg *payments.MerchantTotalsParams) ([]*payments.MerchantTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantTotals", ctx, arg)
+	ret0, _ := ret[0].([]*payments.MerchantTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantTotals indicates an expected call of GetMerchantTotals.
+func (mr *MockRepositoryMockRecorder) GetMerchantTotals(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantTotals", reflect.TypeOf((*MockRepository)(nil).GetMerchantTotals), ctx, arg)
+}
+
+// GetPaymentPlanByID mocks base method.
+func (m *MockRepository) GetPaymentPlanByID(ctx context.Context, id uuid.UUID) (*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentPlanByID", ctx, id)
+	ret0, _ := ret[0].(*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentPlanByID indicates an expected call of GetPaymentPlanByID.
+func (mr *MockRepositoryMockRecorder) GetPaymentPlanByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanByID", reflect.TypeOf((*MockRepository)(nil).GetPaymentPlanByID), ctx, id)
+}
+
+// GetPaymentPlanByOrderReference mocks base method.
+func (m *MockRepository) GetPaymentPlanByOrderReference(ctx context.Context, arg *payments.GetPlanByOrderReferenceParams) (*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentPlanByOrderReference", ctx, arg)
+	ret0, _ := ret[0].(*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentPlanByOrderReference indicates an expected call of GetPaymentPlanByOrderReference.
+func (mr *MockRepositoryMockRecorder) GetPaymentPlanByOrderReference(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanByOrderReference", reflect.TypeOf((*MockRepository)(nil).GetPaymentPlanByOrderReference), ctx, arg)
+}
+
+// GetUserOutstandingAmount mocks base method.
+func (m *MockRepository) GetUserOutstandingAmount(ctx context.Context, arg *payments.UserOutstandingAmountParams) (payments.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOutstandingAmount", ctx, arg)
+	ret0, _ := ret[0].(payments.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOutstandingAmount indicates an expected call of GetUserOutstandingAmount.
+func (mr *MockRepositoryMockRecorder) GetUserOutstandingAmount(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOutstandingAmount", reflect.TypeOf((*MockRepository)(nil).GetUserOutstandingAmount), ctx, arg)
+}
+
+// GetWebhookEndpointByID mocks base method.
+func (m *MockRepository) GetWebhookEndpointByID(ctx context.Context, id uuid.UUID) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpointByID", ctx, id)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpointByID indicates an expected call of GetWebhookEndpointByID.
+func (mr *MockRepositoryMockRecorder) GetWebhookEndpointByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpointByID", reflect.TypeOf((*MockRepository)(nil).GetWebhookEndpointByID), ctx, id)
+}
+
+// ListAuditEntriesByPlanID mocks base method.
+func (m *MockRepository) ListAuditEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*audit.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEntriesByPlanID", ctx, planID)
+	ret0, _ := ret[0].([]*audit.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEntriesByPlanID indicates an expected call of ListAuditEntriesByPlanID.
+func (mr *MockRepositoryMockRecorder) ListAuditEntriesByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEntriesByPlanID", reflect.TypeOf((*MockRepository)(nil).ListAuditEntriesByPlanID), ctx, planID)
+}
+
+// ListCollectionsCaseEventsByCaseID mocks base method.
+func (m *MockRepository) ListCollectionsCaseEventsByCaseID(ctx context.Context, caseID uuid.UUID) ([]*collections.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionsCaseEventsByCaseID", ctx, caseID)
+	ret0, _ := ret[0].([]*collections.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionsCaseEventsByCaseID indicates an expected call of ListCollectionsCaseEventsByCaseID.
+func (mr *MockRepositoryMockRecorder) ListCollectionsCaseEventsByCaseID(ctx, caseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionsCaseEventsByCaseID", reflect.TypeOf((*MockRepository)(nil).ListCollectionsCaseEventsByCaseID), ctx, caseID)
+}
+
+// ListCollectionsCasesByStatus mocks base method.
+func (m *MockRepository) ListCollectionsCasesByStatus(ctx context.Context, status string) ([]*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionsCasesByStatus", ctx, status)
+	ret0, _ := ret[0].([]*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionsCasesByStatus indicates an expected call of ListCollectionsCasesByStatus.
+func (mr *MockRepositoryMockRecorder) ListCollectionsCasesByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionsCasesByStatus", reflect.TypeOf((*MockRepository)(nil).ListCollectionsCasesByStatus), ctx, status)
+}
+
+// ListCreditLineChangesByCreditLineID mocks base method.
+func (m *MockRepository) ListCreditLineChangesByCreditLineID(ctx context.Context, creditLineID uuid.UUID) ([]*payments.CreditLineChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditLineChangesByCreditLineID", ctx, creditLineID)
+	ret0, _ := ret[0].([]*payments.CreditLineChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditLineChangesByCreditLineID indicates an expected call of ListCreditLineChangesByCreditLineID.
+func (mr *MockRepositoryMockRecorder) ListCreditLineChangesByCreditLineID(ctx, creditLineID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditLineChangesByCreditLineID", reflect.TypeOf((*MockRepository)(nil).ListCreditLineChangesByCreditLineID), ctx, creditLineID)
+}
+
+// ListDelinquentPaymentPlans mocks base method.
+func (m *MockRepository) ListDelinquentPaymentPlans(ctx context.Context, limit int32) ([]*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelinquentPaymentPlans", ctx, limit)
 	ret0, _ := ret[0].([]*payments.Plan)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListPaymentPlansByUserID indicates an expected call of ListPaymentPlansByUserID.
-func (mr *MockRepositoryMockRecorder) ListPaymentPlansByUserID(ctx, userID interface{}) *gomock.Call {
+// ListDelinquentPaymentPlans indicates an expected call of ListDelinquentPaymentPlans.
+func (mr *MockRepositoryMockRecorder) ListDelinquentPaymentPlans(ctx, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentPlansByUserID", reflect.TypeOf((*MockRepository)(nil).ListPaymentPlansByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelinquentPaymentPlans", reflect.TypeOf((*MockRepository)(nil).ListDelinquentPaymentPlans), ctx, limit)
+}
+
+// ListDueCollectionsCases mocks base method.
+func (m *MockRepository) ListDueCollectionsCases(ctx context.Context, arg *collections.ListDueCasesParams) ([]*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueCollectionsCases", ctx, arg)
+	ret0, _ := ret[0].([]*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueCollectionsCases indicates an expected call of ListDueCollectionsCases.
+func (mr *MockRepositoryMockRecorder) ListDueCollectionsCases(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueCollectionsCases", reflect.TypeOf((*MockRepository)(nil).ListDueCollectionsCases), ctx, arg)
+}
+
+// ListJournalEntriesByPlanID mocks base method.
+func (m *MockRepository) ListJournalEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*ledger.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJournalEntriesByPlanID", ctx, planID)
+	ret0, _ := ret[0].([]*ledger.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJournalEntriesByPlanID indicates an expected call of ListJournalEntriesByPlanID.
+func (mr *MockRepositoryMockRecorder) ListJournalEntriesByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJournalEntriesByPlanID", reflect.TypeOf((*MockRepository)(nil).ListJournalEntriesByPlanID), ctx, planID)
+}
+
+// ListPaymentInstallmentsByPlanID mocks base method.
+func (m *MockRepository) ListPaymentInstallmentsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentInstallmentsByPlanID", ctx, planID)
+	ret0, _ := ret[0].([]*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentInstallmentsByPlanID indicates an expected call of ListPaymentInstallmentsByPlanID.
+func (mr *MockRepositoryMockRecorder) ListPaymentInstallmentsByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentInstallmentsByPlanID", reflect.TypeOf((*MockRepository)(nil).ListPaymentInstallmentsByPlanID), ctx, planID)
+}
+
+// ListPaymentInstallmentsByStatus mocks base method.
+func (m *MockRepository) ListPaymentInstallmentsByStatus(ctx context.Context, status string) ([]*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentInstallmentsByStatus", ctx, status)
+	ret0, _ := ret[0].([]*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentInstallmentsByStatus indicates an expected call of ListPaymentInstallmentsByStatus.
+func (mr *MockRepositoryMockRecorder) ListPaymentInstallmentsByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentInstallmentsByStatus", reflect.TypeOf((*MockRepository)(nil).ListPaymentInstallmentsByStatus), ctx, status)
+}
+
+// ListPaymentLateFeesByInstallmentID mocks base method.
+func (m *MockRepository) ListPaymentLateFeesByInstallmentID(ctx context.Context, installmentID uuid.UUID) ([]*payments.LateFee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentLateFeesByInstallmentID", ctx, installmentID)
+	ret0, _ := ret[0].([]*payments.LateFee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentLateFeesByInstallmentID indicates an expected call of ListPaymentLateFeesByInstallmentID.
+func (mr *MockRepositoryMockRecorder) ListPaymentLateFeesByInstallmentID(ctx, installmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentLateFeesByInstallmentID", reflect.TypeOf((*MockRepository)(nil).ListPaymentLateFeesByInstallmentID), ctx, installmentID)
+}
+
+// ListPaymentLateFeesByPlanID mocks base method.
+func (m *MockRepository) ListPaymentLateFeesByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.LateFee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentLateFeesByPlanID", ctx, planID)
+	ret0, _ := ret[0].([]*payments.LateFee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentLateFeesByPlanID indicates an expected call of ListPaymentLateFeesByPlanID.
+func (mr *MockRepositoryMockRecorder) ListPaymentLateFeesByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentLateFeesByPlanID", reflect.TypeOf((*MockRepository)(nil).ListPaymentLateFeesByPlanID), ctx, planID)
+}
+
+// ListPaymentPlanLineItemsByPlanID mocks base method.
+func (m *MockRepository) ListPaymentPlanLineItemsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.LineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentPlanLineItemsByPlanID", ctx, planID)
+	ret0, _ := ret[0].([]*payments.LineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentPlanLineItemsByPlanID indicates an expected call of ListPaymentPlanLineItemsByPlanID.
+func (mr *MockRepositoryMockRecorder) ListPaymentPlanLineItemsByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentPlanLineItemsByPlanID", reflect.TypeOf((*MockRepository)(nil).ListPaymentPlanLineItemsByPlanID), ctx, planID)
+}
+
+// ListPaymentPlansByMerchantID mocks base method.
+func (m *MockRepository) ListPaymentPlansByMerchantID(ctx context.Context, arg *payments.ListPlansByMerchantIDParams) ([]*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentPlansByMerchantID", ctx, arg)
+	ret0, _ := ret[0].([]*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentPlansByMerchantID indicates an expected call of ListPaymentPlansByMerchantID.
+func (mr *MockRepositoryMockRecorder) ListPaymentPlansByMerchantID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentPlansByMerchantID", reflect.TypeOf((*MockRepository)(nil).ListPaymentPlansByMerchantID), ctx, arg)
+}
+
+// ListPaymentPlansByStatusCreatedBefore mocks base method.
+func (m *MockRepository) ListPaymentPlansByStatusCreatedBefore(ctx context.Context, arg *payments.ListPlansByStatusCreatedBeforeParams) ([]*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentPlansByStatusCreatedBefore", ctx, arg)
+	ret0, _ := ret[0].([]*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentPlansByStatusCreatedBefore indicates an expected call of ListPaymentPlansByStatusCreatedBefore.
+func (mr *MockRepositoryMockRecorder) ListPaymentPlansByStatusCreatedBefore(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentPlansByStatusCreatedBefore", reflect.TypeOf((*MockRepository)(nil).ListPaymentPlansByStatusCreatedBefore), ctx, arg)
+}
+
+// ListPaymentPlansByUserID mocks base method.
+func (m *MockRepository) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentPlansByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentPlansByUserID indicates an expected call of ListPaymentPlansByUserID.
+func (mr *MockRepositoryMockRecorder) ListPaymentPlansByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentPlansByUserID", reflect.TypeOf((*MockRepository)(nil).ListPaymentPlansByUserID), ctx, userID)
+}
+
+// ListPaymentRefundsByPlanID mocks base method.
+func (m *MockRepository) ListPaymentRefundsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentRefundsByPlanID", ctx, planID)
+	ret0, _ := ret[0].([]*payments.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentRefundsByPlanID indicates an expected call of ListPaymentRefundsByPlanID.
+func (mr *MockRepositoryMockRecorder) ListPaymentRefundsByPlanID(ctx, planID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentRefundsByPlanID", reflect.TypeOf((*MockRepository)(nil).ListPaymentRefundsByPlanID), ctx, planID)
+}
+
+// ListPaymentTransactionsByInstallmentID mocks base method.
+func (m *MockRepository) ListPaymentTransactionsByInstallmentID(ctx context.Context, installmentID uuid.UUID) ([]*payments.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentTransactionsByInstallmentID", ctx, installmentID)
+	ret0, _ := ret[0].([]*payments.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentTransactionsByInstallmentID indicates an expected call of ListPaymentTransactionsByInstallmentID.
+func (mr *MockRepositoryMockRecorder) ListPaymentTransactionsByInstallmentID(ctx, installmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentTransactionsByInstallmentID", reflect.TypeOf((*MockRepository)(nil).ListPaymentTransactionsByInstallmentID), ctx, installmentID)
+}
+
+// ListSettledCollectionsCases mocks base method.
+func (m *MockRepository) ListSettledCollectionsCases(ctx context.Context, limit int32) ([]*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSettledCollectionsCases", ctx, limit)
+	ret0, _ := ret[0].([]*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSettledCollectionsCases indicates an expected call of ListSettledCollectionsCases.
+func (mr *MockRepositoryMockRecorder) ListSettledCollectionsCases(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSettledCollectionsCases", reflect.TypeOf((*MockRepository)(nil).ListSettledCollectionsCases), ctx, limit)
+}
+
+// ListUnpaidPaymentInstallmentsDueBetween mocks base method.
+func (m *MockRepository) ListUnpaidPaymentInstallmentsDueBetween(ctx context.Context, arg *payments.ListUnpaidInstallmentsDueBetweenParams) ([]*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpaidPaymentInstallmentsDueBetween", ctx, arg)
+	ret0, _ := ret[0].([]*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpaidPaymentInstallmentsDueBetween indicates an expected call of ListUnpaidPaymentInstallmentsDueBetween.
+func (mr *MockRepositoryMockRecorder) ListUnpaidPaymentInstallmentsDueBetween(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpaidPaymentInstallmentsDueBetween", reflect.TypeOf((*MockRepository)(nil).ListUnpaidPaymentInstallmentsDueBetween), ctx, arg)
+}
+
+// ListWebhookDeliveriesByStatus mocks base method.
+func (m *MockRepository) ListWebhookDeliveriesByStatus(ctx context.Context, status string) ([]*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveriesByStatus", ctx, status)
+	ret0, _ := ret[0].([]*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveriesByStatus indicates an expected call of ListWebhookDeliveriesByStatus.
+func (mr *MockRepositoryMockRecorder) ListWebhookDeliveriesByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveriesByStatus", reflect.TypeOf((*MockRepository)(nil).ListWebhookDeliveriesByStatus), ctx, status)
+}
+
+// ListWebhookEndpointsByMerchantID mocks base method.
+func (m *MockRepository) ListWebhookEndpointsByMerchantID(ctx context.Context, merchantID uuid.UUID) ([]*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpointsByMerchantID", ctx, merchantID)
+	ret0, _ := ret[0].([]*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpointsByMerchantID indicates an expected call of ListWebhookEndpointsByMerchantID.
+func (mr *MockRepositoryMockRecorder) ListWebhookEndpointsByMerchantID(ctx, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpointsByMerchantID", reflect.TypeOf((*MockRepository)(nil).ListWebhookEndpointsByMerchantID), ctx, merchantID)
+}
+
+// LockCollectionsCase mocks base method.
+func (m *MockRepository) LockCollectionsCase(ctx context.Context, id uuid.UUID) (*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockCollectionsCase", ctx, id)
+	ret0, _ := ret[0].(*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockCollectionsCase indicates an expected call of LockCollectionsCase.
+func (mr *MockRepositoryMockRecorder) LockCollectionsCase(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockCollectionsCase", reflect.TypeOf((*MockRepository)(nil).LockCollectionsCase), ctx, id)
+}
+
+// LockCreditLineByUserID mocks base method.
+func (m *MockRepository) LockCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockCreditLineByUserID", ctx, userID)
+	ret0, _ := ret[0].(*payments.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockCreditLineByUserID indicates an expected call of LockCreditLineByUserID.
+func (mr *MockRepositoryMockRecorder) LockCreditLineByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockCreditLineByUserID", reflect.TypeOf((*MockRepository)(nil).LockCreditLineByUserID), ctx, userID)
+}
+
+// LockDueWebhookDeliveries mocks base method.
+func (m *MockRepository) LockDueWebhookDeliveries(ctx context.Context, arg *webhook.ListDueDeliveriesParams) ([]*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDueWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockDueWebhookDeliveries indicates an expected call of LockDueWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) LockDueWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDueWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).LockDueWebhookDeliveries), ctx, arg)
+}
+
+// LockPaymentInstallment mocks base method.
+func (m *MockRepository) LockPaymentInstallment(ctx context.Context, id uuid.UUID) (*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPaymentInstallment", ctx, id)
+	ret0, _ := ret[0].(*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPaymentInstallment indicates an expected call of LockPaymentInstallment.
+func (mr *MockRepositoryMockRecorder) LockPaymentInstallment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPaymentInstallment", reflect.TypeOf((*MockRepository)(nil).LockPaymentInstallment), ctx, id)
+}
+
+// LockPaymentPlan mocks base method.
+func (m *MockRepository) LockPaymentPlan(ctx context.Context, id uuid.UUID) (*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPaymentPlan", ctx, id)
+	ret0, _ := ret[0].(*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPaymentPlan indicates an expected call of LockPaymentPlan.
+func (mr *MockRepositoryMockRecorder) LockPaymentPlan(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPaymentPlan", reflect.TypeOf((*MockRepository)(nil).LockPaymentPlan), ctx, id)
+}
+
+// LockPaymentPlansWithInstallmentsPastDue mocks base method.
+func (m *MockRepository) LockPaymentPlansWithInstallmentsPastDue(ctx context.Context, arg *payments.LockPlansWithInstallmentsPastDueParams) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPaymentPlansWithInstallmentsPastDue", ctx, arg)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPaymentPlansWithInstallmentsPastDue indicates an expected call of LockPaymentPlansWithInstallmentsPastDue.
+func (mr *MockRepositoryMockRecorder) LockPaymentPlansWithInstallmentsPastDue(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPaymentPlansWithInstallmentsPastDue", reflect.TypeOf((*MockRepository)(nil).LockPaymentPlansWithInstallmentsPastDue), ctx, arg)
+}
+
+// LockUnpublishedOutboxEvents mocks base method.
+func (m *MockRepository) LockUnpublishedOutboxEvents(ctx context.Context, limit int32) ([]*outbox.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUnpublishedOutboxEvents", ctx, limit)
+	ret0, _ := ret[0].([]*outbox.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockUnpublishedOutboxEvents indicates an expected call of LockUnpublishedOutboxEvents.
+func (mr *MockRepositoryMockRecorder) LockUnpublishedOutboxEvents(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUnpublishedOutboxEvents", reflect.TypeOf((*MockRepository)(nil).LockUnpublishedOutboxEvents), ctx, limit)
+}
+
+// LockWebhookDelivery mocks base method.
+func (m *MockRepository) LockWebhookDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockWebhookDelivery indicates an expected call of LockWebhookDelivery.
+func (mr *MockRepositoryMockRecorder) LockWebhookDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockWebhookDelivery", reflect.TypeOf((*MockRepository)(nil).LockWebhookDelivery), ctx, id)
+}
+
+// MarkOutboxEventFailed mocks base method.
+func (m *MockRepository) MarkOutboxEventFailed(ctx context.Context, arg *outbox.MarkEventFailedParams) (*outbox.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventFailed", ctx, arg)
+	ret0, _ := ret[0].(*outbox.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkOutboxEventFailed indicates an expected call of MarkOutboxEventFailed.
+func (mr *MockRepositoryMockRecorder) MarkOutboxEventFailed(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventFailed", reflect.TypeOf((*MockRepository)(nil).MarkOutboxEventFailed), ctx, arg)
+}
+
+// MarkOutboxEventPublished mocks base method.
+func (m *MockRepository) MarkOutboxEventPublished(ctx context.Context, arg *outbox.MarkEventPublishedParams) (*outbox.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventPublished", ctx, arg)
+	ret0, _ := ret[0].(*outbox.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkOutboxEventPublished indicates an expected call of MarkOutboxEventPublished.
+func (mr *MockRepositoryMockRecorder) MarkOutboxEventPublished(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventPublished", reflect.TypeOf((*MockRepository)(nil).MarkOutboxEventPublished), ctx, arg)
+}
+
+// UpdateCollectionsCase mocks base method.
+func (m *MockRepository) UpdateCollectionsCase(ctx context.Context, arg *collections.UpdateCaseParams) (*collections.Case, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollectionsCase", ctx, arg)
+	ret0, _ := ret[0].(*collections.Case)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollectionsCase indicates an expected call of UpdateCollectionsCase.
+func (mr *MockRepositoryMockRecorder) UpdateCollectionsCase(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollectionsCase", reflect.TypeOf((*MockRepository)(nil).UpdateCollectionsCase), ctx, arg)
+}
+
+// UpdateCreditLineLimit mocks base method.
+func (m *MockRepository) UpdateCreditLineLimit(ctx context.Context, arg *payments.UpdateCreditLineLimitParams) (*payments.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLineLimit", ctx, arg)
+	ret0, _ := ret[0].(*payments.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLineLimit indicates an expected call of UpdateCreditLineLimit.
+func (mr *MockRepositoryMockRecorder) UpdateCreditLineLimit(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLineLimit", reflect.TypeOf((*MockRepository)(nil).UpdateCreditLineLimit), ctx, arg)
+}
+
+// UpdateCreditLineStatus mocks base method.
+func (m *MockRepository) UpdateCreditLineStatus(ctx context.Context, arg *payments.UpdateCreditLineStatusParams) (*payments.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLineStatus", ctx, arg)
+	ret0, _ := ret[0].(*payments.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLineStatus indicates an expected call of UpdateCreditLineStatus.
+func (mr *MockRepositoryMockRecorder) UpdateCreditLineStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLineStatus", reflect.TypeOf((*MockRepository)(nil).UpdateCreditLineStatus), ctx, arg)
+}
+
+// UpdatePaymentInstallmentAmount mocks base method.
+func (m *MockRepository) UpdatePaymentInstallmentAmount(ctx context.Context, arg *payments.UpdateInstallmentAmountParams) (*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentInstallmentAmount", ctx, arg)
+	ret0, _ := ret[0].(*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentInstallmentAmount indicates an expected call of UpdatePaymentInstallmentAmount.
+func (mr *MockRepositoryMockRecorder) UpdatePaymentInstallmentAmount(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentInstallmentAmount", reflect.TypeOf((*MockRepository)(nil).UpdatePaymentInstallmentAmount), ctx, arg)
+}
+
+// UpdatePaymentInstallmentStatus mocks base method.
+func (m *MockRepository) UpdatePaymentInstallmentStatus(ctx context.Context, arg *payments.UpdateInstallmentStatusParams) (*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentInstallmentStatus", ctx, arg)
+	ret0, _ := ret[0].(*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentInstallmentStatus indicates an expected call of UpdatePaymentInstallmentStatus.
+func (mr *MockRepositoryMockRecorder) UpdatePaymentInstallmentStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentInstallmentStatus", reflect.TypeOf((*MockRepository)(nil).UpdatePaymentInstallmentStatus), ctx, arg)
+}
+
+// UpdatePaymentInstallmentsStatusDueBefore mocks base method.
+func (m *MockRepository) UpdatePaymentInstallmentsStatusDueBefore(ctx context.Context, arg *payments.UpdateInstallmentsStatusDueBeforeParams) ([]*payments.Installment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentInstallmentsStatusDueBefore", ctx, arg)
+	ret0, _ := ret[0].([]*payments.Installment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentInstallmentsStatusDueBefore indicates an expected call of UpdatePaymentInstallmentsStatusDueBefore.
+func (mr *MockRepositoryMockRecorder) UpdatePaymentInstallmentsStatusDueBefore(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentInstallmentsStatusDueBefore", reflect.TypeOf((*MockRepository)(nil).UpdatePaymentInstallmentsStatusDueBefore), ctx, arg)
+}
+
+// UpdatePaymentPlanStatus mocks base method.
+func (m *MockRepository) UpdatePaymentPlanStatus(ctx context.Context, arg *payments.UpdatePlanStatusParams) (*payments.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentPlanStatus", ctx, arg)
+	ret0, _ := ret[0].(*payments.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentPlanStatus indicates an expected call of UpdatePaymentPlanStatus.
+func (mr *MockRepositoryMockRecorder) UpdatePaymentPlanStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentPlanStatus", reflect.TypeOf((*MockRepository)(nil).UpdatePaymentPlanStatus), ctx, arg)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockRepository) UpdateWebhookDelivery(ctx context.Context, arg *webhook.UpdateDeliveryParams) (*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", ctx, arg)
+	ret0, _ := ret[0].(*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockRepositoryMockRecorder) UpdateWebhookDelivery(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockRepository)(nil).UpdateWebhookDelivery), ctx, arg)
+}
+
+// WithTx mocks base method.
+func (m *MockRepository) WithTx(ctx context.Context, fn func(repo.Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), ctx, fn)
 }
@@ -8,6 +8,7 @@ import (
 	context "context"
 	service "golangreferenceapi/internal/payments/service"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/gofrs/uuid"
 	gomock "github.com/golang/mock/gomock"
@@ -36,6 +37,36 @@ func (m *MockPaymentPlanService) EXPECT() *MockPaymentPlanServiceMockRecorder {
 	return m.recorder
 }
 
+// AssessLateFees mocks base method.
+func (m *MockPaymentPlanService) AssessLateFees(ctx context.Context, now time.Time) (*service.LateFeeRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssessLateFees", ctx, now)
+	ret0, _ := ret[0].(*service.LateFeeRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssessLateFees indicates an expected call of AssessLateFees.
+func (mr *MockPaymentPlanServiceMockRecorder) AssessLateFees(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssessLateFees", reflect.TypeOf((*MockPaymentPlanService)(nil).AssessLateFees), ctx, now)
+}
+
+// CancelPaymentPlan mocks base method.
+func (m *MockPaymentPlanService) CancelPaymentPlan(ctx context.Context, paymentPlanID uuid.UUID, paymentPlan *service.CancelPaymentPlanParams) (*service.PaymentPlans, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPaymentPlan", ctx, paymentPlanID, paymentPlan)
+	ret0, _ := ret[0].(*service.PaymentPlans)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPaymentPlan indicates an expected call of CancelPaymentPlan.
+func (mr *MockPaymentPlanServiceMockRecorder) CancelPaymentPlan(ctx, paymentPlanID, paymentPlan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPaymentPlan", reflect.TypeOf((*MockPaymentPlanService)(nil).CancelPaymentPlan), ctx, paymentPlanID, paymentPlan)
+}
+
 // CompletePaymentPlanCreation mocks base method.
 func (m *MockPaymentPlanService) CompletePaymentPlanCreation(ctx context.Context, paymentPlanID uuid.UUID, paymentPlan *service.CompletePaymentPlanParams) (*service.PaymentPlans, error) {
 	m.ctrl.T.Helper()
@@ -66,17 +97,612 @@ func (mr *MockPaymentPlanServiceMockRecorder) CreatePendingPaymentPlan(ctx, paym
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingPaymentPlan", reflect.TypeOf((*MockPaymentPlanService)(nil).CreatePendingPaymentPlan), ctx, paymentPlan)
 }
 
+// ExpirePendingPaymentPlans mocks base method.
+func (m *MockPaymentPlanService) ExpirePendingPaymentPlans(ctx context.Context, now time.Time, ttl time.Duration) ([]service.PaymentPlans, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePendingPaymentPlans", ctx, now, ttl)
+	ret0, _ := ret[0].([]service.PaymentPlans)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePendingPaymentPlans indicates an expected call of ExpirePendingPaymentPlans.
+func (mr *MockPaymentPlanServiceMockRecorder) ExpirePendingPaymentPlans(ctx, now, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePendingPaymentPlans", reflect.TypeOf((*MockPaymentPlanService)(nil).ExpirePendingPaymentPlans), ctx, now, ttl)
+}
+
+// GetPaymentPlanAudit mocks base method.
+func (m *MockPaymentPlanService) GetPaymentPlanAudit(ctx context.Context, paymentPlanID uuid.UUID) (*service.PaymentPlanAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentPlanAudit", ctx, paymentPlanID)
+	ret0, _ := ret[0].(*service.PaymentPlanAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentPlanAudit indicates an expected call of GetPaymentPlanAudit.
+func (mr *MockPaymentPlanServiceMockRecorder) GetPaymentPlanAudit(ctx, paymentPlanID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanAudit", reflect.TypeOf((*MockPaymentPlanService)(nil).GetPaymentPlanAudit), ctx, paymentPlanID)
+}
+
 // GetPaymentPlanByUserID mocks base method.
-func (m *MockPaymentPlanService) GetPaymentPlanByUserID(ctx context.Context, userID uuid.UUID) ([]service.PaymentPlans, error) {
+func (m *MockPaymentPlanService) GetPaymentPlanByUserID(ctx context.Context, userID uuid.UUID, withHistory bool) ([]service.PaymentPlans, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPaymentPlanByUserID", ctx, userID)
+	ret := m.ctrl.Call(m, "GetPaymentPlanByUserID", ctx, userID, withHistory)
 	ret0, _ := ret[0].([]service.PaymentPlans)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPaymentPlanByUserID indicates an expected call of GetPaymentPlanByUserID.
-func (mr *MockPaymentPlanServiceMockRecorder) GetPaymentPlanByUserID(ctx, userID interface{}) *gomock.Call {
+func (mr *MockPaymentPlanServiceMockRecorder) GetPaymentPlanByUserID(ctx, userID, withHistory interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanByUserID", reflect.TypeOf((*MockPaymentPlanService)(nil).GetPaymentPlanByUserID), ctx, userID, withHistory)
+}
+
+// GetPaymentPlanLedger mocks base method.
+func (m *MockPaymentPlanService) GetPaymentPlanLedger(ctx context.Context, paymentPlanID uuid.UUID) (*service.PaymentPlanLedger, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentPlanLedger", ctx, paymentPlanID)
+	ret0, _ := ret[0].(*service.PaymentPlanLedger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentPlanLedger indicates an expected call of GetPaymentPlanLedger.
+func (mr *MockPaymentPlanServiceMockRecorder) GetPaymentPlanLedger(ctx, paymentPlanID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanLedger", reflect.TypeOf((*MockPaymentPlanService)(nil).GetPaymentPlanLedger), ctx, paymentPlanID)
+}
+
+// PayOffPaymentPlan mocks base method.
+func (m *MockPaymentPlanService) PayOffPaymentPlan(ctx context.Context, paymentPlanID uuid.UUID, payoff *service.PaymentPlanPayoffParams) (*service.PaymentPlanPayoff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayOffPaymentPlan", ctx, paymentPlanID, payoff)
+	ret0, _ := ret[0].(*service.PaymentPlanPayoff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayOffPaymentPlan indicates an expected call of PayOffPaymentPlan.
+func (mr *MockPaymentPlanServiceMockRecorder) PayOffPaymentPlan(ctx, paymentPlanID, payoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayOffPaymentPlan", reflect.TypeOf((*MockPaymentPlanService)(nil).PayOffPaymentPlan), ctx, paymentPlanID, payoff)
+}
+
+// QuotePaymentPlanPayoff mocks base method.
+func (m *MockPaymentPlanService) QuotePaymentPlanPayoff(ctx context.Context, paymentPlanID, userID uuid.UUID) (*service.PaymentPlanPayoffQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuotePaymentPlanPayoff", ctx, paymentPlanID, userID)
+	ret0, _ := ret[0].(*service.PaymentPlanPayoffQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuotePaymentPlanPayoff indicates an expected call of QuotePaymentPlanPayoff.
+func (mr *MockPaymentPlanServiceMockRecorder) QuotePaymentPlanPayoff(ctx, paymentPlanID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuotePaymentPlanPayoff", reflect.TypeOf((*MockPaymentPlanService)(nil).QuotePaymentPlanPayoff), ctx, paymentPlanID, userID)
+}
+
+// RecordInstallmentPayment mocks base method.
+func (m *MockPaymentPlanService) RecordInstallmentPayment(ctx context.Context, paymentPlanID, installmentID uuid.UUID, payment *service.InstallmentPaymentParams) (*service.InstallmentPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordInstallmentPayment", ctx, paymentPlanID, installmentID, payment)
+	ret0, _ := ret[0].(*service.InstallmentPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordInstallmentPayment indicates an expected call of RecordInstallmentPayment.
+func (mr *MockPaymentPlanServiceMockRecorder) RecordInstallmentPayment(ctx, paymentPlanID, installmentID, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordInstallmentPayment", reflect.TypeOf((*MockPaymentPlanService)(nil).RecordInstallmentPayment), ctx, paymentPlanID, installmentID, payment)
+}
+
+// RefundPaymentPlan mocks base method.
+func (m *MockPaymentPlanService) RefundPaymentPlan(ctx context.Context, paymentPlanID uuid.UUID, refund *service.RefundPaymentPlanParams) (*service.PaymentPlanRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPaymentPlan", ctx, paymentPlanID, refund)
+	ret0, _ := ret[0].(*service.PaymentPlanRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundPaymentPlan indicates an expected call of RefundPaymentPlan.
+func (mr *MockPaymentPlanServiceMockRecorder) RefundPaymentPlan(ctx, paymentPlanID, refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPaymentPlan", reflect.TypeOf((*MockPaymentPlanService)(nil).RefundPaymentPlan), ctx, paymentPlanID, refund)
+}
+
+// RelayOutboxEvents mocks base method.
+func (m *MockPaymentPlanService) RelayOutboxEvents(ctx context.Context, now time.Time, limit int) (*service.OutboxRelay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayOutboxEvents", ctx, now, limit)
+	ret0, _ := ret[0].(*service.OutboxRelay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayOutboxEvents indicates an expected call of RelayOutboxEvents.
+func (mr *MockPaymentPlanServiceMockRecorder) RelayOutboxEvents(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayOutboxEvents", reflect.TypeOf((*MockPaymentPlanService)(nil).RelayOutboxEvents), ctx, now, limit)
+}
+
+// ReschedulePaymentPlan mocks base method.
+func (m *MockPaymentPlanService) ReschedulePaymentPlan(ctx context.Context, paymentPlanID uuid.UUID, reschedule *service.ReschedulePaymentPlanParams) (*service.PaymentPlans, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReschedulePaymentPlan", ctx, paymentPlanID, reschedule)
+	ret0, _ := ret[0].(*service.PaymentPlans)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReschedulePaymentPlan indicates an expected call of ReschedulePaymentPlan.
+func (mr *MockPaymentPlanServiceMockRecorder) ReschedulePaymentPlan(ctx, paymentPlanID, reschedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReschedulePaymentPlan", reflect.TypeOf((*MockPaymentPlanService)(nil).ReschedulePaymentPlan), ctx, paymentPlanID, reschedule)
+}
+
+// UpdateInstallmentsPastDue mocks base method.
+func (m *MockPaymentPlanService) UpdateInstallmentsPastDue(ctx context.Context, now time.Time, gracePeriod time.Duration) (*service.InstallmentsPastDue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstallmentsPastDue", ctx, now, gracePeriod)
+	ret0, _ := ret[0].(*service.InstallmentsPastDue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstallmentsPastDue indicates an expected call of UpdateInstallmentsPastDue.
+func (mr *MockPaymentPlanServiceMockRecorder) UpdateInstallmentsPastDue(ctx, now, gracePeriod interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstallmentsPastDue", reflect.TypeOf((*MockPaymentPlanService)(nil).UpdateInstallmentsPastDue), ctx, now, gracePeriod)
+}
+
+// MockCreditLineService is a mock of CreditLineService interface.
+type MockCreditLineService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreditLineServiceMockRecorder
+}
+
+// MockCreditLineServiceMockRecorder is the mock recorder for MockCreditLineService.
+type MockCreditLineServiceMockRecorder struct {
+	mock *MockCreditLineService
+}
+
+// NewMockCreditLineService creates a new mock instance.
+func NewMockCreditLineService(ctrl *gomock.Controller) *MockCreditLineService {
+	mock := &MockCreditLineService{ctrl: ctrl}
+	mock.recorder = &MockCreditLineServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreditLineService) EXPECT() *MockCreditLineServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseCreditLine mocks base method.
+func (m *MockCreditLineService) CloseCreditLine(ctx context.Context, userID uuid.UUID, change *service.CreditLineChangeParams) (*service.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseCreditLine", ctx, userID, change)
+	ret0, _ := ret[0].(*service.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseCreditLine indicates an expected call of CloseCreditLine.
+func (mr *MockCreditLineServiceMockRecorder) CloseCreditLine(ctx, userID, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseCreditLine", reflect.TypeOf((*MockCreditLineService)(nil).CloseCreditLine), ctx, userID, change)
+}
+
+// CreateCreditLine mocks base method.
+func (m *MockCreditLineService) CreateCreditLine(ctx context.Context, userID uuid.UUID, creditLine *service.CreateCreditLineParams) (*service.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCreditLine", ctx, userID, creditLine)
+	ret0, _ := ret[0].(*service.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCreditLine indicates an expected call of CreateCreditLine.
+func (mr *MockCreditLineServiceMockRecorder) CreateCreditLine(ctx, userID, creditLine interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCreditLine", reflect.TypeOf((*MockCreditLineService)(nil).CreateCreditLine), ctx, userID, creditLine)
+}
+
+// FreezeCreditLine mocks base method.
+func (m *MockCreditLineService) FreezeCreditLine(ctx context.Context, userID uuid.UUID, change *service.CreditLineChangeParams) (*service.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeCreditLine", ctx, userID, change)
+	ret0, _ := ret[0].(*service.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeCreditLine indicates an expected call of FreezeCreditLine.
+func (mr *MockCreditLineServiceMockRecorder) FreezeCreditLine(ctx, userID, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeCreditLine", reflect.TypeOf((*MockCreditLineService)(nil).FreezeCreditLine), ctx, userID, change)
+}
+
+// GetCreditLine mocks base method.
+func (m *MockCreditLineService) GetCreditLine(ctx context.Context, userID uuid.UUID) (*service.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCreditLine", ctx, userID)
+	ret0, _ := ret[0].(*service.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCreditLine indicates an expected call of GetCreditLine.
+func (mr *MockCreditLineServiceMockRecorder) GetCreditLine(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCreditLine", reflect.TypeOf((*MockCreditLineService)(nil).GetCreditLine), ctx, userID)
+}
+
+// ListCreditLineChanges mocks base method.
+func (m *MockCreditLineService) ListCreditLineChanges(ctx context.Context, userID uuid.UUID) ([]service.CreditLineChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditLineChanges", ctx, userID)
+	ret0, _ := ret[0].([]service.CreditLineChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditLineChanges indicates an expected call of ListCreditLineChanges.
+func (mr *MockCreditLineServiceMockRecorder) ListCreditLineChanges(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditLineChanges", reflect.TypeOf((*MockCreditLineService)(nil).ListCreditLineChanges), ctx, userID)
+}
+
+// UnfreezeCreditLine mocks base method.
+func (m *MockCreditLineService) UnfreezeCreditLine(ctx context.Context, userID uuid.UUID, change *service.CreditLineChangeParams) (*service.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeCreditLine", ctx, userID, change)
+	ret0, _ := ret[0].(*service.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfreezeCreditLine indicates an expected call of UnfreezeCreditLine.
+func (mr *MockCreditLineServiceMockRecorder) UnfreezeCreditLine(ctx, userID, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeCreditLine", reflect.TypeOf((*MockCreditLineService)(nil).UnfreezeCreditLine), ctx, userID, change)
+}
+
+// UpdateCreditLineLimit mocks base method.
+func (m *MockCreditLineService) UpdateCreditLineLimit(ctx context.Context, userID uuid.UUID, limit *service.UpdateCreditLineLimitParams) (*service.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLineLimit", ctx, userID, limit)
+	ret0, _ := ret[0].(*service.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLineLimit indicates an expected call of UpdateCreditLineLimit.
+func (mr *MockCreditLineServiceMockRecorder) UpdateCreditLineLimit(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLineLimit", reflect.TypeOf((*MockCreditLineService)(nil).UpdateCreditLineLimit), ctx, userID, limit)
+}
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// DeliverWebhooks mocks base method.
+func (m *MockWebhookService) DeliverWebhooks(ctx context.Context, now time.Time, limit int) (*service.WebhookDeliveryRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverWebhooks", ctx, now, limit)
+	ret0, _ := ret[0].(*service.WebhookDeliveryRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverWebhooks indicates an expected call of DeliverWebhooks.
+func (mr *MockWebhookServiceMockRecorder) DeliverWebhooks(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverWebhooks", reflect.TypeOf((*MockWebhookService)(nil).DeliverWebhooks), ctx, now, limit)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookService) ListWebhookDeliveries(ctx context.Context, status string) ([]service.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, status)
+	ret0, _ := ret[0].([]service.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookServiceMockRecorder) ListWebhookDeliveries(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookService)(nil).ListWebhookDeliveries), ctx, status)
+}
+
+// RegisterWebhookEndpoint mocks base method.
+func (m *MockWebhookService) RegisterWebhookEndpoint(ctx context.Context, merchantID uuid.UUID, endpoint *service.RegisterWebhookEndpointParams) (*service.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWebhookEndpoint", ctx, merchantID, endpoint)
+	ret0, _ := ret[0].(*service.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterWebhookEndpoint indicates an expected call of RegisterWebhookEndpoint.
+func (mr *MockWebhookServiceMockRecorder) RegisterWebhookEndpoint(ctx, merchantID, endpoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWebhookEndpoint", reflect.TypeOf((*MockWebhookService)(nil).RegisterWebhookEndpoint), ctx, merchantID, endpoint)
+}
+
+// ReplayWebhookDelivery mocks base method.
+func (m *MockWebhookService) ReplayWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, now time.Time) (*service.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDelivery", ctx, deliveryID, now)
+	ret0, _ := ret[0].(*service.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayWebhookDelivery indicates an expected call of ReplayWebhookDelivery.
+func (mr *MockWebhookServiceMockRecorder) ReplayWebhookDelivery(ctx, deliveryID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDelivery", reflect.TypeOf((*MockWebhookService)(nil).ReplayWebhookDelivery), ctx, deliveryID, now)
+}
+
+// MockReminderService is a mock of ReminderService interface.
+type MockReminderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReminderServiceMockRecorder
+}
+
+// MockReminderServiceMockRecorder is the mock recorder for MockReminderService.
+type MockReminderServiceMockRecorder struct {
+	mock *MockReminderService
+}
+
+// NewMockReminderService creates a new mock instance.
+func NewMockReminderService(ctrl *gomock.Controller) *MockReminderService {
+	mock := &MockReminderService{ctrl: ctrl}
+	mock.recorder = &MockReminderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReminderService) EXPECT() *MockReminderServiceMockRecorder {
+	return m.recorder
+}
+
+// SendInstallmentReminders mocks base method.
+func (m *MockReminderService) SendInstallmentReminders(ctx context.Context, now time.Time) (*service.ReminderRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendInstallmentReminders", ctx, now)
+	ret0, _ := ret[0].(*service.ReminderRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendInstallmentReminders indicates an expected call of SendInstallmentReminders.
+func (mr *MockReminderServiceMockRecorder) SendInstallmentReminders(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendInstallmentReminders", reflect.TypeOf((*MockReminderService)(nil).SendInstallmentReminders), ctx, now)
+}
+
+// MockCollectionsService is a mock of CollectionsService interface.
+type MockCollectionsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionsServiceMockRecorder
+}
+
+// MockCollectionsServiceMockRecorder is the mock recorder for MockCollectionsService.
+type MockCollectionsServiceMockRecorder struct {
+	mock *MockCollectionsService
+}
+
+// NewMockCollectionsService creates a new mock instance.
+func NewMockCollectionsService(ctrl *gomock.Controller) *MockCollectionsService {
+	mock := &MockCollectionsService{ctrl: ctrl}
+	mock.recorder = &MockCollectionsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionsService) EXPECT() *MockCollectionsServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseCollectionsCase mocks base method.
+func (m *MockCollectionsService) CloseCollectionsCase(ctx context.Context, caseID uuid.UUID, now time.Time, change *service.CollectionsCaseChangeParams) (*service.CollectionsCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseCollectionsCase", ctx, caseID, now, change)
+	ret0, _ := ret[0].(*service.CollectionsCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseCollectionsCase indicates an expected call of CloseCollectionsCase.
+func (mr *MockCollectionsServiceMockRecorder) CloseCollectionsCase(ctx, caseID, now, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseCollectionsCase", reflect.TypeOf((*MockCollectionsService)(nil).CloseCollectionsCase), ctx, caseID, now, change)
+}
+
+// GetCollectionsCase mocks base method.
+func (m *MockCollectionsService) GetCollectionsCase(ctx context.Context, caseID uuid.UUID) (*service.CollectionsCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionsCase", ctx, caseID)
+	ret0, _ := ret[0].(*service.CollectionsCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionsCase indicates an expected call of GetCollectionsCase.
+func (mr *MockCollectionsServiceMockRecorder) GetCollectionsCase(ctx, caseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionsCase", reflect.TypeOf((*MockCollectionsService)(nil).GetCollectionsCase), ctx, caseID)
+}
+
+// ListCollectionsCases mocks base method.
+func (m *MockCollectionsService) ListCollectionsCases(ctx context.Context, status string) ([]service.CollectionsCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollectionsCases", ctx, status)
+	ret0, _ := ret[0].([]service.CollectionsCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollectionsCases indicates an expected call of ListCollectionsCases.
+func (mr *MockCollectionsServiceMockRecorder) ListCollectionsCases(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollectionsCases", reflect.TypeOf((*MockCollectionsService)(nil).ListCollectionsCases), ctx, status)
+}
+
+// PauseCollectionsCase mocks base method.
+func (m *MockCollectionsService) PauseCollectionsCase(ctx context.Context, caseID uuid.UUID, change *service.CollectionsCaseChangeParams) (*service.CollectionsCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseCollectionsCase", ctx, caseID, change)
+	ret0, _ := ret[0].(*service.CollectionsCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseCollectionsCase indicates an expected call of PauseCollectionsCase.
+func (mr *MockCollectionsServiceMockRecorder) PauseCollectionsCase(ctx, caseID, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseCollectionsCase", reflect.TypeOf((*MockCollectionsService)(nil).PauseCollectionsCase), ctx, caseID, change)
+}
+
+// ResumeCollectionsCase mocks base method.
+func (m *MockCollectionsService) ResumeCollectionsCase(ctx context.Context, caseID uuid.UUID, now time.Time, change *service.CollectionsCaseChangeParams) (*service.CollectionsCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeCollectionsCase", ctx, caseID, now, change)
+	ret0, _ := ret[0].(*service.CollectionsCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeCollectionsCase indicates an expected call of ResumeCollectionsCase.
+func (mr *MockCollectionsServiceMockRecorder) ResumeCollectionsCase(ctx, caseID, now, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeCollectionsCase", reflect.TypeOf((*MockCollectionsService)(nil).ResumeCollectionsCase), ctx, caseID, now, change)
+}
+
+// RunCollections mocks base method.
+func (m *MockCollectionsService) RunCollections(ctx context.Context, now time.Time, limit int) (*service.CollectionsRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunCollections", ctx, now, limit)
+	ret0, _ := ret[0].(*service.CollectionsRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunCollections indicates an expected call of RunCollections.
+func (mr *MockCollectionsServiceMockRecorder) RunCollections(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCollections", reflect.TypeOf((*MockCollectionsService)(nil).RunCollections), ctx, now, limit)
+}
+
+// MockMerchantService is a mock of MerchantService interface.
+type MockMerchantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchantServiceMockRecorder
+}
+
+// MockMerchantServiceMockRecorder is the mock recorder for MockMerchantService.
+type MockMerchantServiceMockRecorder struct {
+	mock *MockMerchantService
+}
+
+// NewMockMerchantService creates a new mock instance.
+func NewMockMerchantService(ctrl *gomock.Controller) *MockMerchantService {
+	mock := &MockMerchantService{ctrl: ctrl}
+	mock.recorder = &MockMerchantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchantService) EXPECT() *MockMerchantServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateMerchant mocks base method.
+func (m *MockMerchantService) CreateMerchant(ctx context.Context, merchant *service.CreateMerchantParams) (*service.Merchant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMerchant", ctx, merchant)
+	ret0, _ := ret[0].(*service.Merchant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMerchant indicates an expected call of CreateMerchant.
+func (mr *MockMerchantServiceMockRecorder) CreateMerchant(ctx, merchant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMerchant", reflect.TypeOf((*MockMerchantService)(nil).CreateMerchant), ctx, merchant)
+}
+
+// GetMerchantTotals mocks base method.
+func (m *MockMerchantService) GetMerchantTotals(ctx context.Context, merchantID uuid.UUID, from, to time.Time) (*service.MerchantTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchantTotals", ctx, merchantID, from, to)
+	ret0, _ := ret[0].(*service.MerchantTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchantTotals indicates an expected call of GetMerchantTotals.
+func (mr *MockMerchantServiceMockRecorder) GetMerchantTotals(ctx, merchantID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchantTotals", reflect.TypeOf((*MockMerchantService)(nil).GetMerchantTotals), ctx, merchantID, from, to)
+}
+
+// GetPaymentPlanByOrderReference mocks base method.
+func (m *MockMerchantService) GetPaymentPlanByOrderReference(ctx context.Context, merchantID uuid.UUID, orderReference string) (*service.PaymentPlans, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentPlanByOrderReference", ctx, merchantID, orderReference)
+	ret0, _ := ret[0].(*service.PaymentPlans)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentPlanByOrderReference indicates an expected call of GetPaymentPlanByOrderReference.
+func (mr *MockMerchantServiceMockRecorder) GetPaymentPlanByOrderReference(ctx, merchantID, orderReference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanByOrderReference", reflect.TypeOf((*MockMerchantService)(nil).GetPaymentPlanByOrderReference), ctx, merchantID, orderReference)
+}
+
+// ListMerchantPaymentPlans mocks base method.
+func (m *MockMerchantService) ListMerchantPaymentPlans(ctx context.Context, merchantID uuid.UUID, page *service.ListMerchantPaymentPlansParams) ([]service.PaymentPlans, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMerchantPaymentPlans", ctx, merchantID, page)
+	ret0, _ := ret[0].([]service.PaymentPlans)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMerchantPaymentPlans indicates an expected call of ListMerchantPaymentPlans.
+func (mr *MockMerchantServiceMockRecorder) ListMerchantPaymentPlans(ctx, merchantID, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentPlanByUserID", reflect.TypeOf((*MockPaymentPlanService)(nil).GetPaymentPlanByUserID), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMerchantPaymentPlans", reflect.TypeOf((*MockMerchantService)(nil).ListMerchantPaymentPlans), ctx, merchantID, page)
 }
@@ -0,0 +1,223 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golangreferenceapi/internal/payments/currency"
+
+	"github.com/ericlagergren/decimal"
+)
+
+var (
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrCurrencyMismatch    = errors.New("currency mismatch")
+	ErrInvalidSplit        = errors.New("invalid split")
+)
+
+// Money is an amount in a supported currency, amounts in different currencies cannot be combined.
+// A Money is immutable, every operation returns a new one. The zero Money has no currency,
+// it is what a missing amount is decoded to.
+type Money struct {
+	amount decimal.Big
+	cur    currency.Currency
+}
+
+// NewMoney copies amount, it has to be finite
+func NewMoney(amount *decimal.Big, code string) (Money, error) {
+	cur, ok := currency.Lookup(code)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrUnsupportedCurrency, code)
+	}
+
+	if !amount.IsFinite() {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, amount.String())
+	}
+
+	return newMoney(amount, cur), nil
+}
+
+// ParseMoney parses a decimal amount like "10.25"
+func ParseMoney(value, code string) (Money, error) {
+	amount, ok := new(decimal.Big).SetString(value)
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, value)
+	}
+
+	return NewMoney(amount, code)
+}
+
+// ZeroMoney is nothing in the currency of code
+func ZeroMoney(code string) (Money, error) {
+	return NewMoney(new(decimal.Big), code)
+}
+
+// newMoney the context of amount is dropped so equal amounts compare equal with reflect.DeepEqual
+func newMoney(amount *decimal.Big, cur currency.Currency) Money {
+	m := Money{cur: cur}
+	m.amount.Copy(amount)
+
+	return m
+}
+
+// Amount returns a copy of the amount
+func (m Money) Amount() *decimal.Big {
+	return new(decimal.Big).Copy(&m.amount)
+}
+
+// Currency returns the currency code
+func (m Money) Currency() string {
+	return m.cur.Code
+}
+
+// MinorUnits is the number of decimal places of the currency
+func (m Money) MinorUnits() int {
+	return m.cur.MinorUnits
+}
+
+// String returns the amount in plain notation, without the currency
+func (m Money) String() string {
+	return fmt.Sprintf("%f", &m.amount)
+}
+
+func (m Money) Sign() int {
+	return m.amount.Sign()
+}
+
+func (m Money) IsZero() bool {
+	return m.amount.Sign() == 0
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+
+	// decimal(32, 16) needs more than the default 16 digits of precision
+	sum := &decimal.Big{Context: decimal.Context128}
+	sum.Add(&m.amount, &o.amount)
+
+	return newMoney(sum, m.cur), nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+
+	diff := &decimal.Big{Context: decimal.Context128}
+	diff.Sub(&m.amount, &o.amount)
+
+	return newMoney(diff, m.cur), nil
+}
+
+// Mul returns m times factor without rounding it, see Round
+func (m Money) Mul(factor *decimal.Big) Money {
+	product := &decimal.Big{Context: decimal.Context128}
+	product.Mul(&m.amount, factor)
+
+	return newMoney(product, m.cur)
+}
+
+// Cmp compares the amounts like decimal.Big.Cmp
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return 0, err
+	}
+
+	return m.amount.Cmp(&o.amount), nil
+}
+
+// Equal reports whether m and o are the same amount in the same currency, whatever their scale
+func (m Money) Equal(o Money) bool {
+	return m.cur.Code == o.cur.Code && m.amount.Cmp(&o.amount) == 0
+}
+
+// IsRounded reports whether m has no more decimal places than its currency allows
+func (m Money) IsRounded() bool {
+	return m.cur.IsRounded(&m.amount)
+}
+
+// Round rounds m down to the minor unit of its currency
+func (m Money) Round() Money {
+	rounded := &decimal.Big{Context: decimal.Context128}
+	rounded.Copy(&m.amount)
+
+	return newMoney(m.cur.Round(rounded), m.cur)
+}
+
+// Split divides m in n parts rounded down to the minor unit of its currency,
+// the rounding remainder is added to the part at remainderIdx
+func (m Money) Split(n, remainderIdx int) ([]Money, error) {
+	if n < 1 || remainderIdx < 0 || remainderIdx >= n {
+		return nil, fmt.Errorf("%w: part %d of %d", ErrInvalidSplit, remainderIdx, n)
+	}
+
+	count := decimal.New(int64(n), 0)
+
+	base := &decimal.Big{Context: decimal.Context128}
+	base.Context.RoundingMode = decimal.ToZero
+	m.cur.Round(base.Quo(&m.amount, count))
+
+	remainder := &decimal.Big{Context: decimal.Context128}
+	remainder.Mul(base, count)
+	remainder.Sub(&m.amount, remainder)
+	remainder.Add(remainder, base)
+
+	parts := make([]Money, n)
+
+	for idx := range parts {
+		parts[idx] = newMoney(base, m.cur)
+	}
+
+	parts[remainderIdx] = newMoney(remainder, m.cur)
+
+	return parts, nil
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if m.cur.Code != o.cur.Code {
+		return fmt.Errorf("%w: %q and %q", ErrCurrencyMismatch, m.cur.Code, o.cur.Code)
+	}
+
+	return nil
+}
+
+// moneyJSON the amount is a string so no precision is lost by JSON clients parsing numbers as floats
+type moneyJSON struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Value: m.String(), Currency: m.cur.Code})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(raw.Value, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// MustNewMoney is NewMoney for amounts known to be valid, it panics otherwise
+func MustNewMoney(amount *decimal.Big, code string) Money {
+	m, err := NewMoney(amount, code)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
@@ -0,0 +1,347 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		code    string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "decimal amount",
+			value: "10.25",
+			code:  "usd",
+			want:  "10.25",
+		},
+		{
+			name:  "small amounts are not written with an exponent",
+			value: "0.0000000000000002",
+			code:  "usdc",
+			want:  "0.0000000000000002",
+		},
+		{
+			name:    "unsupported currency",
+			value:   "10",
+			code:    "btc",
+			wantErr: ErrUnsupportedCurrency,
+		},
+		{
+			name:    "not a number",
+			value:   "ten",
+			code:    "usd",
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name:    "infinite",
+			value:   "Inf",
+			code:    "usd",
+			wantErr: ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseMoney(tt.value, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.String() != tt.want || got.Currency() != tt.code {
+				t.Errorf("ParseMoney() = %v %v, want %v %v", got, got.Currency(), tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ten   = MustNewMoney(decimal.New(10, 0), "usdc")
+		cents = MustNewMoney(decimal.New(25, 2), "usdc")
+		euros = MustNewMoney(decimal.New(10, 0), "eur")
+	)
+
+	sum, err := ten.Add(cents)
+	if err != nil || sum.String() != "10.25" {
+		t.Errorf("Add() = %v, %v, want 10.25", sum, err)
+	}
+
+	diff, err := cents.Sub(ten)
+	if err != nil || diff.String() != "-9.75" {
+		t.Errorf("Sub() = %v, %v, want -9.75", diff, err)
+	}
+
+	if cmp, err := cents.Cmp(ten); err != nil || cmp >= 0 {
+		t.Errorf("Cmp() = %v, %v, want -1", cmp, err)
+	}
+
+	if product := ten.Mul(decimal.New(15, 3)); product.String() != "0.150" {
+		t.Errorf("Mul() = %v, want 0.150", product)
+	}
+
+	if _, err := ten.Add(euros); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+
+	if _, err := ten.Sub(euros); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+
+	if _, err := ten.Cmp(euros); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestMoney_Equal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		m    Money
+		o    Money
+		want bool
+	}{
+		{
+			name: "trailing zeros do not matter",
+			m:    MustNewMoney(decimal.New(10, 0), "usd"),
+			o:    MustNewMoney(decimal.New(1000, 2), "usd"),
+			want: true,
+		},
+		{
+			name: "different amounts",
+			m:    MustNewMoney(decimal.New(10, 0), "usd"),
+			o:    MustNewMoney(decimal.New(1001, 2), "usd"),
+		},
+		{
+			name: "different currencies",
+			m:    MustNewMoney(decimal.New(10, 0), "usd"),
+			o:    MustNewMoney(decimal.New(10, 0), "sgd"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.m.Equal(tt.o); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Round(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		amount      *decimal.Big
+		code        string
+		want        string
+		wantRounded bool
+	}{
+		{
+			name:        "already rounded",
+			amount:      decimal.New(1025, 2),
+			code:        "usd",
+			want:        "10.25",
+			wantRounded: true,
+		},
+		{
+			name:   "rounded down to the minor unit",
+			amount: decimal.New(10259, 3),
+			code:   "usd",
+			want:   "10.25",
+		},
+		{
+			name:   "six decimal places for stablecoins",
+			amount: decimal.New(12345678, 7),
+			code:   "usdc",
+			want:   "1.234567",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := MustNewMoney(tt.amount, tt.code)
+
+			if got := m.IsRounded(); got != tt.wantRounded {
+				t.Errorf("IsRounded() = %v, want %v", got, tt.wantRounded)
+			}
+
+			if got := m.Round(); got.String() != tt.want || !got.IsRounded() {
+				t.Errorf("Round() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Split(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		amount       *decimal.Big
+		code         string
+		n            int
+		remainderIdx int
+		want         []string
+		wantErr      error
+	}{
+		{
+			name:   "remainder on the first part",
+			amount: decimal.New(100, 0),
+			code:   "usd",
+			n:      3,
+			want:   []string{"33.34", "33.33", "33.33"},
+		},
+		{
+			name:         "remainder on the last part",
+			amount:       decimal.New(100, 0),
+			code:         "usd",
+			n:            3,
+			remainderIdx: 2,
+			want:         []string{"33.33", "33.33", "33.34"},
+		},
+		{
+			name:   "six decimal places for stablecoins",
+			amount: decimal.New(1, 0),
+			code:   "usdc",
+			n:      3,
+			want:   []string{"0.333334", "0.333333", "0.333333"},
+		},
+		{
+			name:    "no parts",
+			amount:  decimal.New(100, 0),
+			code:    "usd",
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:         "remainder out of the parts",
+			amount:       decimal.New(100, 0),
+			code:         "usd",
+			n:            3,
+			remainderIdx: 3,
+			wantErr:      ErrInvalidSplit,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MustNewMoney(tt.amount, tt.code).Split(tt.n, tt.remainderIdx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Split() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			gotAmounts := make([]string, 0, len(got))
+
+			for _, part := range got {
+				if part.Currency() != tt.code {
+					t.Errorf("unexpected currency %v", part.Currency())
+				}
+
+				gotAmounts = append(gotAmounts, part.String())
+			}
+
+			if !reflect.DeepEqual(gotAmounts, tt.want) {
+				t.Errorf("Split() = %v, want %v", gotAmounts, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Parallel()
+
+	m := MustNewMoney(decimal.New(1025, 2), "usd")
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if want := `{"value":"10.25","currency":"usd"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var got Money
+
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Unmarshal() = %v, want %v", got, m)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name:    "unsupported currency",
+			data:    `{"value":"10","currency":"btc"}`,
+			wantErr: ErrUnsupportedCurrency,
+		},
+		{
+			name:    "not a number",
+			data:    `{"value":"ten","currency":"usd"}`,
+			wantErr: ErrInvalidAmount,
+		},
+		{
+			name:    "missing value",
+			data:    `{"currency":"usd"}`,
+			wantErr: ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got Money
+
+			if err := json.Unmarshal([]byte(tt.data), &got); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package outbox holds the events of the payment domain. An event is written in the unit of work of the change
+// it announces and published afterwards by a relay, at least once and in order for each payment plan.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/gofrs/uuid"
+)
+
+// the types of events, the outbox_event_type database enum
+const (
+	EventPlanCreated        = "plan_created"
+	EventPlanCompleted      = "plan_completed"
+	EventInstallmentPaid    = "installment_paid"
+	EventInstallmentOverdue = "installment_overdue"
+	EventPlanPaidOff        = "plan_paid_off"
+	EventPlanRefunded       = "plan_refunded"
+)
+
+// EventPublisher delivers the events to the downstream consumers, an event which failed
+// to publish is published again later so consumers must tolerate duplicates
+type EventPublisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Event PublishedAt is nil until a relay has published it
+type Event struct {
+	ID            uuid.UUID       `json:"id"`
+	PaymentPlanID uuid.UUID       `json:"payment_plan_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+}
+
+type CreateEventParams struct {
+	PaymentPlanID uuid.UUID
+	Type          string
+	Payload       json.RawMessage
+}
+
+type MarkEventPublishedParams struct {
+	ID          uuid.UUID
+	PublishedAt time.Time
+}
+
+type MarkEventFailedParams struct {
+	ID       uuid.UUID
+	FailedAt time.Time
+}
+
+// PlanPayload is the payload of the plan events, the plan as the change left it
+type PlanPayload struct {
+	PaymentPlanID uuid.UUID      `json:"payment_plan_id"`
+	UserID        uuid.UUID      `json:"user_id"`
+	Amount        payments.Money `json:"amount"`
+	Status        string         `json:"status"`
+}
+
+// InstallmentPayload is the payload of the installment events, the installment as the change left it
+type InstallmentPayload struct {
+	PaymentPlanID uuid.UUID      `json:"payment_plan_id"`
+	InstallmentID uuid.UUID      `json:"installment_id"`
+	Amount        payments.Money `json:"amount"`
+	DueAt         time.Time      `json:"due_at"`
+	Status        string         `json:"status"`
+}
+
+func NewPlanEvent(eventType string, plan *payments.Plan) (*CreateEventParams, error) {
+	return newEvent(plan.ID, eventType, PlanPayload{
+		PaymentPlanID: plan.ID,
+		UserID:        plan.UserID,
+		Amount:        plan.Amount,
+		Status:        plan.Status,
+	})
+}
+
+func NewInstallmentEvent(eventType string, inst *payments.Installment) (*CreateEventParams, error) {
+	return newEvent(inst.PaymentPlanID, eventType, InstallmentPayload{
+		PaymentPlanID: inst.PaymentPlanID,
+		InstallmentID: inst.ID,
+		Amount:        inst.Amount,
+		DueAt:         inst.DueAt,
+		Status:        inst.Status,
+	})
+}
+
+func newEvent(paymentPlanID uuid.UUID, eventType string, payload interface{}) (*CreateEventParams, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateEventParams{PaymentPlanID: paymentPlanID, Type: eventType, Payload: encoded}, nil
+}
@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+)
+
+func TestNewPlanEvent(t *testing.T) {
+	t.Parallel()
+
+	plan := &payments.Plan{
+		ID:     uuid.FromStringOrNil("6b9b3c4c-5d2e-4f0a-9d5b-1c2d3e4f5a6b"),
+		UserID: uuid.FromStringOrNil("0e7e4b3a-1f2d-4c5b-8a9e-0f1e2d3c4b5a"),
+		Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+		Status: "pending",
+	}
+
+	got, err := NewPlanEvent(EventPlanCreated, plan)
+	if err != nil {
+		t.Fatalf("NewPlanEvent() error = %v", err)
+	}
+
+	if got.PaymentPlanID != plan.ID || got.Type != EventPlanCreated {
+		t.Errorf("NewPlanEvent() = %v, want an %s event of plan %v", got, EventPlanCreated, plan.ID)
+	}
+
+	wantPayload := `{"payment_plan_id":"6b9b3c4c-5d2e-4f0a-9d5b-1c2d3e4f5a6b",` +
+		`"user_id":"0e7e4b3a-1f2d-4c5b-8a9e-0f1e2d3c4b5a",` +
+		`"amount":{"value":"100","currency":"usdc"},"status":"pending"}`
+	if string(got.Payload) != wantPayload {
+		t.Errorf("NewPlanEvent() payload = %s, want %s", got.Payload, wantPayload)
+	}
+}
+
+func TestNewInstallmentEvent(t *testing.T) {
+	t.Parallel()
+
+	inst := &payments.Installment{
+		ID:            uuid.FromStringOrNil("0e7e4b3a-1f2d-4c5b-8a9e-0f1e2d3c4b5a"),
+		PaymentPlanID: uuid.FromStringOrNil("6b9b3c4c-5d2e-4f0a-9d5b-1c2d3e4f5a6b"),
+		Amount:        payments.MustNewMoney(decimal.New(25, 0), "usdc"),
+		DueAt:         time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC),
+		Status:        "paid",
+	}
+
+	got, err := NewInstallmentEvent(EventInstallmentPaid, inst)
+	if err != nil {
+		t.Fatalf("NewInstallmentEvent() error = %v", err)
+	}
+
+	if got.PaymentPlanID != inst.PaymentPlanID || got.Type != EventInstallmentPaid {
+		t.Errorf("NewInstallmentEvent() = %v, want an %s event of plan %v", got, EventInstallmentPaid, inst.PaymentPlanID)
+	}
+
+	wantPayload := `{"payment_plan_id":"6b9b3c4c-5d2e-4f0a-9d5b-1c2d3e4f5a6b",` +
+		`"installment_id":"0e7e4b3a-1f2d-4c5b-8a9e-0f1e2d3c4b5a",` +
+		`"amount":{"value":"25","currency":"usdc"},"due_at":"2022-07-01T10:00:00Z","status":"paid"}`
+	if string(got.Payload) != wantPayload {
+		t.Errorf("NewInstallmentEvent() payload = %s, want %s", got.Payload, wantPayload)
+	}
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		planID  = uuid.Must(uuid.NewV4())
+		planID2 = uuid.Must(uuid.NewV4())
+		errFail = errors.New("broker unavailable")
+	)
+
+	mp := NewMemoryPublisher()
+	mp.Fail(planID, errFail)
+
+	if err := mp.Publish(ctx, &Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planID}); !errors.Is(err, errFail) {
+		t.Fatalf("MemoryPublisher.Publish() error = %v, wantErr %v", err, errFail)
+	}
+
+	second := &Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planID2}
+	if err := mp.Publish(ctx, second); err != nil {
+		t.Fatalf("MemoryPublisher.Publish() error = %v", err)
+	}
+
+	mp.Fail(planID, nil)
+
+	third := &Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planID}
+	if err := mp.Publish(ctx, third); err != nil {
+		t.Fatalf("MemoryPublisher.Publish() error = %v", err)
+	}
+
+	got := mp.Events()
+	if len(got) != 2 || got[0].ID != second.ID || got[1].ID != third.ID {
+		t.Errorf("MemoryPublisher.Events() = %v, want the events of %v then %v", got, second.ID, third.ID)
+	}
+}
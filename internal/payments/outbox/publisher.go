@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_ EventPublisher = (*LogPublisher)(nil)
+	_ EventPublisher = (*MemoryPublisher)(nil)
+)
+
+// LogPublisher writes every event to the log, it stands in for a broker until downstream teams pick one
+type LogPublisher struct {
+	log *zerolog.Logger
+}
+
+func NewLogPublisher(log *zerolog.Logger) *LogPublisher {
+	return &LogPublisher{log: log}
+}
+
+func (lp *LogPublisher) Publish(ctx context.Context, event *Event) error {
+	lp.log.Info().
+		Str("event_id", event.ID.String()).
+		Str("payment_plan_id", event.PaymentPlanID.String()).
+		Str("type", event.Type).
+		RawJSON("payload", event.Payload).
+		Time("created_at", event.CreatedAt).
+		Msg("outbox event")
+
+	return nil
+}
+
+// MemoryPublisher keeps the events it published, Fail makes it refuse the events of a plan
+type MemoryPublisher struct {
+	lock   sync.Mutex
+	events []*Event
+	fails  map[uuid.UUID]error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{fails: make(map[uuid.UUID]error)}
+}
+
+func (mp *MemoryPublisher) Publish(ctx context.Context, event *Event) error {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	if err, ok := mp.fails[event.PaymentPlanID]; ok {
+		return err
+	}
+
+	published := *event
+	mp.events = append(mp.events, &published)
+
+	return nil
+}
+
+// Fail refuses the events of the plan with err, a nil err publishes them again
+func (mp *MemoryPublisher) Fail(paymentPlanID uuid.UUID, err error) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	if err == nil {
+		delete(mp.fails, paymentPlanID)
+
+		return
+	}
+
+	mp.fails[paymentPlanID] = err
+}
+
+// Events lists the published events in the order they were published
+func (mp *MemoryPublisher) Events() []*Event {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	res := make([]*Event, len(mp.events))
+	copy(res, mp.events)
+
+	return res
+}
@@ -3,25 +3,50 @@ package payments
 import (
 	"time"
 
-	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
 )
 
 type Installment struct {
-	ID            uuid.UUID   `json:"id"`
-	PaymentPlanID uuid.UUID   `json:"payment_plan_id"`
-	Currency      string      `json:"currency"`
-	Amount        decimal.Big `json:"amount"`
-	DueAt         time.Time   `json:"due_at"`
-	Status        string      `json:"status"`
-	CreatedAt     time.Time   `json:"created_at"`
-	UpdatedAt     time.Time   `json:"updated_at"`
+	ID            uuid.UUID `json:"id"`
+	PaymentPlanID uuid.UUID `json:"payment_plan_id"`
+	Amount        Money     `json:"amount"`
+	DueAt         time.Time `json:"due_at"`
+	Status        string    `json:"status"`
+	Version       int32     `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type CreateInstallmentParams struct {
 	PaymentPlanID uuid.UUID
-	Currency      string
-	Amount        decimal.Big
+	Amount        Money
 	DueAt         time.Time
 	Status        string
+	Version       int32
+}
+
+type UpdateInstallmentStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+type UpdateInstallmentAmountParams struct {
+	ID     uuid.UUID
+	Amount Money
+}
+
+// UpdateInstallmentsStatusDueBeforeParams selects the installments of PlanID in FromStatus due before DueBefore
+type UpdateInstallmentsStatusDueBeforeParams struct {
+	PlanID     uuid.UUID
+	FromStatus string
+	ToStatus   string
+	DueBefore  time.Time
+}
+
+// ListUnpaidInstallmentsDueBetweenParams selects the unpaid installments due after DueAfter and up to DueBefore
+// that belong to a plan in PlanStatus
+type ListUnpaidInstallmentsDueBetweenParams struct {
+	PlanStatus string
+	DueAfter   time.Time
+	DueBefore  time.Time
 }
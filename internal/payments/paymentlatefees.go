@@ -0,0 +1,24 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// LateFee is a charge assessed on an overdue installment, it is owed on top of
+// the installment amount
+type LateFee struct {
+	ID                   uuid.UUID `json:"id"`
+	PaymentInstallmentID uuid.UUID `json:"payment_installment_id"`
+	Amount               Money     `json:"amount"`
+	AssessedAt           time.Time `json:"assessed_at"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type CreateLateFeeParams struct {
+	PaymentInstallmentID uuid.UUID
+	Amount               Money
+	AssessedAt           time.Time
+}
@@ -0,0 +1,30 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// LineItem is one of the goods of the order a plan pays for, Position keeps the order
+// the merchant listed them in
+type LineItem struct {
+	ID            uuid.UUID
+	PaymentPlanID uuid.UUID
+	Position      int32
+	SKU           string
+	Name          string
+	Quantity      int32
+	UnitPrice     Money
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type CreateLineItemParams struct {
+	PaymentPlanID uuid.UUID
+	Position      int32
+	SKU           string
+	Name          string
+	Quantity      int32
+	UnitPrice     Money
+}
@@ -3,23 +3,54 @@ package payments
 import (
 	"time"
 
-	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
 )
 
+// Plan MerchantID is uuid.Nil for the plans created without a merchant, OrderReference
+// and Description are empty for the plans created without an order
 type Plan struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Currency  string
-	Amount    decimal.Big
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.UUID
+	Amount         Money
+	Status         string
+	OrderReference string
+	Description    string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 type CreatePlanParams struct {
-	UserID   uuid.UUID
-	Currency string
-	Amount   decimal.Big
-	Status   string
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	MerchantID     uuid.UUID
+	Amount         Money
+	Status         string
+	OrderReference string
+	Description    string
+}
+
+type UpdatePlanStatusParams struct {
+	ID     uuid.UUID
+	Status string
+}
+
+// ListPlansByStatusCreatedBeforeParams selects the plans in Status created before CreatedBefore
+type ListPlansByStatusCreatedBeforeParams struct {
+	Status        string
+	CreatedBefore time.Time
+}
+
+// LockPlansWithInstallmentsPastDueParams selects the plans in PlanStatus with a pending installment
+// due before DueBefore or a due one due before OverdueBefore
+type LockPlansWithInstallmentsPastDueParams struct {
+	PlanStatus    string
+	DueBefore     time.Time
+	OverdueBefore time.Time
+}
+
+// GetPlanByOrderReferenceParams selects the plan paying for the order OrderReference of MerchantID
+type GetPlanByOrderReferenceParams struct {
+	MerchantID     uuid.UUID
+	OrderReference string
 }
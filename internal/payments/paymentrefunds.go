@@ -0,0 +1,25 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Refund is money given back against an installment which was paid
+type Refund struct {
+	ID                   uuid.UUID `json:"id"`
+	PaymentInstallmentID uuid.UUID `json:"payment_installment_id"`
+	Amount               Money     `json:"amount"`
+	Reason               string    `json:"reason"`
+	RefundedAt           time.Time `json:"refunded_at"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type CreateRefundParams struct {
+	PaymentInstallmentID uuid.UUID
+	Amount               Money
+	Reason               string
+	RefundedAt           time.Time
+}
@@ -0,0 +1,26 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Settlement is money received against a whole plan, it pays off every installment
+// still unpaid at once
+type Settlement struct {
+	ID                 uuid.UUID `json:"id"`
+	PaymentPlanID      uuid.UUID `json:"payment_plan_id"`
+	Amount             Money     `json:"amount"`
+	ProcessorReference string    `json:"processor_reference"`
+	PaidAt             time.Time `json:"paid_at"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type CreateSettlementParams struct {
+	PaymentPlanID      uuid.UUID
+	Amount             Money
+	ProcessorReference string
+	PaidAt             time.Time
+}
@@ -0,0 +1,26 @@
+package payments
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Transaction is money received against an installment, an installment can be
+// covered by several of them
+type Transaction struct {
+	ID                   uuid.UUID `json:"id"`
+	PaymentInstallmentID uuid.UUID `json:"payment_installment_id"`
+	Amount               Money     `json:"amount"`
+	ProcessorReference   string    `json:"processor_reference"`
+	PaidAt               time.Time `json:"paid_at"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type CreateTransactionParams struct {
+	PaymentInstallmentID uuid.UUID
+	Amount               Money
+	ProcessorReference   string
+	PaidAt               time.Time
+}
@@ -0,0 +1,85 @@
+package reminder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog"
+)
+
+var (
+	_ Notifier = (*LogNotifier)(nil)
+	_ Notifier = (*MemoryNotifier)(nil)
+)
+
+// LogNotifier writes every notification to the log, it stands in for the email and push channels
+type LogNotifier struct {
+	log *zerolog.Logger
+}
+
+func NewLogNotifier(log *zerolog.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (ln *LogNotifier) Notify(ctx context.Context, notification *Notification) error {
+	ln.log.Info().
+		Str("user_id", notification.UserID.String()).
+		Str("payment_plan_id", notification.PaymentPlanID.String()).
+		Str("installment_id", notification.InstallmentID.String()).
+		Str("reminder", notification.Reminder).
+		Str("message", notification.Message).
+		Msg("installment reminder")
+
+	return nil
+}
+
+// MemoryNotifier keeps the notifications it sent, Fail makes it refuse the notifications of a user
+type MemoryNotifier struct {
+	lock          sync.Mutex
+	notifications []*Notification
+	fails         map[uuid.UUID]error
+}
+
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{fails: make(map[uuid.UUID]error)}
+}
+
+func (mn *MemoryNotifier) Notify(ctx context.Context, notification *Notification) error {
+	mn.lock.Lock()
+	defer mn.lock.Unlock()
+
+	if err, ok := mn.fails[notification.UserID]; ok {
+		return err
+	}
+
+	sent := *notification
+	mn.notifications = append(mn.notifications, &sent)
+
+	return nil
+}
+
+// Fail refuses the notifications of the user with err, a nil err sends them again
+func (mn *MemoryNotifier) Fail(userID uuid.UUID, err error) {
+	mn.lock.Lock()
+	defer mn.lock.Unlock()
+
+	if err == nil {
+		delete(mn.fails, userID)
+
+		return
+	}
+
+	mn.fails[userID] = err
+}
+
+// Notifications lists the sent notifications in the order they were sent
+func (mn *MemoryNotifier) Notifications() []*Notification {
+	mn.lock.Lock()
+	defer mn.lock.Unlock()
+
+	res := make([]*Notification, len(mn.notifications))
+	copy(res, mn.notifications)
+
+	return res
+}
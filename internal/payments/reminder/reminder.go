@@ -0,0 +1,95 @@
+// Package reminder reminds users of the installments they owe. A reminder is sent at an offset of the due date
+// of each unpaid installment, ahead of it or after it, at most once per installment and through a Notifier.
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/gofrs/uuid"
+)
+
+var ErrInvalidReminder = errors.New("invalid reminder")
+
+// Notifier tells users about their installments, a notification which failed is sent again later
+// so implementations must tolerate duplicates
+type Notifier interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+// Notification Reminder is the name of the reminder which sent Message
+type Notification struct {
+	UserID        uuid.UUID
+	PaymentPlanID uuid.UUID
+	InstallmentID uuid.UUID
+	Reminder      string
+	Message       string
+}
+
+// Reminder is sent Offset after the due date of an installment, a negative Offset sends it ahead of the due date
+type Reminder struct {
+	Name     string
+	Offset   time.Duration
+	template *template.Template
+}
+
+// TemplateData is what the template of a reminder is rendered with, DueAt is a date in the YYYY-MM-DD format
+type TemplateData struct {
+	Amount   string
+	Currency string
+	DueAt    string
+	Status   string
+}
+
+// CreateSentParams records that the reminder of an installment was sent
+type CreateSentParams struct {
+	InstallmentID uuid.UUID
+	Reminder      string
+	SentAt        time.Time
+}
+
+func New(name string, offset time.Duration, text string) (*Reminder, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: empty name", ErrInvalidReminder)
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("%w: %s has an empty template", ErrInvalidReminder, name)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s template: %v", ErrInvalidReminder, name, err)
+	}
+
+	return &Reminder{Name: name, Offset: offset, template: tmpl}, nil
+}
+
+// DueWindow is the due dates, after excluded and before included, of the installments whose reminder
+// falls in the window before now. An installment is only reminded within window of its reminder time.
+func (r *Reminder) DueWindow(now time.Time, window time.Duration) (after, before time.Time) {
+	before = now.Add(-r.Offset)
+
+	return before.Add(-window), before
+}
+
+func (r *Reminder) Render(inst *payments.Installment) (string, error) {
+	var message strings.Builder
+
+	if err := r.template.Execute(&message, TemplateData{
+		Amount:   inst.Amount.Amount().String(),
+		Currency: inst.Amount.Currency(),
+		DueAt:    inst.DueAt.Format("2006-01-02"),
+		Status:   inst.Status,
+	}); err != nil {
+		return "", fmt.Errorf("render reminder %s: %w", r.Name, err)
+	}
+
+	return message.String(), nil
+}
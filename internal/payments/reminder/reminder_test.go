@@ -0,0 +1,202 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		reminder string
+		text     string
+		wantErr  bool
+	}{
+		{
+			name:     "happy path",
+			reminder: "upcoming",
+			text:     "{{.Amount}} {{.Currency}} is due on {{.DueAt}}",
+		},
+		{
+			name:     "empty name",
+			reminder: " ",
+			text:     "{{.Amount}} is due",
+			wantErr:  true,
+		},
+		{
+			name:     "empty template",
+			reminder: "upcoming",
+			text:     "",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid template",
+			reminder: "upcoming",
+			text:     "{{.Amount",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tt.reminder, -72*time.Hour, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidReminder) {
+				t.Errorf("New() error = %v, want %v", err, ErrInvalidReminder)
+			}
+		})
+	}
+}
+
+func TestReminder_DueWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		offset     time.Duration
+		wantAfter  time.Time
+		wantBefore time.Time
+	}{
+		{
+			name:       "ahead of the due date",
+			offset:     -72 * time.Hour,
+			wantAfter:  time.Date(2022, 7, 12, 12, 0, 0, 0, time.UTC),
+			wantBefore: time.Date(2022, 7, 13, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "on the due date",
+			offset:     0,
+			wantAfter:  time.Date(2022, 7, 9, 12, 0, 0, 0, time.UTC),
+			wantBefore: time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "after the due date",
+			offset:     24 * time.Hour,
+			wantAfter:  time.Date(2022, 7, 8, 12, 0, 0, 0, time.UTC),
+			wantBefore: time.Date(2022, 7, 9, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := New("reminder", tt.offset, "{{.Amount}} is due")
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			gotAfter, gotBefore := r.DueWindow(now, 24*time.Hour)
+			if !gotAfter.Equal(tt.wantAfter) || !gotBefore.Equal(tt.wantBefore) {
+				t.Errorf("Reminder.DueWindow() = (%v, %v), want (%v, %v)",
+					gotAfter, gotBefore, tt.wantAfter, tt.wantBefore)
+			}
+		})
+	}
+}
+
+func TestReminder_Render(t *testing.T) {
+	t.Parallel()
+
+	inst := &payments.Installment{
+		Amount: payments.MustNewMoney(decimal.New(2500, 2), "usdc"),
+		DueAt:  time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC),
+		Status: "due",
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "happy path",
+			text: "{{.Amount}} {{.Currency}} is {{.Status}} since {{.DueAt}}",
+			want: "25.00 usdc is due since 2022-07-01",
+		},
+		{
+			name:    "unknown field",
+			text:    "{{.Fee}} is due",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := New("reminder", 0, tt.text)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := r.Render(inst)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reminder.Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Reminder.Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryNotifier(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		userID  = uuid.Must(uuid.NewV4())
+		userID2 = uuid.Must(uuid.NewV4())
+		errFail = errors.New("mail server unavailable")
+	)
+
+	mn := NewMemoryNotifier()
+	mn.Fail(userID, errFail)
+
+	if err := mn.Notify(ctx, &Notification{UserID: userID, Reminder: "upcoming"}); !errors.Is(err, errFail) {
+		t.Fatalf("MemoryNotifier.Notify() error = %v, wantErr %v", err, errFail)
+	}
+
+	second := &Notification{UserID: userID2, InstallmentID: uuid.Must(uuid.NewV4()), Reminder: "upcoming"}
+	if err := mn.Notify(ctx, second); err != nil {
+		t.Fatalf("MemoryNotifier.Notify() error = %v", err)
+	}
+
+	mn.Fail(userID, nil)
+
+	third := &Notification{UserID: userID, InstallmentID: uuid.Must(uuid.NewV4()), Reminder: "upcoming"}
+	if err := mn.Notify(ctx, third); err != nil {
+		t.Fatalf("MemoryNotifier.Notify() error = %v", err)
+	}
+
+	got := mn.Notifications()
+	if len(got) != 2 || got[0].InstallmentID != second.InstallmentID || got[1].InstallmentID != third.InstallmentID {
+		t.Errorf("MemoryNotifier.Notifications() = %v, want the notifications of %v then %v",
+			got, second.InstallmentID, third.InstallmentID)
+	}
+}
@@ -0,0 +1,33 @@
+package repo
+
+import "fmt"
+
+type repoError string
+
+func (re repoError) Error() string {
+	return string(re)
+}
+
+// ErrRecordNotFound is returned by every Repository when a lookup by ID matches no record
+const ErrRecordNotFound = repoError("no records found")
+
+// the unique constraints a DuplicateKeyError can name
+const (
+	ConstraintPaymentPlansPkey                = "payment_plans_pkey"
+	ConstraintPaymentPlansMerchantOrderRefIdx = "payment_plans_merchant_id_order_reference_idx"
+)
+
+// DuplicateKeyError is returned by every Repository when a record breaks the unique constraint Constraint,
+// Err is the error of the underlying store
+type DuplicateKeyError struct {
+	Constraint string
+	Err        error
+}
+
+func (dk DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key for %s: %v", dk.Constraint, dk.Err)
+}
+
+func (dk DuplicateKeyError) Unwrap() error {
+	return dk.Err
+}
@@ -1,20 +1,73 @@
 package memory
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/collections"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/statemachine"
+	"golangreferenceapi/internal/payments/webhook"
 
 	"github.com/gofrs/uuid"
 )
 
-type InMemRepo struct {
+var _ repo.Repository = (*InMemRepo)(nil)
+
+// store holds the records shared by a repository and every unit of work started from it
+type store struct {
 	paymentPlansLock        sync.RWMutex
 	paymentPlans            map[uuid.UUID][]*payments.Plan
 	paymentInstallmentsLock sync.RWMutex
 	paymentInstallments     map[uuid.UUID][]*payments.Installment
+	paymentTransactionsLock sync.RWMutex
+	paymentTransactions     map[uuid.UUID][]*payments.Transaction
+	paymentLateFeesLock     sync.RWMutex
+	paymentLateFees         map[uuid.UUID][]*payments.LateFee
+	paymentRefundsLock      sync.RWMutex
+	paymentRefunds          map[uuid.UUID][]*payments.Refund
+	paymentSettlementsLock  sync.RWMutex
+	paymentSettlements      map[uuid.UUID][]*payments.Settlement
+	creditLinesLock         sync.RWMutex
+	creditLines             map[uuid.UUID]*payments.CreditLine
+	creditLineChangesLock   sync.RWMutex
+	creditLineChanges       map[uuid.UUID][]*payments.CreditLineChange
+	journalEntriesLock      sync.RWMutex
+	journalEntries          map[uuid.UUID][]*ledger.Entry
+	outboxEventsLock        sync.RWMutex
+	outboxEvents            []*outbox.Event
+	outboxEventsFailedAt    map[uuid.UUID]time.Time // event id to when its last publication failed
+	webhookEndpointsLock    sync.RWMutex
+	webhookEndpoints        []*webhook.Endpoint
+	webhookDeliveriesLock   sync.RWMutex
+	webhookDeliveries       []*webhook.Delivery
+	remindersSentLock       sync.RWMutex
+	remindersSent           map[uuid.UUID]map[string]time.Time // installment id to reminder name to sent at
+	collectionsCasesLock    sync.RWMutex
+	collectionsCases        []*collections.Case
+	collectionsEventsLock   sync.RWMutex
+	collectionsEvents       map[uuid.UUID][]*collections.Event
+	merchantsLock           sync.RWMutex
+	merchants               map[uuid.UUID]*payments.Merchant
+	paymentLineItemsLock    sync.RWMutex
+	paymentLineItems        map[uuid.UUID][]*payments.LineItem
+	auditEntriesLock        sync.RWMutex
+	auditEntries            map[uuid.UUID][]*audit.Entry
+}
+
+// InMemRepo writes are visible to other callers as soon as they are made,
+// a unit of work started with WithTx only guarantees they are undone on failure
+type InMemRepo struct {
+	*store
+	undoLog *undoLog
 }
 
 type memoryError string
@@ -24,44 +77,119 @@ func (me memoryError) Error() string {
 }
 
 const (
-	ErrRecordNotFound   = memoryError("no records found")
+	ErrRecordNotFound   = repo.ErrRecordNotFound
+	ErrDuplicateKey     = memoryError("a record with the same id already exists")
 	ErrGenerateUUID     = memoryError("failed to generate uuid")
 	ErrMapTypeAssertion = memoryError("type assertion failed when load map")
 )
 
 func NewInMemRepository() *InMemRepo {
 	return &InMemRepo{
-		paymentPlans:        make(map[uuid.UUID][]*payments.Plan),
-		paymentInstallments: make(map[uuid.UUID][]*payments.Installment),
+		store: &store{
+			paymentPlans:         make(map[uuid.UUID][]*payments.Plan),
+			paymentInstallments:  make(map[uuid.UUID][]*payments.Installment),
+			paymentTransactions:  make(map[uuid.UUID][]*payments.Transaction),
+			paymentLateFees:      make(map[uuid.UUID][]*payments.LateFee),
+			paymentRefunds:       make(map[uuid.UUID][]*payments.Refund),
+			paymentSettlements:   make(map[uuid.UUID][]*payments.Settlement),
+			creditLines:          make(map[uuid.UUID]*payments.CreditLine),
+			creditLineChanges:    make(map[uuid.UUID][]*payments.CreditLineChange),
+			journalEntries:       make(map[uuid.UUID][]*ledger.Entry),
+			outboxEventsFailedAt: make(map[uuid.UUID]time.Time),
+			remindersSent:        make(map[uuid.UUID]map[string]time.Time),
+			collectionsEvents:    make(map[uuid.UUID][]*collections.Event),
+			merchants:            make(map[uuid.UUID]*payments.Merchant),
+			paymentLineItems:     make(map[uuid.UUID][]*payments.LineItem),
+			auditEntries:         make(map[uuid.UUID][]*audit.Entry),
+		},
+	}
+}
+
+func (imr *InMemRepo) WithTx(ctx context.Context, fn func(txRepo repo.Repository) error) error {
+	txRepo := &InMemRepo{store: imr.store, undoLog: &undoLog{}}
+
+	if err := fn(txRepo); err != nil {
+		txRepo.undoLog.rollback()
+
+		return err
+	}
+
+	// a nested unit of work is only final once the outer one succeeds
+	if imr.undoLog != nil {
+		imr.undoLog.merge(txRepo.undoLog)
 	}
+
+	return nil
 }
 
 func (imr *InMemRepo) CreatePaymentPlan(
 	ctx context.Context,
 	arg *payments.CreatePlanParams,
 ) (*payments.Plan, error) {
-	planID, err := uuid.NewV4()
-	if err != nil {
-		return nil, ErrGenerateUUID
+	planID := arg.ID
+
+	if planID == uuid.Nil {
+		var err error
+
+		planID, err = uuid.NewV4()
+		if err != nil {
+			return nil, ErrGenerateUUID
+		}
 	}
 
 	plan := &payments.Plan{
-		ID:        planID,
-		UserID:    arg.UserID,
-		Currency:  arg.Currency,
-		Amount:    arg.Amount,
-		Status:    arg.Status,
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
+		ID:             planID,
+		UserID:         arg.UserID,
+		MerchantID:     arg.MerchantID,
+		Amount:         arg.Amount,
+		Status:         arg.Status,
+		OrderReference: arg.OrderReference,
+		Description:    arg.Description,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
 	}
 
 	imr.paymentPlansLock.Lock()
+
+	if imr.findPlan(planID) != nil {
+		imr.paymentPlansLock.Unlock()
+
+		return nil, repo.DuplicateKeyError{Constraint: repo.ConstraintPaymentPlansPkey, Err: ErrDuplicateKey}
+	}
+
+	if arg.OrderReference != "" && imr.findPlanByOrderReference(arg.MerchantID, arg.OrderReference) != nil {
+		imr.paymentPlansLock.Unlock()
+
+		return nil, repo.DuplicateKeyError{Constraint: repo.ConstraintPaymentPlansMerchantOrderRefIdx, Err: ErrDuplicateKey}
+	}
+
 	imr.paymentPlans[arg.UserID] = append(imr.paymentPlans[arg.UserID], plan)
 	imr.paymentPlansLock.Unlock()
 
+	imr.onRollback(func() {
+		imr.removePlan(plan)
+	})
+
+	return plan, nil
+}
+
+func (imr *InMemRepo) GetPaymentPlanByID(ctx context.Context, id uuid.UUID) (*payments.Plan, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	plan := imr.findPlan(id)
+	if plan == nil {
+		return nil, ErrRecordNotFound
+	}
+
 	return plan, nil
 }
 
+// LockPaymentPlan only reads the plan, writes are not isolated in memory
+func (imr *InMemRepo) LockPaymentPlan(ctx context.Context, id uuid.UUID) (*payments.Plan, error) {
+	return imr.GetPaymentPlanByID(ctx, id)
+}
+
 func (imr *InMemRepo) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*payments.Plan, error) {
 	imr.paymentPlansLock.RLock()
 	defer imr.paymentPlansLock.RUnlock()
@@ -74,6 +202,148 @@ func (imr *InMemRepo) ListPaymentPlansByUserID(ctx context.Context, userID uuid.
 	return res, nil
 }
 
+// LockPaymentPlansWithInstallmentsPastDue only lists the plans, writes are not isolated in memory
+func (imr *InMemRepo) LockPaymentPlansWithInstallmentsPastDue(
+	ctx context.Context,
+	arg *payments.LockPlansWithInstallmentsPastDueParams,
+) ([]uuid.UUID, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	planIDs := make([]uuid.UUID, 0)
+
+	for planID, installments := range imr.paymentInstallments {
+		plan := imr.findPlan(planID)
+		if plan == nil || plan.Status != arg.PlanStatus {
+			continue
+		}
+
+		for _, inst := range installments {
+			pastDue := inst.Status == statemachine.InstallmentPending && inst.DueAt.Before(arg.DueBefore)
+			overdue := inst.Status == statemachine.InstallmentDue && inst.DueAt.Before(arg.OverdueBefore)
+
+			if pastDue || overdue {
+				planIDs = append(planIDs, planID)
+
+				break
+			}
+		}
+	}
+
+	sort.Slice(planIDs, func(i, j int) bool {
+		return bytes.Compare(planIDs[i].Bytes(), planIDs[j].Bytes()) < 0
+	})
+
+	return planIDs, nil
+}
+
+func (imr *InMemRepo) ListPaymentPlansByStatusCreatedBefore(
+	ctx context.Context,
+	arg *payments.ListPlansByStatusCreatedBeforeParams,
+) ([]*payments.Plan, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	res := make([]*payments.Plan, 0)
+
+	for _, plans := range imr.paymentPlans {
+		for _, plan := range plans {
+			if plan.Status == arg.Status && plan.CreatedAt.Before(arg.CreatedBefore) {
+				res = append(res, plan)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+
+	return res, nil
+}
+
+func (imr *InMemRepo) ListPaymentPlansByMerchantID(
+	ctx context.Context,
+	arg *payments.ListPlansByMerchantIDParams,
+) ([]*payments.Plan, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	res := make([]*payments.Plan, 0)
+
+	for _, plans := range imr.paymentPlans {
+		for _, plan := range plans {
+			if plan.MerchantID == arg.MerchantID {
+				res = append(res, plan)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if !res[i].CreatedAt.Equal(res[j].CreatedAt) {
+			return res[i].CreatedAt.Before(res[j].CreatedAt) == arg.OldestFirst
+		}
+
+		return (res[i].ID.String() < res[j].ID.String()) == arg.OldestFirst
+	})
+
+	if int(arg.Offset) >= len(res) {
+		return []*payments.Plan{}, nil
+	}
+
+	res = res[arg.Offset:]
+
+	if int(arg.Limit) < len(res) {
+		res = res[:arg.Limit]
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) GetPaymentPlanByOrderReference(
+	ctx context.Context,
+	arg *payments.GetPlanByOrderReferenceParams,
+) (*payments.Plan, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	plan := imr.findPlanByOrderReference(arg.MerchantID, arg.OrderReference)
+	if plan == nil {
+		return nil, ErrRecordNotFound
+	}
+
+	return plan, nil
+}
+
+func (imr *InMemRepo) UpdatePaymentPlanStatus(
+	ctx context.Context,
+	arg *payments.UpdatePlanStatusParams,
+) (*payments.Plan, error) {
+	imr.paymentPlansLock.Lock()
+	defer imr.paymentPlansLock.Unlock()
+
+	previous := imr.findPlan(arg.ID)
+	if previous == nil {
+		return nil, ErrRecordNotFound
+	}
+
+	updated := *previous
+	updated.Status = arg.Status
+	updated.UpdatedAt = time.Now().UTC()
+
+	imr.replacePlan(&updated)
+
+	imr.onRollback(func() {
+		imr.paymentPlansLock.Lock()
+		imr.replacePlan(previous)
+		imr.paymentPlansLock.Unlock()
+	})
+
+	return &updated, nil
+}
+
 func (imr *InMemRepo) CreatePaymentInstallment(
 	ctx context.Context,
 	arg *payments.CreateInstallmentParams,
@@ -86,10 +356,10 @@ func (imr *InMemRepo) CreatePaymentInstallment(
 	inst := &payments.Installment{
 		ID:            installmentID,
 		PaymentPlanID: arg.PaymentPlanID,
-		Currency:      arg.Currency,
 		Amount:        arg.Amount,
 		DueAt:         arg.DueAt,
 		Status:        arg.Status,
+		Version:       arg.Version,
 		CreatedAt:     time.Now().UTC(),
 		UpdatedAt:     time.Now().UTC(),
 	}
@@ -98,6 +368,10 @@ func (imr *InMemRepo) CreatePaymentInstallment(
 	imr.paymentInstallments[arg.PaymentPlanID] = append(imr.paymentInstallments[arg.PaymentPlanID], inst)
 	imr.paymentInstallmentsLock.Unlock()
 
+	imr.onRollback(func() {
+		imr.removeInstallment(inst)
+	})
+
 	return inst, nil
 }
 
@@ -115,3 +389,2005 @@ func (imr *InMemRepo) ListPaymentInstallmentsByPlanID(
 
 	return res, nil
 }
+
+// ListPaymentInstallmentsByStatus returns the installments ordered by due date,
+// an empty list when none has the status
+func (imr *InMemRepo) ListPaymentInstallmentsByStatus(
+	ctx context.Context,
+	status string,
+) ([]*payments.Installment, error) {
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	var res []*payments.Installment
+
+	for _, installments := range imr.paymentInstallments {
+		for _, inst := range installments {
+			if inst.Status == status {
+				res = append(res, inst)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].DueAt.Before(res[j].DueAt)
+	})
+
+	return res, nil
+}
+
+func (imr *InMemRepo) UpdatePaymentInstallmentStatus(
+	ctx context.Context,
+	arg *payments.UpdateInstallmentStatusParams,
+) (*payments.Installment, error) {
+	imr.paymentInstallmentsLock.Lock()
+	defer imr.paymentInstallmentsLock.Unlock()
+
+	for _, installments := range imr.paymentInstallments {
+		for _, inst := range installments {
+			if inst.ID != arg.ID {
+				continue
+			}
+
+			previous := inst
+
+			updated := *inst
+			updated.Status = arg.Status
+			updated.UpdatedAt = time.Now().UTC()
+
+			imr.replaceInstallment(&updated)
+
+			imr.onRollback(func() {
+				imr.paymentInstallmentsLock.Lock()
+				imr.replaceInstallment(previous)
+				imr.paymentInstallmentsLock.Unlock()
+			})
+
+			return &updated, nil
+		}
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) UpdatePaymentInstallmentAmount(
+	ctx context.Context,
+	arg *payments.UpdateInstallmentAmountParams,
+) (*payments.Installment, error) {
+	imr.paymentInstallmentsLock.Lock()
+	defer imr.paymentInstallmentsLock.Unlock()
+
+	for _, installments := range imr.paymentInstallments {
+		for _, inst := range installments {
+			if inst.ID != arg.ID {
+				continue
+			}
+
+			previous := inst
+
+			updated := *inst
+			updated.Amount = arg.Amount
+			updated.UpdatedAt = time.Now().UTC()
+
+			imr.replaceInstallment(&updated)
+
+			imr.onRollback(func() {
+				imr.paymentInstallmentsLock.Lock()
+				imr.replaceInstallment(previous)
+				imr.paymentInstallmentsLock.Unlock()
+			})
+
+			return &updated, nil
+		}
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) UpdatePaymentInstallmentsStatusDueBefore(
+	ctx context.Context,
+	arg *payments.UpdateInstallmentsStatusDueBeforeParams,
+) ([]*payments.Installment, error) {
+	imr.paymentInstallmentsLock.Lock()
+	defer imr.paymentInstallmentsLock.Unlock()
+
+	var (
+		updatedInstallments  []*payments.Installment
+		previousInstallments []*payments.Installment
+	)
+
+	for _, inst := range imr.paymentInstallments[arg.PlanID] {
+		if inst.Status != arg.FromStatus || !inst.DueAt.Before(arg.DueBefore) {
+			continue
+		}
+
+		updated := *inst
+		updated.Status = arg.ToStatus
+		updated.UpdatedAt = time.Now().UTC()
+
+		imr.replaceInstallment(&updated)
+
+		updatedInstallments = append(updatedInstallments, &updated)
+		previousInstallments = append(previousInstallments, inst)
+	}
+
+	imr.onRollback(func() {
+		imr.paymentInstallmentsLock.Lock()
+		defer imr.paymentInstallmentsLock.Unlock()
+
+		for _, previous := range previousInstallments {
+			imr.replaceInstallment(previous)
+		}
+	})
+
+	return updatedInstallments, nil
+}
+
+// LockPaymentInstallment only reads the installment, writes are not isolated in memory
+func (imr *InMemRepo) ListUnpaidPaymentInstallmentsDueBetween(
+	ctx context.Context,
+	arg *payments.ListUnpaidInstallmentsDueBetweenParams,
+) ([]*payments.Installment, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	var res []*payments.Installment
+
+	for planID, installments := range imr.paymentInstallments {
+		plan := imr.findPlan(planID)
+		if plan == nil || plan.Status != arg.PlanStatus {
+			continue
+		}
+
+		for _, inst := range installments {
+			if isUnpaidInstallment(inst) && inst.DueAt.After(arg.DueAfter) && !inst.DueAt.After(arg.DueBefore) {
+				res = append(res, inst)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].DueAt.Before(res[j].DueAt)
+	})
+
+	return res, nil
+}
+
+func (imr *InMemRepo) LockPaymentInstallment(ctx context.Context, id uuid.UUID) (*payments.Installment, error) {
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	for _, installments := range imr.paymentInstallments {
+		for _, inst := range installments {
+			if inst.ID == id {
+				return inst, nil
+			}
+		}
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) CreatePaymentTransaction(
+	ctx context.Context,
+	arg *payments.CreateTransactionParams,
+) (*payments.Transaction, error) {
+	transactionID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	transaction := &payments.Transaction{
+		ID:                   transactionID,
+		PaymentInstallmentID: arg.PaymentInstallmentID,
+		Amount:               arg.Amount,
+		ProcessorReference:   arg.ProcessorReference,
+		PaidAt:               arg.PaidAt,
+		CreatedAt:            time.Now().UTC(),
+		UpdatedAt:            time.Now().UTC(),
+	}
+
+	imr.paymentTransactionsLock.Lock()
+	imr.paymentTransactions[arg.PaymentInstallmentID] = append(
+		imr.paymentTransactions[arg.PaymentInstallmentID],
+		transaction,
+	)
+	imr.paymentTransactionsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeTransaction(transaction)
+	})
+
+	return transaction, nil
+}
+
+// ListPaymentTransactionsByInstallmentID returns an empty list when nothing was paid yet
+func (imr *InMemRepo) ListPaymentTransactionsByInstallmentID(
+	ctx context.Context,
+	installmentID uuid.UUID,
+) ([]*payments.Transaction, error) {
+	imr.paymentTransactionsLock.RLock()
+	defer imr.paymentTransactionsLock.RUnlock()
+
+	return imr.paymentTransactions[installmentID], nil
+}
+
+func (imr *InMemRepo) CreatePaymentLateFee(
+	ctx context.Context,
+	arg *payments.CreateLateFeeParams,
+) (*payments.LateFee, error) {
+	lateFeeID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	lateFee := &payments.LateFee{
+		ID:                   lateFeeID,
+		PaymentInstallmentID: arg.PaymentInstallmentID,
+		Amount:               arg.Amount,
+		AssessedAt:           arg.AssessedAt,
+		CreatedAt:            time.Now().UTC(),
+		UpdatedAt:            time.Now().UTC(),
+	}
+
+	imr.paymentLateFeesLock.Lock()
+	imr.paymentLateFees[arg.PaymentInstallmentID] = append(imr.paymentLateFees[arg.PaymentInstallmentID], lateFee)
+	imr.paymentLateFeesLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeLateFee(lateFee)
+	})
+
+	return lateFee, nil
+}
+
+// ListPaymentLateFeesByInstallmentID returns an empty list when no fee was assessed
+func (imr *InMemRepo) ListPaymentLateFeesByInstallmentID(
+	ctx context.Context,
+	installmentID uuid.UUID,
+) ([]*payments.LateFee, error) {
+	imr.paymentLateFeesLock.RLock()
+	defer imr.paymentLateFeesLock.RUnlock()
+
+	return imr.paymentLateFees[installmentID], nil
+}
+
+// ListPaymentLateFeesByPlanID returns the fees of every installment of the plan ordered by assessment,
+// an empty list when no fee was assessed
+func (imr *InMemRepo) ListPaymentLateFeesByPlanID(
+	ctx context.Context,
+	planID uuid.UUID,
+) ([]*payments.LateFee, error) {
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	imr.paymentLateFeesLock.RLock()
+	defer imr.paymentLateFeesLock.RUnlock()
+
+	var res []*payments.LateFee
+
+	for _, inst := range imr.paymentInstallments[planID] {
+		res = append(res, imr.paymentLateFees[inst.ID]...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].AssessedAt.Before(res[j].AssessedAt)
+	})
+
+	return res, nil
+}
+
+func (imr *InMemRepo) CreatePaymentRefund(
+	ctx context.Context,
+	arg *payments.CreateRefundParams,
+) (*payments.Refund, error) {
+	refundID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	refund := &payments.Refund{
+		ID:                   refundID,
+		PaymentInstallmentID: arg.PaymentInstallmentID,
+		Amount:               arg.Amount,
+		Reason:               arg.Reason,
+		RefundedAt:           arg.RefundedAt,
+		CreatedAt:            time.Now().UTC(),
+		UpdatedAt:            time.Now().UTC(),
+	}
+
+	imr.paymentRefundsLock.Lock()
+	imr.paymentRefunds[arg.PaymentInstallmentID] = append(imr.paymentRefunds[arg.PaymentInstallmentID], refund)
+	imr.paymentRefundsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeRefund(refund)
+	})
+
+	return refund, nil
+}
+
+// ListPaymentRefundsByPlanID returns the refunds of every installment of the plan ordered by refund date,
+// an empty list when nothing was refunded
+func (imr *InMemRepo) ListPaymentRefundsByPlanID(
+	ctx context.Context,
+	planID uuid.UUID,
+) ([]*payments.Refund, error) {
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	imr.paymentRefundsLock.RLock()
+	defer imr.paymentRefundsLock.RUnlock()
+
+	var res []*payments.Refund
+
+	for _, inst := range imr.paymentInstallments[planID] {
+		res = append(res, imr.paymentRefunds[inst.ID]...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].RefundedAt.Before(res[j].RefundedAt)
+	})
+
+	return res, nil
+}
+
+func (imr *InMemRepo) CreatePaymentSettlement(
+	ctx context.Context,
+	arg *payments.CreateSettlementParams,
+) (*payments.Settlement, error) {
+	settlementID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	settlement := &payments.Settlement{
+		ID:                 settlementID,
+		PaymentPlanID:      arg.PaymentPlanID,
+		Amount:             arg.Amount,
+		ProcessorReference: arg.ProcessorReference,
+		PaidAt:             arg.PaidAt,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+	}
+
+	imr.paymentSettlementsLock.Lock()
+	imr.paymentSettlements[arg.PaymentPlanID] = append(imr.paymentSettlements[arg.PaymentPlanID], settlement)
+	imr.paymentSettlementsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeSettlement(settlement)
+	})
+
+	return settlement, nil
+}
+
+// CreateCreditLine a user has one credit line at most
+func (imr *InMemRepo) CreateCreditLine(
+	ctx context.Context,
+	arg *payments.CreateCreditLineParams,
+) (*payments.CreditLine, error) {
+	creditLineID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	creditLine := &payments.CreditLine{
+		ID:        creditLineID,
+		UserID:    arg.UserID,
+		Limit:     arg.Limit,
+		Status:    arg.Status,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	imr.creditLinesLock.Lock()
+
+	if _, ok := imr.creditLines[arg.UserID]; ok {
+		imr.creditLinesLock.Unlock()
+
+		return nil, ErrDuplicateKey
+	}
+
+	imr.creditLines[arg.UserID] = creditLine
+	imr.creditLinesLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.creditLinesLock.Lock()
+		delete(imr.creditLines, creditLine.UserID)
+		imr.creditLinesLock.Unlock()
+	})
+
+	return creditLine, nil
+}
+
+func (imr *InMemRepo) GetCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error) {
+	imr.creditLinesLock.RLock()
+	defer imr.creditLinesLock.RUnlock()
+
+	creditLine, ok := imr.creditLines[userID]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	return creditLine, nil
+}
+
+// LockCreditLineByUserID only reads the credit line, writes are not isolated in memory
+func (imr *InMemRepo) LockCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error) {
+	return imr.GetCreditLineByUserID(ctx, userID)
+}
+
+func (imr *InMemRepo) UpdateCreditLineLimit(
+	ctx context.Context,
+	arg *payments.UpdateCreditLineLimitParams,
+) (*payments.CreditLine, error) {
+	return imr.updateCreditLine(arg.ID, func(creditLine *payments.CreditLine) {
+		creditLine.Limit = arg.Limit
+	})
+}
+
+func (imr *InMemRepo) UpdateCreditLineStatus(
+	ctx context.Context,
+	arg *payments.UpdateCreditLineStatusParams,
+) (*payments.CreditLine, error) {
+	return imr.updateCreditLine(arg.ID, func(creditLine *payments.CreditLine) {
+		creditLine.Status = arg.Status
+	})
+}
+
+func (imr *InMemRepo) updateCreditLine(
+	id uuid.UUID,
+	update func(creditLine *payments.CreditLine),
+) (*payments.CreditLine, error) {
+	imr.creditLinesLock.Lock()
+	defer imr.creditLinesLock.Unlock()
+
+	previous := imr.findCreditLine(id)
+	if previous == nil {
+		return nil, ErrRecordNotFound
+	}
+
+	updated := *previous
+	update(&updated)
+	updated.UpdatedAt = time.Now().UTC()
+
+	imr.creditLines[updated.UserID] = &updated
+
+	imr.onRollback(func() {
+		imr.creditLinesLock.Lock()
+		imr.creditLines[previous.UserID] = previous
+		imr.creditLinesLock.Unlock()
+	})
+
+	return &updated, nil
+}
+
+func (imr *InMemRepo) CreateCreditLineChange(
+	ctx context.Context,
+	arg *payments.CreateCreditLineChangeParams,
+) (*payments.CreditLineChange, error) {
+	changeID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	change := &payments.CreditLineChange{
+		ID:           changeID,
+		CreditLineID: arg.CreditLineID,
+		Action:       arg.Action,
+		Actor:        arg.Actor,
+		Reason:       arg.Reason,
+		Limit:        arg.Limit,
+		Status:       arg.Status,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	imr.creditLineChangesLock.Lock()
+	imr.creditLineChanges[arg.CreditLineID] = append(imr.creditLineChanges[arg.CreditLineID], change)
+	imr.creditLineChangesLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeCreditLineChange(change)
+	})
+
+	return change, nil
+}
+
+// ListCreditLineChangesByCreditLineID an empty list when the line was never changed
+func (imr *InMemRepo) ListCreditLineChangesByCreditLineID(
+	ctx context.Context,
+	creditLineID uuid.UUID,
+) ([]*payments.CreditLineChange, error) {
+	imr.creditLineChangesLock.RLock()
+	defer imr.creditLineChangesLock.RUnlock()
+
+	res := make([]*payments.CreditLineChange, len(imr.creditLineChanges[creditLineID]))
+	copy(res, imr.creditLineChanges[creditLineID])
+
+	return res, nil
+}
+
+// CreateJournalEntry refuses an entry which does not balance like the database does
+func (imr *InMemRepo) CreateJournalEntry(ctx context.Context, arg *ledger.CreateEntryParams) (*ledger.Entry, error) {
+	if err := arg.Validate(); err != nil {
+		return nil, err
+	}
+
+	entryID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	entry := &ledger.Entry{
+		ID:            entryID,
+		PaymentPlanID: arg.PaymentPlanID,
+		Kind:          arg.Kind,
+		Postings:      make([]ledger.Posting, 0, len(arg.Postings)),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	for _, posting := range arg.Postings {
+		postingID, err := uuid.NewV4()
+		if err != nil {
+			return nil, ErrGenerateUUID
+		}
+
+		entry.Postings = append(entry.Postings, ledger.Posting{
+			ID:        postingID,
+			Account:   posting.Account,
+			Direction: posting.Direction,
+			Amount:    posting.Amount,
+		})
+	}
+
+	imr.journalEntriesLock.Lock()
+	imr.journalEntries[arg.PaymentPlanID] = append(imr.journalEntries[arg.PaymentPlanID], entry)
+	imr.journalEntriesLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeJournalEntry(entry)
+	})
+
+	return entry, nil
+}
+
+// ListJournalEntriesByPlanID lists the entries in the order they were created
+func (imr *InMemRepo) ListJournalEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*ledger.Entry, error) {
+	imr.journalEntriesLock.RLock()
+	defer imr.journalEntriesLock.RUnlock()
+
+	res := make([]*ledger.Entry, len(imr.journalEntries[planID]))
+	copy(res, imr.journalEntries[planID])
+
+	return res, nil
+}
+
+func (imr *InMemRepo) CreateOutboxEvent(ctx context.Context, arg *outbox.CreateEventParams) (*outbox.Event, error) {
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	event := &outbox.Event{
+		ID:            eventID,
+		PaymentPlanID: arg.PaymentPlanID,
+		Type:          arg.Type,
+		Payload:       arg.Payload,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	imr.outboxEventsLock.Lock()
+	imr.outboxEvents = append(imr.outboxEvents, event)
+	imr.outboxEventsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeOutboxEvent(event)
+	})
+
+	return event, nil
+}
+
+// LockUnpublishedOutboxEvents only reads the events, writes are not isolated in memory
+func (imr *InMemRepo) LockUnpublishedOutboxEvents(ctx context.Context, limit int32) ([]*outbox.Event, error) {
+	imr.outboxEventsLock.RLock()
+	defer imr.outboxEventsLock.RUnlock()
+
+	res := make([]*outbox.Event, 0)
+	failedPlans := make(map[uuid.UUID]bool)
+
+	for _, event := range imr.outboxEvents {
+		if len(res) >= int(limit) {
+			break
+		}
+
+		if event.PublishedAt != nil || failedPlans[event.PaymentPlanID] {
+			continue
+		}
+
+		res = append(res, event)
+
+		if _, failed := imr.outboxEventsFailedAt[event.ID]; failed {
+			failedPlans[event.PaymentPlanID] = true
+		}
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) MarkOutboxEventPublished(
+	ctx context.Context,
+	arg *outbox.MarkEventPublishedParams,
+) (*outbox.Event, error) {
+	imr.outboxEventsLock.Lock()
+	defer imr.outboxEventsLock.Unlock()
+
+	for _, previous := range imr.outboxEvents {
+		if previous.ID != arg.ID {
+			continue
+		}
+
+		publishedAt := arg.PublishedAt
+		updated := *previous
+		updated.PublishedAt = &publishedAt
+
+		imr.replaceOutboxEvent(&updated)
+
+		imr.onRollback(func() {
+			imr.outboxEventsLock.Lock()
+			imr.replaceOutboxEvent(previous)
+			imr.outboxEventsLock.Unlock()
+		})
+
+		return &updated, nil
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) MarkOutboxEventFailed(
+	ctx context.Context,
+	arg *outbox.MarkEventFailedParams,
+) (*outbox.Event, error) {
+	imr.outboxEventsLock.Lock()
+	defer imr.outboxEventsLock.Unlock()
+
+	for _, event := range imr.outboxEvents {
+		if event.ID != arg.ID {
+			continue
+		}
+
+		previous, failed := imr.outboxEventsFailedAt[event.ID]
+		imr.outboxEventsFailedAt[event.ID] = arg.FailedAt
+
+		imr.onRollback(func() {
+			imr.outboxEventsLock.Lock()
+			defer imr.outboxEventsLock.Unlock()
+
+			if failed {
+				imr.outboxEventsFailedAt[event.ID] = previous
+			} else {
+				delete(imr.outboxEventsFailedAt, event.ID)
+			}
+		})
+
+		return event, nil
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) CreateWebhookEndpoint(
+	ctx context.Context,
+	arg *webhook.CreateEndpointParams,
+) (*webhook.Endpoint, error) {
+	endpointID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	endpoint := &webhook.Endpoint{
+		ID:         endpointID,
+		MerchantID: arg.MerchantID,
+		URL:        arg.URL,
+		Secret:     arg.Secret,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	imr.webhookEndpointsLock.Lock()
+	imr.webhookEndpoints = append(imr.webhookEndpoints, endpoint)
+	imr.webhookEndpointsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeWebhookEndpoint(endpoint)
+	})
+
+	return endpoint, nil
+}
+
+func (imr *InMemRepo) GetWebhookEndpointByID(ctx context.Context, id uuid.UUID) (*webhook.Endpoint, error) {
+	imr.webhookEndpointsLock.RLock()
+	defer imr.webhookEndpointsLock.RUnlock()
+
+	for _, endpoint := range imr.webhookEndpoints {
+		if endpoint.ID == id {
+			return endpoint, nil
+		}
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) ListWebhookEndpointsByMerchantID(
+	ctx context.Context,
+	merchantID uuid.UUID,
+) ([]*webhook.Endpoint, error) {
+	imr.webhookEndpointsLock.RLock()
+	defer imr.webhookEndpointsLock.RUnlock()
+
+	res := make([]*webhook.Endpoint, 0)
+
+	for _, endpoint := range imr.webhookEndpoints {
+		if endpoint.MerchantID == merchantID {
+			res = append(res, endpoint)
+		}
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) CreateWebhookDelivery(ctx context.Context, arg *webhook.CreateDeliveryParams) error {
+	deliveryID, err := uuid.NewV4()
+	if err != nil {
+		return ErrGenerateUUID
+	}
+
+	now := time.Now().UTC()
+	delivery := &webhook.Delivery{
+		ID:            deliveryID,
+		EndpointID:    arg.EndpointID,
+		EventID:       arg.EventID,
+		EventType:     arg.EventType,
+		Payload:       arg.Payload,
+		Status:        webhook.DeliveryStatusPending,
+		NextAttemptAt: arg.NextAttemptAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	imr.webhookDeliveriesLock.Lock()
+	defer imr.webhookDeliveriesLock.Unlock()
+
+	for _, existing := range imr.webhookDeliveries {
+		if existing.EndpointID == arg.EndpointID && existing.EventID == arg.EventID {
+			return nil
+		}
+	}
+
+	imr.webhookDeliveries = append(imr.webhookDeliveries, delivery)
+
+	imr.onRollback(func() {
+		imr.removeWebhookDelivery(delivery)
+	})
+
+	return nil
+}
+
+// LockWebhookDelivery only reads the delivery, writes are not isolated in memory
+func (imr *InMemRepo) LockWebhookDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	imr.webhookDeliveriesLock.RLock()
+	defer imr.webhookDeliveriesLock.RUnlock()
+
+	for _, delivery := range imr.webhookDeliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+// LockDueWebhookDeliveries only reads the deliveries, writes are not isolated in memory
+func (imr *InMemRepo) LockDueWebhookDeliveries(
+	ctx context.Context,
+	arg *webhook.ListDueDeliveriesParams,
+) ([]*webhook.Delivery, error) {
+	imr.webhookDeliveriesLock.RLock()
+	defer imr.webhookDeliveriesLock.RUnlock()
+
+	res := make([]*webhook.Delivery, 0)
+
+	for _, delivery := range imr.webhookDeliveries {
+		if delivery.Status == webhook.DeliveryStatusPending && !delivery.NextAttemptAt.After(arg.Before) {
+			res = append(res, delivery)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].NextAttemptAt.Before(res[j].NextAttemptAt)
+	})
+
+	if len(res) > int(arg.Limit) {
+		res = res[:arg.Limit]
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) ListWebhookDeliveriesByStatus(ctx context.Context, status string) ([]*webhook.Delivery, error) {
+	imr.webhookDeliveriesLock.RLock()
+	defer imr.webhookDeliveriesLock.RUnlock()
+
+	res := make([]*webhook.Delivery, 0)
+
+	for idx := len(imr.webhookDeliveries) - 1; idx >= 0; idx-- {
+		if imr.webhookDeliveries[idx].Status == status {
+			res = append(res, imr.webhookDeliveries[idx])
+		}
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) UpdateWebhookDelivery(
+	ctx context.Context,
+	arg *webhook.UpdateDeliveryParams,
+) (*webhook.Delivery, error) {
+	imr.webhookDeliveriesLock.Lock()
+	defer imr.webhookDeliveriesLock.Unlock()
+
+	for _, previous := range imr.webhookDeliveries {
+		if previous.ID != arg.ID {
+			continue
+		}
+
+		updated := *previous
+		updated.Status = arg.Status
+		updated.Attempts = arg.Attempts
+		updated.NextAttemptAt = arg.NextAttemptAt
+		updated.LastError = arg.LastError
+		updated.DeliveredAt = arg.DeliveredAt
+		updated.UpdatedAt = time.Now().UTC()
+
+		imr.replaceWebhookDelivery(&updated)
+
+		imr.onRollback(func() {
+			imr.webhookDeliveriesLock.Lock()
+			imr.replaceWebhookDelivery(previous)
+			imr.webhookDeliveriesLock.Unlock()
+		})
+
+		return &updated, nil
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) CreateReminderSent(ctx context.Context, arg *reminder.CreateSentParams) (bool, error) {
+	imr.remindersSentLock.Lock()
+	defer imr.remindersSentLock.Unlock()
+
+	sent, ok := imr.remindersSent[arg.InstallmentID]
+	if !ok {
+		sent = make(map[string]time.Time)
+		imr.remindersSent[arg.InstallmentID] = sent
+	}
+
+	if _, ok := sent[arg.Reminder]; ok {
+		return false, nil
+	}
+
+	sent[arg.Reminder] = arg.SentAt
+
+	imr.onRollback(func() {
+		imr.remindersSentLock.Lock()
+		defer imr.remindersSentLock.Unlock()
+
+		delete(imr.remindersSent[arg.InstallmentID], arg.Reminder)
+	})
+
+	return true, nil
+}
+
+// ListDelinquentPaymentPlans the locks are taken in the order of the store fields
+// like every reader of several records
+func (imr *InMemRepo) ListDelinquentPaymentPlans(ctx context.Context, limit int32) ([]*payments.Plan, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	imr.collectionsCasesLock.RLock()
+	defer imr.collectionsCasesLock.RUnlock()
+
+	res := make([]*payments.Plan, 0)
+
+	for _, plans := range imr.paymentPlans {
+		for _, plan := range plans {
+			if plan.Status == statemachine.PlanComplete &&
+				imr.hasOverdueInstallment(plan.ID) && imr.findActiveCollectionsCase(plan.ID) == nil {
+				res = append(res, plan)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+
+	if len(res) > int(limit) {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) CreateCollectionsCase(
+	ctx context.Context,
+	arg *collections.CreateCaseParams,
+) (*collections.Case, error) {
+	caseID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	now := time.Now().UTC()
+	collectionsCase := &collections.Case{
+		ID:            caseID,
+		PaymentPlanID: arg.PaymentPlanID,
+		UserID:        arg.UserID,
+		Status:        arg.Status,
+		NextActionAt:  arg.NextActionAt,
+		OpenedAt:      arg.OpenedAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	imr.collectionsCasesLock.Lock()
+	defer imr.collectionsCasesLock.Unlock()
+
+	if imr.findActiveCollectionsCase(arg.PaymentPlanID) != nil {
+		return nil, ErrDuplicateKey
+	}
+
+	imr.collectionsCases = append(imr.collectionsCases, collectionsCase)
+
+	imr.onRollback(func() {
+		imr.removeCollectionsCase(collectionsCase)
+	})
+
+	return collectionsCase, nil
+}
+
+func (imr *InMemRepo) GetCollectionsCaseByID(ctx context.Context, id uuid.UUID) (*collections.Case, error) {
+	imr.collectionsCasesLock.RLock()
+	defer imr.collectionsCasesLock.RUnlock()
+
+	for _, collectionsCase := range imr.collectionsCases {
+		if collectionsCase.ID == id {
+			return collectionsCase, nil
+		}
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) GetActiveCollectionsCaseByPlanID(
+	ctx context.Context,
+	planID uuid.UUID,
+) (*collections.Case, error) {
+	imr.collectionsCasesLock.RLock()
+	defer imr.collectionsCasesLock.RUnlock()
+
+	if collectionsCase := imr.findActiveCollectionsCase(planID); collectionsCase != nil {
+		return collectionsCase, nil
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+// LockCollectionsCase only reads the case, writes are not isolated in memory
+func (imr *InMemRepo) LockCollectionsCase(ctx context.Context, id uuid.UUID) (*collections.Case, error) {
+	return imr.GetCollectionsCaseByID(ctx, id)
+}
+
+func (imr *InMemRepo) ListDueCollectionsCases(
+	ctx context.Context,
+	arg *collections.ListDueCasesParams,
+) ([]*collections.Case, error) {
+	imr.collectionsCasesLock.RLock()
+	defer imr.collectionsCasesLock.RUnlock()
+
+	res := make([]*collections.Case, 0)
+
+	for _, collectionsCase := range imr.collectionsCases {
+		if collectionsCase.Status == statemachine.CollectionsCaseOpen &&
+			collectionsCase.NextActionAt != nil && !collectionsCase.NextActionAt.After(arg.Before) {
+			res = append(res, collectionsCase)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].NextActionAt.Before(*res[j].NextActionAt)
+	})
+
+	if len(res) > int(arg.Limit) {
+		res = res[:arg.Limit]
+	}
+
+	return res, nil
+}
+
+// ListSettledCollectionsCases the locks are taken in the order of the store fields
+func (imr *InMemRepo) ListSettledCollectionsCases(ctx context.Context, limit int32) ([]*collections.Case, error) {
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	imr.collectionsCasesLock.RLock()
+	defer imr.collectionsCasesLock.RUnlock()
+
+	res := make([]*collections.Case, 0)
+
+	for _, collectionsCase := range imr.collectionsCases {
+		if collectionsCase.Status != statemachine.CollectionsCaseClosed &&
+			!imr.hasOverdueInstallment(collectionsCase.PaymentPlanID) {
+			res = append(res, collectionsCase)
+		}
+	}
+
+	if len(res) > int(limit) {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) ListCollectionsCasesByStatus(ctx context.Context, status string) ([]*collections.Case, error) {
+	imr.collectionsCasesLock.RLock()
+	defer imr.collectionsCasesLock.RUnlock()
+
+	res := make([]*collections.Case, 0)
+
+	for idx := len(imr.collectionsCases) - 1; idx >= 0; idx-- {
+		if imr.collectionsCases[idx].Status == status {
+			res = append(res, imr.collectionsCases[idx])
+		}
+	}
+
+	return res, nil
+}
+
+func (imr *InMemRepo) UpdateCollectionsCase(
+	ctx context.Context,
+	arg *collections.UpdateCaseParams,
+) (*collections.Case, error) {
+	imr.collectionsCasesLock.Lock()
+	defer imr.collectionsCasesLock.Unlock()
+
+	for _, previous := range imr.collectionsCases {
+		if previous.ID != arg.ID {
+			continue
+		}
+
+		updated := *previous
+		updated.Status = arg.Status
+		updated.Stage = arg.Stage
+		updated.NextActionAt = arg.NextActionAt
+		updated.ClosedAt = arg.ClosedAt
+		updated.UpdatedAt = time.Now().UTC()
+
+		imr.replaceCollectionsCase(&updated)
+
+		imr.onRollback(func() {
+			imr.collectionsCasesLock.Lock()
+			imr.replaceCollectionsCase(previous)
+			imr.collectionsCasesLock.Unlock()
+		})
+
+		return &updated, nil
+	}
+
+	return nil, ErrRecordNotFound
+}
+
+func (imr *InMemRepo) CreateCollectionsCaseEvent(
+	ctx context.Context,
+	arg *collections.CreateEventParams,
+) (*collections.Event, error) {
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	event := &collections.Event{
+		ID:        eventID,
+		CaseID:    arg.CaseID,
+		Kind:      arg.Kind,
+		Stage:     arg.Stage,
+		Actor:     arg.Actor,
+		Note:      arg.Note,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	imr.collectionsEventsLock.Lock()
+	imr.collectionsEvents[arg.CaseID] = append(imr.collectionsEvents[arg.CaseID], event)
+	imr.collectionsEventsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeCollectionsEvent(event)
+	})
+
+	return event, nil
+}
+
+func (imr *InMemRepo) ListCollectionsCaseEventsByCaseID(
+	ctx context.Context,
+	caseID uuid.UUID,
+) ([]*collections.Event, error) {
+	imr.collectionsEventsLock.RLock()
+	defer imr.collectionsEventsLock.RUnlock()
+
+	res := make([]*collections.Event, len(imr.collectionsEvents[caseID]))
+	copy(res, imr.collectionsEvents[caseID])
+
+	return res, nil
+}
+
+func (imr *InMemRepo) GetUserOutstandingAmount(
+	ctx context.Context,
+	arg *payments.UserOutstandingAmountParams,
+) (payments.Money, error) {
+	outstanding, err := payments.ZeroMoney(arg.Currency)
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	imr.paymentTransactionsLock.RLock()
+	defer imr.paymentTransactionsLock.RUnlock()
+
+	imr.paymentLateFeesLock.RLock()
+	defer imr.paymentLateFeesLock.RUnlock()
+
+	for _, plan := range imr.paymentPlans[arg.UserID] {
+		for _, inst := range imr.paymentInstallments[plan.ID] {
+			if !isUnpaidInstallment(inst) || inst.Amount.Currency() != arg.Currency {
+				continue
+			}
+
+			if outstanding, err = outstanding.Add(inst.Amount); err != nil {
+				return payments.Money{}, err
+			}
+
+			for _, lateFee := range imr.paymentLateFees[inst.ID] {
+				if outstanding, err = outstanding.Add(lateFee.Amount); err != nil {
+					return payments.Money{}, err
+				}
+			}
+
+			for _, transaction := range imr.paymentTransactions[inst.ID] {
+				if outstanding, err = outstanding.Sub(transaction.Amount); err != nil {
+					return payments.Money{}, err
+				}
+			}
+		}
+	}
+
+	return outstanding, nil
+}
+
+func (imr *InMemRepo) CreateMerchant(
+	ctx context.Context,
+	arg *payments.CreateMerchantParams,
+) (*payments.Merchant, error) {
+	merchantID := arg.ID
+
+	if merchantID == uuid.Nil {
+		var err error
+
+		merchantID, err = uuid.NewV4()
+		if err != nil {
+			return nil, ErrGenerateUUID
+		}
+	}
+
+	merchant := &payments.Merchant{
+		ID:        merchantID,
+		Name:      arg.Name,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	imr.merchantsLock.Lock()
+
+	if _, ok := imr.merchants[merchantID]; ok {
+		imr.merchantsLock.Unlock()
+
+		return nil, ErrDuplicateKey
+	}
+
+	imr.merchants[merchantID] = merchant
+	imr.merchantsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.merchantsLock.Lock()
+		delete(imr.merchants, merchantID)
+		imr.merchantsLock.Unlock()
+	})
+
+	return merchant, nil
+}
+
+func (imr *InMemRepo) GetMerchantByID(ctx context.Context, id uuid.UUID) (*payments.Merchant, error) {
+	imr.merchantsLock.RLock()
+	defer imr.merchantsLock.RUnlock()
+
+	merchant, ok := imr.merchants[id]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	return merchant, nil
+}
+
+func (imr *InMemRepo) GetMerchantTotals(
+	ctx context.Context,
+	arg *payments.MerchantTotalsParams,
+) ([]*payments.MerchantTotals, error) {
+	imr.paymentPlansLock.RLock()
+	defer imr.paymentPlansLock.RUnlock()
+
+	imr.paymentInstallmentsLock.RLock()
+	defer imr.paymentInstallmentsLock.RUnlock()
+
+	imr.paymentTransactionsLock.RLock()
+	defer imr.paymentTransactionsLock.RUnlock()
+
+	imr.paymentLateFeesLock.RLock()
+	defer imr.paymentLateFeesLock.RUnlock()
+
+	imr.journalEntriesLock.RLock()
+	defer imr.journalEntriesLock.RUnlock()
+
+	byCurrency := make(map[string]*payments.MerchantTotals)
+
+	for _, plans := range imr.paymentPlans {
+		for _, plan := range plans {
+			if plan.MerchantID != arg.MerchantID ||
+				(plan.Status != statemachine.PlanComplete && plan.Status != statemachine.PlanRefunded) ||
+				plan.CreatedAt.Before(arg.CreatedFrom) || !plan.CreatedAt.Before(arg.CreatedTo) {
+				continue
+			}
+
+			totals, err := merchantTotalsOf(byCurrency, plan.Amount.Currency())
+			if err != nil {
+				return nil, err
+			}
+
+			if err := imr.addPlanToMerchantTotals(totals, plan); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res := make([]*payments.MerchantTotals, 0, len(byCurrency))
+
+	for _, totals := range byCurrency {
+		res = append(res, totals)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Originated.Currency() < res[j].Originated.Currency()
+	})
+
+	return res, nil
+}
+
+func merchantTotalsOf(byCurrency map[string]*payments.MerchantTotals, code string) (*payments.MerchantTotals, error) {
+	if totals, ok := byCurrency[code]; ok {
+		return totals, nil
+	}
+
+	zero, err := payments.ZeroMoney(code)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &payments.MerchantTotals{Originated: zero, Outstanding: zero, Refunded: zero}
+	byCurrency[code] = totals
+
+	return totals, nil
+}
+
+// addPlanToMerchantTotals paymentInstallmentsLock, paymentTransactionsLock, paymentLateFeesLock
+// and journalEntriesLock must be held. What was refunded is what was taken back off the merchant payable,
+// the principal given back or no longer owed, the late fees are not the merchant's.
+func (s *store) addPlanToMerchantTotals(totals *payments.MerchantTotals, plan *payments.Plan) error {
+	var err error
+
+	if totals.Originated, err = totals.Originated.Add(plan.Amount); err != nil {
+		return err
+	}
+
+	for _, entry := range s.journalEntries[plan.ID] {
+		for _, posting := range entry.Postings {
+			if posting.Account != ledger.AccountMerchantPayable || posting.Direction != ledger.Debit {
+				continue
+			}
+
+			if totals.Refunded, err = totals.Refunded.Add(posting.Amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, inst := range s.paymentInstallments[plan.ID] {
+		if !isUnpaidInstallment(inst) {
+			continue
+		}
+
+		if totals.Outstanding, err = totals.Outstanding.Add(inst.Amount); err != nil {
+			return err
+		}
+
+		for _, lateFee := range s.paymentLateFees[inst.ID] {
+			if totals.Outstanding, err = totals.Outstanding.Add(lateFee.Amount); err != nil {
+				return err
+			}
+		}
+
+		for _, transaction := range s.paymentTransactions[inst.ID] {
+			if totals.Outstanding, err = totals.Outstanding.Sub(transaction.Amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func isUnpaidInstallment(inst *payments.Installment) bool {
+	return inst.Status == statemachine.InstallmentPending ||
+		inst.Status == statemachine.InstallmentDue ||
+		inst.Status == statemachine.InstallmentOverdue
+}
+
+func (imr *InMemRepo) CreatePaymentPlanLineItem(
+	ctx context.Context,
+	arg *payments.CreateLineItemParams,
+) (*payments.LineItem, error) {
+	lineItemID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	lineItem := &payments.LineItem{
+		ID:            lineItemID,
+		PaymentPlanID: arg.PaymentPlanID,
+		Position:      arg.Position,
+		SKU:           arg.SKU,
+		Name:          arg.Name,
+		Quantity:      arg.Quantity,
+		UnitPrice:     arg.UnitPrice,
+		CreatedAt:     time.Now().UTC(),
+		UpdatedAt:     time.Now().UTC(),
+	}
+
+	imr.paymentLineItemsLock.Lock()
+
+	for _, existing := range imr.paymentLineItems[arg.PaymentPlanID] {
+		if existing.Position == arg.Position {
+			imr.paymentLineItemsLock.Unlock()
+
+			return nil, ErrDuplicateKey
+		}
+	}
+
+	imr.paymentLineItems[arg.PaymentPlanID] = append(imr.paymentLineItems[arg.PaymentPlanID], lineItem)
+	imr.paymentLineItemsLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeLineItem(lineItem)
+	})
+
+	return lineItem, nil
+}
+
+// ListPaymentPlanLineItemsByPlanID returns an empty list when the plan has no line item
+func (imr *InMemRepo) ListPaymentPlanLineItemsByPlanID(
+	ctx context.Context,
+	planID uuid.UUID,
+) ([]*payments.LineItem, error) {
+	imr.paymentLineItemsLock.RLock()
+	defer imr.paymentLineItemsLock.RUnlock()
+
+	res := make([]*payments.LineItem, len(imr.paymentLineItems[planID]))
+	copy(res, imr.paymentLineItems[planID])
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Position < res[j].Position
+	})
+
+	return res, nil
+}
+
+// CreateAuditEntry a sequence already taken by the plan is refused with ErrDuplicateKey
+func (imr *InMemRepo) CreateAuditEntry(ctx context.Context, arg *audit.CreateEntryParams) (*audit.Entry, error) {
+	entryID, err := uuid.NewV4()
+	if err != nil {
+		return nil, ErrGenerateUUID
+	}
+
+	entry := &audit.Entry{
+		ID:            entryID,
+		PaymentPlanID: arg.PaymentPlanID,
+		Sequence:      arg.Sequence,
+		Actor:         arg.Actor,
+		Action:        arg.Action,
+		Before:        arg.Before,
+		After:         arg.After,
+		RequestID:     arg.RequestID,
+		OccurredAt:    arg.OccurredAt,
+		PrevHash:      arg.PrevHash,
+		Hash:          arg.Hash,
+	}
+
+	imr.auditEntriesLock.Lock()
+
+	for _, existing := range imr.auditEntries[arg.PaymentPlanID] {
+		if existing.Sequence == arg.Sequence {
+			imr.auditEntriesLock.Unlock()
+
+			return nil, ErrDuplicateKey
+		}
+	}
+
+	imr.auditEntries[arg.PaymentPlanID] = append(imr.auditEntries[arg.PaymentPlanID], entry)
+	imr.auditEntriesLock.Unlock()
+
+	imr.onRollback(func() {
+		imr.removeAuditEntry(entry)
+	})
+
+	return entry, nil
+}
+
+func (imr *InMemRepo) GetLatestAuditEntryByPlanID(ctx context.Context, planID uuid.UUID) (*audit.Entry, error) {
+	imr.auditEntriesLock.RLock()
+	defer imr.auditEntriesLock.RUnlock()
+
+	var latest *audit.Entry
+
+	for _, entry := range imr.auditEntries[planID] {
+		if latest == nil || entry.Sequence > latest.Sequence {
+			latest = entry
+		}
+	}
+
+	if latest == nil {
+		return nil, ErrRecordNotFound
+	}
+
+	return latest, nil
+}
+
+// ListAuditEntriesByPlanID returns an empty list when the plan has no entry
+func (imr *InMemRepo) ListAuditEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*audit.Entry, error) {
+	imr.auditEntriesLock.RLock()
+	defer imr.auditEntriesLock.RUnlock()
+
+	res := make([]*audit.Entry, len(imr.auditEntries[planID]))
+	copy(res, imr.auditEntries[planID])
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Sequence < res[j].Sequence
+	})
+
+	return res, nil
+}
+
+func (imr *InMemRepo) onRollback(undo func()) {
+	if imr.undoLog == nil {
+		return
+	}
+
+	imr.undoLog.push(undo)
+}
+
+// findPlan paymentPlansLock must be held
+func (s *store) findPlan(id uuid.UUID) *payments.Plan {
+	for _, plans := range s.paymentPlans {
+		for _, plan := range plans {
+			if plan.ID == id {
+				return plan
+			}
+		}
+	}
+
+	return nil
+}
+
+// replacePlan copies on write, callers may still hold the previously listed records.
+// paymentPlansLock must be held.
+// findPlanByOrderReference paymentPlansLock must be held
+func (s *store) findPlanByOrderReference(merchantID uuid.UUID, orderReference string) *payments.Plan {
+	for _, plans := range s.paymentPlans {
+		for _, plan := range plans {
+			if plan.MerchantID == merchantID && plan.OrderReference == orderReference {
+				return plan
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *store) replacePlan(plan *payments.Plan) {
+	plans := s.paymentPlans[plan.UserID]
+
+	for idx := range plans {
+		if plans[idx].ID != plan.ID {
+			continue
+		}
+
+		updatedPlans := make([]*payments.Plan, len(plans))
+		copy(updatedPlans, plans)
+		updatedPlans[idx] = plan
+
+		s.paymentPlans[plan.UserID] = updatedPlans
+
+		return
+	}
+}
+
+func (s *store) removePlan(plan *payments.Plan) {
+	s.paymentPlansLock.Lock()
+	defer s.paymentPlansLock.Unlock()
+
+	plans := s.paymentPlans[plan.UserID]
+	kept := make([]*payments.Plan, 0, len(plans))
+
+	for _, existing := range plans {
+		if existing.ID != plan.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentPlans, plan.UserID)
+
+		return
+	}
+
+	s.paymentPlans[plan.UserID] = kept
+}
+
+// replaceInstallment copies on write, callers may still hold the previously listed records.
+// paymentInstallmentsLock must be held.
+func (s *store) replaceInstallment(inst *payments.Installment) {
+	installments := s.paymentInstallments[inst.PaymentPlanID]
+
+	for idx := range installments {
+		if installments[idx].ID != inst.ID {
+			continue
+		}
+
+		updatedInstallments := make([]*payments.Installment, len(installments))
+		copy(updatedInstallments, installments)
+		updatedInstallments[idx] = inst
+
+		s.paymentInstallments[inst.PaymentPlanID] = updatedInstallments
+
+		return
+	}
+}
+
+func (s *store) removeInstallment(inst *payments.Installment) {
+	s.paymentInstallmentsLock.Lock()
+	defer s.paymentInstallmentsLock.Unlock()
+
+	installments := s.paymentInstallments[inst.PaymentPlanID]
+	kept := make([]*payments.Installment, 0, len(installments))
+
+	for _, existing := range installments {
+		if existing.ID != inst.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentInstallments, inst.PaymentPlanID)
+
+		return
+	}
+
+	s.paymentInstallments[inst.PaymentPlanID] = kept
+}
+
+func (s *store) removeTransaction(transaction *payments.Transaction) {
+	s.paymentTransactionsLock.Lock()
+	defer s.paymentTransactionsLock.Unlock()
+
+	transactions := s.paymentTransactions[transaction.PaymentInstallmentID]
+	kept := make([]*payments.Transaction, 0, len(transactions))
+
+	for _, existing := range transactions {
+		if existing.ID != transaction.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentTransactions, transaction.PaymentInstallmentID)
+
+		return
+	}
+
+	s.paymentTransactions[transaction.PaymentInstallmentID] = kept
+}
+
+func (s *store) removeLateFee(lateFee *payments.LateFee) {
+	s.paymentLateFeesLock.Lock()
+	defer s.paymentLateFeesLock.Unlock()
+
+	lateFees := s.paymentLateFees[lateFee.PaymentInstallmentID]
+	kept := make([]*payments.LateFee, 0, len(lateFees))
+
+	for _, existing := range lateFees {
+		if existing.ID != lateFee.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentLateFees, lateFee.PaymentInstallmentID)
+
+		return
+	}
+
+	s.paymentLateFees[lateFee.PaymentInstallmentID] = kept
+}
+
+func (s *store) removeRefund(refund *payments.Refund) {
+	s.paymentRefundsLock.Lock()
+	defer s.paymentRefundsLock.Unlock()
+
+	refunds := s.paymentRefunds[refund.PaymentInstallmentID]
+	kept := make([]*payments.Refund, 0, len(refunds))
+
+	for _, existing := range refunds {
+		if existing.ID != refund.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentRefunds, refund.PaymentInstallmentID)
+
+		return
+	}
+
+	s.paymentRefunds[refund.PaymentInstallmentID] = kept
+}
+
+// undoLog records how to revert the writes of a unit of work
+type undoLog struct {
+	lock  sync.Mutex
+	undos []func()
+}
+
+func (ul *undoLog) push(undo func()) {
+	ul.lock.Lock()
+	ul.undos = append(ul.undos, undo)
+	ul.lock.Unlock()
+}
+
+func (ul *undoLog) merge(child *undoLog) {
+	child.lock.Lock()
+	undos := child.undos
+	child.lock.Unlock()
+
+	ul.lock.Lock()
+	ul.undos = append(ul.undos, undos...)
+	ul.lock.Unlock()
+}
+
+// rollback reverts the writes in reverse order
+func (ul *undoLog) rollback() {
+	ul.lock.Lock()
+	defer ul.lock.Unlock()
+
+	for idx := len(ul.undos) - 1; idx >= 0; idx-- {
+		ul.undos[idx]()
+	}
+
+	ul.undos = nil
+}
+
+func (s *store) removeSettlement(settlement *payments.Settlement) {
+	s.paymentSettlementsLock.Lock()
+	defer s.paymentSettlementsLock.Unlock()
+
+	settlements := s.paymentSettlements[settlement.PaymentPlanID]
+	kept := make([]*payments.Settlement, 0, len(settlements))
+
+	for _, existing := range settlements {
+		if existing.ID != settlement.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentSettlements, settlement.PaymentPlanID)
+
+		return
+	}
+
+	s.paymentSettlements[settlement.PaymentPlanID] = kept
+}
+
+// findCreditLine creditLinesLock must be held
+func (s *store) findCreditLine(id uuid.UUID) *payments.CreditLine {
+	for _, creditLine := range s.creditLines {
+		if creditLine.ID == id {
+			return creditLine
+		}
+	}
+
+	return nil
+}
+
+func (s *store) removeCreditLineChange(change *payments.CreditLineChange) {
+	s.creditLineChangesLock.Lock()
+	defer s.creditLineChangesLock.Unlock()
+
+	changes := s.creditLineChanges[change.CreditLineID]
+	kept := make([]*payments.CreditLineChange, 0, len(changes))
+
+	for _, existing := range changes {
+		if existing.ID != change.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.creditLineChanges, change.CreditLineID)
+
+		return
+	}
+
+	s.creditLineChanges[change.CreditLineID] = kept
+}
+
+func (s *store) removeJournalEntry(entry *ledger.Entry) {
+	s.journalEntriesLock.Lock()
+	defer s.journalEntriesLock.Unlock()
+
+	entries := s.journalEntries[entry.PaymentPlanID]
+	kept := make([]*ledger.Entry, 0, len(entries))
+
+	for _, existing := range entries {
+		if existing.ID != entry.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.journalEntries, entry.PaymentPlanID)
+
+		return
+	}
+
+	s.journalEntries[entry.PaymentPlanID] = kept
+}
+
+func (s *store) removeOutboxEvent(event *outbox.Event) {
+	s.outboxEventsLock.Lock()
+	defer s.outboxEventsLock.Unlock()
+
+	kept := make([]*outbox.Event, 0, len(s.outboxEvents))
+
+	for _, existing := range s.outboxEvents {
+		if existing.ID != event.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	s.outboxEvents = kept
+}
+
+func (s *store) removeWebhookEndpoint(endpoint *webhook.Endpoint) {
+	s.webhookEndpointsLock.Lock()
+	defer s.webhookEndpointsLock.Unlock()
+
+	kept := make([]*webhook.Endpoint, 0, len(s.webhookEndpoints))
+
+	for _, existing := range s.webhookEndpoints {
+		if existing.ID != endpoint.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	s.webhookEndpoints = kept
+}
+
+func (s *store) removeWebhookDelivery(delivery *webhook.Delivery) {
+	s.webhookDeliveriesLock.Lock()
+	defer s.webhookDeliveriesLock.Unlock()
+
+	kept := make([]*webhook.Delivery, 0, len(s.webhookDeliveries))
+
+	for _, existing := range s.webhookDeliveries {
+		if existing.ID != delivery.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	s.webhookDeliveries = kept
+}
+
+// replaceWebhookDelivery copies on write like replacePlan.
+// webhookDeliveriesLock must be held.
+func (s *store) replaceWebhookDelivery(delivery *webhook.Delivery) {
+	for idx := range s.webhookDeliveries {
+		if s.webhookDeliveries[idx].ID != delivery.ID {
+			continue
+		}
+
+		updatedDeliveries := make([]*webhook.Delivery, len(s.webhookDeliveries))
+		copy(updatedDeliveries, s.webhookDeliveries)
+		updatedDeliveries[idx] = delivery
+
+		s.webhookDeliveries = updatedDeliveries
+
+		return
+	}
+}
+
+// replaceOutboxEvent copies on write like replacePlan.
+// outboxEventsLock must be held.
+func (s *store) replaceOutboxEvent(event *outbox.Event) {
+	for idx := range s.outboxEvents {
+		if s.outboxEvents[idx].ID != event.ID {
+			continue
+		}
+
+		updatedEvents := make([]*outbox.Event, len(s.outboxEvents))
+		copy(updatedEvents, s.outboxEvents)
+		updatedEvents[idx] = event
+
+		s.outboxEvents = updatedEvents
+
+		return
+	}
+}
+
+// hasOverdueInstallment paymentInstallmentsLock must be held
+func (s *store) hasOverdueInstallment(planID uuid.UUID) bool {
+	for _, inst := range s.paymentInstallments[planID] {
+		if inst.Status == statemachine.InstallmentOverdue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findActiveCollectionsCase collectionsCasesLock must be held
+func (s *store) findActiveCollectionsCase(planID uuid.UUID) *collections.Case {
+	for _, collectionsCase := range s.collectionsCases {
+		if collectionsCase.PaymentPlanID == planID && collectionsCase.Status != statemachine.CollectionsCaseClosed {
+			return collectionsCase
+		}
+	}
+
+	return nil
+}
+
+func (s *store) removeCollectionsCase(collectionsCase *collections.Case) {
+	s.collectionsCasesLock.Lock()
+	defer s.collectionsCasesLock.Unlock()
+
+	kept := make([]*collections.Case, 0, len(s.collectionsCases))
+
+	for _, existing := range s.collectionsCases {
+		if existing.ID != collectionsCase.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	s.collectionsCases = kept
+}
+
+// replaceCollectionsCase copies on write like replacePlan.
+// collectionsCasesLock must be held.
+func (s *store) replaceCollectionsCase(collectionsCase *collections.Case) {
+	for idx := range s.collectionsCases {
+		if s.collectionsCases[idx].ID != collectionsCase.ID {
+			continue
+		}
+
+		updatedCases := make([]*collections.Case, len(s.collectionsCases))
+		copy(updatedCases, s.collectionsCases)
+		updatedCases[idx] = collectionsCase
+
+		s.collectionsCases = updatedCases
+
+		return
+	}
+}
+
+func (s *store) removeCollectionsEvent(event *collections.Event) {
+	s.collectionsEventsLock.Lock()
+	defer s.collectionsEventsLock.Unlock()
+
+	events := s.collectionsEvents[event.CaseID]
+	kept := make([]*collections.Event, 0, len(events))
+
+	for _, existing := range events {
+		if existing.ID != event.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.collectionsEvents, event.CaseID)
+
+		return
+	}
+
+	s.collectionsEvents[event.CaseID] = kept
+}
+
+func (s *store) removeLineItem(lineItem *payments.LineItem) {
+	s.paymentLineItemsLock.Lock()
+	defer s.paymentLineItemsLock.Unlock()
+
+	lineItems := s.paymentLineItems[lineItem.PaymentPlanID]
+	kept := make([]*payments.LineItem, 0, len(lineItems))
+
+	for _, existing := range lineItems {
+		if existing.ID != lineItem.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.paymentLineItems, lineItem.PaymentPlanID)
+
+		return
+	}
+
+	s.paymentLineItems[lineItem.PaymentPlanID] = kept
+}
+
+func (s *store) removeAuditEntry(entry *audit.Entry) {
+	s.auditEntriesLock.Lock()
+	defer s.auditEntriesLock.Unlock()
+
+	entries := s.auditEntries[entry.PaymentPlanID]
+	kept := make([]*audit.Entry, 0, len(entries))
+
+	for _, existing := range entries {
+		if existing.ID != entry.ID {
+			kept = append(kept, existing)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.auditEntries, entry.PaymentPlanID)
+
+		return
+	}
+
+	s.auditEntries[entry.PaymentPlanID] = kept
+}
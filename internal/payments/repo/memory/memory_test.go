@@ -1,15 +1,25 @@
 package memory
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"reflect"
 	"testing"
 	"time"
 
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/collections"
 	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/webhook"
 
 	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
@@ -91,6 +101,33 @@ func TestMemoryError_ErrRecordNotFound(t *testing.T) {
 	}
 }
 
+func TestMemoryError_ErrDuplicateKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ErrDuplicateKey,
+			expectedString: "a record with the same id already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
 func TestMemoryError_ErrMapTypeAssertion(t *testing.T) {
 	t.Parallel()
 
@@ -118,6 +155,164 @@ func TestMemoryError_ErrMapTypeAssertion(t *testing.T) {
 	}
 }
 
+func TestInMemRepository_WithTx(t *testing.T) {
+	t.Parallel()
+
+	errDummy := memoryError("dummy")
+
+	tests := []struct {
+		name      string
+		fn        func(userID uuid.UUID) func(txRepo repo.Repository) error
+		wantErr   bool
+		wantPlans int
+	}{
+		{
+			name: "commit",
+			fn: func(userID uuid.UUID) func(txRepo repo.Repository) error {
+				return func(txRepo repo.Repository) error {
+					return createPlanWithInstallment(txRepo, userID)
+				}
+			},
+			wantErr:   false,
+			wantPlans: 1,
+		},
+		{
+			name: "rollback",
+			fn: func(userID uuid.UUID) func(txRepo repo.Repository) error {
+				return func(txRepo repo.Repository) error {
+					if err := createPlanWithInstallment(txRepo, userID); err != nil {
+						return err
+					}
+
+					return errDummy
+				}
+			},
+			wantErr:   true,
+			wantPlans: 0,
+		},
+		{
+			name: "nested rollback after inner commit",
+			fn: func(userID uuid.UUID) func(txRepo repo.Repository) error {
+				return func(txRepo repo.Repository) error {
+					if err := txRepo.WithTx(context.Background(), func(innerRepo repo.Repository) error {
+						return createPlanWithInstallment(innerRepo, userID)
+					}); err != nil {
+						return err
+					}
+
+					return errDummy
+				}
+			},
+			wantErr:   true,
+			wantPlans: 0,
+		},
+		{
+			name: "inner rollback only",
+			fn: func(userID uuid.UUID) func(txRepo repo.Repository) error {
+				return func(txRepo repo.Repository) error {
+					if err := createPlanWithInstallment(txRepo, userID); err != nil {
+						return err
+					}
+
+					_ = txRepo.WithTx(context.Background(), func(innerRepo repo.Repository) error {
+						if err := createPlanWithInstallment(innerRepo, userID); err != nil {
+							return err
+						}
+
+						return errDummy
+					})
+
+					return nil
+				}
+			},
+			wantErr:   false,
+			wantPlans: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			memRepo := NewInMemRepository()
+			userID := uuid.Must(uuid.NewV4())
+
+			err := memRepo.WithTx(context.Background(), tt.fn(userID))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			plans, _ := memRepo.ListPaymentPlansByUserID(context.Background(), userID)
+			if len(plans) != tt.wantPlans {
+				t.Fatalf("got len(plans) = %v, want %v", len(plans), tt.wantPlans)
+			}
+
+			for _, plan := range plans {
+				installments, err := memRepo.ListPaymentInstallmentsByPlanID(context.Background(), plan.ID)
+				if err != nil || len(installments) != 1 {
+					t.Errorf("got installments = %v, err = %v, want 1 installment", installments, err)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemRepository_WithTx_RollbackStatus(t *testing.T) {
+	t.Parallel()
+
+	memRepo := NewInMemRepository()
+	userID := uuid.Must(uuid.NewV4())
+
+	plan, err := memRepo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	err = memRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+		if _, err := txRepo.UpdatePaymentPlanStatus(context.Background(), &payments.UpdatePlanStatusParams{
+			ID:     plan.ID,
+			Status: "complete",
+		}); err != nil {
+			return err
+		}
+
+		return ErrRecordNotFound
+	})
+	if err == nil {
+		t.Errorf("expected err but nil returned")
+	}
+
+	plans, _ := memRepo.ListPaymentPlansByUserID(context.Background(), userID)
+	if len(plans) != 1 || plans[0].Status != "pending" {
+		t.Errorf("got plans = %v, want the pending plan to be restored", plans)
+	}
+}
+
+func createPlanWithInstallment(repository repo.Repository, userID uuid.UUID) error {
+	plan, err := repository.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+		PaymentPlanID: plan.ID,
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         time.Now().UTC(),
+		Status:        "pending",
+	})
+
+	return err
+}
+
 func TestInMemRepository_CreatePaymentPlan(t *testing.T) {
 	t.Parallel()
 
@@ -128,10 +323,9 @@ func TestInMemRepository_CreatePaymentPlan(t *testing.T) {
 	)
 
 	_, err := repo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
-		UserID:   userUUID,
-		Currency: "usdc",
-		Amount:   *decimal.New(1098, 2),
-		Status:   "pending",
+		UserID: userUUID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
 	})
 	if err != nil {
 		t.Fatalf("fail to create payment plan: %v", err)
@@ -151,17 +345,15 @@ func TestInMemRepository_CreatePaymentPlan(t *testing.T) {
 			name: "happy path - user has no existing plans",
 			args: args{
 				arg: &payments.CreatePlanParams{
-					UserID:   newUserUUID,
-					Currency: "usdc",
-					Amount:   *decimal.New(1098, 2),
-					Status:   "pending",
+					UserID: newUserUUID,
+					Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+					Status: "pending",
 				},
 			},
 			want: &payments.Plan{
-				UserID:   newUserUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(1098, 2),
-				Status:   "pending",
+				UserID: newUserUUID,
+				Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				Status: "pending",
 			},
 			wantErr: false,
 		},
@@ -169,17 +361,15 @@ func TestInMemRepository_CreatePaymentPlan(t *testing.T) {
 			name: "happy path - user has existing plans",
 			args: args{
 				arg: &payments.CreatePlanParams{
-					UserID:   userUUID,
-					Currency: "usdc",
-					Amount:   *decimal.New(1098, 2),
-					Status:   "pending",
+					UserID: userUUID,
+					Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+					Status: "pending",
 				},
 			},
 			want: &payments.Plan{
-				UserID:   userUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(1098, 2),
-				Status:   "pending",
+				UserID: userUUID,
+				Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				Status: "pending",
 			},
 			wantErr: false,
 		},
@@ -204,11 +394,11 @@ func TestInMemRepository_CreatePaymentPlan(t *testing.T) {
 				t.Errorf("wrong expected user id: got %v, want %v", tt.want.UserID, got.UserID)
 			}
 
-			if got.Currency != tt.want.Currency {
-				t.Errorf("wrong expected currency: got %v, want %v", tt.want.Currency, got.Currency)
+			if got.Amount.Currency() != tt.want.Amount.Currency() {
+				t.Errorf("wrong expected currency: got %v, want %v", tt.want.Amount.Currency(), got.Amount.Currency())
 			}
 
-			if got.Amount.Cmp(&tt.want.Amount) != 0 {
+			if got.Amount.Amount().Cmp(tt.want.Amount.Amount()) != 0 {
 				t.Errorf("wrong expected amount: got %v, want %v", tt.want.Amount, got.Amount)
 			}
 
@@ -219,6 +409,99 @@ func TestInMemRepository_CreatePaymentPlan(t *testing.T) {
 	}
 }
 
+func TestInMemRepository_CreatePaymentPlan_ClientID(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		arg     = &payments.CreatePlanParams{
+			ID:     planID,
+			UserID: uuid.Must(uuid.NewV4()),
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: "pending",
+		}
+	)
+
+	plan, err := memRepo.CreatePaymentPlan(context.Background(), arg)
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	if plan.ID != planID {
+		t.Errorf("wrong expected id: got %v, want %v", plan.ID, planID)
+	}
+
+	_, err = memRepo.CreatePaymentPlan(context.Background(), arg)
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %v", err)
+	}
+
+	var duplicateKey repo.DuplicateKeyError
+	if !errors.As(err, &duplicateKey) || duplicateKey.Constraint != repo.ConstraintPaymentPlansPkey {
+		t.Errorf("expected the primary key to be named, got %v", err)
+	}
+
+	plans, err := memRepo.ListPaymentPlansByUserID(context.Background(), arg.UserID)
+	if err != nil {
+		t.Fatalf("fail to list payment plans: %v", err)
+	}
+
+	if len(plans) != 1 {
+		t.Errorf("expected a single plan, got %d", len(plans))
+	}
+}
+
+func TestInMemRepository_GetPaymentPlanByID(t *testing.T) {
+	t.Parallel()
+
+	memRepo := NewInMemRepository()
+
+	plan, err := memRepo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		want    *payments.Plan
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			id:   plan.ID,
+			want: plan,
+		},
+		{
+			name:    "plan does not exist",
+			id:      uuid.Must(uuid.NewV4()),
+			wantErr: repo.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := memRepo.GetPaymentPlanByID(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestInMemRepository_CreatePaymentPlan_Concurrency(t *testing.T) {
 	t.Parallel()
 
@@ -227,16 +510,14 @@ func TestInMemRepository_CreatePaymentPlan_Concurrency(t *testing.T) {
 		n           = 20
 		userUUID, _ = uuid.NewV4()
 		params      = &payments.CreatePlanParams{
-			UserID:   userUUID,
-			Currency: "usdc",
-			Amount:   *decimal.New(1098, 2),
-			Status:   "pending",
+			UserID: userUUID,
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: "pending",
 		}
 		want = &payments.Plan{
-			UserID:   userUUID,
-			Currency: "usdc",
-			Amount:   *decimal.New(1098, 2),
-			Status:   "pending",
+			UserID: userUUID,
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: "pending",
 		}
 	)
 
@@ -268,11 +549,11 @@ func TestInMemRepository_CreatePaymentPlan_Concurrency(t *testing.T) {
 			t.Errorf("wrong expected user id: got %v, want %v", want.UserID, res.UserID)
 		}
 
-		if res.Currency != want.Currency {
-			t.Errorf("wrong expected currency: got %v, want %v", want.Currency, res.Currency)
+		if res.Amount.Currency() != want.Amount.Currency() {
+			t.Errorf("wrong expected currency: got %v, want %v", want.Amount.Currency(), res.Amount.Currency())
 		}
 
-		if res.Amount.Cmp(&want.Amount) != 0 {
+		if res.Amount.Amount().Cmp(want.Amount.Amount()) != 0 {
 			t.Errorf("wrong expected amount: got %v, want %v", want.Amount, res.Amount)
 		}
 
@@ -300,10 +581,9 @@ func TestInMemRepository_ListPaymentPlansByUserID(t *testing.T) {
 	)
 
 	existingPlan, err := repo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
-		UserID:   userUUID,
-		Currency: "usdc",
-		Amount:   *decimal.New(1098, 2),
-		Status:   "pending",
+		UserID: userUUID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
 	})
 	if err != nil {
 		t.Fatalf("fail to create payment plan: %v", err)
@@ -353,6 +633,89 @@ func TestInMemRepository_ListPaymentPlansByUserID(t *testing.T) {
 	}
 }
 
+func TestInMemRepository_UpdatePaymentPlanStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		repo        = NewInMemRepository()
+		userUUID, _ = uuid.NewV4()
+	)
+
+	existingPlan, err := repo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+		UserID: userUUID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	type args struct {
+		arg *payments.UpdatePlanStatusParams
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "happy path",
+			args: args{
+				arg: &payments.UpdatePlanStatusParams{
+					ID:     existingPlan.ID,
+					Status: "complete",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "no records",
+			args: args{
+				arg: &payments.UpdatePlanStatusParams{
+					ID:     uuid.Must(uuid.NewV4()),
+					Status: "complete",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.UpdatePaymentPlanStatus(context.Background(), tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Status != tt.args.arg.Status {
+				t.Errorf("wrong expected status: got %v, want %v", got.Status, tt.args.arg.Status)
+			}
+
+			plans, err := repo.ListPaymentPlansByUserID(context.Background(), userUUID)
+			if err != nil {
+				t.Fatalf("fail to list payment plans: %v", err)
+			}
+
+			if plans[0].Status != tt.args.arg.Status {
+				t.Errorf("wrong expected persisted status: got %v, want %v", plans[0].Status, tt.args.arg.Status)
+			}
+
+			if existingPlan.Status != "pending" {
+				t.Errorf("previously returned plan was mutated: got %v", existingPlan.Status)
+			}
+		})
+	}
+}
+
 func TestInMemRepository_CreatePaymentInstallment(t *testing.T) {
 	t.Parallel()
 
@@ -365,8 +728,7 @@ func TestInMemRepository_CreatePaymentInstallment(t *testing.T) {
 
 	_, err := repo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
 		PaymentPlanID: planID,
-		Currency:      "usdc",
-		Amount:        *decimal.New(1098, 2),
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 		DueAt:         dueAt,
 		Status:        "pending",
 	})
@@ -389,18 +751,18 @@ func TestInMemRepository_CreatePaymentInstallment(t *testing.T) {
 			args: args{
 				arg: &payments.CreateInstallmentParams{
 					PaymentPlanID: newPlanID,
-					Currency:      "usdc",
-					Amount:        *decimal.New(1098, 2),
+					Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 					DueAt:         dueAt,
 					Status:        "pending",
+					Version:       2,
 				},
 			},
 			want: &payments.Installment{
 				PaymentPlanID: newPlanID,
-				Currency:      "usdc",
-				Amount:        *decimal.New(1098, 2),
+				Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 				DueAt:         dueAt,
 				Status:        "pending",
+				Version:       2,
 			},
 			wantErr: false,
 		},
@@ -409,18 +771,18 @@ func TestInMemRepository_CreatePaymentInstallment(t *testing.T) {
 			args: args{
 				arg: &payments.CreateInstallmentParams{
 					PaymentPlanID: planID,
-					Currency:      "usdc",
-					Amount:        *decimal.New(1098, 2),
+					Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 					DueAt:         dueAt,
 					Status:        "pending",
+					Version:       2,
 				},
 			},
 			want: &payments.Installment{
 				PaymentPlanID: planID,
-				Currency:      "usdc",
-				Amount:        *decimal.New(1098, 2),
+				Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 				DueAt:         dueAt,
 				Status:        "pending",
+				Version:       2,
 			},
 			wantErr: false,
 		},
@@ -445,11 +807,11 @@ func TestInMemRepository_CreatePaymentInstallment(t *testing.T) {
 				t.Errorf("wrong expected user id: got %v, want %v", tt.want.PaymentPlanID, got.PaymentPlanID)
 			}
 
-			if got.Currency != tt.want.Currency {
-				t.Errorf("wrong expected currency: got %v, want %v", tt.want.Currency, got.Currency)
+			if got.Amount.Currency() != tt.want.Amount.Currency() {
+				t.Errorf("wrong expected currency: got %v, want %v", tt.want.Amount.Currency(), got.Amount.Currency())
 			}
 
-			if got.Amount.Cmp(&tt.want.Amount) != 0 {
+			if got.Amount.Amount().Cmp(tt.want.Amount.Amount()) != 0 {
 				t.Errorf("wrong expected amount: got %v, want %v", tt.want.Amount, got.Amount)
 			}
 
@@ -460,6 +822,10 @@ func TestInMemRepository_CreatePaymentInstallment(t *testing.T) {
 			if got.Status != tt.want.Status {
 				t.Errorf("wrong expected status: got %v, want %v", tt.want.Status, got.Status)
 			}
+
+			if got.Version != tt.want.Version {
+				t.Errorf("wrong expected version: got %v, want %v", tt.want.Version, got.Version)
+			}
 		})
 	}
 }
@@ -475,15 +841,13 @@ func TestInMemRepository_CreatePaymentInstallment_Concurrency(t *testing.T) {
 		dueAt, _  = time.Parse(common.TimeFormat, "2021-11-10T23:00:00Z")
 		params    = &payments.CreateInstallmentParams{
 			PaymentPlanID: planID,
-			Currency:      "usdc",
-			Amount:        *decimal.New(1098, 2),
+			Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 			DueAt:         dueAt,
 			Status:        "pending",
 		}
 		want = &payments.Installment{
 			PaymentPlanID: planID,
-			Currency:      "usdc",
-			Amount:        *decimal.New(1098, 2),
+			Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 			DueAt:         dueAt,
 			Status:        "pending",
 		}
@@ -517,11 +881,11 @@ func TestInMemRepository_CreatePaymentInstallment_Concurrency(t *testing.T) {
 			t.Errorf("wrong expected user id: got %v, want %v", want.PaymentPlanID, res.PaymentPlanID)
 		}
 
-		if res.Currency != want.Currency {
-			t.Errorf("wrong expected currency: got %v, want %v", want.Currency, res.Currency)
+		if res.Amount.Currency() != want.Amount.Currency() {
+			t.Errorf("wrong expected currency: got %v, want %v", want.Amount.Currency(), res.Amount.Currency())
 		}
 
-		if res.Amount.Cmp(&want.Amount) != 0 {
+		if res.Amount.Amount().Cmp(want.Amount.Amount()) != 0 {
 			t.Errorf("wrong expected amount: got %v, want %v", want.Amount, res.Amount)
 		}
 
@@ -555,8 +919,7 @@ func TestInMemRepository_ListPaymentInstallmentsByPlanID(t *testing.T) {
 
 	installment, err := repo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
 		PaymentPlanID: planID,
-		Currency:      "usdc",
-		Amount:        *decimal.New(1098, 2),
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 		DueAt:         dueAt,
 		Status:        "pending",
 	})
@@ -610,63 +973,2025 @@ func TestInMemRepository_ListPaymentInstallmentsByPlanID(t *testing.T) {
 	}
 }
 
-func BenchmarkCreatePaymentPlan(b *testing.B) {
-	repo := NewInMemRepository()
-	param := &payments.CreatePlanParams{
-		UserID:   uuid.Must(uuid.NewV4()),
-		Currency: "usdc",
-		Amount:   *decimal.New(1098, 2),
-		Status:   "pending",
-	}
-
-	b.ResetTimer()
-
-	for i := 0; i < b.N; i++ {
-		_, err := repo.CreatePaymentPlan(context.Background(), param)
-		if err != nil {
-			b.Fatalf("err creating payment plan")
-		}
-	}
-}
+func TestInMemRepository_UpdatePaymentInstallmentStatus(t *testing.T) {
+	t.Parallel()
 
-func BenchmarkListPaymentPlansByUserID(b *testing.B) {
-	repo := NewInMemRepository()
-	userID, _ := uuid.NewV4()
+	var (
+		repo      = NewInMemRepository()
+		dueAt, _  = time.Parse(common.TimeFormat, "2021-11-10T23:00:00Z")
+		planID, _ = uuid.NewV4()
+	)
 
-	repo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
-		UserID:   userID,
-		Currency: "usdc",
-		Amount:   *decimal.New(1098, 2),
-		Status:   "pending",
+	installment, err := repo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+		PaymentPlanID: planID,
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         dueAt,
+		Status:        "pending",
 	})
-
-	b.ResetTimer()
-
-	for i := 0; i < b.N; i++ {
-		_, err := repo.ListPaymentPlansByUserID(context.Background(), userID)
-		if err != nil {
-			b.Fatalf("err listing payment plans by user id")
-		}
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
 	}
-}
 
-func BenchmarkCreatePaymentInstallment(b *testing.B) {
-	repo := NewInMemRepository()
-	params := &payments.CreateInstallmentParams{
-		PaymentPlanID: uuid.Must(uuid.NewV4()),
-		Currency:      "usdc",
-		Amount:        *decimal.New(1098, 2),
-		DueAt:         time.Time{},
-		Status:        "pending",
+	type args struct {
+		arg *payments.UpdateInstallmentStatusParams
 	}
 
-	b.ResetTimer()
-
-	for i := 0; i < b.N; i++ {
-		_, err := repo.CreatePaymentInstallment(context.Background(), params)
-		if err != nil {
-			b.Fatalf("err creating payment installment")
-		}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "happy path",
+			args: args{
+				arg: &payments.UpdateInstallmentStatusParams{
+					ID:     installment.ID,
+					Status: "paid",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "no records",
+			args: args{
+				arg: &payments.UpdateInstallmentStatusParams{
+					ID:     uuid.Must(uuid.NewV4()),
+					Status: "paid",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.UpdatePaymentInstallmentStatus(context.Background(), tt.args.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.Status != tt.args.arg.Status {
+				t.Errorf("wrong expected status: got %v, want %v", got.Status, tt.args.arg.Status)
+			}
+
+			installments, err := repo.ListPaymentInstallmentsByPlanID(context.Background(), planID)
+			if err != nil {
+				t.Fatalf("fail to list installments: %v", err)
+			}
+
+			if installments[0].Status != tt.args.arg.Status {
+				t.Errorf("wrong expected persisted status: got %v, want %v", installments[0].Status, tt.args.arg.Status)
+			}
+		})
+	}
+}
+
+func TestInMemRepository_UpdatePaymentInstallmentAmount(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		repo      = NewInMemRepository()
+		dueAt, _  = time.Parse(common.TimeFormat, "2021-11-10T23:00:00Z")
+		planID, _ = uuid.NewV4()
+		amount    = payments.MustNewMoney(decimal.New(500, 2), "usdc")
+	)
+
+	installment, err := repo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+		PaymentPlanID: planID,
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         dueAt,
+		Status:        "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
+	}
+
+	got, err := repo.UpdatePaymentInstallmentAmount(ctx, &payments.UpdateInstallmentAmountParams{
+		ID:     installment.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePaymentInstallmentAmount() error = %v", err)
+	}
+
+	if got.Amount.String() != amount.String() || got.Status != installment.Status {
+		t.Errorf("UpdatePaymentInstallmentAmount() = %v %v, want %v %v", got.Amount, got.Status, amount, installment.Status)
+	}
+
+	installments, err := repo.ListPaymentInstallmentsByPlanID(ctx, planID)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	if installments[0].Amount.String() != amount.String() {
+		t.Errorf("wrong expected persisted amount: got %v, want %v", installments[0].Amount, amount)
+	}
+
+	if _, err := repo.UpdatePaymentInstallmentAmount(ctx, &payments.UpdateInstallmentAmountParams{
+		ID:     uuid.Must(uuid.NewV4()),
+		Amount: amount,
+	}); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("UpdatePaymentInstallmentAmount() of a missing installment error = %v, want %v", err, ErrRecordNotFound)
+	}
+}
+
+func TestInMemRepository_UpdatePaymentInstallmentsStatusDueBefore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		ctx     = context.Background()
+		now     = time.Now().UTC()
+	)
+
+	createPlan := func(status string) *payments.Plan {
+		plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+			UserID: uuid.Must(uuid.NewV4()),
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create payment plan: %v", err)
+		}
+
+		return plan
+	}
+
+	createInstallment := func(planID uuid.UUID, dueAt time.Time, status string) *payments.Installment {
+		installment, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			DueAt:         dueAt,
+			Status:        status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		return installment
+	}
+
+	completePlan := createPlan("complete")
+	otherPlan := createPlan("complete")
+
+	pastInstallment := createInstallment(completePlan.ID, now.Add(-time.Hour), "pending")
+	createInstallment(completePlan.ID, now.Add(time.Hour), "pending")
+	paidInstallment := createInstallment(completePlan.ID, now.Add(-time.Hour), "paid")
+	otherInstallment := createInstallment(otherPlan.ID, now.Add(-time.Hour), "pending")
+
+	arg := &payments.UpdateInstallmentsStatusDueBeforeParams{
+		PlanID:     completePlan.ID,
+		FromStatus: "pending",
+		ToStatus:   "due",
+		DueBefore:  now,
+	}
+
+	errRollback := errors.New("rollback")
+
+	err := memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.UpdatePaymentInstallmentsStatusDueBefore(ctx, arg); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	installments, err := memRepo.ListPaymentInstallmentsByPlanID(ctx, completePlan.ID)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	if installments[0].Status != "pending" {
+		t.Errorf("expected the rolled back installment to be pending, got %v", installments[0].Status)
+	}
+
+	updated, err := memRepo.UpdatePaymentInstallmentsStatusDueBefore(ctx, arg)
+	if err != nil {
+		t.Fatalf("fail to update installments: %v", err)
+	}
+
+	if len(updated) != 1 || updated[0].ID != pastInstallment.ID || updated[0].Status != "due" {
+		t.Fatalf("unexpected updated installments %v", updated)
+	}
+
+	installments, err = memRepo.ListPaymentInstallmentsByPlanID(ctx, completePlan.ID)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	for _, inst := range installments {
+		want := "pending"
+
+		switch inst.ID {
+		case pastInstallment.ID:
+			want = "due"
+		case paidInstallment.ID:
+			want = "paid"
+		}
+
+		if inst.Status != want {
+			t.Errorf("installment %v: got status %v, want %v", inst.ID, inst.Status, want)
+		}
+	}
+
+	otherInstallments, err := memRepo.ListPaymentInstallmentsByPlanID(ctx, otherPlan.ID)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	if otherInstallments[0].ID != otherInstallment.ID || otherInstallments[0].Status != "pending" {
+		t.Errorf("expected the installment of another plan to stay pending, got %v", otherInstallments[0].Status)
+	}
+}
+
+func TestInMemRepository_LockPaymentPlansWithInstallmentsPastDue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		ctx     = context.Background()
+		now     = time.Now().UTC()
+	)
+
+	createPlanWithInstallment := func(planStatus string, dueAt time.Time, status string) *payments.Plan {
+		plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+			UserID: uuid.Must(uuid.NewV4()),
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: planStatus,
+		})
+		if err != nil {
+			t.Fatalf("fail to create payment plan: %v", err)
+		}
+
+		if _, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+			PaymentPlanID: plan.ID,
+			Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			DueAt:         dueAt,
+			Status:        status,
+		}); err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		return plan
+	}
+
+	duePlan := createPlanWithInstallment("complete", now.Add(-time.Hour), "pending")
+	overduePlan := createPlanWithInstallment("complete", now.Add(-3*time.Hour), "due")
+	createPlanWithInstallment("complete", now.Add(-time.Hour), "due")
+	createPlanWithInstallment("complete", now.Add(time.Hour), "pending")
+	createPlanWithInstallment("pending", now.Add(-time.Hour), "pending")
+
+	planIDs, err := memRepo.LockPaymentPlansWithInstallmentsPastDue(ctx, &payments.LockPlansWithInstallmentsPastDueParams{
+		PlanStatus:    "complete",
+		DueBefore:     now,
+		OverdueBefore: now.Add(-2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("fail to lock payment plans: %v", err)
+	}
+
+	want := []uuid.UUID{duePlan.ID, overduePlan.ID}
+	if bytes.Compare(want[0].Bytes(), want[1].Bytes()) > 0 {
+		want[0], want[1] = want[1], want[0]
+	}
+
+	if len(planIDs) != len(want) || planIDs[0] != want[0] || planIDs[1] != want[1] {
+		t.Errorf("got plans %v, want %v", planIDs, want)
+	}
+}
+
+func TestInMemRepository_ListUnpaidPaymentInstallmentsDueBetween(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		ctx     = context.Background()
+		now     = time.Now().UTC()
+	)
+
+	createPlan := func(status string) *payments.Plan {
+		plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+			UserID: uuid.Must(uuid.NewV4()),
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create payment plan: %v", err)
+		}
+
+		return plan
+	}
+
+	createInstallment := func(planID uuid.UUID, dueAt time.Time, status string) *payments.Installment {
+		installment, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			DueAt:         dueAt,
+			Status:        status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		return installment
+	}
+
+	completePlan := createPlan("complete")
+	pendingPlan := createPlan("pending")
+
+	overdueInstallment := createInstallment(completePlan.ID, now.Add(-time.Hour), "overdue")
+	upcomingInstallment := createInstallment(completePlan.ID, now, "pending")
+	createInstallment(completePlan.ID, now.Add(-2*time.Hour), "due")
+	createInstallment(completePlan.ID, now.Add(time.Hour), "pending")
+	createInstallment(completePlan.ID, now.Add(-time.Hour), "paid")
+	createInstallment(pendingPlan.ID, now.Add(-time.Hour), "pending")
+
+	listed, err := memRepo.ListUnpaidPaymentInstallmentsDueBetween(
+		ctx,
+		&payments.ListUnpaidInstallmentsDueBetweenParams{
+			PlanStatus: "complete",
+			DueAfter:   now.Add(-2 * time.Hour),
+			DueBefore:  now,
+		},
+	)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	if len(listed) != 2 || listed[0].ID != overdueInstallment.ID || listed[1].ID != upcomingInstallment.ID {
+		t.Errorf("unexpected listed installments %v", listed)
+	}
+}
+
+func TestInMemRepository_CreateReminderSent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo       = NewInMemRepository()
+		ctx           = context.Background()
+		installmentID = uuid.Must(uuid.NewV4())
+		errRollback   = errors.New("rollback")
+	)
+
+	arg := &reminder.CreateSentParams{InstallmentID: installmentID, Reminder: "upcoming", SentAt: time.Now().UTC()}
+
+	err := memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreateReminderSent(ctx, arg); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	for _, want := range []bool{true, false} {
+		recorded, err := memRepo.CreateReminderSent(ctx, arg)
+		if err != nil {
+			t.Fatalf("fail to create reminder sent: %v", err)
+		}
+
+		if recorded != want {
+			t.Errorf("CreateReminderSent() = %v, want %v", recorded, want)
+		}
+	}
+
+	recorded, err := memRepo.CreateReminderSent(ctx, &reminder.CreateSentParams{
+		InstallmentID: installmentID,
+		Reminder:      "overdue",
+		SentAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("fail to create reminder sent: %v", err)
+	}
+
+	if !recorded {
+		t.Errorf("another reminder of the installment was not recorded")
+	}
+}
+
+func TestInMemRepository_LockPaymentInstallment(t *testing.T) {
+	t.Parallel()
+
+	memRepo := NewInMemRepository()
+
+	installment, err := memRepo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+		PaymentPlanID: uuid.Must(uuid.NewV4()),
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         time.Now().UTC(),
+		Status:        "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
+	}
+
+	got, err := memRepo.LockPaymentInstallment(context.Background(), installment.ID)
+	if err != nil {
+		t.Fatalf("fail to lock installment: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, installment) {
+		t.Errorf("got %v, want %v", got, installment)
+	}
+
+	if _, err := memRepo.LockPaymentInstallment(context.Background(), uuid.Must(uuid.NewV4())); !errors.Is(
+		err, repo.ErrRecordNotFound,
+	) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestInMemRepository_PaymentTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo       = NewInMemRepository()
+		installmentID = uuid.Must(uuid.NewV4())
+		paidAt        = time.Now().UTC()
+	)
+
+	transactions, err := memRepo.ListPaymentTransactionsByInstallmentID(context.Background(), installmentID)
+	if err != nil || len(transactions) != 0 {
+		t.Fatalf("expected no transactions, got %v, err %v", transactions, err)
+	}
+
+	for _, amount := range []int64{40, 60} {
+		transaction, err := memRepo.CreatePaymentTransaction(context.Background(), &payments.CreateTransactionParams{
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(amount, 0), "usdc"),
+			ProcessorReference:   "psp-ref",
+			PaidAt:               paidAt,
+		})
+		if err != nil {
+			t.Fatalf("fail to create transaction: %v", err)
+		}
+
+		if transaction.ID == uuid.Nil || transaction.PaymentInstallmentID != installmentID {
+			t.Errorf("unexpected transaction %v", transaction)
+		}
+	}
+
+	// a rolled back payment is not listed
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreatePaymentTransaction(context.Background(), &payments.CreateTransactionParams{
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(1, 0), "usdc"),
+			ProcessorReference:   "psp-ref",
+			PaidAt:               paidAt,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	transactions, err = memRepo.ListPaymentTransactionsByInstallmentID(context.Background(), installmentID)
+	if err != nil {
+		t.Fatalf("fail to list transactions: %v", err)
+	}
+
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+
+	if transactions[0].Amount.Amount().Cmp(decimal.New(40, 0)) != 0 || transactions[1].Amount.Amount().Cmp(decimal.New(60, 0)) != 0 {
+		t.Errorf("unexpected transactions %v", transactions)
+	}
+}
+
+func TestInMemRepository_ListPaymentInstallmentsByStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		now     = time.Now().UTC()
+	)
+
+	for _, inst := range []struct {
+		dueAt  time.Time
+		status string
+	}{
+		{dueAt: now.Add(-time.Hour), status: "overdue"},
+		{dueAt: now.Add(-2 * time.Hour), status: "overdue"},
+		{dueAt: now.Add(-3 * time.Hour), status: "due"},
+	} {
+		if _, err := memRepo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			DueAt:         inst.dueAt,
+			Status:        inst.status,
+		}); err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+	}
+
+	installments, err := memRepo.ListPaymentInstallmentsByStatus(context.Background(), "overdue")
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	if len(installments) != 2 {
+		t.Fatalf("expected 2 installments, got %d", len(installments))
+	}
+
+	if !installments[0].DueAt.Before(installments[1].DueAt) {
+		t.Errorf("expected installments ordered by due date, got %v", installments)
+	}
+
+	installments, err = memRepo.ListPaymentInstallmentsByStatus(context.Background(), "paid")
+	if err != nil || len(installments) != 0 {
+		t.Errorf("expected no installments, got %v, err %v", installments, err)
+	}
+}
+
+func TestInMemRepository_PaymentLateFees(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo    = NewInMemRepository()
+		planID     = uuid.Must(uuid.NewV4())
+		assessedAt = time.Now().UTC()
+	)
+
+	installments := make([]*payments.Installment, 2)
+
+	for idx := range installments {
+		inst, err := memRepo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			DueAt:         assessedAt,
+			Status:        "overdue",
+		})
+		if err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		installments[idx] = inst
+	}
+
+	lateFees, err := memRepo.ListPaymentLateFeesByInstallmentID(context.Background(), installments[0].ID)
+	if err != nil || len(lateFees) != 0 {
+		t.Fatalf("expected no late fees, got %v, err %v", lateFees, err)
+	}
+
+	// the second installment is charged first
+	for idx, inst := range []*payments.Installment{installments[1], installments[0]} {
+		lateFee, err := memRepo.CreatePaymentLateFee(context.Background(), &payments.CreateLateFeeParams{
+			PaymentInstallmentID: inst.ID,
+			Amount:               payments.MustNewMoney(decimal.New(int64(idx+1), 0), "usdc"),
+			AssessedAt:           assessedAt.Add(time.Duration(idx) * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("fail to create late fee: %v", err)
+		}
+
+		if lateFee.ID == uuid.Nil || lateFee.PaymentInstallmentID != inst.ID {
+			t.Errorf("unexpected late fee %v", lateFee)
+		}
+	}
+
+	// a rolled back late fee is not listed
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreatePaymentLateFee(context.Background(), &payments.CreateLateFeeParams{
+			PaymentInstallmentID: installments[0].ID,
+			Amount:               payments.MustNewMoney(decimal.New(3, 0), "usdc"),
+			AssessedAt:           assessedAt,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	lateFees, err = memRepo.ListPaymentLateFeesByInstallmentID(context.Background(), installments[0].ID)
+	if err != nil {
+		t.Fatalf("fail to list late fees: %v", err)
+	}
+
+	if len(lateFees) != 1 || lateFees[0].Amount.Amount().Cmp(decimal.New(2, 0)) != 0 {
+		t.Errorf("unexpected late fees %v", lateFees)
+	}
+
+	lateFees, err = memRepo.ListPaymentLateFeesByPlanID(context.Background(), planID)
+	if err != nil {
+		t.Fatalf("fail to list late fees: %v", err)
+	}
+
+	if len(lateFees) != 2 ||
+		lateFees[0].PaymentInstallmentID != installments[1].ID ||
+		lateFees[1].PaymentInstallmentID != installments[0].ID {
+		t.Errorf("expected late fees ordered by assessment, got %v", lateFees)
+	}
+}
+
+func TestInMemRepository_ListPaymentPlansByStatusCreatedBefore(t *testing.T) {
+	t.Parallel()
+
+	memRepo := NewInMemRepository()
+	beforeCreation := time.Now().UTC()
+
+	plans := make([]*payments.Plan, 0, 3)
+
+	for _, status := range []string{"pending", "complete", "pending"} {
+		plan, err := memRepo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+			UserID: uuid.Must(uuid.NewV4()),
+			Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status: status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create plan: %v", err)
+		}
+
+		plans = append(plans, plan)
+	}
+
+	got, err := memRepo.ListPaymentPlansByStatusCreatedBefore(context.Background(), &payments.ListPlansByStatusCreatedBeforeParams{
+		Status:        "pending",
+		CreatedBefore: time.Now().UTC().Add(time.Second),
+	})
+	if err != nil {
+		t.Fatalf("fail to list plans: %v", err)
+	}
+
+	if want := []*payments.Plan{plans[0], plans[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the pending plans, oldest first, got %v, want %v", got, want)
+	}
+
+	got, err = memRepo.ListPaymentPlansByStatusCreatedBefore(context.Background(), &payments.ListPlansByStatusCreatedBeforeParams{
+		Status:        "pending",
+		CreatedBefore: beforeCreation,
+	})
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected no plan created before %v, got %v, %v", beforeCreation, got, err)
+	}
+}
+
+func TestInMemRepository_LockPaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	memRepo := NewInMemRepository()
+
+	plan, err := memRepo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create plan: %v", err)
+	}
+
+	got, err := memRepo.LockPaymentPlan(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("fail to lock plan: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, plan) {
+		t.Errorf("got %v, want %v", got, plan)
+	}
+
+	if _, err := memRepo.LockPaymentPlan(context.Background(), uuid.Must(uuid.NewV4())); !errors.Is(
+		err, repo.ErrRecordNotFound,
+	) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestInMemRepository_PaymentRefunds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo    = NewInMemRepository()
+		planID     = uuid.Must(uuid.NewV4())
+		refundedAt = time.Now().UTC()
+	)
+
+	installments := make([]*payments.Installment, 2)
+
+	for idx := range installments {
+		inst, err := memRepo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			DueAt:         refundedAt,
+			Status:        "paid",
+		})
+		if err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		installments[idx] = inst
+	}
+
+	refunds, err := memRepo.ListPaymentRefundsByPlanID(context.Background(), planID)
+	if err != nil || len(refunds) != 0 {
+		t.Fatalf("expected no refunds, got %v, err %v", refunds, err)
+	}
+
+	// the second installment is refunded first
+	for idx, inst := range []*payments.Installment{installments[1], installments[0]} {
+		refund, err := memRepo.CreatePaymentRefund(context.Background(), &payments.CreateRefundParams{
+			PaymentInstallmentID: inst.ID,
+			Amount:               payments.MustNewMoney(decimal.New(int64(idx+1), 0), "usdc"),
+			Reason:               "purchase returned",
+			RefundedAt:           refundedAt.Add(time.Duration(idx) * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("fail to create refund: %v", err)
+		}
+
+		if refund.ID == uuid.Nil || refund.PaymentInstallmentID != inst.ID || refund.Reason != "purchase returned" {
+			t.Errorf("unexpected refund %v", refund)
+		}
+	}
+
+	// a rolled back refund is not listed
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreatePaymentRefund(context.Background(), &payments.CreateRefundParams{
+			PaymentInstallmentID: installments[0].ID,
+			Amount:               payments.MustNewMoney(decimal.New(3, 0), "usdc"),
+			RefundedAt:           refundedAt,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	refunds, err = memRepo.ListPaymentRefundsByPlanID(context.Background(), planID)
+	if err != nil {
+		t.Fatalf("fail to list refunds: %v", err)
+	}
+
+	if len(refunds) != 2 ||
+		refunds[0].PaymentInstallmentID != installments[1].ID ||
+		refunds[1].PaymentInstallmentID != installments[0].ID {
+		t.Errorf("expected refunds ordered by refund date, got %v", refunds)
+	}
+}
+
+func TestInMemRepository_CreatePaymentSettlement(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		paidAt  = time.Now().UTC()
+	)
+
+	settlement, err := memRepo.CreatePaymentSettlement(context.Background(), &payments.CreateSettlementParams{
+		PaymentPlanID:      planID,
+		Amount:             payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+		ProcessorReference: "psp-ref-1",
+		PaidAt:             paidAt,
+	})
+	if err != nil {
+		t.Fatalf("fail to create settlement: %v", err)
+	}
+
+	if settlement.ID == uuid.Nil || settlement.PaymentPlanID != planID ||
+		settlement.ProcessorReference != "psp-ref-1" || !settlement.PaidAt.Equal(paidAt) {
+		t.Errorf("unexpected settlement %v", settlement)
+	}
+
+	// a rolled back settlement is not kept
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreatePaymentSettlement(context.Background(), &payments.CreateSettlementParams{
+			PaymentPlanID:      planID,
+			Amount:             payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			ProcessorReference: "psp-ref-2",
+			PaidAt:             paidAt,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	memRepo.paymentSettlementsLock.RLock()
+	defer memRepo.paymentSettlementsLock.RUnlock()
+
+	if settlements := memRepo.paymentSettlements[planID]; len(settlements) != 1 || settlements[0].ID != settlement.ID {
+		t.Errorf("expected only the committed settlement, got %v", settlements)
+	}
+}
+
+func TestInMemRepository_CreditLines(t *testing.T) {
+	t.Parallel()
+
+	var (
+		memRepo = NewInMemRepository()
+		userID  = uuid.Must(uuid.NewV4())
+		params  = &payments.CreateCreditLineParams{
+			UserID: userID,
+			Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+			Status: "active",
+		}
+	)
+
+	if _, err := memRepo.GetCreditLineByUserID(context.Background(), userID); !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	creditLine, err := memRepo.CreateCreditLine(context.Background(), params)
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	if creditLine.ID == uuid.Nil || creditLine.UserID != userID || !creditLine.Limit.Equal(params.Limit) {
+		t.Errorf("unexpected credit line %v", creditLine)
+	}
+
+	if _, err := memRepo.CreateCreditLine(context.Background(), params); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected duplicate key, got %v", err)
+	}
+
+	got, err := memRepo.GetCreditLineByUserID(context.Background(), userID)
+	if err != nil || got.ID != creditLine.ID {
+		t.Errorf("GetCreditLineByUserID() = %v, %v, want %v", got, err, creditLine)
+	}
+
+	locked, err := memRepo.LockCreditLineByUserID(context.Background(), userID)
+	if err != nil || locked.ID != creditLine.ID {
+		t.Errorf("LockCreditLineByUserID() = %v, %v, want %v", locked, err, creditLine)
+	}
+
+	// a rolled back credit line is not kept
+	errRollback := errors.New("rollback")
+	otherUserID := uuid.Must(uuid.NewV4())
+
+	err = memRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreateCreditLine(context.Background(), &payments.CreateCreditLineParams{
+			UserID: otherUserID,
+			Limit:  payments.MustNewMoney(decimal.New(500, 0), "usdc"),
+			Status: "active",
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	if _, err := memRepo.GetCreditLineByUserID(context.Background(), otherUserID); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected record not found, got %v", err)
+	}
+}
+
+func TestInMemRepository_UpdateCreditLine(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		userID  = uuid.Must(uuid.NewV4())
+	)
+
+	creditLine, err := memRepo.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: userID,
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: "active",
+	})
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	raised, err := memRepo.UpdateCreditLineLimit(ctx, &payments.UpdateCreditLineLimitParams{
+		ID:    creditLine.ID,
+		Limit: payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+	})
+	if err != nil || !raised.Limit.Equal(payments.MustNewMoney(decimal.New(2000, 0), "usdc")) {
+		t.Fatalf("UpdateCreditLineLimit() = %v, %v", raised, err)
+	}
+
+	// a rolled back status change leaves the line as it was
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+			ID:     creditLine.ID,
+			Status: "frozen",
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	got, err := memRepo.GetCreditLineByUserID(ctx, userID)
+	if err != nil || got.Status != "active" || !got.Limit.Equal(raised.Limit) {
+		t.Errorf("GetCreditLineByUserID() = %v, %v, want %v", got, err, raised)
+	}
+
+	if _, err := memRepo.UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+		ID:     uuid.Must(uuid.NewV4()),
+		Status: "frozen",
+	}); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected record not found, got %v", err)
+	}
+}
+
+func TestInMemRepository_CreditLineChanges(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		memRepo      = NewInMemRepository()
+		creditLineID = uuid.Must(uuid.NewV4())
+		params       = &payments.CreateCreditLineChangeParams{
+			CreditLineID: creditLineID,
+			Action:       "freeze",
+			Actor:        "risk-ops",
+			Reason:       "chargeback",
+			Limit:        payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+			Status:       "frozen",
+		}
+	)
+
+	changes, err := memRepo.ListCreditLineChangesByCreditLineID(ctx, creditLineID)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("ListCreditLineChangesByCreditLineID() = %v, %v, want none", changes, err)
+	}
+
+	change, err := memRepo.CreateCreditLineChange(ctx, params)
+	if err != nil {
+		t.Fatalf("fail to create credit line change: %v", err)
+	}
+
+	if change.ID == uuid.Nil || change.Actor != params.Actor || change.Reason != params.Reason {
+		t.Errorf("unexpected credit line change %v", change)
+	}
+
+	// a rolled back change is not kept
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreateCreditLineChange(ctx, params); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	changes, err = memRepo.ListCreditLineChangesByCreditLineID(ctx, creditLineID)
+	if err != nil || len(changes) != 1 || changes[0].ID != change.ID {
+		t.Errorf("ListCreditLineChangesByCreditLineID() = %v, %v, want [%v]", changes, err, change)
+	}
+}
+
+func TestInMemRepository_JournalEntries(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		amount  = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		created = ledger.NewTransfer(planID, ledger.EntryPlanCreated, ledger.AccountUserReceivable,
+			ledger.AccountMerchantPayable, amount)
+	)
+
+	entry, err := memRepo.CreateJournalEntry(ctx, created)
+	if err != nil {
+		t.Fatalf("fail to create journal entry: %v", err)
+	}
+
+	if entry.ID == uuid.Nil || len(entry.Postings) != 2 || entry.Postings[0].ID == uuid.Nil {
+		t.Errorf("unexpected journal entry %v", entry)
+	}
+
+	// an entry which does not balance is refused
+	if _, err := memRepo.CreateJournalEntry(ctx, &ledger.CreateEntryParams{
+		PaymentPlanID: planID,
+		Kind:          ledger.EntryInstallmentPaid,
+		Postings: []ledger.CreatePostingParams{
+			{Account: ledger.AccountCash, Direction: ledger.Debit, Amount: amount},
+		},
+	}); !errors.Is(err, ledger.ErrUnbalancedEntry) {
+		t.Errorf("expected %v, got %v", ledger.ErrUnbalancedEntry, err)
+	}
+
+	// a rolled back entry is not kept
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreateJournalEntry(ctx, created); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	entries, err := memRepo.ListJournalEntriesByPlanID(ctx, planID)
+	if err != nil || len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Errorf("ListJournalEntriesByPlanID() = %v, %v, want [%v]", entries, err, entry)
+	}
+}
+
+func TestInMemRepository_OutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		now     = time.Now().UTC()
+	)
+
+	created, err := memRepo.CreateOutboxEvent(ctx, &outbox.CreateEventParams{
+		PaymentPlanID: planID,
+		Type:          outbox.EventPlanCreated,
+		Payload:       []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("fail to create outbox event: %v", err)
+	}
+
+	completed, err := memRepo.CreateOutboxEvent(ctx, &outbox.CreateEventParams{
+		PaymentPlanID: planID,
+		Type:          outbox.EventPlanCompleted,
+		Payload:       []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("fail to create outbox event: %v", err)
+	}
+
+	// the unpublished events come in the order they were created, up to the limit
+	events, err := memRepo.LockUnpublishedOutboxEvents(ctx, 1)
+	if err != nil || len(events) != 1 || events[0].ID != created.ID {
+		t.Fatalf("LockUnpublishedOutboxEvents() = %v, %v, want [%v]", events, err, created)
+	}
+
+	// a rolled back publication leaves the event unpublished
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.MarkOutboxEventPublished(ctx, &outbox.MarkEventPublishedParams{
+			ID:          created.ID,
+			PublishedAt: now,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	published, err := memRepo.MarkOutboxEventPublished(ctx, &outbox.MarkEventPublishedParams{
+		ID:          created.ID,
+		PublishedAt: now,
+	})
+	if err != nil || published.PublishedAt == nil || !published.PublishedAt.Equal(now) {
+		t.Fatalf("MarkOutboxEventPublished() = %v, %v", published, err)
+	}
+
+	events, err = memRepo.LockUnpublishedOutboxEvents(ctx, 10)
+	if err != nil || len(events) != 1 || events[0].ID != completed.ID {
+		t.Errorf("LockUnpublishedOutboxEvents() = %v, %v, want [%v]", events, err, completed)
+	}
+
+	if _, err := memRepo.MarkOutboxEventPublished(ctx, &outbox.MarkEventPublishedParams{
+		ID:          uuid.Must(uuid.NewV4()),
+		PublishedAt: now,
+	}); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+
+	if _, err := memRepo.MarkOutboxEventFailed(ctx, &outbox.MarkEventFailedParams{
+		ID:       uuid.Must(uuid.NewV4()),
+		FailedAt: now,
+	}); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+}
+
+func TestInMemRepository_LockUnpublishedOutboxEventsAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		planID2 = uuid.Must(uuid.NewV4())
+		now     = time.Now().UTC()
+	)
+
+	createEvent := func(planID uuid.UUID, eventType string) *outbox.Event {
+		event, err := memRepo.CreateOutboxEvent(ctx, &outbox.CreateEventParams{
+			PaymentPlanID: planID,
+			Type:          eventType,
+			Payload:       []byte(`{}`),
+		})
+		if err != nil {
+			t.Fatalf("fail to create outbox event: %v", err)
+		}
+
+		return event
+	}
+
+	created := createEvent(planID, outbox.EventPlanCreated)
+	createEvent(planID, outbox.EventPlanCompleted)
+	created2 := createEvent(planID2, outbox.EventPlanCreated)
+
+	// a rolled back failure does not hold the plan back
+	errRollback := errors.New("rollback")
+
+	err := memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.MarkOutboxEventFailed(ctx, &outbox.MarkEventFailedParams{
+			ID:       created.ID,
+			FailedAt: now,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	events, err := memRepo.LockUnpublishedOutboxEvents(ctx, 10)
+	if err != nil || len(events) != 3 {
+		t.Fatalf("LockUnpublishedOutboxEvents() = %v, %v, want 3 events", events, err)
+	}
+
+	if _, err := memRepo.MarkOutboxEventFailed(ctx, &outbox.MarkEventFailedParams{
+		ID:       created.ID,
+		FailedAt: now,
+	}); err != nil {
+		t.Fatalf("MarkOutboxEventFailed() error = %v", err)
+	}
+
+	// the failed event is read again, the events of its plan after it are not and do not take the limit
+	events, err = memRepo.LockUnpublishedOutboxEvents(ctx, 2)
+	if err != nil || len(events) != 2 || events[0].ID != created.ID || events[1].ID != created2.ID {
+		t.Errorf("LockUnpublishedOutboxEvents() = %v, %v, want [%v %v]", events, err, created, created2)
+	}
+}
+
+func TestInMemRepository_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		memRepo    = NewInMemRepository()
+		merchantID = uuid.Must(uuid.NewV4())
+		eventID    = uuid.Must(uuid.NewV4())
+		now        = time.Now().UTC()
+	)
+
+	endpoint, err := memRepo.CreateWebhookEndpoint(ctx, &webhook.CreateEndpointParams{
+		MerchantID: merchantID,
+		URL:        "https://merchant.example.com/webhooks",
+		Secret:     "whsec_test",
+	})
+	if err != nil {
+		t.Fatalf("fail to create webhook endpoint: %v", err)
+	}
+
+	if got, err := memRepo.GetWebhookEndpointByID(ctx, endpoint.ID); err != nil || got.ID != endpoint.ID {
+		t.Errorf("GetWebhookEndpointByID() = %v, %v, want %v", got, err, endpoint)
+	}
+
+	endpoints, err := memRepo.ListWebhookEndpointsByMerchantID(ctx, merchantID)
+	if err != nil || len(endpoints) != 1 || endpoints[0].ID != endpoint.ID {
+		t.Errorf("ListWebhookEndpointsByMerchantID() = %v, %v, want [%v]", endpoints, err, endpoint)
+	}
+
+	// the later delivery is created first, the due ones come the earliest due first
+	for _, delivery := range []*webhook.CreateDeliveryParams{
+		{EndpointID: endpoint.ID, EventID: uuid.Must(uuid.NewV4()), NextAttemptAt: now.Add(time.Minute)},
+		{EndpointID: endpoint.ID, EventID: eventID, NextAttemptAt: now},
+		{EndpointID: endpoint.ID, EventID: eventID, NextAttemptAt: now},
+	} {
+		if err := memRepo.CreateWebhookDelivery(ctx, delivery); err != nil {
+			t.Fatalf("fail to create webhook delivery: %v", err)
+		}
+	}
+
+	due, err := memRepo.LockDueWebhookDeliveries(ctx, &webhook.ListDueDeliveriesParams{Before: now, Limit: 10})
+	if err != nil || len(due) != 1 || due[0].EventID != eventID {
+		t.Fatalf("LockDueWebhookDeliveries() = %v, %v, want the delivery of %v only once", due, err, eventID)
+	}
+
+	due, err = memRepo.LockDueWebhookDeliveries(ctx, &webhook.ListDueDeliveriesParams{
+		Before: now.Add(time.Minute),
+		Limit:  10,
+	})
+	if err != nil || len(due) != 2 || due[0].EventID != eventID {
+		t.Fatalf("LockDueWebhookDeliveries() = %v, %v, want the 2 deliveries", due, err)
+	}
+
+	// a rolled back update leaves the delivery as it was
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+			ID:            due[0].ID,
+			Status:        webhook.DeliveryStatusDelivered,
+			Attempts:      1,
+			NextAttemptAt: now,
+			DeliveredAt:   &now,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	if got, err := memRepo.LockWebhookDelivery(ctx, due[0].ID); err != nil || got.Status != webhook.DeliveryStatusPending {
+		t.Errorf("LockWebhookDelivery() = %v, %v, want the delivery still pending", got, err)
+	}
+
+	dead, err := memRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+		ID:            due[0].ID,
+		Status:        webhook.DeliveryStatusDead,
+		Attempts:      3,
+		NextAttemptAt: now,
+		LastError:     "dummyErr",
+	})
+	if err != nil || dead.Status != webhook.DeliveryStatusDead || dead.Attempts != 3 || dead.LastError != "dummyErr" {
+		t.Fatalf("UpdateWebhookDelivery() = %v, %v", dead, err)
+	}
+
+	deliveries, err := memRepo.ListWebhookDeliveriesByStatus(ctx, webhook.DeliveryStatusDead)
+	if err != nil || len(deliveries) != 1 || deliveries[0].ID != dead.ID {
+		t.Errorf("ListWebhookDeliveriesByStatus() = %v, %v, want [%v]", deliveries, err, dead)
+	}
+
+	if _, err := memRepo.LockWebhookDelivery(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+
+	if _, err := memRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+		ID: uuid.Must(uuid.NewV4()),
+	}); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+}
+
+func TestInMemRepository_CollectionsCases(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		userID  = uuid.Must(uuid.NewV4())
+		now     = time.Now().UTC()
+	)
+
+	plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "complete",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	installment, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+		PaymentPlanID: plan.ID,
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         now.Add(-30 * 24 * time.Hour),
+		Status:        "overdue",
+	})
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
+	}
+
+	plans, err := memRepo.ListDelinquentPaymentPlans(ctx, 10)
+	if err != nil || len(plans) != 1 || plans[0].ID != plan.ID {
+		t.Fatalf("ListDelinquentPaymentPlans() = %v, %v, want [%v]", plans, err, plan)
+	}
+
+	// a rolled back case is not kept
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+			PaymentPlanID: plan.ID,
+			UserID:        userID,
+			Status:        "open",
+			OpenedAt:      now,
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	if _, err := memRepo.GetActiveCollectionsCaseByPlanID(ctx, plan.ID); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+
+	collectionsCase, err := memRepo.CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+		PaymentPlanID: plan.ID,
+		UserID:        userID,
+		Status:        "open",
+		OpenedAt:      now,
+		NextActionAt:  &now,
+	})
+	if err != nil {
+		t.Fatalf("fail to create collections case: %v", err)
+	}
+
+	if _, err := memRepo.CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+		PaymentPlanID: plan.ID,
+		UserID:        userID,
+		Status:        "open",
+		OpenedAt:      now,
+	}); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected %v, got %v", ErrDuplicateKey, err)
+	}
+
+	if plans, err := memRepo.ListDelinquentPaymentPlans(ctx, 10); err != nil || len(plans) != 0 {
+		t.Errorf("ListDelinquentPaymentPlans() = %v, %v, want none", plans, err)
+	}
+
+	due, err := memRepo.ListDueCollectionsCases(ctx, &collections.ListDueCasesParams{Before: now, Limit: 10})
+	if err != nil || len(due) != 1 || due[0].ID != collectionsCase.ID {
+		t.Fatalf("ListDueCollectionsCases() = %v, %v, want [%v]", due, err, collectionsCase)
+	}
+
+	if settled, err := memRepo.ListSettledCollectionsCases(ctx, 10); err != nil || len(settled) != 0 {
+		t.Errorf("ListSettledCollectionsCases() = %v, %v, want none", settled, err)
+	}
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+			ID:     collectionsCase.ID,
+			Status: "paused",
+			Stage:  "reminder",
+		}); err != nil {
+			return err
+		}
+
+		if _, err := txRepo.CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+			CaseID: collectionsCase.ID,
+			Kind:   collections.EventPaused,
+			Stage:  "reminder",
+			Actor:  "agent@example.com",
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	got, err := memRepo.LockCollectionsCase(ctx, collectionsCase.ID)
+	if err != nil || got.Status != "open" || got.Stage != "" {
+		t.Errorf("LockCollectionsCase() = %v, %v, want the case still open", got, err)
+	}
+
+	if events, err := memRepo.ListCollectionsCaseEventsByCaseID(ctx, collectionsCase.ID); err != nil || len(events) != 0 {
+		t.Errorf("ListCollectionsCaseEventsByCaseID() = %v, %v, want none", events, err)
+	}
+
+	if _, err := memRepo.CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+		CaseID: collectionsCase.ID,
+		Kind:   collections.EventOpened,
+		Actor:  "collections",
+	}); err != nil {
+		t.Fatalf("fail to create collections case event: %v", err)
+	}
+
+	if events, err := memRepo.ListCollectionsCaseEventsByCaseID(ctx, collectionsCase.ID); err != nil || len(events) != 1 {
+		t.Errorf("ListCollectionsCaseEventsByCaseID() = %v, %v, want 1 event", events, err)
+	}
+
+	if _, err := memRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     installment.ID,
+		Status: "paid",
+	}); err != nil {
+		t.Fatalf("fail to pay installment: %v", err)
+	}
+
+	settled, err := memRepo.ListSettledCollectionsCases(ctx, 10)
+	if err != nil || len(settled) != 1 || settled[0].ID != collectionsCase.ID {
+		t.Fatalf("ListSettledCollectionsCases() = %v, %v, want [%v]", settled, err, collectionsCase)
+	}
+
+	closed, err := memRepo.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+		ID:       collectionsCase.ID,
+		Status:   "closed",
+		ClosedAt: &now,
+	})
+	if err != nil || closed.Status != "closed" || closed.NextActionAt != nil || closed.ClosedAt == nil {
+		t.Fatalf("UpdateCollectionsCase() = %v, %v", closed, err)
+	}
+
+	cases, err := memRepo.ListCollectionsCasesByStatus(ctx, "closed")
+	if err != nil || len(cases) != 1 || cases[0].ID != collectionsCase.ID {
+		t.Errorf("ListCollectionsCasesByStatus() = %v, %v, want [%v]", cases, err, closed)
+	}
+
+	if _, err := memRepo.GetCollectionsCaseByID(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+
+	if _, err := memRepo.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+		ID: uuid.Must(uuid.NewV4()),
+	}); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", ErrRecordNotFound, err)
+	}
+}
+
+func TestInMemRepository_GetUserOutstandingAmount(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		userID  = uuid.Must(uuid.NewV4())
+	)
+
+	plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(300, 0), "usdc"),
+		Status: "complete",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	installments := make(map[string]*payments.Installment)
+
+	for _, status := range []string{"paid", "due", "overdue", "void"} {
+		inst, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+			PaymentPlanID: plan.ID,
+			Amount:        payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			DueAt:         time.Now().UTC(),
+			Status:        status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		installments[status] = inst
+	}
+
+	if _, err := memRepo.CreatePaymentLateFee(ctx, &payments.CreateLateFeeParams{
+		PaymentInstallmentID: installments["overdue"].ID,
+		Amount:               payments.MustNewMoney(decimal.New(5, 0), "usdc"),
+		AssessedAt:           time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("fail to create late fee: %v", err)
+	}
+
+	if _, err := memRepo.CreatePaymentTransaction(ctx, &payments.CreateTransactionParams{
+		PaymentInstallmentID: installments["due"].ID,
+		Amount:               payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+		ProcessorReference:   "psp-ref-1",
+		PaidAt:               time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("fail to create transaction: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params *payments.UserOutstandingAmountParams
+		want   payments.Money
+	}{
+		{
+			name:   "unpaid installments with their late fees and payments",
+			params: &payments.UserOutstandingAmountParams{UserID: userID, Currency: "usdc"},
+			want:   payments.MustNewMoney(decimal.New(165, 0), "usdc"),
+		},
+		{
+			name:   "other currency",
+			params: &payments.UserOutstandingAmountParams{UserID: userID, Currency: "usd"},
+			want:   payments.MustNewMoney(decimal.New(0, 0), "usd"),
+		},
+		{
+			name:   "other user",
+			params: &payments.UserOutstandingAmountParams{UserID: uuid.Must(uuid.NewV4()), Currency: "usdc"},
+			want:   payments.MustNewMoney(decimal.New(0, 0), "usdc"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := memRepo.GetUserOutstandingAmount(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("fail to get outstanding amount: %v", err)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("GetUserOutstandingAmount() = %v %v, want %v %v", got, got.Currency(), tt.want, tt.want.Currency())
+			}
+		})
+	}
+}
+
+func TestInMemRepository_Merchants(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		memRepo    = NewInMemRepository()
+		merchantID = uuid.Must(uuid.NewV4())
+	)
+
+	merchant, err := memRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"})
+	if err != nil {
+		t.Fatalf("fail to create merchant: %v", err)
+	}
+
+	if merchant.ID != merchantID || merchant.Name != "Acme" {
+		t.Errorf("unexpected merchant %v", merchant)
+	}
+
+	if _, err := memRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"}); err != ErrDuplicateKey {
+		t.Errorf("expected a duplicate key error, got %v", err)
+	}
+
+	got, err := memRepo.GetMerchantByID(ctx, merchantID)
+	if err != nil || !reflect.DeepEqual(got, merchant) {
+		t.Errorf("GetMerchantByID() = %v, %v, want %v", got, err, merchant)
+	}
+
+	if _, err := memRepo.GetMerchantByID(ctx, uuid.Must(uuid.NewV4())); err != ErrRecordNotFound {
+		t.Errorf("expected a record not found error, got %v", err)
+	}
+
+	var rolledBack *payments.Merchant
+
+	_ = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		rolledBack, err = txRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{Name: "Rolled back"})
+		if err != nil {
+			t.Fatalf("fail to create merchant: %v", err)
+		}
+
+		return errors.New("rollback")
+	})
+
+	if _, err := memRepo.GetMerchantByID(ctx, rolledBack.ID); err != ErrRecordNotFound {
+		t.Errorf("expected the merchant to be rolled back, got %v", err)
+	}
+}
+
+func TestInMemRepository_ListPaymentPlansByMerchantID(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		memRepo    = NewInMemRepository()
+		merchantID = uuid.Must(uuid.NewV4())
+	)
+
+	plans := make([]*payments.Plan, 0, 3)
+
+	for _, merchant := range []uuid.UUID{merchantID, uuid.Must(uuid.NewV4()), merchantID, merchantID} {
+		plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+			UserID:     uuid.Must(uuid.NewV4()),
+			MerchantID: merchant,
+			Amount:     payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status:     "pending",
+		})
+		if err != nil {
+			t.Fatalf("fail to create plan: %v", err)
+		}
+
+		if merchant == merchantID {
+			plans = append(plans, plan)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		params *payments.ListPlansByMerchantIDParams
+		want   []*payments.Plan
+	}{
+		{
+			name:   "latest first",
+			params: &payments.ListPlansByMerchantIDParams{MerchantID: merchantID, Limit: 10},
+			want:   []*payments.Plan{plans[2], plans[1], plans[0]},
+		},
+		{
+			name:   "oldest first",
+			params: &payments.ListPlansByMerchantIDParams{MerchantID: merchantID, Limit: 10, OldestFirst: true},
+			want:   []*payments.Plan{plans[0], plans[1], plans[2]},
+		},
+		{
+			name:   "page",
+			params: &payments.ListPlansByMerchantIDParams{MerchantID: merchantID, Offset: 1, Limit: 1},
+			want:   []*payments.Plan{plans[1]},
+		},
+		{
+			name:   "past the last page",
+			params: &payments.ListPlansByMerchantIDParams{MerchantID: merchantID, Offset: 3, Limit: 10},
+			want:   []*payments.Plan{},
+		},
+		{
+			name:   "other merchant",
+			params: &payments.ListPlansByMerchantIDParams{MerchantID: uuid.Must(uuid.NewV4()), Limit: 10},
+			want:   []*payments.Plan{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := memRepo.ListPaymentPlansByMerchantID(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("fail to list plans: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListPaymentPlansByMerchantID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInMemRepository_GetPaymentPlanByOrderReference(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		memRepo    = NewInMemRepository()
+		merchantID = uuid.Must(uuid.NewV4())
+		amount     = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+	)
+
+	plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID:         uuid.Must(uuid.NewV4()),
+		MerchantID:     merchantID,
+		Amount:         amount,
+		Status:         "pending",
+		OrderReference: "order-42",
+		Description:    "a chair",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	got, err := memRepo.GetPaymentPlanByOrderReference(ctx, &payments.GetPlanByOrderReferenceParams{
+		MerchantID:     merchantID,
+		OrderReference: "order-42",
+	})
+	if err != nil || !reflect.DeepEqual(got, plan) {
+		t.Errorf("GetPaymentPlanByOrderReference() = %v, %v, want %v", got, err, plan)
+	}
+
+	_, err = memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID:         uuid.Must(uuid.NewV4()),
+		MerchantID:     merchantID,
+		Amount:         amount,
+		Status:         "pending",
+		OrderReference: "order-42",
+	})
+
+	var duplicateKey repo.DuplicateKeyError
+	if !errors.As(err, &duplicateKey) || duplicateKey.Constraint != repo.ConstraintPaymentPlansMerchantOrderRefIdx ||
+		!errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected a duplicate key error on the order reference, got %v", err)
+	}
+
+	// the order references of merchants are independent and a plan may have none
+	for _, arg := range []*payments.CreatePlanParams{
+		{UserID: uuid.Must(uuid.NewV4()), MerchantID: uuid.Must(uuid.NewV4()), Amount: amount, OrderReference: "order-42"},
+		{UserID: uuid.Must(uuid.NewV4()), MerchantID: merchantID, Amount: amount},
+		{UserID: uuid.Must(uuid.NewV4()), MerchantID: merchantID, Amount: amount},
+	} {
+		if _, err := memRepo.CreatePaymentPlan(ctx, arg); err != nil {
+			t.Errorf("fail to create payment plan: %v", err)
+		}
+	}
+
+	if _, err := memRepo.GetPaymentPlanByOrderReference(ctx, &payments.GetPlanByOrderReferenceParams{
+		MerchantID:     merchantID,
+		OrderReference: "order-43",
+	}); err != ErrRecordNotFound {
+		t.Errorf("expected a record not found error, got %v", err)
+	}
+}
+
+func TestInMemRepository_PaymentPlanLineItems(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+	)
+
+	lineItems, err := memRepo.ListPaymentPlanLineItemsByPlanID(ctx, planID)
+	if err != nil || len(lineItems) != 0 {
+		t.Fatalf("expected no line items, got %v, err %v", lineItems, err)
+	}
+
+	// the second line item is created first
+	for _, position := range []int32{1, 0} {
+		lineItem, err := memRepo.CreatePaymentPlanLineItem(ctx, &payments.CreateLineItemParams{
+			PaymentPlanID: planID,
+			Position:      position,
+			SKU:           fmt.Sprintf("SKU-%d", position),
+			Name:          "Chair",
+			Quantity:      position + 1,
+			UnitPrice:     payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+		})
+		if err != nil {
+			t.Fatalf("fail to create line item: %v", err)
+		}
+
+		if lineItem.ID == uuid.Nil || lineItem.PaymentPlanID != planID || lineItem.Position != position {
+			t.Errorf("unexpected line item %v", lineItem)
+		}
+	}
+
+	if _, err := memRepo.CreatePaymentPlanLineItem(ctx, &payments.CreateLineItemParams{
+		PaymentPlanID: planID,
+		Position:      1,
+		Name:          "Table",
+		Quantity:      1,
+		UnitPrice:     payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+	}); err != ErrDuplicateKey {
+		t.Errorf("expected a duplicate key error, got %v", err)
+	}
+
+	// a rolled back line item is not listed
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := txRepo.CreatePaymentPlanLineItem(ctx, &payments.CreateLineItemParams{
+			PaymentPlanID: planID,
+			Position:      2,
+			Name:          "Table",
+			Quantity:      1,
+			UnitPrice:     payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+		}); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	lineItems, err = memRepo.ListPaymentPlanLineItemsByPlanID(ctx, planID)
+	if err != nil {
+		t.Fatalf("fail to list line items: %v", err)
+	}
+
+	if len(lineItems) != 2 || lineItems[0].SKU != "SKU-0" || lineItems[1].SKU != "SKU-1" {
+		t.Errorf("expected line items ordered by position, got %v", lineItems)
+	}
+}
+
+func TestInMemRepository_GetMerchantTotals(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		memRepo    = NewInMemRepository()
+		merchantID = uuid.Must(uuid.NewV4())
+		from       = time.Now().UTC()
+		usdc       = func(amount int64) payments.Money { return payments.MustNewMoney(decimal.New(amount, 0), "usdc") }
+	)
+
+	for _, status := range []string{"complete", "refunded", "pending", "cancelled"} {
+		plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+			UserID:     uuid.Must(uuid.NewV4()),
+			MerchantID: merchantID,
+			Amount:     payments.MustNewMoney(decimal.New(200, 0), "usdc"),
+			Status:     status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create plan: %v", err)
+		}
+
+		installments := make(map[string]*payments.Installment)
+
+		for _, instStatus := range []string{"paid", "overdue"} {
+			inst, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+				PaymentPlanID: plan.ID,
+				Amount:        payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				DueAt:         time.Now().UTC(),
+				Status:        instStatus,
+			})
+			if err != nil {
+				t.Fatalf("fail to create installment: %v", err)
+			}
+
+			installments[instStatus] = inst
+		}
+
+		if _, err := memRepo.CreatePaymentLateFee(ctx, &payments.CreateLateFeeParams{
+			PaymentInstallmentID: installments["overdue"].ID,
+			Amount:               payments.MustNewMoney(decimal.New(5, 0), "usdc"),
+			AssessedAt:           time.Now().UTC(),
+		}); err != nil {
+			t.Fatalf("fail to create late fee: %v", err)
+		}
+
+		if _, err := memRepo.CreatePaymentTransaction(ctx, &payments.CreateTransactionParams{
+			PaymentInstallmentID: installments["overdue"].ID,
+			Amount:               payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+			ProcessorReference:   "psp-ref-1",
+			PaidAt:               time.Now().UTC(),
+		}); err != nil {
+			t.Fatalf("fail to create transaction: %v", err)
+		}
+
+		// 30 given back of which 5 of late fees, and 20 the user no longer owes
+		for _, entry := range []*ledger.CreateEntryParams{
+			{
+				PaymentPlanID: plan.ID,
+				Kind:          ledger.EntryRefunded,
+				Postings: []ledger.CreatePostingParams{
+					{Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: usdc(25)},
+					{Account: ledger.AccountFeeIncome, Direction: ledger.Debit, Amount: usdc(5)},
+					{Account: ledger.AccountCash, Direction: ledger.Credit, Amount: usdc(30)},
+				},
+			},
+			{
+				PaymentPlanID: plan.ID,
+				Kind:          ledger.EntryRefunded,
+				Postings: []ledger.CreatePostingParams{
+					{Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: usdc(20)},
+					{Account: ledger.AccountUserReceivable, Direction: ledger.Credit, Amount: usdc(20)},
+				},
+			},
+		} {
+			if _, err := memRepo.CreateJournalEntry(ctx, entry); err != nil {
+				t.Fatalf("fail to create journal entry: %v", err)
+			}
+		}
+	}
+
+	to := time.Now().UTC().Add(time.Second)
+
+	tests := []struct {
+		name   string
+		params *payments.MerchantTotalsParams
+		want   []*payments.MerchantTotals
+	}{
+		{
+			name:   "complete and refunded plans",
+			params: &payments.MerchantTotalsParams{MerchantID: merchantID, CreatedFrom: from, CreatedTo: to},
+			want: []*payments.MerchantTotals{
+				{
+					Originated:  payments.MustNewMoney(decimal.New(400, 0), "usdc"),
+					Outstanding: payments.MustNewMoney(decimal.New(130, 0), "usdc"),
+					Refunded:    payments.MustNewMoney(decimal.New(90, 0), "usdc"),
+				},
+			},
+		},
+		{
+			name:   "no plan created in the range",
+			params: &payments.MerchantTotalsParams{MerchantID: merchantID, CreatedFrom: to, CreatedTo: to.Add(time.Hour)},
+			want:   []*payments.MerchantTotals{},
+		},
+		{
+			name:   "other merchant",
+			params: &payments.MerchantTotalsParams{MerchantID: uuid.Must(uuid.NewV4()), CreatedFrom: from, CreatedTo: to},
+			want:   []*payments.MerchantTotals{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := memRepo.GetMerchantTotals(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("fail to get merchant totals: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetMerchantTotals() = %v, want %v", got, tt.want)
+			}
+
+			for idx := range got {
+				if !got[idx].Originated.Equal(tt.want[idx].Originated) ||
+					!got[idx].Outstanding.Equal(tt.want[idx].Outstanding) ||
+					!got[idx].Refunded.Equal(tt.want[idx].Refunded) {
+					t.Errorf("GetMerchantTotals() = %v, want %v", got[idx], tt.want[idx])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCreatePaymentPlan(b *testing.B) {
+	repo := NewInMemRepository()
+	param := &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := repo.CreatePaymentPlan(context.Background(), param)
+		if err != nil {
+			b.Fatalf("err creating payment plan")
+		}
+	}
+}
+
+func BenchmarkListPaymentPlansByUserID(b *testing.B) {
+	repo := NewInMemRepository()
+	userID, _ := uuid.NewV4()
+
+	repo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := repo.ListPaymentPlansByUserID(context.Background(), userID)
+		if err != nil {
+			b.Fatalf("err listing payment plans by user id")
+		}
+	}
+}
+
+func BenchmarkCreatePaymentInstallment(b *testing.B) {
+	repo := NewInMemRepository()
+	params := &payments.CreateInstallmentParams{
+		PaymentPlanID: uuid.Must(uuid.NewV4()),
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         time.Time{},
+		Status:        "pending",
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := repo.CreatePaymentInstallment(context.Background(), params)
+		if err != nil {
+			b.Fatalf("err creating payment installment")
+		}
 	}
 }
 
@@ -676,8 +3001,7 @@ func BenchmarkListPaymentInstallmentsByPlanID(b *testing.B) {
 
 	repo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
 		PaymentPlanID: planID,
-		Currency:      "usdc",
-		Amount:        *decimal.New(1098, 2),
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 		DueAt:         time.Time{},
 		Status:        "pending",
 	})
@@ -691,3 +3015,94 @@ func BenchmarkListPaymentInstallmentsByPlanID(b *testing.B) {
 		}
 	}
 }
+
+func TestInMemRepository_AuditEntries(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		memRepo = NewInMemRepository()
+		planID  = uuid.Must(uuid.NewV4())
+		amount  = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+	)
+
+	if _, err := memRepo.GetLatestAuditEntryByPlanID(ctx, planID); err != repo.ErrRecordNotFound {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	appendEntry := func(txRepo repo.Repository, status string) (*audit.Entry, error) {
+		var prev *audit.Entry
+
+		latest, err := txRepo.GetLatestAuditEntryByPlanID(ctx, planID)
+		if err == nil {
+			prev = latest
+		} else if err != repo.ErrRecordNotFound {
+			return nil, err
+		}
+
+		params, err := audit.NewEntry(prev, &audit.Change{
+			PaymentPlanID: planID,
+			Actor:         audit.InternalActor("checkout"),
+			Action:        audit.ActionPlanCreated,
+			After:         &audit.Snapshot{Plan: &audit.PlanState{Status: status, Amount: amount}},
+			OccurredAt:    time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return txRepo.CreateAuditEntry(ctx, params)
+	}
+
+	for _, status := range []string{"pending", "complete"} {
+		entry, err := appendEntry(memRepo, status)
+		if err != nil {
+			t.Fatalf("fail to create audit entry: %v", err)
+		}
+
+		if entry.ID == uuid.Nil || entry.PaymentPlanID != planID || entry.After.Plan.Status != status {
+			t.Errorf("unexpected audit entry %v", entry)
+		}
+	}
+
+	latest, err := memRepo.GetLatestAuditEntryByPlanID(ctx, planID)
+	if err != nil || latest.Sequence != 2 {
+		t.Fatalf("expected the second entry to be the latest, got %v, err %v", latest, err)
+	}
+
+	// an entry forking the chain is refused
+	if _, err := memRepo.CreateAuditEntry(ctx, &audit.CreateEntryParams{
+		PaymentPlanID: planID,
+		Sequence:      2,
+		Action:        audit.ActionPlanCancelled,
+	}); err != ErrDuplicateKey {
+		t.Errorf("expected a duplicate key error, got %v", err)
+	}
+
+	// a rolled back entry is not listed
+	errRollback := errors.New("rollback")
+
+	err = memRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		if _, err := appendEntry(txRepo, "refunded"); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	entries, err := memRepo.ListAuditEntriesByPlanID(ctx, planID)
+	if err != nil {
+		t.Fatalf("fail to list audit entries: %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Sequence != 1 || entries[1].Sequence != 2 {
+		t.Fatalf("expected audit entries ordered by sequence, got %v", entries)
+	}
+
+	if err := audit.Verify(entries); err != nil {
+		t.Errorf("expected an intact audit chain, got %v", err)
+	}
+}
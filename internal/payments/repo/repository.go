@@ -4,14 +4,168 @@ import (
 	"context"
 
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/collections"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/webhook"
 
 	"github.com/gofrs/uuid"
 )
 
 //go:generate mockgen -source=./repository.go -destination=../mock/repomock/mockrepository.go -package=repomock
 type Repository interface {
+	// WithTx runs fn as a single unit of work, every call made on txRepo is
+	// committed when fn returns nil and rolled back when it returns an error
+	WithTx(ctx context.Context, fn func(txRepo Repository) error) error
+
 	CreatePaymentPlan(ctx context.Context, arg *payments.CreatePlanParams) (*payments.Plan, error)
+	GetPaymentPlanByID(ctx context.Context, id uuid.UUID) (*payments.Plan, error)
+	// LockPaymentPlan reads a plan and keeps concurrent units of work
+	// from changing it until the current one ends
+	LockPaymentPlan(ctx context.Context, id uuid.UUID) (*payments.Plan, error)
 	ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*payments.Plan, error)
+	// LockPaymentPlansWithInstallmentsPastDue locks the plans with installments past due in the order of their ids
+	// and lists their ids, concurrent units of work cannot change them until the current one ends
+	LockPaymentPlansWithInstallmentsPastDue(
+		ctx context.Context,
+		arg *payments.LockPlansWithInstallmentsPastDueParams,
+	) ([]uuid.UUID, error)
+	// ListPaymentPlansByStatusCreatedBefore lists the oldest plans first
+	ListPaymentPlansByStatusCreatedBefore(
+		ctx context.Context,
+		arg *payments.ListPlansByStatusCreatedBeforeParams,
+	) ([]*payments.Plan, error)
+	// ListPaymentPlansByMerchantID lists a page of the plans of a merchant, the latest first unless OldestFirst is set
+	ListPaymentPlansByMerchantID(
+		ctx context.Context,
+		arg *payments.ListPlansByMerchantIDParams,
+	) ([]*payments.Plan, error)
+	// GetPaymentPlanByOrderReference reads the plan paying for an order of a merchant,
+	// a merchant has at most one plan per order reference
+	GetPaymentPlanByOrderReference(
+		ctx context.Context,
+		arg *payments.GetPlanByOrderReferenceParams,
+	) (*payments.Plan, error)
+	UpdatePaymentPlanStatus(ctx context.Context, arg *payments.UpdatePlanStatusParams) (*payments.Plan, error)
 	CreatePaymentInstallment(ctx context.Context, arg *payments.CreateInstallmentParams) (*payments.Installment, error)
 	ListPaymentInstallmentsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.Installment, error)
+	ListPaymentInstallmentsByStatus(ctx context.Context, status string) ([]*payments.Installment, error)
+	UpdatePaymentInstallmentStatus(
+		ctx context.Context,
+		arg *payments.UpdateInstallmentStatusParams,
+	) (*payments.Installment, error)
+	UpdatePaymentInstallmentAmount(
+		ctx context.Context,
+		arg *payments.UpdateInstallmentAmountParams,
+	) (*payments.Installment, error)
+	UpdatePaymentInstallmentsStatusDueBefore(
+		ctx context.Context,
+		arg *payments.UpdateInstallmentsStatusDueBeforeParams,
+	) ([]*payments.Installment, error)
+	// ListUnpaidPaymentInstallmentsDueBetween lists the pending, due and overdue installments, the earliest due first
+	ListUnpaidPaymentInstallmentsDueBetween(
+		ctx context.Context,
+		arg *payments.ListUnpaidInstallmentsDueBetweenParams,
+	) ([]*payments.Installment, error)
+	// LockPaymentInstallment reads an installment and keeps concurrent units of work
+	// from changing it until the current one ends
+	LockPaymentInstallment(ctx context.Context, id uuid.UUID) (*payments.Installment, error)
+	CreatePaymentTransaction(ctx context.Context, arg *payments.CreateTransactionParams) (*payments.Transaction, error)
+	ListPaymentTransactionsByInstallmentID(
+		ctx context.Context,
+		installmentID uuid.UUID,
+	) ([]*payments.Transaction, error)
+	CreatePaymentLateFee(ctx context.Context, arg *payments.CreateLateFeeParams) (*payments.LateFee, error)
+	ListPaymentLateFeesByInstallmentID(ctx context.Context, installmentID uuid.UUID) ([]*payments.LateFee, error)
+	ListPaymentLateFeesByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.LateFee, error)
+	CreatePaymentRefund(ctx context.Context, arg *payments.CreateRefundParams) (*payments.Refund, error)
+	ListPaymentRefundsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.Refund, error)
+	CreatePaymentSettlement(ctx context.Context, arg *payments.CreateSettlementParams) (*payments.Settlement, error)
+	CreateCreditLine(ctx context.Context, arg *payments.CreateCreditLineParams) (*payments.CreditLine, error)
+	GetCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error)
+	// LockCreditLineByUserID reads the credit line of a user and keeps concurrent units of work
+	// from locking it until the current one ends
+	LockCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error)
+	UpdateCreditLineLimit(ctx context.Context, arg *payments.UpdateCreditLineLimitParams) (*payments.CreditLine, error)
+	UpdateCreditLineStatus(ctx context.Context, arg *payments.UpdateCreditLineStatusParams) (*payments.CreditLine, error)
+	CreateCreditLineChange(
+		ctx context.Context,
+		arg *payments.CreateCreditLineChangeParams,
+	) (*payments.CreditLineChange, error)
+	// ListCreditLineChangesByCreditLineID lists the oldest changes first
+	ListCreditLineChangesByCreditLineID(
+		ctx context.Context,
+		creditLineID uuid.UUID,
+	) ([]*payments.CreditLineChange, error)
+	// CreateJournalEntry appends an entry and its postings to the ledger, an entry which does not balance is refused
+	CreateJournalEntry(ctx context.Context, arg *ledger.CreateEntryParams) (*ledger.Entry, error)
+	// ListJournalEntriesByPlanID lists the entries of a plan in the order they were created
+	ListJournalEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*ledger.Entry, error)
+	CreateOutboxEvent(ctx context.Context, arg *outbox.CreateEventParams) (*outbox.Event, error)
+	// LockUnpublishedOutboxEvents reads up to limit events not published yet, the oldest first, leaving out the
+	// events of a plan which come after one that failed to publish, and keeps concurrent units of work from
+	// reading them until the current one ends
+	LockUnpublishedOutboxEvents(ctx context.Context, limit int32) ([]*outbox.Event, error)
+	MarkOutboxEventPublished(ctx context.Context, arg *outbox.MarkEventPublishedParams) (*outbox.Event, error)
+	MarkOutboxEventFailed(ctx context.Context, arg *outbox.MarkEventFailedParams) (*outbox.Event, error)
+	CreateWebhookEndpoint(ctx context.Context, arg *webhook.CreateEndpointParams) (*webhook.Endpoint, error)
+	GetWebhookEndpointByID(ctx context.Context, id uuid.UUID) (*webhook.Endpoint, error)
+	// ListWebhookEndpointsByMerchantID lists the oldest endpoints first
+	ListWebhookEndpointsByMerchantID(ctx context.Context, merchantID uuid.UUID) ([]*webhook.Endpoint, error)
+	// CreateWebhookDelivery creates a pending delivery, the delivery of an event to an endpoint
+	// is only created once and a second one is ignored
+	CreateWebhookDelivery(ctx context.Context, arg *webhook.CreateDeliveryParams) error
+	// LockWebhookDelivery reads a delivery and keeps concurrent units of work
+	// from changing it until the current one ends
+	LockWebhookDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error)
+	// LockDueWebhookDeliveries reads the pending deliveries due first and locks them, the ones
+	// locked by a concurrent unit of work are skipped
+	LockDueWebhookDeliveries(ctx context.Context, arg *webhook.ListDueDeliveriesParams) ([]*webhook.Delivery, error)
+	// ListWebhookDeliveriesByStatus lists the latest deliveries first
+	ListWebhookDeliveriesByStatus(ctx context.Context, status string) ([]*webhook.Delivery, error)
+	UpdateWebhookDelivery(ctx context.Context, arg *webhook.UpdateDeliveryParams) (*webhook.Delivery, error)
+	// CreateReminderSent records that the reminder of an installment was sent, it reports false
+	// when it was already recorded and the reminder must not be sent again
+	CreateReminderSent(ctx context.Context, arg *reminder.CreateSentParams) (bool, error)
+	// ListDelinquentPaymentPlans lists up to limit complete plans with an overdue installment
+	// and no collections case which is not closed, the oldest plans first
+	ListDelinquentPaymentPlans(ctx context.Context, limit int32) ([]*payments.Plan, error)
+	CreateCollectionsCase(ctx context.Context, arg *collections.CreateCaseParams) (*collections.Case, error)
+	GetCollectionsCaseByID(ctx context.Context, id uuid.UUID) (*collections.Case, error)
+	// GetActiveCollectionsCaseByPlanID reads the case of a plan which is not closed, a plan has one at most
+	GetActiveCollectionsCaseByPlanID(ctx context.Context, planID uuid.UUID) (*collections.Case, error)
+	// LockCollectionsCase reads a case and keeps concurrent units of work
+	// from changing it until the current one ends
+	LockCollectionsCase(ctx context.Context, id uuid.UUID) (*collections.Case, error)
+	// ListDueCollectionsCases lists up to Limit open cases to escalate, the ones due first
+	ListDueCollectionsCases(ctx context.Context, arg *collections.ListDueCasesParams) ([]*collections.Case, error)
+	// ListSettledCollectionsCases lists up to limit cases which are not closed and whose plan
+	// has no overdue installment left, the oldest cases first
+	ListSettledCollectionsCases(ctx context.Context, limit int32) ([]*collections.Case, error)
+	// ListCollectionsCasesByStatus lists the latest cases first
+	ListCollectionsCasesByStatus(ctx context.Context, status string) ([]*collections.Case, error)
+	UpdateCollectionsCase(ctx context.Context, arg *collections.UpdateCaseParams) (*collections.Case, error)
+	CreateCollectionsCaseEvent(ctx context.Context, arg *collections.CreateEventParams) (*collections.Event, error)
+	// ListCollectionsCaseEventsByCaseID lists the oldest events first
+	ListCollectionsCaseEventsByCaseID(ctx context.Context, caseID uuid.UUID) ([]*collections.Event, error)
+	// GetUserOutstandingAmount sums what is left to pay on the unpaid installments of the user,
+	// late fees included, zero when nothing is owed
+	GetUserOutstandingAmount(ctx context.Context, arg *payments.UserOutstandingAmountParams) (payments.Money, error)
+	CreateMerchant(ctx context.Context, arg *payments.CreateMerchantParams) (*payments.Merchant, error)
+	GetMerchantByID(ctx context.Context, id uuid.UUID) (*payments.Merchant, error)
+	// GetMerchantTotals sums the complete and refunded plans of a merchant created in the range,
+	// one totals per currency the merchant sold in, none when it sold nothing
+	GetMerchantTotals(ctx context.Context, arg *payments.MerchantTotalsParams) ([]*payments.MerchantTotals, error)
+	CreatePaymentPlanLineItem(ctx context.Context, arg *payments.CreateLineItemParams) (*payments.LineItem, error)
+	// ListPaymentPlanLineItemsByPlanID lists the line items in the order they were given
+	ListPaymentPlanLineItemsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.LineItem, error)
+	// CreateAuditEntry appends an entry to the audit trail of a plan, an entry whose sequence
+	// is already taken is refused and entries are never updated or deleted
+	CreateAuditEntry(ctx context.Context, arg *audit.CreateEntryParams) (*audit.Entry, error)
+	// GetLatestAuditEntryByPlanID returns ErrRecordNotFound when the plan has no entry yet
+	GetLatestAuditEntryByPlanID(ctx context.Context, planID uuid.UUID) (*audit.Entry, error)
+	// ListAuditEntriesByPlanID lists the entries of a plan in sequence order
+	ListAuditEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*audit.Entry, error)
 }
@@ -17,16 +17,14 @@ var (
 	testBenchAmount                 = decimal.New(1098, 2)
 	testBenchUserID                 = uuid.Must(uuid.NewV4())
 	testBenchCreatePaymentPlanParam = &payments.CreatePlanParams{
-		UserID:   testBenchUserID,
-		Currency: "usdc",
-		Amount:   *testBenchAmount,
-		Status:   "pending",
+		UserID: testBenchUserID,
+		Amount: payments.MustNewMoney(testBenchAmount, "usdc"),
+		Status: "pending",
 	}
 	testBenchRefPaymentPlan = &payments.Plan{
 		ID:        uuid.UUID{},
 		UserID:    uuid.UUID{},
-		Currency:  "",
-		Amount:    decimal.Big{},
+		Amount:    payments.Money{},
 		Status:    "",
 		CreatedAt: time.Time{},
 		UpdatedAt: time.Time{},
@@ -56,10 +54,10 @@ func BenchmarkCreatePaymentInstallment(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, err := testRefRepo.CreatePaymentInstallment(testBenchCtx, &payments.CreateInstallmentParams{
 			PaymentPlanID: testBenchRefPaymentPlan.ID,
-			Currency:      "usdc",
-			Amount:        *testBenchAmount,
+			Amount:        payments.MustNewMoney(testBenchAmount, "usdc"),
 			DueAt:         time.Time{},
 			Status:        "pending",
+			Version:       1,
 		})
 		if err != nil {
 			b.Fatalf("err creating payment installment")
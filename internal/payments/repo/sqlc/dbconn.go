@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"golangreferenceapi/internal/api/configuration"
-	"golangreferenceapi/internal/db"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/monacohq/golang-common/database/pginit"
@@ -52,5 +51,5 @@ func NewRepo(ctx context.Context, cfg *configuration.Database) (*Repo, error) {
 		return &Repo{}, fmt.Errorf("failed to init pgx: %w", err)
 	}
 
-	return NewSQLCRepository(db.New(pool)), nil
+	return NewSQLCRepository(pool), nil
 }
@@ -5,3 +5,6 @@ type UnsupportedDBEntityError struct{}
 func (e UnsupportedDBEntityError) Error() string {
 	return "DB entity interface does not match any supported struct"
 }
+
+// pgUniqueViolation is the SQLSTATE of a unique constraint violation
+const pgUniqueViolation = "23505"
@@ -2,35 +2,128 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"golangreferenceapi/internal/db"
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/collections"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/webhook"
 
+	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 )
 
+var _ repo.Repository = (*Repo)(nil)
+
+// DBConn is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx,
+// beginning a transaction from a pgx.Tx creates a savepoint
+type DBConn interface {
+	db.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type Repo struct {
+	conn    DBConn
 	querier db.Querier
 }
 
-func NewSQLCRepository(querier db.Querier) *Repo {
-	return &Repo{querier: querier}
+func NewSQLCRepository(conn DBConn) *Repo {
+	return &Repo{conn: conn, querier: db.New(conn)}
 }
 
-func (impl *Repo) CreatePaymentPlan(ctx context.Context, arg *payments.CreatePlanParams) (*payments.Plan, error) {
-	planID, err := uuid.NewV4()
+func (impl *Repo) WithTx(ctx context.Context, fn func(txRepo repo.Repository) error) error {
+	tx, err := impl.conn.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(NewSQLCRepository(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("failed to rollback transaction (%v): %w", rbErr, err)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePaymentPlan keeps the caller's plan ID so a retried request maps to the same record,
+// one is only generated when none is given
+func (impl *Repo) CreatePaymentPlan(ctx context.Context, arg *payments.CreatePlanParams) (*payments.Plan, error) {
+	planID := arg.ID
+
+	if planID == uuid.Nil {
+		var err error
+
+		planID, err = uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	dbEntity, err := impl.querier.CreatePaymentPlan(ctx, &db.CreatePaymentPlanParams{
-		ID:       planID,
-		UserID:   arg.UserID,
-		Currency: db.Currency(arg.Currency),
-		Amount:   arg.Amount,
-		Status:   db.PaymentStatus(arg.Status),
+		ID:             planID,
+		UserID:         arg.UserID,
+		MerchantID:     uuid.NullUUID{UUID: arg.MerchantID, Valid: arg.MerchantID != uuid.Nil},
+		Currency:       db.Currency(arg.Amount.Currency()),
+		Amount:         *arg.Amount.Amount(),
+		Status:         db.PaymentStatus(arg.Status),
+		OrderReference: newNullString(arg.OrderReference),
+		Description:    newNullString(arg.Description),
 	})
 	if err != nil {
+		return nil, newDuplicateKeyError(err)
+	}
+
+	plan, err := impl.newPlanFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (impl *Repo) GetPaymentPlanByID(ctx context.Context, id uuid.UUID) (*payments.Plan, error) {
+	dbEntity, err := impl.querier.GetPaymentPlanByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	plan, err := impl.newPlanFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (impl *Repo) LockPaymentPlan(ctx context.Context, id uuid.UUID) (*payments.Plan, error) {
+	dbEntity, err := impl.querier.GetPaymentPlanByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
 		return nil, err
 	}
 
@@ -42,6 +135,25 @@ func (impl *Repo) CreatePaymentPlan(ctx context.Context, arg *payments.CreatePla
 	return plan, nil
 }
 
+func (impl *Repo) LockPaymentPlansWithInstallmentsPastDue(
+	ctx context.Context,
+	arg *payments.LockPlansWithInstallmentsPastDueParams,
+) ([]uuid.UUID, error) {
+	planIDs, err := impl.querier.ListPaymentPlanIDsWithInstallmentsPastDueForUpdate(
+		ctx,
+		&db.ListPaymentPlanIDsWithInstallmentsPastDueForUpdateParams{
+			PlanStatus:    db.PaymentStatus(arg.PlanStatus),
+			DueBefore:     arg.DueBefore,
+			OverdueBefore: arg.OverdueBefore,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return planIDs, nil
+}
+
 func (impl *Repo) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID) ([]*payments.Plan, error) {
 	entities, err := impl.querier.ListPaymentPlansByUserID(ctx, userID)
 	if err != nil {
@@ -62,6 +174,127 @@ func (impl *Repo) ListPaymentPlansByUserID(ctx context.Context, userID uuid.UUID
 	return plans, nil
 }
 
+func (impl *Repo) ListPaymentPlansByStatusCreatedBefore(
+	ctx context.Context,
+	arg *payments.ListPlansByStatusCreatedBeforeParams,
+) ([]*payments.Plan, error) {
+	entities, err := impl.querier.ListPaymentPlansByStatusCreatedBefore(ctx, &db.ListPaymentPlansByStatusCreatedBeforeParams{
+		Status:    db.PaymentStatus(arg.Status),
+		CreatedAt: arg.CreatedBefore,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*payments.Plan, len(entities))
+
+	for idx, entity := range entities {
+		plan, err := impl.newPlanFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		plans[idx] = plan
+	}
+
+	return plans, nil
+}
+
+func (impl *Repo) ListPaymentPlansByMerchantID(
+	ctx context.Context,
+	arg *payments.ListPlansByMerchantIDParams,
+) ([]*payments.Plan, error) {
+	merchantID := uuid.NullUUID{UUID: arg.MerchantID, Valid: true}
+
+	var entities []interface{}
+
+	if arg.OldestFirst {
+		rows, err := impl.querier.ListPaymentPlansByMerchantIDOldestFirst(
+			ctx,
+			&db.ListPaymentPlansByMerchantIDOldestFirstParams{MerchantID: merchantID, Limit: arg.Limit, Offset: arg.Offset},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			entities = append(entities, row)
+		}
+	} else {
+		rows, err := impl.querier.ListPaymentPlansByMerchantIDLatestFirst(
+			ctx,
+			&db.ListPaymentPlansByMerchantIDLatestFirstParams{MerchantID: merchantID, Limit: arg.Limit, Offset: arg.Offset},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			entities = append(entities, row)
+		}
+	}
+
+	plans := make([]*payments.Plan, len(entities))
+
+	for idx, entity := range entities {
+		plan, err := impl.newPlanFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		plans[idx] = plan
+	}
+
+	return plans, nil
+}
+
+func (impl *Repo) GetPaymentPlanByOrderReference(
+	ctx context.Context,
+	arg *payments.GetPlanByOrderReferenceParams,
+) (*payments.Plan, error) {
+	dbEntity, err := impl.querier.GetPaymentPlanByMerchantIDOrderReference(
+		ctx,
+		&db.GetPaymentPlanByMerchantIDOrderReferenceParams{
+			MerchantID:     uuid.NullUUID{UUID: arg.MerchantID, Valid: true},
+			OrderReference: newNullString(arg.OrderReference),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	plan, err := impl.newPlanFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (impl *Repo) UpdatePaymentPlanStatus(
+	ctx context.Context,
+	arg *payments.UpdatePlanStatusParams,
+) (*payments.Plan, error) {
+	dbEntity, err := impl.querier.UpdatePaymentPlanStatus(ctx, &db.UpdatePaymentPlanStatusParams{
+		ID:     arg.ID,
+		Status: db.PaymentStatus(arg.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := impl.newPlanFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
 func (impl *Repo) CreatePaymentInstallment(
 	ctx context.Context,
 	arg *payments.CreateInstallmentParams,
@@ -74,10 +307,11 @@ func (impl *Repo) CreatePaymentInstallment(
 	dbEntity, err := impl.querier.CreatePaymentInstallments(ctx, &db.CreatePaymentInstallmentsParams{
 		ID:            installmentID,
 		PaymentPlanID: arg.PaymentPlanID,
-		Currency:      db.Currency(arg.Currency),
-		Amount:        arg.Amount,
+		Currency:      db.Currency(arg.Amount.Currency()),
+		Amount:        *arg.Amount.Amount(),
 		DueAt:         arg.DueAt,
 		Status:        db.PaymentInstallmentStatus(arg.Status),
+		Version:       arg.Version,
 	})
 	if err != nil {
 		return nil, err
@@ -114,91 +348,2662 @@ func (impl *Repo) ListPaymentInstallmentsByPlanID(
 	return installments, nil
 }
 
-func (impl *Repo) newPlanFromDBEntity(entity interface{}) (*payments.Plan, error) {
-	createPaymentPlanRowEntity, valid := entity.(*db.CreatePaymentPlanRow)
-	if valid {
-		return &payments.Plan{
-			ID:        createPaymentPlanRowEntity.ID,
-			UserID:    createPaymentPlanRowEntity.UserID,
-			Currency:  string(createPaymentPlanRowEntity.Currency),
-			Amount:    createPaymentPlanRowEntity.Amount,
-			Status:    string(createPaymentPlanRowEntity.Status),
-			CreatedAt: createPaymentPlanRowEntity.CreatedAt,
-			UpdatedAt: createPaymentPlanRowEntity.UpdatedAt,
-		}, nil
+func (impl *Repo) ListPaymentInstallmentsByStatus(
+	ctx context.Context,
+	status string,
+) ([]*payments.Installment, error) {
+	entities, err := impl.querier.ListPaymentInstallmentsByStatus(ctx, db.PaymentInstallmentStatus(status))
+	if err != nil {
+		return nil, err
 	}
 
-	listPaymentPlansByUserIDRowEntity, valid := entity.(*db.ListPaymentPlansByUserIDRow)
-	if valid {
-		return &payments.Plan{
-			ID:        listPaymentPlansByUserIDRowEntity.ID,
-			UserID:    listPaymentPlansByUserIDRowEntity.UserID,
-			Currency:  string(listPaymentPlansByUserIDRowEntity.Currency),
-			Amount:    listPaymentPlansByUserIDRowEntity.Amount,
-			Status:    string(listPaymentPlansByUserIDRowEntity.Status),
-			CreatedAt: listPaymentPlansByUserIDRowEntity.CreatedAt,
-			UpdatedAt: listPaymentPlansByUserIDRowEntity.UpdatedAt,
-		}, nil
+	installments := make([]*payments.Installment, len(entities))
+
+	for idx, entity := range entities {
+		installment, err := impl.newInstallmentFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		installments[idx] = installment
 	}
 
-	planEntity, valid := entity.(*db.PaymentPlan)
-	if valid {
-		return &payments.Plan{
-			ID:        planEntity.ID,
-			UserID:    planEntity.UserID,
-			Currency:  string(planEntity.Currency),
-			Amount:    planEntity.Amount,
-			Status:    string(planEntity.Status),
-			CreatedAt: planEntity.CreatedAt,
-			UpdatedAt: planEntity.UpdatedAt,
-		}, nil
+	return installments, nil
+}
+
+func (impl *Repo) UpdatePaymentInstallmentStatus(
+	ctx context.Context,
+	arg *payments.UpdateInstallmentStatusParams,
+) (*payments.Installment, error) {
+	dbEntity, err := impl.querier.UpdatePaymentInstallmentStatus(ctx, &db.UpdatePaymentInstallmentStatusParams{
+		ID:     arg.ID,
+		Status: db.PaymentInstallmentStatus(arg.Status),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, UnsupportedDBEntityError{}
+	installment, err := impl.newInstallmentFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return installment, nil
 }
 
-func (impl *Repo) newInstallmentFromDBEntity(entity interface{}) (*payments.Installment, error) {
-	createInstRowEntity, valid := entity.(*db.CreatePaymentInstallmentsRow)
-	if valid {
-		return &payments.Installment{
-			ID:            createInstRowEntity.ID,
-			PaymentPlanID: createInstRowEntity.PaymentPlanID,
-			Currency:      string(createInstRowEntity.Currency),
-			Amount:        createInstRowEntity.Amount,
-			DueAt:         createInstRowEntity.DueAt,
-			Status:        string(createInstRowEntity.Status),
-			CreatedAt:     createInstRowEntity.CreatedAt,
-			UpdatedAt:     createInstRowEntity.UpdatedAt,
-		}, nil
+func (impl *Repo) UpdatePaymentInstallmentAmount(
+	ctx context.Context,
+	arg *payments.UpdateInstallmentAmountParams,
+) (*payments.Installment, error) {
+	dbEntity, err := impl.querier.UpdatePaymentInstallmentAmount(ctx, &db.UpdatePaymentInstallmentAmountParams{
+		ID:     arg.ID,
+		Amount: *arg.Amount.Amount(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	listInstsByUserIDRowEntity, valid := entity.(*db.ListPaymentInstallmentsByPlanIDRow)
-	if valid {
-		return &payments.Installment{
-			ID:            listInstsByUserIDRowEntity.ID,
-			PaymentPlanID: listInstsByUserIDRowEntity.PaymentPlanID,
-			Currency:      string(listInstsByUserIDRowEntity.Currency),
-			Amount:        listInstsByUserIDRowEntity.Amount,
-			DueAt:         listInstsByUserIDRowEntity.DueAt,
-			Status:        string(listInstsByUserIDRowEntity.Status),
-			CreatedAt:     listInstsByUserIDRowEntity.CreatedAt,
-			UpdatedAt:     listInstsByUserIDRowEntity.UpdatedAt,
-		}, nil
+	installment, err := impl.newInstallmentFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
 	}
 
-	instEntity, valid := entity.(*db.PaymentInstallment)
-	if valid {
-		return &payments.Installment{
-			ID:            instEntity.ID,
-			PaymentPlanID: instEntity.PaymentPlanID,
-			Currency:      string(instEntity.Currency),
-			Amount:        instEntity.Amount,
-			DueAt:         instEntity.DueAt,
-			Status:        string(instEntity.Status),
-			CreatedAt:     instEntity.CreatedAt,
-			UpdatedAt:     instEntity.UpdatedAt,
-		}, nil
+	return installment, nil
+}
+
+func (impl *Repo) UpdatePaymentInstallmentsStatusDueBefore(
+	ctx context.Context,
+	arg *payments.UpdateInstallmentsStatusDueBeforeParams,
+) ([]*payments.Installment, error) {
+	entities, err := impl.querier.UpdatePaymentInstallmentsStatusDueBefore(
+		ctx,
+		&db.UpdatePaymentInstallmentsStatusDueBeforeParams{
+			ToStatus:      db.PaymentInstallmentStatus(arg.ToStatus),
+			PaymentPlanID: arg.PlanID,
+			FromStatus:    db.PaymentInstallmentStatus(arg.FromStatus),
+			DueBefore:     arg.DueBefore,
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, UnsupportedDBEntityError{}
+	installments := make([]*payments.Installment, len(entities))
+
+	for idx, entity := range entities {
+		installment, err := impl.newInstallmentFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		installments[idx] = installment
+	}
+
+	return installments, nil
+}
+
+func (impl *Repo) ListUnpaidPaymentInstallmentsDueBetween(
+	ctx context.Context,
+	arg *payments.ListUnpaidInstallmentsDueBetweenParams,
+) ([]*payments.Installment, error) {
+	entities, err := impl.querier.ListUnpaidPaymentInstallmentsDueBetween(
+		ctx,
+		&db.ListUnpaidPaymentInstallmentsDueBetweenParams{
+			DueAfter:   arg.DueAfter,
+			DueBefore:  arg.DueBefore,
+			PlanStatus: db.PaymentStatus(arg.PlanStatus),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	installments := make([]*payments.Installment, len(entities))
+
+	for idx, entity := range entities {
+		installment, err := impl.newInstallmentFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		installments[idx] = installment
+	}
+
+	return installments, nil
+}
+
+func (impl *Repo) LockPaymentInstallment(ctx context.Context, id uuid.UUID) (*payments.Installment, error) {
+	dbEntity, err := impl.querier.GetPaymentInstallmentByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	installment, err := impl.newInstallmentFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return installment, nil
+}
+
+func (impl *Repo) CreatePaymentTransaction(
+	ctx context.Context,
+	arg *payments.CreateTransactionParams,
+) (*payments.Transaction, error) {
+	transactionID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreatePaymentTransaction(ctx, &db.CreatePaymentTransactionParams{
+		ID:                   transactionID,
+		PaymentInstallmentID: arg.PaymentInstallmentID,
+		Currency:             db.Currency(arg.Amount.Currency()),
+		Amount:               *arg.Amount.Amount(),
+		ProcessorReference:   arg.ProcessorReference,
+		PaidAt:               arg.PaidAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := impl.newTransactionFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (impl *Repo) ListPaymentTransactionsByInstallmentID(
+	ctx context.Context,
+	installmentID uuid.UUID,
+) ([]*payments.Transaction, error) {
+	entities, err := impl.querier.ListPaymentTransactionsByInstallmentID(ctx, installmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*payments.Transaction, len(entities))
+
+	for idx, entity := range entities {
+		transaction, err := impl.newTransactionFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions[idx] = transaction
+	}
+
+	return transactions, nil
+}
+
+func (impl *Repo) CreatePaymentLateFee(
+	ctx context.Context,
+	arg *payments.CreateLateFeeParams,
+) (*payments.LateFee, error) {
+	lateFeeID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreatePaymentLateFee(ctx, &db.CreatePaymentLateFeeParams{
+		ID:                   lateFeeID,
+		PaymentInstallmentID: arg.PaymentInstallmentID,
+		Currency:             db.Currency(arg.Amount.Currency()),
+		Amount:               *arg.Amount.Amount(),
+		AssessedAt:           arg.AssessedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lateFee, err := impl.newLateFeeFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return lateFee, nil
+}
+
+func (impl *Repo) ListPaymentLateFeesByInstallmentID(
+	ctx context.Context,
+	installmentID uuid.UUID,
+) ([]*payments.LateFee, error) {
+	entities, err := impl.querier.ListPaymentLateFeesByInstallmentID(ctx, installmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	lateFees := make([]*payments.LateFee, len(entities))
+
+	for idx, entity := range entities {
+		lateFee, err := impl.newLateFeeFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		lateFees[idx] = lateFee
+	}
+
+	return lateFees, nil
+}
+
+func (impl *Repo) ListPaymentLateFeesByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.LateFee, error) {
+	entities, err := impl.querier.ListPaymentLateFeesByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	lateFees := make([]*payments.LateFee, len(entities))
+
+	for idx, entity := range entities {
+		lateFee, err := impl.newLateFeeFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		lateFees[idx] = lateFee
+	}
+
+	return lateFees, nil
+}
+
+func (impl *Repo) CreatePaymentRefund(
+	ctx context.Context,
+	arg *payments.CreateRefundParams,
+) (*payments.Refund, error) {
+	refundID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreatePaymentRefund(ctx, &db.CreatePaymentRefundParams{
+		ID:                   refundID,
+		PaymentInstallmentID: arg.PaymentInstallmentID,
+		Currency:             db.Currency(arg.Amount.Currency()),
+		Amount:               *arg.Amount.Amount(),
+		Reason:               arg.Reason,
+		RefundedAt:           arg.RefundedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := impl.newRefundFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (impl *Repo) ListPaymentRefundsByPlanID(ctx context.Context, planID uuid.UUID) ([]*payments.Refund, error) {
+	entities, err := impl.querier.ListPaymentRefundsByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*payments.Refund, len(entities))
+
+	for idx, entity := range entities {
+		refund, err := impl.newRefundFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		refunds[idx] = refund
+	}
+
+	return refunds, nil
+}
+
+func (impl *Repo) CreatePaymentSettlement(
+	ctx context.Context,
+	arg *payments.CreateSettlementParams,
+) (*payments.Settlement, error) {
+	settlementID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreatePaymentSettlement(ctx, &db.CreatePaymentSettlementParams{
+		ID:                 settlementID,
+		PaymentPlanID:      arg.PaymentPlanID,
+		Currency:           db.Currency(arg.Amount.Currency()),
+		Amount:             *arg.Amount.Amount(),
+		ProcessorReference: arg.ProcessorReference,
+		PaidAt:             arg.PaidAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	settlement, err := impl.newSettlementFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+func (impl *Repo) CreateCreditLine(
+	ctx context.Context,
+	arg *payments.CreateCreditLineParams,
+) (*payments.CreditLine, error) {
+	creditLineID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreateCreditLine(ctx, &db.CreateCreditLineParams{
+		ID:          creditLineID,
+		UserID:      arg.UserID,
+		Currency:    db.Currency(arg.Limit.Currency()),
+		CreditLimit: *arg.Limit.Amount(),
+		Status:      db.CreditLineStatus(arg.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	creditLine, err := impl.newCreditLineFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+func (impl *Repo) GetCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error) {
+	dbEntity, err := impl.querier.GetCreditLineByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	creditLine, err := impl.newCreditLineFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+func (impl *Repo) LockCreditLineByUserID(ctx context.Context, userID uuid.UUID) (*payments.CreditLine, error) {
+	dbEntity, err := impl.querier.GetCreditLineByUserIDForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	creditLine, err := impl.newCreditLineFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+func (impl *Repo) UpdateCreditLineLimit(
+	ctx context.Context,
+	arg *payments.UpdateCreditLineLimitParams,
+) (*payments.CreditLine, error) {
+	dbEntity, err := impl.querier.UpdateCreditLineLimit(ctx, &db.UpdateCreditLineLimitParams{
+		ID:          arg.ID,
+		CreditLimit: *arg.Limit.Amount(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	creditLine, err := impl.newCreditLineFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+func (impl *Repo) UpdateCreditLineStatus(
+	ctx context.Context,
+	arg *payments.UpdateCreditLineStatusParams,
+) (*payments.CreditLine, error) {
+	dbEntity, err := impl.querier.UpdateCreditLineStatus(ctx, &db.UpdateCreditLineStatusParams{
+		ID:     arg.ID,
+		Status: db.CreditLineStatus(arg.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	creditLine, err := impl.newCreditLineFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return creditLine, nil
+}
+
+func (impl *Repo) CreateCreditLineChange(
+	ctx context.Context,
+	arg *payments.CreateCreditLineChangeParams,
+) (*payments.CreditLineChange, error) {
+	changeID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreateCreditLineChange(ctx, &db.CreateCreditLineChangeParams{
+		ID:           changeID,
+		CreditLineID: arg.CreditLineID,
+		Currency:     db.Currency(arg.Limit.Currency()),
+		Action:       db.CreditLineAction(arg.Action),
+		Actor:        arg.Actor,
+		Reason:       arg.Reason,
+		CreditLimit:  *arg.Limit.Amount(),
+		Status:       db.CreditLineStatus(arg.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := impl.newCreditLineChangeFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (impl *Repo) ListCreditLineChangesByCreditLineID(
+	ctx context.Context,
+	creditLineID uuid.UUID,
+) ([]*payments.CreditLineChange, error) {
+	entities, err := impl.querier.ListCreditLineChangesByCreditLineID(ctx, creditLineID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*payments.CreditLineChange, len(entities))
+
+	for idx, entity := range entities {
+		change, err := impl.newCreditLineChangeFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		changes[idx] = change
+	}
+
+	return changes, nil
+}
+
+// CreateJournalEntry the balance of the entry is checked by the database when the transaction commits
+func (impl *Repo) CreateJournalEntry(ctx context.Context, arg *ledger.CreateEntryParams) (*ledger.Entry, error) {
+	entryID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	entryEntity, err := impl.querier.CreateJournalEntry(ctx, &db.CreateJournalEntryParams{
+		ID:            entryID,
+		PaymentPlanID: arg.PaymentPlanID,
+		Kind:          db.JournalEntryKind(arg.Kind),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &ledger.Entry{
+		ID:            entryEntity.ID,
+		PaymentPlanID: entryEntity.PaymentPlanID,
+		Kind:          string(entryEntity.Kind),
+		Postings:      make([]ledger.Posting, 0, len(arg.Postings)),
+		CreatedAt:     entryEntity.CreatedAt,
+	}
+
+	for _, posting := range arg.Postings {
+		postingID, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+
+		postingEntity, err := impl.querier.CreateJournalPosting(ctx, &db.CreateJournalPostingParams{
+			ID:             postingID,
+			JournalEntryID: entry.ID,
+			Account:        db.LedgerAccount(posting.Account),
+			Direction:      db.PostingDirection(posting.Direction),
+			Currency:       db.Currency(posting.Amount.Currency()),
+			Amount:         *posting.Amount.Amount(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		newPosting, err := impl.newPostingFromDBEntity(postingEntity)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Postings = append(entry.Postings, *newPosting)
+	}
+
+	return entry, nil
+}
+
+// ListJournalEntriesByPlanID the postings are read at once and grouped by entry, they come ordered by entry
+func (impl *Repo) ListJournalEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*ledger.Entry, error) {
+	entities, err := impl.querier.ListJournalPostingsByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*ledger.Entry, 0)
+
+	for _, entity := range entities {
+		posting, err := impl.newPostingFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entries) == 0 || entries[len(entries)-1].ID != entity.JournalEntryID {
+			entries = append(entries, &ledger.Entry{
+				ID:            entity.JournalEntryID,
+				PaymentPlanID: entity.PaymentPlanID,
+				Kind:          string(entity.Kind),
+				CreatedAt:     entity.EntryCreatedAt,
+			})
+		}
+
+		entry := entries[len(entries)-1]
+		entry.Postings = append(entry.Postings, *posting)
+	}
+
+	return entries, nil
+}
+
+func (impl *Repo) CreateOutboxEvent(ctx context.Context, arg *outbox.CreateEventParams) (*outbox.Event, error) {
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := impl.querier.CreateOutboxEvent(ctx, &db.CreateOutboxEventParams{
+		ID:            eventID,
+		PaymentPlanID: arg.PaymentPlanID,
+		EventType:     db.OutboxEventType(arg.Type),
+		Payload:       arg.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.newOutboxEventFromDBEntity(entity)
+}
+
+// LockUnpublishedOutboxEvents a second relay waits for the events locked by the first one
+// rather than skipping them, the events of a plan could be published out of order otherwise
+func (impl *Repo) LockUnpublishedOutboxEvents(ctx context.Context, limit int32) ([]*outbox.Event, error) {
+	entities, err := impl.querier.ListUnpublishedOutboxEventsForUpdate(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*outbox.Event, 0, len(entities))
+
+	for _, entity := range entities {
+		event, err := impl.newOutboxEventFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (impl *Repo) MarkOutboxEventPublished(
+	ctx context.Context,
+	arg *outbox.MarkEventPublishedParams,
+) (*outbox.Event, error) {
+	entity, err := impl.querier.UpdateOutboxEventPublishedAt(ctx, &db.UpdateOutboxEventPublishedAtParams{
+		ID:          arg.ID,
+		PublishedAt: sql.NullTime{Time: arg.PublishedAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newOutboxEventFromDBEntity(entity)
+}
+
+func (impl *Repo) MarkOutboxEventFailed(
+	ctx context.Context,
+	arg *outbox.MarkEventFailedParams,
+) (*outbox.Event, error) {
+	entity, err := impl.querier.UpdateOutboxEventFailedAt(ctx, &db.UpdateOutboxEventFailedAtParams{
+		ID:       arg.ID,
+		FailedAt: sql.NullTime{Time: arg.FailedAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newOutboxEventFromDBEntity(entity)
+}
+
+func (impl *Repo) CreateWebhookEndpoint(
+	ctx context.Context,
+	arg *webhook.CreateEndpointParams,
+) (*webhook.Endpoint, error) {
+	endpointID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := impl.querier.CreateWebhookEndpoint(ctx, &db.CreateWebhookEndpointParams{
+		ID:         endpointID,
+		MerchantID: arg.MerchantID,
+		Url:        arg.URL,
+		Secret:     arg.Secret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.newWebhookEndpointFromDBEntity(entity)
+}
+
+func (impl *Repo) GetWebhookEndpointByID(ctx context.Context, id uuid.UUID) (*webhook.Endpoint, error) {
+	entity, err := impl.querier.GetWebhookEndpointByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newWebhookEndpointFromDBEntity(entity)
+}
+
+func (impl *Repo) ListWebhookEndpointsByMerchantID(
+	ctx context.Context,
+	merchantID uuid.UUID,
+) ([]*webhook.Endpoint, error) {
+	entities, err := impl.querier.ListWebhookEndpointsByMerchantID(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*webhook.Endpoint, 0, len(entities))
+
+	for _, entity := range entities {
+		endpoint, err := impl.newWebhookEndpointFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+func (impl *Repo) CreateWebhookDelivery(ctx context.Context, arg *webhook.CreateDeliveryParams) error {
+	deliveryID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	return impl.querier.CreateWebhookDelivery(ctx, &db.CreateWebhookDeliveryParams{
+		ID:                deliveryID,
+		WebhookEndpointID: arg.EndpointID,
+		OutboxEventID:     arg.EventID,
+		EventType:         db.OutboxEventType(arg.EventType),
+		Payload:           arg.Payload,
+		Status:            db.WebhookDeliveryStatusPending,
+		NextAttemptAt:     arg.NextAttemptAt,
+	})
+}
+
+func (impl *Repo) LockWebhookDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	entity, err := impl.querier.GetWebhookDeliveryByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newWebhookDeliveryFromDBEntity(entity)
+}
+
+// LockDueWebhookDeliveries the deliveries are independent from each other so concurrent
+// dispatchers share the due ones rather than waiting for each other
+func (impl *Repo) LockDueWebhookDeliveries(
+	ctx context.Context,
+	arg *webhook.ListDueDeliveriesParams,
+) ([]*webhook.Delivery, error) {
+	entities, err := impl.querier.ListDueWebhookDeliveriesForUpdate(ctx, &db.ListDueWebhookDeliveriesForUpdateParams{
+		NextAttemptAt: arg.Before,
+		Limit:         arg.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*webhook.Delivery, 0, len(entities))
+
+	for _, entity := range entities {
+		delivery, err := impl.newWebhookDeliveryFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (impl *Repo) ListWebhookDeliveriesByStatus(ctx context.Context, status string) ([]*webhook.Delivery, error) {
+	entities, err := impl.querier.ListWebhookDeliveriesByStatus(ctx, db.WebhookDeliveryStatus(status))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*webhook.Delivery, 0, len(entities))
+
+	for _, entity := range entities {
+		delivery, err := impl.newWebhookDeliveryFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (impl *Repo) UpdateWebhookDelivery(
+	ctx context.Context,
+	arg *webhook.UpdateDeliveryParams,
+) (*webhook.Delivery, error) {
+	deliveredAt := sql.NullTime{}
+	if arg.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: *arg.DeliveredAt, Valid: true}
+	}
+
+	entity, err := impl.querier.UpdateWebhookDeliveryAttempt(ctx, &db.UpdateWebhookDeliveryAttemptParams{
+		ID:            arg.ID,
+		Status:        db.WebhookDeliveryStatus(arg.Status),
+		Attempts:      int32(arg.Attempts),
+		NextAttemptAt: arg.NextAttemptAt,
+		LastError:     arg.LastError,
+		DeliveredAt:   deliveredAt,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newWebhookDeliveryFromDBEntity(entity)
+}
+
+func (impl *Repo) CreateReminderSent(ctx context.Context, arg *reminder.CreateSentParams) (bool, error) {
+	reminderSentID, err := uuid.NewV4()
+	if err != nil {
+		return false, err
+	}
+
+	recorded, err := impl.querier.CreateReminderSent(ctx, &db.CreateReminderSentParams{
+		ID:                   reminderSentID,
+		PaymentInstallmentID: arg.InstallmentID,
+		Reminder:             arg.Reminder,
+		SentAt:               arg.SentAt,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return recorded > 0, nil
+}
+
+func (impl *Repo) ListDelinquentPaymentPlans(ctx context.Context, limit int32) ([]*payments.Plan, error) {
+	entities, err := impl.querier.ListDelinquentPaymentPlans(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*payments.Plan, len(entities))
+
+	for idx, entity := range entities {
+		plan, err := impl.newPlanFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		plans[idx] = plan
+	}
+
+	return plans, nil
+}
+
+func (impl *Repo) CreateCollectionsCase(
+	ctx context.Context,
+	arg *collections.CreateCaseParams,
+) (*collections.Case, error) {
+	caseID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// a concurrent case for the same plan fails on the partial unique payment_plan_id
+	entity, err := impl.querier.CreateCollectionsCase(ctx, &db.CreateCollectionsCaseParams{
+		ID:            caseID,
+		PaymentPlanID: arg.PaymentPlanID,
+		UserID:        arg.UserID,
+		Status:        db.CollectionsCaseStatus(arg.Status),
+		OpenedAt:      arg.OpenedAt,
+		NextActionAt:  newNullTime(arg.NextActionAt),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.newCollectionsCaseFromDBEntity(entity)
+}
+
+func (impl *Repo) GetCollectionsCaseByID(ctx context.Context, id uuid.UUID) (*collections.Case, error) {
+	entity, err := impl.querier.GetCollectionsCaseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newCollectionsCaseFromDBEntity(entity)
+}
+
+func (impl *Repo) GetActiveCollectionsCaseByPlanID(ctx context.Context, planID uuid.UUID) (*collections.Case, error) {
+	entity, err := impl.querier.GetActiveCollectionsCaseByPaymentPlanID(ctx, planID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newCollectionsCaseFromDBEntity(entity)
+}
+
+func (impl *Repo) LockCollectionsCase(ctx context.Context, id uuid.UUID) (*collections.Case, error) {
+	entity, err := impl.querier.GetCollectionsCaseByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newCollectionsCaseFromDBEntity(entity)
+}
+
+func (impl *Repo) ListDueCollectionsCases(
+	ctx context.Context,
+	arg *collections.ListDueCasesParams,
+) ([]*collections.Case, error) {
+	entities, err := impl.querier.ListDueCollectionsCases(ctx, &db.ListDueCollectionsCasesParams{
+		Before: arg.Before,
+		Limit:  arg.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]*collections.Case, 0, len(entities))
+
+	for _, entity := range entities {
+		collectionsCase, err := impl.newCollectionsCaseFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, collectionsCase)
+	}
+
+	return cases, nil
+}
+
+func (impl *Repo) ListSettledCollectionsCases(ctx context.Context, limit int32) ([]*collections.Case, error) {
+	entities, err := impl.querier.ListSettledCollectionsCases(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]*collections.Case, 0, len(entities))
+
+	for _, entity := range entities {
+		collectionsCase, err := impl.newCollectionsCaseFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, collectionsCase)
+	}
+
+	return cases, nil
+}
+
+func (impl *Repo) ListCollectionsCasesByStatus(ctx context.Context, status string) ([]*collections.Case, error) {
+	entities, err := impl.querier.ListCollectionsCasesByStatus(ctx, db.CollectionsCaseStatus(status))
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]*collections.Case, 0, len(entities))
+
+	for _, entity := range entities {
+		collectionsCase, err := impl.newCollectionsCaseFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, collectionsCase)
+	}
+
+	return cases, nil
+}
+
+func (impl *Repo) UpdateCollectionsCase(
+	ctx context.Context,
+	arg *collections.UpdateCaseParams,
+) (*collections.Case, error) {
+	entity, err := impl.querier.UpdateCollectionsCase(ctx, &db.UpdateCollectionsCaseParams{
+		ID:           arg.ID,
+		Status:       db.CollectionsCaseStatus(arg.Status),
+		Stage:        arg.Stage,
+		NextActionAt: newNullTime(arg.NextActionAt),
+		ClosedAt:     newNullTime(arg.ClosedAt),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newCollectionsCaseFromDBEntity(entity)
+}
+
+func (impl *Repo) CreateCollectionsCaseEvent(
+	ctx context.Context,
+	arg *collections.CreateEventParams,
+) (*collections.Event, error) {
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := impl.querier.CreateCollectionsCaseEvent(ctx, &db.CreateCollectionsCaseEventParams{
+		ID:                eventID,
+		CollectionsCaseID: arg.CaseID,
+		Kind:              db.CollectionsCaseEventKind(arg.Kind),
+		Stage:             arg.Stage,
+		Actor:             arg.Actor,
+		Note:              arg.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.newCollectionsCaseEventFromDBEntity(entity)
+}
+
+func (impl *Repo) ListCollectionsCaseEventsByCaseID(
+	ctx context.Context,
+	caseID uuid.UUID,
+) ([]*collections.Event, error) {
+	entities, err := impl.querier.ListCollectionsCaseEventsByCaseID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*collections.Event, 0, len(entities))
+
+	for _, entity := range entities {
+		event, err := impl.newCollectionsCaseEventFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (impl *Repo) GetUserOutstandingAmount(
+	ctx context.Context,
+	arg *payments.UserOutstandingAmountParams,
+) (payments.Money, error) {
+	outstanding, err := impl.querier.GetUserOutstandingAmount(ctx, &db.GetUserOutstandingAmountParams{
+		UserID:   arg.UserID,
+		Currency: db.Currency(arg.Currency),
+	})
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	return newMoneyFromDBEntity(&outstanding, db.Currency(arg.Currency))
+}
+
+func (impl *Repo) CreateMerchant(ctx context.Context, arg *payments.CreateMerchantParams) (*payments.Merchant, error) {
+	entity, err := impl.querier.CreateMerchant(ctx, &db.CreateMerchantParams{
+		ID:   arg.ID,
+		Name: arg.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.newMerchantFromDBEntity(entity)
+}
+
+func (impl *Repo) GetMerchantByID(ctx context.Context, id uuid.UUID) (*payments.Merchant, error) {
+	entity, err := impl.querier.GetMerchantByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newMerchantFromDBEntity(entity)
+}
+
+func (impl *Repo) GetMerchantTotals(
+	ctx context.Context,
+	arg *payments.MerchantTotalsParams,
+) ([]*payments.MerchantTotals, error) {
+	entities, err := impl.querier.GetMerchantTotals(ctx, &db.GetMerchantTotalsParams{
+		MerchantID:  uuid.NullUUID{UUID: arg.MerchantID, Valid: true},
+		CreatedFrom: arg.CreatedFrom,
+		CreatedTo:   arg.CreatedTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make([]*payments.MerchantTotals, 0, len(entities))
+
+	for _, entity := range entities {
+		originated, err := newMoneyFromDBEntity(&entity.OriginatedAmount, entity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		outstanding, err := newMoneyFromDBEntity(&entity.OutstandingAmount, entity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		refunded, err := newMoneyFromDBEntity(&entity.RefundedAmount, entity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, &payments.MerchantTotals{
+			Originated:  originated,
+			Outstanding: outstanding,
+			Refunded:    refunded,
+		})
+	}
+
+	return totals, nil
+}
+
+func (impl *Repo) CreatePaymentPlanLineItem(
+	ctx context.Context,
+	arg *payments.CreateLineItemParams,
+) (*payments.LineItem, error) {
+	lineItemID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	dbEntity, err := impl.querier.CreatePaymentPlanLineItem(ctx, &db.CreatePaymentPlanLineItemParams{
+		ID:            lineItemID,
+		PaymentPlanID: arg.PaymentPlanID,
+		Position:      arg.Position,
+		Sku:           arg.SKU,
+		Name:          arg.Name,
+		Quantity:      arg.Quantity,
+		Currency:      db.Currency(arg.UnitPrice.Currency()),
+		UnitPrice:     *arg.UnitPrice.Amount(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lineItem, err := impl.newLineItemFromDBEntity(dbEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return lineItem, nil
+}
+
+func (impl *Repo) ListPaymentPlanLineItemsByPlanID(
+	ctx context.Context,
+	planID uuid.UUID,
+) ([]*payments.LineItem, error) {
+	entities, err := impl.querier.ListPaymentPlanLineItemsByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineItems := make([]*payments.LineItem, len(entities))
+
+	for idx, entity := range entities {
+		lineItem, err := impl.newLineItemFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		lineItems[idx] = lineItem
+	}
+
+	return lineItems, nil
+}
+
+// CreateAuditEntry a nil Before is stored as a json null, a sequence already taken
+// is refused by the unique index on the plan and the sequence
+func (impl *Repo) CreateAuditEntry(ctx context.Context, arg *audit.CreateEntryParams) (*audit.Entry, error) {
+	entryID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := json.Marshal(arg.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := json.Marshal(arg.After)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := impl.querier.CreateAuditEntry(ctx, &db.CreateAuditEntryParams{
+		ID:             entryID,
+		PaymentPlanID:  arg.PaymentPlanID,
+		Sequence:       arg.Sequence,
+		ActorType:      db.AuditActorType(arg.Actor.Type),
+		ActorID:        arg.Actor.ID,
+		Action:         db.AuditAction(arg.Action),
+		BeforeSnapshot: before,
+		AfterSnapshot:  after,
+		RequestID:      newNullString(arg.RequestID),
+		OccurredAt:     arg.OccurredAt,
+		PrevHash:       arg.PrevHash,
+		Hash:           arg.Hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return impl.newAuditEntryFromDBEntity(entity)
+}
+
+func (impl *Repo) GetLatestAuditEntryByPlanID(ctx context.Context, planID uuid.UUID) (*audit.Entry, error) {
+	entity, err := impl.querier.GetLatestAuditEntryByPlanID(ctx, planID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrRecordNotFound
+		}
+
+		return nil, err
+	}
+
+	return impl.newAuditEntryFromDBEntity(entity)
+}
+
+func (impl *Repo) ListAuditEntriesByPlanID(ctx context.Context, planID uuid.UUID) ([]*audit.Entry, error) {
+	entities, err := impl.querier.ListAuditEntriesByPlanID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*audit.Entry, 0, len(entities))
+
+	for _, entity := range entities {
+		entry, err := impl.newAuditEntryFromDBEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (impl *Repo) newPlanFromDBEntity(entity interface{}) (*payments.Plan, error) {
+	createPaymentPlanRowEntity, valid := entity.(*db.CreatePaymentPlanRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&createPaymentPlanRowEntity.Amount, createPaymentPlanRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             createPaymentPlanRowEntity.ID,
+			UserID:         createPaymentPlanRowEntity.UserID,
+			MerchantID:     createPaymentPlanRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(createPaymentPlanRowEntity.Status),
+			OrderReference: createPaymentPlanRowEntity.OrderReference.String,
+			Description:    createPaymentPlanRowEntity.Description.String,
+			CreatedAt:      createPaymentPlanRowEntity.CreatedAt,
+			UpdatedAt:      createPaymentPlanRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	getPaymentPlanByIDRowEntity, valid := entity.(*db.GetPaymentPlanByIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&getPaymentPlanByIDRowEntity.Amount, getPaymentPlanByIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             getPaymentPlanByIDRowEntity.ID,
+			UserID:         getPaymentPlanByIDRowEntity.UserID,
+			MerchantID:     getPaymentPlanByIDRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(getPaymentPlanByIDRowEntity.Status),
+			OrderReference: getPaymentPlanByIDRowEntity.OrderReference.String,
+			Description:    getPaymentPlanByIDRowEntity.Description.String,
+			CreatedAt:      getPaymentPlanByIDRowEntity.CreatedAt,
+			UpdatedAt:      getPaymentPlanByIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	getPlanForUpdateRowEntity, valid := entity.(*db.GetPaymentPlanByIDForUpdateRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&getPlanForUpdateRowEntity.Amount, getPlanForUpdateRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             getPlanForUpdateRowEntity.ID,
+			UserID:         getPlanForUpdateRowEntity.UserID,
+			MerchantID:     getPlanForUpdateRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(getPlanForUpdateRowEntity.Status),
+			OrderReference: getPlanForUpdateRowEntity.OrderReference.String,
+			Description:    getPlanForUpdateRowEntity.Description.String,
+			CreatedAt:      getPlanForUpdateRowEntity.CreatedAt,
+			UpdatedAt:      getPlanForUpdateRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listPlansCreatedBeforeRowEntity, valid := entity.(*db.ListPaymentPlansByStatusCreatedBeforeRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listPlansCreatedBeforeRowEntity.Amount, listPlansCreatedBeforeRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             listPlansCreatedBeforeRowEntity.ID,
+			UserID:         listPlansCreatedBeforeRowEntity.UserID,
+			MerchantID:     listPlansCreatedBeforeRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(listPlansCreatedBeforeRowEntity.Status),
+			OrderReference: listPlansCreatedBeforeRowEntity.OrderReference.String,
+			Description:    listPlansCreatedBeforeRowEntity.Description.String,
+			CreatedAt:      listPlansCreatedBeforeRowEntity.CreatedAt,
+			UpdatedAt:      listPlansCreatedBeforeRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listPaymentPlansByUserIDRowEntity, valid := entity.(*db.ListPaymentPlansByUserIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listPaymentPlansByUserIDRowEntity.Amount, listPaymentPlansByUserIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             listPaymentPlansByUserIDRowEntity.ID,
+			UserID:         listPaymentPlansByUserIDRowEntity.UserID,
+			MerchantID:     listPaymentPlansByUserIDRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(listPaymentPlansByUserIDRowEntity.Status),
+			OrderReference: listPaymentPlansByUserIDRowEntity.OrderReference.String,
+			Description:    listPaymentPlansByUserIDRowEntity.Description.String,
+			CreatedAt:      listPaymentPlansByUserIDRowEntity.CreatedAt,
+			UpdatedAt:      listPaymentPlansByUserIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	updatePaymentPlanStatusRowEntity, valid := entity.(*db.UpdatePaymentPlanStatusRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&updatePaymentPlanStatusRowEntity.Amount, updatePaymentPlanStatusRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             updatePaymentPlanStatusRowEntity.ID,
+			UserID:         updatePaymentPlanStatusRowEntity.UserID,
+			MerchantID:     updatePaymentPlanStatusRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(updatePaymentPlanStatusRowEntity.Status),
+			OrderReference: updatePaymentPlanStatusRowEntity.OrderReference.String,
+			Description:    updatePaymentPlanStatusRowEntity.Description.String,
+			CreatedAt:      updatePaymentPlanStatusRowEntity.CreatedAt,
+			UpdatedAt:      updatePaymentPlanStatusRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listDelinquentPlansRowEntity, valid := entity.(*db.ListDelinquentPaymentPlansRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listDelinquentPlansRowEntity.Amount, listDelinquentPlansRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             listDelinquentPlansRowEntity.ID,
+			UserID:         listDelinquentPlansRowEntity.UserID,
+			MerchantID:     listDelinquentPlansRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(listDelinquentPlansRowEntity.Status),
+			OrderReference: listDelinquentPlansRowEntity.OrderReference.String,
+			Description:    listDelinquentPlansRowEntity.Description.String,
+			CreatedAt:      listDelinquentPlansRowEntity.CreatedAt,
+			UpdatedAt:      listDelinquentPlansRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	merchantPlansOldestRowEntity, valid := entity.(*db.ListPaymentPlansByMerchantIDOldestFirstRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&merchantPlansOldestRowEntity.Amount, merchantPlansOldestRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             merchantPlansOldestRowEntity.ID,
+			UserID:         merchantPlansOldestRowEntity.UserID,
+			MerchantID:     merchantPlansOldestRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(merchantPlansOldestRowEntity.Status),
+			OrderReference: merchantPlansOldestRowEntity.OrderReference.String,
+			Description:    merchantPlansOldestRowEntity.Description.String,
+			CreatedAt:      merchantPlansOldestRowEntity.CreatedAt,
+			UpdatedAt:      merchantPlansOldestRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	merchantPlansLatestRowEntity, valid := entity.(*db.ListPaymentPlansByMerchantIDLatestFirstRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&merchantPlansLatestRowEntity.Amount, merchantPlansLatestRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             merchantPlansLatestRowEntity.ID,
+			UserID:         merchantPlansLatestRowEntity.UserID,
+			MerchantID:     merchantPlansLatestRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(merchantPlansLatestRowEntity.Status),
+			OrderReference: merchantPlansLatestRowEntity.OrderReference.String,
+			Description:    merchantPlansLatestRowEntity.Description.String,
+			CreatedAt:      merchantPlansLatestRowEntity.CreatedAt,
+			UpdatedAt:      merchantPlansLatestRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	planByOrderRowEntity, valid := entity.(*db.GetPaymentPlanByMerchantIDOrderReferenceRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&planByOrderRowEntity.Amount, planByOrderRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             planByOrderRowEntity.ID,
+			UserID:         planByOrderRowEntity.UserID,
+			MerchantID:     planByOrderRowEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(planByOrderRowEntity.Status),
+			OrderReference: planByOrderRowEntity.OrderReference.String,
+			Description:    planByOrderRowEntity.Description.String,
+			CreatedAt:      planByOrderRowEntity.CreatedAt,
+			UpdatedAt:      planByOrderRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	planEntity, valid := entity.(*db.PaymentPlan)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&planEntity.Amount, planEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Plan{
+			ID:             planEntity.ID,
+			UserID:         planEntity.UserID,
+			MerchantID:     planEntity.MerchantID.UUID,
+			Amount:         amount,
+			Status:         string(planEntity.Status),
+			OrderReference: planEntity.OrderReference.String,
+			Description:    planEntity.Description.String,
+			CreatedAt:      planEntity.CreatedAt,
+			UpdatedAt:      planEntity.UpdatedAt,
+		}, nil
+	}
+
+	return nil, UnsupportedDBEntityError{}
+}
+
+func (impl *Repo) newMerchantFromDBEntity(entity interface{}) (*payments.Merchant, error) {
+	switch merchantEntity := entity.(type) {
+	case *db.CreateMerchantRow:
+		return newMerchant(&db.Merchant{
+			ID:        merchantEntity.ID,
+			CreatedAt: merchantEntity.CreatedAt,
+			UpdatedAt: merchantEntity.UpdatedAt,
+			Name:      merchantEntity.Name,
+		}), nil
+	case *db.GetMerchantByIDRow:
+		return newMerchant(&db.Merchant{
+			ID:        merchantEntity.ID,
+			CreatedAt: merchantEntity.CreatedAt,
+			UpdatedAt: merchantEntity.UpdatedAt,
+			Name:      merchantEntity.Name,
+		}), nil
+	case *db.Merchant:
+		return newMerchant(merchantEntity), nil
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newMerchant(entity *db.Merchant) *payments.Merchant {
+	return &payments.Merchant{
+		ID:        entity.ID,
+		Name:      entity.Name,
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+func (impl *Repo) newInstallmentFromDBEntity(entity interface{}) (*payments.Installment, error) {
+	createInstRowEntity, valid := entity.(*db.CreatePaymentInstallmentsRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&createInstRowEntity.Amount, createInstRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            createInstRowEntity.ID,
+			PaymentPlanID: createInstRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         createInstRowEntity.DueAt,
+			Status:        string(createInstRowEntity.Status),
+			Version:       createInstRowEntity.Version,
+			CreatedAt:     createInstRowEntity.CreatedAt,
+			UpdatedAt:     createInstRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	getInstForUpdateRowEntity, valid := entity.(*db.GetPaymentInstallmentByIDForUpdateRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&getInstForUpdateRowEntity.Amount, getInstForUpdateRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            getInstForUpdateRowEntity.ID,
+			PaymentPlanID: getInstForUpdateRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         getInstForUpdateRowEntity.DueAt,
+			Status:        string(getInstForUpdateRowEntity.Status),
+			Version:       getInstForUpdateRowEntity.Version,
+			CreatedAt:     getInstForUpdateRowEntity.CreatedAt,
+			UpdatedAt:     getInstForUpdateRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listInstsByUserIDRowEntity, valid := entity.(*db.ListPaymentInstallmentsByPlanIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listInstsByUserIDRowEntity.Amount, listInstsByUserIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            listInstsByUserIDRowEntity.ID,
+			PaymentPlanID: listInstsByUserIDRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         listInstsByUserIDRowEntity.DueAt,
+			Status:        string(listInstsByUserIDRowEntity.Status),
+			Version:       listInstsByUserIDRowEntity.Version,
+			CreatedAt:     listInstsByUserIDRowEntity.CreatedAt,
+			UpdatedAt:     listInstsByUserIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listInstsByStatusRowEntity, valid := entity.(*db.ListPaymentInstallmentsByStatusRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listInstsByStatusRowEntity.Amount, listInstsByStatusRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            listInstsByStatusRowEntity.ID,
+			PaymentPlanID: listInstsByStatusRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         listInstsByStatusRowEntity.DueAt,
+			Status:        string(listInstsByStatusRowEntity.Status),
+			Version:       listInstsByStatusRowEntity.Version,
+			CreatedAt:     listInstsByStatusRowEntity.CreatedAt,
+			UpdatedAt:     listInstsByStatusRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	updateInstStatusRowEntity, valid := entity.(*db.UpdatePaymentInstallmentStatusRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&updateInstStatusRowEntity.Amount, updateInstStatusRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            updateInstStatusRowEntity.ID,
+			PaymentPlanID: updateInstStatusRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         updateInstStatusRowEntity.DueAt,
+			Status:        string(updateInstStatusRowEntity.Status),
+			Version:       updateInstStatusRowEntity.Version,
+			CreatedAt:     updateInstStatusRowEntity.CreatedAt,
+			UpdatedAt:     updateInstStatusRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	updateInstAmountRowEntity, valid := entity.(*db.UpdatePaymentInstallmentAmountRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&updateInstAmountRowEntity.Amount, updateInstAmountRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            updateInstAmountRowEntity.ID,
+			PaymentPlanID: updateInstAmountRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         updateInstAmountRowEntity.DueAt,
+			Status:        string(updateInstAmountRowEntity.Status),
+			Version:       updateInstAmountRowEntity.Version,
+			CreatedAt:     updateInstAmountRowEntity.CreatedAt,
+			UpdatedAt:     updateInstAmountRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	updateInstsDueBeforeRowEntity, valid := entity.(*db.UpdatePaymentInstallmentsStatusDueBeforeRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&updateInstsDueBeforeRowEntity.Amount, updateInstsDueBeforeRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            updateInstsDueBeforeRowEntity.ID,
+			PaymentPlanID: updateInstsDueBeforeRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         updateInstsDueBeforeRowEntity.DueAt,
+			Status:        string(updateInstsDueBeforeRowEntity.Status),
+			Version:       updateInstsDueBeforeRowEntity.Version,
+			CreatedAt:     updateInstsDueBeforeRowEntity.CreatedAt,
+			UpdatedAt:     updateInstsDueBeforeRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listUnpaidInstsDueRowEntity, valid := entity.(*db.ListUnpaidPaymentInstallmentsDueBetweenRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listUnpaidInstsDueRowEntity.Amount, listUnpaidInstsDueRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            listUnpaidInstsDueRowEntity.ID,
+			PaymentPlanID: listUnpaidInstsDueRowEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         listUnpaidInstsDueRowEntity.DueAt,
+			Status:        string(listUnpaidInstsDueRowEntity.Status),
+			Version:       listUnpaidInstsDueRowEntity.Version,
+			CreatedAt:     listUnpaidInstsDueRowEntity.CreatedAt,
+			UpdatedAt:     listUnpaidInstsDueRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	instEntity, valid := entity.(*db.PaymentInstallment)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&instEntity.Amount, instEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Installment{
+			ID:            instEntity.ID,
+			PaymentPlanID: instEntity.PaymentPlanID,
+			Amount:        amount,
+			DueAt:         instEntity.DueAt,
+			Status:        string(instEntity.Status),
+			Version:       instEntity.Version,
+			CreatedAt:     instEntity.CreatedAt,
+			UpdatedAt:     instEntity.UpdatedAt,
+		}, nil
+	}
+
+	return nil, UnsupportedDBEntityError{}
+}
+
+func (impl *Repo) newTransactionFromDBEntity(entity interface{}) (*payments.Transaction, error) {
+	createTxRowEntity, valid := entity.(*db.CreatePaymentTransactionRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&createTxRowEntity.Amount, createTxRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Transaction{
+			ID:                   createTxRowEntity.ID,
+			PaymentInstallmentID: createTxRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			ProcessorReference:   createTxRowEntity.ProcessorReference,
+			PaidAt:               createTxRowEntity.PaidAt,
+			CreatedAt:            createTxRowEntity.CreatedAt,
+			UpdatedAt:            createTxRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listTxsByInstIDRowEntity, valid := entity.(*db.ListPaymentTransactionsByInstallmentIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listTxsByInstIDRowEntity.Amount, listTxsByInstIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Transaction{
+			ID:                   listTxsByInstIDRowEntity.ID,
+			PaymentInstallmentID: listTxsByInstIDRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			ProcessorReference:   listTxsByInstIDRowEntity.ProcessorReference,
+			PaidAt:               listTxsByInstIDRowEntity.PaidAt,
+			CreatedAt:            listTxsByInstIDRowEntity.CreatedAt,
+			UpdatedAt:            listTxsByInstIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	txEntity, valid := entity.(*db.PaymentTransaction)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&txEntity.Amount, txEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Transaction{
+			ID:                   txEntity.ID,
+			PaymentInstallmentID: txEntity.PaymentInstallmentID,
+			Amount:               amount,
+			ProcessorReference:   txEntity.ProcessorReference,
+			PaidAt:               txEntity.PaidAt,
+			CreatedAt:            txEntity.CreatedAt,
+			UpdatedAt:            txEntity.UpdatedAt,
+		}, nil
+	}
+
+	return nil, UnsupportedDBEntityError{}
+}
+
+func (impl *Repo) newLateFeeFromDBEntity(entity interface{}) (*payments.LateFee, error) {
+	createLateFeeRowEntity, valid := entity.(*db.CreatePaymentLateFeeRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&createLateFeeRowEntity.Amount, createLateFeeRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.LateFee{
+			ID:                   createLateFeeRowEntity.ID,
+			PaymentInstallmentID: createLateFeeRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			AssessedAt:           createLateFeeRowEntity.AssessedAt,
+			CreatedAt:            createLateFeeRowEntity.CreatedAt,
+			UpdatedAt:            createLateFeeRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listLateFeesByInstIDRowEntity, valid := entity.(*db.ListPaymentLateFeesByInstallmentIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listLateFeesByInstIDRowEntity.Amount, listLateFeesByInstIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.LateFee{
+			ID:                   listLateFeesByInstIDRowEntity.ID,
+			PaymentInstallmentID: listLateFeesByInstIDRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			AssessedAt:           listLateFeesByInstIDRowEntity.AssessedAt,
+			CreatedAt:            listLateFeesByInstIDRowEntity.CreatedAt,
+			UpdatedAt:            listLateFeesByInstIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listLateFeesByPlanIDRowEntity, valid := entity.(*db.ListPaymentLateFeesByPlanIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listLateFeesByPlanIDRowEntity.Amount, listLateFeesByPlanIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.LateFee{
+			ID:                   listLateFeesByPlanIDRowEntity.ID,
+			PaymentInstallmentID: listLateFeesByPlanIDRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			AssessedAt:           listLateFeesByPlanIDRowEntity.AssessedAt,
+			CreatedAt:            listLateFeesByPlanIDRowEntity.CreatedAt,
+			UpdatedAt:            listLateFeesByPlanIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	lateFeeEntity, valid := entity.(*db.PaymentLateFee)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&lateFeeEntity.Amount, lateFeeEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.LateFee{
+			ID:                   lateFeeEntity.ID,
+			PaymentInstallmentID: lateFeeEntity.PaymentInstallmentID,
+			Amount:               amount,
+			AssessedAt:           lateFeeEntity.AssessedAt,
+			CreatedAt:            lateFeeEntity.CreatedAt,
+			UpdatedAt:            lateFeeEntity.UpdatedAt,
+		}, nil
+	}
+
+	return nil, UnsupportedDBEntityError{}
+}
+
+func (impl *Repo) newRefundFromDBEntity(entity interface{}) (*payments.Refund, error) {
+	createRefundRowEntity, valid := entity.(*db.CreatePaymentRefundRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&createRefundRowEntity.Amount, createRefundRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Refund{
+			ID:                   createRefundRowEntity.ID,
+			PaymentInstallmentID: createRefundRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			Reason:               createRefundRowEntity.Reason,
+			RefundedAt:           createRefundRowEntity.RefundedAt,
+			CreatedAt:            createRefundRowEntity.CreatedAt,
+			UpdatedAt:            createRefundRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	listRefundsByPlanIDRowEntity, valid := entity.(*db.ListPaymentRefundsByPlanIDRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&listRefundsByPlanIDRowEntity.Amount, listRefundsByPlanIDRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Refund{
+			ID:                   listRefundsByPlanIDRowEntity.ID,
+			PaymentInstallmentID: listRefundsByPlanIDRowEntity.PaymentInstallmentID,
+			Amount:               amount,
+			Reason:               listRefundsByPlanIDRowEntity.Reason,
+			RefundedAt:           listRefundsByPlanIDRowEntity.RefundedAt,
+			CreatedAt:            listRefundsByPlanIDRowEntity.CreatedAt,
+			UpdatedAt:            listRefundsByPlanIDRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	refundEntity, valid := entity.(*db.PaymentRefund)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&refundEntity.Amount, refundEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Refund{
+			ID:                   refundEntity.ID,
+			PaymentInstallmentID: refundEntity.PaymentInstallmentID,
+			Amount:               amount,
+			Reason:               refundEntity.Reason,
+			RefundedAt:           refundEntity.RefundedAt,
+			CreatedAt:            refundEntity.CreatedAt,
+			UpdatedAt:            refundEntity.UpdatedAt,
+		}, nil
+	}
+
+	return nil, UnsupportedDBEntityError{}
+}
+
+func (impl *Repo) newSettlementFromDBEntity(entity interface{}) (*payments.Settlement, error) {
+	createSettlementRowEntity, valid := entity.(*db.CreatePaymentSettlementRow)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&createSettlementRowEntity.Amount, createSettlementRowEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Settlement{
+			ID:                 createSettlementRowEntity.ID,
+			PaymentPlanID:      createSettlementRowEntity.PaymentPlanID,
+			Amount:             amount,
+			ProcessorReference: createSettlementRowEntity.ProcessorReference,
+			PaidAt:             createSettlementRowEntity.PaidAt,
+			CreatedAt:          createSettlementRowEntity.CreatedAt,
+			UpdatedAt:          createSettlementRowEntity.UpdatedAt,
+		}, nil
+	}
+
+	settlementEntity, valid := entity.(*db.PaymentSettlement)
+	if valid {
+		amount, err := newMoneyFromDBEntity(&settlementEntity.Amount, settlementEntity.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &payments.Settlement{
+			ID:                 settlementEntity.ID,
+			PaymentPlanID:      settlementEntity.PaymentPlanID,
+			Amount:             amount,
+			ProcessorReference: settlementEntity.ProcessorReference,
+			PaidAt:             settlementEntity.PaidAt,
+			CreatedAt:          settlementEntity.CreatedAt,
+			UpdatedAt:          settlementEntity.UpdatedAt,
+		}, nil
+	}
+
+	return nil, UnsupportedDBEntityError{}
+}
+
+func (impl *Repo) newCreditLineFromDBEntity(entity interface{}) (*payments.CreditLine, error) {
+	switch creditLineEntity := entity.(type) {
+	case *db.CreateCreditLineRow:
+		return newCreditLine(
+			creditLineEntity.ID,
+			creditLineEntity.UserID,
+			&creditLineEntity.CreditLimit,
+			creditLineEntity.Currency,
+			creditLineEntity.Status,
+			creditLineEntity.CreatedAt,
+			creditLineEntity.UpdatedAt,
+		)
+	case *db.GetCreditLineByUserIDRow:
+		return newCreditLine(
+			creditLineEntity.ID,
+			creditLineEntity.UserID,
+			&creditLineEntity.CreditLimit,
+			creditLineEntity.Currency,
+			creditLineEntity.Status,
+			creditLineEntity.CreatedAt,
+			creditLineEntity.UpdatedAt,
+		)
+	case *db.GetCreditLineByUserIDForUpdateRow:
+		return newCreditLine(
+			creditLineEntity.ID,
+			creditLineEntity.UserID,
+			&creditLineEntity.CreditLimit,
+			creditLineEntity.Currency,
+			creditLineEntity.Status,
+			creditLineEntity.CreatedAt,
+			creditLineEntity.UpdatedAt,
+		)
+	case *db.UpdateCreditLineLimitRow:
+		return newCreditLine(
+			creditLineEntity.ID,
+			creditLineEntity.UserID,
+			&creditLineEntity.CreditLimit,
+			creditLineEntity.Currency,
+			creditLineEntity.Status,
+			creditLineEntity.CreatedAt,
+			creditLineEntity.UpdatedAt,
+		)
+	case *db.UpdateCreditLineStatusRow:
+		return newCreditLine(
+			creditLineEntity.ID,
+			creditLineEntity.UserID,
+			&creditLineEntity.CreditLimit,
+			creditLineEntity.Currency,
+			creditLineEntity.Status,
+			creditLineEntity.CreatedAt,
+			creditLineEntity.UpdatedAt,
+		)
+	case *db.CreditLine:
+		return newCreditLine(
+			creditLineEntity.ID,
+			creditLineEntity.UserID,
+			&creditLineEntity.CreditLimit,
+			creditLineEntity.Currency,
+			creditLineEntity.Status,
+			creditLineEntity.CreatedAt,
+			creditLineEntity.UpdatedAt,
+		)
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newCreditLine(
+	id uuid.UUID,
+	userID uuid.UUID,
+	creditLimit *decimal.Big,
+	cur db.Currency,
+	status db.CreditLineStatus,
+	createdAt time.Time,
+	updatedAt time.Time,
+) (*payments.CreditLine, error) {
+	limit, err := newMoneyFromDBEntity(creditLimit, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payments.CreditLine{
+		ID:        id,
+		UserID:    userID,
+		Limit:     limit,
+		Status:    string(status),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+func (impl *Repo) newCreditLineChangeFromDBEntity(entity interface{}) (*payments.CreditLineChange, error) {
+	switch changeEntity := entity.(type) {
+	case *db.CreateCreditLineChangeRow:
+		return newCreditLineChange(&db.CreditLineChange{
+			ID:           changeEntity.ID,
+			CreatedAt:    changeEntity.CreatedAt,
+			CreditLineID: changeEntity.CreditLineID,
+			Currency:     changeEntity.Currency,
+			Action:       changeEntity.Action,
+			Actor:        changeEntity.Actor,
+			Reason:       changeEntity.Reason,
+			CreditLimit:  changeEntity.CreditLimit,
+			Status:       changeEntity.Status,
+		})
+	case *db.ListCreditLineChangesByCreditLineIDRow:
+		return newCreditLineChange(&db.CreditLineChange{
+			ID:           changeEntity.ID,
+			CreatedAt:    changeEntity.CreatedAt,
+			CreditLineID: changeEntity.CreditLineID,
+			Currency:     changeEntity.Currency,
+			Action:       changeEntity.Action,
+			Actor:        changeEntity.Actor,
+			Reason:       changeEntity.Reason,
+			CreditLimit:  changeEntity.CreditLimit,
+			Status:       changeEntity.Status,
+		})
+	case *db.CreditLineChange:
+		return newCreditLineChange(changeEntity)
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newCreditLineChange(entity *db.CreditLineChange) (*payments.CreditLineChange, error) {
+	limit, err := newMoneyFromDBEntity(&entity.CreditLimit, entity.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payments.CreditLineChange{
+		ID:           entity.ID,
+		CreditLineID: entity.CreditLineID,
+		Action:       string(entity.Action),
+		Actor:        entity.Actor,
+		Reason:       entity.Reason,
+		Limit:        limit,
+		Status:       string(entity.Status),
+		CreatedAt:    entity.CreatedAt,
+	}, nil
+}
+
+func (impl *Repo) newPostingFromDBEntity(entity interface{}) (*ledger.Posting, error) {
+	switch postingEntity := entity.(type) {
+	case *db.CreateJournalPostingRow:
+		return newPosting(&db.JournalPosting{
+			ID:             postingEntity.ID,
+			CreatedAt:      postingEntity.CreatedAt,
+			JournalEntryID: postingEntity.JournalEntryID,
+			Account:        postingEntity.Account,
+			Direction:      postingEntity.Direction,
+			Currency:       postingEntity.Currency,
+			Amount:         postingEntity.Amount,
+		})
+	case *db.ListJournalPostingsByPlanIDRow:
+		return newPosting(&db.JournalPosting{
+			ID:             postingEntity.ID,
+			JournalEntryID: postingEntity.JournalEntryID,
+			Account:        postingEntity.Account,
+			Direction:      postingEntity.Direction,
+			Currency:       postingEntity.Currency,
+			Amount:         postingEntity.Amount,
+		})
+	case *db.JournalPosting:
+		return newPosting(postingEntity)
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newPosting(entity *db.JournalPosting) (*ledger.Posting, error) {
+	amount, err := newMoneyFromDBEntity(&entity.Amount, entity.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledger.Posting{
+		ID:        entity.ID,
+		Account:   string(entity.Account),
+		Direction: string(entity.Direction),
+		Amount:    amount,
+	}, nil
+}
+
+func (impl *Repo) newOutboxEventFromDBEntity(entity interface{}) (*outbox.Event, error) {
+	switch eventEntity := entity.(type) {
+	case *db.CreateOutboxEventRow:
+		return newOutboxEvent(&db.OutboxEvent{
+			ID:            eventEntity.ID,
+			CreatedAt:     eventEntity.CreatedAt,
+			PaymentPlanID: eventEntity.PaymentPlanID,
+			EventType:     eventEntity.EventType,
+			Payload:       eventEntity.Payload,
+			PublishedAt:   eventEntity.PublishedAt,
+		}), nil
+	case *db.ListUnpublishedOutboxEventsForUpdateRow:
+		return newOutboxEvent(&db.OutboxEvent{
+			ID:            eventEntity.ID,
+			CreatedAt:     eventEntity.CreatedAt,
+			PaymentPlanID: eventEntity.PaymentPlanID,
+			EventType:     eventEntity.EventType,
+			Payload:       eventEntity.Payload,
+			PublishedAt:   eventEntity.PublishedAt,
+		}), nil
+	case *db.UpdateOutboxEventPublishedAtRow:
+		return newOutboxEvent(&db.OutboxEvent{
+			ID:            eventEntity.ID,
+			CreatedAt:     eventEntity.CreatedAt,
+			PaymentPlanID: eventEntity.PaymentPlanID,
+			EventType:     eventEntity.EventType,
+			Payload:       eventEntity.Payload,
+			PublishedAt:   eventEntity.PublishedAt,
+		}), nil
+	case *db.UpdateOutboxEventFailedAtRow:
+		return newOutboxEvent(&db.OutboxEvent{
+			ID:            eventEntity.ID,
+			CreatedAt:     eventEntity.CreatedAt,
+			PaymentPlanID: eventEntity.PaymentPlanID,
+			EventType:     eventEntity.EventType,
+			Payload:       eventEntity.Payload,
+			PublishedAt:   eventEntity.PublishedAt,
+		}), nil
+	case *db.OutboxEvent:
+		return newOutboxEvent(eventEntity), nil
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func (impl *Repo) newWebhookEndpointFromDBEntity(entity interface{}) (*webhook.Endpoint, error) {
+	switch endpointEntity := entity.(type) {
+	case *db.CreateWebhookEndpointRow:
+		return newWebhookEndpoint(&db.WebhookEndpoint{
+			ID:         endpointEntity.ID,
+			CreatedAt:  endpointEntity.CreatedAt,
+			MerchantID: endpointEntity.MerchantID,
+			Url:        endpointEntity.Url,
+			Secret:     endpointEntity.Secret,
+		}), nil
+	case *db.GetWebhookEndpointByIDRow:
+		return newWebhookEndpoint(&db.WebhookEndpoint{
+			ID:         endpointEntity.ID,
+			CreatedAt:  endpointEntity.CreatedAt,
+			MerchantID: endpointEntity.MerchantID,
+			Url:        endpointEntity.Url,
+			Secret:     endpointEntity.Secret,
+		}), nil
+	case *db.ListWebhookEndpointsByMerchantIDRow:
+		return newWebhookEndpoint(&db.WebhookEndpoint{
+			ID:         endpointEntity.ID,
+			CreatedAt:  endpointEntity.CreatedAt,
+			MerchantID: endpointEntity.MerchantID,
+			Url:        endpointEntity.Url,
+			Secret:     endpointEntity.Secret,
+		}), nil
+	case *db.WebhookEndpoint:
+		return newWebhookEndpoint(endpointEntity), nil
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newWebhookEndpoint(entity *db.WebhookEndpoint) *webhook.Endpoint {
+	return &webhook.Endpoint{
+		ID:         entity.ID,
+		MerchantID: entity.MerchantID,
+		URL:        entity.Url,
+		Secret:     entity.Secret,
+		CreatedAt:  entity.CreatedAt,
+	}
+}
+
+func (impl *Repo) newWebhookDeliveryFromDBEntity(entity interface{}) (*webhook.Delivery, error) {
+	switch deliveryEntity := entity.(type) {
+	case *db.GetWebhookDeliveryByIDForUpdateRow:
+		return newWebhookDelivery(&db.WebhookDelivery{
+			ID:                deliveryEntity.ID,
+			CreatedAt:         deliveryEntity.CreatedAt,
+			UpdatedAt:         deliveryEntity.UpdatedAt,
+			WebhookEndpointID: deliveryEntity.WebhookEndpointID,
+			OutboxEventID:     deliveryEntity.OutboxEventID,
+			EventType:         deliveryEntity.EventType,
+			Payload:           deliveryEntity.Payload,
+			Status:            deliveryEntity.Status,
+			Attempts:          deliveryEntity.Attempts,
+			NextAttemptAt:     deliveryEntity.NextAttemptAt,
+			LastError:         deliveryEntity.LastError,
+			DeliveredAt:       deliveryEntity.DeliveredAt,
+		}), nil
+	case *db.ListDueWebhookDeliveriesForUpdateRow:
+		return newWebhookDelivery(&db.WebhookDelivery{
+			ID:                deliveryEntity.ID,
+			CreatedAt:         deliveryEntity.CreatedAt,
+			UpdatedAt:         deliveryEntity.UpdatedAt,
+			WebhookEndpointID: deliveryEntity.WebhookEndpointID,
+			OutboxEventID:     deliveryEntity.OutboxEventID,
+			EventType:         deliveryEntity.EventType,
+			Payload:           deliveryEntity.Payload,
+			Status:            deliveryEntity.Status,
+			Attempts:          deliveryEntity.Attempts,
+			NextAttemptAt:     deliveryEntity.NextAttemptAt,
+			LastError:         deliveryEntity.LastError,
+			DeliveredAt:       deliveryEntity.DeliveredAt,
+		}), nil
+	case *db.ListWebhookDeliveriesByStatusRow:
+		return newWebhookDelivery(&db.WebhookDelivery{
+			ID:                deliveryEntity.ID,
+			CreatedAt:         deliveryEntity.CreatedAt,
+			UpdatedAt:         deliveryEntity.UpdatedAt,
+			WebhookEndpointID: deliveryEntity.WebhookEndpointID,
+			OutboxEventID:     deliveryEntity.OutboxEventID,
+			EventType:         deliveryEntity.EventType,
+			Payload:           deliveryEntity.Payload,
+			Status:            deliveryEntity.Status,
+			Attempts:          deliveryEntity.Attempts,
+			NextAttemptAt:     deliveryEntity.NextAttemptAt,
+			LastError:         deliveryEntity.LastError,
+			DeliveredAt:       deliveryEntity.DeliveredAt,
+		}), nil
+	case *db.UpdateWebhookDeliveryAttemptRow:
+		return newWebhookDelivery(&db.WebhookDelivery{
+			ID:                deliveryEntity.ID,
+			CreatedAt:         deliveryEntity.CreatedAt,
+			UpdatedAt:         deliveryEntity.UpdatedAt,
+			WebhookEndpointID: deliveryEntity.WebhookEndpointID,
+			OutboxEventID:     deliveryEntity.OutboxEventID,
+			EventType:         deliveryEntity.EventType,
+			Payload:           deliveryEntity.Payload,
+			Status:            deliveryEntity.Status,
+			Attempts:          deliveryEntity.Attempts,
+			NextAttemptAt:     deliveryEntity.NextAttemptAt,
+			LastError:         deliveryEntity.LastError,
+			DeliveredAt:       deliveryEntity.DeliveredAt,
+		}), nil
+	case *db.WebhookDelivery:
+		return newWebhookDelivery(deliveryEntity), nil
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newWebhookDelivery(entity *db.WebhookDelivery) *webhook.Delivery {
+	delivery := &webhook.Delivery{
+		ID:            entity.ID,
+		EndpointID:    entity.WebhookEndpointID,
+		EventID:       entity.OutboxEventID,
+		EventType:     string(entity.EventType),
+		Payload:       entity.Payload,
+		Status:        string(entity.Status),
+		Attempts:      int(entity.Attempts),
+		NextAttemptAt: entity.NextAttemptAt,
+		LastError:     entity.LastError,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+
+	if entity.DeliveredAt.Valid {
+		deliveredAt := entity.DeliveredAt.Time
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	return delivery
+}
+
+func newOutboxEvent(entity *db.OutboxEvent) *outbox.Event {
+	event := &outbox.Event{
+		ID:            entity.ID,
+		PaymentPlanID: entity.PaymentPlanID,
+		Type:          string(entity.EventType),
+		Payload:       entity.Payload,
+		CreatedAt:     entity.CreatedAt,
+	}
+
+	if entity.PublishedAt.Valid {
+		publishedAt := entity.PublishedAt.Time
+		event.PublishedAt = &publishedAt
+	}
+
+	return event
+}
+
+func (impl *Repo) newCollectionsCaseFromDBEntity(entity interface{}) (*collections.Case, error) {
+	switch caseEntity := entity.(type) {
+	case *db.CreateCollectionsCaseRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.GetCollectionsCaseByIDRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.GetCollectionsCaseByIDForUpdateRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.GetActiveCollectionsCaseByPaymentPlanIDRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.ListDueCollectionsCasesRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.ListSettledCollectionsCasesRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.ListCollectionsCasesByStatusRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.UpdateCollectionsCaseRow:
+		return newCollectionsCase(&db.CollectionsCase{
+			ID:            caseEntity.ID,
+			CreatedAt:     caseEntity.CreatedAt,
+			UpdatedAt:     caseEntity.UpdatedAt,
+			PaymentPlanID: caseEntity.PaymentPlanID,
+			UserID:        caseEntity.UserID,
+			Status:        caseEntity.Status,
+			Stage:         caseEntity.Stage,
+			NextActionAt:  caseEntity.NextActionAt,
+			OpenedAt:      caseEntity.OpenedAt,
+			ClosedAt:      caseEntity.ClosedAt,
+		}), nil
+	case *db.CollectionsCase:
+		return newCollectionsCase(caseEntity), nil
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newCollectionsCase(entity *db.CollectionsCase) *collections.Case {
+	collectionsCase := &collections.Case{
+		ID:            entity.ID,
+		PaymentPlanID: entity.PaymentPlanID,
+		UserID:        entity.UserID,
+		Status:        string(entity.Status),
+		Stage:         entity.Stage,
+		OpenedAt:      entity.OpenedAt,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+
+	if entity.NextActionAt.Valid {
+		nextActionAt := entity.NextActionAt.Time
+		collectionsCase.NextActionAt = &nextActionAt
+	}
+
+	if entity.ClosedAt.Valid {
+		closedAt := entity.ClosedAt.Time
+		collectionsCase.ClosedAt = &closedAt
+	}
+
+	return collectionsCase
+}
+
+func (impl *Repo) newCollectionsCaseEventFromDBEntity(entity interface{}) (*collections.Event, error) {
+	switch eventEntity := entity.(type) {
+	case *db.CreateCollectionsCaseEventRow:
+		return newCollectionsCaseEvent(&db.CollectionsCaseEvent{
+			ID:                eventEntity.ID,
+			CreatedAt:         eventEntity.CreatedAt,
+			CollectionsCaseID: eventEntity.CollectionsCaseID,
+			Kind:              eventEntity.Kind,
+			Stage:             eventEntity.Stage,
+			Actor:             eventEntity.Actor,
+			Note:              eventEntity.Note,
+		}), nil
+	case *db.ListCollectionsCaseEventsByCaseIDRow:
+		return newCollectionsCaseEvent(&db.CollectionsCaseEvent{
+			ID:                eventEntity.ID,
+			CreatedAt:         eventEntity.CreatedAt,
+			CollectionsCaseID: eventEntity.CollectionsCaseID,
+			Kind:              eventEntity.Kind,
+			Stage:             eventEntity.Stage,
+			Actor:             eventEntity.Actor,
+			Note:              eventEntity.Note,
+		}), nil
+	case *db.CollectionsCaseEvent:
+		return newCollectionsCaseEvent(eventEntity), nil
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newCollectionsCaseEvent(entity *db.CollectionsCaseEvent) *collections.Event {
+	return &collections.Event{
+		ID:        entity.ID,
+		CaseID:    entity.CollectionsCaseID,
+		Kind:      string(entity.Kind),
+		Stage:     entity.Stage,
+		Actor:     entity.Actor,
+		Note:      entity.Note,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+func (impl *Repo) newLineItemFromDBEntity(entity interface{}) (*payments.LineItem, error) {
+	switch lineItemEntity := entity.(type) {
+	case *db.CreatePaymentPlanLineItemRow:
+		return newLineItem(&db.PaymentPlanLineItem{
+			ID:            lineItemEntity.ID,
+			CreatedAt:     lineItemEntity.CreatedAt,
+			UpdatedAt:     lineItemEntity.UpdatedAt,
+			PaymentPlanID: lineItemEntity.PaymentPlanID,
+			Position:      lineItemEntity.Position,
+			Sku:           lineItemEntity.Sku,
+			Name:          lineItemEntity.Name,
+			Quantity:      lineItemEntity.Quantity,
+			Currency:      lineItemEntity.Currency,
+			UnitPrice:     lineItemEntity.UnitPrice,
+		})
+	case *db.ListPaymentPlanLineItemsByPlanIDRow:
+		return newLineItem(&db.PaymentPlanLineItem{
+			ID:            lineItemEntity.ID,
+			CreatedAt:     lineItemEntity.CreatedAt,
+			UpdatedAt:     lineItemEntity.UpdatedAt,
+			PaymentPlanID: lineItemEntity.PaymentPlanID,
+			Position:      lineItemEntity.Position,
+			Sku:           lineItemEntity.Sku,
+			Name:          lineItemEntity.Name,
+			Quantity:      lineItemEntity.Quantity,
+			Currency:      lineItemEntity.Currency,
+			UnitPrice:     lineItemEntity.UnitPrice,
+		})
+	case *db.PaymentPlanLineItem:
+		return newLineItem(lineItemEntity)
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+func newLineItem(entity *db.PaymentPlanLineItem) (*payments.LineItem, error) {
+	unitPrice, err := newMoneyFromDBEntity(&entity.UnitPrice, entity.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payments.LineItem{
+		ID:            entity.ID,
+		PaymentPlanID: entity.PaymentPlanID,
+		Position:      entity.Position,
+		SKU:           entity.Sku,
+		Name:          entity.Name,
+		Quantity:      entity.Quantity,
+		UnitPrice:     unitPrice,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}, nil
+}
+
+func (impl *Repo) newAuditEntryFromDBEntity(entity interface{}) (*audit.Entry, error) {
+	switch entryEntity := entity.(type) {
+	case *db.CreateAuditEntryRow:
+		return newAuditEntry(&db.AuditEntry{
+			ID:             entryEntity.ID,
+			PaymentPlanID:  entryEntity.PaymentPlanID,
+			Sequence:       entryEntity.Sequence,
+			ActorType:      entryEntity.ActorType,
+			ActorID:        entryEntity.ActorID,
+			Action:         entryEntity.Action,
+			BeforeSnapshot: entryEntity.BeforeSnapshot,
+			AfterSnapshot:  entryEntity.AfterSnapshot,
+			RequestID:      entryEntity.RequestID,
+			OccurredAt:     entryEntity.OccurredAt,
+			PrevHash:       entryEntity.PrevHash,
+			Hash:           entryEntity.Hash,
+		})
+	case *db.GetLatestAuditEntryByPlanIDRow:
+		return newAuditEntry(&db.AuditEntry{
+			ID:             entryEntity.ID,
+			PaymentPlanID:  entryEntity.PaymentPlanID,
+			Sequence:       entryEntity.Sequence,
+			ActorType:      entryEntity.ActorType,
+			ActorID:        entryEntity.ActorID,
+			Action:         entryEntity.Action,
+			BeforeSnapshot: entryEntity.BeforeSnapshot,
+			AfterSnapshot:  entryEntity.AfterSnapshot,
+			RequestID:      entryEntity.RequestID,
+			OccurredAt:     entryEntity.OccurredAt,
+			PrevHash:       entryEntity.PrevHash,
+			Hash:           entryEntity.Hash,
+		})
+	case *db.ListAuditEntriesByPlanIDRow:
+		return newAuditEntry(&db.AuditEntry{
+			ID:             entryEntity.ID,
+			PaymentPlanID:  entryEntity.PaymentPlanID,
+			Sequence:       entryEntity.Sequence,
+			ActorType:      entryEntity.ActorType,
+			ActorID:        entryEntity.ActorID,
+			Action:         entryEntity.Action,
+			BeforeSnapshot: entryEntity.BeforeSnapshot,
+			AfterSnapshot:  entryEntity.AfterSnapshot,
+			RequestID:      entryEntity.RequestID,
+			OccurredAt:     entryEntity.OccurredAt,
+			PrevHash:       entryEntity.PrevHash,
+			Hash:           entryEntity.Hash,
+		})
+	case *db.AuditEntry:
+		return newAuditEntry(entryEntity)
+	default:
+		return nil, UnsupportedDBEntityError{}
+	}
+}
+
+// newAuditEntry the snapshots are decoded into their types so an entry hashes
+// the same once read back, whatever the database did to the json
+func newAuditEntry(entity *db.AuditEntry) (*audit.Entry, error) {
+	entry := &audit.Entry{
+		ID:            entity.ID,
+		PaymentPlanID: entity.PaymentPlanID,
+		Sequence:      entity.Sequence,
+		Actor:         audit.Actor{Type: string(entity.ActorType), ID: entity.ActorID},
+		Action:        string(entity.Action),
+		RequestID:     entity.RequestID.String,
+		OccurredAt:    entity.OccurredAt,
+		PrevHash:      entity.PrevHash,
+		Hash:          entity.Hash,
+	}
+
+	if err := json.Unmarshal(entity.BeforeSnapshot, &entry.Before); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(entity.AfterSnapshot, &entry.After); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// newDuplicateKeyError maps a unique violation to repo.DuplicateKeyError, any other error is returned as it is
+func newDuplicateKeyError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return repo.DuplicateKeyError{Constraint: pgErr.ConstraintName, Err: err}
+	}
+
+	return err
+}
+
+// newNullString an empty string is stored as NULL
+func newNullString(value string) sql.NullString {
+	if value == "" {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: value, Valid: true}
+}
+
+// newNullTime a nil time is stored as NULL
+func newNullTime(at *time.Time) sql.NullTime {
+	if at == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *at, Valid: true}
+}
+
+// newMoneyFromDBEntity a stored amount is finite and in a currency of the currency enum,
+// an error means the registry is missing a currency of the enum
+func newMoneyFromDBEntity(amount *decimal.Big, cur db.Currency) (payments.Money, error) {
+	return payments.NewMoney(amount, string(cur))
 }
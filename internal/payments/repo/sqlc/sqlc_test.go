@@ -2,6 +2,9 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -10,18 +13,27 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"golangreferenceapi/database"
 	"golangreferenceapi/internal/db"
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/collections"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/webhook"
 
 	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/monacohq/golang-common/database/pginit"
@@ -125,7 +137,7 @@ func TestMain(m *testing.M) {
 	}
 
 	testQuerier = db.New(testRefPoolConn)
-	testRefRepo = NewSQLCRepository(testQuerier)
+	testRefRepo = NewSQLCRepository(testRefPoolConn)
 
 	code := m.Run()
 
@@ -140,14 +152,88 @@ func TestMain(m *testing.M) {
 func TestNewSQLCRepository(t *testing.T) {
 	t.Parallel()
 
-	querier := db.New(&pgx.Conn{})
-	repo := NewSQLCRepository(querier)
+	repo := NewSQLCRepository(&pgx.Conn{})
 
 	if reflect.TypeOf(repo) != reflect.TypeOf(&Repo{}) {
 		t.Errorf("returned testRefRepo is not of Repo")
 	}
 }
 
+func TestSQLCRepo_WithTx(t *testing.T) {
+	t.Parallel()
+
+	errDummy := errors.New("dummy")
+
+	testcases := []struct {
+		testName    string
+		fnErr       error
+		expectErr   bool
+		expectPlans int
+	}{
+		{
+			testName:    "commit",
+			fnErr:       nil,
+			expectErr:   false,
+			expectPlans: 1,
+		},
+		{
+			testName:    "rollback",
+			fnErr:       errDummy,
+			expectErr:   true,
+			expectPlans: 0,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			userID := uuid.Must(uuid.NewV4())
+
+			err := testRefRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+				plan, err := txRepo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
+					UserID: userID,
+					Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+					Status: "pending",
+				})
+				if err != nil {
+					return err
+				}
+
+				if _, err := txRepo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
+					PaymentPlanID: plan.ID,
+					Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+					DueAt:         time.Now().UTC().Truncate(time.Microsecond),
+					Status:        "pending",
+					Version:       1,
+				}); err != nil {
+					return err
+				}
+
+				return testcase.fnErr
+			})
+			if testcase.expectErr != (err != nil) {
+				t.Errorf("unexpected err result: %v", err)
+			}
+
+			if testcase.fnErr != nil && !errors.Is(err, testcase.fnErr) {
+				t.Errorf("expected the unit of work error to be returned, got: %v", err)
+			}
+
+			plans, err := testRefRepo.ListPaymentPlansByUserID(context.Background(), userID)
+			if err != nil {
+				t.Fatalf("list payment plans err: %v", err)
+			}
+
+			if len(plans) != testcase.expectPlans {
+				t.Errorf("expect %v plans but %v returned", testcase.expectPlans, len(plans))
+			}
+		})
+	}
+}
+
 func TestSQLCRepo_CreatePaymentPlan(t *testing.T) {
 	t.Parallel()
 
@@ -162,24 +248,22 @@ func TestSQLCRepo_CreatePaymentPlan(t *testing.T) {
 		{
 			testName: "happy",
 			paramArg: &payments.CreatePlanParams{
-				UserID:   userUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(1098, 2),
-				Status:   "pending",
+				UserID: userUUID,
+				Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				Status: "pending",
 			},
 			expectErr: false,
 			expectRow: &payments.Plan{
-				UserID:   userUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(1098, 2),
-				Status:   "pending",
+				UserID: userUUID,
+				Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				Status: "pending",
 			},
 		},
 		{
 			testName: "currency field nil",
 			paramArg: &payments.CreatePlanParams{
 				UserID: userUUID,
-				Amount: *decimal.New(1098, 2),
+				Amount: payments.Money{},
 				Status: "pending",
 			},
 			expectErr: true,
@@ -187,27 +271,24 @@ func TestSQLCRepo_CreatePaymentPlan(t *testing.T) {
 		{
 			testName: "negative amount",
 			paramArg: &payments.CreatePlanParams{
-				UserID:   userUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(-1099, 2),
-				Status:   "pending",
+				UserID: userUUID,
+				Amount: payments.MustNewMoney(decimal.New(-1099, 2), "usdc"),
+				Status: "pending",
 			},
 			expectErr: true,
 		},
 		{
 			testName: "decimal wrong precision",
 			paramArg: &payments.CreatePlanParams{
-				UserID:   userUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(31485937839476927, 16),
-				Status:   "pending",
+				UserID: userUUID,
+				Amount: payments.MustNewMoney(decimal.New(31485937839476927, 16), "usdc"),
+				Status: "pending",
 			},
 			expectErr: false,
 			expectRow: &payments.Plan{
-				UserID:   userUUID,
-				Currency: "usdc",
-				Amount:   *decimal.New(31485937839476927, 16),
-				Status:   "pending",
+				UserID: userUUID,
+				Amount: payments.MustNewMoney(decimal.New(31485937839476927, 16), "usdc"),
+				Status: "pending",
 			},
 		},
 	}
@@ -238,11 +319,11 @@ func TestSQLCRepo_CreatePaymentPlan(t *testing.T) {
 				t.Errorf("wrong expected user id: got %v, want %v", testcase.expectRow.UserID, pp.UserID)
 			}
 
-			if pp.Currency != testcase.expectRow.Currency {
-				t.Errorf("wrong expected currency: got %v, want %v", testcase.expectRow.Currency, pp.Currency)
+			if pp.Amount.Currency() != testcase.expectRow.Amount.Currency() {
+				t.Errorf("wrong expected currency: got %v, want %v", testcase.expectRow.Amount.Currency(), pp.Amount.Currency())
 			}
 
-			if pp.Amount.Cmp(&testcase.expectRow.Amount) != 0 {
+			if pp.Amount.Amount().Cmp(testcase.expectRow.Amount.Amount()) != 0 {
 				t.Errorf("wrong expected amount: got %v, want %v", testcase.expectRow.Amount, pp.Amount)
 			}
 
@@ -292,6 +373,98 @@ func TestSQLCRepo_CreatePaymentPlan_ExistingID(t *testing.T) {
 	}
 }
 
+func TestSQLCRepo_CreatePaymentPlan_ClientID(t *testing.T) {
+	t.Parallel()
+
+	planID := uuid.Must(uuid.NewV4())
+	arg := &payments.CreatePlanParams{
+		ID:     planID,
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	}
+
+	plan, err := testRefRepo.CreatePaymentPlan(context.Background(), arg)
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	if plan.ID != planID {
+		t.Errorf("wrong expected id: got %v, want %v", plan.ID, planID)
+	}
+
+	_, err = testRefRepo.CreatePaymentPlan(context.Background(), arg)
+
+	var duplicateKey repo.DuplicateKeyError
+	if !errors.As(err, &duplicateKey) || duplicateKey.Constraint != repo.ConstraintPaymentPlansPkey {
+		t.Errorf("expected a duplicate key error on the primary key, got %v", err)
+	}
+}
+
+func TestSQLCRepo_newDuplicateKeyError(t *testing.T) {
+	t.Parallel()
+
+	uniqueViolation := &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: repo.ConstraintPaymentPlansPkey}
+
+	var duplicateKey repo.DuplicateKeyError
+	if err := newDuplicateKeyError(fmt.Errorf("insert: %w", uniqueViolation)); !errors.As(err, &duplicateKey) ||
+		duplicateKey.Constraint != repo.ConstraintPaymentPlansPkey || !errors.Is(err, uniqueViolation) {
+		t.Errorf("expected a duplicate key error on the primary key, got %v", err)
+	}
+
+	otherErr := &pgconn.PgError{Code: "23503"}
+	if err := newDuplicateKeyError(otherErr); err != otherErr {
+		t.Errorf("expected any other error to be returned as it is, got %v", err)
+	}
+}
+
+func TestSQLCRepo_GetPaymentPlanByID(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+
+	testcases := []struct {
+		testName  string
+		paramID   uuid.UUID
+		expectErr error
+	}{
+		{
+			testName: "happy",
+			paramID:  existingPlan.ID,
+		},
+		{
+			testName:  "plan does not exist",
+			paramID:   uuid.Must(uuid.NewV4()),
+			expectErr: repo.ErrRecordNotFound,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := testRefRepo.GetPaymentPlanByID(context.Background(), testcase.paramID)
+			if !errors.Is(err, testcase.expectErr) {
+				t.Fatalf("unexpected err: got %v, want %v", err, testcase.expectErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if plan.ID != existingPlan.ID {
+				t.Errorf("wrong expected id: got %v, want %v", plan.ID, existingPlan.ID)
+			}
+
+			if plan.Amount.Amount().Cmp(existingPlan.Amount.Amount()) != 0 {
+				t.Errorf("wrong expected amount: got %v, want %v", plan.Amount, existingPlan.Amount)
+			}
+		})
+	}
+}
+
 func TestSQLCRepo_ListPaymentPlansByUserID_ListOne(t *testing.T) {
 	t.Parallel()
 
@@ -341,11 +514,11 @@ func TestSQLCRepo_ListPaymentPlansByUserID_ListOne(t *testing.T) {
 				t.Errorf("wrong expected user id: got %v, want %v", plans[0].UserID, existingPlan.UserID)
 			}
 
-			if existingPlan.Currency != plans[0].Currency {
-				t.Errorf("wrong expected currency: got %v, want %v", plans[0].Currency, existingPlan.Currency)
+			if existingPlan.Amount.Currency() != plans[0].Amount.Currency() {
+				t.Errorf("wrong expected currency: got %v, want %v", plans[0].Amount.Currency(), existingPlan.Amount.Currency())
 			}
 
-			if existingPlan.Amount.Cmp(&plans[0].Amount) != 0 {
+			if existingPlan.Amount.Amount().Cmp(plans[0].Amount.Amount()) != 0 {
 				t.Errorf("wrong expected amount: got %v, want %v", plans[0].Amount, existingPlan.Amount)
 			}
 
@@ -396,6 +569,87 @@ func TestSQLCRepo_ListPaymentPlansByUserID_ListMany(t *testing.T) {
 	}
 }
 
+func TestSQLCRepo_UpdatePaymentPlanStatus(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	cancelledPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+
+	if _, err := testRefRepo.UpdatePaymentPlanStatus(context.Background(), &payments.UpdatePlanStatusParams{
+		ID:     cancelledPlan.ID,
+		Status: "cancelled",
+	}); err != nil {
+		t.Fatalf("fail to cancel payment plan: %v", err)
+	}
+
+	testcases := []struct {
+		testName  string
+		paramArg  *payments.UpdatePlanStatusParams
+		expectErr bool
+	}{
+		{
+			testName: "happy",
+			paramArg: &payments.UpdatePlanStatusParams{
+				ID:     existingPlan.ID,
+				Status: "complete",
+			},
+			expectErr: false,
+		},
+		{
+			testName: "plan does not exist",
+			paramArg: &payments.UpdatePlanStatusParams{
+				ID:     uuid.Must(uuid.NewV4()),
+				Status: "complete",
+			},
+			expectErr: true,
+		},
+		{
+			testName: "unknown status",
+			paramArg: &payments.UpdatePlanStatusParams{
+				ID:     existingPlan.ID,
+				Status: "unknown",
+			},
+			expectErr: true,
+		},
+		{
+			testName: "invalid transition",
+			paramArg: &payments.UpdatePlanStatusParams{
+				ID:     cancelledPlan.ID,
+				Status: "complete",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := testRefRepo.UpdatePaymentPlanStatus(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned")
+				}
+
+				return
+			}
+
+			if plan.ID != testcase.paramArg.ID {
+				t.Errorf("wrong expected id: got %v, want %v", plan.ID, testcase.paramArg.ID)
+			}
+
+			if plan.Status != testcase.paramArg.Status {
+				t.Errorf("wrong expected status: got %v, want %v", plan.Status, testcase.paramArg.Status)
+			}
+		})
+	}
+}
+
 func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 	t.Parallel()
 
@@ -411,10 +665,10 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 			testName: "happy",
 			paramArg: &payments.CreateInstallmentParams{
 				PaymentPlanID: createdPlan.ID,
-				Currency:      "usdc",
-				Amount:        *decimal.New(1098, 2),
+				Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 				DueAt:         time.Now().UTC().Truncate(time.Microsecond),
 				Status:        "pending",
+				Version:       1,
 			},
 			expectErr: false,
 		},
@@ -422,10 +676,10 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 			testName: "payment plan does not exist",
 			paramArg: &payments.CreateInstallmentParams{
 				PaymentPlanID: uuid.Must(uuid.NewV4()),
-				Currency:      "usdc",
-				Amount:        *decimal.New(1098, 2),
+				Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 				DueAt:         time.Now().UTC().Truncate(time.Microsecond),
 				Status:        "pending",
+				Version:       1,
 			},
 			expectErr: true,
 		},
@@ -433,10 +687,10 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 			testName: "amount negative",
 			paramArg: &payments.CreateInstallmentParams{
 				PaymentPlanID: createdPlan.ID,
-				Currency:      "usdc",
-				Amount:        *decimal.New(-1098, 2),
+				Amount:        payments.MustNewMoney(decimal.New(-1098, 2), "usdc"),
 				DueAt:         time.Now().UTC().Truncate(time.Microsecond),
 				Status:        "pending",
+				Version:       1,
 			},
 			expectErr: true,
 		},
@@ -444,9 +698,19 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 			testName: "status field nil",
 			paramArg: &payments.CreateInstallmentParams{
 				PaymentPlanID: createdPlan.ID,
-				Currency:      "usdc",
-				Amount:        *decimal.New(1098, 2),
+				Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				DueAt:         time.Now().UTC().Truncate(time.Microsecond),
+				Version:       1,
+			},
+			expectErr: true,
+		},
+		{
+			testName: "version zero",
+			paramArg: &payments.CreateInstallmentParams{
+				PaymentPlanID: createdPlan.ID,
+				Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 				DueAt:         time.Now().UTC().Truncate(time.Microsecond),
+				Status:        "pending",
 			},
 			expectErr: true,
 		},
@@ -454,10 +718,10 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 			testName: "decimal wrong precision",
 			paramArg: &payments.CreateInstallmentParams{
 				PaymentPlanID: createdPlan.ID,
-				Currency:      "usdc",
-				Amount:        *decimal.New(31485937839476927, 16),
+				Amount:        payments.MustNewMoney(decimal.New(31485937839476927, 16), "usdc"),
 				DueAt:         time.Now().UTC().Truncate(time.Microsecond),
 				Status:        "pending",
+				Version:       1,
 			},
 			expectErr: false,
 		},
@@ -484,11 +748,11 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 				t.Errorf("expect uuid but nil returned")
 			}
 
-			if ppi.Currency != testcase.paramArg.Currency {
-				t.Errorf("wrong expected currency: got %v, want %v", testcase.paramArg.Currency, ppi.Currency)
+			if ppi.Amount.Currency() != testcase.paramArg.Amount.Currency() {
+				t.Errorf("wrong expected currency: got %v, want %v", testcase.paramArg.Amount.Currency(), ppi.Amount.Currency())
 			}
 
-			if ppi.Amount.Cmp(&testcase.paramArg.Amount) != 0 {
+			if ppi.Amount.Amount().Cmp(testcase.paramArg.Amount.Amount()) != 0 {
 				t.Errorf("wrong expected amount: got %v, want %v", testcase.paramArg.Amount, ppi.Amount)
 			}
 
@@ -499,6 +763,10 @@ func TestSQLCRepo_CreatePaymentInstallment(t *testing.T) {
 			if ppi.Status != testcase.paramArg.Status {
 				t.Errorf("wrong expected status: got %v, want %v", testcase.paramArg.Status, ppi.Status)
 			}
+
+			if ppi.Version != testcase.paramArg.Version {
+				t.Errorf("wrong expected version: got %v, want %v", testcase.paramArg.Version, ppi.Version)
+			}
 		})
 	}
 }
@@ -585,11 +853,11 @@ func TestSQLCRepo_ListPaymentInstallmentsByPlanID_ListOne(t *testing.T) {
 				return
 			}
 
-			if createdInstallment.Currency != installments[0].Currency {
-				t.Errorf("wrong expected currency: got %v, want %v", installments[0].Currency, createdInstallment.Currency)
+			if createdInstallment.Amount.Currency() != installments[0].Amount.Currency() {
+				t.Errorf("wrong expected currency: got %v, want %v", installments[0].Amount.Currency(), createdInstallment.Amount.Currency())
 			}
 
-			if createdInstallment.Amount.Cmp(&installments[0].Amount) != 0 {
+			if createdInstallment.Amount.Amount().Cmp(installments[0].Amount.Amount()) != 0 {
 				t.Errorf("wrong expected amount: got %v, want %v", installments[0].Amount, createdInstallment.Amount)
 			}
 
@@ -645,38 +913,48 @@ func TestSQLCRepo_ListPaymentInstallmentsByPlanID_ListMany(t *testing.T) {
 	}
 }
 
-func TestSQLCRepo_newPlanFromDBEntity(t *testing.T) {
+func TestSQLCRepo_UpdatePaymentInstallmentStatus(t *testing.T) {
 	t.Parallel()
 
-	querier := db.New(&pgx.Conn{})
-	repo := NewSQLCRepository(querier)
+	createdPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	createdInstallment := createRandomPaymentPlanInstallment(t, createdPlan.ID)
+	voidInstallment := createRandomPaymentPlanInstallment(t, createdPlan.ID)
 
-	type unsupportedStruct struct{}
+	if _, err := testRefRepo.UpdatePaymentInstallmentStatus(context.Background(), &payments.UpdateInstallmentStatusParams{
+		ID:     voidInstallment.ID,
+		Status: "void",
+	}); err != nil {
+		t.Fatalf("fail to void installment: %v", err)
+	}
 
 	testcases := []struct {
-		testName      string
-		paramDBEntity interface{}
-		expectErr     bool
+		testName  string
+		paramArg  *payments.UpdateInstallmentStatusParams
+		expectErr bool
 	}{
 		{
-			testName:      "happy - CreatePaymentPlanRow",
-			paramDBEntity: &db.CreatePaymentPlanRow{},
-			expectErr:     false,
-		},
-		{
-			testName:      "happy - ListPaymentPlansByUserIDRow",
-			paramDBEntity: &db.ListPaymentPlansByUserIDRow{},
-			expectErr:     false,
+			testName: "happy",
+			paramArg: &payments.UpdateInstallmentStatusParams{
+				ID:     createdInstallment.ID,
+				Status: "paid",
+			},
+			expectErr: false,
 		},
 		{
-			testName:      "happy - PaymentPlan",
-			paramDBEntity: &db.PaymentPlan{},
-			expectErr:     false,
+			testName: "installment does not exist",
+			paramArg: &payments.UpdateInstallmentStatusParams{
+				ID:     uuid.Must(uuid.NewV4()),
+				Status: "paid",
+			},
+			expectErr: true,
 		},
 		{
-			testName:      "failed - unsupported DB entity type",
-			paramDBEntity: &unsupportedStruct{},
-			expectErr:     true,
+			testName: "invalid transition",
+			paramArg: &payments.UpdateInstallmentStatusParams{
+				ID:     voidInstallment.ID,
+				Status: "paid",
+			},
+			expectErr: true,
 		},
 	}
 
@@ -686,51 +964,63 @@ func TestSQLCRepo_newPlanFromDBEntity(t *testing.T) {
 		t.Run(testcase.testName, func(t *testing.T) {
 			t.Parallel()
 
-			plan, err := repo.newPlanFromDBEntity(testcase.paramDBEntity)
-
-			if testcase.expectErr {
-				if err == nil {
-					t.Errorf("expected err but nil returned")
-				}
-			} else {
-				if reflect.TypeOf(plan) != reflect.TypeOf(&payments.Plan{}) {
-					t.Errorf("returned entity is not of *payments.Plan")
+			installment, err := testRefRepo.UpdatePaymentInstallmentStatus(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned")
 				}
+
+				return
+			}
+
+			if installment.ID != testcase.paramArg.ID {
+				t.Errorf("wrong expected id: got %v, want %v", installment.ID, testcase.paramArg.ID)
+			}
+
+			if installment.Status != testcase.paramArg.Status {
+				t.Errorf("wrong expected status: got %v, want %v", installment.Status, testcase.paramArg.Status)
 			}
 		})
 	}
 }
 
-func TestSQLCRepo_newInstallmentFromDBEntity(t *testing.T) {
+func TestSQLCRepo_UpdatePaymentInstallmentAmount(t *testing.T) {
 	t.Parallel()
 
-	querier := db.New(&pgx.Conn{})
-	repo := NewSQLCRepository(querier)
+	createdPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	createdInstallment := createRandomPaymentPlanInstallment(t, createdPlan.ID)
 
 	testcases := []struct {
-		testName      string
-		paramDBEntity interface{}
-		expectErr     bool
+		testName  string
+		paramArg  *payments.UpdateInstallmentAmountParams
+		expectErr bool
 	}{
 		{
-			testName:      "happy - CreatePaymentInstallmentsRow",
-			paramDBEntity: &db.CreatePaymentInstallmentsRow{},
-			expectErr:     false,
-		},
-		{
-			testName:      "happy - ListPaymentInstallmentsByPlanIDRow",
-			paramDBEntity: &db.ListPaymentInstallmentsByPlanIDRow{},
-			expectErr:     false,
+			testName: "happy",
+			paramArg: &payments.UpdateInstallmentAmountParams{
+				ID:     createdInstallment.ID,
+				Amount: payments.MustNewMoney(decimal.New(1, 0), createdInstallment.Amount.Currency()),
+			},
+			expectErr: false,
 		},
 		{
-			testName:      "happy - PaymentInstallment",
-			paramDBEntity: &db.PaymentInstallment{},
-			expectErr:     false,
+			testName: "installment does not exist",
+			paramArg: &payments.UpdateInstallmentAmountParams{
+				ID:     uuid.Must(uuid.NewV4()),
+				Amount: payments.MustNewMoney(decimal.New(1, 0), createdInstallment.Amount.Currency()),
+			},
+			expectErr: true,
 		},
 		{
-			testName:      "failed - json err",
-			paramDBEntity: "some invalid input",
-			expectErr:     true,
+			testName: "amount not positive",
+			paramArg: &payments.UpdateInstallmentAmountParams{
+				ID:     createdInstallment.ID,
+				Amount: payments.MustNewMoney(decimal.New(0, 0), createdInstallment.Amount.Currency()),
+			},
+			expectErr: true,
 		},
 	}
 
@@ -740,11 +1030,2883 @@ func TestSQLCRepo_newInstallmentFromDBEntity(t *testing.T) {
 		t.Run(testcase.testName, func(t *testing.T) {
 			t.Parallel()
 
-			plan, err := repo.newInstallmentFromDBEntity(testcase.paramDBEntity)
-
-			if testcase.expectErr {
-				if err == nil {
-					t.Errorf("expected err but nil returned")
+			installment, err := testRefRepo.UpdatePaymentInstallmentAmount(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned")
+				}
+
+				return
+			}
+
+			if installment.Amount.String() != testcase.paramArg.Amount.String() {
+				t.Errorf("wrong expected amount: got %v, want %v", installment.Amount, testcase.paramArg.Amount)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_UpdatePaymentInstallmentsStatusDueBefore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	completePlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	pendingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+
+	if _, err := testRefRepo.UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+		ID:     completePlan.ID,
+		Status: "complete",
+	}); err != nil {
+		t.Fatalf("fail to complete payment plan: %v", err)
+	}
+
+	// created with a due date of now
+	pastInstallment := createRandomPaymentPlanInstallment(t, completePlan.ID)
+	pendingPlanInstallment := createRandomPaymentPlanInstallment(t, pendingPlan.ID)
+
+	planIDs, err := testRefRepo.LockPaymentPlansWithInstallmentsPastDue(
+		ctx,
+		&payments.LockPlansWithInstallmentsPastDueParams{
+			PlanStatus:    "complete",
+			DueBefore:     time.Now().UTC().Add(time.Minute),
+			OverdueBefore: time.Now().UTC().Add(-time.Hour),
+		},
+	)
+	if err != nil {
+		t.Fatalf("fail to lock payment plans: %v", err)
+	}
+
+	locked := false
+
+	for _, planID := range planIDs {
+		if planID == pendingPlan.ID {
+			t.Errorf("pending plan was locked")
+		}
+
+		if planID == completePlan.ID {
+			locked = true
+		}
+	}
+
+	if !locked {
+		t.Errorf("plan with an installment past due was not locked")
+	}
+
+	updated, err := testRefRepo.UpdatePaymentInstallmentsStatusDueBefore(
+		ctx,
+		&payments.UpdateInstallmentsStatusDueBeforeParams{
+			PlanID:     completePlan.ID,
+			FromStatus: "pending",
+			ToStatus:   "due",
+			DueBefore:  time.Now().UTC().Add(time.Minute),
+		},
+	)
+	if err != nil {
+		t.Fatalf("fail to update installments: %v", err)
+	}
+
+	found := false
+
+	for _, inst := range updated {
+		if inst.ID == pendingPlanInstallment.ID {
+			t.Errorf("installment of another plan was updated")
+		}
+
+		if inst.ID == pastInstallment.ID {
+			found = true
+
+			if inst.Status != "due" {
+				t.Errorf("wrong expected status: got %v, want due", inst.Status)
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("installment past due was not updated")
+	}
+}
+
+func TestSQLCRepo_ListUnpaidPaymentInstallmentsDueBetween(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	completePlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	pendingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+
+	if _, err := testRefRepo.UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+		ID:     completePlan.ID,
+		Status: "complete",
+	}); err != nil {
+		t.Fatalf("fail to complete payment plan: %v", err)
+	}
+
+	// created with a due date of now
+	unpaidInstallment := createRandomPaymentPlanInstallment(t, completePlan.ID)
+	paidInstallment := createRandomPaymentPlanInstallment(t, completePlan.ID)
+	pendingPlanInstallment := createRandomPaymentPlanInstallment(t, pendingPlan.ID)
+
+	if _, err := testRefRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     paidInstallment.ID,
+		Status: "paid",
+	}); err != nil {
+		t.Fatalf("fail to pay installment: %v", err)
+	}
+
+	now := time.Now().UTC()
+
+	listed, err := testRefRepo.ListUnpaidPaymentInstallmentsDueBetween(
+		ctx,
+		&payments.ListUnpaidInstallmentsDueBetweenParams{
+			PlanStatus: "complete",
+			DueAfter:   now.Add(-time.Hour),
+			DueBefore:  now.Add(time.Minute),
+		},
+	)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	found := false
+
+	for _, inst := range listed {
+		switch inst.ID {
+		case unpaidInstallment.ID:
+			found = true
+		case paidInstallment.ID:
+			t.Errorf("paid installment was listed")
+		case pendingPlanInstallment.ID:
+			t.Errorf("installment of a pending plan was listed")
+		}
+	}
+
+	if !found {
+		t.Errorf("unpaid installment was not listed")
+	}
+
+	listed, err = testRefRepo.ListUnpaidPaymentInstallmentsDueBetween(
+		ctx,
+		&payments.ListUnpaidInstallmentsDueBetweenParams{
+			PlanStatus: "complete",
+			DueAfter:   now.Add(time.Minute),
+			DueBefore:  now.Add(time.Hour),
+		},
+	)
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	for _, inst := range listed {
+		if inst.ID == unpaidInstallment.ID {
+			t.Errorf("installment due before the window was listed")
+		}
+	}
+}
+
+func TestSQLCRepo_CreateReminderSent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	plan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	installment := createRandomPaymentPlanInstallment(t, plan.ID)
+
+	for _, want := range []bool{true, false} {
+		recorded, err := testRefRepo.CreateReminderSent(ctx, &reminder.CreateSentParams{
+			InstallmentID: installment.ID,
+			Reminder:      "upcoming",
+			SentAt:        time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatalf("fail to create reminder sent: %v", err)
+		}
+
+		if recorded != want {
+			t.Errorf("CreateReminderSent() = %v, want %v", recorded, want)
+		}
+	}
+
+	recorded, err := testRefRepo.CreateReminderSent(ctx, &reminder.CreateSentParams{
+		InstallmentID: installment.ID,
+		Reminder:      "overdue",
+		SentAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("fail to create reminder sent: %v", err)
+	}
+
+	if !recorded {
+		t.Errorf("another reminder of the installment was not recorded")
+	}
+}
+
+func TestSQLCRepo_LockPaymentInstallment(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	existingInstallment := createRandomPaymentPlanInstallment(t, existingPlan.ID)
+
+	testcases := []struct {
+		testName  string
+		paramID   uuid.UUID
+		expectErr error
+	}{
+		{
+			testName: "happy",
+			paramID:  existingInstallment.ID,
+		},
+		{
+			testName:  "installment does not exist",
+			paramID:   uuid.Must(uuid.NewV4()),
+			expectErr: repo.ErrRecordNotFound,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := testRefRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+				installment, err := txRepo.LockPaymentInstallment(context.Background(), testcase.paramID)
+				if err != nil {
+					return err
+				}
+
+				if installment.ID != existingInstallment.ID {
+					t.Errorf("wrong expected id: got %v, want %v", installment.ID, existingInstallment.ID)
+				}
+
+				return nil
+			})
+			if !errors.Is(err, testcase.expectErr) {
+				t.Errorf("unexpected err: got %v, want %v", err, testcase.expectErr)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_PaymentTransactions(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	existingInstallment := createRandomPaymentPlanInstallment(t, existingPlan.ID)
+	paidAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	testcases := []struct {
+		testName  string
+		paramArg  *payments.CreateTransactionParams
+		expectErr bool
+	}{
+		{
+			testName: "happy",
+			paramArg: &payments.CreateTransactionParams{
+				PaymentInstallmentID: existingInstallment.ID,
+				Amount:               payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				ProcessorReference:   "psp-ref-1",
+				PaidAt:               paidAt,
+			},
+			expectErr: false,
+		},
+		{
+			testName: "installment does not exist",
+			paramArg: &payments.CreateTransactionParams{
+				PaymentInstallmentID: uuid.Must(uuid.NewV4()),
+				Amount:               payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+				ProcessorReference:   "psp-ref-2",
+				PaidAt:               paidAt,
+			},
+			expectErr: true,
+		},
+		{
+			testName: "negative amount",
+			paramArg: &payments.CreateTransactionParams{
+				PaymentInstallmentID: existingInstallment.ID,
+				Amount:               payments.MustNewMoney(decimal.New(-1098, 2), "usdc"),
+				ProcessorReference:   "psp-ref-3",
+				PaidAt:               paidAt,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			transaction, err := testRefRepo.CreatePaymentTransaction(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned: %v", err)
+				}
+
+				return
+			}
+
+			if transaction.ID == uuid.Nil {
+				t.Errorf("expect uuid but nil returned")
+			}
+
+			if !transaction.PaidAt.Equal(testcase.paramArg.PaidAt) {
+				t.Errorf("wrong expected paid at: got %v, want %v", transaction.PaidAt, testcase.paramArg.PaidAt)
+			}
+
+			transactions, err := testRefRepo.ListPaymentTransactionsByInstallmentID(
+				context.Background(),
+				testcase.paramArg.PaymentInstallmentID,
+			)
+			if err != nil {
+				t.Fatalf("fail to list transactions: %v", err)
+			}
+
+			if len(transactions) != 1 || transactions[0].ID != transaction.ID {
+				t.Errorf("unexpected transactions %v", transactions)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newTransactionFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentTransactionRow",
+			paramDBEntity: &db.CreatePaymentTransactionRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListPaymentTransactionsByInstallmentIDRow",
+			paramDBEntity: &db.ListPaymentTransactionsByInstallmentIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - PaymentTransaction",
+			paramDBEntity: &db.PaymentTransaction{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			transaction, err := sqlcRepo.newTransactionFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(transaction) != reflect.TypeOf(&payments.Transaction{}) {
+				t.Errorf("returned entity is not of *payments.Transaction")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_ListPaymentInstallmentsByStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	overdueInstallment := createRandomPaymentPlanInstallment(t, existingPlan.ID)
+	pendingInstallment := createRandomPaymentPlanInstallment(t, existingPlan.ID)
+
+	// an installment is due before it is overdue
+	for _, status := range []string{"due", "overdue"} {
+		if _, err := testRefRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+			ID:     overdueInstallment.ID,
+			Status: status,
+		}); err != nil {
+			t.Fatalf("fail to update installment: %v", err)
+		}
+	}
+
+	installments, err := testRefRepo.ListPaymentInstallmentsByStatus(ctx, "overdue")
+	if err != nil {
+		t.Fatalf("fail to list installments: %v", err)
+	}
+
+	found := false
+
+	for _, inst := range installments {
+		if inst.ID == pendingInstallment.ID {
+			t.Errorf("pending installment was listed")
+		}
+
+		if inst.ID == overdueInstallment.ID {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("overdue installment was not listed")
+	}
+}
+
+func TestSQLCRepo_PaymentLateFees(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	existingInstallment := createRandomPaymentPlanInstallment(t, existingPlan.ID)
+	assessedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	testcases := []struct {
+		testName  string
+		paramArg  *payments.CreateLateFeeParams
+		expectErr bool
+	}{
+		{
+			testName: "happy",
+			paramArg: &payments.CreateLateFeeParams{
+				PaymentInstallmentID: existingInstallment.ID,
+				Amount:               payments.MustNewMoney(decimal.New(15, 1), "usdc"),
+				AssessedAt:           assessedAt,
+			},
+			expectErr: false,
+		},
+		{
+			testName: "installment does not exist",
+			paramArg: &payments.CreateLateFeeParams{
+				PaymentInstallmentID: uuid.Must(uuid.NewV4()),
+				Amount:               payments.MustNewMoney(decimal.New(15, 1), "usdc"),
+				AssessedAt:           assessedAt,
+			},
+			expectErr: true,
+		},
+		{
+			testName: "negative amount",
+			paramArg: &payments.CreateLateFeeParams{
+				PaymentInstallmentID: existingInstallment.ID,
+				Amount:               payments.MustNewMoney(decimal.New(-15, 1), "usdc"),
+				AssessedAt:           assessedAt,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			lateFee, err := testRefRepo.CreatePaymentLateFee(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned: %v", err)
+				}
+
+				return
+			}
+
+			if lateFee.ID == uuid.Nil {
+				t.Errorf("expect uuid but nil returned")
+			}
+
+			if !lateFee.AssessedAt.Equal(testcase.paramArg.AssessedAt) {
+				t.Errorf("wrong expected assessed at: got %v, want %v", lateFee.AssessedAt, testcase.paramArg.AssessedAt)
+			}
+
+			lateFees, err := testRefRepo.ListPaymentLateFeesByInstallmentID(
+				context.Background(),
+				testcase.paramArg.PaymentInstallmentID,
+			)
+			if err != nil {
+				t.Fatalf("fail to list late fees: %v", err)
+			}
+
+			if len(lateFees) != 1 || lateFees[0].ID != lateFee.ID {
+				t.Errorf("unexpected late fees %v", lateFees)
+			}
+
+			lateFees, err = testRefRepo.ListPaymentLateFeesByPlanID(context.Background(), existingPlan.ID)
+			if err != nil {
+				t.Fatalf("fail to list late fees: %v", err)
+			}
+
+			if len(lateFees) != 1 || lateFees[0].ID != lateFee.ID {
+				t.Errorf("unexpected late fees %v", lateFees)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newLateFeeFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentLateFeeRow",
+			paramDBEntity: &db.CreatePaymentLateFeeRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListPaymentLateFeesByInstallmentIDRow",
+			paramDBEntity: &db.ListPaymentLateFeesByInstallmentIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListPaymentLateFeesByPlanIDRow",
+			paramDBEntity: &db.ListPaymentLateFeesByPlanIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - PaymentLateFee",
+			paramDBEntity: &db.PaymentLateFee{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			lateFee, err := sqlcRepo.newLateFeeFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(lateFee) != reflect.TypeOf(&payments.LateFee{}) {
+				t.Errorf("returned entity is not of *payments.LateFee")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_ListPaymentPlansByStatusCreatedBefore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pendingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	completePlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+
+	if _, err := testRefRepo.UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+		ID:     completePlan.ID,
+		Status: "complete",
+	}); err != nil {
+		t.Fatalf("fail to update plan: %v", err)
+	}
+
+	testcases := []struct {
+		testName      string
+		paramBefore   time.Time
+		expectPending bool
+	}{
+		{
+			testName:      "created before",
+			paramBefore:   pendingPlan.CreatedAt.Add(time.Second),
+			expectPending: true,
+		},
+		{
+			testName:    "created after",
+			paramBefore: pendingPlan.CreatedAt,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			plans, err := testRefRepo.ListPaymentPlansByStatusCreatedBefore(ctx, &payments.ListPlansByStatusCreatedBeforeParams{
+				Status:        "pending",
+				CreatedBefore: testcase.paramBefore,
+			})
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			// other tests create plans in the same database, only the ones of this test are checked
+			foundPending := false
+
+			for idx, plan := range plans {
+				if plan.Status != "pending" || !plan.CreatedAt.Before(testcase.paramBefore) {
+					t.Errorf("unexpected plan %v", plan)
+				}
+
+				if idx > 0 && plan.CreatedAt.Before(plans[idx-1].CreatedAt) {
+					t.Errorf("plans are not listed oldest first")
+				}
+
+				foundPending = foundPending || plan.ID == pendingPlan.ID
+			}
+
+			if foundPending != testcase.expectPending {
+				t.Errorf("pending plan listed: got %v, want %v", foundPending, testcase.expectPending)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_LockPaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+
+	testcases := []struct {
+		testName  string
+		paramID   uuid.UUID
+		expectErr error
+	}{
+		{
+			testName: "happy",
+			paramID:  existingPlan.ID,
+		},
+		{
+			testName:  "plan does not exist",
+			paramID:   uuid.Must(uuid.NewV4()),
+			expectErr: repo.ErrRecordNotFound,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := testRefRepo.WithTx(context.Background(), func(txRepo repo.Repository) error {
+				plan, err := txRepo.LockPaymentPlan(context.Background(), testcase.paramID)
+				if err != nil {
+					return err
+				}
+
+				if plan.ID != existingPlan.ID {
+					t.Errorf("wrong expected id: got %v, want %v", plan.ID, existingPlan.ID)
+				}
+
+				return nil
+			})
+			if !errors.Is(err, testcase.expectErr) {
+				t.Errorf("unexpected err: got %v, want %v", err, testcase.expectErr)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_PaymentRefunds(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	existingInstallment := createRandomPaymentPlanInstallment(t, existingPlan.ID)
+	refundedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	testcases := []struct {
+		testName  string
+		paramArg  *payments.CreateRefundParams
+		expectErr bool
+	}{
+		{
+			testName: "happy",
+			paramArg: &payments.CreateRefundParams{
+				PaymentInstallmentID: existingInstallment.ID,
+				Amount:               payments.MustNewMoney(decimal.New(15, 1), "usdc"),
+				Reason:               "purchase returned",
+				RefundedAt:           refundedAt,
+			},
+			expectErr: false,
+		},
+		{
+			testName: "installment does not exist",
+			paramArg: &payments.CreateRefundParams{
+				PaymentInstallmentID: uuid.Must(uuid.NewV4()),
+				Amount:               payments.MustNewMoney(decimal.New(15, 1), "usdc"),
+				RefundedAt:           refundedAt,
+			},
+			expectErr: true,
+		},
+		{
+			testName: "negative amount",
+			paramArg: &payments.CreateRefundParams{
+				PaymentInstallmentID: existingInstallment.ID,
+				Amount:               payments.MustNewMoney(decimal.New(-15, 1), "usdc"),
+				RefundedAt:           refundedAt,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			refund, err := testRefRepo.CreatePaymentRefund(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned: %v", err)
+				}
+
+				return
+			}
+
+			if refund.ID == uuid.Nil {
+				t.Errorf("expect uuid but nil returned")
+			}
+
+			if refund.Reason != testcase.paramArg.Reason {
+				t.Errorf("wrong expected reason: got %v, want %v", refund.Reason, testcase.paramArg.Reason)
+			}
+
+			refunds, err := testRefRepo.ListPaymentRefundsByPlanID(context.Background(), existingPlan.ID)
+			if err != nil {
+				t.Fatalf("fail to list refunds: %v", err)
+			}
+
+			if len(refunds) != 1 || refunds[0].ID != refund.ID {
+				t.Errorf("unexpected refunds %v", refunds)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newRefundFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentRefundRow",
+			paramDBEntity: &db.CreatePaymentRefundRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListPaymentRefundsByPlanIDRow",
+			paramDBEntity: &db.ListPaymentRefundsByPlanIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - PaymentRefund",
+			paramDBEntity: &db.PaymentRefund{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			refund, err := sqlcRepo.newRefundFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(refund) != reflect.TypeOf(&payments.Refund{}) {
+				t.Errorf("returned entity is not of *payments.Refund")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_CreatePaymentSettlement(t *testing.T) {
+	t.Parallel()
+
+	existingPlan := createRandomPaymentPlan(t, uuid.Must(uuid.NewV4()))
+	paidAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	testcases := []struct {
+		testName  string
+		paramArg  *payments.CreateSettlementParams
+		expectErr bool
+	}{
+		{
+			testName: "happy",
+			paramArg: &payments.CreateSettlementParams{
+				PaymentPlanID:      existingPlan.ID,
+				Amount:             payments.MustNewMoney(decimal.New(15, 1), "usdc"),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             paidAt,
+			},
+			expectErr: false,
+		},
+		{
+			testName: "plan does not exist",
+			paramArg: &payments.CreateSettlementParams{
+				PaymentPlanID:      uuid.Must(uuid.NewV4()),
+				Amount:             payments.MustNewMoney(decimal.New(15, 1), "usdc"),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             paidAt,
+			},
+			expectErr: true,
+		},
+		{
+			testName: "negative amount",
+			paramArg: &payments.CreateSettlementParams{
+				PaymentPlanID:      existingPlan.ID,
+				Amount:             payments.MustNewMoney(decimal.New(-15, 1), "usdc"),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             paidAt,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			settlement, err := testRefRepo.CreatePaymentSettlement(context.Background(), testcase.paramArg)
+			if testcase.expectErr && err == nil {
+				t.Errorf("expects err but nil returned")
+			}
+			if err != nil {
+				if !testcase.expectErr {
+					t.Errorf("expect no err but err returned: %v", err)
+				}
+
+				return
+			}
+
+			if settlement.ID == uuid.Nil {
+				t.Errorf("expect uuid but nil returned")
+			}
+
+			if settlement.ProcessorReference != testcase.paramArg.ProcessorReference {
+				t.Errorf("wrong expected processor reference: got %v, want %v",
+					settlement.ProcessorReference, testcase.paramArg.ProcessorReference)
+			}
+
+			if !settlement.PaidAt.Equal(paidAt) {
+				t.Errorf("wrong expected paid at: got %v, want %v", settlement.PaidAt, paidAt)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newSettlementFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentSettlementRow",
+			paramDBEntity: &db.CreatePaymentSettlementRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - PaymentSettlement",
+			paramDBEntity: &db.PaymentSettlement{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			settlement, err := sqlcRepo.newSettlementFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(settlement) != reflect.TypeOf(&payments.Settlement{}) {
+				t.Errorf("returned entity is not of *payments.Settlement")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_CreditLines(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+	params := &payments.CreateCreditLineParams{
+		UserID: userID,
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: "active",
+	}
+
+	if _, err := testRefRepo.GetCreditLineByUserID(ctx, userID); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+
+	creditLine, err := testRefRepo.CreateCreditLine(ctx, params)
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	if creditLine.UserID != userID || !creditLine.Limit.Equal(params.Limit) || creditLine.Status != params.Status {
+		t.Errorf("unexpected credit line %v", creditLine)
+	}
+
+	// a user has one credit line at most
+	if _, err := testRefRepo.CreateCreditLine(ctx, params); err == nil {
+		t.Errorf("expects err but nil returned")
+	}
+
+	got, err := testRefRepo.GetCreditLineByUserID(ctx, userID)
+	if err != nil || got.ID != creditLine.ID {
+		t.Errorf("GetCreditLineByUserID() = %v, %v, want %v", got, err, creditLine)
+	}
+}
+
+func TestSQLCRepo_LockCreditLineByUserID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+
+	creditLine, err := testRefRepo.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: userID,
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: "active",
+	})
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	if err := testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		_, err := txRepo.LockCreditLineByUserID(ctx, uuid.Must(uuid.NewV4()))
+
+		return err
+	}); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("unexpected err: got %v, want %v", err, repo.ErrRecordNotFound)
+	}
+
+	// the second unit of work only gets the line once the first one has ended
+	var (
+		order   = make([]string, 0, 2)
+		orderMu sync.Mutex
+		second  = make(chan error, 1)
+	)
+
+	record := func(step string) {
+		orderMu.Lock()
+		defer orderMu.Unlock()
+
+		order = append(order, step)
+	}
+
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		locked, err := txRepo.LockCreditLineByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if locked.ID != creditLine.ID {
+			t.Errorf("wrong expected id: got %v, want %v", locked.ID, creditLine.ID)
+		}
+
+		go func() {
+			second <- testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+				_, err := txRepo.LockCreditLineByUserID(ctx, userID)
+				record("second")
+
+				return err
+			})
+		}()
+
+		time.Sleep(200 * time.Millisecond)
+		record("first")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fail to lock credit line: %v", err)
+	}
+
+	if err := <-second; err != nil {
+		t.Fatalf("fail to lock credit line: %v", err)
+	}
+
+	if !reflect.DeepEqual(order, []string{"first", "second"}) {
+		t.Errorf("credit line was not kept locked, got %v", order)
+	}
+}
+
+func TestSQLCRepo_UpdateCreditLine(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	creditLine, err := testRefRepo.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: "active",
+	})
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	limit := payments.MustNewMoney(decimal.New(2000, 0), "usdc")
+
+	raised, err := testRefRepo.UpdateCreditLineLimit(ctx, &payments.UpdateCreditLineLimitParams{
+		ID:    creditLine.ID,
+		Limit: limit,
+	})
+	if err != nil || !raised.Limit.Equal(limit) {
+		t.Fatalf("UpdateCreditLineLimit() = %v, %v", raised, err)
+	}
+
+	closed, err := testRefRepo.UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+		ID:     creditLine.ID,
+		Status: "closed",
+	})
+	if err != nil || closed.Status != "closed" {
+		t.Fatalf("UpdateCreditLineStatus() = %v, %v", closed, err)
+	}
+
+	// the credit_lines_status_transition trigger refuses to reopen a closed line
+	if _, err := testRefRepo.UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+		ID:     creditLine.ID,
+		Status: "active",
+	}); err == nil {
+		t.Errorf("expects err but nil returned")
+	}
+}
+
+func TestSQLCRepo_CreditLineChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	creditLine, err := testRefRepo.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: "active",
+	})
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	for _, action := range []string{"create", "freeze"} {
+		if _, err := testRefRepo.CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+			CreditLineID: creditLine.ID,
+			Action:       action,
+			Actor:        "risk-ops",
+			Reason:       "review",
+			Limit:        creditLine.Limit,
+			Status:       creditLine.Status,
+		}); err != nil {
+			t.Fatalf("fail to create credit line change: %v", err)
+		}
+	}
+
+	changes, err := testRefRepo.ListCreditLineChangesByCreditLineID(ctx, creditLine.ID)
+	if err != nil {
+		t.Fatalf("fail to list credit line changes: %v", err)
+	}
+
+	if len(changes) != 2 || changes[0].Action != "create" || changes[1].Action != "freeze" {
+		t.Errorf("unexpected credit line changes %v", changes)
+	}
+
+	// a change needs an existing credit line
+	if _, err := testRefRepo.CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+		CreditLineID: uuid.Must(uuid.NewV4()),
+		Action:       "freeze",
+		Limit:        creditLine.Limit,
+		Status:       "frozen",
+	}); err == nil {
+		t.Errorf("expects err but nil returned")
+	}
+}
+
+func TestSQLCRepo_JournalEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	amount := payments.MustNewMoney(decimal.New(1098, 2), "usdc")
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: amount,
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		for _, entry := range []*ledger.CreateEntryParams{
+			ledger.NewTransfer(plan.ID, ledger.EntryPlanCreated, ledger.AccountUserReceivable,
+				ledger.AccountMerchantPayable, amount),
+			ledger.NewTransfer(plan.ID, ledger.EntryInstallmentPaid, ledger.AccountCash,
+				ledger.AccountUserReceivable, amount),
+		} {
+			if _, err := txRepo.CreateJournalEntry(ctx, entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fail to create journal entries: %v", err)
+	}
+
+	entries, err := testRefRepo.ListJournalEntriesByPlanID(ctx, plan.ID)
+	if err != nil {
+		t.Fatalf("fail to list journal entries: %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Kind != ledger.EntryPlanCreated || entries[1].Kind != ledger.EntryInstallmentPaid {
+		t.Fatalf("unexpected journal entries %v", entries)
+	}
+
+	for _, entry := range entries {
+		if len(entry.Postings) != 2 || entry.Postings[0].Direction != ledger.Debit ||
+			!entry.Postings[1].Amount.Equal(amount) {
+			t.Errorf("unexpected postings %v", entry.Postings)
+		}
+	}
+
+	// an entry which does not balance is refused when the transaction commits
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		_, err := txRepo.CreateJournalEntry(ctx, &ledger.CreateEntryParams{
+			PaymentPlanID: plan.ID,
+			Kind:          ledger.EntryInstallmentPaid,
+			Postings: []ledger.CreatePostingParams{
+				{Account: ledger.AccountCash, Direction: ledger.Debit, Amount: amount},
+			},
+		})
+
+		return err
+	})
+	if err == nil {
+		t.Errorf("expects err but nil returned")
+	}
+
+	// the ledger is append-only
+	if _, err := testRefPoolConn.Exec(ctx, "DELETE FROM journal_postings WHERE journal_entry_id = $1",
+		entries[0].ID); err == nil {
+		t.Errorf("expects err but nil returned")
+	}
+}
+
+func TestSQLCRepo_OutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	created, err := testRefRepo.CreateOutboxEvent(ctx, &outbox.CreateEventParams{
+		PaymentPlanID: plan.ID,
+		Type:          outbox.EventPlanCreated,
+		Payload:       []byte(`{"status": "pending"}`),
+	})
+	if err != nil {
+		t.Fatalf("fail to create outbox event: %v", err)
+	}
+
+	if created.PublishedAt != nil || created.Type != outbox.EventPlanCreated {
+		t.Errorf("unexpected outbox event %v", created)
+	}
+
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		events, err := txRepo.LockUnpublishedOutboxEvents(ctx, 1000)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if event.ID != created.ID {
+				continue
+			}
+
+			_, err := txRepo.MarkOutboxEventPublished(ctx, &outbox.MarkEventPublishedParams{
+				ID:          event.ID,
+				PublishedAt: now,
+			})
+
+			return err
+		}
+
+		return fmt.Errorf("outbox event %v is not unpublished", created.ID)
+	})
+	if err != nil {
+		t.Fatalf("fail to publish outbox event: %v", err)
+	}
+
+	events, err := testRefRepo.LockUnpublishedOutboxEvents(ctx, 1000)
+	if err != nil {
+		t.Fatalf("fail to list unpublished outbox events: %v", err)
+	}
+
+	for _, event := range events {
+		if event.ID == created.ID {
+			t.Errorf("published outbox event %v is still listed", event)
+		}
+	}
+
+	if _, err := testRefRepo.MarkOutboxEventPublished(ctx, &outbox.MarkEventPublishedParams{
+		ID:          uuid.Must(uuid.NewV4()),
+		PublishedAt: now,
+	}); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", repo.ErrRecordNotFound, err)
+	}
+
+	if _, err := testRefRepo.MarkOutboxEventFailed(ctx, &outbox.MarkEventFailedParams{
+		ID:       uuid.Must(uuid.NewV4()),
+		FailedAt: now,
+	}); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", repo.ErrRecordNotFound, err)
+	}
+}
+
+func TestSQLCRepo_LockUnpublishedOutboxEventsAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	createEvent := func(eventType string) *outbox.Event {
+		event, err := testRefRepo.CreateOutboxEvent(ctx, &outbox.CreateEventParams{
+			PaymentPlanID: plan.ID,
+			Type:          eventType,
+			Payload:       []byte(`{}`),
+		})
+		if err != nil {
+			t.Fatalf("fail to create outbox event: %v", err)
+		}
+
+		return event
+	}
+
+	created := createEvent(outbox.EventPlanCreated)
+	completed := createEvent(outbox.EventPlanCompleted)
+
+	if _, err := testRefRepo.MarkOutboxEventFailed(ctx, &outbox.MarkEventFailedParams{
+		ID:       created.ID,
+		FailedAt: now,
+	}); err != nil {
+		t.Fatalf("MarkOutboxEventFailed() error = %v", err)
+	}
+
+	// the failed event is read again, the events of its plan after it are not
+	var listed []uuid.UUID
+
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		events, err := txRepo.LockUnpublishedOutboxEvents(ctx, 1000)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if event.PaymentPlanID == plan.ID {
+				listed = append(listed, event.ID)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fail to list unpublished outbox events: %v", err)
+	}
+
+	if len(listed) != 1 || listed[0] != created.ID {
+		t.Errorf("listed %v, want [%v] without %v", listed, created.ID, completed.ID)
+	}
+}
+
+func TestSQLCRepo_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	merchantID := uuid.Must(uuid.NewV4())
+
+	if _, err := testRefRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"}); err != nil {
+		t.Fatalf("fail to create merchant: %v", err)
+	}
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID:     uuid.Must(uuid.NewV4()),
+		MerchantID: merchantID,
+		Amount:     payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status:     "complete",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	if plan.MerchantID != merchantID {
+		t.Errorf("payment plan merchant = %v, want %v", plan.MerchantID, merchantID)
+	}
+
+	event, err := testRefRepo.CreateOutboxEvent(ctx, &outbox.CreateEventParams{
+		PaymentPlanID: plan.ID,
+		Type:          outbox.EventPlanCompleted,
+		Payload:       []byte(`{"status": "complete"}`),
+	})
+	if err != nil {
+		t.Fatalf("fail to create outbox event: %v", err)
+	}
+
+	endpoint, err := testRefRepo.CreateWebhookEndpoint(ctx, &webhook.CreateEndpointParams{
+		MerchantID: merchantID,
+		URL:        "https://merchant.example.com/webhooks",
+		Secret:     "whsec_test",
+	})
+	if err != nil {
+		t.Fatalf("fail to create webhook endpoint: %v", err)
+	}
+
+	if got, err := testRefRepo.GetWebhookEndpointByID(ctx, endpoint.ID); err != nil || got.URL != endpoint.URL {
+		t.Errorf("GetWebhookEndpointByID() = %v, %v, want %v", got, err, endpoint)
+	}
+
+	endpoints, err := testRefRepo.ListWebhookEndpointsByMerchantID(ctx, merchantID)
+	if err != nil || len(endpoints) != 1 || endpoints[0].ID != endpoint.ID {
+		t.Errorf("ListWebhookEndpointsByMerchantID() = %v, %v, want [%v]", endpoints, err, endpoint)
+	}
+
+	// the event is delivered once to the endpoint however many times it is published
+	for i := 0; i < 2; i++ {
+		if err := testRefRepo.CreateWebhookDelivery(ctx, &webhook.CreateDeliveryParams{
+			EndpointID:    endpoint.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       event.Payload,
+			NextAttemptAt: now,
+		}); err != nil {
+			t.Fatalf("fail to create webhook delivery: %v", err)
+		}
+	}
+
+	var delivery *webhook.Delivery
+
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		due, err := txRepo.LockDueWebhookDeliveries(ctx, &webhook.ListDueDeliveriesParams{Before: now, Limit: 1000})
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range due {
+			if candidate.EndpointID != endpoint.ID {
+				continue
+			}
+
+			if delivery != nil {
+				return fmt.Errorf("event %v is delivered twice to endpoint %v", event.ID, endpoint.ID)
+			}
+
+			delivery, err = txRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+				ID:            candidate.ID,
+				Status:        webhook.DeliveryStatusDead,
+				Attempts:      3,
+				NextAttemptAt: now,
+				LastError:     "dummyErr",
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil || delivery == nil {
+		t.Fatalf("fail to update webhook delivery: %v", err)
+	}
+
+	if delivery.Status != webhook.DeliveryStatusDead || delivery.Attempts != 3 || delivery.DeliveredAt != nil {
+		t.Errorf("unexpected webhook delivery %v", delivery)
+	}
+
+	dead, err := testRefRepo.ListWebhookDeliveriesByStatus(ctx, webhook.DeliveryStatusDead)
+	if err != nil {
+		t.Fatalf("fail to list dead webhook deliveries: %v", err)
+	}
+
+	found := false
+
+	for _, candidate := range dead {
+		found = found || candidate.ID == delivery.ID
+	}
+
+	if !found {
+		t.Errorf("dead webhook delivery %v is not listed", delivery.ID)
+	}
+
+	delivered, err := testRefRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+		ID:            delivery.ID,
+		Status:        webhook.DeliveryStatusDelivered,
+		Attempts:      1,
+		NextAttemptAt: now,
+		DeliveredAt:   &now,
+	})
+	if err != nil || delivered.DeliveredAt == nil || !delivered.DeliveredAt.Equal(now) {
+		t.Errorf("UpdateWebhookDelivery() = %v, %v", delivered, err)
+	}
+
+	if _, err := testRefRepo.LockWebhookDelivery(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", repo.ErrRecordNotFound, err)
+	}
+}
+
+func TestSQLCRepo_CollectionsCases(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "complete",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	installment, err := testRefRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+		PaymentPlanID: plan.ID,
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		DueAt:         now.Add(-30 * 24 * time.Hour),
+		Status:        "overdue",
+		Version:       1,
+	})
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
+	}
+
+	if !isDelinquentPlan(t, plan.ID) {
+		t.Errorf("delinquent payment plan %v is not listed", plan.ID)
+	}
+
+	collectionsCase, err := testRefRepo.CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+		PaymentPlanID: plan.ID,
+		UserID:        plan.UserID,
+		Status:        "open",
+		OpenedAt:      now,
+		NextActionAt:  &now,
+	})
+	if err != nil {
+		t.Fatalf("fail to create collections case: %v", err)
+	}
+
+	if isDelinquentPlan(t, plan.ID) {
+		t.Errorf("payment plan %v with an open case is listed as delinquent", plan.ID)
+	}
+
+	// a plan has one case at most which is not closed
+	if _, err := testRefRepo.CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+		PaymentPlanID: plan.ID,
+		UserID:        plan.UserID,
+		Status:        "open",
+		OpenedAt:      now,
+	}); err == nil {
+		t.Errorf("a second active collections case was created for payment plan %v", plan.ID)
+	}
+
+	if got, err := testRefRepo.GetActiveCollectionsCaseByPlanID(ctx, plan.ID); err != nil || got.ID != collectionsCase.ID {
+		t.Errorf("GetActiveCollectionsCaseByPlanID() = %v, %v, want %v", got, err, collectionsCase)
+	}
+
+	var escalated *collections.Case
+
+	err = testRefRepo.WithTx(ctx, func(txRepo repo.Repository) error {
+		due, err := txRepo.ListDueCollectionsCases(ctx, &collections.ListDueCasesParams{Before: now, Limit: 1000})
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range due {
+			if candidate.ID != collectionsCase.ID {
+				continue
+			}
+
+			locked, err := txRepo.LockCollectionsCase(ctx, candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			nextActionAt := now.Add(7 * 24 * time.Hour)
+
+			escalated, err = txRepo.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+				ID:           locked.ID,
+				Status:       locked.Status,
+				Stage:        "reminder",
+				NextActionAt: &nextActionAt,
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := txRepo.CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+				CaseID: locked.ID,
+				Kind:   collections.EventEscalated,
+				Stage:  "reminder",
+				Actor:  "collections",
+				Note:   "notice sent",
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil || escalated == nil {
+		t.Fatalf("fail to escalate collections case: %v", err)
+	}
+
+	if escalated.Stage != "reminder" || escalated.NextActionAt == nil || escalated.ClosedAt != nil {
+		t.Errorf("unexpected collections case %v", escalated)
+	}
+
+	if got, err := testRefRepo.GetCollectionsCaseByID(ctx, collectionsCase.ID); err != nil || got.Stage != "reminder" {
+		t.Errorf("GetCollectionsCaseByID() = %v, %v, want stage reminder", got, err)
+	}
+
+	events, err := testRefRepo.ListCollectionsCaseEventsByCaseID(ctx, collectionsCase.ID)
+	if err != nil || len(events) != 1 || events[0].Kind != collections.EventEscalated {
+		t.Errorf("ListCollectionsCaseEventsByCaseID() = %v, %v", events, err)
+	}
+
+	if _, err := testRefRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     installment.ID,
+		Status: "paid",
+	}); err != nil {
+		t.Fatalf("fail to pay installment: %v", err)
+	}
+
+	settled, err := testRefRepo.ListSettledCollectionsCases(ctx, 1000)
+	if err != nil {
+		t.Fatalf("fail to list settled collections cases: %v", err)
+	}
+
+	found := false
+
+	for _, candidate := range settled {
+		found = found || candidate.ID == collectionsCase.ID
+	}
+
+	if !found {
+		t.Errorf("settled collections case %v is not listed", collectionsCase.ID)
+	}
+
+	closed, err := testRefRepo.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+		ID:       collectionsCase.ID,
+		Status:   "closed",
+		Stage:    escalated.Stage,
+		ClosedAt: &now,
+	})
+	if err != nil || closed.NextActionAt != nil || closed.ClosedAt == nil || !closed.ClosedAt.Equal(now) {
+		t.Errorf("UpdateCollectionsCase() = %v, %v", closed, err)
+	}
+
+	// a closed case cannot be opened again
+	if _, err := testRefRepo.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+		ID:     collectionsCase.ID,
+		Status: "open",
+	}); err == nil {
+		t.Errorf("closed collections case %v was opened again", collectionsCase.ID)
+	}
+
+	closedCases, err := testRefRepo.ListCollectionsCasesByStatus(ctx, "closed")
+	if err != nil || len(closedCases) == 0 {
+		t.Errorf("ListCollectionsCasesByStatus() = %v, %v", closedCases, err)
+	}
+
+	if _, err := testRefRepo.LockCollectionsCase(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("expected %v, got %v", repo.ErrRecordNotFound, err)
+	}
+}
+
+func TestSQLCRepo_GetUserOutstandingAmount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := uuid.Must(uuid.NewV4())
+	plan := createRandomPaymentPlan(t, userID)
+
+	// 10.98 each, the paid one is not owed anymore
+	paidInstallment := createRandomPaymentPlanInstallment(t, plan.ID)
+	overdueInstallment := createRandomPaymentPlanInstallment(t, plan.ID)
+	createRandomPaymentPlanInstallment(t, plan.ID)
+
+	if _, err := testRefRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     paidInstallment.ID,
+		Status: "paid",
+	}); err != nil {
+		t.Fatalf("fail to update installment: %v", err)
+	}
+
+	for _, status := range []string{"due", "overdue"} {
+		if _, err := testRefRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+			ID:     overdueInstallment.ID,
+			Status: status,
+		}); err != nil {
+			t.Fatalf("fail to update installment: %v", err)
+		}
+	}
+
+	if _, err := testRefRepo.CreatePaymentLateFee(ctx, &payments.CreateLateFeeParams{
+		PaymentInstallmentID: overdueInstallment.ID,
+		Amount:               payments.MustNewMoney(decimal.New(2, 0), "usdc"),
+		AssessedAt:           time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("fail to create late fee: %v", err)
+	}
+
+	if _, err := testRefRepo.CreatePaymentTransaction(ctx, &payments.CreateTransactionParams{
+		PaymentInstallmentID: overdueInstallment.ID,
+		Amount:               payments.MustNewMoney(decimal.New(5, 0), "usdc"),
+		ProcessorReference:   "psp-ref-1",
+		PaidAt:               time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("fail to create transaction: %v", err)
+	}
+
+	testcases := []struct {
+		testName string
+		paramArg *payments.UserOutstandingAmountParams
+		expected payments.Money
+	}{
+		{
+			testName: "unpaid installments with their late fees and payments",
+			paramArg: &payments.UserOutstandingAmountParams{UserID: userID, Currency: "usdc"},
+			expected: payments.MustNewMoney(decimal.New(1896, 2), "usdc"),
+		},
+		{
+			testName: "other currency",
+			paramArg: &payments.UserOutstandingAmountParams{UserID: userID, Currency: "usd"},
+			expected: payments.MustNewMoney(decimal.New(0, 0), "usd"),
+		},
+		{
+			testName: "other user",
+			paramArg: &payments.UserOutstandingAmountParams{UserID: uuid.Must(uuid.NewV4()), Currency: "usdc"},
+			expected: payments.MustNewMoney(decimal.New(0, 0), "usdc"),
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			outstanding, err := testRefRepo.GetUserOutstandingAmount(ctx, testcase.paramArg)
+			if err != nil {
+				t.Fatalf("fail to get outstanding amount: %v", err)
+			}
+
+			if !outstanding.Equal(testcase.expected) {
+				t.Errorf("wrong expected outstanding amount: got %v, want %v", outstanding, testcase.expected)
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_Merchants(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	merchantID := uuid.Must(uuid.NewV4())
+
+	merchant, err := testRefRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"})
+	if err != nil {
+		t.Fatalf("fail to create merchant: %v", err)
+	}
+
+	if got, err := testRefRepo.GetMerchantByID(ctx, merchantID); err != nil || !reflect.DeepEqual(got, merchant) {
+		t.Errorf("GetMerchantByID() = %v, %v, want %v", got, err, merchant)
+	}
+
+	if _, err := testRefRepo.GetMerchantByID(ctx, uuid.Must(uuid.NewV4())); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("expected a record not found error, got %v", err)
+	}
+
+	from := time.Now().UTC().Add(-time.Second)
+	plans := make([]*payments.Plan, 0, 3)
+
+	for _, status := range []string{"complete", "refunded", "pending"} {
+		plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+			UserID:     uuid.Must(uuid.NewV4()),
+			MerchantID: merchantID,
+			Amount:     payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+			Status:     status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create payment plan: %v", err)
+		}
+
+		plans = append(plans, plan)
+	}
+
+	latest, err := testRefRepo.ListPaymentPlansByMerchantID(ctx, &payments.ListPlansByMerchantIDParams{
+		MerchantID: merchantID,
+		Limit:      2,
+	})
+	if err != nil || len(latest) != 2 || latest[0].ID != plans[2].ID || latest[1].ID != plans[1].ID {
+		t.Errorf("ListPaymentPlansByMerchantID() = %v, %v, want the two latest plans", latest, err)
+	}
+
+	oldest, err := testRefRepo.ListPaymentPlansByMerchantID(ctx, &payments.ListPlansByMerchantIDParams{
+		MerchantID:  merchantID,
+		Offset:      1,
+		Limit:       10,
+		OldestFirst: true,
+	})
+	if err != nil || len(oldest) != 2 || oldest[0].ID != plans[1].ID || oldest[1].ID != plans[2].ID {
+		t.Errorf("ListPaymentPlansByMerchantID() = %v, %v, want the plans after the oldest", oldest, err)
+	}
+
+	// 10.98 each, the complete and refunded plans only
+	createRandomPaymentPlanInstallment(t, plans[0].ID)
+	createRandomPaymentPlanInstallment(t, plans[1].ID)
+	createRandomPaymentPlanInstallment(t, plans[2].ID)
+
+	// what was refunded is what was taken off the merchant payable, the late fees given back are not counted
+	for _, entry := range []*ledger.CreateEntryParams{
+		ledger.NewTransfer(plans[0].ID, ledger.EntryRefunded, ledger.AccountMerchantPayable,
+			ledger.AccountUserReceivable, payments.MustNewMoney(decimal.New(2, 0), "usdc")),
+		ledger.NewTransfer(plans[1].ID, ledger.EntryReceivableWrittenOff, ledger.AccountMerchantPayable,
+			ledger.AccountUserReceivable, payments.MustNewMoney(decimal.New(1, 0), "usdc")),
+		ledger.NewTransfer(plans[0].ID, ledger.EntryRefunded, ledger.AccountFeeIncome,
+			ledger.AccountCash, payments.MustNewMoney(decimal.New(5, 0), "usdc")),
+	} {
+		if _, err := testRefRepo.CreateJournalEntry(ctx, entry); err != nil {
+			t.Fatalf("fail to create journal entry: %v", err)
+		}
+	}
+
+	totals, err := testRefRepo.GetMerchantTotals(ctx, &payments.MerchantTotalsParams{
+		MerchantID:  merchantID,
+		CreatedFrom: from,
+		CreatedTo:   time.Now().UTC().Add(time.Second),
+	})
+	if err != nil || len(totals) != 1 {
+		t.Fatalf("GetMerchantTotals() = %v, %v, want the totals in usdc", totals, err)
+	}
+
+	if want := payments.MustNewMoney(decimal.New(2196, 2), "usdc"); !totals[0].Originated.Equal(want) {
+		t.Errorf("originated = %v, want %v", totals[0].Originated, want)
+	}
+
+	if want := payments.MustNewMoney(decimal.New(2196, 2), "usdc"); !totals[0].Outstanding.Equal(want) {
+		t.Errorf("outstanding = %v, want %v", totals[0].Outstanding, want)
+	}
+
+	if want := payments.MustNewMoney(decimal.New(3, 0), "usdc"); !totals[0].Refunded.Equal(want) {
+		t.Errorf("refunded = %v, want %v", totals[0].Refunded, want)
+	}
+}
+
+func TestSQLCRepo_PaymentPlanOrders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	merchantID := uuid.Must(uuid.NewV4())
+	orderReference := "order-" + merchantID.String()
+
+	if _, err := testRefRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"}); err != nil {
+		t.Fatalf("fail to create merchant: %v", err)
+	}
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID:         uuid.Must(uuid.NewV4()),
+		MerchantID:     merchantID,
+		Amount:         payments.MustNewMoney(decimal.New(30, 0), "usdc"),
+		Status:         "pending",
+		OrderReference: orderReference,
+		Description:    "a chair and a table",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	if plan.OrderReference != orderReference || plan.Description != "a chair and a table" {
+		t.Errorf("unexpected payment plan order %v", plan)
+	}
+
+	got, err := testRefRepo.GetPaymentPlanByOrderReference(ctx, &payments.GetPlanByOrderReferenceParams{
+		MerchantID:     merchantID,
+		OrderReference: orderReference,
+	})
+	if err != nil || got.ID != plan.ID {
+		t.Errorf("GetPaymentPlanByOrderReference() = %v, %v, want %v", got, err, plan)
+	}
+
+	var duplicateKey repo.DuplicateKeyError
+
+	if _, err := testRefRepo.GetPaymentPlanByOrderReference(ctx, &payments.GetPlanByOrderReferenceParams{
+		MerchantID:     merchantID,
+		OrderReference: "unknown",
+	}); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Errorf("expected a record not found error, got %v", err)
+	}
+
+	if _, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID:         uuid.Must(uuid.NewV4()),
+		MerchantID:     merchantID,
+		Amount:         payments.MustNewMoney(decimal.New(30, 0), "usdc"),
+		Status:         "pending",
+		OrderReference: orderReference,
+	}); !errors.As(err, &duplicateKey) || duplicateKey.Constraint != repo.ConstraintPaymentPlansMerchantOrderRefIdx {
+		t.Errorf("expected a duplicate key error on the order reference, got %v", err)
+	}
+
+	// the table is created first
+	for _, arg := range []*payments.CreateLineItemParams{
+		{PaymentPlanID: plan.ID, Position: 1, Name: "Table", Quantity: 1, UnitPrice: payments.MustNewMoney(decimal.New(20, 0), "usdc")},
+		{PaymentPlanID: plan.ID, Position: 0, SKU: "CH-1", Name: "Chair", Quantity: 2, UnitPrice: payments.MustNewMoney(decimal.New(5, 0), "usdc")},
+	} {
+		if _, err := testRefRepo.CreatePaymentPlanLineItem(ctx, arg); err != nil {
+			t.Fatalf("fail to create line item: %v", err)
+		}
+	}
+
+	lineItems, err := testRefRepo.ListPaymentPlanLineItemsByPlanID(ctx, plan.ID)
+	if err != nil || len(lineItems) != 2 {
+		t.Fatalf("ListPaymentPlanLineItemsByPlanID() = %v, %v, want the two line items", lineItems, err)
+	}
+
+	if lineItems[0].SKU != "CH-1" || lineItems[0].Quantity != 2 || lineItems[1].Name != "Table" ||
+		!lineItems[1].UnitPrice.Equal(payments.MustNewMoney(decimal.New(20, 0), "usdc")) {
+		t.Errorf("expected the line items ordered by position, got %v", lineItems)
+	}
+}
+
+func TestSQLCRepo_AuditEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	amount := payments.MustNewMoney(decimal.New(1098, 2), "usdc")
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: amount,
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	if _, err := testRefRepo.GetLatestAuditEntryByPlanID(ctx, plan.ID); !errors.Is(err, repo.ErrRecordNotFound) {
+		t.Fatalf("expected a record not found error, got %v", err)
+	}
+
+	var prev *audit.Entry
+
+	for _, change := range []*audit.Change{
+		{
+			Actor:     audit.InternalActor("checkout"),
+			Action:    audit.ActionPlanCreated,
+			After:     &audit.Snapshot{Plan: &audit.PlanState{Status: "pending", Amount: amount}},
+			RequestID: "req-1",
+		},
+		{
+			Actor:  audit.SystemActor("installment_scheduler"),
+			Action: audit.ActionPlanExpired,
+			Before: &audit.Snapshot{Plan: &audit.PlanState{Status: "pending", Amount: amount}},
+			After:  &audit.Snapshot{Plan: &audit.PlanState{Status: "cancelled", Amount: amount}},
+		},
+	} {
+		change.PaymentPlanID = plan.ID
+		change.OccurredAt = time.Now()
+
+		params, err := audit.NewEntry(prev, change)
+		if err != nil {
+			t.Fatalf("fail to chain audit entry: %v", err)
+		}
+
+		if prev, err = testRefRepo.CreateAuditEntry(ctx, params); err != nil {
+			t.Fatalf("fail to create audit entry: %v", err)
+		}
+	}
+
+	latest, err := testRefRepo.GetLatestAuditEntryByPlanID(ctx, plan.ID)
+	if err != nil || latest.Sequence != 2 || latest.Hash != prev.Hash {
+		t.Errorf("GetLatestAuditEntryByPlanID() = %v, %v, want %v", latest, err, prev)
+	}
+
+	// a second change chained to the same entry is refused
+	if _, err := testRefRepo.CreateAuditEntry(ctx, &audit.CreateEntryParams{
+		PaymentPlanID: plan.ID,
+		Sequence:      2,
+		Actor:         audit.InternalActor("checkout"),
+		Action:        audit.ActionPlanCancelled,
+		OccurredAt:    time.Now(),
+	}); err == nil {
+		t.Errorf("expected the sequence to be unique per payment plan")
+	}
+
+	entries, err := testRefRepo.ListAuditEntriesByPlanID(ctx, plan.ID)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ListAuditEntriesByPlanID() = %v, %v, want the two entries", entries, err)
+	}
+
+	if entries[0].Before != nil || entries[0].RequestID != "req-1" || entries[1].After.Plan.Status != "cancelled" {
+		t.Errorf("unexpected audit entries %v", entries)
+	}
+
+	// the snapshots read back from jsonb still hash the same
+	if err := audit.Verify(entries); err != nil {
+		t.Errorf("expected an intact audit chain, got %v", err)
+	}
+}
+
+func TestSQLCRepo_AuditEntriesConcurrentChanges(t *testing.T) {
+	t.Parallel()
+
+	const changes = 5
+
+	ctx := context.Background()
+	amount := payments.MustNewMoney(decimal.New(1098, 2), "usdc")
+
+	plan, err := testRefRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: uuid.Must(uuid.NewV4()),
+		Amount: amount,
+		Status: "pending",
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	// each unit of work locks the plan before it reads the latest entry, they chain one after the other
+	chain := func(txRepo repo.Repository) error {
+		if _, err := txRepo.LockPaymentPlan(ctx, plan.ID); err != nil {
+			return err
+		}
+
+		prev, err := txRepo.GetLatestAuditEntryByPlanID(ctx, plan.ID)
+		if err != nil && !errors.Is(err, repo.ErrRecordNotFound) {
+			return err
+		}
+
+		params, err := audit.NewEntry(prev, &audit.Change{
+			PaymentPlanID: plan.ID,
+			Actor:         audit.InternalActor("checkout"),
+			Action:        audit.ActionPlanCancelled,
+			Before:        &audit.Snapshot{Plan: &audit.PlanState{Status: "pending", Amount: amount}},
+			OccurredAt:    time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		// the others read the chain while this one still holds the plan
+		time.Sleep(20 * time.Millisecond)
+
+		_, err = txRepo.CreateAuditEntry(ctx, params)
+
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, changes)
+
+	for i := 0; i < changes; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs <- testRefRepo.WithTx(ctx, chain)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("fail to chain audit entry: %v", err)
+		}
+	}
+
+	entries, err := testRefRepo.ListAuditEntriesByPlanID(ctx, plan.ID)
+	if err != nil || len(entries) != changes {
+		t.Fatalf("ListAuditEntriesByPlanID() = %v, %v, want %d entries", entries, err, changes)
+	}
+
+	if err := audit.Verify(entries); err != nil {
+		t.Errorf("expected an intact audit chain, got %v", err)
+	}
+}
+
+func TestSQLCRepo_newCreditLineFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateCreditLineRow",
+			paramDBEntity: &db.CreateCreditLineRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - GetCreditLineByUserIDRow",
+			paramDBEntity: &db.GetCreditLineByUserIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - GetCreditLineByUserIDForUpdateRow",
+			paramDBEntity: &db.GetCreditLineByUserIDForUpdateRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - UpdateCreditLineLimitRow",
+			paramDBEntity: &db.UpdateCreditLineLimitRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - UpdateCreditLineStatusRow",
+			paramDBEntity: &db.UpdateCreditLineStatusRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - CreditLine",
+			paramDBEntity: &db.CreditLine{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported currency",
+			paramDBEntity: &db.CreditLine{Currency: "xyz"},
+			expectErr:     true,
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			creditLine, err := sqlcRepo.newCreditLineFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(creditLine) != reflect.TypeOf(&payments.CreditLine{}) {
+				t.Errorf("returned entity is not of *payments.CreditLine")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newCreditLineChangeFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateCreditLineChangeRow",
+			paramDBEntity: &db.CreateCreditLineChangeRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListCreditLineChangesByCreditLineIDRow",
+			paramDBEntity: &db.ListCreditLineChangesByCreditLineIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - CreditLineChange",
+			paramDBEntity: &db.CreditLineChange{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported currency",
+			paramDBEntity: &db.CreditLineChange{Currency: "xyz"},
+			expectErr:     true,
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			change, err := sqlcRepo.newCreditLineChangeFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(change) != reflect.TypeOf(&payments.CreditLineChange{}) {
+				t.Errorf("returned entity is not of *payments.CreditLineChange")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newPostingFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateJournalPostingRow",
+			paramDBEntity: &db.CreateJournalPostingRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListJournalPostingsByPlanIDRow",
+			paramDBEntity: &db.ListJournalPostingsByPlanIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - JournalPosting",
+			paramDBEntity: &db.JournalPosting{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported currency",
+			paramDBEntity: &db.JournalPosting{Currency: "xyz"},
+			expectErr:     true,
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			posting, err := sqlcRepo.newPostingFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(posting) != reflect.TypeOf(&ledger.Posting{}) {
+				t.Errorf("returned entity is not of *ledger.Posting")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newOutboxEventFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateOutboxEventRow",
+			paramDBEntity: &db.CreateOutboxEventRow{EventType: db.OutboxEventTypePlanCreated},
+		},
+		{
+			testName:      "happy - ListUnpublishedOutboxEventsForUpdateRow",
+			paramDBEntity: &db.ListUnpublishedOutboxEventsForUpdateRow{EventType: db.OutboxEventTypePlanCreated},
+		},
+		{
+			testName:      "happy - UpdateOutboxEventPublishedAtRow",
+			paramDBEntity: &db.UpdateOutboxEventPublishedAtRow{EventType: db.OutboxEventTypePlanCreated},
+		},
+		{
+			testName:      "happy - UpdateOutboxEventFailedAtRow",
+			paramDBEntity: &db.UpdateOutboxEventFailedAtRow{EventType: db.OutboxEventTypePlanCreated},
+		},
+		{
+			testName:      "happy - OutboxEvent",
+			paramDBEntity: &db.OutboxEvent{EventType: db.OutboxEventTypePlanCreated},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := sqlcRepo.newOutboxEventFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(event) != reflect.TypeOf(&outbox.Event{}) {
+				t.Errorf("returned entity is not of *outbox.Event")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newMerchantFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateMerchantRow",
+			paramDBEntity: &db.CreateMerchantRow{},
+		},
+		{
+			testName:      "happy - GetMerchantByIDRow",
+			paramDBEntity: &db.GetMerchantByIDRow{},
+		},
+		{
+			testName:      "happy - Merchant",
+			paramDBEntity: &db.Merchant{},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			merchant, err := sqlcRepo.newMerchantFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(merchant) != reflect.TypeOf(&payments.Merchant{}) {
+				t.Errorf("returned entity is not of *payments.Merchant")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newLineItemFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentPlanLineItemRow",
+			paramDBEntity: &db.CreatePaymentPlanLineItemRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - ListPaymentPlanLineItemsByPlanIDRow",
+			paramDBEntity: &db.ListPaymentPlanLineItemsByPlanIDRow{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "happy - PaymentPlanLineItem",
+			paramDBEntity: &db.PaymentPlanLineItem{Currency: db.CurrencyUsdc},
+		},
+		{
+			testName:      "failed - unsupported currency",
+			paramDBEntity: &db.PaymentPlanLineItem{Currency: "xyz"},
+			expectErr:     true,
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			lineItem, err := sqlcRepo.newLineItemFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(lineItem) != reflect.TypeOf(&payments.LineItem{}) {
+				t.Errorf("returned entity is not of *payments.LineItem")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newWebhookEndpointFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateWebhookEndpointRow",
+			paramDBEntity: &db.CreateWebhookEndpointRow{},
+		},
+		{
+			testName:      "happy - GetWebhookEndpointByIDRow",
+			paramDBEntity: &db.GetWebhookEndpointByIDRow{},
+		},
+		{
+			testName:      "happy - ListWebhookEndpointsByMerchantIDRow",
+			paramDBEntity: &db.ListWebhookEndpointsByMerchantIDRow{},
+		},
+		{
+			testName:      "happy - WebhookEndpoint",
+			paramDBEntity: &db.WebhookEndpoint{},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			endpoint, err := sqlcRepo.newWebhookEndpointFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(endpoint) != reflect.TypeOf(&webhook.Endpoint{}) {
+				t.Errorf("returned entity is not of *webhook.Endpoint")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newWebhookDeliveryFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - GetWebhookDeliveryByIDForUpdateRow",
+			paramDBEntity: &db.GetWebhookDeliveryByIDForUpdateRow{Status: db.WebhookDeliveryStatusPending},
+		},
+		{
+			testName:      "happy - ListDueWebhookDeliveriesForUpdateRow",
+			paramDBEntity: &db.ListDueWebhookDeliveriesForUpdateRow{Status: db.WebhookDeliveryStatusPending},
+		},
+		{
+			testName:      "happy - ListWebhookDeliveriesByStatusRow",
+			paramDBEntity: &db.ListWebhookDeliveriesByStatusRow{Status: db.WebhookDeliveryStatusDead},
+		},
+		{
+			testName: "happy - UpdateWebhookDeliveryAttemptRow",
+			paramDBEntity: &db.UpdateWebhookDeliveryAttemptRow{
+				Status:      db.WebhookDeliveryStatusDelivered,
+				DeliveredAt: sql.NullTime{Time: time.Now(), Valid: true},
+			},
+		},
+		{
+			testName:      "happy - WebhookDelivery",
+			paramDBEntity: &db.WebhookDelivery{Status: db.WebhookDeliveryStatusPending},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			delivery, err := sqlcRepo.newWebhookDeliveryFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(delivery) != reflect.TypeOf(&webhook.Delivery{}) {
+				t.Errorf("returned entity is not of *webhook.Delivery")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newCollectionsCaseFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName: "happy - CreateCollectionsCaseRow",
+			paramDBEntity: &db.CreateCollectionsCaseRow{
+				Status:       db.CollectionsCaseStatusOpen,
+				NextActionAt: sql.NullTime{Time: time.Now(), Valid: true},
+			},
+		},
+		{
+			testName:      "happy - GetCollectionsCaseByIDRow",
+			paramDBEntity: &db.GetCollectionsCaseByIDRow{Status: db.CollectionsCaseStatusOpen},
+		},
+		{
+			testName:      "happy - GetCollectionsCaseByIDForUpdateRow",
+			paramDBEntity: &db.GetCollectionsCaseByIDForUpdateRow{Status: db.CollectionsCaseStatusPaused},
+		},
+		{
+			testName:      "happy - GetActiveCollectionsCaseByPaymentPlanIDRow",
+			paramDBEntity: &db.GetActiveCollectionsCaseByPaymentPlanIDRow{Status: db.CollectionsCaseStatusOpen},
+		},
+		{
+			testName:      "happy - ListDueCollectionsCasesRow",
+			paramDBEntity: &db.ListDueCollectionsCasesRow{Status: db.CollectionsCaseStatusOpen},
+		},
+		{
+			testName:      "happy - ListSettledCollectionsCasesRow",
+			paramDBEntity: &db.ListSettledCollectionsCasesRow{Status: db.CollectionsCaseStatusPaused},
+		},
+		{
+			testName:      "happy - ListCollectionsCasesByStatusRow",
+			paramDBEntity: &db.ListCollectionsCasesByStatusRow{Status: db.CollectionsCaseStatusOpen},
+		},
+		{
+			testName: "happy - UpdateCollectionsCaseRow",
+			paramDBEntity: &db.UpdateCollectionsCaseRow{
+				Status:   db.CollectionsCaseStatusClosed,
+				ClosedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			},
+		},
+		{
+			testName:      "happy - CollectionsCase",
+			paramDBEntity: &db.CollectionsCase{Status: db.CollectionsCaseStatusOpen},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			collectionsCase, err := sqlcRepo.newCollectionsCaseFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(collectionsCase) != reflect.TypeOf(&collections.Case{}) {
+				t.Errorf("returned entity is not of *collections.Case")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newCollectionsCaseEventFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateCollectionsCaseEventRow",
+			paramDBEntity: &db.CreateCollectionsCaseEventRow{Kind: db.CollectionsCaseEventKindOpened},
+		},
+		{
+			testName:      "happy - ListCollectionsCaseEventsByCaseIDRow",
+			paramDBEntity: &db.ListCollectionsCaseEventsByCaseIDRow{Kind: db.CollectionsCaseEventKindEscalated},
+		},
+		{
+			testName:      "happy - CollectionsCaseEvent",
+			paramDBEntity: &db.CollectionsCaseEvent{Kind: db.CollectionsCaseEventKindClosed},
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := sqlcRepo.newCollectionsCaseEventFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(event) != reflect.TypeOf(&collections.Event{}) {
+				t.Errorf("returned entity is not of *collections.Event")
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newPlanFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	repo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentPlanRow",
+			paramDBEntity: &db.CreatePaymentPlanRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - GetPaymentPlanByIDRow",
+			paramDBEntity: &db.GetPaymentPlanByIDRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - GetPaymentPlanByIDForUpdateRow",
+			paramDBEntity: &db.GetPaymentPlanByIDForUpdateRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - GetPaymentPlanByMerchantIDOrderReferenceRow",
+			paramDBEntity: &db.GetPaymentPlanByMerchantIDOrderReferenceRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListPaymentPlansByMerchantIDOldestFirstRow",
+			paramDBEntity: &db.ListPaymentPlansByMerchantIDOldestFirstRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListPaymentPlansByMerchantIDLatestFirstRow",
+			paramDBEntity: &db.ListPaymentPlansByMerchantIDLatestFirstRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListPaymentPlansByStatusCreatedBeforeRow",
+			paramDBEntity: &db.ListPaymentPlansByStatusCreatedBeforeRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListPaymentPlansByUserIDRow",
+			paramDBEntity: &db.ListPaymentPlansByUserIDRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListDelinquentPaymentPlansRow",
+			paramDBEntity: &db.ListDelinquentPaymentPlansRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - UpdatePaymentPlanStatusRow",
+			paramDBEntity: &db.UpdatePaymentPlanStatusRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - PaymentPlan",
+			paramDBEntity: &db.PaymentPlan{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "failed - unsupported currency",
+			paramDBEntity: &db.PaymentPlan{Currency: "xyz"},
+			expectErr:     true,
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := repo.newPlanFromDBEntity(testcase.paramDBEntity)
+
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+			} else {
+				if reflect.TypeOf(plan) != reflect.TypeOf(&payments.Plan{}) {
+					t.Errorf("returned entity is not of *payments.Plan")
+				}
+			}
+		})
+	}
+}
+
+func TestSQLCRepo_newInstallmentFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	repo := NewSQLCRepository(&pgx.Conn{})
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreatePaymentInstallmentsRow",
+			paramDBEntity: &db.CreatePaymentInstallmentsRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - GetPaymentInstallmentByIDForUpdateRow",
+			paramDBEntity: &db.GetPaymentInstallmentByIDForUpdateRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - UpdatePaymentInstallmentsStatusDueBeforeRow",
+			paramDBEntity: &db.UpdatePaymentInstallmentsStatusDueBeforeRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListUnpaidPaymentInstallmentsDueBetweenRow",
+			paramDBEntity: &db.ListUnpaidPaymentInstallmentsDueBetweenRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListPaymentInstallmentsByPlanIDRow",
+			paramDBEntity: &db.ListPaymentInstallmentsByPlanIDRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - ListPaymentInstallmentsByStatusRow",
+			paramDBEntity: &db.ListPaymentInstallmentsByStatusRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - UpdatePaymentInstallmentStatusRow",
+			paramDBEntity: &db.UpdatePaymentInstallmentStatusRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - UpdatePaymentInstallmentAmountRow",
+			paramDBEntity: &db.UpdatePaymentInstallmentAmountRow{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "happy - PaymentInstallment",
+			paramDBEntity: &db.PaymentInstallment{Currency: db.CurrencyUsdc},
+			expectErr:     false,
+		},
+		{
+			testName:      "failed - json err",
+			paramDBEntity: "some invalid input",
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := repo.newInstallmentFromDBEntity(testcase.paramDBEntity)
+
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
 				}
 			} else {
 				if reflect.TypeOf(plan) != reflect.TypeOf(&payments.Installment{}) {
@@ -755,14 +3917,31 @@ func TestSQLCRepo_newInstallmentFromDBEntity(t *testing.T) {
 	}
 }
 
+// isDelinquentPlan reports whether the plan is among the delinquent ones, the other tests create some too
+func isDelinquentPlan(t *testing.T, planID uuid.UUID) bool {
+	t.Helper()
+
+	plans, err := testRefRepo.ListDelinquentPaymentPlans(context.Background(), 1000)
+	if err != nil {
+		t.Fatalf("fail to list delinquent payment plans: %v", err)
+	}
+
+	for _, plan := range plans {
+		if plan.ID == planID {
+			return true
+		}
+	}
+
+	return false
+}
+
 func createRandomPaymentPlan(t *testing.T, userID uuid.UUID) *payments.Plan {
 	t.Helper()
 
 	plan, err := testRefRepo.CreatePaymentPlan(context.Background(), &payments.CreatePlanParams{
-		UserID:   userID,
-		Currency: "usdc",
-		Amount:   *decimal.New(1098, 2),
-		Status:   "pending",
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
+		Status: "pending",
 	})
 	if err != nil {
 		t.Fatalf("fail to create payment plan: %v", err)
@@ -776,10 +3955,10 @@ func createRandomPaymentPlanInstallment(t *testing.T, id uuid.UUID) *payments.In
 
 	plan, err := testRefRepo.CreatePaymentInstallment(context.Background(), &payments.CreateInstallmentParams{
 		PaymentPlanID: id,
-		Currency:      "usdc",
-		Amount:        *decimal.New(1098, 2),
+		Amount:        payments.MustNewMoney(decimal.New(1098, 2), "usdc"),
 		DueAt:         time.Now().UTC().Truncate(time.Microsecond),
 		Status:        "pending",
+		Version:       1,
 	})
 	if err != nil {
 		t.Fatalf("fail to create installment: %v", err)
@@ -825,3 +4004,70 @@ func runMigrations(dbURL string) error {
 
 	return nil
 }
+
+func TestSQLCRepo_newAuditEntryFromDBEntity(t *testing.T) {
+	t.Parallel()
+
+	sqlcRepo := NewSQLCRepository(&pgx.Conn{})
+
+	type unsupportedStruct struct{}
+
+	var (
+		noSnapshot = json.RawMessage("null")
+		snapshot   = json.RawMessage(`{"plan":{"status":"pending","amount":{"value":"10","currency":"usdc"}}}`)
+	)
+
+	testcases := []struct {
+		testName      string
+		paramDBEntity interface{}
+		expectErr     bool
+	}{
+		{
+			testName:      "happy - CreateAuditEntryRow",
+			paramDBEntity: &db.CreateAuditEntryRow{BeforeSnapshot: noSnapshot, AfterSnapshot: snapshot},
+		},
+		{
+			testName:      "happy - GetLatestAuditEntryByPlanIDRow",
+			paramDBEntity: &db.GetLatestAuditEntryByPlanIDRow{BeforeSnapshot: snapshot, AfterSnapshot: snapshot},
+		},
+		{
+			testName:      "happy - ListAuditEntriesByPlanIDRow",
+			paramDBEntity: &db.ListAuditEntriesByPlanIDRow{BeforeSnapshot: noSnapshot, AfterSnapshot: snapshot},
+		},
+		{
+			testName:      "happy - AuditEntry",
+			paramDBEntity: &db.AuditEntry{BeforeSnapshot: noSnapshot, AfterSnapshot: snapshot},
+		},
+		{
+			testName:      "failed - invalid snapshot",
+			paramDBEntity: &db.AuditEntry{BeforeSnapshot: noSnapshot, AfterSnapshot: json.RawMessage("{")},
+			expectErr:     true,
+		},
+		{
+			testName:      "failed - unsupported DB entity type",
+			paramDBEntity: &unsupportedStruct{},
+			expectErr:     true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		testcase := testcase
+
+		t.Run(testcase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			entry, err := sqlcRepo.newAuditEntryFromDBEntity(testcase.paramDBEntity)
+			if testcase.expectErr {
+				if err == nil {
+					t.Errorf("expected err but nil returned")
+				}
+
+				return
+			}
+
+			if reflect.TypeOf(entry) != reflect.TypeOf(&audit.Entry{}) {
+				t.Errorf("returned entity is not of *audit.Entry")
+			}
+		})
+	}
+}
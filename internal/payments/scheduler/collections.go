@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/rs/zerolog"
+)
+
+// CollectionsWorker periodically opens, escalates and closes the collections cases
+type CollectionsWorker struct {
+	collectionsService service.CollectionsService
+	log                *zerolog.Logger
+	interval           time.Duration
+	batchSize          int
+	now                func() time.Time
+
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewCollectionsWorker(
+	collectionsService service.CollectionsService,
+	log *zerolog.Logger,
+	interval time.Duration,
+	batchSize int,
+) *CollectionsWorker {
+	return &CollectionsWorker{
+		collectionsService: collectionsService,
+		log:                log,
+		interval:           interval,
+		batchSize:          batchSize,
+		now:                func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Tick runs the collections once, each pass handles up to a batch of cases
+func (cw *CollectionsWorker) Tick(ctx context.Context) (*service.CollectionsRun, error) {
+	run, err := cw.collectionsService.RunCollections(ctx, cw.now(), cw.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("collections worker tick: %w", err)
+	}
+
+	return run, nil
+}
+
+// Start runs a tick every interval until Stop is called, a non positive interval disables the worker
+func (cw *CollectionsWorker) Start(ctx context.Context) {
+	if cw.interval <= 0 {
+		cw.log.Info().Msg("collections worker disabled")
+
+		return
+	}
+
+	ctx, cw.cancel = context.WithCancel(ctx)
+	cw.done = make(chan struct{})
+
+	go func() {
+		defer close(cw.done)
+
+		ticker := time.NewTicker(cw.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cw.runTick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop waits for the running tick to complete
+func (cw *CollectionsWorker) Stop() error {
+	if cw.done == nil {
+		return nil
+	}
+
+	cw.stopOnce.Do(func() {
+		cw.cancel()
+		<-cw.done
+	})
+
+	return nil
+}
+
+func (cw *CollectionsWorker) runTick(ctx context.Context) {
+	run, err := cw.Tick(ctx)
+	if err != nil {
+		cw.log.Error().Err(err).Msg("collections worker failed")
+
+		return
+	}
+
+	if run.Opened == 0 && run.Escalated == 0 && run.Closed == 0 && run.Failed == 0 {
+		return
+	}
+
+	cw.log.Info().
+		Int("opened", run.Opened).
+		Int("escalated", run.Escalated).
+		Int("closed", run.Closed).
+		Int("failed", run.Failed).
+		Msg("collections worker tick")
+}
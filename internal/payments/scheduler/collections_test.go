@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+)
+
+func TestCollectionsWorker_Tick(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		now      = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		log      = zerolog.Nop()
+		errDummy = errors.New("dummyErr")
+		run      = &service.CollectionsRun{Opened: 1, Escalated: 2, Failed: 1}
+	)
+
+	tests := []struct {
+		name    string
+		run     *service.CollectionsRun
+		runErr  error
+		want    *service.CollectionsRun
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			run:  run,
+			want: run,
+		},
+		{
+			name:    "collections error",
+			runErr:  errDummy,
+			wantErr: errDummy,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+			collectionsService.EXPECT().
+				RunCollections(ctx, now, 50).
+				Return(tt.run, tt.runErr)
+
+			collectionsWorker := NewCollectionsWorker(collectionsService, &log, time.Minute, 50)
+			collectionsWorker.now = func() time.Time { return now }
+
+			got, err := collectionsWorker.Tick(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tick() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionsWorker_StartStop(t *testing.T) {
+	t.Parallel()
+
+	log := zerolog.Nop()
+	ticked := make(chan struct{})
+
+	collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+	collectionsService.EXPECT().
+		RunCollections(gomock.Any(), gomock.Any(), 50).
+		DoAndReturn(func(ctx context.Context, now time.Time, limit int) (*service.CollectionsRun, error) {
+			select {
+			case ticked <- struct{}{}:
+			default:
+			}
+
+			return &service.CollectionsRun{Escalated: 1}, nil
+		}).
+		MinTimes(1)
+
+	collectionsWorker := NewCollectionsWorker(collectionsService, &log, time.Millisecond, 50)
+	collectionsWorker.Start(context.Background())
+
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("collections worker did not tick")
+	}
+
+	if err := collectionsWorker.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	// stopping twice is a no-op
+	if err := collectionsWorker.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/rs/zerolog"
+)
+
+// OutboxRelay periodically publishes the outbox events which are not published yet
+type OutboxRelay struct {
+	paymentService service.PaymentPlanService
+	log            *zerolog.Logger
+	interval       time.Duration
+	batchSize      int
+	now            func() time.Time
+
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewOutboxRelay(
+	paymentService service.PaymentPlanService,
+	log *zerolog.Logger,
+	interval time.Duration,
+	batchSize int,
+) *OutboxRelay {
+	return &OutboxRelay{
+		paymentService: paymentService,
+		log:            log,
+		interval:       interval,
+		batchSize:      batchSize,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Tick publishes a single batch of events
+func (or *OutboxRelay) Tick(ctx context.Context) (*service.OutboxRelay, error) {
+	relay, err := or.paymentService.RelayOutboxEvents(ctx, or.now(), or.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("outbox relay tick: %w", err)
+	}
+
+	return relay, nil
+}
+
+// Start runs a tick every interval until Stop is called, a non positive interval disables the relay
+func (or *OutboxRelay) Start(ctx context.Context) {
+	if or.interval <= 0 {
+		or.log.Info().Msg("outbox relay disabled")
+
+		return
+	}
+
+	ctx, or.cancel = context.WithCancel(ctx)
+	or.done = make(chan struct{})
+
+	go func() {
+		defer close(or.done)
+
+		ticker := time.NewTicker(or.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				or.runTick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop waits for the running tick to complete
+func (or *OutboxRelay) Stop() error {
+	if or.done == nil {
+		return nil
+	}
+
+	or.stopOnce.Do(func() {
+		or.cancel()
+		<-or.done
+	})
+
+	return nil
+}
+
+func (or *OutboxRelay) runTick(ctx context.Context) {
+	relay, err := or.Tick(ctx)
+	if err != nil {
+		or.log.Error().Err(err).Msg("outbox relay failed")
+
+		return
+	}
+
+	if relay.Published == 0 && relay.Failed == 0 {
+		return
+	}
+
+	or.log.Info().
+		Int("published", relay.Published).
+		Int("failed", relay.Failed).
+		Int("held_back", relay.HeldBack).
+		Msg("outbox relay tick")
+}
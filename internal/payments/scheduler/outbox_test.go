@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+)
+
+func TestOutboxRelay_Tick(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		now       = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		batchSize = 100
+		log       = zerolog.Nop()
+		errDummy  = errors.New("dummyErr")
+		relay     = &service.OutboxRelay{Published: 2, Failed: 1, HeldBack: 1}
+	)
+
+	tests := []struct {
+		name     string
+		relay    *service.OutboxRelay
+		relayErr error
+		want     *service.OutboxRelay
+		wantErr  error
+	}{
+		{
+			name:  "happy path",
+			relay: relay,
+			want:  relay,
+		},
+		{
+			name:     "relay error",
+			relayErr: errDummy,
+			wantErr:  errDummy,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().
+				RelayOutboxEvents(ctx, now, batchSize).
+				Return(tt.relay, tt.relayErr)
+
+			outboxRelay := NewOutboxRelay(paymentService, &log, time.Minute, batchSize)
+			outboxRelay.now = func() time.Time { return now }
+
+			got, err := outboxRelay.Tick(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tick() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboxRelay_StartStop(t *testing.T) {
+	t.Parallel()
+
+	log := zerolog.Nop()
+	ticked := make(chan struct{})
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+	paymentService.EXPECT().
+		RelayOutboxEvents(gomock.Any(), gomock.Any(), 10).
+		DoAndReturn(func(ctx context.Context, now time.Time, limit int) (*service.OutboxRelay, error) {
+			select {
+			case ticked <- struct{}{}:
+			default:
+			}
+
+			return &service.OutboxRelay{Published: 1}, nil
+		}).
+		MinTimes(1)
+
+	outboxRelay := NewOutboxRelay(paymentService, &log, time.Millisecond, 10)
+	outboxRelay.Start(context.Background())
+
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("outbox relay did not tick")
+	}
+
+	if err := outboxRelay.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	// stopping twice is a no-op
+	if err := outboxRelay.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
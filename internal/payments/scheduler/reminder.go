@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/rs/zerolog"
+)
+
+// ReminderScheduler periodically sends the reminders of the installments which fell due
+type ReminderScheduler struct {
+	reminderService service.ReminderService
+	log             *zerolog.Logger
+	interval        time.Duration
+	now             func() time.Time
+
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewReminderScheduler(
+	reminderService service.ReminderService,
+	log *zerolog.Logger,
+	interval time.Duration,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		reminderService: reminderService,
+		log:             log,
+		interval:        interval,
+		now:             func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Tick sends the reminders once
+func (rs *ReminderScheduler) Tick(ctx context.Context) (*service.ReminderRun, error) {
+	run, err := rs.reminderService.SendInstallmentReminders(ctx, rs.now())
+	if err != nil {
+		return nil, fmt.Errorf("reminder scheduler tick: %w", err)
+	}
+
+	return run, nil
+}
+
+// Start runs a tick every interval until Stop is called, a non positive interval disables the scheduler
+func (rs *ReminderScheduler) Start(ctx context.Context) {
+	if rs.interval <= 0 {
+		rs.log.Info().Msg("reminder scheduler disabled")
+
+		return
+	}
+
+	ctx, rs.cancel = context.WithCancel(ctx)
+	rs.done = make(chan struct{})
+
+	go func() {
+		defer close(rs.done)
+
+		ticker := time.NewTicker(rs.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rs.runTick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop waits for the running tick to complete
+func (rs *ReminderScheduler) Stop() error {
+	if rs.done == nil {
+		return nil
+	}
+
+	rs.stopOnce.Do(func() {
+		rs.cancel()
+		<-rs.done
+	})
+
+	return nil
+}
+
+func (rs *ReminderScheduler) runTick(ctx context.Context) {
+	run, err := rs.Tick(ctx)
+	if err != nil {
+		rs.log.Error().Err(err).Msg("reminder scheduler failed")
+
+		return
+	}
+
+	if run.Sent == 0 && run.Failed == 0 {
+		return
+	}
+
+	rs.log.Info().
+		Int("sent", run.Sent).
+		Int("failed", run.Failed).
+		Msg("reminder scheduler tick")
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+)
+
+func TestReminderScheduler_Tick(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		now      = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		log      = zerolog.Nop()
+		errDummy = errors.New("dummyErr")
+		run      = &service.ReminderRun{Sent: 2, Failed: 1}
+	)
+
+	tests := []struct {
+		name    string
+		run     *service.ReminderRun
+		runErr  error
+		want    *service.ReminderRun
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			run:  run,
+			want: run,
+		},
+		{
+			name:    "reminder error",
+			runErr:  errDummy,
+			wantErr: errDummy,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reminderService := servicemock.NewMockReminderService(gomock.NewController(t))
+			reminderService.EXPECT().
+				SendInstallmentReminders(ctx, now).
+				Return(tt.run, tt.runErr)
+
+			reminderScheduler := NewReminderScheduler(reminderService, &log, time.Minute)
+			reminderScheduler.now = func() time.Time { return now }
+
+			got, err := reminderScheduler.Tick(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tick() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderScheduler_StartStop(t *testing.T) {
+	t.Parallel()
+
+	log := zerolog.Nop()
+	ticked := make(chan struct{})
+
+	reminderService := servicemock.NewMockReminderService(gomock.NewController(t))
+	reminderService.EXPECT().
+		SendInstallmentReminders(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, now time.Time) (*service.ReminderRun, error) {
+			select {
+			case ticked <- struct{}{}:
+			default:
+			}
+
+			return &service.ReminderRun{Sent: 1}, nil
+		}).
+		MinTimes(1)
+
+	reminderScheduler := NewReminderScheduler(reminderService, &log, time.Millisecond)
+	reminderScheduler.Start(context.Background())
+
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("reminder scheduler did not tick")
+	}
+
+	if err := reminderScheduler.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	// stopping twice is a no-op
+	if err := reminderScheduler.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
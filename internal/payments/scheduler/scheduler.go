@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/rs/zerolog"
+)
+
+// installmentSchedulerActor is who the changes made by the scheduler are attributed to in the audit trail
+const installmentSchedulerActor = "installment_scheduler"
+
+// InstallmentScheduler periodically flags the installments which are due or overdue,
+// charges the late fees of the overdue ones and expires the plans left pending
+type InstallmentScheduler struct {
+	paymentService service.PaymentPlanService
+	log            *zerolog.Logger
+	interval       time.Duration
+	gracePeriod    time.Duration
+	pendingPlanTTL time.Duration
+	now            func() time.Time
+
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewInstallmentScheduler a non positive pendingPlanTTL never expires the pending plans
+func NewInstallmentScheduler(
+	paymentService service.PaymentPlanService,
+	log *zerolog.Logger,
+	interval time.Duration,
+	gracePeriod time.Duration,
+	pendingPlanTTL time.Duration,
+) *InstallmentScheduler {
+	return &InstallmentScheduler{
+		paymentService: paymentService,
+		log:            log,
+		interval:       interval,
+		gracePeriod:    gracePeriod,
+		pendingPlanTTL: pendingPlanTTL,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// TickResult is what a single pass changed
+type TickResult struct {
+	PastDue  *service.InstallmentsPastDue
+	LateFees *service.LateFeeRun
+	Expired  []service.PaymentPlans
+}
+
+// Tick runs a single pass over the installments,
+// the ones which just became overdue are charged in the same pass
+func (is *InstallmentScheduler) Tick(ctx context.Context) (*TickResult, error) {
+	ctx = audit.WithActor(ctx, audit.SystemActor(installmentSchedulerActor))
+	now := is.now()
+
+	pastDue, err := is.paymentService.UpdateInstallmentsPastDue(ctx, now, is.gracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("installment scheduler tick: %w", err)
+	}
+
+	lateFees, err := is.paymentService.AssessLateFees(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("installment scheduler tick: %w", err)
+	}
+
+	expired := make([]service.PaymentPlans, 0)
+
+	if is.pendingPlanTTL > 0 {
+		if expired, err = is.paymentService.ExpirePendingPaymentPlans(ctx, now, is.pendingPlanTTL); err != nil {
+			return nil, fmt.Errorf("installment scheduler tick: %w", err)
+		}
+	}
+
+	return &TickResult{PastDue: pastDue, LateFees: lateFees, Expired: expired}, nil
+}
+
+// Start runs a tick every interval until Stop is called, a non positive interval disables the scheduler
+func (is *InstallmentScheduler) Start(ctx context.Context) {
+	if is.interval <= 0 {
+		is.log.Info().Msg("installment scheduler disabled")
+
+		return
+	}
+
+	ctx, is.cancel = context.WithCancel(ctx)
+	is.done = make(chan struct{})
+
+	go func() {
+		defer close(is.done)
+
+		ticker := time.NewTicker(is.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				is.runTick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop waits for the running tick to complete
+func (is *InstallmentScheduler) Stop() error {
+	if is.done == nil {
+		return nil
+	}
+
+	is.stopOnce.Do(func() {
+		is.cancel()
+		<-is.done
+	})
+
+	return nil
+}
+
+func (is *InstallmentScheduler) runTick(ctx context.Context) {
+	result, err := is.Tick(ctx)
+	if err != nil {
+		is.log.Error().Err(err).Msg("installment scheduler failed")
+
+		return
+	}
+
+	is.log.Info().
+		Int("due", len(result.PastDue.Due)).
+		Int("overdue", len(result.PastDue.Overdue)).
+		Int("late_fees", len(result.LateFees.Assessed)).
+		Int("late_fees_failed", result.LateFees.Failed).
+		Int("expired", len(result.Expired)).
+		Msg("installment scheduler tick")
+}
@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+)
+
+func TestInstallmentScheduler_Tick(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx         = context.Background()
+		actorCtx    = audit.WithActor(ctx, audit.SystemActor(installmentSchedulerActor))
+		now         = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		gracePeriod = 72 * time.Hour
+		ttl         = 24 * time.Hour
+		log         = zerolog.Nop()
+		errDummy    = errors.New("dummyErr")
+		pastDue     = &service.InstallmentsPastDue{
+			Due:     []service.PaymentPlanInstallment{{ID: "due"}},
+			Overdue: []service.PaymentPlanInstallment{{ID: "overdue"}},
+		}
+		lateFees = &service.LateFeeRun{Assessed: []service.PaymentPlanLateFee{{ID: "late fee", InstallmentID: "overdue"}}}
+		expired  = []service.PaymentPlans{{ID: "expired", Status: "cancelled"}}
+	)
+
+	tests := []struct {
+		name           string
+		pendingPlanTTL time.Duration
+		pastDue        *service.InstallmentsPastDue
+		pastDueErr     error
+		assessLateFees bool
+		lateFees       *service.LateFeeRun
+		lateFeesErr    error
+		expire         bool
+		expired        []service.PaymentPlans
+		expireErr      error
+		want           *TickResult
+		wantErr        error
+	}{
+		{
+			name:           "happy path",
+			pendingPlanTTL: ttl,
+			pastDue:        pastDue,
+			assessLateFees: true,
+			lateFees:       lateFees,
+			expire:         true,
+			expired:        expired,
+			want:           &TickResult{PastDue: pastDue, LateFees: lateFees, Expired: expired},
+		},
+		{
+			name:           "expiry disabled",
+			pastDue:        pastDue,
+			assessLateFees: true,
+			lateFees:       lateFees,
+			want:           &TickResult{PastDue: pastDue, LateFees: lateFees, Expired: []service.PaymentPlans{}},
+		},
+		{
+			name:       "past due error",
+			pastDueErr: errDummy,
+			wantErr:    errDummy,
+		},
+		{
+			name:           "late fees error",
+			pastDue:        pastDue,
+			assessLateFees: true,
+			lateFeesErr:    errDummy,
+			wantErr:        errDummy,
+		},
+		{
+			name:           "expire error",
+			pendingPlanTTL: ttl,
+			pastDue:        pastDue,
+			assessLateFees: true,
+			lateFees:       lateFees,
+			expire:         true,
+			expireErr:      errDummy,
+			wantErr:        errDummy,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().
+				UpdateInstallmentsPastDue(actorCtx, now, gracePeriod).
+				Return(tt.pastDue, tt.pastDueErr)
+
+			if tt.assessLateFees {
+				paymentService.EXPECT().
+					AssessLateFees(actorCtx, now).
+					Return(tt.lateFees, tt.lateFeesErr)
+			}
+
+			if tt.expire {
+				paymentService.EXPECT().
+					ExpirePendingPaymentPlans(actorCtx, now, tt.pendingPlanTTL).
+					Return(tt.expired, tt.expireErr)
+			}
+
+			installmentScheduler := NewInstallmentScheduler(paymentService, &log, time.Minute, gracePeriod, tt.pendingPlanTTL)
+			installmentScheduler.now = func() time.Time { return now }
+
+			got, err := installmentScheduler.Tick(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tick() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallmentScheduler_StartStop(t *testing.T) {
+	t.Parallel()
+
+	log := zerolog.Nop()
+	ticked := make(chan struct{})
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+	paymentService.EXPECT().
+		UpdateInstallmentsPastDue(gomock.Any(), gomock.Any(), time.Hour).
+		DoAndReturn(func(ctx context.Context, now time.Time, gracePeriod time.Duration) (*service.InstallmentsPastDue, error) {
+			select {
+			case ticked <- struct{}{}:
+			default:
+			}
+
+			return &service.InstallmentsPastDue{}, nil
+		}).
+		MinTimes(1)
+
+	paymentService.EXPECT().
+		AssessLateFees(gomock.Any(), gomock.Any()).
+		Return(&service.LateFeeRun{}, nil).
+		AnyTimes()
+
+	installmentScheduler := NewInstallmentScheduler(paymentService, &log, time.Millisecond, time.Hour, 0)
+	installmentScheduler.Start(context.Background())
+
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not tick")
+	}
+
+	if err := installmentScheduler.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	// stopping twice is a no-op
+	if err := installmentScheduler.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestInstallmentScheduler_Disabled(t *testing.T) {
+	t.Parallel()
+
+	log := zerolog.Nop()
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	installmentScheduler := NewInstallmentScheduler(paymentService, &log, 0, time.Hour, 0)
+	installmentScheduler.Start(context.Background())
+
+	if err := installmentScheduler.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
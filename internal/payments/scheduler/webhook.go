@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/rs/zerolog"
+)
+
+// WebhookDispatcher periodically sends the webhook deliveries which are due
+type WebhookDispatcher struct {
+	webhookService service.WebhookService
+	log            *zerolog.Logger
+	interval       time.Duration
+	batchSize      int
+	now            func() time.Time
+
+	stopOnce sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewWebhookDispatcher(
+	webhookService service.WebhookService,
+	log *zerolog.Logger,
+	interval time.Duration,
+	batchSize int,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookService: webhookService,
+		log:            log,
+		interval:       interval,
+		batchSize:      batchSize,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Tick sends a single batch of deliveries
+func (wd *WebhookDispatcher) Tick(ctx context.Context) (*service.WebhookDeliveryRun, error) {
+	run, err := wd.webhookService.DeliverWebhooks(ctx, wd.now(), wd.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("webhook dispatcher tick: %w", err)
+	}
+
+	return run, nil
+}
+
+// Start runs a tick every interval until Stop is called, a non positive interval disables the dispatcher
+func (wd *WebhookDispatcher) Start(ctx context.Context) {
+	if wd.interval <= 0 {
+		wd.log.Info().Msg("webhook dispatcher disabled")
+
+		return
+	}
+
+	ctx, wd.cancel = context.WithCancel(ctx)
+	wd.done = make(chan struct{})
+
+	go func() {
+		defer close(wd.done)
+
+		ticker := time.NewTicker(wd.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				wd.runTick(ctx)
+			}
+		}
+	}()
+}
+
+// Stop waits for the running tick to complete
+func (wd *WebhookDispatcher) Stop() error {
+	if wd.done == nil {
+		return nil
+	}
+
+	wd.stopOnce.Do(func() {
+		wd.cancel()
+		<-wd.done
+	})
+
+	return nil
+}
+
+func (wd *WebhookDispatcher) runTick(ctx context.Context) {
+	run, err := wd.Tick(ctx)
+	if err != nil {
+		wd.log.Error().Err(err).Msg("webhook dispatcher failed")
+
+		return
+	}
+
+	if run.Delivered == 0 && run.Retried == 0 && run.Dead == 0 {
+		return
+	}
+
+	wd.log.Info().
+		Int("delivered", run.Delivered).
+		Int("retried", run.Retried).
+		Int("dead", run.Dead).
+		Msg("webhook dispatcher tick")
+}
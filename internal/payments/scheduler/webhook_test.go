@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+)
+
+func TestWebhookDispatcher_Tick(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		now       = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		batchSize = 100
+		log       = zerolog.Nop()
+		errDummy  = errors.New("dummyErr")
+		run       = &service.WebhookDeliveryRun{Delivered: 2, Retried: 1, Dead: 1}
+	)
+
+	tests := []struct {
+		name    string
+		run     *service.WebhookDeliveryRun
+		runErr  error
+		want    *service.WebhookDeliveryRun
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			run:  run,
+			want: run,
+		},
+		{
+			name:    "delivery error",
+			runErr:  errDummy,
+			wantErr: errDummy,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+			webhookService.EXPECT().
+				DeliverWebhooks(ctx, now, batchSize).
+				Return(tt.run, tt.runErr)
+
+			dispatcher := NewWebhookDispatcher(webhookService, &log, time.Minute, batchSize)
+			dispatcher.now = func() time.Time { return now }
+
+			got, err := dispatcher.Tick(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tick() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatcher_StartStop(t *testing.T) {
+	t.Parallel()
+
+	log := zerolog.Nop()
+	ticked := make(chan struct{})
+
+	webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+	webhookService.EXPECT().
+		DeliverWebhooks(gomock.Any(), gomock.Any(), 10).
+		DoAndReturn(func(ctx context.Context, now time.Time, limit int) (*service.WebhookDeliveryRun, error) {
+			select {
+			case ticked <- struct{}{}:
+			default:
+			}
+
+			return &service.WebhookDeliveryRun{Delivered: 1}, nil
+		}).
+		MinTimes(1)
+
+	dispatcher := NewWebhookDispatcher(webhookService, &log, time.Millisecond, 10)
+	dispatcher.Start(context.Background())
+
+	select {
+	case <-ticked:
+	case <-time.After(time.Second):
+		t.Fatal("webhook dispatcher did not tick")
+	}
+
+	if err := dispatcher.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+
+	// stopping twice is a no-op
+	if err := dispatcher.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// GetPaymentPlanAudit returns the audit trail of a plan, the oldest entry first, and whether its chain is intact
+func (p *PaymentServiceImp) GetPaymentPlanAudit(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+) (*PaymentPlanAudit, error) {
+	plan, err := p.repository.GetPaymentPlanByID(ctx, paymentPlanID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+		}
+
+		return nil, GetPaymentPlanByIDError{planID: paymentPlanID}
+	}
+
+	entries, err := p.repository.ListAuditEntriesByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListAuditEntriesByPlanIDError{planID: plan.ID}
+	}
+
+	planAudit := &PaymentPlanAudit{
+		PaymentPlanID: plan.ID.String(),
+		Intact:        true,
+		Entries:       make([]AuditEntry, 0, len(entries)),
+	}
+
+	var broken audit.BrokenChainError
+	if err := audit.Verify(entries); errors.As(err, &broken) {
+		planAudit.Intact = false
+		planAudit.BrokenAt = &broken.Sequence
+	}
+
+	for _, entry := range entries {
+		planAudit.Entries = append(planAudit.Entries, newAuditEntry(entry))
+	}
+
+	return planAudit, nil
+}
+
+// recordAuditEntry chains the change to the trail of the plan in the unit of work of the change,
+// the actor and the request are the ones of ctx. The unit of work must have locked the plan before any of
+// its installments, so concurrent units of work changing the plan chain one after the other.
+func recordAuditEntry(
+	ctx context.Context,
+	repository repo.Repository,
+	planID uuid.UUID,
+	action string,
+	before, after *audit.Snapshot,
+) error {
+	prev, err := repository.GetLatestAuditEntryByPlanID(ctx, planID)
+	if err != nil && !errors.Is(err, repo.ErrRecordNotFound) {
+		return GetLatestAuditEntryError{planID: planID}
+	}
+
+	entry, err := audit.NewEntry(prev, &audit.Change{
+		PaymentPlanID: planID,
+		Actor:         audit.ActorFromContext(ctx),
+		Action:        action,
+		Before:        before,
+		After:         after,
+		RequestID:     audit.RequestIDFromContext(ctx),
+		OccurredAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return CreateAuditEntryError{planID: planID, action: action}
+	}
+
+	if _, err := repository.CreateAuditEntry(ctx, entry); err != nil {
+		return CreateAuditEntryError{planID: planID, action: action}
+	}
+
+	return nil
+}
+
+func newAuditEntry(entry *audit.Entry) AuditEntry {
+	return AuditEntry{
+		Sequence:   entry.Sequence,
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		Before:     entry.Before,
+		After:      entry.After,
+		RequestID:  entry.RequestID,
+		OccurredAt: entry.OccurredAt.Format(common.TimeFormat),
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
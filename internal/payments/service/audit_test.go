@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_GetPaymentPlanAudit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		planID     = uuid.Must(uuid.NewV4())
+		occurredAt = time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC)
+		actor      = audit.InternalActor("checkout")
+
+		plan = &payments.Plan{
+			ID:     planID,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			Status: paymentPlanStatusComplete,
+		}
+		pendingSnapshot  = &audit.Snapshot{Plan: &audit.PlanState{Status: paymentPlanStatusPending, Amount: plan.Amount}}
+		completeSnapshot = &audit.Snapshot{Plan: &audit.PlanState{Status: paymentPlanStatusComplete, Amount: plan.Amount}}
+	)
+
+	created := newAuditLogEntry(mustAuditEntryParams(nil, &audit.Change{
+		PaymentPlanID: planID,
+		Actor:         actor,
+		Action:        audit.ActionPlanCreated,
+		After:         pendingSnapshot,
+		RequestID:     "req-1",
+		OccurredAt:    occurredAt,
+	}))
+
+	completed := newAuditLogEntry(mustAuditEntryParams(created, &audit.Change{
+		PaymentPlanID: planID,
+		Actor:         actor,
+		Action:        audit.ActionPlanCompleted,
+		Before:        pendingSnapshot,
+		After:         completeSnapshot,
+		OccurredAt:    occurredAt,
+	}))
+
+	tampered := *completed
+	tampered.Actor = audit.SystemActor("installment_scheduler")
+
+	wantEntries := []AuditEntry{
+		{
+			Sequence:   1,
+			Actor:      actor,
+			Action:     audit.ActionPlanCreated,
+			After:      pendingSnapshot,
+			RequestID:  "req-1",
+			OccurredAt: "2022-07-01T10:00:00Z",
+			Hash:       created.Hash,
+		},
+		{
+			Sequence:   2,
+			Actor:      actor,
+			Action:     audit.ActionPlanCompleted,
+			Before:     pendingSnapshot,
+			After:      completeSnapshot,
+			OccurredAt: "2022-07-01T10:00:00Z",
+			PrevHash:   created.Hash,
+			Hash:       completed.Hash,
+		},
+	}
+
+	brokenAt := int64(2)
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    *PaymentPlanAudit
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListAuditEntriesByPlanID(ctx, planID).Return([]*audit.Entry{created, completed}, nil),
+				)
+			},
+			want: &PaymentPlanAudit{
+				PaymentPlanID: planID.String(),
+				Intact:        true,
+				Entries:       wantEntries,
+			},
+		},
+		{
+			name: "a tampered entry breaks the chain",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListAuditEntriesByPlanID(ctx, planID).Return([]*audit.Entry{created, &tampered}, nil),
+				)
+			},
+			want: &PaymentPlanAudit{
+				PaymentPlanID: planID.String(),
+				Intact:        false,
+				BrokenAt:      &brokenAt,
+				Entries: []AuditEntry{
+					wantEntries[0],
+					{
+						Sequence:   2,
+						Actor:      tampered.Actor,
+						Action:     audit.ActionPlanCompleted,
+						Before:     pendingSnapshot,
+						After:      completeSnapshot,
+						OccurredAt: "2022-07-01T10:00:00Z",
+						PrevHash:   created.Hash,
+						Hash:       completed.Hash,
+					},
+				},
+			},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "GetPaymentPlanByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: GetPaymentPlanByIDError{planID: planID},
+		},
+		{
+			name: "ListAuditEntriesByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListAuditEntriesByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListAuditEntriesByPlanIDError{planID: planID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.GetPaymentPlanAudit(ctx, planID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.GetPaymentPlanAudit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.GetPaymentPlanAudit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_recordAuditEntry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		planID = uuid.Must(uuid.NewV4())
+		userID = uuid.Must(uuid.NewV4())
+		ctx    = audit.WithRequestID(audit.WithActor(context.Background(), audit.UserActor(userID)), "req-1")
+		before = &audit.Snapshot{Plan: &audit.PlanState{
+			Status: paymentPlanStatusPending,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+		}}
+		after = &audit.Snapshot{Plan: &audit.PlanState{
+			Status: paymentPlanStatusCancelled,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+		}}
+	)
+
+	latest := newAuditLogEntry(mustAuditEntryParams(nil, &audit.Change{
+		PaymentPlanID: planID,
+		Actor:         audit.InternalActor("checkout"),
+		Action:        audit.ActionPlanCreated,
+		After:         before,
+		OccurredAt:    time.Now(),
+	}))
+
+	tests := []struct {
+		name         string
+		prepare      func(rm *repomock.MockRepository, created *[]*audit.CreateEntryParams)
+		wantSequence int64
+		wantPrevHash string
+		wantErr      error
+	}{
+		{
+			name: "the first entry of a plan starts its chain",
+			prepare: func(rm *repomock.MockRepository, created *[]*audit.CreateEntryParams) {
+				gomock.InOrder(
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).DoAndReturn(captureAuditEntry(created)),
+				)
+			},
+			wantSequence: 1,
+		},
+		{
+			name: "an entry is chained to the latest one",
+			prepare: func(rm *repomock.MockRepository, created *[]*audit.CreateEntryParams) {
+				gomock.InOrder(
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(latest, nil),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).DoAndReturn(captureAuditEntry(created)),
+				)
+			},
+			wantSequence: 2,
+			wantPrevHash: latest.Hash,
+		},
+		{
+			name: "GetLatestAuditEntryByPlanID error",
+			prepare: func(rm *repomock.MockRepository, created *[]*audit.CreateEntryParams) {
+				rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: GetLatestAuditEntryError{planID: planID},
+		},
+		{
+			name: "CreateAuditEntry error",
+			prepare: func(rm *repomock.MockRepository, created *[]*audit.CreateEntryParams) {
+				gomock.InOrder(
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(latest, nil),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateAuditEntryError{planID: planID, action: audit.ActionPlanCancelled},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var created []*audit.CreateEntryParams
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm, &created)
+
+			err := recordAuditEntry(ctx, rm, planID, audit.ActionPlanCancelled, before, after)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("recordAuditEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(created) != 1 {
+				t.Fatalf("recordAuditEntry() created %d entries, want 1", len(created))
+			}
+
+			got := created[0]
+			if got.Sequence != tt.wantSequence || got.PrevHash != tt.wantPrevHash {
+				t.Errorf("recordAuditEntry() = entry %d after %q, want entry %d after %q",
+					got.Sequence, got.PrevHash, tt.wantSequence, tt.wantPrevHash)
+			}
+
+			if got.Actor != audit.UserActor(userID) || got.RequestID != "req-1" {
+				t.Errorf("recordAuditEntry() made by %v in %q, want the actor and the request of the context",
+					got.Actor, got.RequestID)
+			}
+
+			if got.Action != audit.ActionPlanCancelled || got.Before != before || got.After != after {
+				t.Errorf("recordAuditEntry() = %v, want the change it was given", got)
+			}
+		})
+	}
+}
+
+func Test_recordInstallmentsPastDue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		planID  = uuid.Must(uuid.NewV4())
+		planID2 = uuid.Must(uuid.NewV4())
+	)
+
+	newInstallment := func(planID uuid.UUID, status string) *payments.Installment {
+		return &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			DueAt:         time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+			Status:        status,
+		}
+	}
+
+	dueInstallment := newInstallment(planID, PaymentInstallmentStatusDue)
+	skippedDue := newInstallment(planID, PaymentInstallmentStatusDue)
+	skippedOverdue := *skippedDue
+	skippedOverdue.Status = PaymentInstallmentStatusOverdue
+	overdueInstallment := newInstallment(planID2, PaymentInstallmentStatusOverdue)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var created []*audit.CreateEntryParams
+
+	rm := repomock.NewMockRepository(ctrl)
+	gomock.InOrder(
+		rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+		rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).DoAndReturn(captureAuditEntry(&created)),
+		rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID2).Return(nil, repo.ErrRecordNotFound),
+		rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).DoAndReturn(captureAuditEntry(&created)),
+	)
+
+	err := recordInstallmentsPastDue(ctx, rm,
+		[]*payments.Installment{dueInstallment, skippedDue},
+		[]*payments.Installment{&skippedOverdue, overdueInstallment},
+	)
+	if err != nil {
+		t.Fatalf("recordInstallmentsPastDue() error = %v", err)
+	}
+
+	statuses := func(snapshot *audit.Snapshot) []string {
+		var got []string
+
+		for _, inst := range snapshot.Installments {
+			got = append(got, inst.Status)
+		}
+
+		return got
+	}
+
+	want := []struct {
+		planID uuid.UUID
+		before []string
+		after  []string
+	}{
+		{
+			planID: planID,
+			before: []string{PaymentInstallmentStatusPending, PaymentInstallmentStatusPending},
+			after:  []string{PaymentInstallmentStatusDue, PaymentInstallmentStatusOverdue},
+		},
+		{
+			planID: planID2,
+			before: []string{PaymentInstallmentStatusDue},
+			after:  []string{PaymentInstallmentStatusOverdue},
+		},
+	}
+
+	if len(created) != len(want) {
+		t.Fatalf("recordInstallmentsPastDue() created %d entries, want %d", len(created), len(want))
+	}
+
+	for idx, entry := range created {
+		if entry.PaymentPlanID != want[idx].planID || entry.Action != audit.ActionInstallmentsPastDue {
+			t.Errorf("recordInstallmentsPastDue() entry %d = %v, want one for plan %v", idx, entry, want[idx].planID)
+		}
+
+		if got := statuses(entry.Before); !reflect.DeepEqual(got, want[idx].before) {
+			t.Errorf("recordInstallmentsPastDue() entry %d before = %v, want %v", idx, got, want[idx].before)
+		}
+
+		if got := statuses(entry.After); !reflect.DeepEqual(got, want[idx].after) {
+			t.Errorf("recordInstallmentsPastDue() entry %d after = %v, want %v", idx, got, want[idx].after)
+		}
+	}
+}
+
+func mustAuditEntryParams(prev *audit.Entry, change *audit.Change) *audit.CreateEntryParams {
+	params, err := audit.NewEntry(prev, change)
+	if err != nil {
+		panic(err)
+	}
+
+	return params
+}
+
+// newAuditLogEntry is the entry the repository returns once params are written
+func newAuditLogEntry(params *audit.CreateEntryParams) *audit.Entry {
+	return &audit.Entry{
+		ID:            uuid.Must(uuid.NewV4()),
+		PaymentPlanID: params.PaymentPlanID,
+		Sequence:      params.Sequence,
+		Actor:         params.Actor,
+		Action:        params.Action,
+		Before:        params.Before,
+		After:         params.After,
+		RequestID:     params.RequestID,
+		OccurredAt:    params.OccurredAt,
+		PrevHash:      params.PrevHash,
+		Hash:          params.Hash,
+	}
+}
+
+func captureAuditEntry(
+	created *[]*audit.CreateEntryParams,
+) func(context.Context, *audit.CreateEntryParams) (*audit.Entry, error) {
+	return func(_ context.Context, params *audit.CreateEntryParams) (*audit.Entry, error) {
+		*created = append(*created, params)
+
+		return newAuditLogEntry(params), nil
+	}
+}
@@ -0,0 +1,685 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/collections"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/statemachine"
+
+	"github.com/gofrs/uuid"
+)
+
+var _ CollectionsService = (*CollectionsServiceImp)(nil)
+
+// the states of a collections case, the collections_case_status database enum
+const (
+	CollectionsCaseStatusOpen   = statemachine.CollectionsCaseOpen
+	CollectionsCaseStatusPaused = statemachine.CollectionsCasePaused
+	CollectionsCaseStatusClosed = statemachine.CollectionsCaseClosed
+)
+
+// collectionsActor is who the changes made by RunCollections are recorded for
+const collectionsActor = "collections"
+
+// CollectionsServiceImp a case climbs at most one stage per run, the stages missed while the runs
+// were stopped are caught up one run at a time
+type CollectionsServiceImp struct {
+	repository repo.Repository
+	notifier   reminder.Notifier
+	ladder     *collections.Ladder
+}
+
+func NewCollectionsService(ladder *collections.Ladder) *CollectionsServiceImp {
+	return &CollectionsServiceImp{ladder: ladder}
+}
+
+func (c *CollectionsServiceImp) UseRepo(repository repo.Repository) {
+	c.repository = repository
+}
+
+// UseNotifier no case is opened or climbs the ladder until a notifier is used
+func (c *CollectionsServiceImp) UseNotifier(notifier reminder.Notifier) {
+	c.notifier = notifier
+}
+
+// RunCollections the settled cases are closed first so a plan paid since the previous run does not
+// get another notice, they are closed even without a notifier. A credit line frozen by a case is unfrozen
+// when the case closes.
+func (c *CollectionsServiceImp) RunCollections(ctx context.Context, now time.Time, limit int) (*CollectionsRun, error) {
+	run := &CollectionsRun{}
+
+	settled, err := c.repository.ListSettledCollectionsCases(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("run collections: %w", ListCollectionsCasesError{status: "settled"})
+	}
+
+	for _, collectionsCase := range settled {
+		closed, err := c.closeSettledCase(ctx, collectionsCase.ID, now)
+		countCase(run, &run.Closed, closed, err)
+	}
+
+	if c.notifier == nil {
+		return run, nil
+	}
+
+	plans, err := c.repository.ListDelinquentPaymentPlans(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("run collections: %w", ListDelinquentPaymentPlansError{})
+	}
+
+	for _, plan := range plans {
+		opened, err := c.openCase(ctx, plan.ID, now)
+		countCase(run, &run.Opened, opened, err)
+	}
+
+	due, err := c.repository.ListDueCollectionsCases(ctx, &collections.ListDueCasesParams{
+		Before: now,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("run collections: %w", ListCollectionsCasesError{status: "due"})
+	}
+
+	for _, collectionsCase := range due {
+		escalated, err := c.escalateCase(ctx, collectionsCase.ID, now)
+		countCase(run, &run.Escalated, escalated, err)
+	}
+
+	return run, nil
+}
+
+// countCase adds a case to counter when it changed and to the failed ones when it could not
+func countCase(run *CollectionsRun, counter *int, changed bool, err error) {
+	switch {
+	case err != nil:
+		run.Failed++
+	case changed:
+		*counter++
+	}
+}
+
+func (c *CollectionsServiceImp) GetCollectionsCase(ctx context.Context, caseID uuid.UUID) (*CollectionsCase, error) {
+	collectionsCase, err := c.repository.GetCollectionsCaseByID(ctx, caseID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, CollectionsCaseNotFoundError{caseID: caseID}
+		}
+
+		return nil, GetCollectionsCaseError{caseID: caseID}
+	}
+
+	events, err := c.repository.ListCollectionsCaseEventsByCaseID(ctx, caseID)
+	if err != nil {
+		return nil, ListCollectionsCaseEventsError{caseID: caseID}
+	}
+
+	resp := newCollectionsCase(collectionsCase)
+
+	resp.History = make([]CollectionsCaseEvent, 0, len(events))
+	for _, event := range events {
+		resp.History = append(resp.History, newCollectionsCaseEvent(event))
+	}
+
+	return &resp, nil
+}
+
+func (c *CollectionsServiceImp) ListCollectionsCases(ctx context.Context, status string) ([]CollectionsCase, error) {
+	if !collectionsStates.IsState(status) {
+		return nil, InvalidCollectionsCaseStatusError{status: status}
+	}
+
+	cases, err := c.repository.ListCollectionsCasesByStatus(ctx, status)
+	if err != nil {
+		return nil, ListCollectionsCasesError{status: status}
+	}
+
+	resp := make([]CollectionsCase, 0, len(cases))
+	for _, collectionsCase := range cases {
+		resp = append(resp, newCollectionsCase(collectionsCase))
+	}
+
+	return resp, nil
+}
+
+// PauseCollectionsCase a paused case keeps its stage, it is closed like an open one once the plan is settled
+func (c *CollectionsServiceImp) PauseCollectionsCase(
+	ctx context.Context,
+	caseID uuid.UUID,
+	change *CollectionsCaseChangeParams,
+) (*CollectionsCase, error) {
+	return c.changeCase(ctx, caseID, change, "pause", func(collectionsCase *collections.Case) *caseUpdate {
+		return &caseUpdate{
+			status: CollectionsCaseStatusPaused,
+			stage:  collectionsCase.Stage,
+			kind:   collections.EventPaused,
+		}
+	})
+}
+
+func (c *CollectionsServiceImp) ResumeCollectionsCase(
+	ctx context.Context,
+	caseID uuid.UUID,
+	now time.Time,
+	change *CollectionsCaseChangeParams,
+) (*CollectionsCase, error) {
+	return c.changeCase(ctx, caseID, change, "resume", func(collectionsCase *collections.Case) *caseUpdate {
+		return &caseUpdate{
+			status:       CollectionsCaseStatusOpen,
+			stage:        collectionsCase.Stage,
+			nextActionAt: c.ladder.NextActionAt(collectionsCase.Stage, now),
+			kind:         collections.EventResumed,
+		}
+	})
+}
+
+func (c *CollectionsServiceImp) CloseCollectionsCase(
+	ctx context.Context,
+	caseID uuid.UUID,
+	now time.Time,
+	change *CollectionsCaseChangeParams,
+) (*CollectionsCase, error) {
+	return c.changeCase(ctx, caseID, change, "close", func(collectionsCase *collections.Case) *caseUpdate {
+		return &caseUpdate{
+			status:   CollectionsCaseStatusClosed,
+			stage:    collectionsCase.Stage,
+			closedAt: &now,
+			kind:     collections.EventClosed,
+		}
+	})
+}
+
+// caseUpdate is the state a case is left in and the step recorded in its history
+type caseUpdate struct {
+	status       string
+	stage        string
+	nextActionAt *time.Time
+	closedAt     *time.Time
+	kind         string
+	note         string
+}
+
+// changeCase runs a change made by an agent in a unit of work, the case and its history
+// are written together or not at all
+func (c *CollectionsServiceImp) changeCase(
+	ctx context.Context,
+	caseID uuid.UUID,
+	change *CollectionsCaseChangeParams,
+	operation string,
+	update func(collectionsCase *collections.Case) *caseUpdate,
+) (*CollectionsCase, error) {
+	if err := checkActorAndReason(change.Actor, change.Reason); err != nil {
+		return nil, err
+	}
+
+	var changed *collections.Case
+
+	err := c.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		collectionsCase, err := lockCollectionsCase(ctx, txRepo, caseID)
+		if err != nil {
+			return err
+		}
+
+		caseChange := update(collectionsCase)
+		caseChange.note = change.Reason
+
+		changed, err = updateCollectionsCase(ctx, txRepo, collectionsCase, caseChange, change.Actor)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s collections case: %w", operation, err)
+	}
+
+	resp := newCollectionsCase(changed)
+
+	return &resp, nil
+}
+
+// closeSettledCase reports false when the plan of the case got overdue again or the case was closed meanwhile
+func (c *CollectionsServiceImp) closeSettledCase(ctx context.Context, caseID uuid.UUID, now time.Time) (bool, error) {
+	closed := false
+
+	err := c.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		collectionsCase, err := lockCollectionsCase(ctx, txRepo, caseID)
+		if err != nil {
+			return err
+		}
+
+		if collectionsCase.Status == CollectionsCaseStatusClosed {
+			return nil
+		}
+
+		overdue, err := listOverdueInstallments(ctx, txRepo, collectionsCase.PaymentPlanID)
+		if err != nil || len(overdue) > 0 {
+			return err
+		}
+
+		note := "the overdue installments are paid"
+
+		released, err := releaseCreditLine(ctx, txRepo, collectionsCase)
+		if err != nil {
+			return err
+		}
+
+		if released {
+			note += ", credit line unfrozen"
+		}
+
+		if _, err := updateCollectionsCase(ctx, txRepo, collectionsCase, &caseUpdate{
+			status:   CollectionsCaseStatusClosed,
+			stage:    collectionsCase.Stage,
+			closedAt: &now,
+			kind:     collections.EventClosed,
+			note:     note,
+		}, collectionsActor); err != nil {
+			return err
+		}
+
+		closed = true
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("close settled collections case: %w", err)
+	}
+
+	return closed, nil
+}
+
+// openCase locks the plan so concurrent runs do not both open a case for it,
+// it reports false when the plan has one already or is no longer complete
+func (c *CollectionsServiceImp) openCase(ctx context.Context, planID uuid.UUID, now time.Time) (bool, error) {
+	opened := false
+
+	err := c.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		plan, err := txRepo.LockPaymentPlan(ctx, planID)
+		if err != nil {
+			return LockPaymentPlanError{planID: planID}
+		}
+
+		if plan.Status != paymentPlanStatusComplete {
+			return nil
+		}
+
+		_, err = txRepo.GetActiveCollectionsCaseByPlanID(ctx, planID)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, repo.ErrRecordNotFound) {
+			return GetActiveCollectionsCaseError{planID: planID}
+		}
+
+		collectionsCase, err := txRepo.CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+			PaymentPlanID: planID,
+			UserID:        plan.UserID,
+			Status:        CollectionsCaseStatusOpen,
+			OpenedAt:      now,
+			NextActionAt:  c.ladder.NextActionAt("", now),
+		})
+		if err != nil {
+			return CreateCollectionsCaseError{planID: planID}
+		}
+
+		err = recordCollectionsCaseEvent(ctx, txRepo, collectionsCase, collections.EventOpened, collectionsActor, "")
+		if err != nil {
+			return err
+		}
+
+		opened = true
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("open collections case: %w", err)
+	}
+
+	return opened, nil
+}
+
+// escalateCase runs the next stage of the case and the case records it in the same unit of work,
+// a notice which fails to send leaves the case at its stage to be attempted again on the next run.
+// It reports false when the case is no longer due.
+func (c *CollectionsServiceImp) escalateCase(ctx context.Context, caseID uuid.UUID, now time.Time) (bool, error) {
+	escalated := false
+
+	err := c.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		collectionsCase, err := lockCollectionsCase(ctx, txRepo, caseID)
+		if err != nil {
+			return err
+		}
+
+		if collectionsCase.Status != CollectionsCaseStatusOpen ||
+			collectionsCase.NextActionAt == nil || collectionsCase.NextActionAt.After(now) {
+			return nil
+		}
+
+		stage, ok := c.ladder.Next(collectionsCase.Stage)
+		if !ok {
+			// the stage of the case was the last one or was removed from the ladder, the case stays
+			// open with the collections team and its history shows it is no longer escalated
+			_, err := updateCollectionsCase(ctx, txRepo, collectionsCase, &caseUpdate{
+				status: collectionsCase.Status,
+				stage:  collectionsCase.Stage,
+				kind:   collections.EventEscalated,
+				note:   fmt.Sprintf("no stage after %q, left with the collections team", collectionsCase.Stage),
+			}, collectionsActor)
+
+			return err
+		}
+
+		overdue, err := listOverdueInstallments(ctx, txRepo, collectionsCase.PaymentPlanID)
+		if err != nil || len(overdue) == 0 {
+			// a settled plan has its case closed on the next run
+			return err
+		}
+
+		note, err := c.runStage(ctx, txRepo, collectionsCase, stage, overdue)
+		if err != nil {
+			return err
+		}
+
+		if _, err := updateCollectionsCase(ctx, txRepo, collectionsCase, &caseUpdate{
+			status:       CollectionsCaseStatusOpen,
+			stage:        stage.Name,
+			nextActionAt: c.ladder.NextActionAt(stage.Name, now),
+			kind:         collections.EventEscalated,
+			note:         note,
+		}, collectionsActor); err != nil {
+			return err
+		}
+
+		escalated = true
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("escalate collections case: %w", err)
+	}
+
+	return escalated, nil
+}
+
+// runStage does what the stage says and returns the note recorded with it
+func (c *CollectionsServiceImp) runStage(
+	ctx context.Context,
+	repository repo.Repository,
+	collectionsCase *collections.Case,
+	stage *collections.Stage,
+	overdue []*payments.Installment,
+) (string, error) {
+	switch stage.Action {
+	case collections.ActionNotify:
+		data, err := newCollectionsTemplateData(stage, overdue)
+		if err != nil {
+			return "", RenderCollectionsNoticeError{caseID: collectionsCase.ID, stage: stage.Name}
+		}
+
+		message, err := stage.Render(data)
+		if err != nil {
+			return "", RenderCollectionsNoticeError{caseID: collectionsCase.ID, stage: stage.Name}
+		}
+
+		if err := c.notifier.Notify(ctx, &reminder.Notification{
+			UserID:        collectionsCase.UserID,
+			PaymentPlanID: collectionsCase.PaymentPlanID,
+			Reminder:      stage.Name,
+			Message:       message,
+		}); err != nil {
+			return "", NotifyCollectionsCaseError{caseID: collectionsCase.ID, stage: stage.Name}
+		}
+
+		return "notice sent", nil
+	case collections.ActionRestrict:
+		return restrictCreditLine(ctx, repository, collectionsCase, stage.Name)
+	default:
+		return "handed over to the collections team", nil
+	}
+}
+
+// restrictCreditLine freezes the active credit line of the user, a user without one
+// or whose line is already frozen or closed is left as it is
+func restrictCreditLine(
+	ctx context.Context,
+	repository repo.Repository,
+	collectionsCase *collections.Case,
+	stage string,
+) (string, error) {
+	creditLine, err := lockCreditLine(ctx, repository, collectionsCase.UserID)
+	if err != nil {
+		if errors.As(err, &CreditLineNotFoundError{}) {
+			return "no credit line to freeze", nil
+		}
+
+		return "", err
+	}
+
+	if creditLine.Status != CreditLineStatusActive {
+		return fmt.Sprintf("credit line already %s", creditLine.Status), nil
+	}
+
+	frozen, err := updateCreditLineStatus(ctx, repository, creditLine, CreditLineStatusFrozen)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := recordCreditLineChange(
+		ctx, repository, frozen, creditLineActionFreeze, collectionsActor,
+		collectionsCaseReason(collectionsCase.ID)+" stage "+stage,
+	); err != nil {
+		return "", err
+	}
+
+	return "credit line frozen", nil
+}
+
+// releaseCreditLine unfreezes the credit line the case froze, a line changed since the case froze it
+// is left as it is. It reports whether the line was unfrozen.
+func releaseCreditLine(
+	ctx context.Context,
+	repository repo.Repository,
+	collectionsCase *collections.Case,
+) (bool, error) {
+	creditLine, err := lockCreditLine(ctx, repository, collectionsCase.UserID)
+	if err != nil {
+		if errors.As(err, &CreditLineNotFoundError{}) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if creditLine.Status != CreditLineStatusFrozen {
+		return false, nil
+	}
+
+	changes, err := repository.ListCreditLineChangesByCreditLineID(ctx, creditLine.ID)
+	if err != nil {
+		return false, ListCreditLineChangesError{userID: creditLine.UserID}
+	}
+
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	reason := collectionsCaseReason(collectionsCase.ID)
+	latest := changes[len(changes)-1]
+	if latest.Action != creditLineActionFreeze || latest.Actor != collectionsActor ||
+		!strings.HasPrefix(latest.Reason, reason+" ") {
+		return false, nil
+	}
+
+	unfrozen, err := updateCreditLineStatus(ctx, repository, creditLine, CreditLineStatusActive)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := recordCreditLineChange(
+		ctx, repository, unfrozen, creditLineActionUnfreeze, collectionsActor, reason+" closed",
+	); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// collectionsCaseReason starts the reason of the credit line changes made by a case
+func collectionsCaseReason(caseID uuid.UUID) string {
+	return "collections case " + caseID.String()
+}
+
+func lockCollectionsCase(ctx context.Context, repository repo.Repository, caseID uuid.UUID) (*collections.Case, error) {
+	collectionsCase, err := repository.LockCollectionsCase(ctx, caseID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, CollectionsCaseNotFoundError{caseID: caseID}
+		}
+
+		return nil, LockCollectionsCaseError{caseID: caseID}
+	}
+
+	return collectionsCase, nil
+}
+
+// updateCollectionsCase the status change goes through the state machine, the case
+// and the step of its history are written with the same repository
+func updateCollectionsCase(
+	ctx context.Context,
+	repository repo.Repository,
+	collectionsCase *collections.Case,
+	update *caseUpdate,
+	actor string,
+) (*collections.Case, error) {
+	if update.status != collectionsCase.Status {
+		if err := collectionsStates.Transition(collectionsCase, collectionsCase.Status, update.status); err != nil {
+			return nil, InvalidStateTransitionError{
+				entity: "collections case",
+				id:     collectionsCase.ID,
+				from:   collectionsCase.Status,
+				to:     update.status,
+			}
+		}
+	}
+
+	updated, err := repository.UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+		ID:           collectionsCase.ID,
+		Status:       update.status,
+		Stage:        update.stage,
+		NextActionAt: update.nextActionAt,
+		ClosedAt:     update.closedAt,
+	})
+	if err != nil {
+		return nil, UpdateCollectionsCaseError{caseID: collectionsCase.ID}
+	}
+
+	if err := recordCollectionsCaseEvent(ctx, repository, updated, update.kind, actor, update.note); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func recordCollectionsCaseEvent(
+	ctx context.Context,
+	repository repo.Repository,
+	collectionsCase *collections.Case,
+	kind string,
+	actor string,
+	note string,
+) error {
+	if _, err := repository.CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+		CaseID: collectionsCase.ID,
+		Kind:   kind,
+		Stage:  collectionsCase.Stage,
+		Actor:  actor,
+		Note:   note,
+	}); err != nil {
+		return CreateCollectionsCaseEventError{caseID: collectionsCase.ID}
+	}
+
+	return nil
+}
+
+func listOverdueInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	planID uuid.UUID,
+) ([]*payments.Installment, error) {
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, planID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: planID}
+	}
+
+	overdue := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if inst.Status == PaymentInstallmentStatusOverdue {
+			overdue = append(overdue, inst)
+		}
+	}
+
+	return overdue, nil
+}
+
+// newCollectionsTemplateData Amount sums the overdue installments, late fees excluded
+func newCollectionsTemplateData(
+	stage *collections.Stage,
+	overdue []*payments.Installment,
+) (collections.TemplateData, error) {
+	amount := overdue[0].Amount
+
+	for _, inst := range overdue[1:] {
+		var err error
+
+		if amount, err = amount.Add(inst.Amount); err != nil {
+			return collections.TemplateData{}, err
+		}
+	}
+
+	return collections.TemplateData{
+		Stage:        stage.Name,
+		Amount:       amount.Amount().String(),
+		Currency:     amount.Currency(),
+		Installments: len(overdue),
+	}, nil
+}
+
+func newCollectionsCase(collectionsCase *collections.Case) CollectionsCase {
+	resp := CollectionsCase{
+		ID:            collectionsCase.ID.String(),
+		PaymentPlanID: collectionsCase.PaymentPlanID.String(),
+		UserID:        collectionsCase.UserID.String(),
+		Status:        collectionsCase.Status,
+		Stage:         collectionsCase.Stage,
+		OpenedAt:      collectionsCase.OpenedAt.Format(common.TimeFormat),
+	}
+
+	if collectionsCase.NextActionAt != nil {
+		resp.NextActionAt = collectionsCase.NextActionAt.Format(common.TimeFormat)
+	}
+
+	if collectionsCase.ClosedAt != nil {
+		resp.ClosedAt = collectionsCase.ClosedAt.Format(common.TimeFormat)
+	}
+
+	return resp
+}
+
+func newCollectionsCaseEvent(event *collections.Event) CollectionsCaseEvent {
+	return CollectionsCaseEvent{
+		ID:        event.ID.String(),
+		Kind:      event.Kind,
+		Stage:     event.Stage,
+		Actor:     event.Actor,
+		Note:      event.Note,
+		CreatedAt: event.CreatedAt.Format(common.TimeFormat),
+	}
+}
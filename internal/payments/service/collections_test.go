@@ -0,0 +1,1062 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/collections"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/repo/memory"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func mustLadder(t *testing.T) *collections.Ladder {
+	t.Helper()
+
+	stage := func(name string, delay time.Duration, action, text string) *collections.Stage {
+		s, err := collections.NewStage(name, delay, action, text)
+		if err != nil {
+			t.Fatalf("collections.NewStage() error = %v", err)
+		}
+
+		return s
+	}
+
+	ladder, err := collections.NewLadder(
+		stage("first_notice", 0, collections.ActionNotify, "{{.Amount}} {{.Currency}} is overdue"),
+		stage("restriction", 7*24*time.Hour, collections.ActionRestrict, ""),
+		stage("handover", 7*24*time.Hour, collections.ActionHandover, ""),
+	)
+	if err != nil {
+		t.Fatalf("collections.NewLadder() error = %v", err)
+	}
+
+	return ladder
+}
+
+func TestCollectionsServiceImp_RunCollections(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		now          = time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+		userID       = uuid.Must(uuid.NewV4())
+		caseID       = uuid.Must(uuid.NewV4())
+		creditLineID = uuid.Must(uuid.NewV4())
+		plan         = &payments.Plan{ID: uuid.Must(uuid.NewV4()), UserID: userID, Status: paymentPlanStatusComplete}
+		limit        = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		dueArg       = &collections.ListDueCasesParams{Before: now, Limit: 10}
+		overdue      = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Amount:        payments.MustNewMoney(decimal.New(2500, 2), "usdc"),
+			Status:        PaymentInstallmentStatusOverdue,
+		}
+		paid = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Amount:        payments.MustNewMoney(decimal.New(2500, 2), "usdc"),
+			Status:        PaymentInstallmentStatusPaid,
+		}
+	)
+
+	caseAt := func(status, stage string, nextActionAt *time.Time) *collections.Case {
+		return &collections.Case{
+			ID:            caseID,
+			PaymentPlanID: plan.ID,
+			UserID:        userID,
+			Status:        status,
+			Stage:         stage,
+			NextActionAt:  nextActionAt,
+			OpenedAt:      now,
+		}
+	}
+
+	nextWeek := now.Add(7 * 24 * time.Hour)
+
+	noSettledNorDelinquent := func(rm *repomock.MockRepository) []*gomock.Call {
+		return []*gomock.Call{
+			rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return(nil, nil),
+			rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return(nil, nil),
+		}
+	}
+
+	settledCase := func(rm *repomock.MockRepository) []*gomock.Call {
+		return []*gomock.Call{
+			rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).
+				Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "restriction", &nextWeek)}, nil),
+			rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+			rm.EXPECT().LockCollectionsCase(ctx, caseID).
+				Return(caseAt(CollectionsCaseStatusOpen, "restriction", &nextWeek), nil),
+			rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{paid}, nil),
+			rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(&payments.CreditLine{
+				ID: creditLineID, UserID: userID, Limit: limit, Status: CreditLineStatusFrozen,
+			}, nil),
+		}
+	}
+
+	closedWith := func(rm *repomock.MockRepository, note string) []*gomock.Call {
+		return []*gomock.Call{
+			rm.EXPECT().UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+				ID:       caseID,
+				Status:   CollectionsCaseStatusClosed,
+				Stage:    "restriction",
+				ClosedAt: &now,
+			}).Return(caseAt(CollectionsCaseStatusClosed, "restriction", nil), nil),
+			rm.EXPECT().CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+				CaseID: caseID,
+				Kind:   collections.EventClosed,
+				Stage:  "restriction",
+				Actor:  collectionsActor,
+				Note:   note,
+			}).Return(&collections.Event{}, nil),
+			rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return(nil, nil),
+			rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, nil),
+		}
+	}
+
+	escalatedTo := func(rm *repomock.MockRepository, stage string, note string) []*gomock.Call {
+		escalated := caseAt(CollectionsCaseStatusOpen, stage, &nextWeek)
+
+		return []*gomock.Call{
+			rm.EXPECT().UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+				ID:           caseID,
+				Status:       CollectionsCaseStatusOpen,
+				Stage:        stage,
+				NextActionAt: &nextWeek,
+			}).Return(escalated, nil),
+			rm.EXPECT().CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+				CaseID: caseID,
+				Kind:   collections.EventEscalated,
+				Stage:  stage,
+				Actor:  collectionsActor,
+				Note:   note,
+			}).Return(&collections.Event{}, nil),
+		}
+	}
+
+	tests := []struct {
+		name              string
+		prepare           func(rm *repomock.MockRepository)
+		failUser          uuid.UUID
+		want              *CollectionsRun
+		wantNotifications []*reminder.Notification
+		wantErr           error
+	}{
+		{
+			name: "open a case",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return(nil, nil),
+					rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().GetActiveCollectionsCaseByPlanID(ctx, plan.ID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateCollectionsCase(ctx, &collections.CreateCaseParams{
+						PaymentPlanID: plan.ID,
+						UserID:        userID,
+						Status:        CollectionsCaseStatusOpen,
+						OpenedAt:      now,
+						NextActionAt:  &now,
+					}).Return(caseAt(CollectionsCaseStatusOpen, "", &now), nil),
+					rm.EXPECT().CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+						CaseID: caseID,
+						Kind:   collections.EventOpened,
+						Actor:  collectionsActor,
+					}).Return(&collections.Event{}, nil),
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, nil),
+				)
+			},
+			want: &CollectionsRun{Opened: 1},
+		},
+		{
+			name: "plan already has a case",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return(nil, nil),
+					rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().GetActiveCollectionsCaseByPlanID(ctx, plan.ID).
+						Return(caseAt(CollectionsCaseStatusPaused, "", nil), nil),
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, nil),
+				)
+			},
+			want: &CollectionsRun{},
+		},
+		{
+			name: "close a settled case",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "first_notice", &nextWeek)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).
+						Return(caseAt(CollectionsCaseStatusOpen, "first_notice", &nextWeek), nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{paid}, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+						ID:       caseID,
+						Status:   CollectionsCaseStatusClosed,
+						Stage:    "first_notice",
+						ClosedAt: &now,
+					}).Return(caseAt(CollectionsCaseStatusClosed, "first_notice", nil), nil),
+					rm.EXPECT().CreateCollectionsCaseEvent(ctx, gomock.Any()).Return(&collections.Event{}, nil),
+					rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return(nil, nil),
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, nil),
+				)
+			},
+			want: &CollectionsRun{Closed: 1},
+		},
+		{
+			name: "close a settled case which froze the credit line",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := settledCase(rm)
+				calls = append(calls,
+					rm.EXPECT().ListCreditLineChangesByCreditLineID(ctx, creditLineID).
+						Return([]*payments.CreditLineChange{{
+							CreditLineID: creditLineID,
+							Action:       creditLineActionFreeze,
+							Actor:        collectionsActor,
+							Reason:       "collections case " + caseID.String() + " stage restriction",
+						}}, nil),
+					rm.EXPECT().UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+						ID:     creditLineID,
+						Status: CreditLineStatusActive,
+					}).Return(&payments.CreditLine{
+						ID: creditLineID, UserID: userID, Limit: limit, Status: CreditLineStatusActive,
+					}, nil),
+					rm.EXPECT().CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+						CreditLineID: creditLineID,
+						Action:       creditLineActionUnfreeze,
+						Actor:        collectionsActor,
+						Reason:       "collections case " + caseID.String() + " closed",
+						Limit:        limit,
+						Status:       CreditLineStatusActive,
+					}).Return(&payments.CreditLineChange{}, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Any()).
+						Return(payments.MustNewMoney(decimal.New(0, 0), "usdc"), nil),
+				)
+				calls = append(calls, closedWith(rm, "the overdue installments are paid, credit line unfrozen")...)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Closed: 1},
+		},
+		{
+			name: "close a settled case whose credit line was frozen since by someone else",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := settledCase(rm)
+				calls = append(calls,
+					rm.EXPECT().ListCreditLineChangesByCreditLineID(ctx, creditLineID).
+						Return([]*payments.CreditLineChange{
+							{
+								CreditLineID: creditLineID,
+								Action:       creditLineActionFreeze,
+								Actor:        collectionsActor,
+								Reason:       "collections case " + caseID.String() + " stage restriction",
+							},
+							{CreditLineID: creditLineID, Action: creditLineActionUnfreeze, Actor: "risk-ops"},
+							{CreditLineID: creditLineID, Action: creditLineActionFreeze, Actor: "risk-ops"},
+						}, nil),
+				)
+				calls = append(calls, closedWith(rm, "the overdue installments are paid")...)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Closed: 1},
+		},
+		{
+			name: "ListCreditLineChangesByCreditLineID error on close",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := settledCase(rm)
+				calls = append(calls,
+					rm.EXPECT().ListCreditLineChangesByCreditLineID(ctx, creditLineID).
+						Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return(nil, nil),
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, nil),
+				)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Failed: 1},
+		},
+		{
+			name: "escalate to a notice",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls,
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "", &now)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseAt(CollectionsCaseStatusOpen, "", &now), nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).
+						Return([]*payments.Installment{overdue, paid, overdue}, nil),
+				)
+				calls = append(calls, escalatedTo(rm, "first_notice", "notice sent")...)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Escalated: 1},
+			wantNotifications: []*reminder.Notification{
+				{
+					UserID:        userID,
+					PaymentPlanID: plan.ID,
+					Reminder:      "first_notice",
+					Message:       "50.00 usdc is overdue",
+				},
+			},
+		},
+		{
+			name: "escalate to a restriction",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls,
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "first_notice", &now)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).
+						Return(caseAt(CollectionsCaseStatusOpen, "first_notice", &now), nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{overdue}, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(&payments.CreditLine{
+						ID: creditLineID, UserID: userID, Limit: limit, Status: CreditLineStatusActive,
+					}, nil),
+					rm.EXPECT().UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+						ID:     creditLineID,
+						Status: CreditLineStatusFrozen,
+					}).Return(&payments.CreditLine{
+						ID: creditLineID, UserID: userID, Limit: limit, Status: CreditLineStatusFrozen,
+					}, nil),
+					rm.EXPECT().CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+						CreditLineID: creditLineID,
+						Action:       creditLineActionFreeze,
+						Actor:        collectionsActor,
+						Reason:       "collections case " + caseID.String() + " stage restriction",
+						Limit:        limit,
+						Status:       CreditLineStatusFrozen,
+					}).Return(&payments.CreditLineChange{}, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Any()).
+						Return(payments.MustNewMoney(decimal.New(25, 0), "usdc"), nil),
+				)
+				calls = append(calls, escalatedTo(rm, "restriction", "credit line frozen")...)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Escalated: 1},
+		},
+		{
+			name: "restriction without a credit line",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls,
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "first_notice", &now)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).
+						Return(caseAt(CollectionsCaseStatusOpen, "first_notice", &now), nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{overdue}, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+				)
+				calls = append(calls, escalatedTo(rm, "restriction", "no credit line to freeze")...)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Escalated: 1},
+		},
+		{
+			name: "last stage reached",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls,
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "handover", &now)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseAt(CollectionsCaseStatusOpen, "handover", &now), nil),
+					rm.EXPECT().UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+						ID:     caseID,
+						Status: CollectionsCaseStatusOpen,
+						Stage:  "handover",
+					}).Return(caseAt(CollectionsCaseStatusOpen, "handover", nil), nil),
+					rm.EXPECT().CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+						CaseID: caseID,
+						Kind:   collections.EventEscalated,
+						Stage:  "handover",
+						Actor:  collectionsActor,
+						Note:   `no stage after "handover", left with the collections team`,
+					}).Return(&collections.Event{}, nil),
+				)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{},
+		},
+		{
+			name:     "notice failed",
+			failUser: userID,
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls,
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "", &now)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseAt(CollectionsCaseStatusOpen, "", &now), nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{overdue}, nil),
+				)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{Failed: 1},
+		},
+		{
+			name: "case no longer due",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls,
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).
+						Return([]*collections.Case{caseAt(CollectionsCaseStatusOpen, "", &now)}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseAt(CollectionsCaseStatusPaused, "", nil), nil),
+				)
+				gomock.InOrder(calls...)
+			},
+			want: &CollectionsRun{},
+		},
+		{
+			name: "ListSettledCollectionsCases error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: ListCollectionsCasesError{status: "settled"},
+		},
+		{
+			name: "ListDelinquentPaymentPlans error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return(nil, nil),
+					rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListDelinquentPaymentPlansError{},
+		},
+		{
+			name: "ListDueCollectionsCases error",
+			prepare: func(rm *repomock.MockRepository) {
+				calls := noSettledNorDelinquent(rm)
+				calls = append(calls, rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, fmt.Errorf("dummyErr")))
+				gomock.InOrder(calls...)
+			},
+			wantErr: ListCollectionsCasesError{status: "due"},
+		},
+		{
+			name: "CreateCollectionsCase error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return(nil, nil),
+					rm.EXPECT().ListDelinquentPaymentPlans(ctx, int32(10)).Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().GetActiveCollectionsCaseByPlanID(ctx, plan.ID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateCollectionsCase(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().ListDueCollectionsCases(ctx, dueArg).Return(nil, nil),
+				)
+			},
+			want: &CollectionsRun{Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			notifier := reminder.NewMemoryNotifier()
+			notifier.Fail(tt.failUser, errors.New("dummyErr"))
+
+			c := NewCollectionsService(mustLadder(t))
+			c.UseRepo(rm)
+			c.UseNotifier(notifier)
+
+			got, err := c.RunCollections(ctx, now, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectionsServiceImp.RunCollections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectionsServiceImp.RunCollections() = %v, want %v", got, tt.want)
+			}
+
+			notifications := notifier.Notifications()
+			if len(notifications) != len(tt.wantNotifications) {
+				t.Fatalf("sent %d notifications, want %d", len(notifications), len(tt.wantNotifications))
+			}
+
+			for idx, notification := range notifications {
+				if !reflect.DeepEqual(notification, tt.wantNotifications[idx]) {
+					t.Errorf("notification %d = %+v, want %+v", idx, notification, tt.wantNotifications[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestCollectionsServiceImp_RunCollectionsWithoutNotifier(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		ctx    = context.Background()
+		now    = time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+		caseID = uuid.Must(uuid.NewV4())
+		userID = uuid.Must(uuid.NewV4())
+		open   = &collections.Case{ID: caseID, UserID: userID, Status: CollectionsCaseStatusOpen, Stage: "first_notice"}
+		rm     = repomock.NewMockRepository(ctrl)
+	)
+
+	// the settled cases are closed without a notifier, nothing else is done
+	gomock.InOrder(
+		rm.EXPECT().ListSettledCollectionsCases(ctx, int32(10)).Return([]*collections.Case{open}, nil),
+		rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+		rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(open, nil),
+		rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Any()).Return(nil, nil),
+		rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+		rm.EXPECT().UpdateCollectionsCase(ctx, gomock.Any()).Return(open, nil),
+		rm.EXPECT().CreateCollectionsCaseEvent(ctx, gomock.Any()).Return(&collections.Event{}, nil),
+	)
+
+	c := NewCollectionsService(mustLadder(t))
+	c.UseRepo(rm)
+
+	got, err := c.RunCollections(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("CollectionsServiceImp.RunCollections() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, &CollectionsRun{Closed: 1}) {
+		t.Errorf("CollectionsServiceImp.RunCollections() = %v, want the settled case closed", got)
+	}
+}
+
+func TestCollectionsServiceImp_ChangeCollectionsCase(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		now      = time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+		caseID   = uuid.Must(uuid.NewV4())
+		planID   = uuid.Must(uuid.NewV4())
+		userID   = uuid.Must(uuid.NewV4())
+		change   = &CollectionsCaseChangeParams{Actor: "agent-7", Reason: "payment promised"}
+		nextWeek = now.Add(7 * 24 * time.Hour)
+	)
+
+	caseIn := func(status string, nextActionAt, closedAt *time.Time) *collections.Case {
+		return &collections.Case{
+			ID:            caseID,
+			PaymentPlanID: planID,
+			UserID:        userID,
+			Status:        status,
+			Stage:         "first_notice",
+			NextActionAt:  nextActionAt,
+			OpenedAt:      now,
+			ClosedAt:      closedAt,
+		}
+	}
+
+	pause := func(c *CollectionsServiceImp, change *CollectionsCaseChangeParams) (*CollectionsCase, error) {
+		return c.PauseCollectionsCase(ctx, caseID, change)
+	}
+	resume := func(c *CollectionsServiceImp, change *CollectionsCaseChangeParams) (*CollectionsCase, error) {
+		return c.ResumeCollectionsCase(ctx, caseID, now, change)
+	}
+	closeCase := func(c *CollectionsServiceImp, change *CollectionsCaseChangeParams) (*CollectionsCase, error) {
+		return c.CloseCollectionsCase(ctx, caseID, now, change)
+	}
+
+	changedTo := func(from *collections.Case, to *collections.Case, kind string) func(rm *repomock.MockRepository) {
+		return func(rm *repomock.MockRepository) {
+			gomock.InOrder(
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+				rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(from, nil),
+				rm.EXPECT().UpdateCollectionsCase(ctx, &collections.UpdateCaseParams{
+					ID:           caseID,
+					Status:       to.Status,
+					Stage:        "first_notice",
+					NextActionAt: to.NextActionAt,
+					ClosedAt:     to.ClosedAt,
+				}).Return(to, nil),
+				rm.EXPECT().CreateCollectionsCaseEvent(ctx, &collections.CreateEventParams{
+					CaseID: caseID,
+					Kind:   kind,
+					Stage:  "first_notice",
+					Actor:  "agent-7",
+					Note:   "payment promised",
+				}).Return(&collections.Event{}, nil),
+			)
+		}
+	}
+
+	wantCase := func(status, nextActionAt, closedAt string) *CollectionsCase {
+		return &CollectionsCase{
+			ID:            caseID.String(),
+			PaymentPlanID: planID.String(),
+			UserID:        userID.String(),
+			Status:        status,
+			Stage:         "first_notice",
+			NextActionAt:  nextActionAt,
+			OpenedAt:      "2022-07-10T12:00:00Z",
+			ClosedAt:      closedAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		call    func(c *CollectionsServiceImp, change *CollectionsCaseChangeParams) (*CollectionsCase, error)
+		change  *CollectionsCaseChangeParams
+		prepare func(rm *repomock.MockRepository)
+		want    *CollectionsCase
+		wantErr error
+	}{
+		{
+			name: "pause",
+			call: pause,
+			prepare: changedTo(
+				caseIn(CollectionsCaseStatusOpen, &nextWeek, nil),
+				caseIn(CollectionsCaseStatusPaused, nil, nil),
+				collections.EventPaused,
+			),
+			want: wantCase(CollectionsCaseStatusPaused, "", ""),
+		},
+		{
+			name: "resume",
+			call: resume,
+			prepare: changedTo(
+				caseIn(CollectionsCaseStatusPaused, nil, nil),
+				caseIn(CollectionsCaseStatusOpen, &nextWeek, nil),
+				collections.EventResumed,
+			),
+			want: wantCase(CollectionsCaseStatusOpen, "2022-07-17T12:00:00Z", ""),
+		},
+		{
+			name: "close",
+			call: closeCase,
+			prepare: changedTo(
+				caseIn(CollectionsCaseStatusPaused, nil, nil),
+				caseIn(CollectionsCaseStatusClosed, nil, &now),
+				collections.EventClosed,
+			),
+			want: wantCase(CollectionsCaseStatusClosed, "", "2022-07-10T12:00:00Z"),
+		},
+		{
+			name:    "missing actor",
+			call:    pause,
+			change:  &CollectionsCaseChangeParams{Reason: "payment promised"},
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: MissingActorError{},
+		},
+		{
+			name:    "missing reason",
+			call:    pause,
+			change:  &CollectionsCaseChangeParams{Actor: "agent-7"},
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: MissingReasonError{},
+		},
+		{
+			name: "resume a closed case",
+			call: resume,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseIn(CollectionsCaseStatusClosed, nil, &now), nil),
+				)
+			},
+			wantErr: InvalidStateTransitionError{
+				entity: "collections case",
+				id:     caseID,
+				from:   CollectionsCaseStatusClosed,
+				to:     CollectionsCaseStatusOpen,
+			},
+		},
+		{
+			name: "case not found",
+			call: pause,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			wantErr: CollectionsCaseNotFoundError{caseID: caseID},
+		},
+		{
+			name: "LockCollectionsCase error",
+			call: pause,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: LockCollectionsCaseError{caseID: caseID},
+		},
+		{
+			name: "UpdateCollectionsCase error",
+			call: pause,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseIn(CollectionsCaseStatusOpen, &nextWeek, nil), nil),
+					rm.EXPECT().UpdateCollectionsCase(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateCollectionsCaseError{caseID: caseID},
+		},
+		{
+			name: "CreateCollectionsCaseEvent error",
+			call: pause,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCollectionsCase(ctx, caseID).Return(caseIn(CollectionsCaseStatusOpen, &nextWeek, nil), nil),
+					rm.EXPECT().UpdateCollectionsCase(ctx, gomock.Any()).Return(caseIn(CollectionsCaseStatusPaused, nil, nil), nil),
+					rm.EXPECT().CreateCollectionsCaseEvent(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateCollectionsCaseEventError{caseID: caseID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			c := NewCollectionsService(mustLadder(t))
+			c.UseRepo(rm)
+
+			caseChange := tt.change
+			if caseChange == nil {
+				caseChange = change
+			}
+
+			got, err := tt.call(c, caseChange)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectionsServiceImp change error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectionsServiceImp change = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionsServiceImp_GetCollectionsCase(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		at        = time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+		caseID    = uuid.Must(uuid.NewV4())
+		eventID   = uuid.Must(uuid.NewV4())
+		planID    = uuid.Must(uuid.NewV4())
+		userID    = uuid.Must(uuid.NewV4())
+		collCase  = &collections.Case{ID: caseID, PaymentPlanID: planID, UserID: userID, Status: "open", OpenedAt: at}
+		openEvent = &collections.Event{ID: eventID, CaseID: caseID, Kind: "opened", Actor: "collections", CreatedAt: at}
+	)
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    *CollectionsCase
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCollectionsCaseByID(ctx, caseID).Return(collCase, nil),
+					rm.EXPECT().ListCollectionsCaseEventsByCaseID(ctx, caseID).
+						Return([]*collections.Event{openEvent}, nil),
+				)
+			},
+			want: &CollectionsCase{
+				ID:            caseID.String(),
+				PaymentPlanID: planID.String(),
+				UserID:        userID.String(),
+				Status:        "open",
+				OpenedAt:      "2022-07-10T12:00:00Z",
+				History: []CollectionsCaseEvent{
+					{ID: eventID.String(), Kind: "opened", Actor: "collections", CreatedAt: "2022-07-10T12:00:00Z"},
+				},
+			},
+		},
+		{
+			name: "case not found",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetCollectionsCaseByID(ctx, caseID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: CollectionsCaseNotFoundError{caseID: caseID},
+		},
+		{
+			name: "GetCollectionsCaseByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetCollectionsCaseByID(ctx, caseID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: GetCollectionsCaseError{caseID: caseID},
+		},
+		{
+			name: "ListCollectionsCaseEventsByCaseID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCollectionsCaseByID(ctx, caseID).Return(collCase, nil),
+					rm.EXPECT().ListCollectionsCaseEventsByCaseID(ctx, caseID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListCollectionsCaseEventsError{caseID: caseID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			c := NewCollectionsService(mustLadder(t))
+			c.UseRepo(rm)
+
+			got, err := c.GetCollectionsCase(ctx, caseID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectionsServiceImp.GetCollectionsCase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectionsServiceImp.GetCollectionsCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionsServiceImp_ListCollectionsCases(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		at       = time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+		collCase = &collections.Case{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: uuid.Must(uuid.NewV4()),
+			UserID:        uuid.Must(uuid.NewV4()),
+			Status:        "paused",
+			Stage:         "first_notice",
+			OpenedAt:      at,
+		}
+	)
+
+	tests := []struct {
+		name    string
+		status  string
+		prepare func(rm *repomock.MockRepository)
+		want    []CollectionsCase
+		wantErr error
+	}{
+		{
+			name:   "happy path",
+			status: "paused",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListCollectionsCasesByStatus(ctx, "paused").Return([]*collections.Case{collCase}, nil)
+			},
+			want: []CollectionsCase{
+				{
+					ID:            collCase.ID.String(),
+					PaymentPlanID: collCase.PaymentPlanID.String(),
+					UserID:        collCase.UserID.String(),
+					Status:        "paused",
+					Stage:         "first_notice",
+					OpenedAt:      "2022-07-10T12:00:00Z",
+				},
+			},
+		},
+		{
+			name:    "invalid status",
+			status:  "escalated",
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: InvalidCollectionsCaseStatusError{status: "escalated"},
+		},
+		{
+			name:   "ListCollectionsCasesByStatus error",
+			status: "open",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListCollectionsCasesByStatus(ctx, "open").Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: ListCollectionsCasesError{status: "open"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			c := NewCollectionsService(mustLadder(t))
+			c.UseRepo(rm)
+
+			got, err := c.ListCollectionsCases(ctx, tt.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectionsServiceImp.ListCollectionsCases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectionsServiceImp.ListCollectionsCases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionsServiceImp_RunCollections_Ladder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		dueAt   = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		memRepo = memory.NewInMemRepository()
+		userID  = uuid.Must(uuid.NewV4())
+	)
+
+	plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+		Status: paymentPlanStatusComplete,
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	inst, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+		PaymentPlanID: plan.ID,
+		Amount:        payments.MustNewMoney(decimal.New(25, 0), "usdc"),
+		DueAt:         dueAt,
+		Status:        PaymentInstallmentStatusOverdue,
+	})
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
+	}
+
+	creditLine, err := memRepo.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: userID,
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: CreditLineStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("fail to create credit line: %v", err)
+	}
+
+	notifier := reminder.NewMemoryNotifier()
+	c := NewCollectionsService(mustLadder(t))
+	c.UseRepo(memRepo)
+	c.UseNotifier(notifier)
+
+	run := func(now time.Time) *CollectionsRun {
+		got, err := c.RunCollections(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("CollectionsServiceImp.RunCollections() error = %v", err)
+		}
+
+		return got
+	}
+
+	start := dueAt.Add(24 * time.Hour)
+
+	// the first stage has no delay, the case opens and sends its notice in the same run
+	if got := run(start); got.Opened != 1 || got.Escalated != 1 {
+		t.Errorf("first run: got %+v, want 1 opened and 1 escalated", got)
+	}
+
+	if got := run(start.Add(time.Hour)); !reflect.DeepEqual(got, &CollectionsRun{}) {
+		t.Errorf("second run: got %+v, want nothing done", got)
+	}
+
+	if got := run(start.Add(7 * 24 * time.Hour)); got.Escalated != 1 {
+		t.Errorf("a week later: got %+v, want 1 escalated", got)
+	}
+
+	frozen, err := memRepo.GetCreditLineByUserID(ctx, userID)
+	if err != nil || frozen.Status != CreditLineStatusFrozen {
+		t.Errorf("credit line %v = %v, %v, want it frozen", creditLine.ID, frozen, err)
+	}
+
+	if _, err := memRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     inst.ID,
+		Status: PaymentInstallmentStatusPaid,
+	}); err != nil {
+		t.Fatalf("fail to pay installment: %v", err)
+	}
+
+	if got := run(start.Add(14 * 24 * time.Hour)); got.Closed != 1 || got.Escalated != 0 {
+		t.Errorf("after the payment: got %+v, want 1 closed", got)
+	}
+
+	unfrozen, err := memRepo.GetCreditLineByUserID(ctx, userID)
+	if err != nil || unfrozen.Status != CreditLineStatusActive {
+		t.Errorf("credit line %v = %v, %v, want it unfrozen", creditLine.ID, unfrozen, err)
+	}
+
+	cases, err := c.ListCollectionsCases(ctx, CollectionsCaseStatusClosed)
+	if err != nil || len(cases) != 1 {
+		t.Fatalf("CollectionsServiceImp.ListCollectionsCases() = %v, %v, want the closed case", cases, err)
+	}
+
+	got, err := c.GetCollectionsCase(ctx, uuid.FromStringOrNil(cases[0].ID))
+	if err != nil {
+		t.Fatalf("CollectionsServiceImp.GetCollectionsCase() error = %v", err)
+	}
+
+	wantKinds := []string{
+		collections.EventOpened, collections.EventEscalated, collections.EventEscalated, collections.EventClosed,
+	}
+	if len(got.History) != len(wantKinds) {
+		t.Fatalf("history = %+v, want %v", got.History, wantKinds)
+	}
+
+	for idx, event := range got.History {
+		if event.Kind != wantKinds[idx] {
+			t.Errorf("history %d = %v, want %v", idx, event.Kind, wantKinds[idx])
+		}
+	}
+
+	notifications := notifier.Notifications()
+	if len(notifications) != 1 || notifications[0].Message != "25 usdc is overdue" {
+		t.Errorf("notifications = %+v, want the first notice", notifications)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/statemachine"
+
+	"github.com/gofrs/uuid"
+)
+
+// the states of a credit line, the credit_line_status database enum
+const (
+	CreditLineStatusActive = statemachine.CreditLineActive
+	CreditLineStatusFrozen = statemachine.CreditLineFrozen
+	CreditLineStatusClosed = statemachine.CreditLineClosed
+)
+
+var _ CreditLineService = (*CreditLineServiceImp)(nil)
+
+type CreditLineServiceImp struct {
+	repository repo.Repository
+}
+
+func NewCreditLineService() *CreditLineServiceImp {
+	return &CreditLineServiceImp{}
+}
+
+func (c *CreditLineServiceImp) UseRepo(repository repo.Repository) {
+	c.repository = repository
+}
+
+func (c *CreditLineServiceImp) GetCreditLine(ctx context.Context, userID uuid.UUID) (*CreditLine, error) {
+	creditLine, err := c.repository.GetCreditLineByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, CreditLineNotFoundError{userID: userID}
+		}
+
+		return nil, GetCreditLineByUserIDError{userID: userID}
+	}
+
+	available, err := availableCredit(ctx, c.repository, creditLine)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCreditLine(creditLine, available), nil
+}
+
+// availableCredit is never negative, a limit lowered below what the user owes leaves nothing available
+func availableCredit(
+	ctx context.Context,
+	repository repo.Repository,
+	creditLine *payments.CreditLine,
+) (payments.Money, error) {
+	outstanding, err := repository.GetUserOutstandingAmount(ctx, &payments.UserOutstandingAmountParams{
+		UserID:   creditLine.UserID,
+		Currency: creditLine.Limit.Currency(),
+	})
+	if err != nil {
+		return payments.Money{}, GetUserOutstandingAmountError{userID: creditLine.UserID}
+	}
+
+	available, err := creditLine.Limit.Sub(outstanding)
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	if available.Sign() < 0 {
+		return payments.ZeroMoney(available.Currency())
+	}
+
+	return available, nil
+}
+
+// reserveCredit checks that amount fits in what is available on the active credit line of the user.
+// The line stays locked until the unit of work ends, the unpaid installments created in it
+// then hold the credit until they are paid or voided.
+func reserveCredit(
+	ctx context.Context,
+	repository repo.Repository,
+	userID uuid.UUID,
+	amount payments.Money,
+) error {
+	creditLine, err := lockCreditLine(ctx, repository, userID)
+	if err != nil {
+		return err
+	}
+
+	if creditLine.Status != CreditLineStatusActive {
+		return CreditLineNotActiveError{userID: userID, status: creditLine.Status}
+	}
+
+	// a line in another currency leaves no credit in the currency of the amount
+	available, err := payments.ZeroMoney(amount.Currency())
+	if err != nil {
+		return err
+	}
+
+	if creditLine.Limit.Currency() == amount.Currency() {
+		if available, err = availableCredit(ctx, repository, creditLine); err != nil {
+			return err
+		}
+	}
+
+	if cmp, err := amount.Cmp(available); err != nil || cmp > 0 {
+		return InsufficientCreditError{
+			userID:    userID,
+			amount:    amount.String(),
+			available: available.String(),
+			currency:  amount.Currency(),
+		}
+	}
+
+	return nil
+}
+
+func lockCreditLine(ctx context.Context, repository repo.Repository, userID uuid.UUID) (*payments.CreditLine, error) {
+	creditLine, err := repository.LockCreditLineByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, CreditLineNotFoundError{userID: userID}
+		}
+
+		return nil, LockCreditLineError{userID: userID}
+	}
+
+	return creditLine, nil
+}
+
+func newCreditLine(creditLine *payments.CreditLine, available payments.Money) *CreditLine {
+	return &CreditLine{
+		ID:              creditLine.ID.String(),
+		UserID:          creditLine.UserID.String(),
+		TotalAmount:     creditLine.Limit,
+		AvailableAmount: available,
+		Status:          creditLine.Status,
+	}
+}
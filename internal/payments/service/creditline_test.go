@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCreditLineServiceImp_GetCreditLine(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		userID       = uuid.Must(uuid.NewV4())
+		creditLineID = uuid.Must(uuid.NewV4())
+		currency     = "usdc"
+
+		creditLine = &payments.CreditLine{
+			ID:     creditLineID,
+			UserID: userID,
+			Limit:  payments.MustNewMoney(decimal.New(1000, 0), currency),
+			Status: "active",
+		}
+		outstandingParams = &payments.UserOutstandingAmountParams{UserID: userID, Currency: currency}
+	)
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    *CreditLine
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Eq(outstandingParams)).
+						Return(payments.MustNewMoney(decimal.New(2495, 1), currency), nil),
+				)
+			},
+			want: &CreditLine{
+				ID:              creditLineID.String(),
+				UserID:          userID.String(),
+				TotalAmount:     creditLine.Limit,
+				AvailableAmount: payments.MustNewMoney(decimal.New(7505, 1), currency),
+				Status:          "active",
+			},
+		},
+		{
+			name: "nothing available once the limit is exceeded",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Eq(outstandingParams)).
+						Return(payments.MustNewMoney(decimal.New(1200, 0), currency), nil),
+				)
+			},
+			want: &CreditLine{
+				ID:              creditLineID.String(),
+				UserID:          userID.String(),
+				TotalAmount:     creditLine.Limit,
+				AvailableAmount: payments.MustNewMoney(decimal.New(0, 0), currency),
+				Status:          "active",
+			},
+		},
+		{
+			name: "CreditLineNotFound error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: CreditLineNotFoundError{userID: userID},
+		},
+		{
+			name: "GetCreditLineByUserID error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: GetCreditLineByUserIDError{userID: userID},
+		},
+		{
+			name: "GetUserOutstandingAmount error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Any()).Return(payments.Money{}, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: GetUserOutstandingAmountError{userID: userID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			c := NewCreditLineService()
+			c.UseRepo(rm)
+
+			got, err := c.GetCreditLine(ctx, userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreditLineServiceImp.GetCreditLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreditLineServiceImp.GetCreditLine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
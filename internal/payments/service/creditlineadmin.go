@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// the changes made to a credit line, the credit_line_action database enum
+const (
+	creditLineActionCreate      = "create"
+	creditLineActionUpdateLimit = "update_limit"
+	creditLineActionFreeze      = "freeze"
+	creditLineActionUnfreeze    = "unfreeze"
+	creditLineActionClose       = "close"
+)
+
+// CreateCreditLine a user has one credit line at most, a closed one included
+func (c *CreditLineServiceImp) CreateCreditLine(
+	ctx context.Context,
+	userID uuid.UUID,
+	params *CreateCreditLineParams,
+) (*CreditLine, error) {
+	if err := checkPositiveAmount(params.Limit, "limit"); err != nil {
+		return nil, err
+	}
+
+	if err := checkActorAndReason(params.Actor, params.Reason); err != nil {
+		return nil, err
+	}
+
+	return c.changeCreditLine(ctx, "create credit line", func(txRepo repo.Repository) (*CreditLine, error) {
+		return createCreditLine(ctx, txRepo, userID, params)
+	})
+}
+
+// UpdateCreditLineLimit a limit lowered below what the user owes leaves nothing available,
+// the running payment plans are not affected
+func (c *CreditLineServiceImp) UpdateCreditLineLimit(
+	ctx context.Context,
+	userID uuid.UUID,
+	params *UpdateCreditLineLimitParams,
+) (*CreditLine, error) {
+	if err := checkPositiveAmount(params.Limit, "limit"); err != nil {
+		return nil, err
+	}
+
+	if err := checkActorAndReason(params.Actor, params.Reason); err != nil {
+		return nil, err
+	}
+
+	return c.changeCreditLine(ctx, "update credit line limit", func(txRepo repo.Repository) (*CreditLine, error) {
+		return updateCreditLineLimit(ctx, txRepo, userID, params)
+	})
+}
+
+func (c *CreditLineServiceImp) FreezeCreditLine(
+	ctx context.Context,
+	userID uuid.UUID,
+	change *CreditLineChangeParams,
+) (*CreditLine, error) {
+	return c.changeCreditLineStatus(ctx, userID, CreditLineStatusFrozen, creditLineActionFreeze, change)
+}
+
+func (c *CreditLineServiceImp) UnfreezeCreditLine(
+	ctx context.Context,
+	userID uuid.UUID,
+	change *CreditLineChangeParams,
+) (*CreditLine, error) {
+	return c.changeCreditLineStatus(ctx, userID, CreditLineStatusActive, creditLineActionUnfreeze, change)
+}
+
+func (c *CreditLineServiceImp) CloseCreditLine(
+	ctx context.Context,
+	userID uuid.UUID,
+	change *CreditLineChangeParams,
+) (*CreditLine, error) {
+	return c.changeCreditLineStatus(ctx, userID, CreditLineStatusClosed, creditLineActionClose, change)
+}
+
+func (c *CreditLineServiceImp) ListCreditLineChanges(ctx context.Context, userID uuid.UUID) ([]CreditLineChange, error) {
+	creditLine, err := c.repository.GetCreditLineByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, CreditLineNotFoundError{userID: userID}
+		}
+
+		return nil, GetCreditLineByUserIDError{userID: userID}
+	}
+
+	changes, err := c.repository.ListCreditLineChangesByCreditLineID(ctx, creditLine.ID)
+	if err != nil {
+		return nil, ListCreditLineChangesError{userID: userID}
+	}
+
+	res := make([]CreditLineChange, len(changes))
+	for idx, change := range changes {
+		res[idx] = newCreditLineChange(change)
+	}
+
+	return res, nil
+}
+
+func (c *CreditLineServiceImp) changeCreditLineStatus(
+	ctx context.Context,
+	userID uuid.UUID,
+	status string,
+	action string,
+	change *CreditLineChangeParams,
+) (*CreditLine, error) {
+	if err := checkActorAndReason(change.Actor, change.Reason); err != nil {
+		return nil, err
+	}
+
+	return c.changeCreditLine(ctx, action+" credit line", func(txRepo repo.Repository) (*CreditLine, error) {
+		creditLine, err := lockCreditLine(ctx, txRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		updatedCreditLine, err := updateCreditLineStatus(ctx, txRepo, creditLine, status)
+		if err != nil {
+			return nil, err
+		}
+
+		return recordCreditLineChange(ctx, txRepo, updatedCreditLine, action, change.Actor, change.Reason)
+	})
+}
+
+// changeCreditLine runs change in a unit of work, the credit line and the record of the change
+// are written together or not at all
+func (c *CreditLineServiceImp) changeCreditLine(
+	ctx context.Context,
+	operation string,
+	change func(txRepo repo.Repository) (*CreditLine, error),
+) (*CreditLine, error) {
+	var changedCreditLine *CreditLine
+
+	err := c.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		changedCreditLine, txErr = change(txRepo)
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", operation, err)
+	}
+
+	return changedCreditLine, nil
+}
+
+func createCreditLine(
+	ctx context.Context,
+	repository repo.Repository,
+	userID uuid.UUID,
+	params *CreateCreditLineParams,
+) (*CreditLine, error) {
+	_, err := repository.GetCreditLineByUserID(ctx, userID)
+	if err == nil {
+		return nil, CreditLineExistsError{userID: userID}
+	}
+
+	if !errors.Is(err, repo.ErrRecordNotFound) {
+		return nil, GetCreditLineByUserIDError{userID: userID}
+	}
+
+	// a concurrent creation for the same user fails on the unique user_id
+	creditLine, err := repository.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: userID,
+		Limit:  params.Limit,
+		Status: CreditLineStatusActive,
+	})
+	if err != nil {
+		return nil, CreateCreditLineError{userID: userID}
+	}
+
+	return recordCreditLineChange(ctx, repository, creditLine, creditLineActionCreate, params.Actor, params.Reason)
+}
+
+func updateCreditLineLimit(
+	ctx context.Context,
+	repository repo.Repository,
+	userID uuid.UUID,
+	params *UpdateCreditLineLimitParams,
+) (*CreditLine, error) {
+	creditLine, err := lockCreditLine(ctx, repository, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if creditLine.Status == CreditLineStatusClosed {
+		return nil, CreditLineClosedError{userID: userID}
+	}
+
+	if params.Limit.Currency() != creditLine.Limit.Currency() {
+		return nil, CurrencyMismatchError{
+			field:    "limit.currency",
+			entity:   "credit line",
+			expected: creditLine.Limit.Currency(),
+			actual:   params.Limit.Currency(),
+		}
+	}
+
+	updatedCreditLine, err := repository.UpdateCreditLineLimit(ctx, &payments.UpdateCreditLineLimitParams{
+		ID:    creditLine.ID,
+		Limit: params.Limit,
+	})
+	if err != nil {
+		return nil, UpdateCreditLineLimitError{userID: userID}
+	}
+
+	return recordCreditLineChange(
+		ctx, repository, updatedCreditLine, creditLineActionUpdateLimit, params.Actor, params.Reason,
+	)
+}
+
+// recordCreditLineChange keeps who left creditLine in its current state and why
+func recordCreditLineChange(
+	ctx context.Context,
+	repository repo.Repository,
+	creditLine *payments.CreditLine,
+	action string,
+	actor string,
+	reason string,
+) (*CreditLine, error) {
+	if _, err := repository.CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+		CreditLineID: creditLine.ID,
+		Action:       action,
+		Actor:        actor,
+		Reason:       reason,
+		Limit:        creditLine.Limit,
+		Status:       creditLine.Status,
+	}); err != nil {
+		return nil, CreateCreditLineChangeError{userID: creditLine.UserID}
+	}
+
+	available, err := availableCredit(ctx, repository, creditLine)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCreditLine(creditLine, available), nil
+}
+
+func checkActorAndReason(actor, reason string) error {
+	if actor == "" {
+		return MissingActorError{}
+	}
+
+	if reason == "" {
+		return MissingReasonError{}
+	}
+
+	return nil
+}
+
+func newCreditLineChange(change *payments.CreditLineChange) CreditLineChange {
+	return CreditLineChange{
+		ID:        change.ID.String(),
+		Action:    change.Action,
+		Actor:     change.Actor,
+		Reason:    change.Reason,
+		Limit:     change.Limit,
+		Status:    change.Status,
+		CreatedAt: change.CreatedAt.Format(common.TimeFormat),
+	}
+}
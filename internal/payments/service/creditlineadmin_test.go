@@ -0,0 +1,588 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCreditLineServiceImp_CreateCreditLine(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		userID       = uuid.Must(uuid.NewV4())
+		creditLineID = uuid.Must(uuid.NewV4())
+		currency     = "usdc"
+		limit        = payments.MustNewMoney(decimal.New(1000, 0), currency)
+
+		params = &CreateCreditLineParams{Limit: limit, Actor: "risk-ops", Reason: "onboarding"}
+
+		creditLine = &payments.CreditLine{
+			ID:     creditLineID,
+			UserID: userID,
+			Limit:  limit,
+			Status: CreditLineStatusActive,
+		}
+		changeParams = &payments.CreateCreditLineChangeParams{
+			CreditLineID: creditLineID,
+			Action:       creditLineActionCreate,
+			Actor:        "risk-ops",
+			Reason:       "onboarding",
+			Limit:        limit,
+			Status:       CreditLineStatusActive,
+		}
+	)
+
+	tests := []struct {
+		name    string
+		params  *CreateCreditLineParams
+		prepare func(rm *repomock.MockRepository)
+		want    *CreditLine
+		wantErr error
+	}{
+		{
+			name:   "happy path",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+						UserID: userID,
+						Limit:  limit,
+						Status: CreditLineStatusActive,
+					}).Return(creditLine, nil),
+					rm.EXPECT().CreateCreditLineChange(ctx, changeParams).Return(&payments.CreditLineChange{}, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Any()).
+						Return(payments.MustNewMoney(decimal.New(0, 0), currency), nil),
+				)
+			},
+			want: &CreditLine{
+				ID:              creditLineID.String(),
+				UserID:          userID.String(),
+				TotalAmount:     limit,
+				AvailableAmount: limit,
+				Status:          CreditLineStatusActive,
+			},
+		},
+		{
+			name:    "invalid limit",
+			params:  &CreateCreditLineParams{Limit: payments.MustNewMoney(decimal.New(0, 0), currency), Actor: "a", Reason: "r"},
+			wantErr: InvalidAmountError{field: "limit.value", value: "0"},
+		},
+		{
+			name:    "missing actor",
+			params:  &CreateCreditLineParams{Limit: limit, Reason: "onboarding"},
+			wantErr: MissingActorError{},
+		},
+		{
+			name:    "missing reason",
+			params:  &CreateCreditLineParams{Limit: limit, Actor: "risk-ops"},
+			wantErr: MissingReasonError{},
+		},
+		{
+			name:   "credit line exists",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+				)
+			},
+			wantErr: CreditLineExistsError{userID: userID},
+		},
+		{
+			name:   "GetCreditLineByUserID error",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: GetCreditLineByUserIDError{userID: userID},
+		},
+		{
+			name:   "CreateCreditLine error",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateCreditLine(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateCreditLineError{userID: userID},
+		},
+		{
+			name:   "CreateCreditLineChange error",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateCreditLine(ctx, gomock.Any()).Return(creditLine, nil),
+					rm.EXPECT().CreateCreditLineChange(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateCreditLineChangeError{userID: userID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(rm)
+			}
+
+			c := NewCreditLineService()
+			c.UseRepo(rm)
+
+			got, err := c.CreateCreditLine(ctx, userID, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreditLineServiceImp.CreateCreditLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreditLineServiceImp.CreateCreditLine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreditLineServiceImp_UpdateCreditLineLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		userID       = uuid.Must(uuid.NewV4())
+		creditLineID = uuid.Must(uuid.NewV4())
+		currency     = "usdc"
+		lowered      = payments.MustNewMoney(decimal.New(500, 0), currency)
+
+		params = &UpdateCreditLineLimitParams{Limit: lowered, Actor: "risk-ops", Reason: "missed payments"}
+
+		creditLine = &payments.CreditLine{
+			ID:     creditLineID,
+			UserID: userID,
+			Limit:  payments.MustNewMoney(decimal.New(1000, 0), currency),
+			Status: CreditLineStatusFrozen,
+		}
+	)
+
+	loweredLine := *creditLine
+	loweredLine.Limit = lowered
+
+	closedLine := *creditLine
+	closedLine.Status = CreditLineStatusClosed
+
+	tests := []struct {
+		name    string
+		params  *UpdateCreditLineLimitParams
+		prepare func(rm *repomock.MockRepository)
+		want    *CreditLine
+		wantErr error
+	}{
+		{
+			name:   "happy path, lowered below what is owed",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().UpdateCreditLineLimit(ctx, &payments.UpdateCreditLineLimitParams{
+						ID:    creditLineID,
+						Limit: lowered,
+					}).Return(&loweredLine, nil),
+					rm.EXPECT().CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+						CreditLineID: creditLineID,
+						Action:       creditLineActionUpdateLimit,
+						Actor:        "risk-ops",
+						Reason:       "missed payments",
+						Limit:        lowered,
+						Status:       CreditLineStatusFrozen,
+					}).Return(&payments.CreditLineChange{}, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Any()).
+						Return(payments.MustNewMoney(decimal.New(700, 0), currency), nil),
+				)
+			},
+			want: &CreditLine{
+				ID:              creditLineID.String(),
+				UserID:          userID.String(),
+				TotalAmount:     lowered,
+				AvailableAmount: payments.MustNewMoney(decimal.New(0, 0), currency),
+				Status:          CreditLineStatusFrozen,
+			},
+		},
+		{
+			name:    "missing actor",
+			params:  &UpdateCreditLineLimitParams{Limit: lowered, Reason: "missed payments"},
+			wantErr: MissingActorError{},
+		},
+		{
+			name:   "credit line not found",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			wantErr: CreditLineNotFoundError{userID: userID},
+		},
+		{
+			name:   "credit line closed",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(&closedLine, nil),
+				)
+			},
+			wantErr: CreditLineClosedError{userID: userID},
+		},
+		{
+			name: "currency mismatch",
+			params: &UpdateCreditLineLimitParams{
+				Limit:  payments.MustNewMoney(decimal.New(500, 0), "usdt"),
+				Actor:  "risk-ops",
+				Reason: "missed payments",
+			},
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+				)
+			},
+			wantErr: CurrencyMismatchError{
+				field:    "limit.currency",
+				entity:   "credit line",
+				expected: currency,
+				actual:   "usdt",
+			},
+		},
+		{
+			name:   "UpdateCreditLineLimit error",
+			params: params,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().UpdateCreditLineLimit(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateCreditLineLimitError{userID: userID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(rm)
+			}
+
+			c := NewCreditLineService()
+			c.UseRepo(rm)
+
+			got, err := c.UpdateCreditLineLimit(ctx, userID, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreditLineServiceImp.UpdateCreditLineLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreditLineServiceImp.UpdateCreditLineLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreditLineServiceImp_ChangeCreditLineStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		userID       = uuid.Must(uuid.NewV4())
+		creditLineID = uuid.Must(uuid.NewV4())
+		currency     = "usdc"
+		limit        = payments.MustNewMoney(decimal.New(1000, 0), currency)
+		change       = &CreditLineChangeParams{Actor: "risk-ops", Reason: "fraud review"}
+	)
+
+	lineIn := func(status string) *payments.CreditLine {
+		return &payments.CreditLine{ID: creditLineID, UserID: userID, Limit: limit, Status: status}
+	}
+
+	freeze := func(c *CreditLineServiceImp) (*CreditLine, error) {
+		return c.FreezeCreditLine(ctx, userID, change)
+	}
+	unfreeze := func(c *CreditLineServiceImp) (*CreditLine, error) {
+		return c.UnfreezeCreditLine(ctx, userID, change)
+	}
+	closeLine := func(c *CreditLineServiceImp) (*CreditLine, error) {
+		return c.CloseCreditLine(ctx, userID, change)
+	}
+
+	changedTo := func(from, to, action string) func(rm *repomock.MockRepository) {
+		return func(rm *repomock.MockRepository) {
+			gomock.InOrder(
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+				rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(lineIn(from), nil),
+				rm.EXPECT().UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+					ID:     creditLineID,
+					Status: to,
+				}).Return(lineIn(to), nil),
+				rm.EXPECT().CreateCreditLineChange(ctx, &payments.CreateCreditLineChangeParams{
+					CreditLineID: creditLineID,
+					Action:       action,
+					Actor:        "risk-ops",
+					Reason:       "fraud review",
+					Limit:        limit,
+					Status:       to,
+				}).Return(&payments.CreditLineChange{}, nil),
+				rm.EXPECT().GetUserOutstandingAmount(ctx, gomock.Any()).
+					Return(payments.MustNewMoney(decimal.New(0, 0), currency), nil),
+			)
+		}
+	}
+
+	wantLine := func(status string) *CreditLine {
+		return &CreditLine{
+			ID:              creditLineID.String(),
+			UserID:          userID.String(),
+			TotalAmount:     limit,
+			AvailableAmount: limit,
+			Status:          status,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		call    func(c *CreditLineServiceImp) (*CreditLine, error)
+		prepare func(rm *repomock.MockRepository)
+		want    *CreditLine
+		wantErr error
+	}{
+		{
+			name:    "freeze",
+			call:    freeze,
+			prepare: changedTo(CreditLineStatusActive, CreditLineStatusFrozen, creditLineActionFreeze),
+			want:    wantLine(CreditLineStatusFrozen),
+		},
+		{
+			name:    "unfreeze",
+			call:    unfreeze,
+			prepare: changedTo(CreditLineStatusFrozen, CreditLineStatusActive, creditLineActionUnfreeze),
+			want:    wantLine(CreditLineStatusActive),
+		},
+		{
+			name:    "close",
+			call:    closeLine,
+			prepare: changedTo(CreditLineStatusFrozen, CreditLineStatusClosed, creditLineActionClose),
+			want:    wantLine(CreditLineStatusClosed),
+		},
+		{
+			name: "missing reason",
+			call: func(c *CreditLineServiceImp) (*CreditLine, error) {
+				return c.FreezeCreditLine(ctx, userID, &CreditLineChangeParams{Actor: "risk-ops"})
+			},
+			wantErr: MissingReasonError{},
+		},
+		{
+			name: "unfreeze a closed line",
+			call: unfreeze,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(lineIn(CreditLineStatusClosed), nil),
+				)
+			},
+			wantErr: InvalidStateTransitionError{
+				entity: "credit line",
+				id:     creditLineID,
+				from:   CreditLineStatusClosed,
+				to:     CreditLineStatusActive,
+			},
+		},
+		{
+			name: "LockCreditLineByUserID error",
+			call: freeze,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: LockCreditLineError{userID: userID},
+		},
+		{
+			name: "UpdateCreditLineStatus error",
+			call: freeze,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(lineIn(CreditLineStatusActive), nil),
+					rm.EXPECT().UpdateCreditLineStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateCreditLineStatusError{userID: userID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(rm)
+			}
+
+			c := NewCreditLineService()
+			c.UseRepo(rm)
+
+			got, err := tt.call(c)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("returned %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreditLineServiceImp_ListCreditLineChanges(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx          = context.Background()
+		userID       = uuid.Must(uuid.NewV4())
+		creditLineID = uuid.Must(uuid.NewV4())
+		changeID     = uuid.Must(uuid.NewV4())
+		limit        = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		createdAt    = time.Date(2022, 10, 18, 20, 0, 0, 0, time.UTC)
+
+		creditLine = &payments.CreditLine{ID: creditLineID, UserID: userID, Limit: limit, Status: "frozen"}
+	)
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    []CreditLineChange
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().ListCreditLineChangesByCreditLineID(ctx, creditLineID).Return(
+						[]*payments.CreditLineChange{
+							{
+								ID:           changeID,
+								CreditLineID: creditLineID,
+								Action:       "freeze",
+								Actor:        "risk-ops",
+								Reason:       "fraud review",
+								Limit:        limit,
+								Status:       "frozen",
+								CreatedAt:    createdAt,
+							},
+						}, nil),
+				)
+			},
+			want: []CreditLineChange{
+				{
+					ID:        changeID.String(),
+					Action:    "freeze",
+					Actor:     "risk-ops",
+					Reason:    "fraud review",
+					Limit:     limit,
+					Status:    "frozen",
+					CreatedAt: "2022-10-18T20:00:00Z",
+				},
+			},
+		},
+		{
+			name: "CreditLineNotFound error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: CreditLineNotFoundError{userID: userID},
+		},
+		{
+			name: "ListCreditLineChangesByCreditLineID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetCreditLineByUserID(ctx, userID).Return(creditLine, nil),
+					rm.EXPECT().ListCreditLineChangesByCreditLineID(ctx, creditLineID).
+						Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListCreditLineChangesError{userID: userID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			c := NewCreditLineService()
+			c.UseRepo(rm)
+
+			got, err := c.ListCreditLineChanges(ctx, userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreditLineServiceImp.ListCreditLineChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreditLineServiceImp.ListCreditLineChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
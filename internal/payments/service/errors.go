@@ -1,17 +1,138 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"golangreferenceapi/internal/payments/common"
 
 	"github.com/gofrs/uuid"
 )
 
+// FieldError is a validation rule broken by one field of a request
+type FieldError interface {
+	error
+	Field() string
+}
+
+// ValidationErrors is every validation rule a request broke, in the order they were checked
+type ValidationErrors []error
+
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+	for _, err := range ve {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether one of the broken rules is target
+func (ve ValidationErrors) Is(target error) bool {
+	for _, err := range ve {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first broken rule matching target
+func (ve ValidationErrors) As(target interface{}) bool {
+	for _, err := range ve {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fields maps every field of the request a broken rule is about to its message,
+// the messages of several rules broken on one field are joined
+func (ve ValidationErrors) Fields() map[string]string {
+	fields := make(map[string]string)
+
+	for _, err := range ve {
+		var fieldErr FieldError
+		if !errors.As(err, &fieldErr) {
+			continue
+		}
+
+		if message, found := fields[fieldErr.Field()]; found {
+			fields[fieldErr.Field()] = message + "; " + err.Error()
+
+			continue
+		}
+
+		fields[fieldErr.Field()] = err.Error()
+	}
+
+	return fields
+}
+
+// orNil is nil when no rule was broken
+func (ve ValidationErrors) orNil() error {
+	if len(ve) == 0 {
+		return nil
+	}
+
+	return ve
+}
+
+// joinValidationErrors flattens the validation errors of several checks, nil when none of them failed
+func joinValidationErrors(errs ...error) error {
+	var joined ValidationErrors
+
+	for _, err := range errs {
+		var violations ValidationErrors
+
+		switch {
+		case err == nil:
+		case errors.As(err, &violations):
+			joined = append(joined, violations...)
+		default:
+			joined = append(joined, err)
+		}
+	}
+
+	return joined.orNil()
+}
+
 type CreatePaymentPlanError struct{}
 
 func (cp CreatePaymentPlanError) Error() string {
 	return "failed to create payment plan"
 }
 
+type GetPaymentPlanByIDError struct {
+	planID uuid.UUID
+}
+
+func (gp GetPaymentPlanByIDError) Error() string {
+	return fmt.Sprintf("failed to get payment plan: %v", gp.planID)
+}
+
+type PaymentPlanConflictError struct {
+	planID uuid.UUID
+}
+
+func (pc PaymentPlanConflictError) Error() string {
+	return fmt.Sprintf("payment plan %v already exists with a different payload", pc.planID)
+}
+
+// PaymentPlanIDTakenError a concurrent request created a plan with the same ID first
+type PaymentPlanIDTakenError struct {
+	planID uuid.UUID
+}
+
+func (pt PaymentPlanIDTakenError) Error() string {
+	return fmt.Sprintf("payment plan id already taken: %v", pt.planID)
+}
+
 type ListPaymentPlansByUserIDError struct {
 	userID uuid.UUID
 }
@@ -41,3 +162,935 @@ type PaymentRecordNotFoundError struct {
 func (pr PaymentRecordNotFoundError) Error() string {
 	return fmt.Sprintf("failed to get payment plan: %v", pr.planID)
 }
+
+type UpdatePaymentPlanStatusError struct {
+	planID uuid.UUID
+}
+
+func (up UpdatePaymentPlanStatusError) Error() string {
+	return fmt.Sprintf("failed to update payment plan status: %v", up.planID)
+}
+
+type UpdatePaymentInstallmentStatusError struct {
+	installmentID uuid.UUID
+}
+
+func (ui UpdatePaymentInstallmentStatusError) Error() string {
+	return fmt.Sprintf("failed to update payment installment status: %v", ui.installmentID)
+}
+
+type UpdatePaymentInstallmentAmountError struct {
+	installmentID uuid.UUID
+}
+
+func (ui UpdatePaymentInstallmentAmountError) Error() string {
+	return fmt.Sprintf("failed to update payment installment amount: %v", ui.installmentID)
+}
+
+type InvalidAmountError struct {
+	field string
+	value string
+}
+
+func (ia InvalidAmountError) Error() string {
+	return fmt.Sprintf("%s: invalid amount %q, a positive decimal is expected", ia.field, ia.value)
+}
+
+func (ia InvalidAmountError) Field() string {
+	return ia.field
+}
+
+type MissingInstallmentsError struct{}
+
+func (mi MissingInstallmentsError) Error() string {
+	return "installments: at least one installment is expected"
+}
+
+func (mi MissingInstallmentsError) Field() string {
+	return "installments"
+}
+
+type InvalidScheduleError struct {
+	field    string
+	value    string
+	expected string
+}
+
+func (is InvalidScheduleError) Error() string {
+	return fmt.Sprintf("%s: invalid value %q, %s is expected", is.field, is.value, is.expected)
+}
+
+func (is InvalidScheduleError) Field() string {
+	return is.field
+}
+
+type ScheduleConflictError struct{}
+
+func (sc ScheduleConflictError) Error() string {
+	return "schedule: installments cannot be listed when a schedule is given"
+}
+
+func (sc ScheduleConflictError) Field() string {
+	return "schedule"
+}
+
+// CurrencyMismatchError entity is the payment plan unless another one is given
+type CurrencyMismatchError struct {
+	field    string
+	entity   string
+	expected string
+	actual   string
+}
+
+func (cm CurrencyMismatchError) Error() string {
+	entity := cm.entity
+	if entity == "" {
+		entity = "payment plan"
+	}
+
+	return fmt.Sprintf("%s: currency %q does not match the %s currency %q", cm.field, cm.actual, entity, cm.expected)
+}
+
+func (cm CurrencyMismatchError) Field() string {
+	return cm.field
+}
+
+type PastDueDateError struct {
+	field string
+	dueAt time.Time
+}
+
+func (pd PastDueDateError) Error() string {
+	return fmt.Sprintf("%s: due date %s is in the past", pd.field, pd.dueAt.Format(common.TimeFormat))
+}
+
+func (pd PastDueDateError) Field() string {
+	return pd.field
+}
+
+type InstallmentSumMismatchError struct {
+	totalAmount     string
+	installmentsSum string
+}
+
+func (is InstallmentSumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"installments: sum of amounts %s does not match the total amount %s",
+		is.installmentsSum, is.totalAmount,
+	)
+}
+
+func (is InstallmentSumMismatchError) Field() string {
+	return "installments"
+}
+
+type InstallmentNotFoundError struct {
+	planID        uuid.UUID
+	installmentID uuid.UUID
+}
+
+func (in InstallmentNotFoundError) Error() string {
+	return fmt.Sprintf("payment installment %v not found in payment plan %v", in.installmentID, in.planID)
+}
+
+type LockPaymentInstallmentError struct {
+	installmentID uuid.UUID
+}
+
+func (lp LockPaymentInstallmentError) Error() string {
+	return fmt.Sprintf("failed to lock payment installment: %v", lp.installmentID)
+}
+
+type InstallmentAlreadyPaidError struct {
+	installmentID uuid.UUID
+}
+
+func (ia InstallmentAlreadyPaidError) Error() string {
+	return fmt.Sprintf("payment installment %v is already paid", ia.installmentID)
+}
+
+type MissingProcessorReferenceError struct{}
+
+func (mp MissingProcessorReferenceError) Error() string {
+	return "processor_reference: a payment processor reference is expected"
+}
+
+type OverpaymentError struct {
+	installmentID uuid.UUID
+	amount        string
+	outstanding   string
+}
+
+func (op OverpaymentError) Error() string {
+	return fmt.Sprintf(
+		"amount: %s exceeds the outstanding amount %s of payment installment %v",
+		op.amount, op.outstanding, op.installmentID,
+	)
+}
+
+type ListPaymentTransactionsByInstallmentIDError struct {
+	installmentID uuid.UUID
+}
+
+func (lp ListPaymentTransactionsByInstallmentIDError) Error() string {
+	return fmt.Sprintf("failed to get payment transactions for installment: %v", lp.installmentID)
+}
+
+type CreatePaymentTransactionError struct {
+	installmentID uuid.UUID
+}
+
+func (cp CreatePaymentTransactionError) Error() string {
+	return fmt.Sprintf("failed to create payment transaction for installment: %v", cp.installmentID)
+}
+
+type UpdateInstallmentsPastDueError struct {
+	status string
+}
+
+func (ui UpdateInstallmentsPastDueError) Error() string {
+	return fmt.Sprintf("failed to move installments past due to %s", ui.status)
+}
+
+type LockPaymentPlansPastDueError struct{}
+
+func (lp LockPaymentPlansPastDueError) Error() string {
+	return "failed to lock the payment plans with installments past due"
+}
+
+type InvalidLateFeeRuleError struct {
+	field string
+	value string
+}
+
+func (il InvalidLateFeeRuleError) Error() string {
+	return fmt.Sprintf("late fee rule %s: invalid value %q", il.field, il.value)
+}
+
+type ListPaymentInstallmentsByStatusError struct {
+	status string
+}
+
+func (lp ListPaymentInstallmentsByStatusError) Error() string {
+	return fmt.Sprintf("failed to get %s payment installments", lp.status)
+}
+
+type ListPaymentLateFeesByInstallmentIDError struct {
+	installmentID uuid.UUID
+}
+
+func (lp ListPaymentLateFeesByInstallmentIDError) Error() string {
+	return fmt.Sprintf("failed to get payment late fees for installment: %v", lp.installmentID)
+}
+
+type ListPaymentLateFeesByPlanIDError struct {
+	planID uuid.UUID
+}
+
+func (lp ListPaymentLateFeesByPlanIDError) Error() string {
+	return fmt.Sprintf("failed to get payment late fees for plan: %v", lp.planID)
+}
+
+type CreatePaymentLateFeeError struct {
+	installmentID uuid.UUID
+}
+
+func (cp CreatePaymentLateFeeError) Error() string {
+	return fmt.Sprintf("failed to create payment late fee for installment: %v", cp.installmentID)
+}
+
+type InstallmentVoidError struct {
+	installmentID uuid.UUID
+}
+
+func (iv InstallmentVoidError) Error() string {
+	return fmt.Sprintf("payment installment %v is void", iv.installmentID)
+}
+
+type LockPaymentPlanError struct {
+	planID uuid.UUID
+}
+
+func (lp LockPaymentPlanError) Error() string {
+	return fmt.Sprintf("failed to lock payment plan: %v", lp.planID)
+}
+
+type RefundExceedsPaidAmountError struct {
+	amount     string
+	refundable string
+}
+
+func (re RefundExceedsPaidAmountError) Error() string {
+	return fmt.Sprintf("amount: %s exceeds the refundable amount %s", re.amount, re.refundable)
+}
+
+type ListPaymentRefundsByPlanIDError struct {
+	planID uuid.UUID
+}
+
+func (lp ListPaymentRefundsByPlanIDError) Error() string {
+	return fmt.Sprintf("failed to get payment refunds for plan: %v", lp.planID)
+}
+
+type CreatePaymentRefundError struct {
+	installmentID uuid.UUID
+}
+
+func (cp CreatePaymentRefundError) Error() string {
+	return fmt.Sprintf("failed to create payment refund for installment: %v", cp.installmentID)
+}
+
+type PaymentPlanNotPayableError struct {
+	planID uuid.UUID
+	status string
+}
+
+func (pn PaymentPlanNotPayableError) Error() string {
+	return fmt.Sprintf("payment plan %v cannot be paid off: %s", pn.planID, pn.status)
+}
+
+type PaymentPlanAlreadyPaidError struct {
+	planID uuid.UUID
+}
+
+func (pa PaymentPlanAlreadyPaidError) Error() string {
+	return fmt.Sprintf("payment plan %v has no unpaid installment", pa.planID)
+}
+
+type PayoffAmountMismatchError struct {
+	amount      string
+	outstanding string
+}
+
+func (pm PayoffAmountMismatchError) Error() string {
+	return fmt.Sprintf("amount: %s does not match the outstanding amount %s", pm.amount, pm.outstanding)
+}
+
+type CreatePaymentSettlementError struct {
+	planID uuid.UUID
+}
+
+func (cs CreatePaymentSettlementError) Error() string {
+	return fmt.Sprintf("failed to create payment settlement for plan: %v", cs.planID)
+}
+
+type InstallmentSupersededError struct {
+	installmentID uuid.UUID
+}
+
+func (is InstallmentSupersededError) Error() string {
+	return fmt.Sprintf("payment installment %v was superseded by a reschedule", is.installmentID)
+}
+
+type PaymentPlanNotReschedulableError struct {
+	planID uuid.UUID
+	status string
+}
+
+func (pn PaymentPlanNotReschedulableError) Error() string {
+	return fmt.Sprintf("payment plan %v cannot be rescheduled: %s", pn.planID, pn.status)
+}
+
+type UnsupportedCurrencyError struct {
+	field string
+	value string
+}
+
+func (uc UnsupportedCurrencyError) Error() string {
+	return fmt.Sprintf("%s: unsupported currency %q", uc.field, uc.value)
+}
+
+func (uc UnsupportedCurrencyError) Field() string {
+	return uc.field
+}
+
+type AmountPrecisionError struct {
+	field      string
+	value      string
+	currency   string
+	minorUnits int
+}
+
+func (ap AmountPrecisionError) Error() string {
+	return fmt.Sprintf("%s: amount %q has more than the %d decimal places of %s",
+		ap.field, ap.value, ap.minorUnits, ap.currency)
+}
+
+func (ap AmountPrecisionError) Field() string {
+	return ap.field
+}
+
+type InvalidStateTransitionError struct {
+	entity string
+	id     uuid.UUID
+	from   string
+	to     string
+}
+
+func (is InvalidStateTransitionError) Error() string {
+	return fmt.Sprintf("%s %v cannot go from %s to %s", is.entity, is.id, is.from, is.to)
+}
+
+type CreditLineNotFoundError struct {
+	userID uuid.UUID
+}
+
+func (cn CreditLineNotFoundError) Error() string {
+	return fmt.Sprintf("no credit line for user: %v", cn.userID)
+}
+
+type GetCreditLineByUserIDError struct {
+	userID uuid.UUID
+}
+
+func (gc GetCreditLineByUserIDError) Error() string {
+	return fmt.Sprintf("failed to get credit line for user: %v", gc.userID)
+}
+
+type GetUserOutstandingAmountError struct {
+	userID uuid.UUID
+}
+
+func (gu GetUserOutstandingAmountError) Error() string {
+	return fmt.Sprintf("failed to get outstanding amount for user: %v", gu.userID)
+}
+
+type LockCreditLineError struct {
+	userID uuid.UUID
+}
+
+func (lc LockCreditLineError) Error() string {
+	return fmt.Sprintf("failed to lock credit line for user: %v", lc.userID)
+}
+
+type CreditLineNotActiveError struct {
+	userID uuid.UUID
+	status string
+}
+
+func (cn CreditLineNotActiveError) Error() string {
+	return fmt.Sprintf("credit line of user %v is %s", cn.userID, cn.status)
+}
+
+type InsufficientCreditError struct {
+	userID    uuid.UUID
+	amount    string
+	available string
+	currency  string
+}
+
+func (ic InsufficientCreditError) Error() string {
+	return fmt.Sprintf(
+		"total_amount: %s exceeds the available credit %s %s of user %v",
+		ic.amount, ic.available, ic.currency, ic.userID,
+	)
+}
+
+type ListPaymentPlansByStatusError struct {
+	status string
+}
+
+func (lp ListPaymentPlansByStatusError) Error() string {
+	return fmt.Sprintf("failed to get %s payment plans", lp.status)
+}
+
+type MissingActorError struct{}
+
+func (ma MissingActorError) Error() string {
+	return "actor: who makes the change is expected"
+}
+
+type MissingReasonError struct{}
+
+func (mr MissingReasonError) Error() string {
+	return "reason: why the change is made is expected"
+}
+
+type CreditLineExistsError struct {
+	userID uuid.UUID
+}
+
+func (ce CreditLineExistsError) Error() string {
+	return fmt.Sprintf("user %v already has a credit line", ce.userID)
+}
+
+type CreditLineClosedError struct {
+	userID uuid.UUID
+}
+
+func (cc CreditLineClosedError) Error() string {
+	return fmt.Sprintf("credit line of user %v is closed", cc.userID)
+}
+
+type CreateCreditLineError struct {
+	userID uuid.UUID
+}
+
+func (cc CreateCreditLineError) Error() string {
+	return fmt.Sprintf("failed to create credit line for user: %v", cc.userID)
+}
+
+type UpdateCreditLineLimitError struct {
+	userID uuid.UUID
+}
+
+func (uc UpdateCreditLineLimitError) Error() string {
+	return fmt.Sprintf("failed to update credit line limit for user: %v", uc.userID)
+}
+
+type UpdateCreditLineStatusError struct {
+	userID uuid.UUID
+}
+
+func (uc UpdateCreditLineStatusError) Error() string {
+	return fmt.Sprintf("failed to update credit line status for user: %v", uc.userID)
+}
+
+type CreateCreditLineChangeError struct {
+	userID uuid.UUID
+}
+
+func (cc CreateCreditLineChangeError) Error() string {
+	return fmt.Sprintf("failed to record credit line change for user: %v", cc.userID)
+}
+
+type ListCreditLineChangesError struct {
+	userID uuid.UUID
+}
+
+func (lc ListCreditLineChangesError) Error() string {
+	return fmt.Sprintf("failed to get credit line changes for user: %v", lc.userID)
+}
+
+type InvalidJournalEntryError struct {
+	planID uuid.UUID
+	kind   string
+}
+
+func (ij InvalidJournalEntryError) Error() string {
+	return fmt.Sprintf("journal entry %s of plan %v does not balance", ij.kind, ij.planID)
+}
+
+type CreateJournalEntryError struct {
+	planID uuid.UUID
+	kind   string
+}
+
+func (cj CreateJournalEntryError) Error() string {
+	return fmt.Sprintf("failed to create journal entry %s for plan: %v", cj.kind, cj.planID)
+}
+
+type ListJournalEntriesByPlanIDError struct {
+	planID uuid.UUID
+}
+
+func (lj ListJournalEntriesByPlanIDError) Error() string {
+	return fmt.Sprintf("failed to get journal entries for plan: %v", lj.planID)
+}
+
+type CreateOutboxEventError struct {
+	planID    uuid.UUID
+	eventType string
+}
+
+func (co CreateOutboxEventError) Error() string {
+	return fmt.Sprintf("failed to create outbox event %s for plan: %v", co.eventType, co.planID)
+}
+
+type ListUnpublishedOutboxEventsError struct{}
+
+func (lu ListUnpublishedOutboxEventsError) Error() string {
+	return "failed to list unpublished outbox events"
+}
+
+type MarkOutboxEventPublishedError struct {
+	eventID uuid.UUID
+}
+
+func (mo MarkOutboxEventPublishedError) Error() string {
+	return fmt.Sprintf("failed to mark outbox event as published: %v", mo.eventID)
+}
+
+type MarkOutboxEventFailedError struct {
+	eventID uuid.UUID
+}
+
+func (mo MarkOutboxEventFailedError) Error() string {
+	return fmt.Sprintf("failed to mark outbox event as failed: %v", mo.eventID)
+}
+
+type InvalidWebhookEndpointError struct {
+	reason string
+}
+
+func (iw InvalidWebhookEndpointError) Error() string {
+	return fmt.Sprintf("invalid webhook endpoint: %s", iw.reason)
+}
+
+type CreateWebhookEndpointError struct {
+	merchantID uuid.UUID
+}
+
+func (cw CreateWebhookEndpointError) Error() string {
+	return fmt.Sprintf("failed to create webhook endpoint for merchant: %v", cw.merchantID)
+}
+
+type ListWebhookEndpointsError struct {
+	merchantID uuid.UUID
+}
+
+func (lw ListWebhookEndpointsError) Error() string {
+	return fmt.Sprintf("failed to get webhook endpoints for merchant: %v", lw.merchantID)
+}
+
+type CreateWebhookDeliveryError struct {
+	endpointID uuid.UUID
+	eventID    uuid.UUID
+}
+
+func (cw CreateWebhookDeliveryError) Error() string {
+	return fmt.Sprintf("failed to create webhook delivery of event %v for endpoint: %v", cw.eventID, cw.endpointID)
+}
+
+type InvalidWebhookDeliveryStatusError struct {
+	status string
+}
+
+func (iw InvalidWebhookDeliveryStatusError) Error() string {
+	return fmt.Sprintf("invalid webhook delivery status: %s", iw.status)
+}
+
+type ListWebhookDeliveriesError struct {
+	status string
+}
+
+func (lw ListWebhookDeliveriesError) Error() string {
+	return fmt.Sprintf("failed to get %s webhook deliveries", lw.status)
+}
+
+type WebhookDeliveryNotFoundError struct {
+	deliveryID uuid.UUID
+}
+
+func (wn WebhookDeliveryNotFoundError) Error() string {
+	return fmt.Sprintf("webhook delivery not found: %v", wn.deliveryID)
+}
+
+type LockWebhookDeliveryError struct {
+	deliveryID uuid.UUID
+}
+
+func (lw LockWebhookDeliveryError) Error() string {
+	return fmt.Sprintf("failed to lock webhook delivery: %v", lw.deliveryID)
+}
+
+type WebhookDeliveryNotDeadError struct {
+	deliveryID uuid.UUID
+	status     string
+}
+
+func (wn WebhookDeliveryNotDeadError) Error() string {
+	return fmt.Sprintf("webhook delivery %v is %s, only dead deliveries are replayed", wn.deliveryID, wn.status)
+}
+
+type UpdateWebhookDeliveryError struct {
+	deliveryID uuid.UUID
+}
+
+func (uw UpdateWebhookDeliveryError) Error() string {
+	return fmt.Sprintf("failed to update webhook delivery: %v", uw.deliveryID)
+}
+
+type GetWebhookEndpointByIDError struct {
+	endpointID uuid.UUID
+}
+
+func (gw GetWebhookEndpointByIDError) Error() string {
+	return fmt.Sprintf("failed to get webhook endpoint: %v", gw.endpointID)
+}
+
+type ListRemindableInstallmentsError struct {
+	reminder string
+}
+
+func (lr ListRemindableInstallmentsError) Error() string {
+	return fmt.Sprintf("failed to list installments to remind: %v", lr.reminder)
+}
+
+type RenderReminderError struct {
+	installmentID uuid.UUID
+	reminder      string
+}
+
+func (rr RenderReminderError) Error() string {
+	return fmt.Sprintf("failed to render reminder %v of installment: %v", rr.reminder, rr.installmentID)
+}
+
+type CreateReminderSentError struct {
+	installmentID uuid.UUID
+	reminder      string
+}
+
+func (cr CreateReminderSentError) Error() string {
+	return fmt.Sprintf("failed to record reminder %v of installment: %v", cr.reminder, cr.installmentID)
+}
+
+type NotifyReminderError struct {
+	installmentID uuid.UUID
+	reminder      string
+}
+
+func (nr NotifyReminderError) Error() string {
+	return fmt.Sprintf("failed to notify reminder %v of installment: %v", nr.reminder, nr.installmentID)
+}
+
+type ListDelinquentPaymentPlansError struct{}
+
+func (ld ListDelinquentPaymentPlansError) Error() string {
+	return "failed to list delinquent payment plans"
+}
+
+type InvalidCollectionsCaseStatusError struct {
+	status string
+}
+
+func (ic InvalidCollectionsCaseStatusError) Error() string {
+	return fmt.Sprintf("invalid collections case status: %s", ic.status)
+}
+
+type ListCollectionsCasesError struct {
+	status string
+}
+
+func (lc ListCollectionsCasesError) Error() string {
+	return fmt.Sprintf("failed to get %s collections cases", lc.status)
+}
+
+type CollectionsCaseNotFoundError struct {
+	caseID uuid.UUID
+}
+
+func (cn CollectionsCaseNotFoundError) Error() string {
+	return fmt.Sprintf("collections case not found: %v", cn.caseID)
+}
+
+type GetCollectionsCaseError struct {
+	caseID uuid.UUID
+}
+
+func (gc GetCollectionsCaseError) Error() string {
+	return fmt.Sprintf("failed to get collections case: %v", gc.caseID)
+}
+
+type GetActiveCollectionsCaseError struct {
+	planID uuid.UUID
+}
+
+func (ga GetActiveCollectionsCaseError) Error() string {
+	return fmt.Sprintf("failed to get the active collections case of payment plan: %v", ga.planID)
+}
+
+type LockCollectionsCaseError struct {
+	caseID uuid.UUID
+}
+
+func (lc LockCollectionsCaseError) Error() string {
+	return fmt.Sprintf("failed to lock collections case: %v", lc.caseID)
+}
+
+type CreateCollectionsCaseError struct {
+	planID uuid.UUID
+}
+
+func (cc CreateCollectionsCaseError) Error() string {
+	return fmt.Sprintf("failed to create collections case for payment plan: %v", cc.planID)
+}
+
+type UpdateCollectionsCaseError struct {
+	caseID uuid.UUID
+}
+
+func (uc UpdateCollectionsCaseError) Error() string {
+	return fmt.Sprintf("failed to update collections case: %v", uc.caseID)
+}
+
+type CreateCollectionsCaseEventError struct {
+	caseID uuid.UUID
+}
+
+func (cc CreateCollectionsCaseEventError) Error() string {
+	return fmt.Sprintf("failed to record the history of collections case: %v", cc.caseID)
+}
+
+type ListCollectionsCaseEventsError struct {
+	caseID uuid.UUID
+}
+
+func (lc ListCollectionsCaseEventsError) Error() string {
+	return fmt.Sprintf("failed to get the history of collections case: %v", lc.caseID)
+}
+
+type RenderCollectionsNoticeError struct {
+	caseID uuid.UUID
+	stage  string
+}
+
+func (rc RenderCollectionsNoticeError) Error() string {
+	return fmt.Sprintf("failed to render notice %v of collections case: %v", rc.stage, rc.caseID)
+}
+
+type NotifyCollectionsCaseError struct {
+	caseID uuid.UUID
+	stage  string
+}
+
+func (nc NotifyCollectionsCaseError) Error() string {
+	return fmt.Sprintf("failed to send notice %v of collections case: %v", nc.stage, nc.caseID)
+}
+
+type MissingMerchantError struct{}
+
+func (mm MissingMerchantError) Error() string {
+	return "merchant_id: a merchant is expected"
+}
+
+type MerchantNotFoundError struct {
+	merchantID uuid.UUID
+}
+
+func (mn MerchantNotFoundError) Error() string {
+	return fmt.Sprintf("merchant not found: %v", mn.merchantID)
+}
+
+type GetMerchantError struct {
+	merchantID uuid.UUID
+}
+
+func (gm GetMerchantError) Error() string {
+	return fmt.Sprintf("failed to get merchant: %v", gm.merchantID)
+}
+
+type MissingMerchantNameError struct{}
+
+func (mm MissingMerchantNameError) Error() string {
+	return "name: a merchant name is expected"
+}
+
+type CreateMerchantError struct{}
+
+func (cm CreateMerchantError) Error() string {
+	return "failed to create merchant"
+}
+
+type ListMerchantPaymentPlansError struct {
+	merchantID uuid.UUID
+}
+
+func (lm ListMerchantPaymentPlansError) Error() string {
+	return fmt.Sprintf("failed to get payment plans of merchant: %v", lm.merchantID)
+}
+
+type InvalidDateRangeError struct {
+	from time.Time
+	to   time.Time
+}
+
+func (id InvalidDateRangeError) Error() string {
+	return fmt.Sprintf(
+		"invalid date range: from %s is not before to %s",
+		id.from.Format(common.TimeFormat),
+		id.to.Format(common.TimeFormat),
+	)
+}
+
+type GetMerchantTotalsError struct {
+	merchantID uuid.UUID
+}
+
+func (gm GetMerchantTotalsError) Error() string {
+	return fmt.Sprintf("failed to get totals of merchant: %v", gm.merchantID)
+}
+
+type InvalidOrderError struct {
+	field  string
+	reason string
+}
+
+func (io InvalidOrderError) Error() string {
+	return fmt.Sprintf("%s: %s", io.field, io.reason)
+}
+
+func (io InvalidOrderError) Field() string {
+	return io.field
+}
+
+type OrderReferenceConflictError struct {
+	merchantID     uuid.UUID
+	orderReference string
+}
+
+func (oc OrderReferenceConflictError) Error() string {
+	return fmt.Sprintf(
+		"order %q of merchant %v is already paid by another payment plan",
+		oc.orderReference, oc.merchantID,
+	)
+}
+
+type OrderNotFoundError struct {
+	merchantID     uuid.UUID
+	orderReference string
+}
+
+func (on OrderNotFoundError) Error() string {
+	return fmt.Sprintf("no payment plan found for order %q of merchant %v", on.orderReference, on.merchantID)
+}
+
+type GetPaymentPlanByOrderReferenceError struct {
+	merchantID     uuid.UUID
+	orderReference string
+}
+
+func (gp GetPaymentPlanByOrderReferenceError) Error() string {
+	return fmt.Sprintf("failed to get payment plan of order %q of merchant: %v", gp.orderReference, gp.merchantID)
+}
+
+type CreatePaymentPlanLineItemError struct {
+	planID uuid.UUID
+}
+
+func (cp CreatePaymentPlanLineItemError) Error() string {
+	return fmt.Sprintf("failed to create line item of payment plan: %v", cp.planID)
+}
+
+type ListPaymentPlanLineItemsByPlanIDError struct {
+	planID uuid.UUID
+}
+
+func (lp ListPaymentPlanLineItemsByPlanIDError) Error() string {
+	return fmt.Sprintf("failed to get line items of payment plan: %v", lp.planID)
+}
+
+type GetLatestAuditEntryError struct {
+	planID uuid.UUID
+}
+
+func (gl GetLatestAuditEntryError) Error() string {
+	return fmt.Sprintf("failed to get latest audit entry of payment plan: %v", gl.planID)
+}
+
+type CreateAuditEntryError struct {
+	planID uuid.UUID
+	action string
+}
+
+func (ca CreateAuditEntryError) Error() string {
+	return fmt.Sprintf("failed to create audit entry %s for payment plan: %v", ca.action, ca.planID)
+}
+
+type ListAuditEntriesByPlanIDError struct {
+	planID uuid.UUID
+}
+
+func (la ListAuditEntriesByPlanIDError) Error() string {
+	return fmt.Sprintf("failed to get audit entries of payment plan: %v", la.planID)
+}
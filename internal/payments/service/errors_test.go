@@ -2,7 +2,9 @@ package service
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
@@ -147,3 +149,3038 @@ func TestPaymentRecordNotFoundError(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePaymentPlanStatusError(t *testing.T) {
+	t.Parallel()
+
+	planID := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdatePaymentPlanStatusError{planID: planID},
+			expectedString: fmt.Sprintf("failed to update payment plan status: %v", planID),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdatePaymentInstallmentStatusError(t *testing.T) {
+	t.Parallel()
+
+	installmentID := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdatePaymentInstallmentStatusError{installmentID: installmentID},
+			expectedString: fmt.Sprintf("failed to update payment installment status: %v", installmentID),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdatePaymentInstallmentAmountError(t *testing.T) {
+	t.Parallel()
+
+	installmentID := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdatePaymentInstallmentAmountError{installmentID: installmentID},
+			expectedString: fmt.Sprintf("failed to update payment installment amount: %v", installmentID),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidAmountError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidAmountError{field: "total_amount", value: "x"},
+			expectedString: `total_amount: invalid amount "x", a positive decimal is expected`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMissingInstallmentsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MissingInstallmentsError{},
+			expectedString: "installments: at least one installment is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCurrencyMismatchError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CurrencyMismatchError{field: "installments[0].currency", expected: "usdc", actual: "usdt"},
+			expectedString: `installments[0].currency: currency "usdt" does not match the payment plan currency "usdc"`,
+		},
+		{
+			name:           "credit line",
+			err:            CurrencyMismatchError{field: "limit.currency", entity: "credit line", expected: "usdc", actual: "usdt"},
+			expectedString: `limit.currency: currency "usdt" does not match the credit line currency "usdc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPastDueDateError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PastDueDateError{field: "installments[0].due_at", dueAt: time.Date(2021, 10, 10, 23, 0, 0, 0, time.UTC)},
+			expectedString: "installments[0].due_at: due date 2021-10-10T23:00:00Z is in the past",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInstallmentSumMismatchError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InstallmentSumMismatchError{totalAmount: "100", installmentsSum: "99"},
+			expectedString: "installments: sum of amounts 99 does not match the total amount 100",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetPaymentPlanByIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetPaymentPlanByIDError{planID: uuid.Nil},
+			expectedString: "failed to get payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPaymentPlanConflictError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PaymentPlanConflictError{planID: uuid.Nil},
+			expectedString: "payment plan 00000000-0000-0000-0000-000000000000 already exists with a different payload",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPaymentPlanIDTakenError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PaymentPlanIDTakenError{planID: uuid.Nil},
+			expectedString: "payment plan id already taken: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInstallmentNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InstallmentNotFoundError{},
+			expectedString: "payment installment 00000000-0000-0000-0000-000000000000 not found in payment plan 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestLockPaymentInstallmentError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            LockPaymentInstallmentError{},
+			expectedString: "failed to lock payment installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInstallmentAlreadyPaidError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InstallmentAlreadyPaidError{},
+			expectedString: "payment installment 00000000-0000-0000-0000-000000000000 is already paid",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMissingProcessorReferenceError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MissingProcessorReferenceError{},
+			expectedString: "processor_reference: a payment processor reference is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestOverpaymentError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            OverpaymentError{amount: "10", outstanding: "5"},
+			expectedString: "amount: 10 exceeds the outstanding amount 5 of payment installment 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentTransactionsByInstallmentIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentTransactionsByInstallmentIDError{},
+			expectedString: "failed to get payment transactions for installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreatePaymentTransactionError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreatePaymentTransactionError{},
+			expectedString: "failed to create payment transaction for installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdateInstallmentsPastDueError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdateInstallmentsPastDueError{status: "overdue"},
+			expectedString: "failed to move installments past due to overdue",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestLockPaymentPlansPastDueError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            LockPaymentPlansPastDueError{},
+			expectedString: "failed to lock the payment plans with installments past due",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidLateFeeRuleError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidLateFeeRuleError{field: "kind", value: "flat"},
+			expectedString: `late fee rule kind: invalid value "flat"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentInstallmentsByStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentInstallmentsByStatusError{status: "overdue"},
+			expectedString: "failed to get overdue payment installments",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentLateFeesByInstallmentIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentLateFeesByInstallmentIDError{},
+			expectedString: "failed to get payment late fees for installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentLateFeesByPlanIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentLateFeesByPlanIDError{},
+			expectedString: "failed to get payment late fees for plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreatePaymentLateFeeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreatePaymentLateFeeError{},
+			expectedString: "failed to create payment late fee for installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInstallmentVoidError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InstallmentVoidError{},
+			expectedString: "payment installment 00000000-0000-0000-0000-000000000000 is void",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestLockPaymentPlanError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            LockPaymentPlanError{},
+			expectedString: "failed to lock payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestRefundExceedsPaidAmountError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            RefundExceedsPaidAmountError{amount: "10", refundable: "5"},
+			expectedString: "amount: 10 exceeds the refundable amount 5",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentRefundsByPlanIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentRefundsByPlanIDError{},
+			expectedString: "failed to get payment refunds for plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreatePaymentRefundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreatePaymentRefundError{},
+			expectedString: "failed to create payment refund for installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPaymentPlanNotPayableError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PaymentPlanNotPayableError{status: paymentPlanStatusCancelled},
+			expectedString: "payment plan 00000000-0000-0000-0000-000000000000 cannot be paid off: cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPaymentPlanAlreadyPaidError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PaymentPlanAlreadyPaidError{},
+			expectedString: "payment plan 00000000-0000-0000-0000-000000000000 has no unpaid installment",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPayoffAmountMismatchError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PayoffAmountMismatchError{amount: "50", outstanding: "100"},
+			expectedString: "amount: 50 does not match the outstanding amount 100",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreatePaymentSettlementError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreatePaymentSettlementError{},
+			expectedString: "failed to create payment settlement for plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidScheduleError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidScheduleError{field: "schedule.frequency", value: "daily", expected: "weekly"},
+			expectedString: `schedule.frequency: invalid value "daily", weekly is expected`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestScheduleConflictError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ScheduleConflictError{},
+			expectedString: "schedule: installments cannot be listed when a schedule is given",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInstallmentSupersededError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InstallmentSupersededError{installmentID: uuid.Nil},
+			expectedString: "payment installment 00000000-0000-0000-0000-000000000000 was superseded by a reschedule",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestPaymentPlanNotReschedulableError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            PaymentPlanNotReschedulableError{planID: uuid.Nil, status: "pending"},
+			expectedString: "payment plan 00000000-0000-0000-0000-000000000000 cannot be rescheduled: pending",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUnsupportedCurrencyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UnsupportedCurrencyError{field: "currency", value: "btc"},
+			expectedString: `currency: unsupported currency "btc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestAmountPrecisionError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            AmountPrecisionError{field: "amount", value: "1.005", currency: "usd", minorUnits: 2},
+			expectedString: `amount: amount "1.005" has more than the 2 decimal places of usd`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidStateTransitionError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidStateTransitionError{entity: "payment plan", id: uuid.Nil, from: "cancelled", to: "complete"},
+			expectedString: "payment plan 00000000-0000-0000-0000-000000000000 cannot go from cancelled to complete",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreditLineNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreditLineNotFoundError{userID: uuid.Nil},
+			expectedString: "no credit line for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetCreditLineByUserIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetCreditLineByUserIDError{userID: uuid.Nil},
+			expectedString: "failed to get credit line for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetUserOutstandingAmountError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetUserOutstandingAmountError{userID: uuid.Nil},
+			expectedString: "failed to get outstanding amount for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestLockCreditLineError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            LockCreditLineError{},
+			expectedString: "failed to lock credit line for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreditLineNotActiveError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreditLineNotActiveError{status: "frozen"},
+			expectedString: "credit line of user 00000000-0000-0000-0000-000000000000 is frozen",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInsufficientCreditError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InsufficientCreditError{amount: "2000", available: "1500", currency: "usdc"},
+			expectedString: "total_amount: 2000 exceeds the available credit 1500 usdc of user 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentPlansByStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentPlansByStatusError{status: "pending"},
+			expectedString: "failed to get pending payment plans",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMissingActorError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MissingActorError{},
+			expectedString: "actor: who makes the change is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMissingReasonError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MissingReasonError{},
+			expectedString: "reason: why the change is made is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreditLineExistsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreditLineExistsError{},
+			expectedString: "user 00000000-0000-0000-0000-000000000000 already has a credit line",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreditLineClosedError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreditLineClosedError{},
+			expectedString: "credit line of user 00000000-0000-0000-0000-000000000000 is closed",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateCreditLineError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateCreditLineError{},
+			expectedString: "failed to create credit line for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdateCreditLineLimitError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdateCreditLineLimitError{},
+			expectedString: "failed to update credit line limit for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdateCreditLineStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdateCreditLineStatusError{},
+			expectedString: "failed to update credit line status for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateCreditLineChangeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateCreditLineChangeError{},
+			expectedString: "failed to record credit line change for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListCreditLineChangesError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListCreditLineChangesError{},
+			expectedString: "failed to get credit line changes for user: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidJournalEntryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidJournalEntryError{kind: "refunded"},
+			expectedString: "journal entry refunded of plan 00000000-0000-0000-0000-000000000000 does not balance",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateJournalEntryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateJournalEntryError{kind: "refunded"},
+			expectedString: "failed to create journal entry refunded for plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListJournalEntriesByPlanIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListJournalEntriesByPlanIDError{},
+			expectedString: "failed to get journal entries for plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateOutboxEventError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateOutboxEventError{eventType: "plan_created"},
+			expectedString: "failed to create outbox event plan_created for plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListUnpublishedOutboxEventsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListUnpublishedOutboxEventsError{},
+			expectedString: "failed to list unpublished outbox events",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMarkOutboxEventPublishedError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MarkOutboxEventPublishedError{},
+			expectedString: "failed to mark outbox event as published: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMarkOutboxEventFailedError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MarkOutboxEventFailedError{},
+			expectedString: "failed to mark outbox event as failed: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidWebhookEndpointError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidWebhookEndpointError{reason: "url must be https"},
+			expectedString: "invalid webhook endpoint: url must be https",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateWebhookEndpointError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateWebhookEndpointError{},
+			expectedString: "failed to create webhook endpoint for merchant: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListWebhookEndpointsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListWebhookEndpointsError{},
+			expectedString: "failed to get webhook endpoints for merchant: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateWebhookDeliveryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateWebhookDeliveryError{},
+			expectedString: "failed to create webhook delivery of event 00000000-0000-0000-0000-000000000000 for endpoint: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidWebhookDeliveryStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidWebhookDeliveryStatusError{status: "lost"},
+			expectedString: "invalid webhook delivery status: lost",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListWebhookDeliveriesError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListWebhookDeliveriesError{status: "dead"},
+			expectedString: "failed to get dead webhook deliveries",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            WebhookDeliveryNotFoundError{},
+			expectedString: "webhook delivery not found: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestLockWebhookDeliveryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            LockWebhookDeliveryError{},
+			expectedString: "failed to lock webhook delivery: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryNotDeadError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            WebhookDeliveryNotDeadError{status: "pending"},
+			expectedString: "webhook delivery 00000000-0000-0000-0000-000000000000 is pending, only dead deliveries are replayed",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdateWebhookDeliveryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdateWebhookDeliveryError{},
+			expectedString: "failed to update webhook delivery: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetWebhookEndpointByIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetWebhookEndpointByIDError{},
+			expectedString: "failed to get webhook endpoint: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListRemindableInstallmentsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListRemindableInstallmentsError{reminder: "upcoming"},
+			expectedString: "failed to list installments to remind: upcoming",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestRenderReminderError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            RenderReminderError{reminder: "upcoming"},
+			expectedString: "failed to render reminder upcoming of installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateReminderSentError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateReminderSentError{reminder: "upcoming"},
+			expectedString: "failed to record reminder upcoming of installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestNotifyReminderError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            NotifyReminderError{reminder: "upcoming"},
+			expectedString: "failed to notify reminder upcoming of installment: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListDelinquentPaymentPlansError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListDelinquentPaymentPlansError{},
+			expectedString: "failed to list delinquent payment plans",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidCollectionsCaseStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidCollectionsCaseStatusError{status: "done"},
+			expectedString: "invalid collections case status: done",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListCollectionsCasesError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListCollectionsCasesError{status: "open"},
+			expectedString: "failed to get open collections cases",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCollectionsCaseNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CollectionsCaseNotFoundError{},
+			expectedString: "collections case not found: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetCollectionsCaseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetCollectionsCaseError{},
+			expectedString: "failed to get collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetActiveCollectionsCaseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetActiveCollectionsCaseError{},
+			expectedString: "failed to get the active collections case of payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestLockCollectionsCaseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            LockCollectionsCaseError{},
+			expectedString: "failed to lock collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateCollectionsCaseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateCollectionsCaseError{},
+			expectedString: "failed to create collections case for payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestUpdateCollectionsCaseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            UpdateCollectionsCaseError{},
+			expectedString: "failed to update collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateCollectionsCaseEventError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateCollectionsCaseEventError{},
+			expectedString: "failed to record the history of collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListCollectionsCaseEventsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListCollectionsCaseEventsError{},
+			expectedString: "failed to get the history of collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestRenderCollectionsNoticeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            RenderCollectionsNoticeError{stage: "reminder"},
+			expectedString: "failed to render notice reminder of collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestNotifyCollectionsCaseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            NotifyCollectionsCaseError{stage: "reminder"},
+			expectedString: "failed to send notice reminder of collections case: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMissingMerchantError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MissingMerchantError{},
+			expectedString: "merchant_id: a merchant is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMerchantNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MerchantNotFoundError{},
+			expectedString: "merchant not found: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetMerchantError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetMerchantError{},
+			expectedString: "failed to get merchant: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestMissingMerchantNameError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            MissingMerchantNameError{},
+			expectedString: "name: a merchant name is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateMerchantError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateMerchantError{},
+			expectedString: "failed to create merchant",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListMerchantPaymentPlansError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListMerchantPaymentPlansError{},
+			expectedString: "failed to get payment plans of merchant: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidDateRangeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidDateRangeError{},
+			expectedString: "invalid date range: from 0001-01-01T00:00:00Z is not before to 0001-01-01T00:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetMerchantTotalsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetMerchantTotalsError{},
+			expectedString: "failed to get totals of merchant: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestInvalidOrderError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            InvalidOrderError{field: "line_items[0].quantity", reason: "a positive quantity is expected"},
+			expectedString: "line_items[0].quantity: a positive quantity is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestOrderReferenceConflictError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            OrderReferenceConflictError{orderReference: "order-1"},
+			expectedString: `order "order-1" of merchant 00000000-0000-0000-0000-000000000000 is already paid by another payment plan`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestOrderNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            OrderNotFoundError{orderReference: "order-1"},
+			expectedString: `no payment plan found for order "order-1" of merchant 00000000-0000-0000-0000-000000000000`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetPaymentPlanByOrderReferenceError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetPaymentPlanByOrderReferenceError{orderReference: "order-1"},
+			expectedString: `failed to get payment plan of order "order-1" of merchant: 00000000-0000-0000-0000-000000000000`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreatePaymentPlanLineItemError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreatePaymentPlanLineItemError{},
+			expectedString: "failed to create line item of payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListPaymentPlanLineItemsByPlanIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListPaymentPlanLineItemsByPlanIDError{},
+			expectedString: "failed to get line items of payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestGetLatestAuditEntryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            GetLatestAuditEntryError{},
+			expectedString: "failed to get latest audit entry of payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestCreateAuditEntryError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            CreateAuditEntryError{action: "plan_created"},
+			expectedString: "failed to create audit entry plan_created for payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestListAuditEntriesByPlanIDError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name:           "happy path",
+			err:            ListAuditEntriesByPlanIDError{},
+			expectedString: "failed to get audit entries of payment plan: 00000000-0000-0000-0000-000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedString string
+	}{
+		{
+			name: "happy path",
+			err: ValidationErrors{
+				InvalidAmountError{field: "total_amount.value", value: "0"},
+				MissingInstallmentsError{},
+			},
+			expectedString: `total_amount.value: invalid amount "0", a positive decimal is expected; ` +
+				"installments: at least one installment is expected",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.err.Error() != tt.expectedString {
+				t.Error("unexpected Error string")
+			}
+		})
+	}
+}
+
+func TestValidationErrors_Fields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  ValidationErrors
+		want map[string]string
+	}{
+		{
+			name: "every field is mapped to its message",
+			err: ValidationErrors{
+				InvalidAmountError{field: "total_amount.value", value: "0"},
+				PastDueDateError{field: "installments[0].due_at", dueAt: time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			want: map[string]string{
+				"total_amount.value":     `total_amount.value: invalid amount "0", a positive decimal is expected`,
+				"installments[0].due_at": "installments[0].due_at: due date 2022-07-01T00:00:00Z is in the past",
+			},
+		},
+		{
+			name: "the messages of one field are joined",
+			err: ValidationErrors{
+				MissingInstallmentsError{},
+				InstallmentSumMismatchError{totalAmount: "10", installmentsSum: "0"},
+			},
+			want: map[string]string{
+				"installments": "installments: at least one installment is expected; " +
+					"installments: sum of amounts 0 does not match the total amount 10",
+			},
+		},
+		{
+			name: "a rule without a field is left out",
+			err:  ValidationErrors{MissingProcessorReferenceError{}},
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.err.Fields(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidationErrors.Fields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// ExpirePendingPaymentPlans cancels the plans still pending ttl after they were created,
+// their installments are voided and the credit they held is available again
+func (p *PaymentServiceImp) ExpirePendingPaymentPlans(
+	ctx context.Context,
+	now time.Time,
+	ttl time.Duration,
+) ([]PaymentPlans, error) {
+	expired := make([]PaymentPlans, 0)
+
+	plans, err := p.repository.ListPaymentPlansByStatusCreatedBefore(ctx, &payments.ListPlansByStatusCreatedBeforeParams{
+		Status:        paymentPlanStatusPending,
+		CreatedBefore: now.Add(-ttl),
+	})
+	if err != nil {
+		return nil, ListPaymentPlansByStatusError{status: paymentPlanStatusPending}
+	}
+
+	// every plan is cancelled in its own unit of work, the next run picks up the ones left
+	for _, plan := range plans {
+		var expiredPlan *PaymentPlans
+
+		txErr := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+			var err error
+
+			expiredPlan, err = expirePendingPaymentPlan(ctx, txRepo, plan.ID)
+
+			return err
+		})
+		if txErr != nil {
+			return nil, fmt.Errorf("expire pending payment plans: %w", txErr)
+		}
+
+		if expiredPlan != nil {
+			expired = append(expired, *expiredPlan)
+		}
+	}
+
+	return expired, nil
+}
+
+// expirePendingPaymentPlan returns nil when the plan is not pending anymore
+func expirePendingPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+) (*PaymentPlans, error) {
+	// the plan stays locked so it cannot be completed while it is being cancelled, completing it locks it too
+	plan, err := repository.LockPaymentPlan(ctx, paymentPlanID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, LockPaymentPlanError{planID: paymentPlanID}
+	}
+
+	// it may have been completed or cancelled since it was listed
+	if plan.Status != paymentPlanStatusPending {
+		return nil, nil
+	}
+
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	before := audit.NewPlanSnapshot(plan, installments)
+
+	installments, err = voidUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeOffReceivable(ctx, repository, plan); err != nil {
+		return nil, err
+	}
+
+	cancelledPlan, err := updatePaymentPlanStatus(ctx, repository, plan, paymentPlanStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordAuditEntry(
+		ctx, repository, plan.ID, audit.ActionPlanExpired, before, audit.NewPlanSnapshot(cancelledPlan, installments),
+	); err != nil {
+		return nil, err
+	}
+
+	return newPaymentPlans(cancelledPlan, installments, nil), nil
+}
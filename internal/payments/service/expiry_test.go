@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_ExpirePendingPaymentPlans(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx           = context.Background()
+		now           = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		ttl           = 24 * time.Hour
+		userID        = uuid.Must(uuid.NewV4())
+		planID        = uuid.Must(uuid.NewV4())
+		installmentID = uuid.Must(uuid.NewV4())
+		createdAt, _  = time.Parse(common.TimeFormat, "2022-07-08T10:00:00Z")
+		dueAt, _      = time.Parse(common.TimeFormat, "2022-08-01T10:00:00Z")
+		currency      = "usdc"
+
+		listParams = &payments.ListPlansByStatusCreatedBeforeParams{
+			Status:        paymentPlanStatusPending,
+			CreatedBefore: now.Add(-ttl),
+		}
+
+		plan = &payments.Plan{
+			ID:        planID,
+			UserID:    userID,
+			Amount:    payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status:    paymentPlanStatusPending,
+			CreatedAt: createdAt,
+		}
+
+		installment = &payments.Installment{
+			ID:            installmentID,
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(100, 0), currency),
+			DueAt:         dueAt,
+			Status:        PaymentInstallmentStatusPending,
+		}
+	)
+
+	cancelledPlan := *plan
+	cancelledPlan.Status = paymentPlanStatusCancelled
+
+	completePlan := *plan
+	completePlan.Status = paymentPlanStatusComplete
+
+	voidedInstallment := *installment
+	voidedInstallment.Status = PaymentInstallmentStatusVoid
+
+	journal := []*ledger.Entry{newLedgerEntry(ledger.NewTransfer(
+		planID, ledger.EntryPlanCreated, ledger.AccountUserReceivable, ledger.AccountMerchantPayable, plan.Amount,
+	))}
+
+	writeOffEntry := &ledger.CreateEntryParams{
+		PaymentPlanID: planID,
+		Kind:          ledger.EntryReceivableWrittenOff,
+		Postings: []ledger.CreatePostingParams{
+			{Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: plan.Amount},
+			{Account: ledger.AccountUserReceivable, Direction: ledger.Credit, Amount: plan.Amount},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    []PaymentPlans
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID,
+						Status: PaymentInstallmentStatusVoid,
+					}).Return(&voidedInstallment, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(journal, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(writeOffEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+						ID:     planID,
+						Status: paymentPlanStatusCancelled,
+					}).Return(&cancelledPlan, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			want: []PaymentPlans{
+				{
+					ID:          planID.String(),
+					UserID:      userID.String(),
+					TotalAmount: payments.MustNewMoney(decimal.New(100, 0), currency),
+					Status:      paymentPlanStatusCancelled,
+					CreatedAt:   "2022-07-08T10:00:00Z",
+					Installments: []PaymentPlanInstallment{
+						{
+							ID:     installmentID.String(),
+							Amount: payments.MustNewMoney(decimal.New(100, 0), currency),
+							DueAt:  "2022-08-01T10:00:00Z",
+							Status: PaymentInstallmentStatusVoid,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "nothing to expire",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).Return(nil, nil)
+			},
+			want: []PaymentPlans{},
+		},
+		{
+			name: "plan completed since it was listed",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&completePlan, nil),
+				)
+			},
+			want: []PaymentPlans{},
+		},
+		{
+			name: "plan removed since it was listed",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			want: []PaymentPlans{},
+		},
+		{
+			name: "ListPaymentPlansByStatusCreatedBefore error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+					Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: ListPaymentPlansByStatusError{status: paymentPlanStatusPending},
+		},
+		{
+			name: "LockPaymentPlan error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: LockPaymentPlanError{planID: planID},
+		},
+		{
+			name: "ListPaymentInstallmentsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListPaymentInstallmentsByPlanIDError{planID: planID},
+		},
+		{
+			name: "CreateJournalEntry error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(journal, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateJournalEntryError{planID: planID, kind: ledger.EntryReceivableWrittenOff},
+		},
+		{
+			name: "UpdatePaymentPlanStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByStatusCreatedBefore(ctx, listParams).
+						Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdatePaymentPlanStatusError{planID: planID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(rm)
+			}
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.ExpirePendingPaymentPlans(ctx, now, ttl)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.ExpirePendingPaymentPlans() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.ExpirePendingPaymentPlans() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+)
+
+const (
+	LateFeeKindFixed      = "fixed"
+	LateFeeKindPercentage = "percentage"
+)
+
+const percentBase = 100
+
+// LateFeeRule decides how much is charged on an overdue installment
+type LateFeeRule struct {
+	kind           string
+	amount         decimal.Big
+	interval       time.Duration
+	maxAssessments int
+	caps           map[string]payments.Money
+}
+
+// NewLateFeeRule amount is charged as is for the fixed kind and as a percent of the installment amount
+// for the percentage kind, rounded down to the minor unit of the installment currency.
+// A currency missing from caps is not capped.
+func NewLateFeeRule(
+	kind string,
+	amount string,
+	interval time.Duration,
+	maxAssessments int,
+	caps map[string]string,
+) (*LateFeeRule, error) {
+	rule := &LateFeeRule{
+		kind:           kind,
+		interval:       interval,
+		maxAssessments: maxAssessments,
+		caps:           make(map[string]payments.Money, len(caps)),
+	}
+
+	if kind != LateFeeKindFixed && kind != LateFeeKindPercentage {
+		return nil, InvalidLateFeeRuleError{field: "kind", value: kind}
+	}
+
+	if err := parsePositiveAmount(&rule.amount, "amount", amount); err != nil {
+		return nil, InvalidLateFeeRuleError{field: "amount", value: amount}
+	}
+
+	if interval < 0 {
+		return nil, InvalidLateFeeRuleError{field: "interval", value: interval.String()}
+	}
+
+	if maxAssessments <= 0 {
+		return nil, InvalidLateFeeRuleError{field: "maxAssessments", value: fmt.Sprint(maxAssessments)}
+	}
+
+	for code, capAmount := range caps {
+		feeCap, err := payments.ParseMoney(capAmount, code)
+		if errors.Is(err, payments.ErrUnsupportedCurrency) {
+			return nil, InvalidLateFeeRuleError{field: "caps", value: code}
+		}
+
+		if err != nil || checkPositiveAmount(feeCap, "caps."+code) != nil {
+			return nil, InvalidLateFeeRuleError{field: "caps." + code, value: capAmount}
+		}
+
+		rule.caps[code] = feeCap
+	}
+
+	return rule, nil
+}
+
+// nextFee returns the fee to assess on the installment at now, nil when none is due
+func (lfr *LateFeeRule) nextFee(
+	inst *payments.Installment,
+	lateFees []*payments.LateFee,
+	now time.Time,
+) *payments.Money {
+	if len(lateFees) >= lfr.maxAssessments {
+		return nil
+	}
+
+	assessed, err := payments.ZeroMoney(inst.Amount.Currency())
+	if err != nil {
+		return nil
+	}
+
+	for _, lateFee := range lateFees {
+		if now.Before(lateFee.AssessedAt.Add(lfr.interval)) {
+			return nil
+		}
+
+		// the fees of an installment are in its currency, one which is not cannot be counted
+		if assessed, err = assessed.Add(lateFee.Amount); err != nil {
+			return nil
+		}
+	}
+
+	var fee payments.Money
+
+	switch lfr.kind {
+	case LateFeeKindFixed:
+		if fee, err = payments.NewMoney(&lfr.amount, inst.Amount.Currency()); err != nil {
+			return nil
+		}
+	case LateFeeKindPercentage:
+		percent := &decimal.Big{Context: decimal.Context128}
+		percent.Quo(&lfr.amount, new(decimal.Big).SetUint64(percentBase))
+		fee = inst.Amount.Mul(percent)
+	}
+
+	fee = fee.Round()
+
+	if feeCap, ok := lfr.caps[inst.Amount.Currency()]; ok {
+		remaining, err := feeCap.Sub(assessed)
+		if err != nil {
+			return nil
+		}
+
+		if cmp, err := fee.Cmp(remaining); err != nil || cmp > 0 {
+			fee = remaining.Round()
+		}
+	}
+
+	if fee.Sign() <= 0 {
+		return nil
+	}
+
+	return &fee
+}
+
+func (p *PaymentServiceImp) UseLateFeeRule(rule *LateFeeRule) {
+	p.lateFeeRule = rule
+}
+
+// AssessLateFees charges the overdue installments according to the late fee rule,
+// nothing is charged when no rule is used
+func (p *PaymentServiceImp) AssessLateFees(ctx context.Context, now time.Time) (*LateFeeRun, error) {
+	run := &LateFeeRun{Assessed: make([]PaymentPlanLateFee, 0)}
+
+	if p.lateFeeRule == nil {
+		return run, nil
+	}
+
+	overdue, err := p.repository.ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByStatusError{status: PaymentInstallmentStatusOverdue}
+	}
+
+	// every installment is charged in its own unit of work, one which fails is counted
+	// and left to the next run while the others are still charged
+	for _, inst := range overdue {
+		var (
+			lateFee    *payments.LateFee
+			wasCharged bool
+		)
+
+		txErr := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+			var err error
+
+			lateFee, wasCharged, err = assessLateFee(ctx, txRepo, p.lateFeeRule, inst.PaymentPlanID, inst.ID, now)
+
+			return err
+		})
+		switch {
+		case txErr != nil:
+			run.Failed++
+		case wasCharged:
+			run.Assessed = append(run.Assessed, newPlanLateFee(lateFee))
+		}
+	}
+
+	return run, nil
+}
+
+// assessLateFee reports whether the installment was charged
+func assessLateFee(
+	ctx context.Context,
+	repository repo.Repository,
+	rule *LateFeeRule,
+	planID uuid.UUID,
+	installmentID uuid.UUID,
+	now time.Time,
+) (*payments.LateFee, bool, error) {
+	// the plan is locked before its installment like every unit of work changing the plan,
+	// the installment stays locked so a concurrent run cannot charge it twice
+	if _, err := repository.LockPaymentPlan(ctx, planID); err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+
+		return nil, false, LockPaymentPlanError{planID: planID}
+	}
+
+	inst, err := repository.LockPaymentInstallment(ctx, installmentID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+
+		return nil, false, LockPaymentInstallmentError{installmentID: installmentID}
+	}
+
+	// it may have been paid since it was listed
+	if inst.Status != PaymentInstallmentStatusOverdue {
+		return nil, false, nil
+	}
+
+	lateFees, err := repository.ListPaymentLateFeesByInstallmentID(ctx, inst.ID)
+	if err != nil {
+		return nil, false, ListPaymentLateFeesByInstallmentIDError{installmentID: inst.ID}
+	}
+
+	fee := rule.nextFee(inst, lateFees, now)
+	if fee == nil {
+		return nil, false, nil
+	}
+
+	feesBefore, err := sumLateFees(inst.Amount.Currency(), lateFees)
+	if err != nil {
+		return nil, false, err
+	}
+
+	lateFee, err := repository.CreatePaymentLateFee(ctx, &payments.CreateLateFeeParams{
+		PaymentInstallmentID: inst.ID,
+		Amount:               *fee,
+		AssessedAt:           now,
+	})
+	if err != nil {
+		return nil, false, CreatePaymentLateFeeError{installmentID: inst.ID}
+	}
+
+	if err := postJournalEntry(ctx, repository, ledger.NewTransfer(
+		inst.PaymentPlanID, ledger.EntryLateFeeAssessed, ledger.AccountUserReceivable, ledger.AccountFeeIncome,
+		lateFee.Amount,
+	)); err != nil {
+		return nil, false, err
+	}
+
+	feesAfter, err := feesBefore.Add(lateFee.Amount)
+	if err != nil {
+		return nil, false, err
+	}
+
+	before := audit.NewInstallmentState(inst)
+	before.LateFees = &feesBefore
+	after := audit.NewInstallmentState(inst)
+	after.LateFees = &feesAfter
+
+	if err := recordAuditEntry(
+		ctx, repository, inst.PaymentPlanID, audit.ActionLateFeeAssessed,
+		&audit.Snapshot{Installments: []audit.InstallmentState{before}},
+		&audit.Snapshot{Installments: []audit.InstallmentState{after}},
+	); err != nil {
+		return nil, false, err
+	}
+
+	return lateFee, true, nil
+}
+
+func sumLateFees(currency string, lateFees []*payments.LateFee) (payments.Money, error) {
+	total, err := payments.ZeroMoney(currency)
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	for _, lateFee := range lateFees {
+		if total, err = total.Add(lateFee.Amount); err != nil {
+			return payments.Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+func newPlanLateFee(lateFee *payments.LateFee) PaymentPlanLateFee {
+	return PaymentPlanLateFee{
+		ID:            lateFee.ID.String(),
+		InstallmentID: lateFee.PaymentInstallmentID.String(),
+		Amount:        lateFee.Amount,
+		AssessedAt:    lateFee.AssessedAt.Format(common.TimeFormat),
+	}
+}
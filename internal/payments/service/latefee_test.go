@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNewLateFeeRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		kind           string
+		amount         string
+		interval       time.Duration
+		maxAssessments int
+		caps           map[string]string
+		wantErr        error
+	}{
+		{
+			name:           "fixed",
+			kind:           LateFeeKindFixed,
+			amount:         "10",
+			interval:       24 * time.Hour,
+			maxAssessments: 1,
+		},
+		{
+			name:           "percentage with caps",
+			kind:           LateFeeKindPercentage,
+			amount:         "1.5",
+			interval:       168 * time.Hour,
+			maxAssessments: 3,
+			caps:           map[string]string{"usdc": "25"},
+		},
+		{
+			name:           "unknown kind",
+			kind:           "flat",
+			amount:         "10",
+			maxAssessments: 1,
+			wantErr:        InvalidLateFeeRuleError{field: "kind", value: "flat"},
+		},
+		{
+			name:           "non positive amount",
+			kind:           LateFeeKindFixed,
+			amount:         "0",
+			maxAssessments: 1,
+			wantErr:        InvalidLateFeeRuleError{field: "amount", value: "0"},
+		},
+		{
+			name:           "negative interval",
+			kind:           LateFeeKindFixed,
+			amount:         "10",
+			interval:       -time.Hour,
+			maxAssessments: 1,
+			wantErr:        InvalidLateFeeRuleError{field: "interval", value: "-1h0m0s"},
+		},
+		{
+			name:    "non positive max assessments",
+			kind:    LateFeeKindFixed,
+			amount:  "10",
+			wantErr: InvalidLateFeeRuleError{field: "maxAssessments", value: "0"},
+		},
+		{
+			name:           "invalid cap",
+			kind:           LateFeeKindFixed,
+			amount:         "10",
+			maxAssessments: 1,
+			caps:           map[string]string{"usdc": "abc"},
+			wantErr:        InvalidLateFeeRuleError{field: "caps.usdc", value: "abc"},
+		},
+		{
+			name:           "cap of an unsupported currency",
+			kind:           LateFeeKindFixed,
+			amount:         "10",
+			maxAssessments: 1,
+			caps:           map[string]string{"btc": "1"},
+			wantErr:        InvalidLateFeeRuleError{field: "caps", value: "btc"},
+		},
+		{
+			name:           "cap more precise than its currency",
+			kind:           LateFeeKindFixed,
+			amount:         "10",
+			maxAssessments: 1,
+			caps:           map[string]string{"usd": "1.001"},
+			wantErr:        InvalidLateFeeRuleError{field: "caps.usd", value: "1.001"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLateFeeRule(tt.kind, tt.amount, tt.interval, tt.maxAssessments, tt.caps)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewLateFeeRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && got == nil {
+				t.Error("NewLateFeeRule() = nil, want a rule")
+			}
+		})
+	}
+}
+
+func TestLateFeeRule_nextFee(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now         = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		installment = &payments.Installment{Amount: payments.MustNewMoney(decimal.New(1000, 0), "usdc")}
+	)
+
+	assessedAgo := func(ago time.Duration, amount int64) *payments.LateFee {
+		return &payments.LateFee{Amount: payments.MustNewMoney(decimal.New(amount, 0), "usdc"), AssessedAt: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name     string
+		kind     string
+		amount   string
+		caps     map[string]string
+		lateFees []*payments.LateFee
+		want     string
+	}{
+		{
+			name:   "fixed fee",
+			kind:   LateFeeKindFixed,
+			amount: "10",
+			want:   "10",
+		},
+		{
+			name:   "percentage of the installment amount",
+			kind:   LateFeeKindPercentage,
+			amount: "1.5",
+			want:   "15",
+		},
+		{
+			name:   "percentage is rounded down to the minor unit of the currency",
+			kind:   LateFeeKindPercentage,
+			amount: "0.123456789",
+			want:   "1.234567",
+		},
+		{
+			name:   "nothing when the percentage rounds down to zero",
+			kind:   LateFeeKindPercentage,
+			amount: "0.00000001",
+			want:   "",
+		},
+		{
+			name:     "next assessment once the interval is over",
+			kind:     LateFeeKindFixed,
+			amount:   "10",
+			lateFees: []*payments.LateFee{assessedAgo(24*time.Hour, 10)},
+			want:     "10",
+		},
+		{
+			name:     "nothing within the interval",
+			kind:     LateFeeKindFixed,
+			amount:   "10",
+			lateFees: []*payments.LateFee{assessedAgo(time.Hour, 10)},
+			want:     "",
+		},
+		{
+			name:     "nothing past the max assessments",
+			kind:     LateFeeKindFixed,
+			amount:   "10",
+			lateFees: []*payments.LateFee{assessedAgo(72*time.Hour, 10), assessedAgo(48*time.Hour, 10)},
+			want:     "",
+		},
+		{
+			name:     "capped to what is left under the cap",
+			kind:     LateFeeKindPercentage,
+			amount:   "1.5",
+			caps:     map[string]string{"usdc": "25"},
+			lateFees: []*payments.LateFee{assessedAgo(24*time.Hour, 15)},
+			want:     "10",
+		},
+		{
+			name:     "nothing once the cap is reached",
+			kind:     LateFeeKindFixed,
+			amount:   "10",
+			caps:     map[string]string{"usdc": "10"},
+			lateFees: []*payments.LateFee{assessedAgo(24*time.Hour, 10)},
+			want:     "",
+		},
+		{
+			name:   "caps of other currencies are ignored",
+			kind:   LateFeeKindFixed,
+			amount: "10",
+			caps:   map[string]string{"usdt": "1"},
+			want:   "10",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := NewLateFeeRule(tt.kind, tt.amount, 24*time.Hour, 2, tt.caps)
+			if err != nil {
+				t.Fatalf("NewLateFeeRule() error = %v", err)
+			}
+
+			got := rule.nextFee(installment, tt.lateFees, now)
+
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("nextFee() = %v, want none", got)
+			case tt.want != "" && (got == nil || got.String() != tt.want):
+				t.Errorf("nextFee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceImp_AssessLateFees(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx           = context.Background()
+		now           = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		planID        = uuid.Must(uuid.NewV4())
+		installmentID = uuid.Must(uuid.NewV4())
+		lateFeeID     = uuid.Must(uuid.NewV4())
+
+		installment = &payments.Installment{
+			ID:            installmentID,
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			Status:        PaymentInstallmentStatusOverdue,
+		}
+
+		createLateFeeParams = &payments.CreateLateFeeParams{
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+			AssessedAt:           now,
+		}
+
+		lateFee = &payments.LateFee{
+			ID:                   lateFeeID,
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+			AssessedAt:           now,
+		}
+
+		lateFeeEntry = ledger.NewTransfer(
+			planID, ledger.EntryLateFeeAssessed, ledger.AccountUserReceivable, ledger.AccountFeeIncome, lateFee.Amount,
+		)
+	)
+
+	paidInstallment := *installment
+	paidInstallment.Status = PaymentInstallmentStatusPaid
+
+	failingInstallment := &payments.Installment{ID: uuid.Must(uuid.NewV4()), Status: PaymentInstallmentStatusOverdue}
+
+	assessedLateFee := PaymentPlanLateFee{
+		ID:            lateFeeID.String(),
+		InstallmentID: installmentID.String(),
+		Amount:        payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+		AssessedAt:    "2022-07-10T00:00:00Z",
+	}
+
+	rule, err := NewLateFeeRule(LateFeeKindFixed, "10", 24*time.Hour, 3, nil)
+	if err != nil {
+		t.Fatalf("NewLateFeeRule() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    *LateFeeRule
+		prepare func(rm *repomock.MockRepository)
+		want    *LateFeeRun
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentLateFee(ctx, gomock.Eq(createLateFeeParams)).Return(lateFee, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(lateFeeEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{assessedLateFee}},
+		},
+		{
+			name: "no rule",
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}},
+		},
+		{
+			name: "installment paid since it was listed",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(&paidInstallment, nil),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}},
+		},
+		{
+			name: "installment removed since it was listed",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}},
+		},
+		{
+			name: "no fee due yet",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).
+						Return([]*payments.LateFee{lateFee}, nil),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}},
+		},
+		{
+			name: "an installment which fails does not stop the next one",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{failingInstallment, installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, failingInstallment.PaymentPlanID).
+						Return(&payments.Plan{ID: failingInstallment.PaymentPlanID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, failingInstallment.ID).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentLateFee(ctx, gomock.Eq(createLateFeeParams)).Return(lateFee, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(lateFeeEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{assessedLateFee}, Failed: 1},
+		},
+		{
+			name: "ListPaymentInstallmentsByStatus error",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+					Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: ListPaymentInstallmentsByStatusError{status: PaymentInstallmentStatusOverdue},
+		},
+		{
+			name: "plan removed since the installment was listed",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}},
+		},
+		{
+			name: "LockPaymentPlan error",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}, Failed: 1},
+		},
+		{
+			name: "LockPaymentInstallment error",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}, Failed: 1},
+		},
+		{
+			name: "ListPaymentLateFeesByInstallmentID error",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).
+						Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}, Failed: 1},
+		},
+		{
+			name: "CreatePaymentLateFee error",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentLateFee(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}, Failed: 1},
+		},
+		{
+			name: "CreateJournalEntry error",
+			rule: rule,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentInstallmentsByStatus(ctx, PaymentInstallmentStatusOverdue).
+						Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentLateFee(ctx, gomock.Any()).Return(lateFee, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &LateFeeRun{Assessed: []PaymentPlanLateFee{}, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(rm)
+			}
+
+			p := &PaymentServiceImp{repository: rm}
+			p.UseLateFeeRule(tt.rule)
+
+			got, err := p.AssessLateFees(ctx, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.AssessLateFees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.AssessLateFees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
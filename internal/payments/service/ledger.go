@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// GetPaymentPlanLedger returns the balances of the accounts moved by a plan and the journal entries behind them
+func (p *PaymentServiceImp) GetPaymentPlanLedger(ctx context.Context, paymentPlanID uuid.UUID) (*PaymentPlanLedger, error) {
+	plan, err := p.repository.GetPaymentPlanByID(ctx, paymentPlanID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+		}
+
+		return nil, GetPaymentPlanByIDError{planID: paymentPlanID}
+	}
+
+	entries, err := p.repository.ListJournalEntriesByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListJournalEntriesByPlanIDError{planID: plan.ID}
+	}
+
+	balances, err := ledger.Balances(entries, plan.Amount.Currency())
+	if err != nil {
+		return nil, err
+	}
+
+	planLedger := &PaymentPlanLedger{
+		PaymentPlanID: plan.ID.String(),
+		Balances:      balances,
+		Entries:       make([]JournalEntry, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		planLedger.Entries = append(planLedger.Entries, newJournalEntry(entry))
+	}
+
+	return planLedger, nil
+}
+
+// postJournalEntry appends entry to the ledger, it is called in the unit of work of the change it records
+func postJournalEntry(ctx context.Context, repository repo.Repository, entry *ledger.CreateEntryParams) error {
+	if err := entry.Validate(); err != nil {
+		return InvalidJournalEntryError{planID: entry.PaymentPlanID, kind: entry.Kind}
+	}
+
+	if _, err := repository.CreateJournalEntry(ctx, entry); err != nil {
+		return CreateJournalEntryError{planID: entry.PaymentPlanID, kind: entry.Kind}
+	}
+
+	return nil
+}
+
+// writeOffReceivable clears what the user still owes on a plan whose unpaid installments were voided.
+// Payments are taken as settling the installment amounts before the late fees, so what is left unpaid
+// is written off the fee income first and the merchant payable for the rest.
+func writeOffReceivable(ctx context.Context, repository repo.Repository, plan *payments.Plan) error {
+	entries, err := repository.ListJournalEntriesByPlanID(ctx, plan.ID)
+	if err != nil {
+		return ListJournalEntriesByPlanIDError{planID: plan.ID}
+	}
+
+	balances, err := ledger.Balances(entries, plan.Amount.Currency())
+	if err != nil {
+		return err
+	}
+
+	receivable := accountBalance(balances, ledger.AccountUserReceivable)
+	if receivable.Sign() <= 0 {
+		return nil
+	}
+
+	fees := accountBalance(balances, ledger.AccountFeeIncome)
+	if cmp, err := fees.Cmp(receivable); err != nil || cmp > 0 {
+		fees = receivable
+	}
+
+	principal, err := receivable.Sub(fees)
+	if err != nil {
+		return err
+	}
+
+	entry := &ledger.CreateEntryParams{PaymentPlanID: plan.ID, Kind: ledger.EntryReceivableWrittenOff}
+
+	if fees.Sign() > 0 {
+		entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+			Account: ledger.AccountFeeIncome, Direction: ledger.Debit, Amount: fees,
+		})
+	}
+
+	if principal.Sign() > 0 {
+		entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+			Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: principal,
+		})
+	}
+
+	entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+		Account: ledger.AccountUserReceivable, Direction: ledger.Credit, Amount: receivable,
+	})
+
+	return postJournalEntry(ctx, repository, entry)
+}
+
+// accountBalance is the balance of account, Balances lists every account so it is always found
+func accountBalance(balances []ledger.Balance, account string) payments.Money {
+	for _, balance := range balances {
+		if balance.Account == account {
+			return balance.Amount
+		}
+	}
+
+	return payments.Money{}
+}
+
+func newJournalEntry(entry *ledger.Entry) JournalEntry {
+	postings := make([]JournalPosting, 0, len(entry.Postings))
+
+	for _, posting := range entry.Postings {
+		postings = append(postings, JournalPosting{
+			Account:   posting.Account,
+			Direction: posting.Direction,
+			Amount:    posting.Amount,
+		})
+	}
+
+	return JournalEntry{
+		ID:        entry.ID.String(),
+		Kind:      entry.Kind,
+		Postings:  postings,
+		CreatedAt: entry.CreatedAt.Format(common.TimeFormat),
+	}
+}
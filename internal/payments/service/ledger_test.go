@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_GetPaymentPlanLedger(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		planID    = uuid.Must(uuid.NewV4())
+		currency  = "usdc"
+		createdAt = time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC)
+
+		plan = &payments.Plan{
+			ID:     planID,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status: paymentPlanStatusComplete,
+		}
+	)
+
+	usdc := func(amount int64) payments.Money {
+		return payments.MustNewMoney(decimal.New(amount, 0), currency)
+	}
+
+	planCreated := newLedgerEntry(ledger.NewTransfer(
+		planID, ledger.EntryPlanCreated, ledger.AccountUserReceivable, ledger.AccountMerchantPayable, usdc(100),
+	))
+	planCreated.CreatedAt = createdAt
+
+	installmentPaid := newLedgerEntry(ledger.NewTransfer(
+		planID, ledger.EntryInstallmentPaid, ledger.AccountCash, ledger.AccountUserReceivable, usdc(25),
+	))
+	installmentPaid.CreatedAt = createdAt
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    *PaymentPlanLedger
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).
+						Return([]*ledger.Entry{planCreated, installmentPaid}, nil),
+				)
+			},
+			want: &PaymentPlanLedger{
+				PaymentPlanID: planID.String(),
+				Balances: []ledger.Balance{
+					{Account: ledger.AccountCash, Amount: usdc(25)},
+					{Account: ledger.AccountUserReceivable, Amount: usdc(75)},
+					{Account: ledger.AccountMerchantPayable, Amount: usdc(100)},
+					{Account: ledger.AccountFeeIncome, Amount: usdc(0)},
+				},
+				Entries: []JournalEntry{
+					{
+						ID:   planCreated.ID.String(),
+						Kind: ledger.EntryPlanCreated,
+						Postings: []JournalPosting{
+							{Account: ledger.AccountUserReceivable, Direction: ledger.Debit, Amount: usdc(100)},
+							{Account: ledger.AccountMerchantPayable, Direction: ledger.Credit, Amount: usdc(100)},
+						},
+						CreatedAt: "2022-07-01T10:00:00Z",
+					},
+					{
+						ID:   installmentPaid.ID.String(),
+						Kind: ledger.EntryInstallmentPaid,
+						Postings: []JournalPosting{
+							{Account: ledger.AccountCash, Direction: ledger.Debit, Amount: usdc(25)},
+							{Account: ledger.AccountUserReceivable, Direction: ledger.Credit, Amount: usdc(25)},
+						},
+						CreatedAt: "2022-07-01T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "GetPaymentPlanByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: GetPaymentPlanByIDError{planID: planID},
+		},
+		{
+			name: "ListJournalEntriesByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListJournalEntriesByPlanIDError{planID: planID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.GetPaymentPlanLedger(ctx, planID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.GetPaymentPlanLedger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.GetPaymentPlanLedger() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newLedgerEntry is the entry the repository returns once params are written
+func newLedgerEntry(params *ledger.CreateEntryParams) *ledger.Entry {
+	entry := &ledger.Entry{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: params.PaymentPlanID, Kind: params.Kind}
+
+	for _, posting := range params.Postings {
+		entry.Postings = append(entry.Postings, ledger.Posting{
+			ID:        uuid.Must(uuid.NewV4()),
+			Account:   posting.Account,
+			Direction: posting.Direction,
+			Amount:    posting.Amount,
+		})
+	}
+
+	return entry
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+var _ MerchantService = (*MerchantServiceImp)(nil)
+
+type MerchantServiceImp struct {
+	repository repo.Repository
+}
+
+func NewMerchantService() *MerchantServiceImp {
+	return &MerchantServiceImp{}
+}
+
+func (m *MerchantServiceImp) UseRepo(repository repo.Repository) {
+	m.repository = repository
+}
+
+func (m *MerchantServiceImp) CreateMerchant(ctx context.Context, merchant *CreateMerchantParams) (*Merchant, error) {
+	name := strings.TrimSpace(merchant.Name)
+	if name == "" {
+		return nil, MissingMerchantNameError{}
+	}
+
+	merchantID, err := uuid.NewV4()
+	if err != nil {
+		return nil, CreateMerchantError{}
+	}
+
+	created, err := m.repository.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: name})
+	if err != nil {
+		return nil, CreateMerchantError{}
+	}
+
+	return &Merchant{
+		ID:        created.ID.String(),
+		Name:      created.Name,
+		CreatedAt: created.CreatedAt.Format(common.TimeFormat),
+	}, nil
+}
+
+// ListMerchantPaymentPlans an offset past the last plan returns no plan
+func (m *MerchantServiceImp) ListMerchantPaymentPlans(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	page *ListMerchantPaymentPlansParams,
+) ([]PaymentPlans, error) {
+	if err := checkMerchantExists(ctx, m.repository, merchantID); err != nil {
+		return nil, err
+	}
+
+	offset := page.Offset
+	if offset > math.MaxInt32 {
+		offset = math.MaxInt32
+	}
+
+	plans, err := m.repository.ListPaymentPlansByMerchantID(ctx, &payments.ListPlansByMerchantIDParams{
+		MerchantID:  merchantID,
+		Offset:      int32(offset),
+		Limit:       int32(page.Limit),
+		OldestFirst: page.OldestFirst,
+	})
+	if err != nil {
+		return nil, ListMerchantPaymentPlansError{merchantID: merchantID}
+	}
+
+	paymentPlans := make([]PaymentPlans, 0, len(plans))
+
+	for _, plan := range plans {
+		paymentPlan, err := readPaymentPlan(ctx, m.repository, plan)
+		if err != nil {
+			return nil, err
+		}
+
+		paymentPlan.History = nil
+
+		paymentPlans = append(paymentPlans, *paymentPlan)
+	}
+
+	return paymentPlans, nil
+}
+
+// GetPaymentPlanByOrderReference lists the installments superseded by reschedules as history
+func (m *MerchantServiceImp) GetPaymentPlanByOrderReference(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	orderReference string,
+) (*PaymentPlans, error) {
+	if strings.TrimSpace(orderReference) == "" {
+		return nil, InvalidOrderError{field: "order_reference", reason: "a non blank value is expected"}
+	}
+
+	if err := checkMerchantExists(ctx, m.repository, merchantID); err != nil {
+		return nil, err
+	}
+
+	plan, err := m.repository.GetPaymentPlanByOrderReference(ctx, &payments.GetPlanByOrderReferenceParams{
+		MerchantID:     merchantID,
+		OrderReference: orderReference,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, OrderNotFoundError{merchantID: merchantID, orderReference: orderReference}
+		}
+
+		return nil, GetPaymentPlanByOrderReferenceError{merchantID: merchantID, orderReference: orderReference}
+	}
+
+	return readPaymentPlan(ctx, m.repository, plan)
+}
+
+func (m *MerchantServiceImp) GetMerchantTotals(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	from time.Time,
+	to time.Time,
+) (*MerchantTotals, error) {
+	if !from.Before(to) {
+		return nil, InvalidDateRangeError{from: from, to: to}
+	}
+
+	if err := checkMerchantExists(ctx, m.repository, merchantID); err != nil {
+		return nil, err
+	}
+
+	totals, err := m.repository.GetMerchantTotals(ctx, &payments.MerchantTotalsParams{
+		MerchantID:  merchantID,
+		CreatedFrom: from,
+		CreatedTo:   to,
+	})
+	if err != nil {
+		return nil, GetMerchantTotalsError{merchantID: merchantID}
+	}
+
+	merchantTotals := &MerchantTotals{
+		MerchantID: merchantID.String(),
+		From:       from.Format(common.TimeFormat),
+		To:         to.Format(common.TimeFormat),
+		Totals:     make([]MerchantCurrencyTotals, 0, len(totals)),
+	}
+
+	for _, total := range totals {
+		merchantTotals.Totals = append(merchantTotals.Totals, MerchantCurrencyTotals{
+			Originated:  total.Originated,
+			Outstanding: total.Outstanding,
+			Refunded:    total.Refunded,
+		})
+	}
+
+	return merchantTotals, nil
+}
+
+func checkMerchantExists(ctx context.Context, repository repo.Repository, merchantID uuid.UUID) error {
+	if _, err := repository.GetMerchantByID(ctx, merchantID); err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return MerchantNotFoundError{merchantID: merchantID}
+		}
+
+		return GetMerchantError{merchantID: merchantID}
+	}
+
+	return nil
+}
@@ -0,0 +1,573 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/repo/memory"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestMerchantServiceImp_CreateMerchant(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		merchantID = uuid.Must(uuid.NewV4())
+		createdAt  = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+	)
+
+	createMerchant := func(_ context.Context, arg *payments.CreateMerchantParams) (*payments.Merchant, error) {
+		if arg.ID == uuid.Nil {
+			return nil, fmt.Errorf("missing id")
+		}
+
+		return &payments.Merchant{ID: merchantID, Name: arg.Name, CreatedAt: createdAt}, nil
+	}
+
+	tests := []struct {
+		name    string
+		params  *CreateMerchantParams
+		prepare func(rm *repomock.MockRepository)
+		want    *Merchant
+		wantErr error
+	}{
+		{
+			name:   "happy path",
+			params: &CreateMerchantParams{Name: " Acme "},
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().CreateMerchant(ctx, gomock.Any()).DoAndReturn(createMerchant)
+			},
+			want: &Merchant{ID: merchantID.String(), Name: "Acme", CreatedAt: "2022-07-10T00:00:00Z"},
+		},
+		{
+			name:    "missing name",
+			params:  &CreateMerchantParams{Name: " "},
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: MissingMerchantNameError{},
+		},
+		{
+			name:   "CreateMerchant error",
+			params: &CreateMerchantParams{Name: "Acme"},
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().CreateMerchant(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: CreateMerchantError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			m := &MerchantServiceImp{repository: rm}
+
+			got, err := m.CreateMerchant(ctx, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MerchantServiceImp.CreateMerchant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MerchantServiceImp.CreateMerchant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerchantServiceImp_ListMerchantPaymentPlans(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		merchantID = uuid.Must(uuid.NewV4())
+		createdAt  = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		amount     = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+		plan       = &payments.Plan{
+			ID:         uuid.Must(uuid.NewV4()),
+			UserID:     uuid.Must(uuid.NewV4()),
+			MerchantID: merchantID,
+			Amount:     amount,
+			Status:     paymentPlanStatusComplete,
+			CreatedAt:  createdAt,
+		}
+		installment = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Amount:        amount,
+			DueAt:         createdAt,
+			Status:        PaymentInstallmentStatusPaid,
+			Version:       1,
+		}
+		listArg = &payments.ListPlansByMerchantIDParams{MerchantID: merchantID, Offset: 20, Limit: 10, OldestFirst: true}
+		page    = &ListMerchantPaymentPlansParams{Offset: 20, Limit: 10, OldestFirst: true}
+	)
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    []PaymentPlans
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().ListPaymentPlansByMerchantID(ctx, gomock.Eq(listArg)).Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, plan.ID).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, plan.ID).Return(nil, nil),
+				)
+			},
+			want: []PaymentPlans{
+				{
+					ID:          plan.ID.String(),
+					UserID:      plan.UserID.String(),
+					MerchantID:  merchantID.String(),
+					TotalAmount: amount,
+					Status:      paymentPlanStatusComplete,
+					CreatedAt:   "2022-07-10T00:00:00Z",
+					Installments: []PaymentPlanInstallment{
+						{
+							ID:      installment.ID.String(),
+							Amount:  amount,
+							DueAt:   "2022-07-10T00:00:00Z",
+							Status:  PaymentInstallmentStatusPaid,
+							Version: 1,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "merchant not found",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: MerchantNotFoundError{merchantID: merchantID},
+		},
+		{
+			name: "ListPaymentPlansByMerchantID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().ListPaymentPlansByMerchantID(ctx, gomock.Eq(listArg)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListMerchantPaymentPlansError{merchantID: merchantID},
+		},
+		{
+			name: "ListPaymentInstallmentsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().ListPaymentPlansByMerchantID(ctx, gomock.Eq(listArg)).Return([]*payments.Plan{plan}, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListPaymentInstallmentsByPlanIDError{planID: plan.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			m := &MerchantServiceImp{repository: rm}
+
+			got, err := m.ListMerchantPaymentPlans(ctx, merchantID, page)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MerchantServiceImp.ListMerchantPaymentPlans() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MerchantServiceImp.ListMerchantPaymentPlans() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerchantServiceImp_GetPaymentPlanByOrderReference(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx            = context.Background()
+		merchantID     = uuid.Must(uuid.NewV4())
+		orderReference = "order-42"
+		createdAt      = time.Date(2022, 10, 19, 0, 0, 0, 0, time.UTC)
+		amount         = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+		plan           = &payments.Plan{
+			ID:             uuid.Must(uuid.NewV4()),
+			UserID:         uuid.Must(uuid.NewV4()),
+			MerchantID:     merchantID,
+			Amount:         amount,
+			Status:         paymentPlanStatusComplete,
+			OrderReference: orderReference,
+			Description:    "a chair",
+			CreatedAt:      createdAt,
+		}
+		installment = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Amount:        amount,
+			DueAt:         createdAt,
+			Status:        PaymentInstallmentStatusPending,
+			Version:       1,
+		}
+		lineItem = &payments.LineItem{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			SKU:           "CH-1",
+			Name:          "Chair",
+			Quantity:      1,
+			UnitPrice:     amount,
+		}
+		lookupArg = &payments.GetPlanByOrderReferenceParams{MerchantID: merchantID, OrderReference: orderReference}
+	)
+
+	tests := []struct {
+		name           string
+		orderReference string
+		prepare        func(rm *repomock.MockRepository)
+		want           *PaymentPlans
+		wantErr        error
+	}{
+		{
+			name:           "happy path",
+			orderReference: orderReference,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, gomock.Eq(lookupArg)).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, plan.ID).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, plan.ID).Return([]*payments.LineItem{lineItem}, nil),
+				)
+			},
+			want: &PaymentPlans{
+				ID:             plan.ID.String(),
+				UserID:         plan.UserID.String(),
+				MerchantID:     merchantID.String(),
+				OrderReference: orderReference,
+				Description:    "a chair",
+				TotalAmount:    amount,
+				Status:         paymentPlanStatusComplete,
+				CreatedAt:      "2022-10-19T00:00:00Z",
+				Installments: []PaymentPlanInstallment{
+					{
+						ID:      installment.ID.String(),
+						Amount:  amount,
+						DueAt:   "2022-10-19T00:00:00Z",
+						Status:  PaymentInstallmentStatusPending,
+						Version: 1,
+					},
+				},
+				LineItems: []PaymentPlanLineItem{
+					{SKU: "CH-1", Name: "Chair", Quantity: 1, UnitPrice: amount},
+				},
+			},
+		},
+		{
+			name:           "blank order reference",
+			orderReference: " ",
+			prepare:        func(rm *repomock.MockRepository) {},
+			wantErr:        InvalidOrderError{field: "order_reference", reason: "a non blank value is expected"},
+		},
+		{
+			name:           "merchant not found",
+			orderReference: orderReference,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: MerchantNotFoundError{merchantID: merchantID},
+		},
+		{
+			name:           "order not found",
+			orderReference: orderReference,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, gomock.Eq(lookupArg)).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			wantErr: OrderNotFoundError{merchantID: merchantID, orderReference: orderReference},
+		},
+		{
+			name:           "GetPaymentPlanByOrderReference error",
+			orderReference: orderReference,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, gomock.Eq(lookupArg)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: GetPaymentPlanByOrderReferenceError{merchantID: merchantID, orderReference: orderReference},
+		},
+		{
+			name:           "ListPaymentPlanLineItemsByPlanID error",
+			orderReference: orderReference,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, gomock.Eq(lookupArg)).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, plan.ID).Return([]*payments.Installment{installment}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, plan.ID).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, plan.ID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListPaymentPlanLineItemsByPlanIDError{planID: plan.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			m := &MerchantServiceImp{repository: rm}
+
+			got, err := m.GetPaymentPlanByOrderReference(ctx, merchantID, tt.orderReference)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MerchantServiceImp.GetPaymentPlanByOrderReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MerchantServiceImp.GetPaymentPlanByOrderReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerchantServiceImp_GetMerchantTotals(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		merchantID = uuid.Must(uuid.NewV4())
+		from       = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		to         = time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+		totalsArg  = &payments.MerchantTotalsParams{MerchantID: merchantID, CreatedFrom: from, CreatedTo: to}
+		totals     = &payments.MerchantTotals{
+			Originated:  payments.MustNewMoney(decimal.New(400, 0), "usdc"),
+			Outstanding: payments.MustNewMoney(decimal.New(130, 0), "usdc"),
+			Refunded:    payments.MustNewMoney(decimal.New(60, 0), "usdc"),
+		}
+	)
+
+	tests := []struct {
+		name    string
+		from    time.Time
+		prepare func(rm *repomock.MockRepository)
+		want    *MerchantTotals
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			from: from,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().GetMerchantTotals(ctx, gomock.Eq(totalsArg)).Return([]*payments.MerchantTotals{totals}, nil),
+				)
+			},
+			want: &MerchantTotals{
+				MerchantID: merchantID.String(),
+				From:       "2022-07-01T00:00:00Z",
+				To:         "2022-08-01T00:00:00Z",
+				Totals: []MerchantCurrencyTotals{
+					{Originated: totals.Originated, Outstanding: totals.Outstanding, Refunded: totals.Refunded},
+				},
+			},
+		},
+		{
+			name:    "from is not before to",
+			from:    to,
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: InvalidDateRangeError{from: to, to: to},
+		},
+		{
+			name: "GetMerchantByID error",
+			from: from,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: GetMerchantError{merchantID: merchantID},
+		},
+		{
+			name: "GetMerchantTotals error",
+			from: from,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().GetMerchantTotals(ctx, gomock.Eq(totalsArg)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: GetMerchantTotalsError{merchantID: merchantID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			m := &MerchantServiceImp{repository: rm}
+
+			got, err := m.GetMerchantTotals(ctx, merchantID, tt.from, to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MerchantServiceImp.GetMerchantTotals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MerchantServiceImp.GetMerchantTotals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerchantServiceImp_GetMerchantTotals_Refunds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		memRepo    = memory.NewInMemRepository()
+		merchantID = uuid.Must(uuid.NewV4())
+		userID     = uuid.Must(uuid.NewV4())
+		from       = time.Now().UTC().Add(-time.Second)
+		usdc       = func(amount int64) payments.Money { return payments.MustNewMoney(decimal.New(amount, 0), "usdc") }
+	)
+
+	if _, err := memRepo.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"}); err != nil {
+		t.Fatalf("fail to create merchant: %v", err)
+	}
+
+	plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID:     userID,
+		MerchantID: merchantID,
+		Amount:     usdc(100),
+		Status:     paymentPlanStatusComplete,
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	installments := make([]*payments.Installment, 0, 2)
+
+	for _, status := range []string{PaymentInstallmentStatusPaid, PaymentInstallmentStatusOverdue} {
+		inst, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+			PaymentPlanID: plan.ID,
+			Amount:        usdc(50),
+			DueAt:         time.Now().UTC().Add(time.Duration(len(installments)) * time.Hour),
+			Status:        status,
+		})
+		if err != nil {
+			t.Fatalf("fail to create installment: %v", err)
+		}
+
+		installments = append(installments, inst)
+	}
+
+	if _, err := memRepo.CreatePaymentLateFee(ctx, &payments.CreateLateFeeParams{
+		PaymentInstallmentID: installments[1].ID,
+		Amount:               usdc(5),
+		AssessedAt:           time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("fail to create late fee: %v", err)
+	}
+
+	for _, entry := range []*ledger.CreateEntryParams{
+		ledger.NewTransfer(plan.ID, ledger.EntryPlanCreated, ledger.AccountUserReceivable,
+			ledger.AccountMerchantPayable, usdc(100)),
+		ledger.NewTransfer(plan.ID, ledger.EntryInstallmentPaid, ledger.AccountCash,
+			ledger.AccountUserReceivable, usdc(50)),
+		ledger.NewTransfer(plan.ID, ledger.EntryLateFeeAssessed, ledger.AccountUserReceivable,
+			ledger.AccountFeeIncome, usdc(5)),
+	} {
+		if _, err := memRepo.CreateJournalEntry(ctx, entry); err != nil {
+			t.Fatalf("fail to create journal entry: %v", err)
+		}
+	}
+
+	p := NewPaymentPlanService()
+	p.UseRepo(memRepo)
+
+	m := NewMerchantService()
+	m.UseRepo(memRepo)
+
+	// 50 taken off the overdue installment, which is voided with its late fee, and 20 given back on the paid one
+	amount := usdc(70)
+	if _, err := p.RefundPaymentPlan(ctx, plan.ID, &RefundPaymentPlanParams{
+		UserID: userID,
+		Amount: &amount,
+		Reason: "returned",
+	}); err != nil {
+		t.Fatalf("PaymentServiceImp.RefundPaymentPlan() error = %v", err)
+	}
+
+	got, err := m.GetMerchantTotals(ctx, merchantID, from, time.Now().UTC().Add(time.Second))
+	if err != nil {
+		t.Fatalf("MerchantServiceImp.GetMerchantTotals() error = %v", err)
+	}
+
+	want := []MerchantCurrencyTotals{{Originated: usdc(100), Outstanding: usdc(0), Refunded: usdc(70)}}
+	if len(got.Totals) != 1 || !got.Totals[0].Originated.Equal(want[0].Originated) ||
+		!got.Totals[0].Outstanding.Equal(want[0].Outstanding) || !got.Totals[0].Refunded.Equal(want[0].Refunded) {
+		t.Fatalf("MerchantServiceImp.GetMerchantTotals() = %+v, want %+v", got.Totals, want)
+	}
+
+	planLedger, err := p.GetPaymentPlanLedger(ctx, plan.ID)
+	if err != nil {
+		t.Fatalf("PaymentServiceImp.GetPaymentPlanLedger() error = %v", err)
+	}
+
+	// what is left owed to the merchant is what was originated less what was refunded
+	payable, err := want[0].Originated.Sub(want[0].Refunded)
+	if err != nil {
+		t.Fatalf("fail to subtract: %v", err)
+	}
+
+	if got := accountBalance(planLedger.Balances, ledger.AccountMerchantPayable); !got.Equal(payable) {
+		t.Errorf("merchant payable = %v, want %v", got, payable)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// UseEventPublisher the outbox events are written either way, they are only relayed once a publisher is used
+func (p *PaymentServiceImp) UseEventPublisher(publisher outbox.EventPublisher) {
+	p.eventPublisher = publisher
+}
+
+// RelayOutboxEvents publishes the events in the order they were written and marks them published in the same
+// unit of work, an event published right before a failure is published again by the next relay.
+// Once an event of a plan fails to publish it is marked failed and the later events of the plan are not relayed
+// until it is published, the events of the other plans are not held up by it.
+func (p *PaymentServiceImp) RelayOutboxEvents(ctx context.Context, now time.Time, limit int) (*OutboxRelay, error) {
+	relay := &OutboxRelay{}
+
+	if p.eventPublisher == nil {
+		return relay, nil
+	}
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		events, err := txRepo.LockUnpublishedOutboxEvents(ctx, int32(limit))
+		if err != nil {
+			return ListUnpublishedOutboxEventsError{}
+		}
+
+		failedPlans := make(map[uuid.UUID]bool)
+
+		for _, event := range events {
+			if failedPlans[event.PaymentPlanID] {
+				relay.HeldBack++
+
+				continue
+			}
+
+			if err := p.eventPublisher.Publish(ctx, event); err != nil {
+				failedPlans[event.PaymentPlanID] = true
+				relay.Failed++
+
+				if _, err := txRepo.MarkOutboxEventFailed(ctx, &outbox.MarkEventFailedParams{
+					ID:       event.ID,
+					FailedAt: now,
+				}); err != nil {
+					return MarkOutboxEventFailedError{eventID: event.ID}
+				}
+
+				continue
+			}
+
+			if _, err := txRepo.MarkOutboxEventPublished(ctx, &outbox.MarkEventPublishedParams{
+				ID:          event.ID,
+				PublishedAt: now,
+			}); err != nil {
+				return MarkOutboxEventPublishedError{eventID: event.ID}
+			}
+
+			relay.Published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("relay outbox events: %w", err)
+	}
+
+	return relay, nil
+}
+
+// recordPlanEvent writes the event in the unit of work of the change it announces
+func recordPlanEvent(ctx context.Context, repository repo.Repository, eventType string, plan *payments.Plan) error {
+	event, err := outbox.NewPlanEvent(eventType, plan)
+	if err != nil {
+		return CreateOutboxEventError{planID: plan.ID, eventType: eventType}
+	}
+
+	return recordEvent(ctx, repository, event)
+}
+
+// recordInstallmentEvent writes the event in the unit of work of the change it announces
+func recordInstallmentEvent(
+	ctx context.Context,
+	repository repo.Repository,
+	eventType string,
+	inst *payments.Installment,
+) error {
+	event, err := outbox.NewInstallmentEvent(eventType, inst)
+	if err != nil {
+		return CreateOutboxEventError{planID: inst.PaymentPlanID, eventType: eventType}
+	}
+
+	return recordEvent(ctx, repository, event)
+}
+
+func recordEvent(ctx context.Context, repository repo.Repository, event *outbox.CreateEventParams) error {
+	if _, err := repository.CreateOutboxEvent(ctx, event); err != nil {
+		return CreateOutboxEventError{planID: event.PaymentPlanID, eventType: event.Type}
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_RelayOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		now     = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		planID  = uuid.Must(uuid.NewV4())
+		planID2 = uuid.Must(uuid.NewV4())
+		limit   = 10
+
+		created   = &outbox.Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planID, Type: outbox.EventPlanCreated}
+		completed = &outbox.Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planID, Type: outbox.EventPlanCompleted}
+		created2  = &outbox.Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planID2, Type: outbox.EventPlanCreated}
+		events    = []*outbox.Event{created, created2, completed}
+	)
+
+	markPublished := func(event *outbox.Event) *outbox.MarkEventPublishedParams {
+		return &outbox.MarkEventPublishedParams{ID: event.ID, PublishedAt: now}
+	}
+
+	markFailed := func(event *outbox.Event) *outbox.MarkEventFailedParams {
+		return &outbox.MarkEventFailedParams{ID: event.ID, FailedAt: now}
+	}
+
+	tests := []struct {
+		name          string
+		prepare       func(rm *repomock.MockRepository)
+		failPlan      uuid.UUID
+		want          *OutboxRelay
+		wantPublished []*outbox.Event
+		wantErr       error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockUnpublishedOutboxEvents(ctx, int32(limit)).Return(events, nil),
+					rm.EXPECT().MarkOutboxEventPublished(ctx, markPublished(created)).Return(created, nil),
+					rm.EXPECT().MarkOutboxEventPublished(ctx, markPublished(created2)).Return(created2, nil),
+					rm.EXPECT().MarkOutboxEventPublished(ctx, markPublished(completed)).Return(completed, nil),
+				)
+			},
+			want:          &OutboxRelay{Published: 3},
+			wantPublished: events,
+		},
+		{
+			name: "the events of a plan after a failed one are held back",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockUnpublishedOutboxEvents(ctx, int32(limit)).Return(events, nil),
+					rm.EXPECT().MarkOutboxEventFailed(ctx, markFailed(created)).Return(created, nil),
+					rm.EXPECT().MarkOutboxEventPublished(ctx, markPublished(created2)).Return(created2, nil),
+				)
+			},
+			failPlan:      planID,
+			want:          &OutboxRelay{Published: 1, Failed: 1, HeldBack: 1},
+			wantPublished: []*outbox.Event{created2},
+		},
+		{
+			name: "LockUnpublishedOutboxEvents error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockUnpublishedOutboxEvents(ctx, int32(limit)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListUnpublishedOutboxEventsError{},
+		},
+		{
+			name: "MarkOutboxEventFailed error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockUnpublishedOutboxEvents(ctx, int32(limit)).Return(events, nil),
+					rm.EXPECT().MarkOutboxEventFailed(ctx, markFailed(created)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			failPlan: planID,
+			wantErr:  MarkOutboxEventFailedError{eventID: created.ID},
+		},
+		{
+			name: "MarkOutboxEventPublished error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockUnpublishedOutboxEvents(ctx, int32(limit)).Return(events, nil),
+					rm.EXPECT().MarkOutboxEventPublished(ctx, markPublished(created)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr:       MarkOutboxEventPublishedError{eventID: created.ID},
+			wantPublished: []*outbox.Event{created},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			publisher := outbox.NewMemoryPublisher()
+			publisher.Fail(tt.failPlan, errors.New("dummyErr"))
+
+			p := &PaymentServiceImp{repository: rm}
+			p.UseEventPublisher(publisher)
+
+			got, err := p.RelayOutboxEvents(ctx, now, limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.RelayOutboxEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.RelayOutboxEvents() = %v, want %v", got, tt.want)
+			}
+
+			published := publisher.Events()
+			if len(published) != len(tt.wantPublished) {
+				t.Fatalf("published %d events, want %d", len(published), len(tt.wantPublished))
+			}
+
+			for idx, event := range published {
+				if event.ID != tt.wantPublished[idx].ID {
+					t.Errorf("published event %d is %v, want %v", idx, event.ID, tt.wantPublished[idx].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestPaymentServiceImp_RelayOutboxEventsWithoutPublisher(t *testing.T) {
+	t.Parallel()
+
+	p := &PaymentServiceImp{repository: repomock.NewMockRepository(gomock.NewController(t))}
+
+	got, err := p.RelayOutboxEvents(context.Background(), time.Now(), 10)
+	if err != nil {
+		t.Fatalf("PaymentServiceImp.RelayOutboxEvents() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, &OutboxRelay{}) {
+		t.Errorf("PaymentServiceImp.RelayOutboxEvents() = %v, want nothing relayed", got)
+	}
+}
+
+// mustPlanEvent is the event recorded for plan
+func mustPlanEvent(eventType string, plan *payments.Plan) *outbox.CreateEventParams {
+	event, err := outbox.NewPlanEvent(eventType, plan)
+	if err != nil {
+		panic(err)
+	}
+
+	return event
+}
+
+// mustInstallmentEvent is the event recorded for inst
+func mustInstallmentEvent(eventType string, inst *payments.Installment) *outbox.CreateEventParams {
+	event, err := outbox.NewInstallmentEvent(eventType, inst)
+	if err != nil {
+		panic(err)
+	}
+
+	return event
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// UpdateInstallmentsPastDue moves the pending installments of complete plans past their due date to due,
+// and the due ones still unpaid once the grace period is over to overdue
+func (p *PaymentServiceImp) UpdateInstallmentsPastDue(
+	ctx context.Context,
+	now time.Time,
+	gracePeriod time.Duration,
+) (*InstallmentsPastDue, error) {
+	pastDue := &InstallmentsPastDue{}
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		if err := checkInstallmentsStatusTransition(PaymentInstallmentStatusPending, PaymentInstallmentStatusDue); err != nil {
+			return err
+		}
+
+		if err := checkInstallmentsStatusTransition(PaymentInstallmentStatusDue, PaymentInstallmentStatusOverdue); err != nil {
+			return err
+		}
+
+		// the plans are locked before any of their installments, in the order of their ids,
+		// so the run cannot deadlock with a unit of work changing one of them
+		planIDs, err := txRepo.LockPaymentPlansWithInstallmentsPastDue(ctx, &payments.LockPlansWithInstallmentsPastDueParams{
+			PlanStatus:    paymentPlanStatusComplete,
+			DueBefore:     now,
+			OverdueBefore: now.Add(-gracePeriod),
+		})
+		if err != nil {
+			return LockPaymentPlansPastDueError{}
+		}
+
+		var due, overdue []*payments.Installment
+
+		for _, planID := range planIDs {
+			planDue, err := txRepo.UpdatePaymentInstallmentsStatusDueBefore(
+				ctx,
+				&payments.UpdateInstallmentsStatusDueBeforeParams{
+					PlanID:     planID,
+					FromStatus: PaymentInstallmentStatusPending,
+					ToStatus:   PaymentInstallmentStatusDue,
+					DueBefore:  now,
+				},
+			)
+			if err != nil {
+				return UpdateInstallmentsPastDueError{status: PaymentInstallmentStatusDue}
+			}
+
+			// an installment already past the grace period goes through due and overdue in the same run
+			planOverdue, err := txRepo.UpdatePaymentInstallmentsStatusDueBefore(
+				ctx,
+				&payments.UpdateInstallmentsStatusDueBeforeParams{
+					PlanID:     planID,
+					FromStatus: PaymentInstallmentStatusDue,
+					ToStatus:   PaymentInstallmentStatusOverdue,
+					DueBefore:  now.Add(-gracePeriod),
+				},
+			)
+			if err != nil {
+				return UpdateInstallmentsPastDueError{status: PaymentInstallmentStatusOverdue}
+			}
+
+			due = append(due, planDue...)
+			overdue = append(overdue, planOverdue...)
+		}
+
+		for _, inst := range overdue {
+			if err := recordInstallmentEvent(ctx, txRepo, outbox.EventInstallmentOverdue, inst); err != nil {
+				return err
+			}
+		}
+
+		if err := recordInstallmentsPastDue(ctx, txRepo, due, overdue); err != nil {
+			return err
+		}
+
+		pastDue.Due = newPlanInstallments(due)
+		pastDue.Overdue = newPlanInstallments(overdue)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update installments past due: %w", err)
+	}
+
+	return pastDue, nil
+}
+
+// recordInstallmentsPastDue records one audit entry per plan, an installment both due and overdue
+// went from pending to overdue in the run
+func recordInstallmentsPastDue(
+	ctx context.Context,
+	repository repo.Repository,
+	due []*payments.Installment,
+	overdue []*payments.Installment,
+) error {
+	type planChange struct {
+		planID uuid.UUID
+		before []audit.InstallmentState
+		after  []audit.InstallmentState
+	}
+
+	var changes []*planChange
+
+	changesByPlan := make(map[uuid.UUID]*planChange)
+
+	record := func(inst *payments.Installment, fromStatus string) {
+		change, found := changesByPlan[inst.PaymentPlanID]
+		if !found {
+			change = &planChange{planID: inst.PaymentPlanID}
+			changesByPlan[inst.PaymentPlanID] = change
+			changes = append(changes, change)
+		}
+
+		for idx := range change.after {
+			if change.after[idx].ID == inst.ID {
+				change.after[idx] = audit.NewInstallmentState(inst)
+
+				return
+			}
+		}
+
+		before := audit.NewInstallmentState(inst)
+		before.Status = fromStatus
+
+		change.before = append(change.before, before)
+		change.after = append(change.after, audit.NewInstallmentState(inst))
+	}
+
+	for _, inst := range due {
+		record(inst, PaymentInstallmentStatusPending)
+	}
+
+	for _, inst := range overdue {
+		record(inst, PaymentInstallmentStatusDue)
+	}
+
+	for _, change := range changes {
+		if err := recordAuditEntry(
+			ctx, repository, change.planID, audit.ActionInstallmentsPastDue,
+			&audit.Snapshot{Installments: change.before}, &audit.Snapshot{Installments: change.after},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newPlanInstallments(installments []*payments.Installment) []PaymentPlanInstallment {
+	planInstallments := make([]PaymentPlanInstallment, 0, len(installments))
+
+	for _, inst := range installments {
+		planInstallments = append(planInstallments, PaymentPlanInstallment{
+			ID:      inst.ID.String(),
+			Amount:  inst.Amount,
+			DueAt:   inst.DueAt.Format(common.TimeFormat),
+			Status:  inst.Status,
+			Version: inst.Version,
+		})
+	}
+
+	return planInstallments
+}
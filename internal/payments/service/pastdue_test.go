@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_UpdateInstallmentsPastDue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx         = context.Background()
+		planID      = uuid.Must(uuid.NewV4())
+		now         = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		gracePeriod = 72 * time.Hour
+		lockParams  = &payments.LockPlansWithInstallmentsPastDueParams{
+			PlanStatus:    paymentPlanStatusComplete,
+			DueBefore:     now,
+			OverdueBefore: time.Date(2022, 7, 7, 0, 0, 0, 0, time.UTC),
+		}
+		dueParams = &payments.UpdateInstallmentsStatusDueBeforeParams{
+			PlanID:     planID,
+			FromStatus: PaymentInstallmentStatusPending,
+			ToStatus:   PaymentInstallmentStatusDue,
+			DueBefore:  now,
+		}
+		overdueParams = &payments.UpdateInstallmentsStatusDueBeforeParams{
+			PlanID:     planID,
+			FromStatus: PaymentInstallmentStatusDue,
+			ToStatus:   PaymentInstallmentStatusOverdue,
+			DueBefore:  time.Date(2022, 7, 7, 0, 0, 0, 0, time.UTC),
+		}
+		dueInstallment = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			DueAt:         time.Date(2022, 7, 9, 0, 0, 0, 0, time.UTC),
+			Status:        PaymentInstallmentStatusDue,
+		}
+		overdueInstallment = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+			DueAt:         time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+			Status:        PaymentInstallmentStatusOverdue,
+		}
+	)
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		want    *InstallmentsPastDue
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).Return([]uuid.UUID{planID}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, dueParams).
+						Return([]*payments.Installment{dueInstallment}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, overdueParams).
+						Return([]*payments.Installment{overdueInstallment}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, mustInstallmentEvent(outbox.EventInstallmentOverdue, overdueInstallment)).
+						Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			want: &InstallmentsPastDue{
+				Due: []PaymentPlanInstallment{
+					{
+						ID:     dueInstallment.ID.String(),
+						Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+						DueAt:  "2022-07-09T00:00:00Z",
+						Status: PaymentInstallmentStatusDue,
+					},
+				},
+				Overdue: []PaymentPlanInstallment{
+					{
+						ID:     overdueInstallment.ID.String(),
+						Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+						DueAt:  "2022-07-01T00:00:00Z",
+						Status: PaymentInstallmentStatusOverdue,
+					},
+				},
+			},
+		},
+		{
+			name: "no installment past due",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).Return(nil, nil),
+				)
+			},
+			want: &InstallmentsPastDue{
+				Due:     []PaymentPlanInstallment{},
+				Overdue: []PaymentPlanInstallment{},
+			},
+		},
+		{
+			name: "LockPaymentPlansWithInstallmentsPastDue error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).
+						Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: LockPaymentPlansPastDueError{},
+		},
+		{
+			name: "due update error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).Return([]uuid.UUID{planID}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, dueParams).
+						Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateInstallmentsPastDueError{status: PaymentInstallmentStatusDue},
+		},
+		{
+			name: "overdue update error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).Return([]uuid.UUID{planID}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, dueParams).
+						Return(nil, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, overdueParams).
+						Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateInstallmentsPastDueError{status: PaymentInstallmentStatusOverdue},
+		},
+		{
+			name: "CreateOutboxEvent error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).Return([]uuid.UUID{planID}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, dueParams).
+						Return(nil, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, overdueParams).
+						Return([]*payments.Installment{overdueInstallment}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateOutboxEventError{planID: planID, eventType: outbox.EventInstallmentOverdue},
+		},
+		{
+			name: "CreateAuditEntry error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlansWithInstallmentsPastDue(ctx, lockParams).Return([]uuid.UUID{planID}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, dueParams).
+						Return([]*payments.Installment{dueInstallment}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentsStatusDueBefore(ctx, overdueParams).
+						Return(nil, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateAuditEntryError{planID: planID, action: audit.ActionInstallmentsPastDue},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.UpdateInstallmentsPastDue(ctx, now, gracePeriod)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.UpdateInstallmentsPastDue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.UpdateInstallmentsPastDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
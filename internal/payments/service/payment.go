@@ -2,30 +2,44 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sort"
+	"time"
 
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
 	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
 	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/statemachine"
 
-	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
 )
 
 const (
-	paymentPlanStatusPending = "pending"
+	paymentPlanStatusPending   = statemachine.PlanPending
+	paymentPlanStatusComplete  = statemachine.PlanComplete
+	paymentPlanStatusCancelled = statemachine.PlanCancelled
+	paymentPlanStatusRefunded  = statemachine.PlanRefunded
 )
 
 const (
-	PaymentInstallmentStatusPending = "pending"
-	PaymentInstallmentStatusPaid    = "paid"
-	PaymentInstallmentStatusDue     = "due"
+	PaymentInstallmentStatusPending    = statemachine.InstallmentPending
+	PaymentInstallmentStatusPaid       = statemachine.InstallmentPaid
+	PaymentInstallmentStatusDue        = statemachine.InstallmentDue
+	PaymentInstallmentStatusOverdue    = statemachine.InstallmentOverdue
+	PaymentInstallmentStatusVoid       = statemachine.InstallmentVoid
+	PaymentInstallmentStatusSuperseded = statemachine.InstallmentSuperseded
 )
 
 var _ PaymentPlanService = (*PaymentServiceImp)(nil)
 
 type PaymentServiceImp struct {
-	repository repo.Repository
+	repository     repo.Repository
+	lateFeeRule    *LateFeeRule
+	eventPublisher outbox.EventPublisher
 }
 
 func NewPaymentPlanService() *PaymentServiceImp {
@@ -36,7 +50,11 @@ func (p *PaymentServiceImp) UseRepo(repository repo.Repository) {
 	p.repository = repository
 }
 
-func (p *PaymentServiceImp) GetPaymentPlanByUserID(ctx context.Context, userID uuid.UUID) ([]PaymentPlans, error) {
+func (p *PaymentServiceImp) GetPaymentPlanByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	withHistory bool,
+) ([]PaymentPlans, error) {
 	plans, err := p.repository.ListPaymentPlansByUserID(ctx, userID)
 	if err != nil {
 		return nil, ListPaymentPlansByUserIDError{userID: userID}
@@ -45,36 +63,16 @@ func (p *PaymentServiceImp) GetPaymentPlanByUserID(ctx context.Context, userID u
 	paymentPlans := make([]PaymentPlans, 0, len(plans))
 
 	for _, plan := range plans {
-		paymentPlan := PaymentPlans{
-			ID:          plan.ID.String(),
-			UserID:      plan.UserID.String(),
-			Currency:    plan.Currency,
-			TotalAmount: plan.Amount.String(),
-			Status:      plan.Status,
-			CreatedAt:   plan.CreatedAt.Format(common.TimeFormat),
-		}
-
-		installments, err := p.repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+		paymentPlan, err := readPaymentPlan(ctx, p.repository, plan)
 		if err != nil {
-			return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+			return nil, err
 		}
 
-		planInstallments := make([]PaymentPlanInstallment, 0, len(installments))
-
-		for _, inst := range installments {
-			planInst := PaymentPlanInstallment{
-				ID:       inst.ID.String(),
-				Amount:   inst.Amount.String(),
-				Currency: inst.Currency,
-				DueAt:    inst.DueAt.Format(common.TimeFormat),
-				Status:   inst.Status,
-			}
-			planInstallments = append(planInstallments, planInst)
+		if !withHistory {
+			paymentPlan.History = nil
 		}
 
-		paymentPlan.Installments = planInstallments
-
-		paymentPlans = append(paymentPlans, paymentPlan)
+		paymentPlans = append(paymentPlans, *paymentPlan)
 	}
 
 	return paymentPlans, nil
@@ -84,113 +82,557 @@ func (p *PaymentServiceImp) CreatePendingPaymentPlan(
 	ctx context.Context,
 	paymentPlan *CreatePaymentPlanParams,
 ) (*PaymentPlans, error) {
-	totalAmount := decimal.Big{}
-	totalAmount.SetString(paymentPlan.TotalAmount)
-
-	plan, err := p.repository.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
-		UserID:   paymentPlan.UserID,
-		Currency: paymentPlan.Currency,
-		Amount:   totalAmount,
-		Status:   paymentPlanStatusPending,
+	var newPlan *PaymentPlans
+
+	// the plan and its installments are persisted together or not at all
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		newPlan, txErr = createPendingPaymentPlan(ctx, txRepo, paymentPlan)
+
+		return txErr
 	})
+	if errors.As(err, &PaymentPlanIDTakenError{}) {
+		// a concurrent request with the same ID created the plan first, it is read again
+		// once the unit of work which failed is over
+		var found bool
+
+		newPlan, found, err = replayPaymentPlanCreation(ctx, p.repository, paymentPlan)
+		if err == nil && !found {
+			err = CreatePaymentPlanError{}
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("create pending payment plan: %w", err)
+	}
+
+	return newPlan, nil
+}
+
+func createPendingPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlan *CreatePaymentPlanParams,
+) (*PaymentPlans, error) {
+	// a client retrying with the ID it already used gets the plan created the first time,
+	// even once its due dates have passed since
+	if replayedPlan, found, err := replayPaymentPlanCreation(ctx, repository, paymentPlan); err != nil || found {
+		return replayedPlan, err
+	}
+
+	if paymentPlan.MerchantID == uuid.Nil {
+		return nil, MissingMerchantError{}
+	}
+
+	now := time.Now().UTC()
+
+	paymentPlan, err := withScheduledInstallments(paymentPlan, now)
 	if err != nil {
-		return nil, CreatePaymentPlanError{}
+		return nil, err
+	}
+
+	// the client is told about every broken rule at once, not just the first one
+	if err := joinValidationErrors(
+		validateCreatePaymentPlanParams(paymentPlan, now),
+		validatePaymentPlanOrder(paymentPlan),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := checkMerchantExists(ctx, repository, paymentPlan.MerchantID); err != nil {
+		return nil, err
+	}
+
+	if err := checkOrderReferenceUnused(ctx, repository, paymentPlan.MerchantID, paymentPlan.OrderReference); err != nil {
+		return nil, err
+	}
+
+	if err := reserveCredit(ctx, repository, paymentPlan.UserID, paymentPlan.TotalAmount); err != nil {
+		return nil, err
+	}
+
+	plan, err := repository.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		ID:             paymentPlan.ID,
+		UserID:         paymentPlan.UserID,
+		MerchantID:     paymentPlan.MerchantID,
+		Amount:         paymentPlan.TotalAmount,
+		Status:         paymentPlanStatusPending,
+		OrderReference: paymentPlan.OrderReference,
+		Description:    paymentPlan.Description,
+	})
+	if err != nil {
+		var duplicateKey repo.DuplicateKeyError
+		if !errors.As(err, &duplicateKey) {
+			return nil, CreatePaymentPlanError{}
+		}
+
+		switch duplicateKey.Constraint {
+		case repo.ConstraintPaymentPlansPkey:
+			return nil, PaymentPlanIDTakenError{planID: paymentPlan.ID}
+		case repo.ConstraintPaymentPlansMerchantOrderRefIdx:
+			// a concurrent request for the same order created its plan after the order reference was checked
+			return nil, OrderReferenceConflictError{
+				merchantID:     paymentPlan.MerchantID,
+				orderReference: paymentPlan.OrderReference,
+			}
+		default:
+			return nil, CreatePaymentPlanError{}
+		}
 	}
 
 	newPlan := &PaymentPlans{
-		ID:          plan.ID.String(),
-		UserID:      plan.UserID.String(),
-		Currency:    plan.Currency,
-		TotalAmount: plan.Amount.String(),
-		Status:      plan.Status,
-		CreatedAt:   plan.CreatedAt.Format(common.TimeFormat),
+		ID:             plan.ID.String(),
+		UserID:         plan.UserID.String(),
+		MerchantID:     planMerchantID(plan),
+		OrderReference: plan.OrderReference,
+		Description:    plan.Description,
+		TotalAmount:    plan.Amount,
+		Status:         plan.Status,
+		CreatedAt:      plan.CreatedAt.Format(common.TimeFormat),
+	}
+
+	lineItems := make([]*payments.LineItem, 0, len(paymentPlan.LineItems))
+
+	for idx, item := range paymentPlan.LineItems {
+		lineItem, err := repository.CreatePaymentPlanLineItem(ctx, &payments.CreateLineItemParams{
+			PaymentPlanID: plan.ID,
+			Position:      int32(idx),
+			SKU:           item.SKU,
+			Name:          item.Name,
+			Quantity:      item.Quantity,
+			UnitPrice:     item.UnitPrice,
+		})
+		if err != nil {
+			return nil, CreatePaymentPlanLineItemError{planID: plan.ID}
+		}
+
+		lineItems = append(lineItems, lineItem)
 	}
 
-	sort.SliceStable(paymentPlan.Installments, func(i, j int) bool {
-		return paymentPlan.Installments[i].DueAt.Unix() < paymentPlan.Installments[j].DueAt.Unix()
+	newPlan.LineItems = newPlanLineItems(lineItems)
+
+	sortedInstallments := make([]PaymentPlanInstallmentParams, len(paymentPlan.Installments))
+	copy(sortedInstallments, paymentPlan.Installments)
+
+	sort.SliceStable(sortedInstallments, func(i, j int) bool {
+		return sortedInstallments[i].DueAt.Unix() < sortedInstallments[j].DueAt.Unix()
 	})
 
-	for _, inst := range paymentPlan.Installments {
-		amount := decimal.Big{}
-		amount.SetString(inst.Amount)
+	installments := make([]*payments.Installment, 0, len(sortedInstallments))
 
-		installment, err := p.repository.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+	for _, inst := range sortedInstallments {
+		installment, err := repository.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
 			PaymentPlanID: plan.ID,
-			Currency:      inst.Currency,
-			Amount:        amount,
+			Amount:        inst.Amount,
 			DueAt:         inst.DueAt,
 			Status:        PaymentInstallmentStatusPending,
+			Version:       firstScheduleVersion,
 		})
 		if err != nil {
 			return nil, CreatePaymentInstallmentError{}
 		}
 
+		installments = append(installments, installment)
+
 		newInst := PaymentPlanInstallment{
-			ID:       installment.ID.String(),
-			Amount:   installment.Amount.String(),
-			Currency: installment.Currency,
-			DueAt:    installment.DueAt.Format(common.TimeFormat),
-			Status:   installment.Status,
+			ID:      installment.ID.String(),
+			Amount:  installment.Amount,
+			DueAt:   installment.DueAt.Format(common.TimeFormat),
+			Status:  installment.Status,
+			Version: installment.Version,
 		}
 
 		newPlan.Installments = append(newPlan.Installments, newInst)
 	}
 
+	// the user owes the plan amount and it is owed in turn to the merchant
+	if err := postJournalEntry(ctx, repository, ledger.NewTransfer(
+		plan.ID, ledger.EntryPlanCreated, ledger.AccountUserReceivable, ledger.AccountMerchantPayable, plan.Amount,
+	)); err != nil {
+		return nil, err
+	}
+
+	if err := recordPlanEvent(ctx, repository, outbox.EventPlanCreated, plan); err != nil {
+		return nil, err
+	}
+
+	if err := recordAuditEntry(
+		ctx, repository, plan.ID, audit.ActionPlanCreated, nil, audit.NewPlanSnapshot(plan, installments),
+	); err != nil {
+		return nil, err
+	}
+
 	return newPlan, nil
 }
 
-// CompletePaymentPlanCreation Complete and paid the record of the first installments
+// replayPaymentPlanCreation replays the creation of the plan with the ID of the request, found is false
+// when the request has no ID or no plan has it yet
+func replayPaymentPlanCreation(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlan *CreatePaymentPlanParams,
+) (*PaymentPlans, bool, error) {
+	if paymentPlan.ID == uuid.Nil {
+		return nil, false, nil
+	}
+
+	existing, err := repository.GetPaymentPlanByID(ctx, paymentPlan.ID)
+
+	switch {
+	case errors.Is(err, repo.ErrRecordNotFound):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, GetPaymentPlanByIDError{planID: paymentPlan.ID}
+	}
+
+	replayedPlan, err := replayPendingPaymentPlanCreation(ctx, repository, existing, paymentPlan)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return replayedPlan, true, nil
+}
+
+// replayPendingPaymentPlanCreation returns the existing plan when the request carries
+// the payload it was created with, whatever its status is by now
+func replayPendingPaymentPlanCreation(
+	ctx context.Context,
+	repository repo.Repository,
+	plan *payments.Plan,
+	paymentPlan *CreatePaymentPlanParams,
+) (*PaymentPlans, error) {
+	// the installments of a schedule are generated as they were when the plan was created
+	paymentPlan, err := withScheduledInstallments(paymentPlan, plan.CreatedAt)
+	if err != nil {
+		return nil, PaymentPlanConflictError{planID: plan.ID}
+	}
+
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	lineItems, err := repository.ListPaymentPlanLineItemsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentPlanLineItemsByPlanIDError{planID: plan.ID}
+	}
+
+	if !isSamePaymentPlan(plan, installments, paymentPlan) || !isSameOrder(plan, lineItems, paymentPlan) {
+		return nil, PaymentPlanConflictError{planID: plan.ID}
+	}
+
+	lateFees, err := repository.ListPaymentLateFeesByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentLateFeesByPlanIDError{planID: plan.ID}
+	}
+
+	replayedPlan := newPaymentPlans(plan, installments, lateFees)
+	replayedPlan.LineItems = newPlanLineItems(lineItems)
+
+	return replayedPlan, nil
+}
+
+// isSameOrder compares the line items in the order they were given
+func isSameOrder(plan *payments.Plan, lineItems []*payments.LineItem, paymentPlan *CreatePaymentPlanParams) bool {
+	if plan.OrderReference != paymentPlan.OrderReference ||
+		plan.Description != paymentPlan.Description ||
+		len(lineItems) != len(paymentPlan.LineItems) {
+		return false
+	}
+
+	for idx, requested := range paymentPlan.LineItems {
+		stored := lineItems[idx]
+
+		if stored.SKU != requested.SKU ||
+			stored.Name != requested.Name ||
+			stored.Quantity != requested.Quantity ||
+			!stored.UnitPrice.Equal(requested.UnitPrice) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkOrderReferenceUnused a plan created without an order reference does not collide with any other
+func checkOrderReferenceUnused(
+	ctx context.Context,
+	repository repo.Repository,
+	merchantID uuid.UUID,
+	orderReference string,
+) error {
+	if orderReference == "" {
+		return nil
+	}
+
+	_, err := repository.GetPaymentPlanByOrderReference(ctx, &payments.GetPlanByOrderReferenceParams{
+		MerchantID:     merchantID,
+		OrderReference: orderReference,
+	})
+
+	switch {
+	case err == nil:
+		return OrderReferenceConflictError{merchantID: merchantID, orderReference: orderReference}
+	case errors.Is(err, repo.ErrRecordNotFound):
+		return nil
+	default:
+		return GetPaymentPlanByOrderReferenceError{merchantID: merchantID, orderReference: orderReference}
+	}
+}
+
+// readPaymentPlan lists the installments, the late fees and the line items of a plan read back
+func readPaymentPlan(ctx context.Context, repository repo.Repository, plan *payments.Plan) (*PaymentPlans, error) {
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	lateFees, err := repository.ListPaymentLateFeesByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentLateFeesByPlanIDError{planID: plan.ID}
+	}
+
+	lineItems, err := repository.ListPaymentPlanLineItemsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentPlanLineItemsByPlanIDError{planID: plan.ID}
+	}
+
+	paymentPlan := newPaymentPlans(plan, installments, lateFees)
+	paymentPlan.LineItems = newPlanLineItems(lineItems)
+
+	return paymentPlan, nil
+}
+
+// newPlanLineItems is nil when the plan has no line item
+func newPlanLineItems(lineItems []*payments.LineItem) []PaymentPlanLineItem {
+	if len(lineItems) == 0 {
+		return nil
+	}
+
+	planLineItems := make([]PaymentPlanLineItem, 0, len(lineItems))
+
+	for _, lineItem := range lineItems {
+		planLineItems = append(planLineItems, PaymentPlanLineItem{
+			SKU:       lineItem.SKU,
+			Name:      lineItem.Name,
+			Quantity:  lineItem.Quantity,
+			UnitPrice: lineItem.UnitPrice,
+		})
+	}
+
+	return planLineItems
+}
+
+// isSamePaymentPlan compares installments regardless of their order,
+// due dates are compared at the microsecond precision they are stored with
+func isSamePaymentPlan(
+	plan *payments.Plan,
+	installments []*payments.Installment,
+	paymentPlan *CreatePaymentPlanParams,
+) bool {
+	if plan.UserID != paymentPlan.UserID ||
+		plan.MerchantID != paymentPlan.MerchantID ||
+		!plan.Amount.Equal(paymentPlan.TotalAmount) ||
+		len(installments) != len(paymentPlan.Installments) {
+		return false
+	}
+
+	matched := make([]bool, len(installments))
+
+	for _, requested := range paymentPlan.Installments {
+		found := false
+
+		for storedIdx, stored := range installments {
+			if matched[storedIdx] ||
+				!stored.Amount.Equal(requested.Amount) ||
+				!stored.DueAt.Round(time.Microsecond).Equal(requested.DueAt.Round(time.Microsecond)) {
+				continue
+			}
+
+			matched[storedIdx] = true
+			found = true
+
+			break
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newPaymentPlans lists every late fee under the installment it was assessed on,
+// the installments superseded by reschedules are listed apart as history
+func newPaymentPlans(
+	plan *payments.Plan,
+	installments []*payments.Installment,
+	lateFees []*payments.LateFee,
+) *PaymentPlans {
+	current, superseded := splitSupersededInstallments(installments)
+
+	paymentPlan := &PaymentPlans{
+		ID:             plan.ID.String(),
+		UserID:         plan.UserID.String(),
+		MerchantID:     planMerchantID(plan),
+		OrderReference: plan.OrderReference,
+		Description:    plan.Description,
+		TotalAmount:    plan.Amount,
+		Status:         plan.Status,
+		CreatedAt:      plan.CreatedAt.Format(common.TimeFormat),
+		Installments:   newPlanInstallments(current),
+	}
+
+	if len(superseded) != 0 {
+		paymentPlan.History = newPlanInstallments(superseded)
+	}
+
+	if len(lateFees) == 0 {
+		return paymentPlan
+	}
+
+	totalLateFees := lateFees[0].Amount
+
+	for _, lateFee := range lateFees[1:] {
+		// the late fees of a plan are all in its currency, one which is not is left out of the total
+		if sum, err := totalLateFees.Add(lateFee.Amount); err == nil {
+			totalLateFees = sum
+		}
+	}
+
+	for _, lateFee := range lateFees {
+		if !attachLateFee(paymentPlan.Installments, lateFee) {
+			attachLateFee(paymentPlan.History, lateFee)
+		}
+	}
+
+	paymentPlan.TotalLateFees = &totalLateFees
+
+	return paymentPlan
+}
+
+// planMerchantID is empty for the plans created before merchants were recorded
+func planMerchantID(plan *payments.Plan) string {
+	if plan.MerchantID == uuid.Nil {
+		return ""
+	}
+
+	return plan.MerchantID.String()
+}
+
+func attachLateFee(planInstallments []PaymentPlanInstallment, lateFee *payments.LateFee) bool {
+	for idx := range planInstallments {
+		if planInstallments[idx].ID == lateFee.PaymentInstallmentID.String() {
+			planInstallments[idx].LateFees = append(planInstallments[idx].LateFees, newPlanLateFee(lateFee))
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitSupersededInstallments separates the current schedule from the installments replaced by reschedules
+func splitSupersededInstallments(
+	installments []*payments.Installment,
+) (current []*payments.Installment, superseded []*payments.Installment) {
+	current = make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if inst.Status == PaymentInstallmentStatusSuperseded {
+			superseded = append(superseded, inst)
+
+			continue
+		}
+
+		current = append(current, inst)
+	}
+
+	return current, superseded
+}
+
+// CompletePaymentPlanCreation Complete the pending plan and paid the record of the first installment
 func (p *PaymentServiceImp) CompletePaymentPlanCreation(
 	ctx context.Context,
 	paymentPlanID uuid.UUID,
 	paymentPlan *CompletePaymentPlanParams,
 ) (*PaymentPlans, error) {
-	var retPlan *PaymentPlans
+	var completedPlan *PaymentPlans
+
+	// settling the first installment and completing the plan go together
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		completedPlan, txErr = completePaymentPlan(ctx, txRepo, paymentPlanID, paymentPlan)
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("complete payment plan: %w", err)
+	}
+
+	return completedPlan, nil
+}
+
+func completePaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	paymentPlan *CompletePaymentPlanParams,
+) (*PaymentPlans, error) {
+	// the plan stays locked so it cannot be cancelled or expired while it is being completed
+	plan, err := lockUserPaymentPlan(ctx, repository, paymentPlanID, paymentPlan.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPaymentPlanStatusTransition(plan, paymentPlanStatusComplete); err != nil {
+		return nil, err
+	}
 
-	plans, err := p.repository.ListPaymentPlansByUserID(ctx, paymentPlan.UserID)
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
 	if err != nil {
-		return nil, ListPaymentPlansByUserIDError{userID: paymentPlan.UserID}
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
 	}
 
-	for _, plan := range plans {
-		if plan.ID != paymentPlanID {
-			continue
-		}
+	before := audit.NewPlanSnapshot(plan, installments)
+	after := audit.NewPlanSnapshot(plan, installments)
+	firstIdx := -1
 
-		paymentPlan := PaymentPlans{
-			ID:          plan.ID.String(),
-			UserID:      plan.UserID.String(),
-			Currency:    plan.Currency,
-			TotalAmount: plan.Amount.String(),
-			Status:      plan.Status,
-			CreatedAt:   plan.CreatedAt.Format(common.TimeFormat),
+	for idx, inst := range installments {
+		if firstIdx < 0 || inst.DueAt.Before(installments[firstIdx].DueAt) {
+			firstIdx = idx
 		}
+	}
 
-		installments, err := p.repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if firstIdx >= 0 {
+		paidInst, err := updatePaymentInstallmentStatus(ctx, repository, installments[firstIdx], PaymentInstallmentStatusPaid)
 		if err != nil {
-			return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+			return nil, err
 		}
 
-		planInstallments := make([]PaymentPlanInstallment, 0, len(installments))
-
-		for _, inst := range installments {
-			planInst := PaymentPlanInstallment{
-				ID:       inst.ID.String(),
-				Amount:   inst.Amount.String(),
-				Currency: inst.Currency,
-				DueAt:    inst.DueAt.Format(common.TimeFormat),
-				Status:   inst.Status,
-			}
+		after.Installments[firstIdx] = audit.NewInstallmentState(paidInst)
 
-			planInstallments = append(planInstallments, planInst)
+		if err := postJournalEntry(ctx, repository, ledger.NewTransfer(
+			plan.ID, ledger.EntryInstallmentPaid, ledger.AccountCash, ledger.AccountUserReceivable,
+			installments[firstIdx].Amount,
+		)); err != nil {
+			return nil, err
 		}
+	}
 
-		paymentPlan.Installments = planInstallments
+	completedPlan, err := updatePaymentPlanStatus(ctx, repository, plan, paymentPlanStatusComplete)
+	if err != nil {
+		return nil, err
+	}
 
-		retPlan = &paymentPlan
+	after.Plan = audit.NewPlanState(completedPlan)
 
-		return retPlan, nil
+	if err := recordAuditEntry(ctx, repository, plan.ID, audit.ActionPlanCompleted, before, after); err != nil {
+		return nil, err
 	}
 
-	return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+	return readPaymentPlan(ctx, repository, completedPlan)
 }
@@ -2,14 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
 	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
 	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
 	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
 	"golangreferenceapi/internal/payments/repo/sqlc"
 
 	"github.com/ericlagergren/decimal"
@@ -21,7 +26,7 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 	t.Parallel()
 
 	var (
-		decimalAmount = *decimal.New(1000, 0)
+		decimalAmount = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
 		userID        = uuid.Must(uuid.NewV4())
 		planID        = uuid.Must(uuid.NewV4())
 		installmentID = uuid.Must(uuid.NewV4())
@@ -35,7 +40,6 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 			{
 				ID:        planID,
 				UserID:    userID,
-				Currency:  currency,
 				Amount:    decimalAmount,
 				Status:    status,
 				CreatedAt: createdAt,
@@ -46,7 +50,6 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 			{
 				ID:            installmentID,
 				PaymentPlanID: planID,
-				Currency:      currency,
 				Amount:        decimalAmount,
 				DueAt:         dueAt,
 				Status:        status,
@@ -58,17 +61,89 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 			{
 				ID:          planID.String(),
 				UserID:      userID.String(),
-				Currency:    currency,
-				TotalAmount: decimalAmount.String(),
+				TotalAmount: decimalAmount,
 				Status:      status,
 				CreatedAt:   createdAt.Format(common.TimeFormat),
+				Installments: []PaymentPlanInstallment{
+					{
+						ID:     installmentID.String(),
+						Amount: decimalAmount,
+						DueAt:  dueAt.Format(common.TimeFormat),
+						Status: status,
+					},
+				},
+			},
+		}
+		supersededInstallmentID = uuid.Must(uuid.NewV4())
+		rescheduledInstallments = []*payments.Installment{
+			{
+				ID:            supersededInstallmentID,
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         dueAt,
+				Status:        PaymentInstallmentStatusSuperseded,
+				Version:       1,
+			},
+			paymentInstallments[0],
+		}
+		paymentPlanWithHistoryResponse = []PaymentPlans{
+			{
+				ID:           planID.String(),
+				UserID:       userID.String(),
+				TotalAmount:  decimalAmount,
+				Status:       status,
+				CreatedAt:    createdAt.Format(common.TimeFormat),
+				Installments: paymentPlanResponse[0].Installments,
+				History: []PaymentPlanInstallment{
+					{
+						ID:      supersededInstallmentID.String(),
+						Amount:  decimalAmount,
+						DueAt:   dueAt.Format(common.TimeFormat),
+						Status:  PaymentInstallmentStatusSuperseded,
+						Version: 1,
+					},
+				},
+			},
+		}
+		lateFeeID     = uuid.Must(uuid.NewV4())
+		lateFeeAmount = payments.MustNewMoney(decimal.New(15, 0), "usdc")
+		totalLateFees = payments.MustNewMoney(decimal.New(30, 0), "usdc")
+		assessedAt, _ = time.Parse(common.TimeFormat, "2021-11-20T23:00:00Z")
+		lateFees      = []*payments.LateFee{
+			{
+				ID:                   lateFeeID,
+				PaymentInstallmentID: installmentID,
+				Amount:               lateFeeAmount,
+				AssessedAt:           assessedAt,
+			},
+			{
+				ID:                   lateFeeID,
+				PaymentInstallmentID: installmentID,
+				Amount:               lateFeeAmount,
+				AssessedAt:           assessedAt,
+			},
+		}
+		planLateFee = PaymentPlanLateFee{
+			ID:            lateFeeID.String(),
+			InstallmentID: installmentID.String(),
+			Amount:        payments.MustNewMoney(decimal.New(15, 0), currency),
+			AssessedAt:    assessedAt.Format(common.TimeFormat),
+		}
+		paymentPlanWithLateFeesResponse = []PaymentPlans{
+			{
+				ID:            planID.String(),
+				UserID:        userID.String(),
+				TotalAmount:   decimalAmount,
+				Status:        status,
+				CreatedAt:     createdAt.Format(common.TimeFormat),
+				TotalLateFees: &totalLateFees,
 				Installments: []PaymentPlanInstallment{
 					{
 						ID:       installmentID.String(),
-						Amount:   decimalAmount.String(),
-						Currency: currency,
+						Amount:   decimalAmount,
 						DueAt:    dueAt.Format(common.TimeFormat),
 						Status:   status,
+						LateFees: []PaymentPlanLateFee{planLateFee, planLateFee},
 					},
 				},
 			},
@@ -76,7 +151,8 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 	)
 
 	type args struct {
-		userID uuid.UUID
+		userID      uuid.UUID
+		withHistory bool
 	}
 
 	tests := []struct {
@@ -87,43 +163,996 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "happy path",
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			want:    paymentPlanResponse,
+			wantErr: false,
+		},
+		{
+			name: "happy path with late fees",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, gomock.Eq(planID)).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			want:    paymentPlanWithLateFeesResponse,
+			wantErr: false,
+		},
+		{
+			name: "superseded installments are left out by default",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(rescheduledInstallments, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			want:    paymentPlanResponse,
+			wantErr: false,
+		},
+		{
+			name: "superseded installments are listed as history on request",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(rescheduledInstallments, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+				)
+			},
+			args: args{
+				userID:      userID,
+				withHistory: true,
+			},
+			want:    paymentPlanWithHistoryResponse,
+			wantErr: false,
+		},
+		{
+			name: "ListPaymentPlansByUserID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ListPaymentInstallmentsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ListPaymentLateFeesByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, gomock.Eq(planID)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ListPaymentPlanLineItemsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, gomock.Eq(planID)).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, gomock.Eq(planID)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				userID: userID,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := repomock.NewMockRepository(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(repo)
+			}
+
+			p := &PaymentServiceImp{repository: repo}
+
+			got, err := p.GetPaymentPlanByUserID(ctx, tt.args.userID, tt.args.withHistory)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PaymentServiceImp.GetPaymentPlanByUserID() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.GetPaymentPlanByUserID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceImp_CreatePendingPaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		decimalAmount  = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		totalAmount    = payments.MustNewMoney(decimal.New(2000, 0), "usdc")
+		userID         = uuid.Must(uuid.NewV4())
+		merchantID     = uuid.Must(uuid.NewV4())
+		planID         = uuid.Must(uuid.NewV4())
+		installmentID  = uuid.Must(uuid.NewV4())
+		installmentID2 = uuid.Must(uuid.NewV4())
+		ctx            = context.Background()
+		createdAt, _   = time.Parse(common.TimeFormat, "2021-10-10T23:00:00Z")
+		updatedAt, _   = time.Parse(common.TimeFormat, "2021-10-10T23:00:00Z")
+		dueAt          = time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+		status         = "pending"
+
+		creditLineMock = &payments.CreditLine{
+			ID:     uuid.Must(uuid.NewV4()),
+			UserID: userID,
+			Limit:  payments.MustNewMoney(decimal.New(5000, 0), "usdc"),
+			Status: CreditLineStatusActive,
+		}
+
+		outstandingParamMock = &payments.UserOutstandingAmountParams{UserID: userID, Currency: "usdc"}
+
+		paymentPlanParamMock = &payments.CreatePlanParams{
+			UserID:     userID,
+			MerchantID: merchantID,
+			Amount:     totalAmount,
+			Status:     status,
+		}
+
+		paymentPlanMock = &payments.Plan{
+			ID:         planID,
+			UserID:     userID,
+			MerchantID: merchantID,
+			Amount:     totalAmount,
+			Status:     status,
+			CreatedAt:  createdAt,
+			UpdatedAt:  updatedAt,
+		}
+
+		merchantMock = &payments.Merchant{ID: merchantID, Name: "Acme"}
+
+		paymentInstallmentParamMock = []*payments.CreateInstallmentParams{
+			{
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         dueAt,
+				Status:        status,
+				Version:       1,
+			},
+			{
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         dueAt.Add(1 * time.Hour),
+				Status:        status,
+				Version:       1,
+			},
+		}
+
+		paymentInstallmentMock = []*payments.Installment{
+			{
+				ID:            installmentID,
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         dueAt,
+				Status:        status,
+				CreatedAt:     createdAt,
+				UpdatedAt:     updatedAt,
+			},
+			{
+				ID:            installmentID2,
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         dueAt.Add(1 * time.Hour),
+				Status:        status,
+				CreatedAt:     createdAt,
+				UpdatedAt:     updatedAt,
+			},
+		}
+
+		paymentPlanParams = &CreatePaymentPlanParams{
+			UserID:      userID,
+			MerchantID:  merchantID,
+			TotalAmount: payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+			Installments: []PaymentPlanInstallmentParams{
+				{
+					Amount: payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+					DueAt:  dueAt,
+				},
+				{
+					Amount: payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+					DueAt:  dueAt.Add(1 * time.Hour),
+				},
+			},
+		}
+
+		paymentPlanWithIDParams = &CreatePaymentPlanParams{
+			ID:           planID,
+			UserID:       userID,
+			MerchantID:   merchantID,
+			TotalAmount:  payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+			Installments: paymentPlanParams.Installments,
+		}
+
+		// a schedule started after the plan was created and before now
+		scheduleStartAt = createdAt.Add(24 * time.Hour)
+
+		scheduledInstallmentMock = []*payments.Installment{
+			{
+				ID:            installmentID,
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         scheduleStartAt,
+				Status:        status,
+				CreatedAt:     createdAt,
+				UpdatedAt:     updatedAt,
+			},
+			{
+				ID:            installmentID2,
+				PaymentPlanID: planID,
+				Amount:        decimalAmount,
+				DueAt:         scheduleStartAt.AddDate(0, 1, 0),
+				Status:        status,
+				CreatedAt:     createdAt,
+				UpdatedAt:     updatedAt,
+			},
+		}
+
+		planCreatedEntryMock = ledger.NewTransfer(
+			planID, ledger.EntryPlanCreated, ledger.AccountUserReceivable, ledger.AccountMerchantPayable, totalAmount,
+		)
+
+		planCreatedEventMock = mustPlanEvent(outbox.EventPlanCreated, paymentPlanMock)
+
+		orderReference = "order-42"
+		unitPrice      = payments.MustNewMoney(decimal.New(2000, 0), "usdc")
+
+		orderPlanParams = &CreatePaymentPlanParams{
+			UserID:         userID,
+			MerchantID:     merchantID,
+			TotalAmount:    totalAmount,
+			OrderReference: orderReference,
+			Description:    "a chair",
+			LineItems: []PaymentPlanLineItemParams{
+				{SKU: "CH-1", Name: "Chair", Quantity: 1, UnitPrice: unitPrice},
+			},
+			Installments: paymentPlanParams.Installments,
+		}
+
+		orderPlanParamMock = &payments.CreatePlanParams{
+			UserID:         userID,
+			MerchantID:     merchantID,
+			Amount:         totalAmount,
+			Status:         status,
+			OrderReference: orderReference,
+			Description:    "a chair",
+		}
+
+		orderPlanMock = &payments.Plan{
+			ID:             planID,
+			UserID:         userID,
+			MerchantID:     merchantID,
+			Amount:         totalAmount,
+			Status:         status,
+			OrderReference: orderReference,
+			Description:    "a chair",
+			CreatedAt:      createdAt,
+			UpdatedAt:      updatedAt,
+		}
+
+		orderLookupParamMock = &payments.GetPlanByOrderReferenceParams{
+			MerchantID:     merchantID,
+			OrderReference: orderReference,
+		}
+
+		lineItemParamMock = &payments.CreateLineItemParams{
+			PaymentPlanID: planID,
+			SKU:           "CH-1",
+			Name:          "Chair",
+			Quantity:      1,
+			UnitPrice:     unitPrice,
+		}
+
+		lineItemMock = &payments.LineItem{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: planID,
+			SKU:           "CH-1",
+			Name:          "Chair",
+			Quantity:      1,
+			UnitPrice:     unitPrice,
+			CreatedAt:     createdAt,
+			UpdatedAt:     updatedAt,
+		}
+
+		orderPlanCreatedEventMock = mustPlanEvent(outbox.EventPlanCreated, orderPlanMock)
+
+		paymentPlanResponse = &PaymentPlans{
+			ID:          planID.String(),
+			UserID:      userID.String(),
+			MerchantID:  merchantID.String(),
+			TotalAmount: totalAmount,
+			Status:      status,
+			CreatedAt:   createdAt.Format(common.TimeFormat),
+			Installments: []PaymentPlanInstallment{
+				{
+					ID:     installmentID.String(),
+					Amount: decimalAmount,
+					DueAt:  dueAt.Format(common.TimeFormat),
+					Status: status,
+				},
+				{
+					ID:     installmentID2.String(),
+					Amount: decimalAmount,
+					DueAt:  dueAt.Add(1 * time.Hour).Format(common.TimeFormat),
+					Status: status,
+				},
+			},
+		}
+	)
+
+	orderPlanResponse := *paymentPlanResponse
+	orderPlanResponse.OrderReference = orderReference
+	orderPlanResponse.Description = "a chair"
+	orderPlanResponse.LineItems = []PaymentPlanLineItem{
+		{SKU: "CH-1", Name: "Chair", Quantity: 1, UnitPrice: unitPrice},
+	}
+
+	type args struct {
+		createPaymentPlanParams *CreatePaymentPlanParams
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		args    args
+		want    *PaymentPlans
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(planCreatedEntryMock)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(planCreatedEventMock)).Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			want: paymentPlanResponse,
+		},
+		{
+			name: "unsorted installments are created by due date",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(planCreatedEntryMock)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(planCreatedEventMock)).Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					UserID:      userID,
+					MerchantID:  merchantID,
+					TotalAmount: payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+					Installments: []PaymentPlanInstallmentParams{
+						paymentPlanParams.Installments[1],
+						paymentPlanParams.Installments[0],
+					},
+				},
+			},
+			want: paymentPlanResponse,
+		},
+		{
+			name: "order reference and line items are recorded",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, orderLookupParamMock).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(orderPlanParamMock)).Return(orderPlanMock, nil),
+					rm.EXPECT().CreatePaymentPlanLineItem(ctx, gomock.Eq(lineItemParamMock)).Return(lineItemMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(planCreatedEntryMock)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(orderPlanCreatedEventMock)).Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: orderPlanParams,
+			},
+			want: &orderPlanResponse,
+		},
+		{
+			name: "order reference already used by the merchant",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, orderLookupParamMock).Return(orderPlanMock, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: orderPlanParams,
+			},
+			wantErr: OrderReferenceConflictError{merchantID: merchantID, orderReference: orderReference},
+		},
+		{
+			name: "order reference taken by a concurrent request",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, orderLookupParamMock).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(orderPlanParamMock)).Return(nil, repo.DuplicateKeyError{
+						Constraint: repo.ConstraintPaymentPlansMerchantOrderRefIdx,
+						Err:        fmt.Errorf("dummyErr"),
+					}),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: orderPlanParams,
+			},
+			wantErr: OrderReferenceConflictError{merchantID: merchantID, orderReference: orderReference},
+		},
+		{
+			name: "GetPaymentPlanByOrderReference error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, orderLookupParamMock).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: orderPlanParams,
+			},
+			wantErr: GetPaymentPlanByOrderReferenceError{merchantID: merchantID, orderReference: orderReference},
+		},
+		{
+			name: "line item without quantity",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					UserID:      userID,
+					MerchantID:  merchantID,
+					TotalAmount: totalAmount,
+					LineItems: []PaymentPlanLineItemParams{
+						{SKU: "CH-1", Name: "Chair", UnitPrice: unitPrice},
+					},
+					Installments: paymentPlanParams.Installments,
+				},
+			},
+			wantErr: InvalidOrderError{field: "line_items[0].quantity", reason: "a positive quantity is expected"},
+		},
+		{
+			name: "blank order reference",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					UserID:         userID,
+					MerchantID:     merchantID,
+					TotalAmount:    totalAmount,
+					OrderReference: "  ",
+					Installments:   paymentPlanParams.Installments,
+				},
+			},
+			wantErr: InvalidOrderError{field: "order_reference", reason: "a non blank value is expected"},
+		},
+		{
+			name: "CreatePaymentPlanLineItem error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().GetPaymentPlanByOrderReference(ctx, orderLookupParamMock).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(orderPlanParamMock)).Return(orderPlanMock, nil),
+					rm.EXPECT().CreatePaymentPlanLineItem(ctx, gomock.Eq(lineItemParamMock)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: orderPlanParams,
+			},
+			wantErr: CreatePaymentPlanLineItemError{planID: planID},
+		},
+		{
+			name: "replay with another order",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(orderPlanMock, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(paymentInstallmentMock, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return([]*payments.LineItem{lineItemMock}, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					ID:             planID,
+					UserID:         userID,
+					MerchantID:     merchantID,
+					TotalAmount:    totalAmount,
+					OrderReference: orderReference,
+					Description:    "a chair",
+					LineItems: []PaymentPlanLineItemParams{
+						{SKU: "CH-1", Name: "Chair", Quantity: 2, UnitPrice: unitPrice},
+					},
+					Installments: paymentPlanParams.Installments,
+				},
+			},
+			wantErr: PaymentPlanConflictError{planID: planID},
+		},
+		{
+			name: "invalid total amount",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					UserID:       userID,
+					MerchantID:   merchantID,
+					TotalAmount:  payments.MustNewMoney(decimal.New(0, 0), "usdc"),
+					Installments: paymentPlanParams.Installments,
+				},
+			},
+			wantErr: InvalidAmountError{field: "total_amount.value", value: "0"},
+		},
+		{
+			name: "installment sum mismatch",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					UserID:       userID,
+					MerchantID:   merchantID,
+					TotalAmount:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+					Installments: paymentPlanParams.Installments,
+				},
+			},
+			wantErr: InstallmentSumMismatchError{totalAmount: "1000", installmentsSum: "2000"},
+		},
+		{
+			name: "client supplied id creates the plan with it",
+			prepare: func(rm *repomock.MockRepository) {
+				paramsWithID := *paymentPlanParamMock
+				paramsWithID.ID = planID
+
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(&paramsWithID)).Return(paymentPlanMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(planCreatedEntryMock)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(planCreatedEventMock)).Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanWithIDParams,
+			},
+			want: paymentPlanResponse,
+		},
+		{
+			name: "replay with the same payload returns the existing plan",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(paymentPlanMock, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(paymentInstallmentMock, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(nil, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					ID:          planID,
+					UserID:      userID,
+					MerchantID:  merchantID,
+					TotalAmount: payments.MustNewMoney(decimal.New(200000, 2), "usdc"),
+					Installments: []PaymentPlanInstallmentParams{
+						paymentPlanParams.Installments[1],
+						paymentPlanParams.Installments[0],
+					},
+				},
+			},
+			want: paymentPlanResponse,
+		},
+		{
+			name: "replay with a different payload",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(paymentPlanMock, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(paymentInstallmentMock, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return(nil, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					ID:          planID,
+					UserID:      userID,
+					MerchantID:  merchantID,
+					TotalAmount: payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+					Installments: []PaymentPlanInstallmentParams{
+						paymentPlanParams.Installments[0],
+						{
+							Amount: payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+							DueAt:  dueAt.Add(2 * time.Hour),
+						},
+					},
+				},
+			},
+			wantErr: PaymentPlanConflictError{planID: planID},
+		},
+		{
+			name: "missing merchant",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					UserID:       userID,
+					TotalAmount:  payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+					Installments: paymentPlanParams.Installments,
+				},
+			},
+			wantErr: MissingMerchantError{},
+		},
+		{
+			name: "merchant not found",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: MerchantNotFoundError{merchantID: merchantID},
+		},
+		{
+			name: "GetMerchantByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: GetMerchantError{merchantID: merchantID},
+		},
+		{
+			name: "replay with another merchant",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(paymentPlanMock, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(paymentInstallmentMock, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return(nil, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					ID:           planID,
+					UserID:       userID,
+					MerchantID:   uuid.Must(uuid.NewV4()),
+					TotalAmount:  payments.MustNewMoney(decimal.New(2000, 0), "usdc"),
+					Installments: paymentPlanParams.Installments,
+				},
+			},
+			wantErr: PaymentPlanConflictError{planID: planID},
+		},
+		{
+			name: "GetPaymentPlanByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanWithIDParams,
+			},
+			wantErr: GetPaymentPlanByIDError{planID: planID},
+		},
+		{
+			name: "replay of a schedule whose start has passed since the plan was created",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(paymentPlanMock, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(scheduledInstallmentMock, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(nil, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: &CreatePaymentPlanParams{
+					ID:          planID,
+					UserID:      userID,
+					MerchantID:  merchantID,
+					TotalAmount: totalAmount,
+					Schedule: &PaymentPlanScheduleParams{
+						Count:     2,
+						Frequency: scheduleFrequencyMonthly,
+						StartAt:   scheduleStartAt,
+					},
+				},
+			},
+			want: newPaymentPlans(paymentPlanMock, scheduledInstallmentMock, nil),
+		},
+		{
+			name: "plan created by a concurrent request with the same id is replayed",
+			prepare: func(rm *repomock.MockRepository) {
+				paramsWithID := *paymentPlanParamMock
+				paramsWithID.ID = planID
+
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(&paramsWithID)).Return(nil, repo.DuplicateKeyError{
+						Constraint: repo.ConstraintPaymentPlansPkey,
+						Err:        fmt.Errorf("dummyErr"),
+					}),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(paymentPlanMock, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(paymentInstallmentMock, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(nil, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanWithIDParams,
+			},
+			want: paymentPlanResponse,
+		},
+		{
+			name: "CreatePaymentPlan error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: CreatePaymentPlanError{},
+		},
+		{
+			name: "CreatePaymentInstallment error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: CreatePaymentInstallmentError{},
+		},
+		{
+			name: "CreateJournalEntry error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(planCreatedEntryMock)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: CreateJournalEntryError{planID: planID, kind: ledger.EntryPlanCreated},
+		},
+		{
+			name: "CreateOutboxEvent error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(3000, 0), "usdc"), nil),
+					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(planCreatedEntryMock)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(planCreatedEventMock)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: CreateOutboxEventError{planID: planID, eventType: outbox.EventPlanCreated},
+		},
+		{
+			name: "no credit line",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: CreditLineNotFoundError{userID: userID},
+		},
+		{
+			name: "LockCreditLineByUserID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: LockCreditLineError{userID: userID},
+		},
+		{
+			name: "credit line not active",
+			prepare: func(rm *repomock.MockRepository) {
+				frozenLine := *creditLineMock
+				frozenLine.Status = CreditLineStatusFrozen
+
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(&frozenLine, nil),
+				)
+			},
+			args: args{
+				createPaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: CreditLineNotActiveError{userID: userID, status: CreditLineStatusFrozen},
+		},
+		{
+			name: "insufficient credit",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
-					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.MustNewMoney(decimal.New(300001, 2), "usdc"), nil),
 				)
 			},
 			args: args{
-				userID: userID,
+				createPaymentPlanParams: paymentPlanParams,
 			},
-			want:    paymentPlanResponse,
-			wantErr: false,
+			wantErr: InsufficientCreditError{userID: userID, amount: "2000", available: "1999.99", currency: "usdc"},
 		},
 		{
-			name: "ListPaymentPlansByUserID error",
+			name: "credit line in another currency",
 			prepare: func(rm *repomock.MockRepository) {
+				euroLine := *creditLineMock
+				euroLine.Limit = payments.MustNewMoney(decimal.New(5000, 0), "eur")
+
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(&euroLine, nil),
 				)
 			},
 			args: args{
-				userID: userID,
+				createPaymentPlanParams: paymentPlanParams,
 			},
-			wantErr: true,
+			wantErr: InsufficientCreditError{userID: userID, amount: "2000", available: "0", currency: "usdc"},
 		},
 		{
-			name: "ListPaymentInstallmentsByPlanID error",
+			name: "GetUserOutstandingAmount error",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
-					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(merchantMock, nil),
+					rm.EXPECT().LockCreditLineByUserID(ctx, userID).Return(creditLineMock, nil),
+					rm.EXPECT().GetUserOutstandingAmount(ctx, outstandingParamMock).Return(payments.Money{}, fmt.Errorf("dummyErr")),
 				)
 			},
 			args: args{
-				userID: userID,
+				createPaymentPlanParams: paymentPlanParams,
 			},
-			wantErr: true,
+			wantErr: GetUserOutstandingAmountError{userID: userID},
 		},
 	}
 
@@ -143,24 +1172,24 @@ func TestPaymentServiceImp_GetPaymentPlanByUserID(t *testing.T) {
 
 			p := &PaymentServiceImp{repository: repo}
 
-			got, err := p.GetPaymentPlanByUserID(ctx, tt.args.userID)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("PaymentServiceImp.GetPaymentPlanByUserID() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := p.CreatePendingPaymentPlan(ctx, tt.args.createPaymentPlanParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PaymentServiceImp.CreatePendingPaymentPlan() error = %v, wantErr %v", err, tt.wantErr)
 
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("PaymentServiceImp.GetPaymentPlanByUserID() = %v, want %v", got, tt.want)
+				t.Errorf("PaymentServiceImp.CreatePendingPaymentPlan() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestPaymentServiceImp_CreatePendingPaymentPlan(t *testing.T) {
+func TestPaymentServiceImp_CompletePaymentPlanCreation(t *testing.T) {
 	t.Parallel()
 
 	var (
-		decimalAmount  = *decimal.New(1000, 0)
+		decimalAmount  = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
 		userID         = uuid.Must(uuid.NewV4())
 		planID         = uuid.Must(uuid.NewV4())
 		installmentID  = uuid.Must(uuid.NewV4())
@@ -169,112 +1198,88 @@ func TestPaymentServiceImp_CreatePendingPaymentPlan(t *testing.T) {
 		createdAt, _   = time.Parse(common.TimeFormat, "2021-10-10T23:00:00Z")
 		updatedAt, _   = time.Parse(common.TimeFormat, "2021-10-10T23:00:00Z")
 		dueAt, _       = time.Parse(common.TimeFormat, "2021-11-10T23:00:00Z")
-		currency       = "usdc"
 		status         = "pending"
-
-		paymentPlanParamMock = &payments.CreatePlanParams{
-			UserID:   userID,
-			Currency: currency,
-			Amount:   decimalAmount,
-			Status:   status,
-		}
-
-		paymentPlanMock = &payments.Plan{
+		plan           = &payments.Plan{
 			ID:        planID,
 			UserID:    userID,
-			Currency:  currency,
 			Amount:    decimalAmount,
 			Status:    status,
 			CreatedAt: createdAt,
 			UpdatedAt: updatedAt,
 		}
-
-		paymentInstallmentParamMock = []*payments.CreateInstallmentParams{
-			{
-				PaymentPlanID: planID,
-				Currency:      currency,
-				Amount:        decimalAmount,
-				DueAt:         dueAt,
-				Status:        status,
-			},
+		completedPlans = []*payments.Plan{
 			{
-				PaymentPlanID: planID,
-				Currency:      currency,
-				Amount:        decimalAmount,
-				DueAt:         dueAt.Add(1 * time.Hour),
-				Status:        status,
+				ID:        planID,
+				UserID:    userID,
+				Amount:    decimalAmount,
+				Status:    paymentPlanStatusComplete,
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt,
 			},
 		}
-
-		paymentInstallmentMock = []*payments.Installment{
+		paymentInstallments = []*payments.Installment{
 			{
-				ID:            installmentID,
+				ID:            installmentID2,
 				PaymentPlanID: planID,
-				Currency:      currency,
 				Amount:        decimalAmount,
-				DueAt:         dueAt,
+				DueAt:         dueAt.Add(1 * time.Hour),
 				Status:        status,
 				CreatedAt:     createdAt,
 				UpdatedAt:     updatedAt,
 			},
 			{
-				ID:            installmentID2,
+				ID:            installmentID,
 				PaymentPlanID: planID,
-				Currency:      currency,
 				Amount:        decimalAmount,
-				DueAt:         dueAt.Add(1 * time.Hour),
+				DueAt:         dueAt,
 				Status:        status,
 				CreatedAt:     createdAt,
 				UpdatedAt:     updatedAt,
 			},
 		}
+		paidInstallment = &payments.Installment{
+			ID:            installmentID,
+			PaymentPlanID: planID,
+			Amount:        decimalAmount,
+			DueAt:         dueAt,
+			Status:        PaymentInstallmentStatusPaid,
+			CreatedAt:     createdAt,
+			UpdatedAt:     updatedAt,
+		}
 
-		paymentPlanParams = &CreatePaymentPlanParams{
-			UserID:      userID,
-			Currency:    "usdc",
-			TotalAmount: "1000",
-			Installments: []PaymentPlanInstallmentParams{
-				{
-					Currency: "usdc",
-					Amount:   "1000",
-					DueAt:    dueAt,
-				},
-				{
-					Currency: "usdc",
-					Amount:   "1000",
-					DueAt:    dueAt.Add(1 * time.Hour),
-				},
-			},
+		paymentPlanParams = &CompletePaymentPlanParams{
+			UserID: userID,
 		}
 
+		installmentPaidEventMock = mustInstallmentEvent(outbox.EventInstallmentPaid, paidInstallment)
+		planCompletedEventMock   = mustPlanEvent(outbox.EventPlanCompleted, completedPlans[0])
+
 		paymentPlanResponse = &PaymentPlans{
 			ID:          planID.String(),
 			UserID:      userID.String(),
-			Currency:    currency,
-			TotalAmount: decimalAmount.String(),
-			Status:      status,
+			TotalAmount: decimalAmount,
+			Status:      paymentPlanStatusComplete,
 			CreatedAt:   createdAt.Format(common.TimeFormat),
 			Installments: []PaymentPlanInstallment{
 				{
-					ID:       installmentID.String(),
-					Amount:   decimalAmount.String(),
-					Currency: currency,
-					DueAt:    dueAt.Format(common.TimeFormat),
-					Status:   status,
+					ID:     installmentID2.String(),
+					Amount: decimalAmount,
+					DueAt:  dueAt.Add(1 * time.Hour).Format(common.TimeFormat),
+					Status: status,
 				},
 				{
-					ID:       installmentID2.String(),
-					Amount:   decimalAmount.String(),
-					Currency: currency,
-					DueAt:    dueAt.Add(1 * time.Hour).Format(common.TimeFormat),
-					Status:   status,
+					ID:     installmentID.String(),
+					Amount: decimalAmount,
+					DueAt:  dueAt.Format(common.TimeFormat),
+					Status: PaymentInstallmentStatusPaid,
 				},
 			},
 		}
 	)
 
 	type args struct {
-		createPaymentPlanParams *CreatePaymentPlanParams
+		planID                    uuid.UUID
+		completePaymentPlanParams *CompletePaymentPlanParams
 	}
 
 	tests := []struct {
@@ -282,215 +1287,215 @@ func TestPaymentServiceImp_CreatePendingPaymentPlan(t *testing.T) {
 		prepare func(rm *repomock.MockRepository)
 		args    args
 		want    *PaymentPlans
-		wantErr bool
+		wantErr error
 	}{
 		{
 			name: "happy path",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
-					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(paymentInstallmentMock[0], nil),
-					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[1])).Return(paymentInstallmentMock[1], nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Eq(&payments.UpdateInstallmentStatusParams{
+						ID:     installmentID,
+						Status: PaymentInstallmentStatusPaid,
+					})).Return(paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(installmentPaidEventMock)).Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(ledger.NewTransfer(
+						planID, ledger.EntryInstallmentPaid, ledger.AccountCash, ledger.AccountUserReceivable, decimalAmount,
+					))).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Eq(&payments.UpdatePlanStatusParams{
+						ID:     planID,
+						Status: paymentPlanStatusComplete,
+					})).Return(completedPlans[0], nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(planCompletedEventMock)).Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).
+						Return([]*payments.Installment{paymentInstallments[0], paidInstallment}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentPlanLineItemsByPlanID(ctx, planID).Return(nil, nil),
 				)
 			},
 			args: args{
-				createPaymentPlanParams: paymentPlanParams,
+				planID:                    planID,
+				completePaymentPlanParams: paymentPlanParams,
 			},
-			want:    paymentPlanResponse,
-			wantErr: false,
+			want: paymentPlanResponse,
 		},
 		{
-			name: "CreatePaymentPlan error",
+			name: "LockPaymentPlan error",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
 				)
 			},
 			args: args{
-				createPaymentPlanParams: paymentPlanParams,
+				planID:                    planID,
+				completePaymentPlanParams: paymentPlanParams,
 			},
-			wantErr: true,
+			wantErr: LockPaymentPlanError{planID: planID},
 		},
 		{
-			name: "CreatePaymentInstallment error",
+			name: "completing a complete plan is an invalid transition",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().CreatePaymentPlan(ctx, gomock.Eq(paymentPlanParamMock)).Return(paymentPlanMock, nil),
-					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Eq(paymentInstallmentParamMock[0])).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(completedPlans[0], nil),
 				)
 			},
 			args: args{
-				createPaymentPlanParams: paymentPlanParams,
+				planID:                    planID,
+				completePaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: InvalidStateTransitionError{
+				entity: "payment plan", id: planID, from: paymentPlanStatusComplete, to: paymentPlanStatusComplete,
 			},
-			wantErr: true,
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			repo := repomock.NewMockRepository(ctrl)
-
-			if tt.prepare != nil {
-				tt.prepare(repo)
-			}
-
-			p := &PaymentServiceImp{repository: repo}
-
-			got, err := p.CreatePendingPaymentPlan(ctx, tt.args.createPaymentPlanParams)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("PaymentServiceImp.CreatePendingPaymentPlan() error = %v, wantErr %v", err, tt.wantErr)
-
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("PaymentServiceImp.CreatePendingPaymentPlan() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestPaymentServiceImp_CompletePaymentPlanCreation(t *testing.T) {
-	t.Parallel()
-
-	var (
-		decimalAmount = *decimal.New(1000, 0)
-		userID        = uuid.Must(uuid.NewV4())
-		planID        = uuid.Must(uuid.NewV4())
-		installmentID = uuid.Must(uuid.NewV4())
-		ctx           = context.Background()
-		createdAt, _  = time.Parse(common.TimeFormat, "2021-10-10T23:00:00Z")
-		updatedAt, _  = time.Parse(common.TimeFormat, "2021-10-10T23:00:00Z")
-		dueAt, _      = time.Parse(common.TimeFormat, "2021-11-10T23:00:00Z")
-		currency      = "usdc"
-		status        = "pending"
-		paymentPlans  = []*payments.Plan{
-			{
-				ID:        uuid.Must(uuid.NewV4()),
-				UserID:    userID,
-				Currency:  currency,
-				Amount:    decimalAmount,
-				Status:    status,
-				CreatedAt: createdAt,
-				UpdatedAt: updatedAt,
+		{
+			name: "ListPaymentInstallmentsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(nil, fmt.Errorf("dummyErr")),
+				)
 			},
-			{
-				ID:        planID,
-				UserID:    userID,
-				Currency:  currency,
-				Amount:    decimalAmount,
-				Status:    status,
-				CreatedAt: createdAt,
-				UpdatedAt: updatedAt,
+			args: args{
+				planID:                    planID,
+				completePaymentPlanParams: paymentPlanParams,
 			},
-		}
-		paymentInstallments = []*payments.Installment{
-			{
-				ID:            installmentID,
-				PaymentPlanID: planID,
-				Currency:      currency,
-				Amount:        decimalAmount,
-				DueAt:         dueAt,
-				Status:        status,
-				CreatedAt:     createdAt,
-				UpdatedAt:     updatedAt,
+			wantErr: ListPaymentInstallmentsByPlanIDError{planID: planID},
+		},
+		{
+			name: "UpdatePaymentInstallmentStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
 			},
-		}
-
-		paymentPlanParams = &CompletePaymentPlanParams{
-			UserID: userID,
-		}
-
-		paymentPlanResponse = &PaymentPlans{
-			ID:          planID.String(),
-			UserID:      userID.String(),
-			Currency:    currency,
-			TotalAmount: decimalAmount.String(),
-			Status:      status,
-			CreatedAt:   createdAt.Format(common.TimeFormat),
-			Installments: []PaymentPlanInstallment{
-				{
-					ID:       installmentID.String(),
-					Amount:   decimalAmount.String(),
-					Currency: currency,
-					DueAt:    dueAt.Format(common.TimeFormat),
-					Status:   status,
-				},
+			args: args{
+				planID:                    planID,
+				completePaymentPlanParams: paymentPlanParams,
 			},
-		}
-	)
-
-	type args struct {
-		planID                    uuid.UUID
-		completePaymentPlanParams *CompletePaymentPlanParams
-	}
+			wantErr: UpdatePaymentInstallmentStatusError{installmentID: installmentID},
+		},
+		{
+			name: "InvalidStateTransition error",
+			prepare: func(rm *repomock.MockRepository) {
+				voidInstallment := *paymentInstallments[1]
+				voidInstallment.Status = PaymentInstallmentStatusVoid
 
-	tests := []struct {
-		name    string
-		prepare func(rm *repomock.MockRepository)
-		args    args
-		want    *PaymentPlans
-		wantErr bool
-	}{
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(
+						[]*payments.Installment{paymentInstallments[0], &voidInstallment}, nil),
+				)
+			},
+			args: args{
+				planID:                    planID,
+				completePaymentPlanParams: paymentPlanParams,
+			},
+			wantErr: InvalidStateTransitionError{
+				entity: "payment installment",
+				id:     installmentID,
+				from:   PaymentInstallmentStatusVoid,
+				to:     PaymentInstallmentStatusPaid,
+			},
+		},
 		{
-			name: "happy path",
+			name: "CreateOutboxEvent error",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
 					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Any()).Return(completedPlans[0], nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
 				)
 			},
 			args: args{
 				planID:                    planID,
 				completePaymentPlanParams: paymentPlanParams,
 			},
-			want:    paymentPlanResponse,
-			wantErr: false,
+			wantErr: CreateOutboxEventError{planID: planID, eventType: outbox.EventPlanCompleted},
 		},
 		{
-			name: "ListPaymentPlansByUserID error",
+			name: "CreateAuditEntry error",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Any()).Return(completedPlans[0], nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
 				)
 			},
 			args: args{
 				planID:                    planID,
 				completePaymentPlanParams: paymentPlanParams,
 			},
-			wantErr: true,
+			wantErr: CreateAuditEntryError{planID: planID, action: audit.ActionPlanCompleted},
 		},
 		{
-			name: "ListPaymentInstallmentsByPlanID error",
+			name: "UpdatePaymentPlanStatus error",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return(paymentPlans, nil),
-					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(nil, fmt.Errorf("dummyErr")),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, gomock.Eq(planID)).Return(paymentInstallments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
 				)
 			},
 			args: args{
 				planID:                    planID,
 				completePaymentPlanParams: paymentPlanParams,
 			},
-			wantErr: true,
+			wantErr: UpdatePaymentPlanStatusError{planID: planID},
 		},
 		{
 			name: "PaymentRecordNotFound error",
 			prepare: func(rm *repomock.MockRepository) {
 				gomock.InOrder(
-					rm.EXPECT().ListPaymentPlansByUserID(ctx, gomock.Eq(userID)).Return([]*payments.Plan{}, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
 				)
 			},
 			args: args{
 				planID:                    planID,
 				completePaymentPlanParams: paymentPlanParams,
 			},
-			wantErr: true,
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "payment plan of another user",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+				)
+			},
+			args: args{
+				planID:                    planID,
+				completePaymentPlanParams: &CompletePaymentPlanParams{UserID: uuid.Must(uuid.NewV4())},
+			},
+			wantErr: PaymentRecordNotFoundError{planID: planID},
 		},
 	}
 
@@ -511,7 +1516,7 @@ func TestPaymentServiceImp_CompletePaymentPlanCreation(t *testing.T) {
 			p := &PaymentServiceImp{repository: repo}
 
 			got, err := p.CompletePaymentPlanCreation(ctx, tt.args.planID, tt.args.completePaymentPlanParams)
-			if (err != nil) != tt.wantErr {
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("PaymentServiceImp.CompletePaymentPlanCreation() error = %v, wantErr %v", err, tt.wantErr)
 
 				return
@@ -533,3 +1538,9 @@ func TestPaymentServiceImp_UseRepo(t *testing.T) {
 		t.Errorf("returned repository is not of Repo")
 	}
 }
+
+func runInTx(rm *repomock.MockRepository) func(ctx context.Context, fn func(txRepo repo.Repository) error) error {
+	return func(ctx context.Context, fn func(txRepo repo.Repository) error) error {
+		return fn(rm)
+	}
+}
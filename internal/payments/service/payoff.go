@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// QuotePaymentPlanPayoff computes what is due now to pay off every unpaid installment of the plan
+func (p *PaymentServiceImp) QuotePaymentPlanPayoff(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+	userID uuid.UUID,
+) (*PaymentPlanPayoffQuote, error) {
+	plan, err := p.repository.GetPaymentPlanByID(ctx, paymentPlanID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+		}
+
+		return nil, GetPaymentPlanByIDError{planID: paymentPlanID}
+	}
+
+	if plan.UserID != userID {
+		return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+	}
+
+	if plan.Status != paymentPlanStatusPending && plan.Status != paymentPlanStatusComplete {
+		return nil, PaymentPlanNotPayableError{planID: plan.ID, status: plan.Status}
+	}
+
+	installments, err := p.repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	unpaid := unpaidInstallments(installments)
+
+	outstanding, err := outstandingPayoffAmount(ctx, p.repository, plan.ID, unpaid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentPlanPayoffQuote{
+		PaymentPlanID: plan.ID.String(),
+		Amount:        outstanding,
+		Installments:  newPlanInstallments(unpaid),
+	}, nil
+}
+
+// PayOffPaymentPlan records one settlement covering every unpaid installment of the plan,
+// the installments are marked paid and a pending plan is completed
+func (p *PaymentServiceImp) PayOffPaymentPlan(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+	payoff *PaymentPlanPayoffParams,
+) (*PaymentPlanPayoff, error) {
+	var paidOff *PaymentPlanPayoff
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		paidOff, txErr = payOffPaymentPlan(ctx, txRepo, paymentPlanID, payoff)
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pay off payment plan: %w", err)
+	}
+
+	return paidOff, nil
+}
+
+func payOffPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	payoff *PaymentPlanPayoffParams,
+) (*PaymentPlanPayoff, error) {
+	if err := checkPositiveAmount(payoff.Amount, "amount"); err != nil {
+		return nil, err
+	}
+
+	if payoff.ProcessorReference == "" {
+		return nil, MissingProcessorReferenceError{}
+	}
+
+	plan, err := lockUserPaymentPlan(ctx, repository, paymentPlanID, payoff.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Status != paymentPlanStatusPending && plan.Status != paymentPlanStatusComplete {
+		return nil, PaymentPlanNotPayableError{planID: plan.ID, status: plan.Status}
+	}
+
+	if err := checkSameCurrency(payoff.Amount, "amount", plan.Amount.Currency()); err != nil {
+		return nil, err
+	}
+
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	// the unpaid installments stay locked so a concurrent payment cannot be counted twice
+	installments, err = lockUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	before := audit.NewPlanSnapshot(plan, installments)
+
+	outstanding, err := outstandingPayoffAmount(ctx, repository, plan.ID, unpaidInstallments(installments))
+	if err != nil {
+		return nil, err
+	}
+
+	if !payoff.Amount.Equal(outstanding) {
+		return nil, PayoffAmountMismatchError{amount: payoff.Amount.String(), outstanding: outstanding.String()}
+	}
+
+	paidAt := payoff.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now().UTC()
+	}
+
+	settlement, err := repository.CreatePaymentSettlement(ctx, &payments.CreateSettlementParams{
+		PaymentPlanID:      plan.ID,
+		Amount:             payoff.Amount,
+		ProcessorReference: payoff.ProcessorReference,
+		PaidAt:             paidAt,
+	})
+	if err != nil {
+		return nil, CreatePaymentSettlementError{planID: plan.ID}
+	}
+
+	if err := postJournalEntry(ctx, repository, ledger.NewTransfer(
+		plan.ID, ledger.EntryPlanPaidOff, ledger.AccountCash, ledger.AccountUserReceivable, settlement.Amount,
+	)); err != nil {
+		return nil, err
+	}
+
+	installments, err = payInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Status == paymentPlanStatusPending {
+		plan, err = updatePaymentPlanStatus(ctx, repository, plan, paymentPlanStatusComplete)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordPlanEvent(ctx, repository, outbox.EventPlanPaidOff, plan); err != nil {
+		return nil, err
+	}
+
+	if err := recordAuditEntry(
+		ctx, repository, plan.ID, audit.ActionPlanPaidOff, before, audit.NewPlanSnapshot(plan, installments),
+	); err != nil {
+		return nil, err
+	}
+
+	return &PaymentPlanPayoff{
+		ID:                 settlement.ID.String(),
+		PaymentPlanID:      settlement.PaymentPlanID.String(),
+		Amount:             settlement.Amount,
+		ProcessorReference: settlement.ProcessorReference,
+		PaidAt:             settlement.PaidAt.Format(common.TimeFormat),
+		Status:             plan.Status,
+		Installments:       newPlanInstallments(installments),
+	}, nil
+}
+
+// lockUnpaidInstallments returns the installments with the unpaid ones read again once locked,
+// they may have been paid since they were listed
+func lockUnpaidInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	installments []*payments.Installment,
+) ([]*payments.Installment, error) {
+	lockedInstallments := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if !isUnpaidInstallment(inst) {
+			lockedInstallments = append(lockedInstallments, inst)
+
+			continue
+		}
+
+		lockedInst, err := repository.LockPaymentInstallment(ctx, inst.ID)
+		if err != nil {
+			return nil, LockPaymentInstallmentError{installmentID: inst.ID}
+		}
+
+		lockedInstallments = append(lockedInstallments, lockedInst)
+	}
+
+	return lockedInstallments, nil
+}
+
+// payInstallments returns the installments with their status once the unpaid ones are paid
+func payInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	installments []*payments.Installment,
+) ([]*payments.Installment, error) {
+	paidInstallments := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if !isUnpaidInstallment(inst) {
+			paidInstallments = append(paidInstallments, inst)
+
+			continue
+		}
+
+		paidInst, err := updatePaymentInstallmentStatus(ctx, repository, inst, PaymentInstallmentStatusPaid)
+		if err != nil {
+			return nil, err
+		}
+
+		paidInstallments = append(paidInstallments, paidInst)
+	}
+
+	return paidInstallments, nil
+}
+
+// outstandingPayoffAmount sums what is left to pay on the installments, a plan with nothing left
+// to pay cannot be paid off
+func outstandingPayoffAmount(
+	ctx context.Context,
+	repository repo.Repository,
+	planID uuid.UUID,
+	unpaid []*payments.Installment,
+) (payments.Money, error) {
+	if len(unpaid) == 0 {
+		return payments.Money{}, PaymentPlanAlreadyPaidError{planID: planID}
+	}
+
+	total, err := payments.ZeroMoney(unpaid[0].Amount.Currency())
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	for _, inst := range unpaid {
+		outstanding, err := outstandingInstallmentAmount(ctx, repository, inst)
+		if err != nil {
+			return payments.Money{}, err
+		}
+
+		if total, err = total.Add(outstanding); err != nil {
+			return payments.Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+func unpaidInstallments(installments []*payments.Installment) []*payments.Installment {
+	unpaid := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if isUnpaidInstallment(inst) {
+			unpaid = append(unpaid, inst)
+		}
+	}
+
+	return unpaid
+}
+
+func isUnpaidInstallment(inst *payments.Installment) bool {
+	return inst.Status != PaymentInstallmentStatusPaid &&
+		inst.Status != PaymentInstallmentStatusVoid &&
+		inst.Status != PaymentInstallmentStatusSuperseded
+}
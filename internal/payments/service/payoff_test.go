@@ -0,0 +1,531 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_QuotePaymentPlanPayoff(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx            = context.Background()
+		userID         = uuid.Must(uuid.NewV4())
+		planID         = uuid.Must(uuid.NewV4())
+		installmentID  = uuid.Must(uuid.NewV4())
+		installmentID2 = uuid.Must(uuid.NewV4())
+		dueAt, _       = time.Parse(common.TimeFormat, "2022-08-01T10:00:00Z")
+		currency       = "usdc"
+
+		plan = &payments.Plan{
+			ID:     planID,
+			UserID: userID,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status: paymentPlanStatusComplete,
+		}
+
+		installments = []*payments.Installment{
+			{
+				ID:            installmentID,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt,
+				Status:        PaymentInstallmentStatusPaid,
+			},
+			{
+				ID:            installmentID2,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt.Add(30 * 24 * time.Hour),
+				Status:        PaymentInstallmentStatusOverdue,
+			},
+		}
+	)
+
+	cancelledPlan := *plan
+	cancelledPlan.Status = paymentPlanStatusCancelled
+
+	paidInstallment := *installments[1]
+	paidInstallment.Status = PaymentInstallmentStatusPaid
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		userID  uuid.UUID
+		want    *PaymentPlanPayoffQuote
+		wantErr error
+	}{
+		{
+			name: "the quote covers what is left on the unpaid installments",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return([]*payments.Transaction{
+						{Amount: payments.MustNewMoney(decimal.New(20, 0), "usdc")},
+					}, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID2).Return([]*payments.LateFee{
+						{Amount: payments.MustNewMoney(decimal.New(5, 0), "usdc")},
+					}, nil),
+				)
+			},
+			userID: userID,
+			want: &PaymentPlanPayoffQuote{
+				PaymentPlanID: planID.String(),
+				Amount:        payments.MustNewMoney(decimal.New(35, 0), currency),
+				Installments: []PaymentPlanInstallment{
+					{
+						ID:     installmentID2.String(),
+						Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:  "2022-08-31T10:00:00Z",
+						Status: PaymentInstallmentStatusOverdue,
+					},
+				},
+			},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, repo.ErrRecordNotFound)
+			},
+			userID:  userID,
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "payment plan of another user",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil)
+			},
+			userID:  uuid.Must(uuid.NewV4()),
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "GetPaymentPlanByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			userID:  userID,
+			wantErr: GetPaymentPlanByIDError{planID: planID},
+		},
+		{
+			name: "cancelled payment plan",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(&cancelledPlan, nil)
+			},
+			userID:  userID,
+			wantErr: PaymentPlanNotPayableError{planID: planID, status: paymentPlanStatusCancelled},
+		},
+		{
+			name: "ListPaymentInstallmentsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			userID:  userID,
+			wantErr: ListPaymentInstallmentsByPlanIDError{planID: planID},
+		},
+		{
+			name: "every installment already paid",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetPaymentPlanByID(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return([]*payments.Installment{
+						installments[0], &paidInstallment,
+					}, nil),
+				)
+			},
+			userID:  userID,
+			wantErr: PaymentPlanAlreadyPaidError{planID: planID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.QuotePaymentPlanPayoff(ctx, planID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.QuotePaymentPlanPayoff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.QuotePaymentPlanPayoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceImp_PayOffPaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx            = context.Background()
+		userID         = uuid.Must(uuid.NewV4())
+		planID         = uuid.Must(uuid.NewV4())
+		settlementID   = uuid.Must(uuid.NewV4())
+		installmentID  = uuid.Must(uuid.NewV4())
+		installmentID2 = uuid.Must(uuid.NewV4())
+		dueAt, _       = time.Parse(common.TimeFormat, "2022-08-01T10:00:00Z")
+		paidAt, _      = time.Parse(common.TimeFormat, "2022-08-15T10:00:00Z")
+		currency       = "usdc"
+
+		plan = &payments.Plan{
+			ID:     planID,
+			UserID: userID,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status: paymentPlanStatusPending,
+		}
+
+		installments = []*payments.Installment{
+			{
+				ID:            installmentID,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt,
+				Status:        PaymentInstallmentStatusPaid,
+			},
+			{
+				ID:            installmentID2,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt.Add(30 * 24 * time.Hour),
+				Status:        PaymentInstallmentStatusPending,
+			},
+		}
+
+		settlement = &payments.Settlement{
+			ID:                 settlementID,
+			PaymentPlanID:      planID,
+			Amount:             payments.MustNewMoney(decimal.New(35, 0), currency),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt,
+		}
+
+		payoff = &PaymentPlanPayoffParams{
+			UserID:             userID,
+			Amount:             payments.MustNewMoney(decimal.New(35, 0), currency),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt,
+		}
+	)
+
+	completePlan := *plan
+	completePlan.Status = paymentPlanStatusComplete
+
+	refundedPlan := *plan
+	refundedPlan.Status = paymentPlanStatusRefunded
+
+	paidInstallment := *installments[1]
+	paidInstallment.Status = PaymentInstallmentStatusPaid
+
+	installmentPaidEvent := mustInstallmentEvent(outbox.EventInstallmentPaid, &paidInstallment)
+
+	// lockOutstanding expects the unpaid installment to be locked with 35 left to pay on it
+	lockOutstanding := func(rm *repomock.MockRepository, lockedPlan *payments.Plan) []*gomock.Call {
+		return []*gomock.Call{
+			rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+			rm.EXPECT().LockPaymentPlan(ctx, planID).Return(lockedPlan, nil),
+			rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+			rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(installments[1], nil),
+			rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return([]*payments.Transaction{
+				{Amount: payments.MustNewMoney(decimal.New(20, 0), "usdc")},
+			}, nil),
+			rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID2).Return([]*payments.LateFee{
+				{Amount: payments.MustNewMoney(decimal.New(5, 0), "usdc")},
+			}, nil),
+		}
+	}
+
+	wantPayoff := func(status string) *PaymentPlanPayoff {
+		return &PaymentPlanPayoff{
+			ID:                 settlementID.String(),
+			PaymentPlanID:      planID.String(),
+			Amount:             payments.MustNewMoney(decimal.New(35, 0), currency),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             "2022-08-15T10:00:00Z",
+			Status:             status,
+			Installments: []PaymentPlanInstallment{
+				{
+					ID:     installmentID.String(),
+					Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+					DueAt:  "2022-08-01T10:00:00Z",
+					Status: PaymentInstallmentStatusPaid,
+				},
+				{
+					ID:     installmentID2.String(),
+					Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+					DueAt:  "2022-08-31T10:00:00Z",
+					Status: PaymentInstallmentStatusPaid,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		payoff  *PaymentPlanPayoffParams
+		want    *PaymentPlanPayoff
+		wantErr error
+	}{
+		{
+			name: "paying off a pending plan completes it",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, plan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, &payments.CreateSettlementParams{
+						PaymentPlanID:      planID,
+						Amount:             payments.MustNewMoney(decimal.New(35, 0), currency),
+						ProcessorReference: "psp-ref-1",
+						PaidAt:             paidAt,
+					}).Return(settlement, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, ledger.NewTransfer(planID, ledger.EntryPlanPaidOff,
+						ledger.AccountCash, ledger.AccountUserReceivable, payments.MustNewMoney(decimal.New(35, 0), currency),
+					)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID2,
+						Status: PaymentInstallmentStatusPaid,
+					}).Return(&paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, installmentPaidEvent).Return(&outbox.Event{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+						ID:     planID,
+						Status: paymentPlanStatusComplete,
+					}).Return(&completePlan, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, mustPlanEvent(outbox.EventPlanCompleted, &completePlan)).
+						Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, mustPlanEvent(outbox.EventPlanPaidOff, &completePlan)).
+						Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)...)
+			},
+			payoff: payoff,
+			want:   wantPayoff(paymentPlanStatusComplete),
+		},
+		{
+			name: "paying off a complete plan keeps its status",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, &completePlan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, gomock.Any()).Return(settlement, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, installmentPaidEvent).Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, mustPlanEvent(outbox.EventPlanPaidOff, &completePlan)).
+						Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)...)
+			},
+			payoff: payoff,
+			want:   wantPayoff(paymentPlanStatusComplete),
+		},
+		{
+			name:    "invalid amount",
+			prepare: func(rm *repomock.MockRepository) { rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)) },
+			payoff: &PaymentPlanPayoffParams{
+				UserID: userID, Amount: payments.MustNewMoney(decimal.New(-35, 0), currency), ProcessorReference: "psp-ref-1",
+			},
+			wantErr: InvalidAmountError{field: "amount.value", value: "-35"},
+		},
+		{
+			name:    "missing processor reference",
+			prepare: func(rm *repomock.MockRepository) { rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)) },
+			payoff: &PaymentPlanPayoffParams{
+				UserID: userID, Amount: payments.MustNewMoney(decimal.New(35, 0), currency),
+			},
+			wantErr: MissingProcessorReferenceError{},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			payoff:  payoff,
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "refunded payment plan",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&refundedPlan, nil),
+				)
+			},
+			payoff:  payoff,
+			wantErr: PaymentPlanNotPayableError{planID: planID, status: paymentPlanStatusRefunded},
+		},
+		{
+			name: "currency mismatch",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+				)
+			},
+			payoff: &PaymentPlanPayoffParams{
+				UserID: userID, Amount: payments.MustNewMoney(decimal.New(35, 0), "usdt"), ProcessorReference: "psp-ref-1",
+			},
+			wantErr: CurrencyMismatchError{field: "amount.currency", expected: currency, actual: "usdt"},
+		},
+		{
+			name:    "amount more precise than the currency",
+			prepare: func(rm *repomock.MockRepository) { rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)) },
+			payoff: &PaymentPlanPayoffParams{
+				UserID: userID, Amount: payments.MustNewMoney(decimal.New(350000001, 7), currency), ProcessorReference: "psp-ref-1",
+			},
+			wantErr: AmountPrecisionError{field: "amount.value", value: "35.0000001", currency: currency, minorUnits: 6},
+		},
+		{
+			name: "every installment already paid",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(&paidInstallment, nil),
+				)
+			},
+			payoff:  payoff,
+			wantErr: PaymentPlanAlreadyPaidError{planID: planID},
+		},
+		{
+			name: "LockPaymentInstallment error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payoff:  payoff,
+			wantErr: LockPaymentInstallmentError{installmentID: installmentID2},
+		},
+		{
+			name: "amount does not match the outstanding amount",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(lockOutstanding(rm, plan)...)
+			},
+			payoff: &PaymentPlanPayoffParams{
+				UserID: userID, Amount: payments.MustNewMoney(decimal.New(30, 0), currency), ProcessorReference: "psp-ref-1",
+			},
+			wantErr: PayoffAmountMismatchError{amount: "30", outstanding: "35"},
+		},
+		{
+			name: "CreatePaymentSettlement error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, plan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			payoff:  payoff,
+			wantErr: CreatePaymentSettlementError{planID: planID},
+		},
+		{
+			name: "CreateJournalEntry error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, plan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, gomock.Any()).Return(settlement, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			payoff:  payoff,
+			wantErr: CreateJournalEntryError{planID: planID, kind: ledger.EntryPlanPaidOff},
+		},
+		{
+			name: "UpdatePaymentInstallmentStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, plan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, gomock.Any()).Return(settlement, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			payoff:  payoff,
+			wantErr: UpdatePaymentInstallmentStatusError{installmentID: installmentID2},
+		},
+		{
+			name: "UpdatePaymentPlanStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, plan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, gomock.Any()).Return(settlement, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, installmentPaidEvent).Return(&outbox.Event{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			payoff:  payoff,
+			wantErr: UpdatePaymentPlanStatusError{planID: planID},
+		},
+		{
+			name: "CreateOutboxEvent error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm, &completePlan),
+					rm.EXPECT().CreatePaymentSettlement(ctx, gomock.Any()).Return(settlement, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, installmentPaidEvent).Return(&outbox.Event{}, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			payoff:  payoff,
+			wantErr: CreateOutboxEventError{planID: planID, eventType: outbox.EventPlanPaidOff},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.PayOffPaymentPlan(ctx, planID, tt.payoff)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.PayOffPaymentPlan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.PayOffPaymentPlan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
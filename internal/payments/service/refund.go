@@ -0,0 +1,748 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// CancelPaymentPlan cancels a plan whose creation was not completed, its installments are voided
+func (p *PaymentServiceImp) CancelPaymentPlan(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+	paymentPlan *CancelPaymentPlanParams,
+) (*PaymentPlans, error) {
+	var cancelledPlan *PaymentPlans
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		cancelledPlan, txErr = cancelPaymentPlan(ctx, txRepo, paymentPlanID, paymentPlan)
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cancel payment plan: %w", err)
+	}
+
+	return cancelledPlan, nil
+}
+
+func cancelPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	paymentPlan *CancelPaymentPlanParams,
+) (*PaymentPlans, error) {
+	plan, err := lockUserPaymentPlan(ctx, repository, paymentPlanID, paymentPlan.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPaymentPlanStatusTransition(plan, paymentPlanStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	before := audit.NewPlanSnapshot(plan, installments)
+
+	installments, err = voidUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeOffReceivable(ctx, repository, plan); err != nil {
+		return nil, err
+	}
+
+	cancelledPlan, err := updatePaymentPlanStatus(ctx, repository, plan, paymentPlanStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordAuditEntry(
+		ctx, repository, plan.ID, audit.ActionPlanCancelled, before, audit.NewPlanSnapshot(cancelledPlan, installments),
+	); err != nil {
+		return nil, err
+	}
+
+	return newPaymentPlans(cancelledPlan, installments, nil), nil
+}
+
+// RefundPaymentPlan takes all or part of the purchase off a completed plan. A partial refund lowers what is
+// left to pay on the unpaid installments and gives back what was paid for the rest, the plan stays complete.
+// A full refund gives back everything paid and voids the unpaid installments, the plan is refunded.
+func (p *PaymentServiceImp) RefundPaymentPlan(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+	refund *RefundPaymentPlanParams,
+) (*PaymentPlanRefund, error) {
+	var planRefund *PaymentPlanRefund
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		planRefund, txErr = refundPaymentPlan(ctx, txRepo, paymentPlanID, refund, time.Now().UTC())
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("refund payment plan: %w", err)
+	}
+
+	return planRefund, nil
+}
+
+func refundPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	refund *RefundPaymentPlanParams,
+	now time.Time,
+) (*PaymentPlanRefund, error) {
+	// the plan stays locked so concurrent refunds cannot give back more than was paid
+	plan, err := lockUserPaymentPlan(ctx, repository, paymentPlanID, refund.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// only a complete plan is refunded, a partial refund leaves it complete
+	if err := checkPaymentPlanStatusTransition(plan, paymentPlanStatusRefunded); err != nil {
+		return nil, err
+	}
+
+	// a missing amount refunds the whole purchase
+	if refund.Amount != nil {
+		if err := checkSameCurrency(*refund.Amount, "amount", plan.Amount.Currency()); err != nil {
+			return nil, err
+		}
+
+		if err := checkPositiveAmount(*refund.Amount, "amount"); err != nil {
+			return nil, err
+		}
+	}
+
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	// the unpaid installments stay locked so a concurrent payment cannot land on one being reduced or voided
+	installments, err = lockUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := refundableInstallmentBalances(ctx, repository, plan.ID, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	totalRefundable, totalOwed, err := totalInstallmentBalances(plan.Amount.Currency(), balances)
+	if err != nil {
+		return nil, err
+	}
+
+	purchase, err := totalRefundable.Add(totalOwed)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := purchase
+	if refund.Amount != nil {
+		amount = *refund.Amount
+	}
+
+	cmp, err := amount.Cmp(purchase)
+	if err != nil || cmp > 0 {
+		return nil, RefundExceedsPaidAmountError{amount: amount.String(), refundable: purchase.String()}
+	}
+
+	before := audit.NewPlanSnapshot(plan, installments)
+	before.Plan.Refundable = &totalRefundable
+
+	// a full refund gives back everything paid, a partial one lowers what is left to pay before
+	// giving back what was paid for the rest
+	givenBack := totalRefundable
+	if cmp < 0 {
+		givenBack, installments, err = reduceOwedInstallments(ctx, repository, plan, installments, balances, amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refunds, givenBackFees, err := refundInstallments(
+		ctx, repository, installments, balances, givenBack, refund.Reason, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the money given back to the user is taken back from the merchant, the late fees from the fee income
+	if givenBack.Sign() > 0 {
+		if err := postRefundedEntry(ctx, repository, plan.ID, givenBack, givenBackFees); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmp == 0 {
+		if installments, plan, err = closeRefundedPaymentPlan(ctx, repository, plan, installments); err != nil {
+			return nil, err
+		}
+	}
+
+	leftRefundable, err := totalRefundable.Sub(givenBack)
+	if err != nil {
+		return nil, err
+	}
+
+	after := audit.NewPlanSnapshot(plan, installments)
+	after.Plan.Refundable = &leftRefundable
+
+	if err := recordAuditEntry(ctx, repository, plan.ID, audit.ActionPlanRefunded, before, after); err != nil {
+		return nil, err
+	}
+
+	return &PaymentPlanRefund{
+		PaymentPlanID: plan.ID.String(),
+		Status:        plan.Status,
+		Amount:        amount,
+		Reason:        refund.Reason,
+		Refunds:       refunds,
+		Installments:  newPlanInstallments(installments),
+	}, nil
+}
+
+// closeRefundedPaymentPlan voids the unpaid installments of a plan whose whole purchase was refunded
+// and writes off what the user still owes on them
+func closeRefundedPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	plan *payments.Plan,
+	installments []*payments.Installment,
+) ([]*payments.Installment, *payments.Plan, error) {
+	installments, err := voidUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := writeOffReceivable(ctx, repository, plan); err != nil {
+		return nil, nil, err
+	}
+
+	plan, err = updatePaymentPlanStatus(ctx, repository, plan, paymentPlanStatusRefunded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return installments, plan, nil
+}
+
+// reduceOwedInstallments takes amount off what is left to pay on the unpaid installments, the latest ones first,
+// and returns what is left of amount to give back with the installments once reduced. An installment left with
+// nothing of its amount to pay is voided and its unpaid late fees are forgiven.
+func reduceOwedInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	plan *payments.Plan,
+	installments []*payments.Installment,
+	balances map[uuid.UUID]installmentBalance,
+	amount payments.Money,
+) (payments.Money, []*payments.Installment, error) {
+	reduced, err := payments.ZeroMoney(amount.Currency())
+	if err != nil {
+		return payments.Money{}, nil, err
+	}
+
+	forgivenFees := reduced
+	reducedInstallments := make(map[uuid.UUID]*payments.Installment)
+	remaining := amount
+
+	for _, inst := range latestInstallmentsFirst(installments) {
+		if remaining.Sign() == 0 {
+			break
+		}
+
+		balance, ok := balances[inst.ID]
+		if !ok || balance.owed.Sign() <= 0 {
+			continue
+		}
+
+		part := balance.owed
+		if cmp, err := part.Cmp(remaining); err != nil || cmp > 0 {
+			part = remaining
+		}
+
+		reducedInst, voided, err := reduceInstallment(ctx, repository, inst, balance, part)
+		if err != nil {
+			return payments.Money{}, nil, err
+		}
+
+		if voided {
+			if forgivenFees, err = forgivenFees.Add(balance.owedFees); err != nil {
+				return payments.Money{}, nil, err
+			}
+		}
+
+		reducedInstallments[inst.ID] = reducedInst
+
+		if reduced, err = reduced.Add(part); err != nil {
+			return payments.Money{}, nil, err
+		}
+
+		if remaining, err = remaining.Sub(part); err != nil {
+			return payments.Money{}, nil, err
+		}
+	}
+
+	if reduced.Sign() > 0 {
+		entry, err := newRefundedEntry(plan.ID, reduced, forgivenFees, ledger.AccountUserReceivable)
+		if err != nil {
+			return payments.Money{}, nil, err
+		}
+
+		if err := postJournalEntry(ctx, repository, entry); err != nil {
+			return payments.Money{}, nil, err
+		}
+	}
+
+	updatedInstallments := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if reducedInst, ok := reducedInstallments[inst.ID]; ok {
+			inst = reducedInst
+		}
+
+		updatedInstallments = append(updatedInstallments, inst)
+	}
+
+	return remaining, updatedInstallments, nil
+}
+
+// reduceInstallment lowers the amount of an unpaid installment by part, it is voided when nothing of its amount
+// is left to pay
+func reduceInstallment(
+	ctx context.Context,
+	repository repo.Repository,
+	inst *payments.Installment,
+	balance installmentBalance,
+	part payments.Money,
+) (*payments.Installment, bool, error) {
+	if cmp, err := part.Cmp(balance.owed); err == nil && cmp == 0 {
+		voidedInst, err := updatePaymentInstallmentStatus(ctx, repository, inst, PaymentInstallmentStatusVoid)
+
+		return voidedInst, true, err
+	}
+
+	amount, err := inst.Amount.Sub(part)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reducedInst, err := repository.UpdatePaymentInstallmentAmount(ctx, &payments.UpdateInstallmentAmountParams{
+		ID:     inst.ID,
+		Amount: amount,
+	})
+	if err != nil {
+		return nil, false, UpdatePaymentInstallmentAmountError{installmentID: inst.ID}
+	}
+
+	return reducedInst, false, nil
+}
+
+// postRefundedEntry givenBack is the money given back to the user, givenBackFees what of it was paid for late fees
+func postRefundedEntry(
+	ctx context.Context,
+	repository repo.Repository,
+	planID uuid.UUID,
+	givenBack, givenBackFees payments.Money,
+) error {
+	principal, err := givenBack.Sub(givenBackFees)
+	if err != nil {
+		return err
+	}
+
+	entry, err := newRefundedEntry(planID, principal, givenBackFees, ledger.AccountCash)
+	if err != nil {
+		return err
+	}
+
+	return postJournalEntry(ctx, repository, entry)
+}
+
+// newRefundedEntry the principal refunded is taken off the merchant payable and the late fees off the fee income,
+// the credited account is the cash given back or the receivable the user no longer owes
+func newRefundedEntry(
+	planID uuid.UUID,
+	principal, fees payments.Money,
+	credited string,
+) (*ledger.CreateEntryParams, error) {
+	total, err := principal.Add(fees)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &ledger.CreateEntryParams{PaymentPlanID: planID, Kind: ledger.EntryRefunded}
+
+	if principal.Sign() > 0 {
+		entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+			Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: principal,
+		})
+	}
+
+	if fees.Sign() > 0 {
+		entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+			Account: ledger.AccountFeeIncome, Direction: ledger.Debit, Amount: fees,
+		})
+	}
+
+	entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+		Account: credited, Direction: ledger.Credit, Amount: total,
+	})
+
+	return entry, nil
+}
+
+// refundInstallments spreads amount over what was paid on the installments, the latest ones are refunded first,
+// and returns what of amount gives back late fees. The late fees paid on an installment are given back first.
+func refundInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	installments []*payments.Installment,
+	balances map[uuid.UUID]installmentBalance,
+	amount payments.Money,
+	reason string,
+	now time.Time,
+) ([]PaymentInstallmentRefund, payments.Money, error) {
+	refunds := make([]PaymentInstallmentRefund, 0)
+
+	givenBackFees, err := payments.ZeroMoney(amount.Currency())
+	if err != nil {
+		return nil, payments.Money{}, err
+	}
+
+	remaining := amount
+
+	for _, inst := range latestInstallmentsFirst(installments) {
+		if remaining.Sign() == 0 {
+			break
+		}
+
+		balance := balances[inst.ID]
+
+		part := balance.refundable
+		if part.Sign() <= 0 {
+			continue
+		}
+
+		if cmp, err := part.Cmp(remaining); err != nil || cmp > 0 {
+			part = remaining
+		}
+
+		feePart := balance.refundableFees
+		if cmp, err := feePart.Cmp(part); err != nil || cmp > 0 {
+			feePart = part
+		}
+
+		if givenBackFees, err = givenBackFees.Add(feePart); err != nil {
+			return nil, payments.Money{}, err
+		}
+
+		refund, err := repository.CreatePaymentRefund(ctx, &payments.CreateRefundParams{
+			PaymentInstallmentID: inst.ID,
+			Amount:               part,
+			Reason:               reason,
+			RefundedAt:           now,
+		})
+		if err != nil {
+			return nil, payments.Money{}, CreatePaymentRefundError{installmentID: inst.ID}
+		}
+
+		refunds = append(refunds, newInstallmentRefund(refund))
+
+		if remaining, err = remaining.Sub(part); err != nil {
+			return nil, payments.Money{}, err
+		}
+	}
+
+	return refunds, givenBackFees, nil
+}
+
+func latestInstallmentsFirst(installments []*payments.Installment) []*payments.Installment {
+	byDueDateDesc := make([]*payments.Installment, len(installments))
+	copy(byDueDateDesc, installments)
+
+	sort.SliceStable(byDueDateDesc, func(i, j int) bool {
+		return byDueDateDesc[i].DueAt.After(byDueDateDesc[j].DueAt)
+	})
+
+	return byDueDateDesc
+}
+
+// lockUserPaymentPlan a plan of another user is reported as not found
+func lockUserPaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	userID uuid.UUID,
+) (*payments.Plan, error) {
+	plan, err := repository.LockPaymentPlan(ctx, paymentPlanID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+		}
+
+		return nil, LockPaymentPlanError{planID: paymentPlanID}
+	}
+
+	if plan.UserID != userID {
+		return nil, PaymentRecordNotFoundError{planID: paymentPlanID}
+	}
+
+	return plan, nil
+}
+
+// installmentBalance refundable is what was paid on an installment minus what was already refunded,
+// refundableFees what of it was paid for late fees, owed and owedFees what is left to pay of the amount
+// and of the late fees of an unpaid one
+type installmentBalance struct {
+	refundable     payments.Money
+	refundableFees payments.Money
+	owed           payments.Money
+	owedFees       payments.Money
+}
+
+// refundableInstallmentBalances a paid installment was paid its amount and late fees whether or not payments
+// were recorded against it, the payments on an unpaid one settle its amount before its late fees
+func refundableInstallmentBalances(
+	ctx context.Context,
+	repository repo.Repository,
+	planID uuid.UUID,
+	installments []*payments.Installment,
+) (map[uuid.UUID]installmentBalance, error) {
+	lateFees, err := repository.ListPaymentLateFeesByPlanID(ctx, planID)
+	if err != nil {
+		return nil, ListPaymentLateFeesByPlanIDError{planID: planID}
+	}
+
+	refunds, err := repository.ListPaymentRefundsByPlanID(ctx, planID)
+	if err != nil {
+		return nil, ListPaymentRefundsByPlanIDError{planID: planID}
+	}
+
+	balances := make(map[uuid.UUID]installmentBalance, len(installments))
+
+	for _, inst := range installments {
+		paid, err := paidInstallmentAmount(ctx, repository, inst, lateFees)
+		if err != nil {
+			return nil, err
+		}
+
+		balance := installmentBalance{refundable: paid}
+
+		// what was paid over the amount went to the late fees
+		if balance.refundableFees, err = amountOver(paid, inst.Amount); err != nil {
+			return nil, err
+		}
+
+		if isUnpaidInstallment(inst) {
+			if balance.owed, balance.owedFees, err = owedInstallmentAmounts(inst, paid, lateFees); err != nil {
+				return nil, err
+			}
+		}
+
+		balances[inst.ID] = balance
+	}
+
+	for _, refund := range refunds {
+		balance, ok := balances[refund.PaymentInstallmentID]
+		if !ok {
+			continue
+		}
+
+		if balance.refundable, err = balance.refundable.Sub(refund.Amount); err != nil {
+			return nil, err
+		}
+
+		// the earlier refunds gave back the late fees first
+		if balance.refundableFees, err = amountOver(balance.refundableFees, refund.Amount); err != nil {
+			return nil, err
+		}
+
+		balances[refund.PaymentInstallmentID] = balance
+	}
+
+	return balances, nil
+}
+
+// owedInstallmentAmounts what is left to pay of the amount of an unpaid installment and of its late fees
+func owedInstallmentAmounts(
+	inst *payments.Installment,
+	paid payments.Money,
+	lateFees []*payments.LateFee,
+) (payments.Money, payments.Money, error) {
+	owedFees, err := payments.ZeroMoney(inst.Amount.Currency())
+	if err != nil {
+		return payments.Money{}, payments.Money{}, err
+	}
+
+	for _, lateFee := range lateFees {
+		if lateFee.PaymentInstallmentID != inst.ID {
+			continue
+		}
+
+		if owedFees, err = owedFees.Add(lateFee.Amount); err != nil {
+			return payments.Money{}, payments.Money{}, err
+		}
+	}
+
+	owed, err := inst.Amount.Sub(paid)
+	if err != nil {
+		return payments.Money{}, payments.Money{}, err
+	}
+
+	// what was paid over the amount went to the late fees
+	if owed.Sign() < 0 {
+		if owedFees, err = owedFees.Add(owed); err != nil {
+			return payments.Money{}, payments.Money{}, err
+		}
+
+		if owed, err = payments.ZeroMoney(inst.Amount.Currency()); err != nil {
+			return payments.Money{}, payments.Money{}, err
+		}
+	}
+
+	return owed, owedFees, nil
+}
+
+// amountOver is what amount is over other, zero when it is not
+func amountOver(amount, other payments.Money) (payments.Money, error) {
+	over, err := amount.Sub(other)
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	if over.Sign() < 0 {
+		return payments.ZeroMoney(amount.Currency())
+	}
+
+	return over, nil
+}
+
+func totalInstallmentBalances(
+	currency string,
+	balances map[uuid.UUID]installmentBalance,
+) (payments.Money, payments.Money, error) {
+	totalRefundable, err := payments.ZeroMoney(currency)
+	if err != nil {
+		return payments.Money{}, payments.Money{}, err
+	}
+
+	totalOwed := totalRefundable
+
+	for _, balance := range balances {
+		if totalRefundable, err = totalRefundable.Add(balance.refundable); err != nil {
+			return payments.Money{}, payments.Money{}, err
+		}
+
+		if balance.owed.Sign() > 0 {
+			if totalOwed, err = totalOwed.Add(balance.owed); err != nil {
+				return payments.Money{}, payments.Money{}, err
+			}
+		}
+	}
+
+	return totalRefundable, totalOwed, nil
+}
+
+func paidInstallmentAmount(
+	ctx context.Context,
+	repository repo.Repository,
+	inst *payments.Installment,
+	lateFees []*payments.LateFee,
+) (payments.Money, error) {
+	var err error
+
+	if inst.Status == PaymentInstallmentStatusPaid {
+		paid := inst.Amount
+
+		for _, lateFee := range lateFees {
+			if lateFee.PaymentInstallmentID != inst.ID {
+				continue
+			}
+
+			if paid, err = paid.Add(lateFee.Amount); err != nil {
+				return payments.Money{}, err
+			}
+		}
+
+		return paid, nil
+	}
+
+	transactions, err := repository.ListPaymentTransactionsByInstallmentID(ctx, inst.ID)
+	if err != nil {
+		return payments.Money{}, ListPaymentTransactionsByInstallmentIDError{installmentID: inst.ID}
+	}
+
+	paid, err := payments.ZeroMoney(inst.Amount.Currency())
+	if err != nil {
+		return payments.Money{}, err
+	}
+
+	for _, transaction := range transactions {
+		if paid, err = paid.Add(transaction.Amount); err != nil {
+			return payments.Money{}, err
+		}
+	}
+
+	return paid, nil
+}
+
+// voidUnpaidInstallments returns the installments with their status once voided
+func voidUnpaidInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	installments []*payments.Installment,
+) ([]*payments.Installment, error) {
+	updatedInstallments := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if !isUnpaidInstallment(inst) {
+			updatedInstallments = append(updatedInstallments, inst)
+
+			continue
+		}
+
+		voidedInst, err := updatePaymentInstallmentStatus(ctx, repository, inst, PaymentInstallmentStatusVoid)
+		if err != nil {
+			return nil, err
+		}
+
+		updatedInstallments = append(updatedInstallments, voidedInst)
+	}
+
+	return updatedInstallments, nil
+}
+
+func newInstallmentRefund(refund *payments.Refund) PaymentInstallmentRefund {
+	return PaymentInstallmentRefund{
+		ID:            refund.ID.String(),
+		InstallmentID: refund.PaymentInstallmentID.String(),
+		Amount:        refund.Amount,
+		RefundedAt:    refund.RefundedAt.Format(common.TimeFormat),
+	}
+}
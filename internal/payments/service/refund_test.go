@@ -0,0 +1,829 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_CancelPaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx            = context.Background()
+		userID         = uuid.Must(uuid.NewV4())
+		planID         = uuid.Must(uuid.NewV4())
+		installmentID  = uuid.Must(uuid.NewV4())
+		installmentID2 = uuid.Must(uuid.NewV4())
+		createdAt, _   = time.Parse(common.TimeFormat, "2022-07-01T10:00:00Z")
+		dueAt, _       = time.Parse(common.TimeFormat, "2022-08-01T10:00:00Z")
+		currency       = "usdc"
+
+		plan = &payments.Plan{
+			ID:        planID,
+			UserID:    userID,
+			Amount:    payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status:    paymentPlanStatusPending,
+			CreatedAt: createdAt,
+		}
+
+		installments = []*payments.Installment{
+			{
+				ID:            installmentID,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt,
+				Status:        PaymentInstallmentStatusPending,
+			},
+			{
+				ID:            installmentID2,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt.Add(30 * 24 * time.Hour),
+				Status:        PaymentInstallmentStatusPending,
+			},
+		}
+	)
+
+	cancelledPlan := *plan
+	cancelledPlan.Status = paymentPlanStatusCancelled
+
+	completePlan := *plan
+	completePlan.Status = paymentPlanStatusComplete
+
+	voidedInstallment := *installments[0]
+	voidedInstallment.Status = PaymentInstallmentStatusVoid
+
+	voidedInstallment2 := *installments[1]
+	voidedInstallment2.Status = PaymentInstallmentStatusVoid
+
+	journal := []*ledger.Entry{newLedgerEntry(ledger.NewTransfer(
+		planID, ledger.EntryPlanCreated, ledger.AccountUserReceivable, ledger.AccountMerchantPayable, plan.Amount,
+	))}
+
+	writeOffEntry := &ledger.CreateEntryParams{
+		PaymentPlanID: planID,
+		Kind:          ledger.EntryReceivableWrittenOff,
+		Postings: []ledger.CreatePostingParams{
+			{Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: plan.Amount},
+			{Account: ledger.AccountUserReceivable, Direction: ledger.Credit, Amount: plan.Amount},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		userID  uuid.UUID
+		want    *PaymentPlans
+		wantErr error
+	}{
+		{
+			name: "cancelling a pending plan voids its installments",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID,
+						Status: PaymentInstallmentStatusVoid,
+					}).Return(&voidedInstallment, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID2,
+						Status: PaymentInstallmentStatusVoid,
+					}).Return(&voidedInstallment2, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(journal, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(writeOffEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+						ID:     planID,
+						Status: paymentPlanStatusCancelled,
+					}).Return(&cancelledPlan, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			userID: userID,
+			want: &PaymentPlans{
+				ID:          planID.String(),
+				UserID:      userID.String(),
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), currency),
+				Status:      paymentPlanStatusCancelled,
+				CreatedAt:   "2022-07-01T10:00:00Z",
+				Installments: []PaymentPlanInstallment{
+					{
+						ID:     installmentID.String(),
+						Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:  "2022-08-01T10:00:00Z",
+						Status: PaymentInstallmentStatusVoid,
+					},
+					{
+						ID:     installmentID2.String(),
+						Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:  "2022-08-31T10:00:00Z",
+						Status: PaymentInstallmentStatusVoid,
+					},
+				},
+			},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			userID:  userID,
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "payment plan of another user",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+				)
+			},
+			userID:  uuid.Must(uuid.NewV4()),
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "LockPaymentPlan error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			userID:  userID,
+			wantErr: LockPaymentPlanError{planID: planID},
+		},
+		{
+			name: "payment plan already completed",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&completePlan, nil),
+				)
+			},
+			userID: userID,
+			wantErr: InvalidStateTransitionError{
+				entity: "payment plan", id: planID, from: paymentPlanStatusComplete, to: paymentPlanStatusCancelled,
+			},
+		},
+		{
+			name: "ListPaymentInstallmentsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			userID:  userID,
+			wantErr: ListPaymentInstallmentsByPlanIDError{planID: planID},
+		},
+		{
+			name: "UpdatePaymentInstallmentStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			userID:  userID,
+			wantErr: UpdatePaymentInstallmentStatusError{installmentID: installmentID},
+		},
+		{
+			name: "ListJournalEntriesByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&voidedInstallment, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&voidedInstallment2, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			userID:  userID,
+			wantErr: ListJournalEntriesByPlanIDError{planID: planID},
+		},
+		{
+			name: "UpdatePaymentPlanStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&voidedInstallment, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&voidedInstallment2, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			userID:  userID,
+			wantErr: UpdatePaymentPlanStatusError{planID: planID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.CancelPaymentPlan(ctx, planID, &CancelPaymentPlanParams{UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.CancelPaymentPlan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.CancelPaymentPlan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_refundPaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx            = context.Background()
+		userID         = uuid.Must(uuid.NewV4())
+		planID         = uuid.Must(uuid.NewV4())
+		installmentID  = uuid.Must(uuid.NewV4())
+		installmentID2 = uuid.Must(uuid.NewV4())
+		refundID       = uuid.Must(uuid.NewV4())
+		refundID2      = uuid.Must(uuid.NewV4())
+		dueAt, _       = time.Parse(common.TimeFormat, "2022-07-01T10:00:00Z")
+		now, _         = time.Parse(common.TimeFormat, "2022-07-20T10:00:00Z")
+		currency       = "usdc"
+		reason         = "purchase returned"
+
+		plan = &payments.Plan{
+			ID:     planID,
+			UserID: userID,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status: paymentPlanStatusComplete,
+		}
+
+		// the first installment was paid with a late fee, the second one only partially
+		paidInstallment = &payments.Installment{
+			ID:            installmentID,
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+			DueAt:         dueAt,
+			Status:        PaymentInstallmentStatusPaid,
+		}
+		pendingInstallment = &payments.Installment{
+			ID:            installmentID2,
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+			DueAt:         dueAt.Add(30 * 24 * time.Hour),
+			Status:        PaymentInstallmentStatusPending,
+		}
+		installments = []*payments.Installment{paidInstallment, pendingInstallment}
+
+		lateFees = []*payments.LateFee{
+			{PaymentInstallmentID: installmentID, Amount: payments.MustNewMoney(decimal.New(5, 0), currency)},
+		}
+		transactions = []*payments.Transaction{
+			{PaymentInstallmentID: installmentID2, Amount: payments.MustNewMoney(decimal.New(20, 0), currency)},
+		}
+	)
+
+	refundedPlan := *plan
+	refundedPlan.Status = paymentPlanStatusRefunded
+
+	pendingPlan := *plan
+	pendingPlan.Status = paymentPlanStatusPending
+
+	voidedInstallment := *pendingInstallment
+	voidedInstallment.Status = PaymentInstallmentStatusVoid
+
+	usdc := func(amount int64) payments.Money {
+		return payments.MustNewMoney(decimal.New(amount, 0), currency)
+	}
+
+	reducedInstallment := *pendingInstallment
+	reducedInstallment.Amount = usdc(40)
+
+	// the principal refunded is taken off the merchant payable and the late fees off the fee income, credited is
+	// the cash given back or the receivable the user no longer owes
+	refundedEntry := func(credited string, principal, fees int64) *ledger.CreateEntryParams {
+		entry := &ledger.CreateEntryParams{PaymentPlanID: planID, Kind: ledger.EntryRefunded}
+
+		if principal > 0 {
+			entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+				Account: ledger.AccountMerchantPayable, Direction: ledger.Debit, Amount: usdc(principal),
+			})
+		}
+
+		if fees > 0 {
+			entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+				Account: ledger.AccountFeeIncome, Direction: ledger.Debit, Amount: usdc(fees),
+			})
+		}
+
+		entry.Postings = append(entry.Postings, ledger.CreatePostingParams{
+			Account: credited, Direction: ledger.Credit, Amount: usdc(principal + fees),
+		})
+
+		return entry
+	}
+
+	// the late fee paid was given back off the fee income, the 30 left unpaid are written off the merchant payable
+	writeOffEntry := ledger.NewTransfer(
+		planID, ledger.EntryReceivableWrittenOff, ledger.AccountMerchantPayable, ledger.AccountUserReceivable, usdc(30),
+	)
+
+	journal := []*ledger.Entry{
+		newLedgerEntry(ledger.NewTransfer(
+			planID, ledger.EntryPlanCreated, ledger.AccountUserReceivable, ledger.AccountMerchantPayable, usdc(100),
+		)),
+		newLedgerEntry(ledger.NewTransfer(
+			planID, ledger.EntryLateFeeAssessed, ledger.AccountUserReceivable, ledger.AccountFeeIncome, usdc(5),
+		)),
+		newLedgerEntry(ledger.NewTransfer(
+			planID, ledger.EntryInstallmentPaid, ledger.AccountCash, ledger.AccountUserReceivable, usdc(55),
+		)),
+		newLedgerEntry(ledger.NewTransfer(
+			planID, ledger.EntryInstallmentPaid, ledger.AccountCash, ledger.AccountUserReceivable, usdc(20),
+		)),
+	}
+
+	newRefund := func(id, installmentID uuid.UUID, amount int64) *payments.Refund {
+		return &payments.Refund{
+			ID:                   id,
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(amount, 0), currency),
+			Reason:               reason,
+			RefundedAt:           now,
+		}
+	}
+
+	newRefundParams := func(installmentID uuid.UUID, amount int64) *payments.CreateRefundParams {
+		return &payments.CreateRefundParams{
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(amount, 0), currency),
+			Reason:               reason,
+			RefundedAt:           now,
+		}
+	}
+
+	refundAmount := func(amount *decimal.Big, code string) *payments.Money {
+		money := payments.MustNewMoney(amount, code)
+
+		return &money
+	}
+
+	wantInstallments := []PaymentPlanInstallment{
+		{
+			ID:     installmentID.String(),
+			Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+			DueAt:  "2022-07-01T10:00:00Z",
+			Status: PaymentInstallmentStatusPaid,
+		},
+		{
+			ID:     installmentID2.String(),
+			Amount: payments.MustNewMoney(decimal.New(50, 0), currency),
+			DueAt:  "2022-07-31T10:00:00Z",
+			Status: PaymentInstallmentStatusVoid,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		refund  *RefundPaymentPlanParams
+		want    *PaymentPlanRefund
+		wantErr error
+	}{
+		{
+			name: "full refund gives back what was paid and voids what is left to pay",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Eq(newRefundParams(installmentID2, 20))).
+						Return(newRefund(refundID2, installmentID2, 20), nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Eq(newRefundParams(installmentID, 55))).
+						Return(newRefund(refundID, installmentID, 55), nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountCash, 70, 5))).
+						Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID2,
+						Status: PaymentInstallmentStatusVoid,
+					}).Return(&voidedInstallment, nil),
+					rm.EXPECT().ListJournalEntriesByPlanID(ctx, planID).
+						Return(append(journal, newLedgerEntry(refundedEntry(ledger.AccountCash, 70, 5))), nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(writeOffEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+						ID:     planID,
+						Status: paymentPlanStatusRefunded,
+					}).Return(&refundedPlan, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, mustPlanEvent(outbox.EventPlanRefunded, &refundedPlan)).
+						Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Reason: reason},
+			want: &PaymentPlanRefund{
+				PaymentPlanID: planID.String(),
+				Status:        paymentPlanStatusRefunded,
+				Amount:        payments.MustNewMoney(decimal.New(105, 0), currency),
+				Reason:        reason,
+				Refunds: []PaymentInstallmentRefund{
+					{
+						ID:            refundID2.String(),
+						InstallmentID: installmentID2.String(),
+						Amount:        payments.MustNewMoney(decimal.New(20, 0), currency),
+						RefundedAt:    "2022-07-20T10:00:00Z",
+					},
+					{
+						ID:            refundID.String(),
+						InstallmentID: installmentID.String(),
+						Amount:        payments.MustNewMoney(decimal.New(55, 0), currency),
+						RefundedAt:    "2022-07-20T10:00:00Z",
+					},
+				},
+				Installments: wantInstallments,
+			},
+		},
+		{
+			name: "partial refund lowers what is left to pay and the unpaid installment is still owed",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().UpdatePaymentInstallmentAmount(ctx, &payments.UpdateInstallmentAmountParams{
+						ID:     installmentID2,
+						Amount: usdc(40),
+					}).Return(&reducedInstallment, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountUserReceivable, 10, 0))).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(10, 0), currency), Reason: reason},
+			want: &PaymentPlanRefund{
+				PaymentPlanID: planID.String(),
+				Status:        paymentPlanStatusComplete,
+				Amount:        payments.MustNewMoney(decimal.New(10, 0), currency),
+				Reason:        reason,
+				Refunds:       []PaymentInstallmentRefund{},
+				Installments: []PaymentPlanInstallment{
+					wantInstallments[0],
+					{
+						ID:     installmentID2.String(),
+						Amount: payments.MustNewMoney(decimal.New(40, 0), currency),
+						DueAt:  "2022-07-31T10:00:00Z",
+						Status: PaymentInstallmentStatusPending,
+					},
+				},
+			},
+		},
+		{
+			name: "partial refund over what is left to pay voids the installment and gives back the rest",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID2,
+						Status: PaymentInstallmentStatusVoid,
+					}).Return(&voidedInstallment, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountUserReceivable, 30, 0))).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Eq(newRefundParams(installmentID2, 10))).
+						Return(newRefund(refundID2, installmentID2, 10), nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountCash, 10, 0))).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(40, 0), currency), Reason: reason},
+			want: &PaymentPlanRefund{
+				PaymentPlanID: planID.String(),
+				Status:        paymentPlanStatusComplete,
+				Amount:        payments.MustNewMoney(decimal.New(40, 0), currency),
+				Reason:        reason,
+				Refunds: []PaymentInstallmentRefund{
+					{
+						ID:            refundID2.String(),
+						InstallmentID: installmentID2.String(),
+						Amount:        payments.MustNewMoney(decimal.New(10, 0), currency),
+						RefundedAt:    "2022-07-20T10:00:00Z",
+					},
+				},
+				Installments: wantInstallments,
+			},
+		},
+		{
+			name: "partial refund gives back the late fees paid on an installment first",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&voidedInstallment, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountUserReceivable, 30, 0))).
+						Return(&ledger.Entry{}, nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Eq(newRefundParams(installmentID2, 20))).
+						Return(newRefund(refundID2, installmentID2, 20), nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Eq(newRefundParams(installmentID, 10))).
+						Return(newRefund(refundID, installmentID, 10), nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountCash, 25, 5))).
+						Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(60, 0), currency), Reason: reason},
+			want: &PaymentPlanRefund{
+				PaymentPlanID: planID.String(),
+				Status:        paymentPlanStatusComplete,
+				Amount:        payments.MustNewMoney(decimal.New(60, 0), currency),
+				Reason:        reason,
+				Refunds: []PaymentInstallmentRefund{
+					{
+						ID:            refundID2.String(),
+						InstallmentID: installmentID2.String(),
+						Amount:        payments.MustNewMoney(decimal.New(20, 0), currency),
+						RefundedAt:    "2022-07-20T10:00:00Z",
+					},
+					{
+						ID:            refundID.String(),
+						InstallmentID: installmentID.String(),
+						Amount:        payments.MustNewMoney(decimal.New(10, 0), currency),
+						RefundedAt:    "2022-07-20T10:00:00Z",
+					},
+				},
+				Installments: wantInstallments,
+			},
+		},
+		{
+			name: "refund after the late fees were given back takes the rest off the merchant payable",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return([]*payments.Installment{
+						paidInstallment, &voidedInstallment,
+					}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return([]*payments.Refund{
+						newRefund(refundID, installmentID, 10),
+					}, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(nil, nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Eq(newRefundParams(installmentID, 5))).
+						Return(newRefund(refundID2, installmentID, 5), nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountCash, 5, 0))).
+						Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(5, 0), currency), Reason: reason},
+			want: &PaymentPlanRefund{
+				PaymentPlanID: planID.String(),
+				Status:        paymentPlanStatusComplete,
+				Amount:        payments.MustNewMoney(decimal.New(5, 0), currency),
+				Reason:        reason,
+				Refunds: []PaymentInstallmentRefund{
+					{
+						ID:            refundID2.String(),
+						InstallmentID: installmentID.String(),
+						Amount:        payments.MustNewMoney(decimal.New(5, 0), currency),
+						RefundedAt:    "2022-07-20T10:00:00Z",
+					},
+				},
+				Installments: wantInstallments,
+			},
+		},
+		{
+			name: "partial refund voiding an installment forgives its late fees",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(append(lateFees, &payments.LateFee{
+						PaymentInstallmentID: installmentID2, Amount: usdc(5),
+					}), nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&voidedInstallment, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(refundedEntry(ledger.AccountUserReceivable, 30, 5))).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(30, 0), currency), Reason: reason},
+			want: &PaymentPlanRefund{
+				PaymentPlanID: planID.String(),
+				Status:        paymentPlanStatusComplete,
+				Amount:        payments.MustNewMoney(decimal.New(30, 0), currency),
+				Reason:        reason,
+				Refunds:       []PaymentInstallmentRefund{},
+				Installments:  wantInstallments,
+			},
+		},
+		{
+			name: "payment plan of another user",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: uuid.Must(uuid.NewV4())},
+			wantErr: PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "pending payment plan",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&pendingPlan, nil)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID},
+			wantErr: InvalidStateTransitionError{
+				entity: "payment plan", id: planID, from: paymentPlanStatusPending, to: paymentPlanStatusRefunded,
+			},
+		},
+		{
+			name: "refunded payment plan",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&refundedPlan, nil)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID},
+			wantErr: InvalidStateTransitionError{
+				entity: "payment plan", id: planID, from: paymentPlanStatusRefunded, to: paymentPlanStatusRefunded,
+			},
+		},
+		{
+			name: "currency mismatch",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(10, 0), "usdt")},
+			wantErr: CurrencyMismatchError{field: "amount.currency", expected: currency, actual: "usdt"},
+		},
+		{
+			name: "ListPaymentRefundsByPlanID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID},
+			wantErr: ListPaymentRefundsByPlanIDError{planID: planID},
+		},
+		{
+			name: "invalid amount",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(-1, 0), currency)},
+			wantErr: InvalidAmountError{field: "amount.value", value: "-1"},
+		},
+		{
+			name: "amount more precise than the currency",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil)
+			},
+			refund: &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(10000001, 7), currency)},
+			wantErr: AmountPrecisionError{
+				field: "amount.value", value: "1.0000001", currency: currency, minorUnits: 6,
+			},
+		},
+		{
+			name: "amount exceeds the purchase",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+				)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(10501, 2), currency)},
+			wantErr: RefundExceedsPaidAmountError{amount: "105.01", refundable: "105"},
+		},
+		{
+			name: "UpdatePaymentInstallmentAmount error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().UpdatePaymentInstallmentAmount(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID, Amount: refundAmount(decimal.New(10, 0), currency)},
+			wantErr: UpdatePaymentInstallmentAmountError{installmentID: installmentID2},
+		},
+		{
+			name: "CreatePaymentRefund error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID},
+			wantErr: CreatePaymentRefundError{installmentID: installmentID2},
+		},
+		{
+			name: "CreateJournalEntry error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(pendingInstallment, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(lateFees, nil),
+					rm.EXPECT().ListPaymentRefundsByPlanID(ctx, planID).Return(nil, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return(transactions, nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Any()).Return(newRefund(refundID2, installmentID2, 20), nil),
+					rm.EXPECT().CreatePaymentRefund(ctx, gomock.Any()).Return(newRefund(refundID, installmentID, 55), nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			refund:  &RefundPaymentPlanParams{UserID: userID},
+			wantErr: CreateJournalEntryError{planID: planID, kind: ledger.EntryRefunded},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			got, err := refundPaymentPlan(ctx, rm, planID, tt.refund, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("refundPaymentPlan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("refundPaymentPlan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
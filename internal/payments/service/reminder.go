@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+var _ ReminderService = (*ReminderServiceImp)(nil)
+
+// ReminderServiceImp a reminder is only sent within window of its time, the passes
+// missed for longer than window are not caught up
+type ReminderServiceImp struct {
+	repository repo.Repository
+	notifier   reminder.Notifier
+	reminders  []*reminder.Reminder
+	window     time.Duration
+}
+
+func NewReminderService(reminders []*reminder.Reminder, window time.Duration) *ReminderServiceImp {
+	return &ReminderServiceImp{reminders: reminders, window: window}
+}
+
+func (r *ReminderServiceImp) UseRepo(repository repo.Repository) {
+	r.repository = repository
+}
+
+// UseNotifier no reminder is sent until a notifier is used
+func (r *ReminderServiceImp) UseNotifier(notifier reminder.Notifier) {
+	r.notifier = notifier
+}
+
+// SendInstallmentReminders only reminds the installments of complete plans, a reminder which
+// failed to send is counted as failed and does not stop the others
+func (r *ReminderServiceImp) SendInstallmentReminders(ctx context.Context, now time.Time) (*ReminderRun, error) {
+	run := &ReminderRun{}
+
+	if r.notifier == nil {
+		return run, nil
+	}
+
+	planUsers := make(map[uuid.UUID]uuid.UUID)
+
+	for _, rem := range r.reminders {
+		dueAfter, dueBefore := rem.DueWindow(now, r.window)
+
+		installments, err := r.repository.ListUnpaidPaymentInstallmentsDueBetween(
+			ctx,
+			&payments.ListUnpaidInstallmentsDueBetweenParams{
+				PlanStatus: paymentPlanStatusComplete,
+				DueAfter:   dueAfter,
+				DueBefore:  dueBefore,
+			},
+		)
+		if err != nil {
+			err = ListRemindableInstallmentsError{reminder: rem.Name}
+
+			return nil, fmt.Errorf("send installment reminders: %w", err)
+		}
+
+		for _, inst := range installments {
+			sent, err := r.sendReminder(ctx, rem, inst, now, planUsers)
+			if err != nil {
+				run.Failed++
+
+				continue
+			}
+
+			if sent {
+				run.Sent++
+			}
+		}
+	}
+
+	return run, nil
+}
+
+// sendReminder records the reminder and notifies the user in the same unit of work, a notification which
+// fails leaves the reminder unrecorded. It reports false when the reminder was already sent.
+func (r *ReminderServiceImp) sendReminder(
+	ctx context.Context,
+	rem *reminder.Reminder,
+	inst *payments.Installment,
+	now time.Time,
+	planUsers map[uuid.UUID]uuid.UUID,
+) (bool, error) {
+	userID, ok := planUsers[inst.PaymentPlanID]
+	if !ok {
+		plan, err := r.repository.GetPaymentPlanByID(ctx, inst.PaymentPlanID)
+		if err != nil {
+			return false, GetPaymentPlanByIDError{planID: inst.PaymentPlanID}
+		}
+
+		userID = plan.UserID
+		planUsers[inst.PaymentPlanID] = userID
+	}
+
+	message, err := rem.Render(inst)
+	if err != nil {
+		return false, RenderReminderError{installmentID: inst.ID, reminder: rem.Name}
+	}
+
+	sent := false
+
+	err = r.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		recorded, err := txRepo.CreateReminderSent(ctx, &reminder.CreateSentParams{
+			InstallmentID: inst.ID,
+			Reminder:      rem.Name,
+			SentAt:        now,
+		})
+		if err != nil {
+			return CreateReminderSentError{installmentID: inst.ID, reminder: rem.Name}
+		}
+
+		if !recorded {
+			return nil
+		}
+
+		if err := r.notifier.Notify(ctx, &reminder.Notification{
+			UserID:        userID,
+			PaymentPlanID: inst.PaymentPlanID,
+			InstallmentID: inst.ID,
+			Reminder:      rem.Name,
+			Message:       message,
+		}); err != nil {
+			return NotifyReminderError{installmentID: inst.ID, reminder: rem.Name}
+		}
+
+		sent = true
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("send reminder: %w", err)
+	}
+
+	return sent, nil
+}
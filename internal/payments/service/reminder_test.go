@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/reminder"
+	"golangreferenceapi/internal/payments/repo/memory"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func mustReminder(t *testing.T, name string, offset time.Duration, text string) *reminder.Reminder {
+	t.Helper()
+
+	rem, err := reminder.New(name, offset, text)
+	if err != nil {
+		t.Fatalf("reminder.New() error = %v", err)
+	}
+
+	return rem
+}
+
+func TestReminderServiceImp_SendInstallmentReminders(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		now    = time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+		window = 24 * time.Hour
+		userID = uuid.Must(uuid.NewV4())
+		plan   = &payments.Plan{ID: uuid.Must(uuid.NewV4()), UserID: userID, Status: paymentPlanStatusComplete}
+		inst   = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Amount:        payments.MustNewMoney(decimal.New(2500, 2), "usdc"),
+			DueAt:         now.Add(60 * time.Hour),
+			Status:        PaymentInstallmentStatusPending,
+		}
+		inst2 = &payments.Installment{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Amount:        payments.MustNewMoney(decimal.New(2500, 2), "usdc"),
+			DueAt:         now.Add(70 * time.Hour),
+			Status:        PaymentInstallmentStatusPending,
+		}
+		listArg = &payments.ListUnpaidInstallmentsDueBetweenParams{
+			PlanStatus: paymentPlanStatusComplete,
+			DueAfter:   now.Add(48 * time.Hour),
+			DueBefore:  now.Add(72 * time.Hour),
+		}
+	)
+
+	sentArg := func(inst *payments.Installment) *reminder.CreateSentParams {
+		return &reminder.CreateSentParams{InstallmentID: inst.ID, Reminder: "upcoming", SentAt: now}
+	}
+
+	tests := []struct {
+		name              string
+		text              string
+		prepare           func(rm *repomock.MockRepository)
+		failUser          uuid.UUID
+		want              *ReminderRun
+		wantNotifications []*reminder.Notification
+		wantErr           error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).
+						Return([]*payments.Installment{inst, inst2}, nil),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().CreateReminderSent(ctx, sentArg(inst)).Return(true, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().CreateReminderSent(ctx, sentArg(inst2)).Return(true, nil),
+				)
+			},
+			want: &ReminderRun{Sent: 2},
+			wantNotifications: []*reminder.Notification{
+				{
+					UserID:        userID,
+					PaymentPlanID: plan.ID,
+					InstallmentID: inst.ID,
+					Reminder:      "upcoming",
+					Message:       "25.00 usdc is due on 2022-07-13",
+				},
+				{
+					UserID:        userID,
+					PaymentPlanID: plan.ID,
+					InstallmentID: inst2.ID,
+					Reminder:      "upcoming",
+					Message:       "25.00 usdc is due on 2022-07-13",
+				},
+			},
+		},
+		{
+			name: "reminder already sent",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).
+						Return([]*payments.Installment{inst}, nil),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().CreateReminderSent(ctx, sentArg(inst)).Return(false, nil),
+				)
+			},
+			want: &ReminderRun{},
+		},
+		{
+			name:     "notification failed",
+			failUser: userID,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).
+						Return([]*payments.Installment{inst}, nil),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().CreateReminderSent(ctx, sentArg(inst)).Return(true, nil),
+				)
+			},
+			want: &ReminderRun{Failed: 1},
+		},
+		{
+			name: "render failed",
+			text: "{{.Fee}} is due",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).
+						Return([]*payments.Installment{inst}, nil),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+				)
+			},
+			want: &ReminderRun{Failed: 1},
+		},
+		{
+			name: "ListUnpaidPaymentInstallmentsDueBetween error",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: ListRemindableInstallmentsError{reminder: "upcoming"},
+		},
+		{
+			name: "GetPaymentPlanByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).
+						Return([]*payments.Installment{inst}, nil),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &ReminderRun{Failed: 1},
+		},
+		{
+			name: "CreateReminderSent error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().ListUnpaidPaymentInstallmentsDueBetween(ctx, listArg).
+						Return([]*payments.Installment{inst}, nil),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().CreateReminderSent(ctx, sentArg(inst)).Return(false, fmt.Errorf("dummyErr")),
+				)
+			},
+			want: &ReminderRun{Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			text := tt.text
+			if text == "" {
+				text = "{{.Amount}} {{.Currency}} is due on {{.DueAt}}"
+			}
+
+			notifier := reminder.NewMemoryNotifier()
+			notifier.Fail(tt.failUser, errors.New("dummyErr"))
+
+			r := NewReminderService([]*reminder.Reminder{mustReminder(t, "upcoming", -72*time.Hour, text)}, window)
+			r.UseRepo(rm)
+			r.UseNotifier(notifier)
+
+			got, err := r.SendInstallmentReminders(ctx, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReminderServiceImp.SendInstallmentReminders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReminderServiceImp.SendInstallmentReminders() = %v, want %v", got, tt.want)
+			}
+
+			notifications := notifier.Notifications()
+			if len(notifications) != len(tt.wantNotifications) {
+				t.Fatalf("sent %d notifications, want %d", len(notifications), len(tt.wantNotifications))
+			}
+
+			for idx, notification := range notifications {
+				if !reflect.DeepEqual(notification, tt.wantNotifications[idx]) {
+					t.Errorf("notification %d = %+v, want %+v", idx, notification, tt.wantNotifications[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestReminderServiceImp_SendInstallmentRemindersWithoutNotifier(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upcoming := mustReminder(t, "upcoming", -72*time.Hour, "{{.Amount}} is due")
+
+	r := NewReminderService([]*reminder.Reminder{upcoming}, time.Hour)
+	r.UseRepo(repomock.NewMockRepository(ctrl))
+
+	got, err := r.SendInstallmentReminders(context.Background(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("ReminderServiceImp.SendInstallmentReminders() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, &ReminderRun{}) {
+		t.Errorf("ReminderServiceImp.SendInstallmentReminders() = %v, want nothing sent", got)
+	}
+}
+
+func TestReminderServiceImp_SendInstallmentReminders_Schedule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		dueAt   = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		memRepo = memory.NewInMemRepository()
+		userID  = uuid.Must(uuid.NewV4())
+	)
+
+	plan, err := memRepo.CreatePaymentPlan(ctx, &payments.CreatePlanParams{
+		UserID: userID,
+		Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+		Status: paymentPlanStatusComplete,
+	})
+	if err != nil {
+		t.Fatalf("fail to create payment plan: %v", err)
+	}
+
+	inst, err := memRepo.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+		PaymentPlanID: plan.ID,
+		Amount:        payments.MustNewMoney(decimal.New(25, 0), "usdc"),
+		DueAt:         dueAt,
+		Status:        PaymentInstallmentStatusPending,
+	})
+	if err != nil {
+		t.Fatalf("fail to create installment: %v", err)
+	}
+
+	notifier := reminder.NewMemoryNotifier()
+	r := NewReminderService([]*reminder.Reminder{
+		mustReminder(t, "upcoming", -72*time.Hour, "{{.Amount}} {{.Currency}} is due on {{.DueAt}}"),
+		mustReminder(t, "due", 0, "{{.Amount}} {{.Currency}} is due today"),
+		mustReminder(t, "late", 24*time.Hour, "{{.Amount}} {{.Currency}} is {{.Status}}"),
+	}, 24*time.Hour)
+	r.UseRepo(memRepo)
+	r.UseNotifier(notifier)
+
+	send := func(now time.Time) *ReminderRun {
+		run, err := r.SendInstallmentReminders(ctx, now)
+		if err != nil {
+			t.Fatalf("ReminderServiceImp.SendInstallmentReminders() error = %v", err)
+		}
+
+		return run
+	}
+
+	// a reminder sent once is not sent again by the next passes
+	if run := send(dueAt.Add(-71 * time.Hour)); run.Sent != 1 {
+		t.Errorf("3 days before: sent %d reminders, want 1", run.Sent)
+	}
+
+	if run := send(dueAt.Add(-70 * time.Hour)); run.Sent != 0 {
+		t.Errorf("3 days before again: sent %d reminders, want 0", run.Sent)
+	}
+
+	// a failed reminder is sent by the next pass
+	notifier.Fail(userID, errors.New("mail server unavailable"))
+
+	if run := send(dueAt.Add(time.Hour)); run.Sent != 0 || run.Failed != 1 {
+		t.Errorf("due date while failing: got %+v, want 1 failed", run)
+	}
+
+	notifier.Fail(userID, nil)
+
+	if run := send(dueAt.Add(2 * time.Hour)); run.Sent != 1 {
+		t.Errorf("due date: sent %d reminders, want 1", run.Sent)
+	}
+
+	if _, err := memRepo.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     inst.ID,
+		Status: PaymentInstallmentStatusPaid,
+	}); err != nil {
+		t.Fatalf("fail to pay installment: %v", err)
+	}
+
+	// a paid installment is not reminded
+	if run := send(dueAt.Add(25 * time.Hour)); run.Sent != 0 {
+		t.Errorf("day after a paid installment: sent %d reminders, want 0", run.Sent)
+	}
+
+	got := notifier.Notifications()
+	want := []string{"25 usdc is due on 2022-07-10", "25 usdc is due today"}
+
+	if len(got) != len(want) {
+		t.Fatalf("sent %d notifications, want %d", len(got), len(want))
+	}
+
+	for idx, notification := range got {
+		if notification.Message != want[idx] || notification.UserID != userID {
+			t.Errorf("notification %d = %+v, want %q to %v", idx, notification, want[idx], userID)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// RecordInstallmentPayment records money received against an installment,
+// the installment is marked paid once its payments cover its amount
+func (p *PaymentServiceImp) RecordInstallmentPayment(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+	installmentID uuid.UUID,
+	payment *InstallmentPaymentParams,
+) (*InstallmentPayment, error) {
+	var recorded *InstallmentPayment
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		recorded, txErr = recordInstallmentPayment(ctx, txRepo, paymentPlanID, installmentID, payment)
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record installment payment: %w", err)
+	}
+
+	return recorded, nil
+}
+
+func recordInstallmentPayment(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	installmentID uuid.UUID,
+	payment *InstallmentPaymentParams,
+) (*InstallmentPayment, error) {
+	if err := checkPositiveAmount(payment.Amount, "amount"); err != nil {
+		return nil, err
+	}
+
+	if payment.ProcessorReference == "" {
+		return nil, MissingProcessorReferenceError{}
+	}
+
+	// the plan is locked before its installment like every unit of work changing the plan,
+	// the installment stays locked until the payment is recorded so concurrent payments cannot overpay it
+	if _, err := repository.LockPaymentPlan(ctx, paymentPlanID); err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, InstallmentNotFoundError{planID: paymentPlanID, installmentID: installmentID}
+		}
+
+		return nil, LockPaymentPlanError{planID: paymentPlanID}
+	}
+
+	inst, err := repository.LockPaymentInstallment(ctx, installmentID)
+	if err != nil {
+		if errors.Is(err, repo.ErrRecordNotFound) {
+			return nil, InstallmentNotFoundError{planID: paymentPlanID, installmentID: installmentID}
+		}
+
+		return nil, LockPaymentInstallmentError{installmentID: installmentID}
+	}
+
+	if inst.PaymentPlanID != paymentPlanID {
+		return nil, InstallmentNotFoundError{planID: paymentPlanID, installmentID: installmentID}
+	}
+
+	if inst.Status == PaymentInstallmentStatusPaid {
+		return nil, InstallmentAlreadyPaidError{installmentID: inst.ID}
+	}
+
+	if inst.Status == PaymentInstallmentStatusVoid {
+		return nil, InstallmentVoidError{installmentID: inst.ID}
+	}
+
+	if inst.Status == PaymentInstallmentStatusSuperseded {
+		return nil, InstallmentSupersededError{installmentID: inst.ID}
+	}
+
+	if err := checkSameCurrency(payment.Amount, "amount", inst.Amount.Currency()); err != nil {
+		return nil, err
+	}
+
+	outstanding, err := outstandingInstallmentAmount(ctx, repository, inst)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmp, err := payment.Amount.Cmp(outstanding); err != nil || cmp > 0 {
+		return nil, OverpaymentError{
+			installmentID: inst.ID,
+			amount:        payment.Amount.String(),
+			outstanding:   outstanding.String(),
+		}
+	}
+
+	before := audit.NewInstallmentState(inst)
+	before.Outstanding = &outstanding
+
+	paidAt := payment.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now().UTC()
+	}
+
+	transaction, err := repository.CreatePaymentTransaction(ctx, &payments.CreateTransactionParams{
+		PaymentInstallmentID: inst.ID,
+		Amount:               payment.Amount,
+		ProcessorReference:   payment.ProcessorReference,
+		PaidAt:               paidAt,
+	})
+	if err != nil {
+		return nil, CreatePaymentTransactionError{installmentID: inst.ID}
+	}
+
+	if err := postJournalEntry(ctx, repository, ledger.NewTransfer(
+		inst.PaymentPlanID, ledger.EntryInstallmentPaid, ledger.AccountCash, ledger.AccountUserReceivable,
+		transaction.Amount,
+	)); err != nil {
+		return nil, err
+	}
+
+	outstanding, err = outstanding.Sub(transaction.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedInst := inst
+
+	if outstanding.Sign() == 0 {
+		updatedInst, err = updatePaymentInstallmentStatus(ctx, repository, inst, PaymentInstallmentStatusPaid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	after := audit.NewInstallmentState(updatedInst)
+	after.Outstanding = &outstanding
+
+	if err := recordAuditEntry(
+		ctx, repository, inst.PaymentPlanID, audit.ActionInstallmentPaymentRecorded,
+		&audit.Snapshot{Installments: []audit.InstallmentState{before}},
+		&audit.Snapshot{Installments: []audit.InstallmentState{after}},
+	); err != nil {
+		return nil, err
+	}
+
+	return &InstallmentPayment{
+		ID:                 transaction.ID.String(),
+		InstallmentID:      transaction.PaymentInstallmentID.String(),
+		Amount:             transaction.Amount,
+		ProcessorReference: transaction.ProcessorReference,
+		PaidAt:             transaction.PaidAt.Format(common.TimeFormat),
+		InstallmentStatus:  updatedInst.Status,
+		OutstandingAmount:  outstanding,
+	}, nil
+}
+
+// outstandingInstallmentAmount is the installment amount and its late fees minus every payment
+// already recorded against it
+func outstandingInstallmentAmount(
+	ctx context.Context,
+	repository repo.Repository,
+	inst *payments.Installment,
+) (payments.Money, error) {
+	transactions, err := repository.ListPaymentTransactionsByInstallmentID(ctx, inst.ID)
+	if err != nil {
+		return payments.Money{}, ListPaymentTransactionsByInstallmentIDError{installmentID: inst.ID}
+	}
+
+	lateFees, err := repository.ListPaymentLateFeesByInstallmentID(ctx, inst.ID)
+	if err != nil {
+		return payments.Money{}, ListPaymentLateFeesByInstallmentIDError{installmentID: inst.ID}
+	}
+
+	outstanding := inst.Amount
+
+	for _, lateFee := range lateFees {
+		if outstanding, err = outstanding.Add(lateFee.Amount); err != nil {
+			return payments.Money{}, err
+		}
+	}
+
+	for _, transaction := range transactions {
+		if outstanding, err = outstanding.Sub(transaction.Amount); err != nil {
+			return payments.Money{}, err
+		}
+	}
+
+	return outstanding, nil
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_RecordInstallmentPayment(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx           = context.Background()
+		planID        = uuid.Must(uuid.NewV4())
+		installmentID = uuid.Must(uuid.NewV4())
+		transactionID = uuid.Must(uuid.NewV4())
+		paidAt, _     = time.Parse(common.TimeFormat, "2022-07-01T10:00:00Z")
+		currency      = "usdc"
+
+		installment = &payments.Installment{
+			ID:            installmentID,
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status:        PaymentInstallmentStatusPending,
+		}
+
+		previousTransactions = []*payments.Transaction{
+			{PaymentInstallmentID: installmentID, Amount: payments.MustNewMoney(decimal.New(60, 0), currency)},
+		}
+
+		lateFees = []*payments.LateFee{
+			{PaymentInstallmentID: installmentID, Amount: payments.MustNewMoney(decimal.New(5, 0), currency)},
+		}
+
+		paymentParams = &InstallmentPaymentParams{
+			Amount:             payments.MustNewMoney(decimal.New(40, 0), currency),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt,
+		}
+
+		createTransactionParams = &payments.CreateTransactionParams{
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(40, 0), currency),
+			ProcessorReference:   "psp-ref-1",
+			PaidAt:               paidAt,
+		}
+
+		transaction = &payments.Transaction{
+			ID:                   transactionID,
+			PaymentInstallmentID: installmentID,
+			Amount:               payments.MustNewMoney(decimal.New(40, 0), currency),
+			ProcessorReference:   "psp-ref-1",
+			PaidAt:               paidAt,
+		}
+
+		installmentPaidEntry = ledger.NewTransfer(planID, ledger.EntryInstallmentPaid, ledger.AccountCash,
+			ledger.AccountUserReceivable, payments.MustNewMoney(decimal.New(40, 0), currency))
+	)
+
+	paidInstallment := *installment
+	paidInstallment.Status = PaymentInstallmentStatusPaid
+
+	voidInstallment := *installment
+	voidInstallment.Status = PaymentInstallmentStatusVoid
+
+	supersededInstallment := *installment
+	supersededInstallment.Status = PaymentInstallmentStatusSuperseded
+
+	otherPlanInstallment := *installment
+	otherPlanInstallment.PaymentPlanID = uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		payment *InstallmentPaymentParams
+		want    *InstallmentPayment
+		wantErr error
+	}{
+		{
+			name: "payment covering the installment marks it paid",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(previousTransactions, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentTransaction(ctx, gomock.Eq(createTransactionParams)).Return(transaction, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(installmentPaidEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID,
+						Status: PaymentInstallmentStatusPaid,
+					}).Return(&paidInstallment, nil),
+					rm.EXPECT().CreateOutboxEvent(ctx, gomock.Eq(mustInstallmentEvent(outbox.EventInstallmentPaid, &paidInstallment))).
+						Return(&outbox.Event{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			payment: paymentParams,
+			want: &InstallmentPayment{
+				ID:                 transactionID.String(),
+				InstallmentID:      installmentID.String(),
+				Amount:             payments.MustNewMoney(decimal.New(40, 0), currency),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             "2022-07-01T10:00:00Z",
+				InstallmentStatus:  PaymentInstallmentStatusPaid,
+				OutstandingAmount:  payments.MustNewMoney(decimal.New(0, 0), currency),
+			},
+		},
+		{
+			name: "partial payment keeps the installment open",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentTransaction(ctx, gomock.Eq(createTransactionParams)).Return(transaction, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(installmentPaidEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			payment: paymentParams,
+			want: &InstallmentPayment{
+				ID:                 transactionID.String(),
+				InstallmentID:      installmentID.String(),
+				Amount:             payments.MustNewMoney(decimal.New(40, 0), currency),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             "2022-07-01T10:00:00Z",
+				InstallmentStatus:  PaymentInstallmentStatusPending,
+				OutstandingAmount:  payments.MustNewMoney(decimal.New(60, 0), currency),
+			},
+		},
+		{
+			name: "late fees are owed on top of the installment amount",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(previousTransactions, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(lateFees, nil),
+					rm.EXPECT().CreatePaymentTransaction(ctx, gomock.Eq(createTransactionParams)).Return(transaction, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(installmentPaidEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+				)
+			},
+			payment: paymentParams,
+			want: &InstallmentPayment{
+				ID:                 transactionID.String(),
+				InstallmentID:      installmentID.String(),
+				Amount:             payments.MustNewMoney(decimal.New(40, 0), currency),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             "2022-07-01T10:00:00Z",
+				InstallmentStatus:  PaymentInstallmentStatusPending,
+				OutstandingAmount:  payments.MustNewMoney(decimal.New(5, 0), currency),
+			},
+		},
+		{
+			name: "invalid amount",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			payment: &InstallmentPaymentParams{Amount: payments.MustNewMoney(decimal.New(-1, 0), currency), ProcessorReference: "psp-ref-1"},
+			wantErr: InvalidAmountError{field: "amount.value", value: "-1"},
+		},
+		{
+			name: "missing processor reference",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			payment: &InstallmentPaymentParams{Amount: payments.MustNewMoney(decimal.New(40, 0), currency)},
+			wantErr: MissingProcessorReferenceError{},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			payment: paymentParams,
+			wantErr: InstallmentNotFoundError{planID: planID, installmentID: installmentID},
+		},
+		{
+			name: "LockPaymentPlan error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: LockPaymentPlanError{planID: planID},
+		},
+		{
+			name: "installment not found",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			payment: paymentParams,
+			wantErr: InstallmentNotFoundError{planID: planID, installmentID: installmentID},
+		},
+		{
+			name: "installment belongs to another plan",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(&otherPlanInstallment, nil),
+				)
+			},
+			payment: paymentParams,
+			wantErr: InstallmentNotFoundError{planID: planID, installmentID: installmentID},
+		},
+		{
+			name: "LockPaymentInstallment error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: LockPaymentInstallmentError{installmentID: installmentID},
+		},
+		{
+			name: "installment already paid",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(&paidInstallment, nil),
+				)
+			},
+			payment: paymentParams,
+			wantErr: InstallmentAlreadyPaidError{installmentID: installmentID},
+		},
+		{
+			name: "installment voided by a cancellation or refund",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(&voidInstallment, nil),
+				)
+			},
+			payment: paymentParams,
+			wantErr: InstallmentVoidError{installmentID: installmentID},
+		},
+		{
+			name: "installment superseded by a reschedule",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(&supersededInstallment, nil),
+				)
+			},
+			payment: paymentParams,
+			wantErr: InstallmentSupersededError{installmentID: installmentID},
+		},
+		{
+			name: "currency mismatch",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+				)
+			},
+			payment: &InstallmentPaymentParams{Amount: payments.MustNewMoney(decimal.New(40, 0), "usdt"), ProcessorReference: "psp-ref-1"},
+			wantErr: CurrencyMismatchError{field: "amount.currency", expected: currency, actual: "usdt"},
+		},
+		{
+			name: "amount more precise than the currency",
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm))
+			},
+			payment: &InstallmentPaymentParams{Amount: payments.MustNewMoney(decimal.New(400000001, 7), currency), ProcessorReference: "psp-ref-1"},
+			wantErr: AmountPrecisionError{field: "amount.value", value: "40.0000001", currency: currency, minorUnits: 6},
+		},
+		{
+			name: "ListPaymentTransactionsByInstallmentID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: ListPaymentTransactionsByInstallmentIDError{installmentID: installmentID},
+		},
+		{
+			name: "ListPaymentLateFeesByInstallmentID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(previousTransactions, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: ListPaymentLateFeesByInstallmentIDError{installmentID: installmentID},
+		},
+		{
+			name: "amount exceeds the outstanding amount",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(previousTransactions, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+				)
+			},
+			payment: &InstallmentPaymentParams{Amount: payments.MustNewMoney(decimal.New(4001, 2), currency), ProcessorReference: "psp-ref-1"},
+			wantErr: OverpaymentError{installmentID: installmentID, amount: "40.01", outstanding: "40"},
+		},
+		{
+			name: "CreatePaymentTransaction error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(previousTransactions, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentTransaction(ctx, gomock.Eq(createTransactionParams)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: CreatePaymentTransactionError{installmentID: installmentID},
+		},
+		{
+			name: "CreateJournalEntry error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentTransaction(ctx, gomock.Eq(createTransactionParams)).Return(transaction, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(installmentPaidEntry)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: CreateJournalEntryError{planID: planID, kind: ledger.EntryInstallmentPaid},
+		},
+		{
+			name: "UpdatePaymentInstallmentStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&payments.Plan{ID: planID}, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID).Return(installment, nil),
+					rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID).Return(previousTransactions, nil),
+					rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID).Return(nil, nil),
+					rm.EXPECT().CreatePaymentTransaction(ctx, gomock.Eq(createTransactionParams)).Return(transaction, nil),
+					rm.EXPECT().CreateJournalEntry(ctx, gomock.Eq(installmentPaidEntry)).Return(&ledger.Entry{}, nil),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			payment: paymentParams,
+			wantErr: UpdatePaymentInstallmentStatusError{installmentID: installmentID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.RecordInstallmentPayment(ctx, planID, installmentID, tt.payment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.RecordInstallmentPayment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.RecordInstallmentPayment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outstandingInstallmentAmount(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx         = context.Background()
+		installment = &payments.Installment{ID: uuid.Must(uuid.NewV4())}
+		err         error
+	)
+
+	installment.Amount, err = payments.ParseMoney("1234567890123456.1234567890123456", "usdc")
+	if err != nil {
+		t.Fatalf("ParseMoney() error = %v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rm := repomock.NewMockRepository(ctrl)
+	rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installment.ID).Return([]*payments.Transaction{
+		{Amount: payments.MustNewMoney(decimal.New(1, 16), "usdc")},
+	}, nil)
+	rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installment.ID).Return([]*payments.LateFee{
+		{Amount: payments.MustNewMoney(decimal.New(2, 16), "usdc")},
+	}, nil)
+
+	got, err := outstandingInstallmentAmount(ctx, rm, installment)
+	if err != nil {
+		t.Fatalf("outstandingInstallmentAmount() error = %v", err)
+	}
+
+	if want := "1234567890123456.1234567890123457"; got.String() != want {
+		t.Errorf("outstandingInstallmentAmount() = %v, want %v", got.String(), want)
+	}
+}
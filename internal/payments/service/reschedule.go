@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/gofrs/uuid"
+)
+
+// ReschedulePaymentPlan spreads what is left to pay on a completed plan over new installments,
+// the unpaid installments are superseded and kept with their payments and late fees as history
+func (p *PaymentServiceImp) ReschedulePaymentPlan(
+	ctx context.Context,
+	paymentPlanID uuid.UUID,
+	reschedule *ReschedulePaymentPlanParams,
+) (*PaymentPlans, error) {
+	var rescheduledPlan *PaymentPlans
+
+	err := p.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		var txErr error
+
+		rescheduledPlan, txErr = reschedulePaymentPlan(ctx, txRepo, paymentPlanID, reschedule, time.Now().UTC())
+
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reschedule payment plan: %w", err)
+	}
+
+	return rescheduledPlan, nil
+}
+
+func reschedulePaymentPlan(
+	ctx context.Context,
+	repository repo.Repository,
+	paymentPlanID uuid.UUID,
+	reschedule *ReschedulePaymentPlanParams,
+	now time.Time,
+) (*PaymentPlans, error) {
+	plan, err := lockUserPaymentPlan(ctx, repository, paymentPlanID, reschedule.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Status != paymentPlanStatusComplete {
+		return nil, PaymentPlanNotReschedulableError{planID: plan.ID, status: plan.Status}
+	}
+
+	installments, err := repository.ListPaymentInstallmentsByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentInstallmentsByPlanIDError{planID: plan.ID}
+	}
+
+	// the unpaid installments stay locked so a concurrent payment cannot land on a superseded one
+	installments, err = lockUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	before := audit.NewPlanSnapshot(plan, installments)
+
+	outstanding, err := outstandingPayoffAmount(ctx, repository, plan.ID, unpaidInstallments(installments))
+	if err != nil {
+		return nil, err
+	}
+
+	// the new installments are validated like the ones of a new plan whose total is the outstanding amount
+	newSchedule, err := withScheduledInstallments(&CreatePaymentPlanParams{
+		UserID:       plan.UserID,
+		TotalAmount:  outstanding,
+		Installments: reschedule.Installments,
+		Schedule:     reschedule.Schedule,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCreatePaymentPlanParams(newSchedule, now); err != nil {
+		return nil, err
+	}
+
+	installments, err = supersedeUnpaidInstallments(ctx, repository, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	version := nextScheduleVersion(installments)
+
+	for _, inst := range newSchedule.Installments {
+		newInst, err := repository.CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+			PaymentPlanID: plan.ID,
+			Amount:        inst.Amount,
+			DueAt:         inst.DueAt,
+			Status:        PaymentInstallmentStatusPending,
+			Version:       version,
+		})
+		if err != nil {
+			return nil, CreatePaymentInstallmentError{}
+		}
+
+		installments = append(installments, newInst)
+	}
+
+	if err := recordAuditEntry(
+		ctx, repository, plan.ID, audit.ActionPlanRescheduled, before, audit.NewPlanSnapshot(plan, installments),
+	); err != nil {
+		return nil, err
+	}
+
+	lateFees, err := repository.ListPaymentLateFeesByPlanID(ctx, plan.ID)
+	if err != nil {
+		return nil, ListPaymentLateFeesByPlanIDError{planID: plan.ID}
+	}
+
+	return newPaymentPlans(plan, installments, lateFees), nil
+}
+
+// supersedeUnpaidInstallments returns the installments with their status once the unpaid ones are superseded
+func supersedeUnpaidInstallments(
+	ctx context.Context,
+	repository repo.Repository,
+	installments []*payments.Installment,
+) ([]*payments.Installment, error) {
+	updatedInstallments := make([]*payments.Installment, 0, len(installments))
+
+	for _, inst := range installments {
+		if !isUnpaidInstallment(inst) {
+			updatedInstallments = append(updatedInstallments, inst)
+
+			continue
+		}
+
+		supersededInst, err := updatePaymentInstallmentStatus(ctx, repository, inst, PaymentInstallmentStatusSuperseded)
+		if err != nil {
+			return nil, err
+		}
+
+		updatedInstallments = append(updatedInstallments, supersededInst)
+	}
+
+	return updatedInstallments, nil
+}
+
+// firstScheduleVersion is the version of the installments created with a plan
+const firstScheduleVersion = 1
+
+func nextScheduleVersion(installments []*payments.Installment) int32 {
+	var latest int32 = firstScheduleVersion
+
+	for _, inst := range installments {
+		if inst.Version > latest {
+			latest = inst.Version
+		}
+	}
+
+	return latest + 1
+}
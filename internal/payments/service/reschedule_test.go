@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/repo"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPaymentServiceImp_ReschedulePaymentPlan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx              = context.Background()
+		userID           = uuid.Must(uuid.NewV4())
+		planID           = uuid.Must(uuid.NewV4())
+		installmentID    = uuid.Must(uuid.NewV4())
+		installmentID2   = uuid.Must(uuid.NewV4())
+		newInstallmentID = uuid.Must(uuid.NewV4())
+		dueAt, _         = time.Parse(common.TimeFormat, "2022-08-01T10:00:00Z")
+		newDueAt         = time.Now().UTC().Add(30 * 24 * time.Hour).Truncate(time.Second)
+		currency         = "usdc"
+
+		plan = &payments.Plan{
+			ID:     planID,
+			UserID: userID,
+			Amount: payments.MustNewMoney(decimal.New(100, 0), currency),
+			Status: paymentPlanStatusComplete,
+		}
+
+		installments = []*payments.Installment{
+			{
+				ID:            installmentID,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt,
+				Status:        PaymentInstallmentStatusPaid,
+				Version:       firstScheduleVersion,
+			},
+			{
+				ID:            installmentID2,
+				PaymentPlanID: planID,
+				Amount:        payments.MustNewMoney(decimal.New(50, 0), currency),
+				DueAt:         dueAt.Add(30 * 24 * time.Hour),
+				Status:        PaymentInstallmentStatusOverdue,
+				Version:       firstScheduleVersion,
+			},
+		}
+
+		newInstallment = &payments.Installment{
+			ID:            newInstallmentID,
+			PaymentPlanID: planID,
+			Amount:        payments.MustNewMoney(decimal.New(35, 0), currency),
+			DueAt:         newDueAt,
+			Status:        PaymentInstallmentStatusPending,
+			Version:       firstScheduleVersion + 1,
+		}
+
+		reschedule = &ReschedulePaymentPlanParams{
+			UserID: userID,
+			Installments: []PaymentPlanInstallmentParams{
+				{Amount: payments.MustNewMoney(decimal.New(35, 0), currency), DueAt: newDueAt},
+			},
+		}
+	)
+
+	pendingPlan := *plan
+	pendingPlan.Status = paymentPlanStatusPending
+
+	supersededInstallment := *installments[1]
+	supersededInstallment.Status = PaymentInstallmentStatusSuperseded
+
+	paidInstallment := *installments[1]
+	paidInstallment.Status = PaymentInstallmentStatusPaid
+
+	// lockOutstanding expects the overdue installment to be locked with 35 left to pay on it, late fee included
+	lockOutstanding := func(rm *repomock.MockRepository) []*gomock.Call {
+		return []*gomock.Call{
+			rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+			rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+			rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+			rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(installments[1], nil),
+			rm.EXPECT().ListPaymentTransactionsByInstallmentID(ctx, installmentID2).Return([]*payments.Transaction{
+				{Amount: payments.MustNewMoney(decimal.New(20, 0), "usdc")},
+			}, nil),
+			rm.EXPECT().ListPaymentLateFeesByInstallmentID(ctx, installmentID2).Return([]*payments.LateFee{
+				{Amount: payments.MustNewMoney(decimal.New(5, 0), "usdc")},
+			}, nil),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		prepare    func(rm *repomock.MockRepository)
+		reschedule *ReschedulePaymentPlanParams
+		want       *PaymentPlans
+		wantErr    error
+	}{
+		{
+			name: "the unpaid installments are superseded by the new ones",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+						ID:     installmentID2,
+						Status: PaymentInstallmentStatusSuperseded,
+					}).Return(&supersededInstallment, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, &payments.CreateInstallmentParams{
+						PaymentPlanID: planID,
+						Amount:        payments.MustNewMoney(decimal.New(35, 0), currency),
+						DueAt:         newDueAt,
+						Status:        PaymentInstallmentStatusPending,
+						Version:       firstScheduleVersion + 1,
+					}).Return(newInstallment, nil),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(nil, nil),
+				)...)
+			},
+			reschedule: reschedule,
+			want: &PaymentPlans{
+				ID:          planID.String(),
+				UserID:      userID.String(),
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), currency),
+				Status:      paymentPlanStatusComplete,
+				CreatedAt:   plan.CreatedAt.Format(common.TimeFormat),
+				Installments: []PaymentPlanInstallment{
+					{
+						ID:      installmentID.String(),
+						Amount:  payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:   "2022-08-01T10:00:00Z",
+						Status:  PaymentInstallmentStatusPaid,
+						Version: firstScheduleVersion,
+					},
+					{
+						ID:      newInstallmentID.String(),
+						Amount:  payments.MustNewMoney(decimal.New(35, 0), currency),
+						DueAt:   newDueAt.Format(common.TimeFormat),
+						Status:  PaymentInstallmentStatusPending,
+						Version: firstScheduleVersion + 1,
+					},
+				},
+				History: []PaymentPlanInstallment{
+					{
+						ID:      installmentID2.String(),
+						Amount:  payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:   "2022-08-31T10:00:00Z",
+						Status:  PaymentInstallmentStatusSuperseded,
+						Version: firstScheduleVersion,
+					},
+				},
+			},
+		},
+		{
+			name: "the new installments are generated from a schedule",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&supersededInstallment, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Any()).Return(newInstallment, nil).Times(2),
+					rm.EXPECT().GetLatestAuditEntryByPlanID(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+					rm.EXPECT().CreateAuditEntry(ctx, gomock.Any()).Return(&audit.Entry{}, nil),
+					rm.EXPECT().ListPaymentLateFeesByPlanID(ctx, planID).Return(nil, nil),
+				)...)
+			},
+			reschedule: &ReschedulePaymentPlanParams{
+				UserID:   userID,
+				Schedule: &PaymentPlanScheduleParams{Count: 2, Frequency: scheduleFrequencyMonthly, StartAt: newDueAt},
+			},
+			want: &PaymentPlans{
+				ID:          planID.String(),
+				UserID:      userID.String(),
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), currency),
+				Status:      paymentPlanStatusComplete,
+				CreatedAt:   plan.CreatedAt.Format(common.TimeFormat),
+				Installments: []PaymentPlanInstallment{
+					{
+						ID:      installmentID.String(),
+						Amount:  payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:   "2022-08-01T10:00:00Z",
+						Status:  PaymentInstallmentStatusPaid,
+						Version: firstScheduleVersion,
+					},
+					{
+						ID:      newInstallmentID.String(),
+						Amount:  payments.MustNewMoney(decimal.New(35, 0), currency),
+						DueAt:   newDueAt.Format(common.TimeFormat),
+						Status:  PaymentInstallmentStatusPending,
+						Version: firstScheduleVersion + 1,
+					},
+					{
+						ID:      newInstallmentID.String(),
+						Amount:  payments.MustNewMoney(decimal.New(35, 0), currency),
+						DueAt:   newDueAt.Format(common.TimeFormat),
+						Status:  PaymentInstallmentStatusPending,
+						Version: firstScheduleVersion + 1,
+					},
+				},
+				History: []PaymentPlanInstallment{
+					{
+						ID:      installmentID2.String(),
+						Amount:  payments.MustNewMoney(decimal.New(50, 0), currency),
+						DueAt:   "2022-08-31T10:00:00Z",
+						Status:  PaymentInstallmentStatusSuperseded,
+						Version: firstScheduleVersion,
+					},
+				},
+			},
+		},
+		{
+			name: "payment plan not found",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			reschedule: reschedule,
+			wantErr:    PaymentRecordNotFoundError{planID: planID},
+		},
+		{
+			name: "pending payment plan",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(&pendingPlan, nil),
+				)
+			},
+			reschedule: reschedule,
+			wantErr:    PaymentPlanNotReschedulableError{planID: planID, status: paymentPlanStatusPending},
+		},
+		{
+			name: "every installment already paid",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockPaymentPlan(ctx, planID).Return(plan, nil),
+					rm.EXPECT().ListPaymentInstallmentsByPlanID(ctx, planID).Return(installments, nil),
+					rm.EXPECT().LockPaymentInstallment(ctx, installmentID2).Return(&paidInstallment, nil),
+				)
+			},
+			reschedule: reschedule,
+			wantErr:    PaymentPlanAlreadyPaidError{planID: planID},
+		},
+		{
+			name:    "installments do not add up to the outstanding amount",
+			prepare: func(rm *repomock.MockRepository) { gomock.InOrder(lockOutstanding(rm)...) },
+			reschedule: &ReschedulePaymentPlanParams{
+				UserID: userID,
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(50, 0), currency), DueAt: newDueAt},
+				},
+			},
+			wantErr: InstallmentSumMismatchError{totalAmount: "35", installmentsSum: "50"},
+		},
+		{
+			name:    "installments and schedule together",
+			prepare: func(rm *repomock.MockRepository) { gomock.InOrder(lockOutstanding(rm)...) },
+			reschedule: &ReschedulePaymentPlanParams{
+				UserID:       userID,
+				Installments: reschedule.Installments,
+				Schedule:     &PaymentPlanScheduleParams{Count: 2, Frequency: scheduleFrequencyMonthly, StartAt: newDueAt},
+			},
+			wantErr: ScheduleConflictError{},
+		},
+		{
+			name: "UpdatePaymentInstallmentStatus error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			reschedule: reschedule,
+			wantErr:    UpdatePaymentInstallmentStatusError{installmentID: installmentID2},
+		},
+		{
+			name: "CreatePaymentInstallment error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(append(lockOutstanding(rm),
+					rm.EXPECT().UpdatePaymentInstallmentStatus(ctx, gomock.Any()).Return(&supersededInstallment, nil),
+					rm.EXPECT().CreatePaymentInstallment(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)...)
+			},
+			reschedule: reschedule,
+			wantErr:    CreatePaymentInstallmentError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			p := &PaymentServiceImp{repository: rm}
+
+			got, err := p.ReschedulePaymentPlan(ctx, planID, tt.reschedule)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PaymentServiceImp.ReschedulePaymentPlan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PaymentServiceImp.ReschedulePaymentPlan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nextScheduleVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		installments []*payments.Installment
+		want         int32
+	}{
+		{
+			name:         "installments created with the plan",
+			installments: []*payments.Installment{{Version: 1}, {Version: 1}},
+			want:         2,
+		},
+		{
+			name:         "installments already rescheduled",
+			installments: []*payments.Installment{{Version: 1}, {Version: 3}, {Version: 2}},
+			want:         4,
+		},
+		{
+			name:         "installments created before versioning",
+			installments: []*payments.Installment{{}},
+			want:         2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := nextScheduleVersion(tt.installments); got != tt.want {
+				t.Errorf("nextScheduleVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
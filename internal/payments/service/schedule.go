@@ -0,0 +1,150 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+)
+
+const (
+	scheduleFrequencyWeekly   = "weekly"
+	scheduleFrequencyBiweekly = "biweekly"
+	scheduleFrequencyMonthly  = "monthly"
+
+	scheduleRemainderFirst = "first"
+	scheduleRemainderLast  = "last"
+
+	maxScheduleCount = 120
+)
+
+// withScheduledInstallments returns a copy of the params whose installments are generated from the schedule,
+// params without a schedule are returned as they are
+func withScheduledInstallments(paymentPlan *CreatePaymentPlanParams, now time.Time) (*CreatePaymentPlanParams, error) {
+	if paymentPlan.Schedule == nil {
+		return paymentPlan, nil
+	}
+
+	if len(paymentPlan.Installments) != 0 {
+		return nil, ScheduleConflictError{}
+	}
+
+	if err := checkPositiveAmount(paymentPlan.TotalAmount, "total_amount"); err != nil {
+		return nil, err
+	}
+
+	installments, err := generateInstallmentSchedule(paymentPlan.TotalAmount, paymentPlan.Schedule, now)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := *paymentPlan
+	scheduled.Installments = installments
+
+	return &scheduled, nil
+}
+
+// generateInstallmentSchedule splits totalAmount in schedule.Count installments rounded down to the minor unit
+// of the currency, the rounding remainder is added to the first installment unless the schedule asks for the last one.
+// The first installment is due at schedule.StartAt and the next ones one frequency apart, a monthly
+// installment falls on the last day of the month when the month is shorter than the start day.
+func generateInstallmentSchedule(
+	totalAmount payments.Money,
+	schedule *PaymentPlanScheduleParams,
+	now time.Time,
+) ([]PaymentPlanInstallmentParams, error) {
+	if err := validateSchedule(schedule, now); err != nil {
+		return nil, err
+	}
+
+	remainderIdx := 0
+	if schedule.Remainder == scheduleRemainderLast {
+		remainderIdx = schedule.Count - 1
+	}
+
+	amounts, err := totalAmount.Split(schedule.Count, remainderIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	// every installment but the one with the remainder has the smallest amount
+	if amounts[(remainderIdx+1)%schedule.Count].Sign() <= 0 {
+		return nil, InvalidScheduleError{
+			field:    "schedule.count",
+			value:    strconv.Itoa(schedule.Count),
+			expected: "a count leaving every installment at least one minor unit",
+		}
+	}
+
+	installments := make([]PaymentPlanInstallmentParams, schedule.Count)
+
+	for idx := range installments {
+		installments[idx] = PaymentPlanInstallmentParams{
+			Amount: amounts[idx],
+			DueAt:  scheduledDueAt(schedule.StartAt, schedule.Frequency, idx),
+		}
+	}
+
+	return installments, nil
+}
+
+func validateSchedule(schedule *PaymentPlanScheduleParams, now time.Time) error {
+	if schedule.Count < 1 || schedule.Count > maxScheduleCount {
+		return InvalidScheduleError{
+			field:    "schedule.count",
+			value:    strconv.Itoa(schedule.Count),
+			expected: "a count between 1 and " + strconv.Itoa(maxScheduleCount),
+		}
+	}
+
+	switch schedule.Frequency {
+	case scheduleFrequencyWeekly, scheduleFrequencyBiweekly, scheduleFrequencyMonthly:
+	default:
+		return InvalidScheduleError{
+			field:    "schedule.frequency",
+			value:    schedule.Frequency,
+			expected: "one of weekly, biweekly or monthly",
+		}
+	}
+
+	switch schedule.Remainder {
+	case "", scheduleRemainderFirst, scheduleRemainderLast:
+	default:
+		return InvalidScheduleError{
+			field:    "schedule.remainder",
+			value:    schedule.Remainder,
+			expected: "first or last",
+		}
+	}
+
+	if schedule.StartAt.Before(now) {
+		return PastDueDateError{field: "schedule.start_at", dueAt: schedule.StartAt}
+	}
+
+	return nil
+}
+
+func scheduledDueAt(startAt time.Time, frequency string, idx int) time.Time {
+	switch frequency {
+	case scheduleFrequencyWeekly:
+		return startAt.AddDate(0, 0, 7*idx)
+	case scheduleFrequencyBiweekly:
+		return startAt.AddDate(0, 0, 14*idx)
+	default:
+		return addMonthsClamped(startAt, idx)
+	}
+}
+
+// addMonthsClamped unlike time.AddDate never overflows into the following month,
+// January 31st plus one month is February 28th or 29th
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(year, month+time.Month(months), day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func Test_generateInstallmentSchedule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now     = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		startAt = time.Date(2022, 7, 31, 10, 0, 0, 0, time.UTC)
+		past    = now.Add(-1 * time.Second)
+	)
+
+	tests := []struct {
+		name         string
+		currency     string
+		totalAmount  string
+		schedule     *PaymentPlanScheduleParams
+		wantAmounts  []string
+		wantDueDates []time.Time
+		wantErr      error
+	}{
+		{
+			name:        "monthly with the remainder on the first installment",
+			currency:    "usd",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 3, Frequency: "monthly", StartAt: startAt},
+			wantAmounts: []string{"33.34", "33.33", "33.33"},
+			wantDueDates: []time.Time{
+				startAt,
+				time.Date(2022, 8, 31, 10, 0, 0, 0, time.UTC),
+				time.Date(2022, 9, 30, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "monthly with the remainder on the last installment",
+			currency:    "usd",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 3, Frequency: "monthly", StartAt: startAt, Remainder: "last"},
+			wantAmounts: []string{"33.33", "33.33", "33.34"},
+			wantDueDates: []time.Time{
+				startAt,
+				time.Date(2022, 8, 31, 10, 0, 0, 0, time.UTC),
+				time.Date(2022, 9, 30, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "monthly from the end of january falls on the end of february",
+			currency:    "usd",
+			totalAmount: "50",
+			schedule: &PaymentPlanScheduleParams{
+				Count: 2, Frequency: "monthly", StartAt: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			},
+			wantAmounts: []string{"25", "25"},
+			wantDueDates: []time.Time{
+				time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "monthly in a currency with six decimal places",
+			currency:    "usdc",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 3, Frequency: "monthly", StartAt: startAt},
+			wantAmounts: []string{"33.333334", "33.333333", "33.333333"},
+			wantDueDates: []time.Time{
+				startAt,
+				time.Date(2022, 8, 31, 10, 0, 0, 0, time.UTC),
+				time.Date(2022, 9, 30, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "weekly",
+			currency:    "usd",
+			totalAmount: "10",
+			schedule:    &PaymentPlanScheduleParams{Count: 3, Frequency: "weekly", StartAt: startAt},
+			wantAmounts: []string{"3.34", "3.33", "3.33"},
+			wantDueDates: []time.Time{
+				startAt,
+				startAt.AddDate(0, 0, 7),
+				startAt.AddDate(0, 0, 14),
+			},
+		},
+		{
+			name:        "biweekly rounds to the minor unit of the currency",
+			currency:    "usdc",
+			totalAmount: "10.005",
+			schedule:    &PaymentPlanScheduleParams{Count: 2, Frequency: "biweekly", StartAt: startAt},
+			wantAmounts: []string{"5.0025", "5.0025"},
+			wantDueDates: []time.Time{
+				startAt,
+				startAt.AddDate(0, 0, 14),
+			},
+		},
+		{
+			name:        "count out of range",
+			currency:    "usd",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 0, Frequency: "monthly", StartAt: startAt},
+			wantErr:     InvalidScheduleError{field: "schedule.count", value: "0", expected: "a count between 1 and 120"},
+		},
+		{
+			name:        "unknown frequency",
+			currency:    "usd",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 2, Frequency: "daily", StartAt: startAt},
+			wantErr: InvalidScheduleError{
+				field: "schedule.frequency", value: "daily", expected: "one of weekly, biweekly or monthly",
+			},
+		},
+		{
+			name:        "unknown remainder policy",
+			currency:    "usd",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 2, Frequency: "weekly", StartAt: startAt, Remainder: "middle"},
+			wantErr:     InvalidScheduleError{field: "schedule.remainder", value: "middle", expected: "first or last"},
+		},
+		{
+			name:        "start date in the past",
+			currency:    "usd",
+			totalAmount: "100",
+			schedule:    &PaymentPlanScheduleParams{Count: 2, Frequency: "weekly", StartAt: past},
+			wantErr:     PastDueDateError{field: "schedule.start_at", dueAt: past},
+		},
+		{
+			name:        "installments below one minor unit",
+			currency:    "usd",
+			totalAmount: "0.02",
+			schedule:    &PaymentPlanScheduleParams{Count: 3, Frequency: "weekly", StartAt: startAt},
+			wantErr: InvalidScheduleError{
+				field: "schedule.count", value: "3", expected: "a count leaving every installment at least one minor unit",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			totalAmount, err := payments.ParseMoney(tt.totalAmount, tt.currency)
+			if err != nil {
+				t.Fatalf("ParseMoney() error = %v", err)
+			}
+
+			got, err := generateInstallmentSchedule(totalAmount, tt.schedule, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("generateInstallmentSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			gotAmounts := make([]string, 0, len(got))
+			gotDueDates := make([]time.Time, 0, len(got))
+
+			for _, inst := range got {
+				if inst.Amount.Currency() != tt.currency {
+					t.Errorf("unexpected currency %v", inst.Amount.Currency())
+				}
+
+				gotAmounts = append(gotAmounts, inst.Amount.String())
+				gotDueDates = append(gotDueDates, inst.DueAt)
+			}
+
+			if !reflect.DeepEqual(gotAmounts, tt.wantAmounts) {
+				t.Errorf("unexpected amounts, got %v want %v", gotAmounts, tt.wantAmounts)
+			}
+
+			if !reflect.DeepEqual(gotDueDates, tt.wantDueDates) {
+				t.Errorf("unexpected due dates, got %v want %v", gotDueDates, tt.wantDueDates)
+			}
+		})
+	}
+}
+
+func Test_withScheduledInstallments(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now     = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		startAt = now.Add(24 * time.Hour)
+	)
+
+	tests := []struct {
+		name             string
+		params           *CreatePaymentPlanParams
+		wantInstallments int
+		wantErr          error
+	}{
+		{
+			name: "listed installments are kept",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"), DueAt: startAt},
+				},
+			},
+			wantInstallments: 1,
+		},
+		{
+			name: "installments are generated from the schedule",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Schedule:    &PaymentPlanScheduleParams{Count: 4, Frequency: "biweekly", StartAt: startAt},
+			},
+			wantInstallments: 4,
+		},
+		{
+			name: "installments and schedule together",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"), DueAt: startAt},
+				},
+				Schedule: &PaymentPlanScheduleParams{Count: 4, Frequency: "biweekly", StartAt: startAt},
+			},
+			wantErr: ScheduleConflictError{},
+		},
+		{
+			name: "missing total amount",
+			params: &CreatePaymentPlanParams{
+				Schedule: &PaymentPlanScheduleParams{Count: 4, Frequency: "biweekly", StartAt: startAt},
+			},
+			wantErr: UnsupportedCurrencyError{field: "total_amount.currency", value: ""},
+		},
+		{
+			name: "negative total amount",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(-100, 0), "usdc"),
+				Schedule:    &PaymentPlanScheduleParams{Count: 4, Frequency: "biweekly", StartAt: startAt},
+			},
+			wantErr: InvalidAmountError{field: "total_amount.value", value: "-100"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := withScheduledInstallments(tt.params, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("withScheduledInstallments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(got.Installments) != tt.wantInstallments {
+				t.Errorf("unexpected installments %v", got.Installments)
+			}
+
+			// the caller's params are left untouched
+			if tt.params.Schedule != nil && len(tt.params.Installments) != 0 {
+				t.Errorf("params were modified: %v", tt.params.Installments)
+			}
+
+			if err := validateCreatePaymentPlanParams(got, now); err != nil {
+				t.Errorf("generated installments are not valid: %v", err)
+			}
+		})
+	}
+}
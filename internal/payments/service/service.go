@@ -4,13 +4,18 @@ import (
 	"context"
 	"time"
 
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/ledger"
+
 	"github.com/gofrs/uuid"
 )
 
 //go:generate mockgen -source=./service.go -destination=../mock/servicemock/service_mock.go -package=servicemock
 type PaymentPlanService interface {
-	// GetPaymentPlanByUserID gets payment plans selected by userID
-	GetPaymentPlanByUserID(ctx context.Context, userID uuid.UUID) ([]PaymentPlans, error)
+	// GetPaymentPlanByUserID gets payment plans selected by userID with their current schedule,
+	// the installments superseded by reschedules are only returned withHistory
+	GetPaymentPlanByUserID(ctx context.Context, userID uuid.UUID, withHistory bool) ([]PaymentPlans, error)
 
 	// CreatePendingPaymentPlan
 	CreatePendingPaymentPlan(
@@ -24,40 +29,544 @@ type PaymentPlanService interface {
 		paymentPlanID uuid.UUID,
 		paymentPlan *CompletePaymentPlanParams,
 	) (*PaymentPlans, error)
+
+	// RecordInstallmentPayment records a full or partial payment of an installment
+	RecordInstallmentPayment(
+		ctx context.Context,
+		paymentPlanID uuid.UUID,
+		installmentID uuid.UUID,
+		payment *InstallmentPaymentParams,
+	) (*InstallmentPayment, error)
+
+	// CancelPaymentPlan cancels a pending payment plan and voids its installments
+	CancelPaymentPlan(
+		ctx context.Context,
+		paymentPlanID uuid.UUID,
+		paymentPlan *CancelPaymentPlanParams,
+	) (*PaymentPlans, error)
+
+	// RefundPaymentPlan refunds a completed payment plan fully or partially, only a full refund voids
+	// the unpaid installments
+	RefundPaymentPlan(
+		ctx context.Context,
+		paymentPlanID uuid.UUID,
+		refund *RefundPaymentPlanParams,
+	) (*PaymentPlanRefund, error)
+
+	// ReschedulePaymentPlan replaces the unpaid installments of a payment plan, the replaced ones are kept as history
+	ReschedulePaymentPlan(
+		ctx context.Context,
+		paymentPlanID uuid.UUID,
+		reschedule *ReschedulePaymentPlanParams,
+	) (*PaymentPlans, error)
+
+	// QuotePaymentPlanPayoff computes the amount due now to pay off a payment plan
+	QuotePaymentPlanPayoff(
+		ctx context.Context,
+		paymentPlanID uuid.UUID,
+		userID uuid.UUID,
+	) (*PaymentPlanPayoffQuote, error)
+
+	// PayOffPaymentPlan settles every unpaid installment of a payment plan at once
+	PayOffPaymentPlan(
+		ctx context.Context,
+		paymentPlanID uuid.UUID,
+		payoff *PaymentPlanPayoffParams,
+	) (*PaymentPlanPayoff, error)
+
+	// UpdateInstallmentsPastDue flags the installments which are due or overdue at now
+	UpdateInstallmentsPastDue(
+		ctx context.Context,
+		now time.Time,
+		gracePeriod time.Duration,
+	) (*InstallmentsPastDue, error)
+
+	// AssessLateFees charges the overdue installments at now
+	AssessLateFees(ctx context.Context, now time.Time) (*LateFeeRun, error)
+
+	// ExpirePendingPaymentPlans cancels the plans still pending ttl after they were created
+	ExpirePendingPaymentPlans(ctx context.Context, now time.Time, ttl time.Duration) ([]PaymentPlans, error)
+
+	// GetPaymentPlanLedger returns the account balances and the journal entries of a payment plan
+	GetPaymentPlanLedger(ctx context.Context, paymentPlanID uuid.UUID) (*PaymentPlanLedger, error)
+
+	// GetPaymentPlanAudit returns the audit trail of a payment plan and whether it was tampered with
+	GetPaymentPlanAudit(ctx context.Context, paymentPlanID uuid.UUID) (*PaymentPlanAudit, error)
+
+	// RelayOutboxEvents publishes up to limit outbox events, the oldest first
+	RelayOutboxEvents(ctx context.Context, now time.Time, limit int) (*OutboxRelay, error)
+}
+
+type CreditLineService interface {
+	// GetCreditLine gets the credit line of the user with what is still available on it
+	GetCreditLine(ctx context.Context, userID uuid.UUID) (*CreditLine, error)
+
+	// CreateCreditLine opens an active credit line for a user who has none
+	CreateCreditLine(ctx context.Context, userID uuid.UUID, creditLine *CreateCreditLineParams) (*CreditLine, error)
+
+	// UpdateCreditLineLimit raises or lowers the limit of a credit line which is not closed
+	UpdateCreditLineLimit(ctx context.Context, userID uuid.UUID, limit *UpdateCreditLineLimitParams) (*CreditLine, error)
+
+	// FreezeCreditLine stops an active credit line from taking new payment plans
+	FreezeCreditLine(ctx context.Context, userID uuid.UUID, change *CreditLineChangeParams) (*CreditLine, error)
+
+	// UnfreezeCreditLine makes a frozen credit line active again
+	UnfreezeCreditLine(ctx context.Context, userID uuid.UUID, change *CreditLineChangeParams) (*CreditLine, error)
+
+	// CloseCreditLine closes a credit line for good, its running payment plans are left as they are
+	CloseCreditLine(ctx context.Context, userID uuid.UUID, change *CreditLineChangeParams) (*CreditLine, error)
+
+	// ListCreditLineChanges lists who changed the credit line of the user and why, the oldest change first
+	ListCreditLineChanges(ctx context.Context, userID uuid.UUID) ([]CreditLineChange, error)
+}
+
+type WebhookService interface {
+	// RegisterWebhookEndpoint adds an endpoint to the merchant, the secret signing its deliveries is only returned here
+	RegisterWebhookEndpoint(
+		ctx context.Context,
+		merchantID uuid.UUID,
+		endpoint *RegisterWebhookEndpointParams,
+	) (*WebhookEndpoint, error)
+
+	// DeliverWebhooks sends up to limit deliveries which are due at now, the earliest due first
+	DeliverWebhooks(ctx context.Context, now time.Time, limit int) (*WebhookDeliveryRun, error)
+
+	// ListWebhookDeliveries lists the deliveries in status, the latest first
+	ListWebhookDeliveries(ctx context.Context, status string) ([]WebhookDelivery, error)
+
+	// ReplayWebhookDelivery sends a dead delivery again at now with all of its attempts
+	ReplayWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, now time.Time) (*WebhookDelivery, error)
+}
+
+type ReminderService interface {
+	// SendInstallmentReminders sends the reminders of the unpaid installments which fell due
+	// within the window before now, each reminder only once per installment
+	SendInstallmentReminders(ctx context.Context, now time.Time) (*ReminderRun, error)
+}
+
+type CollectionsService interface {
+	// RunCollections closes the cases whose plan has no overdue installment left, opens a case for
+	// the delinquent plans then climbs each due case one stage, up to limit of each at now
+	RunCollections(ctx context.Context, now time.Time, limit int) (*CollectionsRun, error)
+
+	// GetCollectionsCase gets a case with its history, the oldest step first
+	GetCollectionsCase(ctx context.Context, caseID uuid.UUID) (*CollectionsCase, error)
+
+	// ListCollectionsCases lists the cases in status without their history, the latest first
+	ListCollectionsCases(ctx context.Context, status string) ([]CollectionsCase, error)
+
+	// PauseCollectionsCase stops an open case from climbing the ladder until it is resumed
+	PauseCollectionsCase(
+		ctx context.Context,
+		caseID uuid.UUID,
+		change *CollectionsCaseChangeParams,
+	) (*CollectionsCase, error)
+
+	// ResumeCollectionsCase opens a paused case again, the wait before its next stage starts over at now
+	ResumeCollectionsCase(
+		ctx context.Context,
+		caseID uuid.UUID,
+		now time.Time,
+		change *CollectionsCaseChangeParams,
+	) (*CollectionsCase, error)
+
+	// CloseCollectionsCase closes a case for good at now, the plan gets a new case if it is still delinquent
+	CloseCollectionsCase(
+		ctx context.Context,
+		caseID uuid.UUID,
+		now time.Time,
+		change *CollectionsCaseChangeParams,
+	) (*CollectionsCase, error)
+}
+
+type MerchantService interface {
+	// CreateMerchant records a merchant the payment plans can then be created for
+	CreateMerchant(ctx context.Context, merchant *CreateMerchantParams) (*Merchant, error)
+
+	// ListMerchantPaymentPlans lists a page of the plans of the merchant with their current schedule
+	ListMerchantPaymentPlans(
+		ctx context.Context,
+		merchantID uuid.UUID,
+		page *ListMerchantPaymentPlansParams,
+	) ([]PaymentPlans, error)
+
+	// GetPaymentPlanByOrderReference reads the plan paying for an order of the merchant
+	GetPaymentPlanByOrderReference(ctx context.Context, merchantID uuid.UUID, orderReference string) (*PaymentPlans, error)
+
+	// GetMerchantTotals sums per currency the complete and refunded plans of the merchant created from from to to
+	GetMerchantTotals(ctx context.Context, merchantID uuid.UUID, from time.Time, to time.Time) (*MerchantTotals, error)
 }
 
 type PaymentPlanInstallment struct {
-	ID       string `json:"id"`
-	Amount   string `json:"amount"`
-	Currency string `json:"currency"`
-	DueAt    string `json:"due_at"`
-	Status   string `json:"status"`
+	ID       string               `json:"id"`
+	Amount   payments.Money       `json:"amount"`
+	DueAt    string               `json:"due_at"`
+	Status   string               `json:"status"`
+	Version  int32                `json:"version,omitempty"`
+	LateFees []PaymentPlanLateFee `json:"late_fees,omitempty"`
+}
+
+type PaymentPlanLateFee struct {
+	ID            string         `json:"id"`
+	InstallmentID string         `json:"installment_id"`
+	Amount        payments.Money `json:"amount"`
+	AssessedAt    string         `json:"assessed_at"`
+}
+
+// LateFeeRun is what a late fee pass charged, an installment which failed is counted
+// as failed and does not stop the others
+type LateFeeRun struct {
+	Assessed []PaymentPlanLateFee `json:"assessed"`
+	Failed   int                  `json:"failed"`
 }
 
+// PaymentPlans MerchantID is empty for the plans created before merchants were recorded,
+// LineItems are listed when the plans are created or read, not in the responses to their other changes
 type PaymentPlans struct {
-	ID           string `json:"id"`
-	UserID       string `json:"user_id"`
-	Currency     string `json:"currency"`
-	TotalAmount  string `json:"total_amount"`
-	Status       string `json:"status"`
-	CreatedAt    string `json:"created_at"`
-	Installments []PaymentPlanInstallment
+	ID             string                `json:"id"`
+	UserID         string                `json:"user_id"`
+	MerchantID     string                `json:"merchant_id,omitempty"`
+	OrderReference string                `json:"order_reference,omitempty"`
+	Description    string                `json:"description,omitempty"`
+	TotalAmount    payments.Money        `json:"total_amount"`
+	Status         string                `json:"status"`
+	CreatedAt      string                `json:"created_at"`
+	TotalLateFees  *payments.Money       `json:"total_late_fees,omitempty"`
+	LineItems      []PaymentPlanLineItem `json:"line_items,omitempty"`
+	Installments   []PaymentPlanInstallment
+	History        []PaymentPlanInstallment `json:"history,omitempty"`
+}
+
+type PaymentPlanLineItem struct {
+	SKU       string         `json:"sku"`
+	Name      string         `json:"name"`
+	Quantity  int32          `json:"quantity"`
+	UnitPrice payments.Money `json:"unit_price"`
 }
 
 type PaymentPlanInstallmentParams struct {
-	Amount   string    `json:"amount"`
-	Currency string    `json:"currency"`
-	DueAt    time.Time `json:"due_at"`
+	Amount payments.Money `json:"amount"`
+	DueAt  time.Time      `json:"due_at"`
+}
+
+// PaymentPlanScheduleParams has the installments generated by the service instead of listed by the caller,
+// Frequency is weekly, biweekly or monthly and Remainder is first, the default, or last
+type PaymentPlanScheduleParams struct {
+	Count     int       `json:"count"`
+	Frequency string    `json:"frequency"`
+	StartAt   time.Time `json:"start_at"`
+	Remainder string    `json:"remainder,omitempty"`
 }
 
+// CreatePaymentPlanParams MerchantID is the merchant the purchase was made from, it has to be created first,
+// OrderReference is the order of the merchant the plan pays for, a merchant order is paid by a single plan
 type CreatePaymentPlanParams struct {
-	ID           uuid.UUID `json:"id"`
-	UserID       uuid.UUID `json:"user_id"`
-	Currency     string    `json:"currency"`
-	TotalAmount  string    `json:"total_amount"`
-	Installments []PaymentPlanInstallmentParams
+	ID             uuid.UUID                   `json:"id"`
+	UserID         uuid.UUID                   `json:"user_id"`
+	MerchantID     uuid.UUID                   `json:"merchant_id"`
+	OrderReference string                      `json:"order_reference,omitempty"`
+	Description    string                      `json:"description,omitempty"`
+	LineItems      []PaymentPlanLineItemParams `json:"line_items,omitempty"`
+	TotalAmount    payments.Money              `json:"total_amount"`
+	Installments   []PaymentPlanInstallmentParams
+	Schedule       *PaymentPlanScheduleParams `json:"schedule,omitempty"`
+}
+
+// PaymentPlanLineItemParams UnitPrice is in the currency of the plan, the line items are not
+// required to add up to the plan amount
+type PaymentPlanLineItemParams struct {
+	SKU       string         `json:"sku"`
+	Name      string         `json:"name"`
+	Quantity  int32          `json:"quantity"`
+	UnitPrice payments.Money `json:"unit_price"`
 }
 
 type CompletePaymentPlanParams struct {
 	UserID uuid.UUID `json:"user_id"`
 }
+
+type CancelPaymentPlanParams struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type InstallmentPaymentParams struct {
+	Amount             payments.Money `json:"amount"`
+	ProcessorReference string         `json:"processor_reference"`
+	PaidAt             time.Time      `json:"paid_at"`
+}
+
+type InstallmentPayment struct {
+	ID                 string         `json:"id"`
+	InstallmentID      string         `json:"installment_id"`
+	Amount             payments.Money `json:"amount"`
+	ProcessorReference string         `json:"processor_reference"`
+	PaidAt             string         `json:"paid_at"`
+	InstallmentStatus  string         `json:"installment_status"`
+	OutstandingAmount  payments.Money `json:"outstanding_amount"`
+}
+
+type InstallmentsPastDue struct {
+	Due     []PaymentPlanInstallment `json:"due"`
+	Overdue []PaymentPlanInstallment `json:"overdue"`
+}
+
+// RefundPaymentPlanParams a missing Amount refunds the whole purchase, what was paid and what is left to pay
+type RefundPaymentPlanParams struct {
+	UserID uuid.UUID       `json:"user_id"`
+	Amount *payments.Money `json:"amount,omitempty"`
+	Reason string          `json:"reason"`
+}
+
+type PaymentInstallmentRefund struct {
+	ID            string         `json:"id"`
+	InstallmentID string         `json:"installment_id"`
+	Amount        payments.Money `json:"amount"`
+	RefundedAt    string         `json:"refunded_at"`
+}
+
+// PaymentPlanRefund Amount is what was taken off the purchase, Refunds what was given back of it
+type PaymentPlanRefund struct {
+	PaymentPlanID string                     `json:"payment_plan_id"`
+	Status        string                     `json:"status"`
+	Amount        payments.Money             `json:"amount"`
+	Reason        string                     `json:"reason"`
+	Refunds       []PaymentInstallmentRefund `json:"refunds"`
+	Installments  []PaymentPlanInstallment   `json:"installments"`
+}
+
+// ReschedulePaymentPlanParams the new installments are either listed or generated from a schedule,
+// they have to add up to what is left to pay on the installments they replace
+type ReschedulePaymentPlanParams struct {
+	UserID       uuid.UUID                      `json:"user_id"`
+	Installments []PaymentPlanInstallmentParams `json:"installments"`
+	Schedule     *PaymentPlanScheduleParams     `json:"schedule,omitempty"`
+}
+
+type PaymentPlanPayoffQuote struct {
+	PaymentPlanID string                   `json:"payment_plan_id"`
+	Amount        payments.Money           `json:"amount"`
+	Installments  []PaymentPlanInstallment `json:"installments"`
+}
+
+type PaymentPlanPayoffParams struct {
+	UserID             uuid.UUID      `json:"user_id"`
+	Amount             payments.Money `json:"amount"`
+	ProcessorReference string         `json:"processor_reference"`
+	PaidAt             time.Time      `json:"paid_at"`
+}
+
+type PaymentPlanPayoff struct {
+	ID                 string                   `json:"id"`
+	PaymentPlanID      string                   `json:"payment_plan_id"`
+	Amount             payments.Money           `json:"amount"`
+	ProcessorReference string                   `json:"processor_reference"`
+	PaidAt             string                   `json:"paid_at"`
+	Status             string                   `json:"status"`
+	Installments       []PaymentPlanInstallment `json:"installments"`
+}
+
+// PaymentPlanLedger Balances lists every account of the ledger, Entries are the oldest first
+type PaymentPlanLedger struct {
+	PaymentPlanID string           `json:"payment_plan_id"`
+	Balances      []ledger.Balance `json:"balances"`
+	Entries       []JournalEntry   `json:"entries"`
+}
+
+type JournalEntry struct {
+	ID        string           `json:"id"`
+	Kind      string           `json:"kind"`
+	Postings  []JournalPosting `json:"postings"`
+	CreatedAt string           `json:"created_at"`
+}
+
+type JournalPosting struct {
+	Account   string         `json:"account"`
+	Direction string         `json:"direction"`
+	Amount    payments.Money `json:"amount"`
+}
+
+// PaymentPlanAudit Entries are the oldest first, BrokenAt is the first entry which
+// does not match its hash or the entry before it when the trail is not Intact
+type PaymentPlanAudit struct {
+	PaymentPlanID string       `json:"payment_plan_id"`
+	Intact        bool         `json:"intact"`
+	BrokenAt      *int64       `json:"broken_at,omitempty"`
+	Entries       []AuditEntry `json:"entries"`
+}
+
+// AuditEntry Before is null for the creation of the plan
+type AuditEntry struct {
+	Sequence   int64           `json:"sequence"`
+	Actor      audit.Actor     `json:"actor"`
+	Action     string          `json:"action"`
+	Before     *audit.Snapshot `json:"before"`
+	After      *audit.Snapshot `json:"after"`
+	RequestID  string          `json:"request_id,omitempty"`
+	OccurredAt string          `json:"occurred_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+// CreditLine the available amount is the limit minus what the user still owes on their installments
+type CreditLine struct {
+	ID              string         `json:"id"`
+	UserID          string         `json:"user_id"`
+	TotalAmount     payments.Money `json:"total_amount"`
+	AvailableAmount payments.Money `json:"available_amount"`
+	Status          string         `json:"status"`
+}
+
+// CreateCreditLineParams Actor and Reason are recorded with the change,
+// the actor is the caller of the internal API and is not read from the request body
+type CreateCreditLineParams struct {
+	Limit  payments.Money `json:"limit"`
+	Actor  string         `json:"-"`
+	Reason string         `json:"reason"`
+}
+
+// UpdateCreditLineLimitParams Limit is in the currency of the credit line, Actor and Reason are recorded
+// with the change, the actor is the caller of the internal API and is not read from the request body
+type UpdateCreditLineLimitParams struct {
+	Limit  payments.Money `json:"limit"`
+	Actor  string         `json:"-"`
+	Reason string         `json:"reason"`
+}
+
+// CreditLineChangeParams Actor and Reason are recorded with the change,
+// the actor is the caller of the internal API and is not read from the request body
+type CreditLineChangeParams struct {
+	Actor  string `json:"-"`
+	Reason string `json:"reason"`
+}
+
+// CreditLineChange Limit and Status are the ones the credit line was left with
+type CreditLineChange struct {
+	ID        string         `json:"id"`
+	Action    string         `json:"action"`
+	Actor     string         `json:"actor"`
+	Reason    string         `json:"reason"`
+	Limit     payments.Money `json:"limit"`
+	Status    string         `json:"status"`
+	CreatedAt string         `json:"created_at"`
+}
+
+// OutboxRelay is what a relay pass did, the events of a plan which come after one
+// that failed to publish are held back to keep them in order
+type OutboxRelay struct {
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+	HeldBack  int `json:"held_back"`
+}
+
+type RegisterWebhookEndpointParams struct {
+	URL string `json:"url"`
+}
+
+// WebhookEndpoint the deliveries are signed with Secret, it is only returned when the endpoint is registered
+type WebhookEndpoint struct {
+	ID         string `json:"id"`
+	MerchantID string `json:"merchant_id"`
+	URL        string `json:"url"`
+	Secret     string `json:"secret,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// WebhookDelivery LastError is the error of the latest failed attempt
+type WebhookDelivery struct {
+	ID            string `json:"id"`
+	EndpointID    string `json:"endpoint_id"`
+	EventID       string `json:"event_id"`
+	EventType     string `json:"event_type"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	NextAttemptAt string `json:"next_attempt_at"`
+	LastError     string `json:"last_error,omitempty"`
+	DeliveredAt   string `json:"delivered_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// WebhookDeliveryRun is what a delivery pass did, a failed delivery is either retried later or dead
+type WebhookDeliveryRun struct {
+	Delivered int `json:"delivered"`
+	Retried   int `json:"retried"`
+	Dead      int `json:"dead"`
+}
+
+// ReminderRun is what a reminder pass did, a reminder which failed is sent again by the next pass
+// as long as it is still within the window
+type ReminderRun struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
+
+type CollectionsCaseChangeParams struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// CollectionsCase Stage is the latest stage the case reached and NextActionAt when an open case climbs
+// to the next one, History is only returned for a single case
+type CollectionsCase struct {
+	ID            string                 `json:"id"`
+	PaymentPlanID string                 `json:"payment_plan_id"`
+	UserID        string                 `json:"user_id"`
+	Status        string                 `json:"status"`
+	Stage         string                 `json:"stage,omitempty"`
+	NextActionAt  string                 `json:"next_action_at,omitempty"`
+	OpenedAt      string                 `json:"opened_at"`
+	ClosedAt      string                 `json:"closed_at,omitempty"`
+	History       []CollectionsCaseEvent `json:"history,omitempty"`
+}
+
+type CollectionsCaseEvent struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Stage     string `json:"stage,omitempty"`
+	Actor     string `json:"actor"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// CollectionsRun is what a collections pass did, a case which failed is counted
+// as failed and does not stop the others
+type CollectionsRun struct {
+	Opened    int `json:"opened"`
+	Escalated int `json:"escalated"`
+	Closed    int `json:"closed"`
+	Failed    int `json:"failed"`
+}
+
+type CreateMerchantParams struct {
+	Name string `json:"name"`
+}
+
+type Merchant struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListMerchantPaymentPlansParams the latest plans come first unless OldestFirst is set
+type ListMerchantPaymentPlansParams struct {
+	Offset      int64
+	Limit       int64
+	OldestFirst bool
+}
+
+// MerchantTotals sums the complete and refunded plans created from From included to To excluded,
+// with one entry per currency the merchant sold in
+type MerchantTotals struct {
+	MerchantID string                   `json:"merchant_id"`
+	From       string                   `json:"from"`
+	To         string                   `json:"to"`
+	Totals     []MerchantCurrencyTotals `json:"totals"`
+}
+
+// MerchantCurrencyTotals Originated is the amount of the plans, Outstanding what is left to pay on them
+// late fees included and Refunded what refunds took off what is owed to the merchant for them, whether it was
+// given back or no longer owed, late fees excluded
+type MerchantCurrencyTotals struct {
+	Originated  payments.Money `json:"originated"`
+	Outstanding payments.Money `json:"outstanding"`
+	Refunded    payments.Money `json:"refunded"`
+}
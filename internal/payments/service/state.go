@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/statemachine"
+)
+
+// every status change goes through the state machines, the database triggers refuse the same
+// transitions when they are not
+var (
+	planStates        = statemachine.NewPlanMachine()
+	installmentStates = statemachine.NewInstallmentMachine()
+	creditLineStates  = statemachine.NewCreditLineMachine()
+	collectionsStates = statemachine.NewCollectionsCaseMachine()
+)
+
+func updatePaymentPlanStatus(
+	ctx context.Context,
+	repository repo.Repository,
+	plan *payments.Plan,
+	status string,
+) (*payments.Plan, error) {
+	if err := checkPaymentPlanStatusTransition(plan, status); err != nil {
+		return nil, err
+	}
+
+	updatedPlan, err := repository.UpdatePaymentPlanStatus(ctx, &payments.UpdatePlanStatusParams{
+		ID:     plan.ID,
+		Status: status,
+	})
+	if err != nil {
+		return nil, UpdatePaymentPlanStatusError{planID: plan.ID}
+	}
+
+	switch status {
+	case paymentPlanStatusComplete:
+		if err := recordPlanEvent(ctx, repository, outbox.EventPlanCompleted, updatedPlan); err != nil {
+			return nil, err
+		}
+	case paymentPlanStatusRefunded:
+		if err := recordPlanEvent(ctx, repository, outbox.EventPlanRefunded, updatedPlan); err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedPlan, nil
+}
+
+// checkPaymentPlanStatusTransition refuses a status change before the work leading to it is done,
+// updatePaymentPlanStatus checks it again when the status changes
+func checkPaymentPlanStatusTransition(plan *payments.Plan, status string) error {
+	if err := planStates.Transition(plan, plan.Status, status); err != nil {
+		return InvalidStateTransitionError{entity: "payment plan", id: plan.ID, from: plan.Status, to: status}
+	}
+
+	return nil
+}
+
+func updatePaymentInstallmentStatus(
+	ctx context.Context,
+	repository repo.Repository,
+	inst *payments.Installment,
+	status string,
+) (*payments.Installment, error) {
+	if err := installmentStates.Transition(inst, inst.Status, status); err != nil {
+		return nil, InvalidStateTransitionError{entity: "payment installment", id: inst.ID, from: inst.Status, to: status}
+	}
+
+	updatedInst, err := repository.UpdatePaymentInstallmentStatus(ctx, &payments.UpdateInstallmentStatusParams{
+		ID:     inst.ID,
+		Status: status,
+	})
+	if err != nil {
+		return nil, UpdatePaymentInstallmentStatusError{installmentID: inst.ID}
+	}
+
+	if status == PaymentInstallmentStatusPaid {
+		if err := recordInstallmentEvent(ctx, repository, outbox.EventInstallmentPaid, updatedInst); err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedInst, nil
+}
+
+func updateCreditLineStatus(
+	ctx context.Context,
+	repository repo.Repository,
+	creditLine *payments.CreditLine,
+	status string,
+) (*payments.CreditLine, error) {
+	if err := creditLineStates.Transition(creditLine, creditLine.Status, status); err != nil {
+		return nil, InvalidStateTransitionError{entity: "credit line", id: creditLine.ID, from: creditLine.Status, to: status}
+	}
+
+	updatedCreditLine, err := repository.UpdateCreditLineStatus(ctx, &payments.UpdateCreditLineStatusParams{
+		ID:     creditLine.ID,
+		Status: status,
+	})
+	if err != nil {
+		return nil, UpdateCreditLineStatusError{userID: creditLine.UserID}
+	}
+
+	return updatedCreditLine, nil
+}
+
+// checkInstallmentsStatusTransition the installments updated in bulk are not read first so no guard runs on them,
+// the database trigger still checks each one
+func checkInstallmentsStatusTransition(from, to string) error {
+	if !installmentStates.Can(from, to) {
+		return InvalidStateTransitionError{entity: "payment installments", from: from, to: to}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+)
+
+// maxOrderTextLength the order reference, the sku and the name of the line items are stored as varchar(255)
+const maxOrderTextLength = 255
+
+// validateCreatePaymentPlanParams checks a payment plan creation request before anything is persisted,
+// every rule broken is returned as ValidationErrors
+func validateCreatePaymentPlanParams(paymentPlan *CreatePaymentPlanParams, now time.Time) error {
+	var violations ValidationErrors
+
+	// the installments are only summed up when the total and all of them are valid amounts
+	summable := true
+
+	if err := checkPositiveAmount(paymentPlan.TotalAmount, "total_amount"); err != nil {
+		violations = append(violations, err)
+		summable = false
+	}
+
+	if len(paymentPlan.Installments) == 0 {
+		return append(violations, MissingInstallmentsError{})
+	}
+
+	for idx, inst := range paymentPlan.Installments {
+		field := fmt.Sprintf("installments[%d].amount", idx)
+
+		if err := checkSameCurrency(inst.Amount, field, paymentPlan.TotalAmount.Currency()); err != nil {
+			violations = append(violations, err)
+			summable = false
+		} else if err := checkPositiveAmount(inst.Amount, field); err != nil {
+			violations = append(violations, err)
+			summable = false
+		}
+
+		if inst.DueAt.Before(now) {
+			violations = append(violations, PastDueDateError{
+				field: fmt.Sprintf("installments[%d].due_at", idx),
+				dueAt: inst.DueAt,
+			})
+		}
+	}
+
+	if summable {
+		if err := checkInstallmentsSum(paymentPlan); err != nil {
+			violations = append(violations, err)
+		}
+	}
+
+	return violations.orNil()
+}
+
+// checkInstallmentsSum the installments are expected to add up to the total amount
+func checkInstallmentsSum(paymentPlan *CreatePaymentPlanParams) error {
+	installmentsSum, err := payments.ZeroMoney(paymentPlan.TotalAmount.Currency())
+	if err != nil {
+		return err
+	}
+
+	for _, inst := range paymentPlan.Installments {
+		installmentsSum, err = installmentsSum.Add(inst.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmp, err := installmentsSum.Cmp(paymentPlan.TotalAmount); err != nil || cmp != 0 {
+		return InstallmentSumMismatchError{
+			totalAmount:     paymentPlan.TotalAmount.String(),
+			installmentsSum: installmentsSum.String(),
+		}
+	}
+
+	return nil
+}
+
+// validatePaymentPlanOrder checks the order a payment plan creation request pays for, the order is optional
+// and its line items are not required to add up to the plan amount, every rule broken is returned as ValidationErrors
+func validatePaymentPlanOrder(paymentPlan *CreatePaymentPlanParams) error {
+	var violations ValidationErrors
+
+	if paymentPlan.OrderReference != "" {
+		if err := checkOrderText(paymentPlan.OrderReference, "order_reference"); err != nil {
+			violations = append(violations, err)
+		}
+	}
+
+	for idx, lineItem := range paymentPlan.LineItems {
+		violations = append(violations, checkLineItem(
+			lineItem,
+			fmt.Sprintf("line_items[%d]", idx),
+			paymentPlan.TotalAmount.Currency(),
+		)...)
+	}
+
+	return violations.orNil()
+}
+
+// checkLineItem the unit price is expected in currency, the currency of the plan
+func checkLineItem(lineItem PaymentPlanLineItemParams, field, currency string) ValidationErrors {
+	var violations ValidationErrors
+
+	if err := checkOrderText(lineItem.Name, field+".name"); err != nil {
+		violations = append(violations, err)
+	}
+
+	if utf8.RuneCountInString(lineItem.SKU) > maxOrderTextLength {
+		violations = append(violations, InvalidOrderError{
+			field:  field + ".sku",
+			reason: fmt.Sprintf("at most %d characters are expected", maxOrderTextLength),
+		})
+	}
+
+	if lineItem.Quantity <= 0 {
+		violations = append(violations, InvalidOrderError{
+			field:  field + ".quantity",
+			reason: "a positive quantity is expected",
+		})
+	}
+
+	if err := checkSameCurrency(lineItem.UnitPrice, field+".unit_price", currency); err != nil {
+		return append(violations, err)
+	}
+
+	switch {
+	case lineItem.UnitPrice.Sign() < 0:
+		violations = append(violations, InvalidOrderError{
+			field:  field + ".unit_price.value",
+			reason: "a unit price of zero or more is expected",
+		})
+	case !lineItem.UnitPrice.IsRounded():
+		violations = append(violations, AmountPrecisionError{
+			field:      field + ".unit_price.value",
+			value:      lineItem.UnitPrice.String(),
+			currency:   lineItem.UnitPrice.Currency(),
+			minorUnits: lineItem.UnitPrice.MinorUnits(),
+		})
+	}
+
+	return violations
+}
+
+// checkOrderText a blank text is missing, the limit is the size of the column it is stored in
+func checkOrderText(value, field string) error {
+	if strings.TrimSpace(value) == "" {
+		return InvalidOrderError{field: field, reason: "a non blank value is expected"}
+	}
+
+	if utf8.RuneCountInString(value) > maxOrderTextLength {
+		return InvalidOrderError{field: field, reason: fmt.Sprintf("at most %d characters are expected", maxOrderTextLength)}
+	}
+
+	return nil
+}
+
+func parsePositiveAmount(amount *decimal.Big, field, value string) error {
+	if _, ok := amount.SetString(value); !ok || !amount.IsFinite() || amount.Sign() <= 0 {
+		return InvalidAmountError{field: field, value: value}
+	}
+
+	return nil
+}
+
+// checkPositiveAmount checks a positive amount was given which is not more precise than the minor unit
+// of its currency, a missing amount has no currency
+func checkPositiveAmount(amount payments.Money, field string) error {
+	if amount.Currency() == "" {
+		return UnsupportedCurrencyError{field: field + ".currency", value: amount.Currency()}
+	}
+
+	if amount.Sign() <= 0 {
+		return InvalidAmountError{field: field + ".value", value: amount.String()}
+	}
+
+	if !amount.IsRounded() {
+		return AmountPrecisionError{
+			field:      field + ".value",
+			value:      amount.String(),
+			currency:   amount.Currency(),
+			minorUnits: amount.MinorUnits(),
+		}
+	}
+
+	return nil
+}
+
+func checkSameCurrency(amount payments.Money, field, expected string) error {
+	if amount.Currency() != expected {
+		return CurrencyMismatchError{field: field + ".currency", expected: expected, actual: amount.Currency()}
+	}
+
+	return nil
+}
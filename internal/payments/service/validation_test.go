@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+
+	"github.com/ericlagergren/decimal"
+)
+
+func Test_validateCreatePaymentPlanParams(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now      = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		tomorrow = now.Add(24 * time.Hour)
+		past     = now.Add(-1 * time.Second)
+	)
+
+	tests := []struct {
+		name    string
+		params  *CreatePaymentPlanParams
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(1005, 1), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(5025, 2), "usdc"), DueAt: tomorrow},
+					{Amount: payments.MustNewMoney(decimal.New(5025, 2), "usdc"), DueAt: tomorrow.Add(24 * time.Hour)},
+				},
+			},
+		},
+		{
+			name: "sum beyond the default decimal precision",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(2000000000000000002, 6), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(1000000000000000001, 6), "usdc"), DueAt: tomorrow},
+					{Amount: payments.MustNewMoney(decimal.New(1000000000000000001, 6), "usdc"), DueAt: tomorrow},
+				},
+			},
+		},
+		{
+			name: "missing total amount",
+			params: &CreatePaymentPlanParams{
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"), DueAt: tomorrow},
+				},
+			},
+			wantErr: UnsupportedCurrencyError{field: "total_amount.currency", value: ""},
+		},
+		{
+			name: "total amount more precise than the currency",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100001, 3), "usd"),
+			},
+			wantErr: AmountPrecisionError{field: "total_amount.value", value: "100.001", currency: "usd", minorUnits: 2},
+		},
+		{
+			name: "trailing zeros do not count as decimal places",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100500, 3), "usd"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(10050, 2), "usd"), DueAt: tomorrow},
+				},
+			},
+		},
+		{
+			name: "installment amount more precise than the currency",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usd"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(99995, 3), "usd"), DueAt: tomorrow},
+					{Amount: payments.MustNewMoney(decimal.New(5, 3), "usd"), DueAt: tomorrow},
+				},
+			},
+			wantErr: AmountPrecisionError{
+				field: "installments[0].amount.value", value: "99.995", currency: "usd", minorUnits: 2,
+			},
+		},
+		{
+			name: "zero total amount",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(0, 0), "usdc"),
+			},
+			wantErr: InvalidAmountError{field: "total_amount.value", value: "0"},
+		},
+		{
+			name: "no installments",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			},
+			wantErr: MissingInstallmentsError{},
+		},
+		{
+			name: "negative installment amount",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(150, 0), "usdc"), DueAt: tomorrow},
+					{Amount: payments.MustNewMoney(decimal.New(-50, 0), "usdc"), DueAt: tomorrow},
+				},
+			},
+			wantErr: InvalidAmountError{field: "installments[1].amount.value", value: "-50"},
+		},
+		{
+			name: "mixed currencies",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"), DueAt: tomorrow},
+					{Amount: payments.MustNewMoney(decimal.New(50, 0), "usdt"), DueAt: tomorrow},
+				},
+			},
+			wantErr: CurrencyMismatchError{field: "installments[1].amount.currency", expected: "usdc", actual: "usdt"},
+		},
+		{
+			name: "missing installment amount",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{DueAt: tomorrow},
+				},
+			},
+			wantErr: CurrencyMismatchError{field: "installments[0].amount.currency", expected: "usdc", actual: ""},
+		},
+		{
+			name: "due date in the past",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"), DueAt: past},
+				},
+			},
+			wantErr: PastDueDateError{field: "installments[0].due_at", dueAt: past},
+		},
+		{
+			name: "installments sum mismatch",
+			params: &CreatePaymentPlanParams{
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Installments: []PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"), DueAt: tomorrow},
+					{Amount: payments.MustNewMoney(decimal.New(4999, 2), "usdc"), DueAt: tomorrow},
+				},
+			},
+			wantErr: InstallmentSumMismatchError{totalAmount: "100", installmentsSum: "99.99"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateCreatePaymentPlanParams(tt.params, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateCreatePaymentPlanParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateCreatePaymentPlanParamsEveryViolation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now      = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+		tomorrow = now.Add(24 * time.Hour)
+		past     = now.Add(-1 * time.Second)
+	)
+
+	params := &CreatePaymentPlanParams{
+		TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+		Installments: []PaymentPlanInstallmentParams{
+			{Amount: payments.MustNewMoney(decimal.New(-50, 0), "usdc"), DueAt: tomorrow},
+			{Amount: payments.MustNewMoney(decimal.New(50, 0), "usdt"), DueAt: past},
+		},
+		OrderReference: " ",
+		LineItems: []PaymentPlanLineItemParams{
+			{Name: "Chair", Quantity: 0, UnitPrice: payments.MustNewMoney(decimal.New(10, 0), "usdc")},
+		},
+	}
+
+	err := joinValidationErrors(validateCreatePaymentPlanParams(params, now), validatePaymentPlanOrder(params))
+
+	var violations ValidationErrors
+	if !errors.As(err, &violations) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	// the installments are not summed up once one of their amounts is invalid
+	want := ValidationErrors{
+		InvalidAmountError{field: "installments[0].amount.value", value: "-50"},
+		CurrencyMismatchError{field: "installments[1].amount.currency", expected: "usdc", actual: "usdt"},
+		PastDueDateError{field: "installments[1].due_at", dueAt: past},
+		InvalidOrderError{field: "order_reference", reason: "a non blank value is expected"},
+		InvalidOrderError{field: "line_items[0].quantity", reason: "a positive quantity is expected"},
+	}
+
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("got violations %v, want %v", violations, want)
+	}
+
+	if !errors.As(err, &InvalidOrderError{}) || !errors.Is(err, want[2]) {
+		t.Errorf("expected every violation to be found in %v", err)
+	}
+
+	if err := joinValidationErrors(nil, validatePaymentPlanOrder(&CreatePaymentPlanParams{})); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/webhook"
+
+	"github.com/gofrs/uuid"
+)
+
+var (
+	_ WebhookService        = (*WebhookServiceImp)(nil)
+	_ outbox.EventPublisher = (*WebhookServiceImp)(nil)
+)
+
+// WebhookServiceImp is also the publisher of the outbox relay, it turns the events into deliveries
+// which DeliverWebhooks sends afterwards
+type WebhookServiceImp struct {
+	repository  repo.Repository
+	sender      webhook.Sender
+	retryPolicy *webhook.RetryPolicy
+	sendTimeout time.Duration
+}
+
+// NewWebhookService sendTimeout is the longest sending a delivery may take
+func NewWebhookService(
+	sender webhook.Sender,
+	retryPolicy *webhook.RetryPolicy,
+	sendTimeout time.Duration,
+) *WebhookServiceImp {
+	return &WebhookServiceImp{sender: sender, retryPolicy: retryPolicy, sendTimeout: sendTimeout}
+}
+
+func (w *WebhookServiceImp) UseRepo(repository repo.Repository) {
+	w.repository = repository
+}
+
+func (w *WebhookServiceImp) RegisterWebhookEndpoint(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	endpoint *RegisterWebhookEndpointParams,
+) (*WebhookEndpoint, error) {
+	if err := validateWebhookURL(endpoint.URL); err != nil {
+		return nil, err
+	}
+
+	if err := checkMerchantExists(ctx, w.repository, merchantID); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, CreateWebhookEndpointError{merchantID: merchantID}
+	}
+
+	created, err := w.repository.CreateWebhookEndpoint(ctx, &webhook.CreateEndpointParams{
+		MerchantID: merchantID,
+		URL:        endpoint.URL,
+		Secret:     secret,
+	})
+	if err != nil {
+		return nil, CreateWebhookEndpointError{merchantID: merchantID}
+	}
+
+	return &WebhookEndpoint{
+		ID:         created.ID.String(),
+		MerchantID: created.MerchantID.String(),
+		URL:        created.URL,
+		Secret:     created.Secret,
+		CreatedAt:  created.CreatedAt.Format(common.TimeFormat),
+	}, nil
+}
+
+// Publish creates a delivery of the event for each endpoint of the merchant of the plan, the events
+// of a plan without a merchant and the ones merchants are not subscribed to are dropped.
+// An event published again is not delivered twice to the same endpoint.
+func (w *WebhookServiceImp) Publish(ctx context.Context, event *outbox.Event) error {
+	if !webhook.Subscribed(event.Type) {
+		return nil
+	}
+
+	err := w.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		plan, err := txRepo.GetPaymentPlanByID(ctx, event.PaymentPlanID)
+		if err != nil {
+			return GetPaymentPlanByIDError{planID: event.PaymentPlanID}
+		}
+
+		if plan.MerchantID == uuid.Nil {
+			return nil
+		}
+
+		endpoints, err := txRepo.ListWebhookEndpointsByMerchantID(ctx, plan.MerchantID)
+		if err != nil {
+			return ListWebhookEndpointsError{merchantID: plan.MerchantID}
+		}
+
+		for _, endpoint := range endpoints {
+			if err := txRepo.CreateWebhookDelivery(ctx, &webhook.CreateDeliveryParams{
+				EndpointID:    endpoint.ID,
+				EventID:       event.ID,
+				EventType:     event.Type,
+				Payload:       event.Payload,
+				NextAttemptAt: event.CreatedAt,
+			}); err != nil {
+				return CreateWebhookDeliveryError{endpointID: endpoint.ID, eventID: event.ID}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("publish event to webhooks: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverWebhooks a delivery which fails is attempted again after the delay of the retry policy,
+// it is dead once it ran out of attempts and only sent again when it is replayed.
+// The due deliveries are claimed in a short unit of work, sent outside of any and the result of each one
+// is recorded on its own, a delivery whose result was not recorded is attempted again once its claim expired.
+func (w *WebhookServiceImp) DeliverWebhooks(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) (*WebhookDeliveryRun, error) {
+	claims, err := w.claimDueDeliveries(ctx, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("deliver webhooks: %w", err)
+	}
+
+	run := &WebhookDeliveryRun{}
+
+	for _, claim := range claims {
+		update := w.attemptDelivery(ctx, claim.endpoint, claim.delivery, now)
+
+		if _, err := w.repository.UpdateWebhookDelivery(ctx, update); err != nil {
+			return nil, fmt.Errorf("deliver webhooks: %w", UpdateWebhookDeliveryError{deliveryID: claim.delivery.ID})
+		}
+
+		switch update.Status {
+		case webhook.DeliveryStatusDelivered:
+			run.Delivered++
+		case webhook.DeliveryStatusDead:
+			run.Dead++
+		default:
+			run.Retried++
+		}
+	}
+
+	return run, nil
+}
+
+// deliveryClaim is a due delivery as it was before it was claimed and the endpoint to send it to
+type deliveryClaim struct {
+	delivery *webhook.Delivery
+	endpoint *webhook.Endpoint
+}
+
+// claimDueDeliveries pushes the next attempt of the due deliveries back by the time sending all of them may take,
+// a concurrent dispatcher does not send them again until then
+func (w *WebhookServiceImp) claimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]deliveryClaim, error) {
+	var claims []deliveryClaim
+
+	err := w.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		deliveries, err := txRepo.LockDueWebhookDeliveries(ctx, &webhook.ListDueDeliveriesParams{
+			Before: now,
+			Limit:  int32(limit),
+		})
+		if err != nil {
+			return ListWebhookDeliveriesError{status: webhook.DeliveryStatusPending}
+		}
+
+		claimedUntil := now.Add(time.Duration(len(deliveries)+1) * w.sendTimeout)
+		endpoints := make(map[uuid.UUID]*webhook.Endpoint)
+		claims = make([]deliveryClaim, 0, len(deliveries))
+
+		for _, delivery := range deliveries {
+			endpoint, ok := endpoints[delivery.EndpointID]
+			if !ok {
+				if endpoint, err = txRepo.GetWebhookEndpointByID(ctx, delivery.EndpointID); err != nil {
+					return GetWebhookEndpointByIDError{endpointID: delivery.EndpointID}
+				}
+
+				endpoints[delivery.EndpointID] = endpoint
+			}
+
+			if _, err := txRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+				ID:            delivery.ID,
+				Status:        delivery.Status,
+				Attempts:      delivery.Attempts,
+				NextAttemptAt: claimedUntil,
+				LastError:     delivery.LastError,
+				DeliveredAt:   delivery.DeliveredAt,
+			}); err != nil {
+				return UpdateWebhookDeliveryError{deliveryID: delivery.ID}
+			}
+
+			claims = append(claims, deliveryClaim{delivery: delivery, endpoint: endpoint})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim due webhook deliveries: %w", err)
+	}
+
+	return claims, nil
+}
+
+// attemptDelivery sends the delivery and returns how to update it
+func (w *WebhookServiceImp) attemptDelivery(
+	ctx context.Context,
+	endpoint *webhook.Endpoint,
+	delivery *webhook.Delivery,
+	now time.Time,
+) *webhook.UpdateDeliveryParams {
+	update := &webhook.UpdateDeliveryParams{
+		ID:            delivery.ID,
+		Status:        webhook.DeliveryStatusDelivered,
+		Attempts:      delivery.Attempts + 1,
+		NextAttemptAt: delivery.NextAttemptAt,
+		DeliveredAt:   &now,
+	}
+
+	err := w.sender.Send(ctx, endpoint, delivery, now)
+	if err == nil {
+		return update
+	}
+
+	update.DeliveredAt = nil
+	update.LastError = err.Error()
+
+	if w.retryPolicy.Exhausted(update.Attempts) {
+		update.Status = webhook.DeliveryStatusDead
+	} else {
+		update.Status = webhook.DeliveryStatusPending
+		update.NextAttemptAt = now.Add(w.retryPolicy.Delay(update.Attempts))
+	}
+
+	return update
+}
+
+func (w *WebhookServiceImp) ListWebhookDeliveries(ctx context.Context, status string) ([]WebhookDelivery, error) {
+	switch status {
+	case webhook.DeliveryStatusPending, webhook.DeliveryStatusDelivered, webhook.DeliveryStatusDead:
+	default:
+		return nil, InvalidWebhookDeliveryStatusError{status: status}
+	}
+
+	deliveries, err := w.repository.ListWebhookDeliveriesByStatus(ctx, status)
+	if err != nil {
+		return nil, ListWebhookDeliveriesError{status: status}
+	}
+
+	resp := make([]WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, newWebhookDelivery(delivery))
+	}
+
+	return resp, nil
+}
+
+// ReplayWebhookDelivery the delivery keeps the error it died with until it is attempted again
+func (w *WebhookServiceImp) ReplayWebhookDelivery(
+	ctx context.Context,
+	deliveryID uuid.UUID,
+	now time.Time,
+) (*WebhookDelivery, error) {
+	var replayed *webhook.Delivery
+
+	err := w.repository.WithTx(ctx, func(txRepo repo.Repository) error {
+		delivery, err := txRepo.LockWebhookDelivery(ctx, deliveryID)
+		if err != nil {
+			if errors.Is(err, repo.ErrRecordNotFound) {
+				return WebhookDeliveryNotFoundError{deliveryID: deliveryID}
+			}
+
+			return LockWebhookDeliveryError{deliveryID: deliveryID}
+		}
+
+		if delivery.Status != webhook.DeliveryStatusDead {
+			return WebhookDeliveryNotDeadError{deliveryID: deliveryID, status: delivery.Status}
+		}
+
+		if replayed, err = txRepo.UpdateWebhookDelivery(ctx, &webhook.UpdateDeliveryParams{
+			ID:            deliveryID,
+			Status:        webhook.DeliveryStatusPending,
+			Attempts:      0,
+			NextAttemptAt: now,
+			LastError:     delivery.LastError,
+		}); err != nil {
+			return UpdateWebhookDeliveryError{deliveryID: deliveryID}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("replay webhook delivery: %w", err)
+	}
+
+	resp := newWebhookDelivery(replayed)
+
+	return &resp, nil
+}
+
+// validateWebhookURL only absolute http and https urls are sent to
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return InvalidWebhookEndpointError{reason: "url is not an absolute url"}
+	}
+
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return InvalidWebhookEndpointError{reason: "url scheme must be http or https"}
+	}
+
+	if parsed.Host == "" {
+		return InvalidWebhookEndpointError{reason: "url has no host"}
+	}
+
+	return nil
+}
+
+func newWebhookDelivery(delivery *webhook.Delivery) WebhookDelivery {
+	resp := WebhookDelivery{
+		ID:            delivery.ID.String(),
+		EndpointID:    delivery.EndpointID.String(),
+		EventID:       delivery.EventID.String(),
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt.Format(common.TimeFormat),
+		LastError:     delivery.LastError,
+		CreatedAt:     delivery.CreatedAt.Format(common.TimeFormat),
+	}
+
+	if delivery.DeliveredAt != nil {
+		resp.DeliveredAt = delivery.DeliveredAt.Format(common.TimeFormat)
+	}
+
+	return resp
+}
@@ -0,0 +1,896 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/repomock"
+	"golangreferenceapi/internal/payments/outbox"
+	"golangreferenceapi/internal/payments/repo"
+	"golangreferenceapi/internal/payments/repo/memory"
+	"golangreferenceapi/internal/payments/webhook"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+)
+
+// senderFunc sends the deliveries with a function
+type senderFunc func(ctx context.Context, endpoint *webhook.Endpoint, delivery *webhook.Delivery, now time.Time) error
+
+func (sf senderFunc) Send(
+	ctx context.Context,
+	endpoint *webhook.Endpoint,
+	delivery *webhook.Delivery,
+	now time.Time,
+) error {
+	return sf(ctx, endpoint, delivery, now)
+}
+
+func mustRetryPolicy(baseDelay, maxDelay time.Duration, maxAttempts int) *webhook.RetryPolicy {
+	policy, err := webhook.NewRetryPolicy(baseDelay, maxDelay, maxAttempts)
+	if err != nil {
+		panic(err)
+	}
+
+	return policy
+}
+
+func TestWebhookServiceImp_RegisterWebhookEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		merchantID = uuid.Must(uuid.NewV4())
+		endpointID = uuid.Must(uuid.NewV4())
+		createdAt  = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		url        = "https://merchant.example.com/webhooks"
+	)
+
+	createEndpoint := func(_ context.Context, arg *webhook.CreateEndpointParams) (*webhook.Endpoint, error) {
+		return &webhook.Endpoint{
+			ID:         endpointID,
+			MerchantID: arg.MerchantID,
+			URL:        arg.URL,
+			Secret:     arg.Secret,
+			CreatedAt:  createdAt,
+		}, nil
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		prepare func(rm *repomock.MockRepository)
+		want    *WebhookEndpoint
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			url:  url,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().CreateWebhookEndpoint(ctx, gomock.Any()).DoAndReturn(createEndpoint),
+				)
+			},
+			want: &WebhookEndpoint{
+				ID:         endpointID.String(),
+				MerchantID: merchantID.String(),
+				URL:        url,
+				CreatedAt:  "2022-07-10T00:00:00Z",
+			},
+		},
+		{
+			name:    "no host",
+			url:     "https:///webhooks",
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: InvalidWebhookEndpointError{reason: "url has no host"},
+		},
+		{
+			name:    "not an url",
+			url:     "merchant.example.com",
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: InvalidWebhookEndpointError{reason: "url is not an absolute url"},
+		},
+		{
+			name:    "unsupported scheme",
+			url:     "ftp://merchant.example.com/webhooks",
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: InvalidWebhookEndpointError{reason: "url scheme must be http or https"},
+		},
+		{
+			name: "merchant not found",
+			url:  url,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(nil, repo.ErrRecordNotFound)
+			},
+			wantErr: MerchantNotFoundError{merchantID: merchantID},
+		},
+		{
+			name: "CreateWebhookEndpoint error",
+			url:  url,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().GetMerchantByID(ctx, merchantID).Return(&payments.Merchant{ID: merchantID}, nil),
+					rm.EXPECT().CreateWebhookEndpoint(ctx, gomock.Any()).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateWebhookEndpointError{merchantID: merchantID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			w := &WebhookServiceImp{repository: rm}
+
+			got, err := w.RegisterWebhookEndpoint(ctx, merchantID, &RegisterWebhookEndpointParams{URL: tt.url})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WebhookServiceImp.RegisterWebhookEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got == nil {
+				return
+			}
+
+			if !strings.HasPrefix(got.Secret, "whsec_") {
+				t.Errorf("WebhookServiceImp.RegisterWebhookEndpoint() secret = %q, want a generated secret", got.Secret)
+			}
+
+			got.Secret = ""
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WebhookServiceImp.RegisterWebhookEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceImp_Publish(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx         = context.Background()
+		createdAt   = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		merchantID  = uuid.Must(uuid.NewV4())
+		plan        = &payments.Plan{ID: uuid.Must(uuid.NewV4()), MerchantID: merchantID}
+		planWithout = &payments.Plan{ID: uuid.Must(uuid.NewV4())}
+		endpoint    = &webhook.Endpoint{ID: uuid.Must(uuid.NewV4()), MerchantID: merchantID}
+		endpoint2   = &webhook.Endpoint{ID: uuid.Must(uuid.NewV4()), MerchantID: merchantID}
+		completed   = &outbox.Event{
+			ID:            uuid.Must(uuid.NewV4()),
+			PaymentPlanID: plan.ID,
+			Type:          outbox.EventPlanCompleted,
+			Payload:       []byte(`{"status":"completed"}`),
+			CreatedAt:     createdAt,
+		}
+	)
+
+	delivery := func(endpoint *webhook.Endpoint) *webhook.CreateDeliveryParams {
+		return &webhook.CreateDeliveryParams{
+			EndpointID:    endpoint.ID,
+			EventID:       completed.ID,
+			EventType:     completed.Type,
+			Payload:       completed.Payload,
+			NextAttemptAt: createdAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		event   *outbox.Event
+		prepare func(rm *repomock.MockRepository)
+		wantErr error
+	}{
+		{
+			name:  "happy path",
+			event: completed,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().ListWebhookEndpointsByMerchantID(ctx, merchantID).
+						Return([]*webhook.Endpoint{endpoint, endpoint2}, nil),
+					rm.EXPECT().CreateWebhookDelivery(ctx, delivery(endpoint)).Return(nil),
+					rm.EXPECT().CreateWebhookDelivery(ctx, delivery(endpoint2)).Return(nil),
+				)
+			},
+		},
+		{
+			name:    "not subscribed event",
+			event:   &outbox.Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: plan.ID, Type: outbox.EventPlanCreated},
+			prepare: func(rm *repomock.MockRepository) {},
+		},
+		{
+			name:  "plan without merchant",
+			event: &outbox.Event{ID: uuid.Must(uuid.NewV4()), PaymentPlanID: planWithout.ID, Type: outbox.EventPlanRefunded},
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, planWithout.ID).Return(planWithout, nil),
+				)
+			},
+		},
+		{
+			name:  "GetPaymentPlanByID error",
+			event: completed,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: GetPaymentPlanByIDError{planID: plan.ID},
+		},
+		{
+			name:  "ListWebhookEndpointsByMerchantID error",
+			event: completed,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().ListWebhookEndpointsByMerchantID(ctx, merchantID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListWebhookEndpointsError{merchantID: merchantID},
+		},
+		{
+			name:  "CreateWebhookDelivery error",
+			event: completed,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().GetPaymentPlanByID(ctx, plan.ID).Return(plan, nil),
+					rm.EXPECT().ListWebhookEndpointsByMerchantID(ctx, merchantID).
+						Return([]*webhook.Endpoint{endpoint}, nil),
+					rm.EXPECT().CreateWebhookDelivery(ctx, delivery(endpoint)).Return(fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: CreateWebhookDeliveryError{endpointID: endpoint.ID, eventID: completed.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			w := &WebhookServiceImp{repository: rm}
+
+			if err := w.Publish(ctx, tt.event); !errors.Is(err, tt.wantErr) {
+				t.Errorf("WebhookServiceImp.Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceImp_DeliverWebhooks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		now      = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		limit    = 10
+		endpoint = &webhook.Endpoint{ID: uuid.Must(uuid.NewV4()), URL: "https://merchant.example.com/webhooks"}
+		first    = &webhook.Delivery{
+			ID:            uuid.Must(uuid.NewV4()),
+			EndpointID:    endpoint.ID,
+			Status:        webhook.DeliveryStatusPending,
+			NextAttemptAt: now,
+		}
+		retried = &webhook.Delivery{
+			ID:            uuid.Must(uuid.NewV4()),
+			EndpointID:    endpoint.ID,
+			Status:        webhook.DeliveryStatusPending,
+			Attempts:      1,
+			NextAttemptAt: now.Add(-time.Minute),
+		}
+		last = &webhook.Delivery{
+			ID:            uuid.Must(uuid.NewV4()),
+			EndpointID:    endpoint.ID,
+			Status:        webhook.DeliveryStatusPending,
+			Attempts:      2,
+			NextAttemptAt: now.Add(-2 * time.Minute),
+		}
+		due        = &webhook.ListDueDeliveriesParams{Before: now, Limit: int32(limit)}
+		deliveries = []*webhook.Delivery{first, retried, last}
+		sendErr    = errors.New("dummyErr")
+	)
+
+	// the three deliveries are claimed for the time sending them may take and one more send
+	claimed := func(delivery *webhook.Delivery) *webhook.UpdateDeliveryParams {
+		return &webhook.UpdateDeliveryParams{
+			ID:            delivery.ID,
+			Status:        delivery.Status,
+			Attempts:      delivery.Attempts,
+			NextAttemptAt: now.Add(4 * time.Second),
+		}
+	}
+
+	delivered := func(delivery *webhook.Delivery) *webhook.UpdateDeliveryParams {
+		return &webhook.UpdateDeliveryParams{
+			ID:            delivery.ID,
+			Status:        webhook.DeliveryStatusDelivered,
+			Attempts:      delivery.Attempts + 1,
+			NextAttemptAt: delivery.NextAttemptAt,
+			DeliveredAt:   &now,
+		}
+	}
+
+	failed := func(delivery *webhook.Delivery, status string, nextAttemptAt time.Time) *webhook.UpdateDeliveryParams {
+		return &webhook.UpdateDeliveryParams{
+			ID:            delivery.ID,
+			Status:        status,
+			Attempts:      delivery.Attempts + 1,
+			NextAttemptAt: nextAttemptAt,
+			LastError:     sendErr.Error(),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(rm *repomock.MockRepository)
+		sendErr error
+		want    *WebhookDeliveryRun
+		wantErr error
+	}{
+		{
+			name: "happy path",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockDueWebhookDeliveries(ctx, due).Return(deliveries, nil),
+					rm.EXPECT().GetWebhookEndpointByID(ctx, endpoint.ID).Return(endpoint, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(first)).Return(first, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(retried)).Return(retried, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(last)).Return(last, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, delivered(first)).Return(first, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, delivered(retried)).Return(retried, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, delivered(last)).Return(last, nil),
+				)
+			},
+			want: &WebhookDeliveryRun{Delivered: 3},
+		},
+		{
+			name: "the failed deliveries are retried with a backoff until they are dead",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockDueWebhookDeliveries(ctx, due).Return(deliveries, nil),
+					rm.EXPECT().GetWebhookEndpointByID(ctx, endpoint.ID).Return(endpoint, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(first)).Return(first, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(retried)).Return(retried, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(last)).Return(last, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, failed(first, webhook.DeliveryStatusPending, now.Add(time.Minute))).
+						Return(first, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, failed(retried, webhook.DeliveryStatusPending, now.Add(2*time.Minute))).
+						Return(retried, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, failed(last, webhook.DeliveryStatusDead, last.NextAttemptAt)).
+						Return(last, nil),
+				)
+			},
+			sendErr: sendErr,
+			want:    &WebhookDeliveryRun{Retried: 2, Dead: 1},
+		},
+		{
+			name: "LockDueWebhookDeliveries error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockDueWebhookDeliveries(ctx, due).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: ListWebhookDeliveriesError{status: webhook.DeliveryStatusPending},
+		},
+		{
+			name: "GetWebhookEndpointByID error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockDueWebhookDeliveries(ctx, due).Return(deliveries, nil),
+					rm.EXPECT().GetWebhookEndpointByID(ctx, endpoint.ID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: GetWebhookEndpointByIDError{endpointID: endpoint.ID},
+		},
+		{
+			name: "claiming UpdateWebhookDelivery error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockDueWebhookDeliveries(ctx, due).Return(deliveries, nil),
+					rm.EXPECT().GetWebhookEndpointByID(ctx, endpoint.ID).Return(endpoint, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(first)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateWebhookDeliveryError{deliveryID: first.ID},
+		},
+		{
+			name: "recording UpdateWebhookDelivery error",
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockDueWebhookDeliveries(ctx, due).Return(deliveries, nil),
+					rm.EXPECT().GetWebhookEndpointByID(ctx, endpoint.ID).Return(endpoint, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(first)).Return(first, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(retried)).Return(retried, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, claimed(last)).Return(last, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, delivered(first)).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateWebhookDeliveryError{deliveryID: first.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			sender := senderFunc(func(context.Context, *webhook.Endpoint, *webhook.Delivery, time.Time) error {
+				return tt.sendErr
+			})
+
+			w := NewWebhookService(sender, mustRetryPolicy(time.Minute, time.Hour, 3), time.Second)
+			w.UseRepo(rm)
+
+			got, err := w.DeliverWebhooks(ctx, now, limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WebhookServiceImp.DeliverWebhooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WebhookServiceImp.DeliverWebhooks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceImp_ListWebhookDeliveries(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx         = context.Background()
+		createdAt   = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		deliveredAt = createdAt.Add(time.Minute)
+		dead        = &webhook.Delivery{
+			ID:            uuid.Must(uuid.NewV4()),
+			EndpointID:    uuid.Must(uuid.NewV4()),
+			EventID:       uuid.Must(uuid.NewV4()),
+			EventType:     outbox.EventPlanCompleted,
+			Status:        webhook.DeliveryStatusDead,
+			Attempts:      3,
+			NextAttemptAt: createdAt,
+			LastError:     "webhook endpoint answered with status 500",
+			CreatedAt:     createdAt,
+		}
+		delivered = &webhook.Delivery{
+			ID:            uuid.Must(uuid.NewV4()),
+			EndpointID:    uuid.Must(uuid.NewV4()),
+			EventID:       uuid.Must(uuid.NewV4()),
+			EventType:     outbox.EventPlanRefunded,
+			Status:        webhook.DeliveryStatusDelivered,
+			Attempts:      1,
+			NextAttemptAt: createdAt,
+			DeliveredAt:   &deliveredAt,
+			CreatedAt:     createdAt,
+		}
+	)
+
+	tests := []struct {
+		name    string
+		status  string
+		prepare func(rm *repomock.MockRepository)
+		want    []WebhookDelivery
+		wantErr error
+	}{
+		{
+			name:   "happy path",
+			status: webhook.DeliveryStatusDead,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListWebhookDeliveriesByStatus(ctx, webhook.DeliveryStatusDead).
+					Return([]*webhook.Delivery{dead}, nil)
+			},
+			want: []WebhookDelivery{{
+				ID:            dead.ID.String(),
+				EndpointID:    dead.EndpointID.String(),
+				EventID:       dead.EventID.String(),
+				EventType:     outbox.EventPlanCompleted,
+				Status:        webhook.DeliveryStatusDead,
+				Attempts:      3,
+				NextAttemptAt: "2022-07-10T00:00:00Z",
+				LastError:     "webhook endpoint answered with status 500",
+				CreatedAt:     "2022-07-10T00:00:00Z",
+			}},
+		},
+		{
+			name:   "delivered",
+			status: webhook.DeliveryStatusDelivered,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListWebhookDeliveriesByStatus(ctx, webhook.DeliveryStatusDelivered).
+					Return([]*webhook.Delivery{delivered}, nil)
+			},
+			want: []WebhookDelivery{{
+				ID:            delivered.ID.String(),
+				EndpointID:    delivered.EndpointID.String(),
+				EventID:       delivered.EventID.String(),
+				EventType:     outbox.EventPlanRefunded,
+				Status:        webhook.DeliveryStatusDelivered,
+				Attempts:      1,
+				NextAttemptAt: "2022-07-10T00:00:00Z",
+				DeliveredAt:   "2022-07-10T00:01:00Z",
+				CreatedAt:     "2022-07-10T00:00:00Z",
+			}},
+		},
+		{
+			name:    "invalid status",
+			status:  "lost",
+			prepare: func(rm *repomock.MockRepository) {},
+			wantErr: InvalidWebhookDeliveryStatusError{status: "lost"},
+		},
+		{
+			name:   "ListWebhookDeliveriesByStatus error",
+			status: webhook.DeliveryStatusDead,
+			prepare: func(rm *repomock.MockRepository) {
+				rm.EXPECT().ListWebhookDeliveriesByStatus(ctx, webhook.DeliveryStatusDead).
+					Return(nil, fmt.Errorf("dummyErr"))
+			},
+			wantErr: ListWebhookDeliveriesError{status: webhook.DeliveryStatusDead},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			w := &WebhookServiceImp{repository: rm}
+
+			got, err := w.ListWebhookDeliveries(ctx, tt.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WebhookServiceImp.ListWebhookDeliveries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WebhookServiceImp.ListWebhookDeliveries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceImp_ReplayWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		now       = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		createdAt = now.Add(-time.Hour)
+		dead      = &webhook.Delivery{
+			ID:            uuid.Must(uuid.NewV4()),
+			Status:        webhook.DeliveryStatusDead,
+			Attempts:      3,
+			NextAttemptAt: createdAt,
+			LastError:     "dummyErr",
+			CreatedAt:     createdAt,
+		}
+		pending = &webhook.Delivery{ID: uuid.Must(uuid.NewV4()), Status: webhook.DeliveryStatusPending}
+		replay  = &webhook.UpdateDeliveryParams{
+			ID:            dead.ID,
+			Status:        webhook.DeliveryStatusPending,
+			NextAttemptAt: now,
+			LastError:     "dummyErr",
+		}
+		replayed = &webhook.Delivery{
+			ID:            dead.ID,
+			Status:        webhook.DeliveryStatusPending,
+			NextAttemptAt: now,
+			LastError:     "dummyErr",
+			CreatedAt:     createdAt,
+		}
+	)
+
+	tests := []struct {
+		name       string
+		deliveryID uuid.UUID
+		prepare    func(rm *repomock.MockRepository)
+		want       *WebhookDelivery
+		wantErr    error
+	}{
+		{
+			name:       "happy path",
+			deliveryID: dead.ID,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockWebhookDelivery(ctx, dead.ID).Return(dead, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, replay).Return(replayed, nil),
+				)
+			},
+			want: &WebhookDelivery{
+				ID:            dead.ID.String(),
+				EndpointID:    uuid.Nil.String(),
+				EventID:       uuid.Nil.String(),
+				Status:        webhook.DeliveryStatusPending,
+				NextAttemptAt: "2022-07-10T00:00:00Z",
+				LastError:     "dummyErr",
+				CreatedAt:     "2022-07-09T23:00:00Z",
+			},
+		},
+		{
+			name:       "not found",
+			deliveryID: dead.ID,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockWebhookDelivery(ctx, dead.ID).Return(nil, repo.ErrRecordNotFound),
+				)
+			},
+			wantErr: WebhookDeliveryNotFoundError{deliveryID: dead.ID},
+		},
+		{
+			name:       "LockWebhookDelivery error",
+			deliveryID: dead.ID,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockWebhookDelivery(ctx, dead.ID).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: LockWebhookDeliveryError{deliveryID: dead.ID},
+		},
+		{
+			name:       "not dead",
+			deliveryID: pending.ID,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockWebhookDelivery(ctx, pending.ID).Return(pending, nil),
+				)
+			},
+			wantErr: WebhookDeliveryNotDeadError{deliveryID: pending.ID, status: webhook.DeliveryStatusPending},
+		},
+		{
+			name:       "UpdateWebhookDelivery error",
+			deliveryID: dead.ID,
+			prepare: func(rm *repomock.MockRepository) {
+				gomock.InOrder(
+					rm.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(runInTx(rm)),
+					rm.EXPECT().LockWebhookDelivery(ctx, dead.ID).Return(dead, nil),
+					rm.EXPECT().UpdateWebhookDelivery(ctx, replay).Return(nil, fmt.Errorf("dummyErr")),
+				)
+			},
+			wantErr: UpdateWebhookDeliveryError{deliveryID: dead.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			rm := repomock.NewMockRepository(ctrl)
+			tt.prepare(rm)
+
+			w := &WebhookServiceImp{repository: rm}
+
+			got, err := w.ReplayWebhookDelivery(ctx, tt.deliveryID, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WebhookServiceImp.ReplayWebhookDelivery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WebhookServiceImp.ReplayWebhookDelivery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// webhookReceiver answers 500 to the first failures requests and keeps the messages it accepted
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	requests int
+	messages []webhook.Message
+}
+
+func (wr *webhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	wr.requests++
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	timestamp, signature := req.Header.Get(webhook.HeaderTimestamp), req.Header.Get(webhook.HeaderSignature)
+	if err := webhook.Verify(wr.secret, timestamp, signature, body, time.Now(), 5*time.Minute); err != nil {
+		rw.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	if wr.requests <= wr.failures {
+		rw.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	var message webhook.Message
+	if err := json.Unmarshal(body, &message); err != nil || req.Header.Get(webhook.HeaderID) != message.ID.String() {
+		rw.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	wr.messages = append(wr.messages, message)
+}
+
+func TestWebhookServiceImp_EndToEnd(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		userID     = uuid.Must(uuid.NewV4())
+		merchantID = uuid.Must(uuid.NewV4())
+		amount     = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+		repository = memory.NewInMemRepository()
+		receiver   = &webhookReceiver{failures: 2}
+	)
+
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	if _, err := repository.CreateCreditLine(ctx, &payments.CreateCreditLineParams{
+		UserID: userID,
+		Limit:  payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status: CreditLineStatusActive,
+	}); err != nil {
+		t.Fatalf("CreateCreditLine() error = %v", err)
+	}
+
+	if _, err := repository.CreateMerchant(ctx, &payments.CreateMerchantParams{ID: merchantID, Name: "Acme"}); err != nil {
+		t.Fatalf("CreateMerchant() error = %v", err)
+	}
+
+	w := NewWebhookService(webhook.NewHTTPSender(time.Second), mustRetryPolicy(time.Minute, time.Hour, 2), time.Second)
+	w.UseRepo(repository)
+
+	endpoint, err := w.RegisterWebhookEndpoint(ctx, merchantID, &RegisterWebhookEndpointParams{URL: server.URL})
+	if err != nil {
+		t.Fatalf("WebhookServiceImp.RegisterWebhookEndpoint() error = %v", err)
+	}
+
+	receiver.mu.Lock()
+	receiver.secret = endpoint.Secret
+	receiver.mu.Unlock()
+
+	p := NewPaymentPlanService()
+	p.UseRepo(repository)
+	p.UseEventPublisher(w)
+
+	plan, err := p.CreatePendingPaymentPlan(ctx, &CreatePaymentPlanParams{
+		UserID:      userID,
+		MerchantID:  merchantID,
+		TotalAmount: amount,
+		Installments: []PaymentPlanInstallmentParams{
+			{Amount: amount, DueAt: time.Now().UTC().AddDate(0, 1, 0)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PaymentServiceImp.CreatePendingPaymentPlan() error = %v", err)
+	}
+
+	if _, err := p.CompletePaymentPlanCreation(ctx, uuid.FromStringOrNil(plan.ID), &CompletePaymentPlanParams{
+		UserID: userID,
+	}); err != nil {
+		t.Fatalf("PaymentServiceImp.CompletePaymentPlanCreation() error = %v", err)
+	}
+
+	now := time.Now().UTC()
+
+	if relay, err := p.RelayOutboxEvents(ctx, now, 10); err != nil || relay.Failed != 0 || relay.HeldBack != 0 {
+		t.Fatalf("PaymentServiceImp.RelayOutboxEvents() = %v, %v, want every event published", relay, err)
+	}
+
+	// only plan_completed is delivered, it fails until it runs out of attempts
+	deliver := func(at time.Time, want *WebhookDeliveryRun) {
+		t.Helper()
+
+		got, err := w.DeliverWebhooks(ctx, at, 10)
+		if err != nil {
+			t.Fatalf("WebhookServiceImp.DeliverWebhooks() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("WebhookServiceImp.DeliverWebhooks() at %s = %v, want %v", at, got, want)
+		}
+	}
+
+	deliver(now, &WebhookDeliveryRun{Retried: 1})
+	deliver(now, &WebhookDeliveryRun{})
+	deliver(now.Add(time.Minute), &WebhookDeliveryRun{Dead: 1})
+
+	dead, err := w.ListWebhookDeliveries(ctx, webhook.DeliveryStatusDead)
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("WebhookServiceImp.ListWebhookDeliveries() = %v, %v, want the dead delivery", dead, err)
+	}
+
+	if dead[0].LastError != (webhook.StatusError{StatusCode: http.StatusInternalServerError}).Error() {
+		t.Errorf("dead delivery last error = %q", dead[0].LastError)
+	}
+
+	replayed, err := w.ReplayWebhookDelivery(ctx, uuid.FromStringOrNil(dead[0].ID), now.Add(2*time.Minute))
+	if err != nil || replayed.Status != webhook.DeliveryStatusPending || replayed.Attempts != 0 {
+		t.Fatalf("WebhookServiceImp.ReplayWebhookDelivery() = %v, %v, want a pending delivery", replayed, err)
+	}
+
+	deliver(now.Add(2*time.Minute), &WebhookDeliveryRun{Delivered: 1})
+	deliver(now.Add(time.Hour), &WebhookDeliveryRun{})
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	if len(receiver.messages) != 1 {
+		t.Fatalf("received %d messages, want 1", len(receiver.messages))
+	}
+
+	message := receiver.messages[0]
+	if message.Type != outbox.EventPlanCompleted || message.ID.String() != dead[0].EventID {
+		t.Errorf("received message %v %v, want the plan_completed event %v", message.ID, message.Type, dead[0].EventID)
+	}
+
+	var payload outbox.PlanPayload
+	if err := json.Unmarshal(message.Data, &payload); err != nil || payload.PaymentPlanID.String() != plan.ID {
+		t.Errorf("received payload %s, want the one of plan %v", message.Data, plan.ID)
+	}
+}
@@ -0,0 +1,19 @@
+package statemachine
+
+import "golangreferenceapi/internal/payments/collections"
+
+// the states of a collections case, the collections_case_status database enum
+const (
+	CollectionsCaseOpen   = "open"
+	CollectionsCasePaused = "paused"
+	CollectionsCaseClosed = "closed"
+)
+
+// NewCollectionsCaseMachine a paused case does not climb the ladder until it is resumed, a closed case stays closed.
+// The database collections_cases_status_transition trigger allows the same transitions.
+func NewCollectionsCaseMachine() *Machine[*collections.Case] {
+	return New[*collections.Case](map[string][]string{
+		CollectionsCaseOpen:   {CollectionsCasePaused, CollectionsCaseClosed},
+		CollectionsCasePaused: {CollectionsCaseOpen, CollectionsCaseClosed},
+	})
+}
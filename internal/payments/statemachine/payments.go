@@ -0,0 +1,60 @@
+package statemachine
+
+import "golangreferenceapi/internal/payments"
+
+// the states of a payment plan, the payment_status database enum
+const (
+	PlanPending   = "pending"
+	PlanComplete  = "complete"
+	PlanCancelled = "cancelled"
+	PlanRefunded  = "refunded"
+)
+
+// the states of a payment installment, the payment_installment_status database enum
+const (
+	InstallmentPending = "pending"
+	InstallmentPaid    = "paid"
+	InstallmentDue     = "due"
+	InstallmentOverdue = "overdue"
+	InstallmentVoid    = "void"
+	// InstallmentSuperseded installments were replaced by a reschedule and are kept as history
+	InstallmentSuperseded = "superseded"
+)
+
+// the states of a credit line, the credit_line_status database enum
+const (
+	CreditLineActive = "active"
+	CreditLineFrozen = "frozen"
+	CreditLineClosed = "closed"
+)
+
+// NewPlanMachine a plan is complete once its first installment is paid, only a pending plan can be cancelled
+// and only a complete one refunded. The database payment_plans_status_transition trigger allows the same transitions.
+func NewPlanMachine() *Machine[*payments.Plan] {
+	return New[*payments.Plan](map[string][]string{
+		PlanPending:  {PlanComplete, PlanCancelled},
+		PlanComplete: {PlanRefunded},
+	})
+}
+
+// NewInstallmentMachine an unpaid installment is paid, goes past due, or is voided with its plan
+// or superseded by a reschedule, a due one only becomes overdue once the grace period is over.
+// The database payment_installments_status_transition trigger allows the same transitions.
+func NewInstallmentMachine() *Machine[*payments.Installment] {
+	unpaidExits := []string{InstallmentPaid, InstallmentVoid, InstallmentSuperseded}
+
+	return New[*payments.Installment](map[string][]string{
+		InstallmentPending: append([]string{InstallmentDue}, unpaidExits...),
+		InstallmentDue:     append([]string{InstallmentOverdue}, unpaidExits...),
+		InstallmentOverdue: unpaidExits,
+	})
+}
+
+// NewCreditLineMachine a frozen line takes no new plans until it is unfrozen, a closed line stays closed.
+// The database credit_lines_status_transition trigger allows the same transitions.
+func NewCreditLineMachine() *Machine[*payments.CreditLine] {
+	return New[*payments.CreditLine](map[string][]string{
+		CreditLineActive: {CreditLineFrozen, CreditLineClosed},
+		CreditLineFrozen: {CreditLineActive, CreditLineClosed},
+	})
+}
@@ -0,0 +1,105 @@
+package statemachine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// Guard can refuse a transition the machine allows, subject is what changes state
+type Guard[T any] func(subject T, from, to string) error
+
+// Machine lists the states of T and the transitions between them, a state with no transition
+// out of it is final. Guards are added before the machine is used, it is safe for concurrent use after.
+type Machine[T any] struct {
+	transitions map[string]map[string][]Guard[T]
+}
+
+// New the machine allows the transitions from each state to the states listed and no other
+func New[T any](transitions map[string][]string) *Machine[T] {
+	m := &Machine[T]{transitions: make(map[string]map[string][]Guard[T], len(transitions))}
+
+	for from, targets := range transitions {
+		m.addState(from)
+
+		for _, to := range targets {
+			m.addState(to)
+			m.transitions[from][to] = nil
+		}
+	}
+
+	return m
+}
+
+func (m *Machine[T]) addState(state string) {
+	if _, ok := m.transitions[state]; !ok {
+		m.transitions[state] = make(map[string][]Guard[T])
+	}
+}
+
+// AddGuard runs guard on every transition from from to to, the transition has to be allowed
+func (m *Machine[T]) AddGuard(from, to string, guard Guard[T]) error {
+	if !m.Can(from, to) {
+		return fmt.Errorf("%w from %q to %q", ErrInvalidTransition, from, to)
+	}
+
+	m.transitions[from][to] = append(m.transitions[from][to], guard)
+
+	return nil
+}
+
+// IsState reports whether state is one of the states of the machine
+func (m *Machine[T]) IsState(state string) bool {
+	_, ok := m.transitions[state]
+
+	return ok
+}
+
+// IsFinal reports whether no transition leaves state
+func (m *Machine[T]) IsFinal(state string) bool {
+	return m.IsState(state) && len(m.transitions[state]) == 0
+}
+
+// Can reports whether the machine allows going from from to to, guards are not run
+func (m *Machine[T]) Can(from, to string) bool {
+	_, ok := m.transitions[from][to]
+
+	return ok
+}
+
+// Transition checks that subject can go from from to to, the first guard refusing it stops the transition
+func (m *Machine[T]) Transition(subject T, from, to string) error {
+	guards, ok := m.transitions[from][to]
+	if !ok {
+		return fmt.Errorf("%w from %q to %q", ErrInvalidTransition, from, to)
+	}
+
+	for _, guard := range guards {
+		if err := guard(subject, from, to); err != nil {
+			return fmt.Errorf("%w from %q to %q: %v", ErrInvalidTransition, from, to, err)
+		}
+	}
+
+	return nil
+}
+
+// Transitions returns the states each state can go to, sorted
+func (m *Machine[T]) Transitions() map[string][]string {
+	transitions := make(map[string][]string, len(m.transitions))
+
+	for from, targets := range m.transitions {
+		states := make([]string, 0, len(targets))
+
+		for to := range targets {
+			states = append(states, to)
+		}
+
+		sort.Strings(states)
+
+		transitions[from] = states
+	}
+
+	return transitions
+}
@@ -0,0 +1,192 @@
+package statemachine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/collections"
+)
+
+var errGuard = errors.New("guard refused")
+
+func TestMachine_Transition(t *testing.T) {
+	t.Parallel()
+
+	machine := New[string](map[string][]string{
+		"open":   {"closed", "locked"},
+		"locked": {"open"},
+	})
+
+	if err := machine.AddGuard("locked", "open", func(subject string, from, to string) error {
+		if subject != "key" {
+			return errGuard
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("AddGuard() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		subject string
+		from    string
+		to      string
+		wantErr error
+	}{
+		{
+			name: "allowed",
+			from: "open",
+			to:   "closed",
+		},
+		{
+			name:    "guard passes",
+			subject: "key",
+			from:    "locked",
+			to:      "open",
+		},
+		{
+			name:    "guard refuses",
+			subject: "card",
+			from:    "locked",
+			to:      "open",
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "out of a final state",
+			from:    "closed",
+			to:      "open",
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "not listed",
+			from:    "locked",
+			to:      "closed",
+			wantErr: ErrInvalidTransition,
+		},
+		{
+			name:    "unknown state",
+			from:    "open",
+			to:      "ajar",
+			wantErr: ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := machine.Transition(tt.subject, tt.from, tt.to); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Transition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMachine_AddGuard(t *testing.T) {
+	t.Parallel()
+
+	machine := New[string](map[string][]string{"open": {"closed"}})
+
+	err := machine.AddGuard("closed", "open", func(string, string, string) error { return nil })
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("AddGuard() error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
+
+func TestMachine_States(t *testing.T) {
+	t.Parallel()
+
+	machine := New[string](map[string][]string{"open": {"closed"}})
+
+	if !machine.IsState("closed") || machine.IsState("ajar") {
+		t.Errorf("IsState() does not list the states of the transitions")
+	}
+
+	if machine.IsFinal("open") || !machine.IsFinal("closed") {
+		t.Errorf("IsFinal() does not match the transitions")
+	}
+}
+
+func TestNewPlanMachine(t *testing.T) {
+	t.Parallel()
+
+	want := map[string][]string{
+		PlanPending:   {PlanCancelled, PlanComplete},
+		PlanComplete:  {PlanRefunded},
+		PlanCancelled: {},
+		PlanRefunded:  {},
+	}
+
+	if got := NewPlanMachine().Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transitions() = %v, want %v", got, want)
+	}
+
+	if err := NewPlanMachine().Transition(&payments.Plan{}, PlanCancelled, PlanComplete); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition() error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
+
+func TestNewInstallmentMachine(t *testing.T) {
+	t.Parallel()
+
+	want := map[string][]string{
+		InstallmentPending:    {InstallmentDue, InstallmentPaid, InstallmentSuperseded, InstallmentVoid},
+		InstallmentDue:        {InstallmentOverdue, InstallmentPaid, InstallmentSuperseded, InstallmentVoid},
+		InstallmentOverdue:    {InstallmentPaid, InstallmentSuperseded, InstallmentVoid},
+		InstallmentPaid:       {},
+		InstallmentVoid:       {},
+		InstallmentSuperseded: {},
+	}
+
+	if got := NewInstallmentMachine().Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transitions() = %v, want %v", got, want)
+	}
+
+	err := NewInstallmentMachine().Transition(&payments.Installment{}, InstallmentPending, InstallmentOverdue)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition() error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
+
+func TestNewCreditLineMachine(t *testing.T) {
+	t.Parallel()
+
+	want := map[string][]string{
+		CreditLineActive: {CreditLineClosed, CreditLineFrozen},
+		CreditLineFrozen: {CreditLineActive, CreditLineClosed},
+		CreditLineClosed: {},
+	}
+
+	if got := NewCreditLineMachine().Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transitions() = %v, want %v", got, want)
+	}
+
+	err := NewCreditLineMachine().Transition(&payments.CreditLine{}, CreditLineClosed, CreditLineActive)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition() error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
+
+func TestNewCollectionsCaseMachine(t *testing.T) {
+	t.Parallel()
+
+	want := map[string][]string{
+		CollectionsCaseOpen:   {CollectionsCaseClosed, CollectionsCasePaused},
+		CollectionsCasePaused: {CollectionsCaseClosed, CollectionsCaseOpen},
+		CollectionsCaseClosed: {},
+	}
+
+	if got := NewCollectionsCaseMachine().Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Transitions() = %v, want %v", got, want)
+	}
+
+	err := NewCollectionsCaseMachine().Transition(&collections.Case{}, CollectionsCaseClosed, CollectionsCaseOpen)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition() error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
@@ -23,6 +23,11 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PayLaterServiceClient interface {
 	GetCreditLine(ctx context.Context, in *GetCreditLineRequest, opts ...grpc.CallOption) (*GetCreditLineResponse, error)
+	CreateCreditLine(ctx context.Context, in *CreateCreditLineRequest, opts ...grpc.CallOption) (*CreateCreditLineResponse, error)
+	UpdateCreditLineLimit(ctx context.Context, in *UpdateCreditLineLimitRequest, opts ...grpc.CallOption) (*UpdateCreditLineLimitResponse, error)
+	FreezeCreditLine(ctx context.Context, in *FreezeCreditLineRequest, opts ...grpc.CallOption) (*FreezeCreditLineResponse, error)
+	UnfreezeCreditLine(ctx context.Context, in *UnfreezeCreditLineRequest, opts ...grpc.CallOption) (*UnfreezeCreditLineResponse, error)
+	CloseCreditLine(ctx context.Context, in *CloseCreditLineRequest, opts ...grpc.CallOption) (*CloseCreditLineResponse, error)
 }
 
 type payLaterServiceClient struct {
@@ -42,11 +47,61 @@ func (c *payLaterServiceClient) GetCreditLine(ctx context.Context, in *GetCredit
 	return out, nil
 }
 
+func (c *payLaterServiceClient) CreateCreditLine(ctx context.Context, in *CreateCreditLineRequest, opts ...grpc.CallOption) (*CreateCreditLineResponse, error) {
+	out := new(CreateCreditLineResponse)
+	err := c.cc.Invoke(ctx, "/creditline.v1.PayLaterService/CreateCreditLine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payLaterServiceClient) UpdateCreditLineLimit(ctx context.Context, in *UpdateCreditLineLimitRequest, opts ...grpc.CallOption) (*UpdateCreditLineLimitResponse, error) {
+	out := new(UpdateCreditLineLimitResponse)
+	err := c.cc.Invoke(ctx, "/creditline.v1.PayLaterService/UpdateCreditLineLimit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payLaterServiceClient) FreezeCreditLine(ctx context.Context, in *FreezeCreditLineRequest, opts ...grpc.CallOption) (*FreezeCreditLineResponse, error) {
+	out := new(FreezeCreditLineResponse)
+	err := c.cc.Invoke(ctx, "/creditline.v1.PayLaterService/FreezeCreditLine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payLaterServiceClient) UnfreezeCreditLine(ctx context.Context, in *UnfreezeCreditLineRequest, opts ...grpc.CallOption) (*UnfreezeCreditLineResponse, error) {
+	out := new(UnfreezeCreditLineResponse)
+	err := c.cc.Invoke(ctx, "/creditline.v1.PayLaterService/UnfreezeCreditLine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payLaterServiceClient) CloseCreditLine(ctx context.Context, in *CloseCreditLineRequest, opts ...grpc.CallOption) (*CloseCreditLineResponse, error) {
+	out := new(CloseCreditLineResponse)
+	err := c.cc.Invoke(ctx, "/creditline.v1.PayLaterService/CloseCreditLine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PayLaterServiceServer is the server API for PayLaterService service.
 // All implementations must embed UnimplementedPayLaterServiceServer
 // for forward compatibility
 type PayLaterServiceServer interface {
 	GetCreditLine(context.Context, *GetCreditLineRequest) (*GetCreditLineResponse, error)
+	CreateCreditLine(context.Context, *CreateCreditLineRequest) (*CreateCreditLineResponse, error)
+	UpdateCreditLineLimit(context.Context, *UpdateCreditLineLimitRequest) (*UpdateCreditLineLimitResponse, error)
+	FreezeCreditLine(context.Context, *FreezeCreditLineRequest) (*FreezeCreditLineResponse, error)
+	UnfreezeCreditLine(context.Context, *UnfreezeCreditLineRequest) (*UnfreezeCreditLineResponse, error)
+	CloseCreditLine(context.Context, *CloseCreditLineRequest) (*CloseCreditLineResponse, error)
 	mustEmbedUnimplementedPayLaterServiceServer()
 }
 
@@ -57,6 +112,21 @@ type UnimplementedPayLaterServiceServer struct {
 func (UnimplementedPayLaterServiceServer) GetCreditLine(context.Context, *GetCreditLineRequest) (*GetCreditLineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCreditLine not implemented")
 }
+func (UnimplementedPayLaterServiceServer) CreateCreditLine(context.Context, *CreateCreditLineRequest) (*CreateCreditLineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCreditLine not implemented")
+}
+func (UnimplementedPayLaterServiceServer) UpdateCreditLineLimit(context.Context, *UpdateCreditLineLimitRequest) (*UpdateCreditLineLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCreditLineLimit not implemented")
+}
+func (UnimplementedPayLaterServiceServer) FreezeCreditLine(context.Context, *FreezeCreditLineRequest) (*FreezeCreditLineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeCreditLine not implemented")
+}
+func (UnimplementedPayLaterServiceServer) UnfreezeCreditLine(context.Context, *UnfreezeCreditLineRequest) (*UnfreezeCreditLineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeCreditLine not implemented")
+}
+func (UnimplementedPayLaterServiceServer) CloseCreditLine(context.Context, *CloseCreditLineRequest) (*CloseCreditLineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseCreditLine not implemented")
+}
 func (UnimplementedPayLaterServiceServer) mustEmbedUnimplementedPayLaterServiceServer() {}
 
 // UnsafePayLaterServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +158,96 @@ func _PayLaterService_GetCreditLine_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PayLaterService_CreateCreditLine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCreditLineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayLaterServiceServer).CreateCreditLine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/creditline.v1.PayLaterService/CreateCreditLine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayLaterServiceServer).CreateCreditLine(ctx, req.(*CreateCreditLineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayLaterService_UpdateCreditLineLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCreditLineLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayLaterServiceServer).UpdateCreditLineLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/creditline.v1.PayLaterService/UpdateCreditLineLimit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayLaterServiceServer).UpdateCreditLineLimit(ctx, req.(*UpdateCreditLineLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayLaterService_FreezeCreditLine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeCreditLineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayLaterServiceServer).FreezeCreditLine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/creditline.v1.PayLaterService/FreezeCreditLine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayLaterServiceServer).FreezeCreditLine(ctx, req.(*FreezeCreditLineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayLaterService_UnfreezeCreditLine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeCreditLineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayLaterServiceServer).UnfreezeCreditLine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/creditline.v1.PayLaterService/UnfreezeCreditLine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayLaterServiceServer).UnfreezeCreditLine(ctx, req.(*UnfreezeCreditLineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayLaterService_CloseCreditLine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseCreditLineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayLaterServiceServer).CloseCreditLine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/creditline.v1.PayLaterService/CloseCreditLine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayLaterServiceServer).CloseCreditLine(ctx, req.(*CloseCreditLineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PayLaterService_ServiceDesc is the grpc.ServiceDesc for PayLaterService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +259,26 @@ var PayLaterService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCreditLine",
 			Handler:    _PayLaterService_GetCreditLine_Handler,
 		},
+		{
+			MethodName: "CreateCreditLine",
+			Handler:    _PayLaterService_CreateCreditLine_Handler,
+		},
+		{
+			MethodName: "UpdateCreditLineLimit",
+			Handler:    _PayLaterService_UpdateCreditLineLimit_Handler,
+		},
+		{
+			MethodName: "FreezeCreditLine",
+			Handler:    _PayLaterService_FreezeCreditLine_Handler,
+		},
+		{
+			MethodName: "UnfreezeCreditLine",
+			Handler:    _PayLaterService_UnfreezeCreditLine_Handler,
+		},
+		{
+			MethodName: "CloseCreditLine",
+			Handler:    _PayLaterService_CloseCreditLine_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "creditline/v1/creditline.proto",
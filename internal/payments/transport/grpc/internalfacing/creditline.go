@@ -0,0 +1,230 @@
+package internalfacing
+
+import (
+	"context"
+	"errors"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/grpc/bnplapi/creditline/v1"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// internalCallerMetadataKey is the X-Internal-Caller header of the REST API, gRPC metadata keys are lowercase
+const internalCallerMetadataKey = "x-internal-caller"
+
+// maxActorLength is the size of the actor column of the credit line changes
+const maxActorLength = 255
+
+var _ creditline.PayLaterServiceServer = (*PayLaterServer)(nil)
+
+// PayLaterServer serves the credit line administration, it is registered on the internal gRPC listener only,
+// the user facing one answers these calls with Unimplemented
+type PayLaterServer struct {
+	creditline.UnimplementedPayLaterServiceServer
+	creditLineService service.CreditLineService
+}
+
+func NewPayLaterServer(creditLineService service.CreditLineService) *PayLaterServer {
+	return &PayLaterServer{creditLineService: creditLineService}
+}
+
+// CreateCreditLine the actor is the caller named by the metadata
+func (s *PayLaterServer) CreateCreditLine(ctx context.Context, request *creditline.CreateCreditLineRequest) (
+	*creditline.CreateCreditLineResponse, error,
+) {
+	actor, err := callerFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, limit, err := parseCreditLineLimit(request.GetUserUuid(), request.GetLimit(), request.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	creditLine, err := s.creditLineService.CreateCreditLine(ctx, userID, &service.CreateCreditLineParams{
+		Limit:  limit,
+		Actor:  actor,
+		Reason: request.GetReason(),
+	})
+
+	creditInfo, respErr, err := newCreditInfo(creditLine, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &creditline.CreateCreditLineResponse{CreditInfo: creditInfo, Error: respErr}, nil
+}
+
+func (s *PayLaterServer) UpdateCreditLineLimit(ctx context.Context, request *creditline.UpdateCreditLineLimitRequest) (
+	*creditline.UpdateCreditLineLimitResponse, error,
+) {
+	actor, err := callerFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, limit, err := parseCreditLineLimit(request.GetUserUuid(), request.GetLimit(), request.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	creditLine, err := s.creditLineService.UpdateCreditLineLimit(ctx, userID, &service.UpdateCreditLineLimitParams{
+		Limit:  limit,
+		Actor:  actor,
+		Reason: request.GetReason(),
+	})
+
+	creditInfo, respErr, err := newCreditInfo(creditLine, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &creditline.UpdateCreditLineLimitResponse{CreditInfo: creditInfo, Error: respErr}, nil
+}
+
+func (s *PayLaterServer) FreezeCreditLine(ctx context.Context, request *creditline.FreezeCreditLineRequest) (
+	*creditline.FreezeCreditLineResponse, error,
+) {
+	creditInfo, respErr, err := changeCreditLineStatus(
+		ctx, s.creditLineService.FreezeCreditLine, request.GetUserUuid(), request.GetReason(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &creditline.FreezeCreditLineResponse{CreditInfo: creditInfo, Error: respErr}, nil
+}
+
+func (s *PayLaterServer) UnfreezeCreditLine(ctx context.Context, request *creditline.UnfreezeCreditLineRequest) (
+	*creditline.UnfreezeCreditLineResponse, error,
+) {
+	creditInfo, respErr, err := changeCreditLineStatus(
+		ctx, s.creditLineService.UnfreezeCreditLine, request.GetUserUuid(), request.GetReason(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &creditline.UnfreezeCreditLineResponse{CreditInfo: creditInfo, Error: respErr}, nil
+}
+
+func (s *PayLaterServer) CloseCreditLine(ctx context.Context, request *creditline.CloseCreditLineRequest) (
+	*creditline.CloseCreditLineResponse, error,
+) {
+	creditInfo, respErr, err := changeCreditLineStatus(
+		ctx, s.creditLineService.CloseCreditLine, request.GetUserUuid(), request.GetReason(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &creditline.CloseCreditLineResponse{CreditInfo: creditInfo, Error: respErr}, nil
+}
+
+func changeCreditLineStatus(
+	ctx context.Context,
+	changeStatus func(
+		ctx context.Context,
+		userID uuid.UUID,
+		change *service.CreditLineChangeParams,
+	) (*service.CreditLine, error),
+	userUUID, reason string,
+) (*creditline.CreditInfo, *creditline.Error, error) {
+	actor, err := callerFromMetadata(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userID, err := parseUserUUID(userUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creditLine, err := changeStatus(ctx, userID, &service.CreditLineChangeParams{Actor: actor, Reason: reason})
+
+	return newCreditInfo(creditLine, err)
+}
+
+// newCreditInfo a change refused by the service is answered with an Error and no CreditInfo,
+// any other failure is an Internal status
+func newCreditInfo(creditLine *service.CreditLine, err error) (*creditline.CreditInfo, *creditline.Error, error) {
+	if err != nil {
+		if errors.As(err, &service.CreditLineNotFoundError{}) {
+			return nil, &creditline.Error{Message: "credit line not found"}, nil
+		}
+
+		if isRefusedCreditLineChange(err) {
+			return nil, &creditline.Error{Message: err.Error()}, nil
+		}
+
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &creditline.CreditInfo{
+		TotalAmount:     creditLine.TotalAmount.String(),
+		AvailableAmount: creditLine.AvailableAmount.String(),
+		Currency:        creditLine.TotalAmount.Currency(),
+		Status:          creditLine.Status,
+	}, nil, nil
+}
+
+func isRefusedCreditLineChange(err error) bool {
+	return errors.As(err, &service.MissingActorError{}) ||
+		errors.As(err, &service.MissingReasonError{}) ||
+		errors.As(err, &service.InvalidAmountError{}) ||
+		errors.As(err, &service.AmountPrecisionError{}) ||
+		errors.As(err, &service.UnsupportedCurrencyError{}) ||
+		errors.As(err, &service.CurrencyMismatchError{}) ||
+		errors.As(err, &service.CreditLineExistsError{}) ||
+		errors.As(err, &service.CreditLineClosedError{}) ||
+		errors.As(err, &service.InvalidStateTransitionError{})
+}
+
+func parseCreditLineLimit(userUUID, limit, currency string) (uuid.UUID, payments.Money, error) {
+	userID, err := parseUserUUID(userUUID)
+	if err != nil {
+		return uuid.Nil, payments.Money{}, err
+	}
+
+	money, err := payments.ParseMoney(limit, currency)
+	if err != nil {
+		return uuid.Nil, payments.Money{}, status.Errorf(codes.InvalidArgument, "invalid limit: %v", err)
+	}
+
+	return userID, money, nil
+}
+
+func parseUserUUID(userUUID string) (uuid.UUID, error) {
+	userID, err := uuid.FromString(userUUID)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid user_uuid: %v", err)
+	}
+
+	return userID, nil
+}
+
+// callerFromMetadata the internal caller is the actor of the change, a call without one is refused
+func callerFromMetadata(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get(internalCallerMetadataKey)
+	if len(values) != 1 || values[0] == "" {
+		return "", status.Errorf(codes.Unauthenticated, "expected one %s", internalCallerMetadataKey)
+	}
+
+	caller := values[0]
+	if len(caller) > maxActorLength {
+		caller = caller[:maxActorLength]
+	}
+
+	return caller, nil
+}
@@ -0,0 +1,351 @@
+package internalfacing
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/grpc/bnplapi/creditline/v1"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func callerContext() context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(internalCallerMetadataKey, "risk-ops"))
+}
+
+func TestPayLaterServer_CreateCreditLine(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID  = uuid.Must(uuid.NewV4())
+		limit   = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		request = &creditline.CreateCreditLineRequest{
+			UserUuid: userID.String(),
+			Limit:    "1000",
+			Currency: "usdc",
+			Reason:   "onboarding",
+		}
+		params = &service.CreateCreditLineParams{Limit: limit, Actor: "risk-ops", Reason: "onboarding"}
+	)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		request      *creditline.CreateCreditLineRequest
+		prepare      func(sm *servicemock.MockCreditLineService)
+		wantResponse *creditline.CreateCreditLineResponse
+		wantCode     codes.Code
+	}{
+		{
+			name:    "happy path",
+			request: request,
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().CreateCreditLine(gomock.Any(), userID, params).Return(&service.CreditLine{
+					UserID:          userID.String(),
+					TotalAmount:     limit,
+					AvailableAmount: limit,
+					Status:          "active",
+				}, nil)
+			},
+			wantResponse: &creditline.CreateCreditLineResponse{
+				CreditInfo: &creditline.CreditInfo{
+					TotalAmount:     "1000",
+					AvailableAmount: "1000",
+					Currency:        "usdc",
+					Status:          "active",
+				},
+			},
+		},
+		{
+			name:    "credit line exists",
+			request: request,
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().CreateCreditLine(gomock.Any(), userID, params).
+					Return(nil, service.CreditLineExistsError{})
+			},
+			wantResponse: &creditline.CreateCreditLineResponse{
+				Error: &creditline.Error{Message: service.CreditLineExistsError{}.Error()},
+			},
+		},
+		{
+			name:    "service error",
+			request: request,
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().CreateCreditLine(gomock.Any(), userID, params).Return(nil, errors.New("dummyErr"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "invalid user",
+			request:  &creditline.CreateCreditLineRequest{UserUuid: "not-a-uuid", Limit: "1000", Currency: "usdc"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid limit",
+			request:  &creditline.CreateCreditLineRequest{UserUuid: userID.String(), Limit: "x", Currency: "usdc"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "no caller",
+			ctx:      context.Background(),
+			request:  request,
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			creditLineService := servicemock.NewMockCreditLineService(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(creditLineService)
+			}
+
+			server := NewPayLaterServer(creditLineService)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = callerContext()
+			}
+
+			resp, err := server.CreateCreditLine(ctx, tt.request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("unexpected error %v, want code %v", err, tt.wantCode)
+			}
+
+			if !reflect.DeepEqual(resp, tt.wantResponse) {
+				t.Errorf("returned unexpected response %v, want %v", resp, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestPayLaterServer_UpdateCreditLineLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID  = uuid.Must(uuid.NewV4())
+		limit   = payments.MustNewMoney(decimal.New(500, 0), "usdc")
+		request = &creditline.UpdateCreditLineLimitRequest{
+			UserUuid: userID.String(),
+			Limit:    "500",
+			Currency: "usdc",
+			Reason:   "missed payments",
+		}
+		params = &service.UpdateCreditLineLimitParams{Limit: limit, Actor: "risk-ops", Reason: "missed payments"}
+	)
+
+	tests := []struct {
+		name         string
+		prepare      func(sm *servicemock.MockCreditLineService)
+		wantResponse *creditline.UpdateCreditLineLimitResponse
+		wantCode     codes.Code
+	}{
+		{
+			name: "happy path",
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().UpdateCreditLineLimit(gomock.Any(), userID, params).Return(&service.CreditLine{
+					UserID:          userID.String(),
+					TotalAmount:     limit,
+					AvailableAmount: payments.MustNewMoney(decimal.New(0, 0), "usdc"),
+					Status:          "frozen",
+				}, nil)
+			},
+			wantResponse: &creditline.UpdateCreditLineLimitResponse{
+				CreditInfo: &creditline.CreditInfo{
+					TotalAmount:     "500",
+					AvailableAmount: "0",
+					Currency:        "usdc",
+					Status:          "frozen",
+				},
+			},
+		},
+		{
+			name: "no credit line",
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().UpdateCreditLineLimit(gomock.Any(), userID, params).
+					Return(nil, service.CreditLineNotFoundError{})
+			},
+			wantResponse: &creditline.UpdateCreditLineLimitResponse{
+				Error: &creditline.Error{Message: "credit line not found"},
+			},
+		},
+		{
+			name: "credit line closed",
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().UpdateCreditLineLimit(gomock.Any(), userID, params).
+					Return(nil, service.CreditLineClosedError{})
+			},
+			wantResponse: &creditline.UpdateCreditLineLimitResponse{
+				Error: &creditline.Error{Message: service.CreditLineClosedError{}.Error()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			creditLineService := servicemock.NewMockCreditLineService(ctrl)
+			tt.prepare(creditLineService)
+
+			server := NewPayLaterServer(creditLineService)
+
+			resp, err := server.UpdateCreditLineLimit(callerContext(), request)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("unexpected error %v, want code %v", err, tt.wantCode)
+			}
+
+			if !reflect.DeepEqual(resp, tt.wantResponse) {
+				t.Errorf("returned unexpected response %v, want %v", resp, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestPayLaterServer_ChangeCreditLineStatus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID = uuid.Must(uuid.NewV4())
+		limit  = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		change = &service.CreditLineChangeParams{Actor: "risk-ops", Reason: "fraud review"}
+	)
+
+	creditLineIn := func(status string) *service.CreditLine {
+		return &service.CreditLine{UserID: userID.String(), TotalAmount: limit, AvailableAmount: limit, Status: status}
+	}
+
+	creditInfoIn := func(status string) *creditline.CreditInfo {
+		return &creditline.CreditInfo{TotalAmount: "1000", AvailableAmount: "1000", Currency: "usdc", Status: status}
+	}
+
+	tests := []struct {
+		name     string
+		userUUID string
+		prepare  func(sm *servicemock.MockCreditLineService)
+		call     func(s *PayLaterServer, userUUID string) (*creditline.CreditInfo, *creditline.Error, error)
+		wantInfo *creditline.CreditInfo
+		wantErr  *creditline.Error
+		wantCode codes.Code
+	}{
+		{
+			name:     "freeze",
+			userUUID: userID.String(),
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().FreezeCreditLine(gomock.Any(), userID, change).Return(creditLineIn("frozen"), nil)
+			},
+			call: func(s *PayLaterServer, userUUID string) (*creditline.CreditInfo, *creditline.Error, error) {
+				resp, err := s.FreezeCreditLine(callerContext(), &creditline.FreezeCreditLineRequest{
+					UserUuid: userUUID, Reason: "fraud review",
+				})
+
+				return resp.GetCreditInfo(), resp.GetError(), err
+			},
+			wantInfo: creditInfoIn("frozen"),
+		},
+		{
+			name:     "unfreeze",
+			userUUID: userID.String(),
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().UnfreezeCreditLine(gomock.Any(), userID, change).Return(creditLineIn("active"), nil)
+			},
+			call: func(s *PayLaterServer, userUUID string) (*creditline.CreditInfo, *creditline.Error, error) {
+				resp, err := s.UnfreezeCreditLine(callerContext(), &creditline.UnfreezeCreditLineRequest{
+					UserUuid: userUUID, Reason: "fraud review",
+				})
+
+				return resp.GetCreditInfo(), resp.GetError(), err
+			},
+			wantInfo: creditInfoIn("active"),
+		},
+		{
+			name:     "close a closed line",
+			userUUID: userID.String(),
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().CloseCreditLine(gomock.Any(), userID, change).
+					Return(nil, service.InvalidStateTransitionError{})
+			},
+			call: func(s *PayLaterServer, userUUID string) (*creditline.CreditInfo, *creditline.Error, error) {
+				resp, err := s.CloseCreditLine(callerContext(), &creditline.CloseCreditLineRequest{
+					UserUuid: userUUID, Reason: "fraud review",
+				})
+
+				return resp.GetCreditInfo(), resp.GetError(), err
+			},
+			wantErr: &creditline.Error{Message: service.InvalidStateTransitionError{}.Error()},
+		},
+		{
+			name:     "invalid user",
+			userUUID: "not-a-uuid",
+			call: func(s *PayLaterServer, userUUID string) (*creditline.CreditInfo, *creditline.Error, error) {
+				resp, err := s.FreezeCreditLine(callerContext(), &creditline.FreezeCreditLineRequest{
+					UserUuid: userUUID,
+				})
+
+				return resp.GetCreditInfo(), resp.GetError(), err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "no caller",
+			userUUID: userID.String(),
+			call: func(s *PayLaterServer, userUUID string) (*creditline.CreditInfo, *creditline.Error, error) {
+				resp, err := s.FreezeCreditLine(context.Background(), &creditline.FreezeCreditLineRequest{
+					UserUuid: userUUID, Reason: "fraud review",
+				})
+
+				return resp.GetCreditInfo(), resp.GetError(), err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			creditLineService := servicemock.NewMockCreditLineService(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(creditLineService)
+			}
+
+			info, respErr, err := tt.call(NewPayLaterServer(creditLineService), tt.userUUID)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("unexpected error %v, want code %v", err, tt.wantCode)
+			}
+
+			if !reflect.DeepEqual(info, tt.wantInfo) || !reflect.DeepEqual(respErr, tt.wantErr) {
+				t.Errorf("returned unexpected response %v, %v, want %v, %v", info, respErr, tt.wantInfo, tt.wantErr)
+			}
+		})
+	}
+}
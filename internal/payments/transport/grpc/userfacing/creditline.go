@@ -2,32 +2,79 @@ package userfacing
 
 import (
 	"context"
+	"errors"
 
+	"golangreferenceapi/internal/payments/service"
 	"golangreferenceapi/internal/payments/transport/grpc/bnplapi/creditline/v1"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// userUUIDMetadataKey is the X-CRYPTO-USER-UUID header of the REST API, gRPC metadata keys are lowercase
+const userUUIDMetadataKey = "x-crypto-user-uuid"
+
 var _ creditline.PayLaterServiceServer = (*PayLaterServer)(nil)
 
 type PayLaterServer struct {
 	creditline.UnimplementedPayLaterServiceServer
+	creditLineService service.CreditLineService
 }
 
-func NewPayLaterServer() *PayLaterServer {
-	return &PayLaterServer{}
+func NewPayLaterServer(creditLineService service.CreditLineService) *PayLaterServer {
+	return &PayLaterServer{creditLineService: creditLineService}
 }
 
+// GetCreditLine a user without a credit line gets a response with an Error and no CreditInfo
 func (s *PayLaterServer) GetCreditLine(ctx context.Context, request *creditline.GetCreditLineRequest) (
 	*creditline.GetCreditLineResponse, error,
 ) {
+	userID, err := userUUIDFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	creditLine, err := s.creditLineService.GetCreditLine(ctx, userID)
+	if err != nil {
+		if errors.As(err, &service.CreditLineNotFoundError{}) {
+			return &creditline.GetCreditLineResponse{
+				Error: &creditline.Error{Message: "credit line not found"},
+			}, nil
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	resp := &creditline.GetCreditLineResponse{
 		CreditInfo: &creditline.CreditInfo{
-			TotalAmount:     "1000",
-			AvailableAmount: "1000",
-			Currency:        "USDC",
-			Status:          "active",
+			TotalAmount:     creditLine.TotalAmount.String(),
+			AvailableAmount: creditLine.AvailableAmount.String(),
+			Currency:        creditLine.TotalAmount.Currency(),
+			Status:          creditLine.Status,
 		},
 		Error: nil,
 	}
 
 	return resp, nil
 }
+
+func userUUIDFromMetadata(ctx context.Context) (uuid.UUID, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return uuid.Nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get(userUUIDMetadataKey)
+	if len(values) != 1 {
+		return uuid.Nil, status.Errorf(codes.Unauthenticated, "expected one %s", userUUIDMetadataKey)
+	}
+
+	userID, err := uuid.FromString(values[0])
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", userUUIDMetadataKey, err)
+	}
+
+	return userID, nil
+}
@@ -2,54 +2,171 @@ package userfacing
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
 	"golangreferenceapi/internal/payments/transport/grpc/bnplapi/creditline/v1"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestPayLaterServer_GetCreditLine(t *testing.T) {
 	t.Parallel()
 
+	userID := uuid.Must(uuid.NewV4())
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(userUUIDMetadataKey, userID.String()))
+
 	tests := []struct {
 		name         string
-		request      *creditline.GetCreditLineRequest
+		ctx          context.Context
+		prepare      func(sm *servicemock.MockCreditLineService)
 		wantResponse *creditline.GetCreditLineResponse
+		wantCode     codes.Code
 	}{
 		{
-			name:    "SimpleTest",
-			request: &creditline.GetCreditLineRequest{},
+			name: "happy path",
+			ctx:  userCtx,
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().GetCreditLine(gomock.Any(), userID).Return(&service.CreditLine{
+					UserID:          userID.String(),
+					TotalAmount:     payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+					AvailableAmount: payments.MustNewMoney(decimal.New(7505, 1), "usdc"),
+					Status:          "active",
+				}, nil)
+			},
 			wantResponse: &creditline.GetCreditLineResponse{
 				CreditInfo: &creditline.CreditInfo{
 					TotalAmount:     "1000",
-					AvailableAmount: "1000",
-					Currency:        "USDC",
+					AvailableAmount: "750.5",
+					Currency:        "usdc",
 					Status:          "active",
 				},
 				Error: nil,
 			},
 		},
+		{
+			name: "no credit line",
+			ctx:  userCtx,
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().GetCreditLine(gomock.Any(), userID).Return(nil, service.CreditLineNotFoundError{})
+			},
+			wantResponse: &creditline.GetCreditLineResponse{
+				Error: &creditline.Error{Message: "credit line not found"},
+			},
+		},
+		{
+			name: "service error",
+			ctx:  userCtx,
+			prepare: func(sm *servicemock.MockCreditLineService) {
+				sm.EXPECT().GetCreditLine(gomock.Any(), userID).Return(nil, errors.New("dummyErr"))
+			},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "missing user",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "invalid user",
+			ctx: metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs(userUUIDMetadataKey, "not-a-uuid"),
+			),
+			wantCode: codes.InvalidArgument,
+		},
 	}
-	server := NewPayLaterServer()
 
 	for _, tt := range tests {
 		tt := tt
 
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			resp, err := server.GetCreditLine(context.Background(), tt.request)
-			if err != nil {
-				t.Errorf("unexpected error %v", err)
-			} else if !reflect.DeepEqual(resp, tt.wantResponse) {
-				t.Errorf("returned unexpected response %v", tt.wantResponse)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			creditLineService := servicemock.NewMockCreditLineService(ctrl)
+
+			if tt.prepare != nil {
+				tt.prepare(creditLineService)
+			}
+
+			server := NewPayLaterServer(creditLineService)
+
+			resp, err := server.GetCreditLine(tt.ctx, &creditline.GetCreditLineRequest{})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("unexpected error %v, want code %v", err, tt.wantCode)
+			}
+
+			if !reflect.DeepEqual(resp, tt.wantResponse) {
+				t.Errorf("returned unexpected response %v, want %v", resp, tt.wantResponse)
 			}
 		})
 	}
 }
 
+func TestPayLaterServer_CreditLineAdministration(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := NewPayLaterServer(servicemock.NewMockCreditLineService(ctrl))
+	ctx := metadata.NewIncomingContext(
+		context.Background(), metadata.Pairs(userUUIDMetadataKey, uuid.Must(uuid.NewV4()).String()),
+	)
+
+	_, err := server.CreateCreditLine(ctx, &creditline.CreateCreditLineRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("CreateCreditLine returned %v, want code %v", err, codes.Unimplemented)
+	}
+
+	_, err = server.UpdateCreditLineLimit(ctx, &creditline.UpdateCreditLineLimitRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("UpdateCreditLineLimit returned %v, want code %v", err, codes.Unimplemented)
+	}
+
+	_, err = server.FreezeCreditLine(ctx, &creditline.FreezeCreditLineRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("FreezeCreditLine returned %v, want code %v", err, codes.Unimplemented)
+	}
+
+	_, err = server.UnfreezeCreditLine(ctx, &creditline.UnfreezeCreditLineRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("UnfreezeCreditLine returned %v, want code %v", err, codes.Unimplemented)
+	}
+
+	_, err = server.CloseCreditLine(ctx, &creditline.CloseCreditLineRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("CloseCreditLine returned %v, want code %v", err, codes.Unimplemented)
+	}
+}
+
 func BenchmarkPayLaterServer_GetCreditLine(b *testing.B) {
-	server := NewPayLaterServer()
-	ctx := context.Background()
+	ctrl := gomock.NewController(b)
+	defer ctrl.Finish()
+
+	userID := uuid.Must(uuid.NewV4())
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(userUUIDMetadataKey, userID.String()))
+
+	creditLineService := servicemock.NewMockCreditLineService(ctrl)
+	creditLineService.EXPECT().GetCreditLine(gomock.Any(), userID).Return(&service.CreditLine{
+		TotalAmount:     payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		AvailableAmount: payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+		Status:          "active",
+	}, nil).AnyTimes()
+
+	server := NewPayLaterServer(creditLineService)
 
 	b.ResetTimer()
 
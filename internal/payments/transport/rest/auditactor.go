@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/audit"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/monacohq/golang-common/transport/http/middleware/cryptouseruuid"
+)
+
+// HTTPHeaderKeyInternalCaller names the service calling the internal API
+const HTTPHeaderKeyInternalCaller = "X-Internal-Caller"
+
+// defaultInternalCaller is the actor of the internal calls which do not name their caller
+const defaultInternalCaller = "internal"
+
+// maxActorIDLength is the size of the actor_id column of the audit trail
+const maxActorIDLength = 255
+
+// InternalAuditActor attributes the changes made by a request to the service named by its
+// X-Internal-Caller header, the request ID set by chi's RequestID middleware is recorded with them
+func InternalAuditActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		caller := InternalCaller(req)
+		if caller == "" {
+			caller = defaultInternalCaller
+		}
+
+		next.ServeHTTP(w, withAuditActor(req, audit.InternalActor(caller)))
+	})
+}
+
+// InternalCaller is the service named by the X-Internal-Caller header of a request, empty when it names none
+func InternalCaller(req *http.Request) string {
+	caller := req.Header.Get(HTTPHeaderKeyInternalCaller)
+	if len(caller) > maxActorIDLength {
+		caller = caller[:maxActorIDLength]
+	}
+
+	return caller
+}
+
+// UserAuditActor attributes the changes made by a request to the user read by the cryptouseruuid
+// middleware, it must run after it
+func UserAuditActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userID, err := cryptouseruuid.GetUserUUID(req.Context())
+		if err != nil {
+			next.ServeHTTP(w, withAuditActor(req, audit.UnknownActor))
+
+			return
+		}
+
+		next.ServeHTTP(w, withAuditActor(req, audit.UserActor(*userID)))
+	})
+}
+
+func withAuditActor(req *http.Request, actor audit.Actor) *http.Request {
+	ctx := audit.WithActor(req.Context(), actor)
+
+	if requestID := middleware.GetReqID(ctx); requestID != "" {
+		ctx = audit.WithRequestID(ctx, requestID)
+	}
+
+	return req.WithContext(ctx)
+}
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golangreferenceapi/internal/payments/audit"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gofrs/uuid"
+	"github.com/monacohq/golang-common/transport/http/middleware/cryptouseruuid"
+)
+
+func TestInternalAuditActor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		caller        string
+		requestID     string
+		wantActor     audit.Actor
+		wantRequestID string
+	}{
+		{
+			name:          "named caller",
+			caller:        "checkout",
+			requestID:     "host/abc-000001",
+			wantActor:     audit.InternalActor("checkout"),
+			wantRequestID: "host/abc-000001",
+		},
+		{
+			name:      "unnamed caller",
+			wantActor: audit.InternalActor(defaultInternalCaller),
+		},
+		{
+			name:      "caller name longer than the audit trail keeps",
+			caller:    strings.Repeat("a", maxActorIDLength+1),
+			wantActor: audit.InternalActor(strings.Repeat("a", maxActorIDLength)),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("POST", "/", nil)
+			if tt.caller != "" {
+				req.Header.Set(HTTPHeaderKeyInternalCaller, tt.caller)
+			}
+
+			if tt.requestID != "" {
+				req = req.WithContext(withRequestID(req, tt.requestID))
+			}
+
+			gotActor, gotRequestID := serveAuditActor(InternalAuditActor, req)
+
+			if gotActor != tt.wantActor || gotRequestID != tt.wantRequestID {
+				t.Errorf("InternalAuditActor() = %v in %q, want %v in %q",
+					gotActor, gotRequestID, tt.wantActor, tt.wantRequestID)
+			}
+		})
+	}
+}
+
+func TestUserAuditActor(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.Must(uuid.NewV4())
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req = req.WithContext(cryptouseruuid.SetUserUUID(withRequestID(req, "host/abc-000002"), &userID))
+
+	if gotActor, gotRequestID := serveAuditActor(UserAuditActor, req); gotActor != audit.UserActor(userID) ||
+		gotRequestID != "host/abc-000002" {
+		t.Errorf("UserAuditActor() = %v in %q, want the user of the request", gotActor, gotRequestID)
+	}
+
+	if gotActor, _ := serveAuditActor(UserAuditActor, httptest.NewRequest("POST", "/", nil)); gotActor != audit.UnknownActor {
+		t.Errorf("UserAuditActor() = %v, want %v without a user", gotActor, audit.UnknownActor)
+	}
+}
+
+func serveAuditActor(mw func(http.Handler) http.Handler, req *http.Request) (audit.Actor, string) {
+	var (
+		actor     audit.Actor
+		requestID string
+	)
+
+	mw(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		actor = audit.ActorFromContext(req.Context())
+		requestID = audit.RequestIDFromContext(req.Context())
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	return actor, requestID
+}
+
+// withRequestID is the context chi's RequestID middleware gives to the request
+func withRequestID(req *http.Request, requestID string) context.Context {
+	return context.WithValue(req.Context(), middleware.RequestIDKey, requestID)
+}
@@ -4,12 +4,17 @@ import (
 	"errors"
 	"net/http"
 
+	"golangreferenceapi/internal/payments"
 	"golangreferenceapi/internal/payments/service"
 
 	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
 )
 
 func ServiceErrorToErrorResp(err error) *handlerwrap.ErrorResponse {
+	if resp, ok := validationErrorResp(err); ok {
+		return resp
+	}
+
 	switch {
 	case errors.As(err, &service.CreatePaymentPlanError{}):
 		return handlerwrap.NewErrorResponse(
@@ -19,6 +24,22 @@ func ServiceErrorToErrorResp(err error) *handlerwrap.ErrorResponse {
 			"create_payment_plan_failed",
 			"create payment plan failed",
 		)
+	case errors.As(err, &service.GetPaymentPlanByIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_payment_plan_by_id_failed",
+			"get payment plan by id failed",
+		)
+	case errors.As(err, &service.PaymentPlanConflictError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"payment_plan_conflict",
+			"payment plan already exists with a different payload",
+		)
 	case errors.As(err, &service.ListPaymentPlansByUserIDError{}):
 		return handlerwrap.NewErrorResponse(
 			err,
@@ -51,7 +72,663 @@ func ServiceErrorToErrorResp(err error) *handlerwrap.ErrorResponse {
 			"payment_record_not_found",
 			"payment record not found",
 		)
+	case errors.As(err, &service.UpdatePaymentPlanStatusError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_payment_plan_status_failed",
+			"update payment plan status failed",
+		)
+	case errors.As(err, &service.UpdatePaymentInstallmentStatusError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_payment_installment_status_failed",
+			"update payment installment status failed",
+		)
+	case errors.As(err, &service.UpdatePaymentInstallmentAmountError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_payment_installment_amount_failed",
+			"update payment installment amount failed",
+		)
+	case errors.As(err, &service.InstallmentNotFoundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusNotFound,
+			"payment_installment_not_found",
+			"payment installment not found",
+		)
+	case errors.As(err, &service.LockPaymentInstallmentError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"lock_payment_installment_failed",
+			"lock payment installment failed",
+		)
+	case errors.As(err, &service.InstallmentAlreadyPaidError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"payment_installment_already_paid",
+			"payment installment is already paid",
+		)
+	case errors.As(err, &service.MissingProcessorReferenceError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"missing_processor_reference",
+			err.Error(),
+		)
+	case errors.As(err, &service.OverpaymentError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusUnprocessableEntity,
+			"installment_overpayment",
+			err.Error(),
+		)
+	case errors.As(err, &service.ListPaymentTransactionsByInstallmentIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_payment_transactions_by_installmentid_failed",
+			"list payment transactions by installmentid failed",
+		)
+	case errors.As(err, &service.CreatePaymentTransactionError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_payment_transaction_failed",
+			"create payment transaction failed",
+		)
+	case errors.As(err, &service.ListPaymentLateFeesByInstallmentIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_payment_late_fees_by_installmentid_failed",
+			"list payment late fees by installmentid failed",
+		)
+	case errors.As(err, &service.ListPaymentLateFeesByPlanIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_payment_late_fees_by_planid_failed",
+			"list payment late fees by planid failed",
+		)
+	case errors.As(err, &service.InstallmentVoidError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"payment_installment_void",
+			"payment installment is void",
+		)
+	case errors.As(err, &service.LockPaymentPlanError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"lock_payment_plan_failed",
+			"lock payment plan failed",
+		)
+	case errors.As(err, &service.RefundExceedsPaidAmountError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusUnprocessableEntity,
+			"refund_exceeds_paid_amount",
+			err.Error(),
+		)
+	case errors.As(err, &service.ListPaymentRefundsByPlanIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_payment_refunds_by_planid_failed",
+			"list payment refunds by planid failed",
+		)
+	case errors.As(err, &service.CreatePaymentRefundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_payment_refund_failed",
+			"create payment refund failed",
+		)
+	case errors.As(err, &service.PaymentPlanNotPayableError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"payment_plan_not_payable",
+			"payment plan cannot be paid off",
+		)
+	case errors.As(err, &service.PaymentPlanAlreadyPaidError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"payment_plan_already_paid",
+			"payment plan is already paid",
+		)
+	case errors.As(err, &service.PayoffAmountMismatchError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusUnprocessableEntity,
+			"payoff_amount_mismatch",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreatePaymentSettlementError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_payment_settlement_failed",
+			"create payment settlement failed",
+		)
+	case errors.As(err, &service.InstallmentSupersededError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"installment_superseded",
+			err.Error(),
+		)
+	case errors.As(err, &service.PaymentPlanNotReschedulableError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"payment_plan_not_reschedulable",
+			err.Error(),
+		)
+	case errors.As(err, &service.InvalidStateTransitionError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"invalid_state_transition",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreditLineNotFoundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusNotFound,
+			"credit_line_not_found",
+			"credit line not found",
+		)
+	case errors.As(err, &service.GetCreditLineByUserIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_credit_line_by_userid_failed",
+			"get credit line by userid failed",
+		)
+	case errors.As(err, &service.LockCreditLineError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"lock_credit_line_failed",
+			"lock credit line failed",
+		)
+	case errors.As(err, &service.GetUserOutstandingAmountError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_user_outstanding_amount_failed",
+			"get user outstanding amount failed",
+		)
+	case errors.As(err, &service.CreditLineNotActiveError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusUnprocessableEntity,
+			"credit_line_not_active",
+			err.Error(),
+		)
+	case errors.As(err, &service.InsufficientCreditError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusUnprocessableEntity,
+			"insufficient_credit",
+			err.Error(),
+		)
+	case errors.As(err, &service.MissingActorError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"missing_actor",
+			err.Error(),
+		)
+	case errors.As(err, &service.MissingReasonError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"missing_reason",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreditLineExistsError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"credit_line_exists",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreditLineClosedError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"credit_line_closed",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreateCreditLineError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_credit_line_failed",
+			"create credit line failed",
+		)
+	case errors.As(err, &service.UpdateCreditLineLimitError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_credit_line_limit_failed",
+			"update credit line limit failed",
+		)
+	case errors.As(err, &service.UpdateCreditLineStatusError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_credit_line_status_failed",
+			"update credit line status failed",
+		)
+	case errors.As(err, &service.CreateCreditLineChangeError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_credit_line_change_failed",
+			"create credit line change failed",
+		)
+	case errors.As(err, &service.ListCreditLineChangesError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_credit_line_changes_failed",
+			"list credit line changes failed",
+		)
+	case errors.As(err, &service.InvalidJournalEntryError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"invalid_journal_entry",
+			"invalid journal entry",
+		)
+	case errors.As(err, &service.CreateJournalEntryError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_journal_entry_failed",
+			"create journal entry failed",
+		)
+	case errors.As(err, &service.ListJournalEntriesByPlanIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_journal_entries_failed",
+			"list journal entries failed",
+		)
+	case errors.As(err, &service.CreateOutboxEventError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_outbox_event_failed",
+			"create outbox event failed",
+		)
+	case errors.As(err, &service.InvalidWebhookEndpointError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"invalid_webhook_endpoint",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreateWebhookEndpointError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_webhook_endpoint_failed",
+			"create webhook endpoint failed",
+		)
+	case errors.As(err, &service.InvalidWebhookDeliveryStatusError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"invalid_webhook_delivery_status",
+			err.Error(),
+		)
+	case errors.As(err, &service.ListWebhookDeliveriesError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_webhook_deliveries_failed",
+			"list webhook deliveries failed",
+		)
+	case errors.As(err, &service.WebhookDeliveryNotFoundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusNotFound,
+			"webhook_delivery_not_found",
+			err.Error(),
+		)
+	case errors.As(err, &service.LockWebhookDeliveryError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"lock_webhook_delivery_failed",
+			"lock webhook delivery failed",
+		)
+	case errors.As(err, &service.WebhookDeliveryNotDeadError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"webhook_delivery_not_dead",
+			err.Error(),
+		)
+	case errors.As(err, &service.UpdateWebhookDeliveryError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_webhook_delivery_failed",
+			"update webhook delivery failed",
+		)
+	case errors.As(err, &service.InvalidCollectionsCaseStatusError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"invalid_collections_case_status",
+			err.Error(),
+		)
+	case errors.As(err, &service.ListCollectionsCasesError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_collections_cases_failed",
+			"list collections cases failed",
+		)
+	case errors.As(err, &service.CollectionsCaseNotFoundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusNotFound,
+			"collections_case_not_found",
+			err.Error(),
+		)
+	case errors.As(err, &service.GetCollectionsCaseError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_collections_case_failed",
+			"get collections case failed",
+		)
+	case errors.As(err, &service.ListCollectionsCaseEventsError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_collections_case_events_failed",
+			"list collections case events failed",
+		)
+	case errors.As(err, &service.LockCollectionsCaseError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"lock_collections_case_failed",
+			"lock collections case failed",
+		)
+	case errors.As(err, &service.UpdateCollectionsCaseError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"update_collections_case_failed",
+			"update collections case failed",
+		)
+	case errors.As(err, &service.CreateCollectionsCaseEventError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_collections_case_event_failed",
+			"create collections case event failed",
+		)
+	case errors.As(err, &service.MissingMerchantError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"missing_merchant",
+			err.Error(),
+		)
+	case errors.As(err, &service.MerchantNotFoundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusNotFound,
+			"merchant_not_found",
+			err.Error(),
+		)
+	case errors.As(err, &service.GetMerchantError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_merchant_failed",
+			"get merchant failed",
+		)
+	case errors.As(err, &service.MissingMerchantNameError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"missing_merchant_name",
+			err.Error(),
+		)
+	case errors.As(err, &service.CreateMerchantError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_merchant_failed",
+			"create merchant failed",
+		)
+	case errors.As(err, &service.ListMerchantPaymentPlansError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_merchant_payment_plans_failed",
+			"list merchant payment plans failed",
+		)
+	case errors.As(err, &service.InvalidDateRangeError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusBadRequest,
+			"invalid_date_range",
+			err.Error(),
+		)
+	case errors.As(err, &service.GetMerchantTotalsError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_merchant_totals_failed",
+			"get merchant totals failed",
+		)
+	case errors.As(err, &service.OrderReferenceConflictError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusConflict,
+			"order_reference_conflict",
+			err.Error(),
+		)
+	case errors.As(err, &service.OrderNotFoundError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusNotFound,
+			"order_not_found",
+			err.Error(),
+		)
+	case errors.As(err, &service.GetPaymentPlanByOrderReferenceError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_payment_plan_by_order_reference_failed",
+			"get payment plan by order reference failed",
+		)
+	case errors.As(err, &service.CreatePaymentPlanLineItemError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_payment_plan_line_item_failed",
+			"create payment plan line item failed",
+		)
+	case errors.As(err, &service.ListPaymentPlanLineItemsByPlanIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_payment_plan_line_items_failed",
+			"list payment plan line items failed",
+		)
+	case errors.As(err, &service.GetLatestAuditEntryError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"get_latest_audit_entry_failed",
+			"get latest audit entry failed",
+		)
+	case errors.As(err, &service.CreateAuditEntryError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"create_audit_entry_failed",
+			"create audit entry failed",
+		)
+	case errors.As(err, &service.ListAuditEntriesByPlanIDError{}):
+		return handlerwrap.NewErrorResponse(
+			err,
+			make(map[string]string),
+			http.StatusInternalServerError,
+			"list_audit_entries_failed",
+			"list audit entries failed",
+		)
 	default:
 		return handlerwrap.InternalServerError{Err: err}.ToErrorResponse()
 	}
 }
+
+// validationErrorResp answers every validation rule err broke at once with the message of each field,
+// the status and the code are the ones of the first rule with the lowest status
+// so a malformed field wins over a well formed one which cannot be processed
+func validationErrorResp(err error) (*handlerwrap.ErrorResponse, bool) {
+	var violations service.ValidationErrors
+	if !errors.As(err, &violations) {
+		violations = service.ValidationErrors{err}
+	}
+
+	var (
+		statusCode int
+		errCode    string
+	)
+
+	for _, violation := range violations {
+		violationStatusCode, violationErrCode, ok := validationRule(violation)
+		if ok && (statusCode == 0 || violationStatusCode < statusCode) {
+			statusCode, errCode = violationStatusCode, violationErrCode
+		}
+	}
+
+	if statusCode == 0 {
+		return nil, false
+	}
+
+	return handlerwrap.NewErrorResponse(err, violations.Fields(), statusCode, errCode, err.Error()), true
+}
+
+// validationRule the status and the code a broken validation rule is answered with
+func validationRule(err error) (int, string, bool) {
+	switch {
+	case errors.As(err, &service.InvalidAmountError{}):
+		return http.StatusBadRequest, "invalid_amount", true
+	case errors.As(err, &service.MissingInstallmentsError{}):
+		return http.StatusUnprocessableEntity, "missing_installments", true
+	case errors.As(err, &service.InvalidScheduleError{}):
+		return http.StatusBadRequest, "invalid_schedule", true
+	case errors.As(err, &service.ScheduleConflictError{}):
+		return http.StatusBadRequest, "schedule_conflict", true
+	case errors.As(err, &service.UnsupportedCurrencyError{}):
+		return http.StatusBadRequest, "unsupported_currency", true
+	case errors.As(err, &service.AmountPrecisionError{}):
+		return http.StatusBadRequest, "invalid_amount_precision", true
+	case errors.As(err, &service.CurrencyMismatchError{}), errors.Is(err, payments.ErrCurrencyMismatch):
+		return http.StatusUnprocessableEntity, "currency_mismatch", true
+	case errors.As(err, &service.PastDueDateError{}):
+		return http.StatusUnprocessableEntity, "past_due_date", true
+	case errors.As(err, &service.InstallmentSumMismatchError{}):
+		return http.StatusUnprocessableEntity, "installment_sum_mismatch", true
+	case errors.As(err, &service.InvalidOrderError{}):
+		return http.StatusBadRequest, "invalid_order", true
+	default:
+		return 0, "", false
+	}
+}
@@ -2,9 +2,11 @@ package rest
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
+	"golangreferenceapi/internal/payments"
 	"golangreferenceapi/internal/payments/service"
 )
 
@@ -41,6 +43,463 @@ func TestServiceErrorToErrorResp(t *testing.T) {
 			err:        service.PaymentRecordNotFoundError{},
 			statusCode: http.StatusNotFound,
 		},
+		{
+			name:       "get payment plan by id error",
+			err:        service.GetPaymentPlanByIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "payment plan conflict error",
+			err:        service.PaymentPlanConflictError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "payment installment not found error",
+			err:        service.InstallmentNotFoundError{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "lock payment installment error",
+			err:        service.LockPaymentInstallmentError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "payment installment already paid error",
+			err:        service.InstallmentAlreadyPaidError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "missing processor reference error",
+			err:        service.MissingProcessorReferenceError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "installment overpayment error",
+			err:        service.OverpaymentError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "list payment transactions by installment error",
+			err:        service.ListPaymentTransactionsByInstallmentIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create payment transaction error",
+			err:        service.CreatePaymentTransactionError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list payment late fees by installmentid error",
+			err:        service.ListPaymentLateFeesByInstallmentIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list payment late fees by planid error",
+			err:        service.ListPaymentLateFeesByPlanIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "update payment plan status error",
+			err:        service.UpdatePaymentPlanStatusError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "update payment installment status error",
+			err:        service.UpdatePaymentInstallmentStatusError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "update payment installment amount error",
+			err:        service.UpdatePaymentInstallmentAmountError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid amount",
+			err:        service.InvalidAmountError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "missing installments",
+			err:        service.MissingInstallmentsError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid schedule",
+			err:        service.InvalidScheduleError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "schedule conflict",
+			err:        service.ScheduleConflictError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported currency",
+			err:        service.UnsupportedCurrencyError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "amount precision",
+			err:        service.AmountPrecisionError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "currency mismatch",
+			err:        service.CurrencyMismatchError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "money currency mismatch",
+			err:        fmt.Errorf("record installment payment: %w", payments.ErrCurrencyMismatch),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "past due date",
+			err:        service.PastDueDateError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "installment sum mismatch",
+			err:        service.InstallmentSumMismatchError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "every broken validation rule",
+			err: fmt.Errorf("create pending payment plan: %w", service.ValidationErrors{
+				service.PastDueDateError{},
+				service.InstallmentSumMismatchError{},
+			}),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "a malformed field wins over the rules broken before it",
+			err: fmt.Errorf("create pending payment plan: %w", service.ValidationErrors{
+				service.PastDueDateError{},
+				service.InvalidAmountError{},
+				service.InstallmentSumMismatchError{},
+			}),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "installment void",
+			err:        service.InstallmentVoidError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "lock payment plan error",
+			err:        service.LockPaymentPlanError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "refund exceeds paid amount",
+			err:        service.RefundExceedsPaidAmountError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "list payment refunds by planid error",
+			err:        service.ListPaymentRefundsByPlanIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create payment refund error",
+			err:        service.CreatePaymentRefundError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "payment plan not payable",
+			err:        service.PaymentPlanNotPayableError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "payment plan already paid",
+			err:        service.PaymentPlanAlreadyPaidError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "payoff amount mismatch",
+			err:        service.PayoffAmountMismatchError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "create payment settlement error",
+			err:        service.CreatePaymentSettlementError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "installment superseded",
+			err:        service.InstallmentSupersededError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "payment plan not reschedulable",
+			err:        service.PaymentPlanNotReschedulableError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "invalid state transition",
+			err:        service.InvalidStateTransitionError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "credit line not found",
+			err:        service.CreditLineNotFoundError{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "get credit line by userid error",
+			err:        service.GetCreditLineByUserIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "lock credit line error",
+			err:        service.LockCreditLineError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "get user outstanding amount error",
+			err:        service.GetUserOutstandingAmountError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "credit line not active",
+			err:        service.CreditLineNotActiveError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "insufficient credit",
+			err:        service.InsufficientCreditError{},
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "missing actor",
+			err:        service.MissingActorError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "missing reason",
+			err:        service.MissingReasonError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "credit line exists",
+			err:        service.CreditLineExistsError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "credit line closed",
+			err:        service.CreditLineClosedError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "create credit line",
+			err:        service.CreateCreditLineError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "update credit line limit",
+			err:        service.UpdateCreditLineLimitError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "update credit line status",
+			err:        service.UpdateCreditLineStatusError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create credit line change",
+			err:        service.CreateCreditLineChangeError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list credit line changes",
+			err:        service.ListCreditLineChangesError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid journal entry",
+			err:        service.InvalidJournalEntryError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create journal entry",
+			err:        service.CreateJournalEntryError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list journal entries",
+			err:        service.ListJournalEntriesByPlanIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create outbox event",
+			err:        service.CreateOutboxEventError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid webhook endpoint",
+			err:        service.InvalidWebhookEndpointError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "create webhook endpoint",
+			err:        service.CreateWebhookEndpointError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid webhook delivery status",
+			err:        service.InvalidWebhookDeliveryStatusError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "list webhook deliveries",
+			err:        service.ListWebhookDeliveriesError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "webhook delivery not found",
+			err:        service.WebhookDeliveryNotFoundError{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "lock webhook delivery",
+			err:        service.LockWebhookDeliveryError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "webhook delivery not dead",
+			err:        service.WebhookDeliveryNotDeadError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "update webhook delivery",
+			err:        service.UpdateWebhookDeliveryError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid collections case status",
+			err:        service.InvalidCollectionsCaseStatusError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "list collections cases",
+			err:        service.ListCollectionsCasesError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "collections case not found",
+			err:        service.CollectionsCaseNotFoundError{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "get collections case",
+			err:        service.GetCollectionsCaseError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list collections case events",
+			err:        service.ListCollectionsCaseEventsError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "lock collections case",
+			err:        service.LockCollectionsCaseError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "update collections case",
+			err:        service.UpdateCollectionsCaseError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create collections case event",
+			err:        service.CreateCollectionsCaseEventError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "missing merchant",
+			err:        service.MissingMerchantError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "merchant not found",
+			err:        service.MerchantNotFoundError{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "get merchant",
+			err:        service.GetMerchantError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "missing merchant name",
+			err:        service.MissingMerchantNameError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "create merchant",
+			err:        service.CreateMerchantError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list merchant payment plans",
+			err:        service.ListMerchantPaymentPlansError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid date range",
+			err:        service.InvalidDateRangeError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "get merchant totals",
+			err:        service.GetMerchantTotalsError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid order",
+			err:        service.InvalidOrderError{},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "order reference conflict",
+			err:        service.OrderReferenceConflictError{},
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "order not found",
+			err:        service.OrderNotFoundError{},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "get payment plan by order reference",
+			err:        service.GetPaymentPlanByOrderReferenceError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create payment plan line item",
+			err:        service.CreatePaymentPlanLineItemError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list payment plan line items",
+			err:        service.ListPaymentPlanLineItemsByPlanIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "get latest audit entry",
+			err:        service.GetLatestAuditEntryError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "create audit entry",
+			err:        service.CreateAuditEntryError{},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "list audit entries",
+			err:        service.ListAuditEntriesByPlanIDError{},
+			statusCode: http.StatusInternalServerError,
+		},
 		{
 			name:       "unknown",
 			err:        errors.New("error unknown"),
@@ -0,0 +1,49 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type GetPaymentPlanAuditResponse struct {
+	Audit service.PaymentPlanAudit `json:"audit"`
+}
+
+// getPaymentPlanAuditHandler renders the audit trail of a payment plan
+// @Summary Gets the audit trail of a payment plan
+// @Description returns every change made to a payment plan, oldest first, with who made it
+// @Description and the plan before and after. The entries are hash-chained, intact is false and broken_at
+// @Description the first entry not matching its hash or the entry before it when the trail was tampered with.
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/audit [get]
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} GetPaymentPlanAuditResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid payment uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func getPaymentPlanAuditHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		planAudit, err := paymentService.GetPaymentPlanAudit(req.Context(), *paymentUUID)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       GetPaymentPlanAuditResponse{Audit: *planAudit},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
@@ -0,0 +1,130 @@
+package internalfacing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/audit"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_getPaymentPlanAuditHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		amount        = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+		planAudit     = service.PaymentPlanAudit{
+			PaymentPlanID: paymentPlanID.String(),
+			Intact:        true,
+			Entries: []service.AuditEntry{
+				{
+					Sequence:   1,
+					Actor:      audit.InternalActor("checkout"),
+					Action:     audit.ActionPlanCreated,
+					After:      &audit.Snapshot{Plan: &audit.PlanState{Status: "pending", Amount: amount}},
+					RequestID:  "req-1",
+					OccurredAt: "2022-10-19T04:00:00Z",
+					Hash:       "2f0c6b1e",
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       GetPaymentPlanAuditResponse{Audit: planAudit},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+	paymentService.EXPECT().GetPaymentPlanAudit(
+		gomock.Eq(req.Context()),
+		gomock.Eq(paymentPlanID),
+	).Return(&planAudit, nil)
+
+	resp, errRsp := getPaymentPlanAuditHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_getPaymentPlanAuditHandlerError(t *testing.T) {
+	t.Parallel()
+
+	paymentPlanID := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name           string
+		paymentUUID    string
+		prepare        func(sm *servicemock.MockPaymentPlanService)
+		wantStatusCode int
+	}{
+		{
+			name:           "returns 400 if passing a invalid payment uuid",
+			paymentUUID:    "x",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "returns 404 if the payment plan does not exist",
+			paymentUUID: paymentPlanID.String(),
+			prepare: func(sm *servicemock.MockPaymentPlanService) {
+				sm.EXPECT().GetPaymentPlanAudit(gomock.Any(), paymentPlanID).Return(nil, service.PaymentRecordNotFoundError{})
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:        "returns 500 if the service fails",
+			paymentUUID: paymentPlanID.String(),
+			prepare: func(sm *servicemock.MockPaymentPlanService) {
+				sm.EXPECT().GetPaymentPlanAudit(gomock.Any(), paymentPlanID).Return(nil, errors.New("dummyErr"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+			if tt.prepare != nil {
+				tt.prepare(paymentService)
+			}
+
+			req := httptest.NewRequest("GET", "/", nil)
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := getPaymentPlanAuditHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != tt.wantStatusCode {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, tt.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package internalfacing
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/gofrs/uuid"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type CollectionsCaseChangeRequest struct {
+	Change service.CollectionsCaseChangeParams `json:"change"`
+}
+
+type CollectionsCaseResponse struct {
+	Case service.CollectionsCase `json:"case"`
+}
+
+type ListCollectionsCasesResponse struct {
+	Cases []service.CollectionsCase `json:"cases"`
+}
+
+// listCollectionsCasesHandler lists the collections cases in a status
+// @Summary Lists collections cases
+// @Description the cases in status, open by default, the latest first
+// @Tags collections
+// @Produce json
+// @Router /internal/v1/collections-cases [get]
+// @Param status query string false "open, paused or closed"
+// @Success 200 {object} ListCollectionsCasesResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid status"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func listCollectionsCasesHandler(collectionsService service.CollectionsService) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		status := req.URL.Query().Get(queryParamStatus)
+		if status == "" {
+			status = service.CollectionsCaseStatusOpen
+		}
+
+		cases, err := collectionsService.ListCollectionsCases(req.Context(), status)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       ListCollectionsCasesResponse{Cases: cases},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// getCollectionsCaseHandler returns a collections case with its history
+// @Summary Gets a collections case
+// @Description the case with the stages it went through and who paused, resumed or closed it, the oldest step first
+// @Tags collections
+// @Produce json
+// @Router /internal/v1/collections-cases/{case_uuid} [get]
+// @Param case_uuid path string true "Collections case UUID"
+// @Success 200 {object} CollectionsCaseResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid case uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "collections case not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func getCollectionsCaseHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	collectionsService service.CollectionsService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		caseUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamCaseUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		collectionsCase, err := collectionsService.GetCollectionsCase(req.Context(), *caseUUID)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       CollectionsCaseResponse{Case: *collectionsCase},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// pauseCollectionsCaseHandler stops a collections case from climbing the ladder
+// @Summary Pauses a collections case
+// @Description a paused case keeps its stage and is still closed once the overdue installments are paid
+// @Tags collections
+// @Produce json
+// @Router /internal/v1/collections-cases/{case_uuid}/pause [post]
+// @Param collections_case_change_request body CollectionsCaseChangeRequest true "Collections case change reqBody"
+// @Param case_uuid path string true "Collections case UUID"
+// @Success 200 {object} CollectionsCaseResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing actor or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "collections case not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "collections case is not open"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func pauseCollectionsCaseHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	collectionsService service.CollectionsService,
+) handlerwrap.TypedHandler {
+	return collectionsCaseChangeHandler(paramsGetter, collectionsService.PauseCollectionsCase)
+}
+
+// resumeCollectionsCaseHandler makes a paused collections case climb the ladder again
+// @Summary Resumes a collections case
+// @Description the next stage of the case is reached its delay after now
+// @Tags collections
+// @Produce json
+// @Router /internal/v1/collections-cases/{case_uuid}/resume [post]
+// @Param collections_case_change_request body CollectionsCaseChangeRequest true "Collections case change reqBody"
+// @Param case_uuid path string true "Collections case UUID"
+// @Success 200 {object} CollectionsCaseResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing actor or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "collections case not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "collections case is not paused"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func resumeCollectionsCaseHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	collectionsService service.CollectionsService,
+) handlerwrap.TypedHandler {
+	return collectionsCaseChangeHandler(paramsGetter, func(
+		ctx context.Context,
+		caseID uuid.UUID,
+		change *service.CollectionsCaseChangeParams,
+	) (*service.CollectionsCase, error) {
+		return collectionsService.ResumeCollectionsCase(ctx, caseID, time.Now().UTC(), change)
+	})
+}
+
+// closeCollectionsCaseHandler closes a collections case for good
+// @Summary Closes a collections case
+// @Description a closed case cannot be reopened, a credit line frozen by the case stays frozen,
+// @Description the plan gets a new case if it is still delinquent on the next collections run
+// @Tags collections
+// @Produce json
+// @Router /internal/v1/collections-cases/{case_uuid}/close [post]
+// @Param collections_case_change_request body CollectionsCaseChangeRequest true "Collections case change reqBody"
+// @Param case_uuid path string true "Collections case UUID"
+// @Success 200 {object} CollectionsCaseResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing actor or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "collections case not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "collections case is already closed"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func closeCollectionsCaseHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	collectionsService service.CollectionsService,
+) handlerwrap.TypedHandler {
+	return collectionsCaseChangeHandler(paramsGetter, func(
+		ctx context.Context,
+		caseID uuid.UUID,
+		change *service.CollectionsCaseChangeParams,
+	) (*service.CollectionsCase, error) {
+		return collectionsService.CloseCollectionsCase(ctx, caseID, time.Now().UTC(), change)
+	})
+}
+
+func collectionsCaseChangeHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	changeCase func(
+		ctx context.Context,
+		caseID uuid.UUID,
+		change *service.CollectionsCaseChangeParams,
+	) (*service.CollectionsCase, error),
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request CollectionsCaseChangeRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		caseUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamCaseUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		collectionsCase, err := changeCase(req.Context(), *caseUUID, &request.Change)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       CollectionsCaseResponse{Case: *collectionsCase},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
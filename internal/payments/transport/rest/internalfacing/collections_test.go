@@ -0,0 +1,195 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_listCollectionsCasesHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := []service.CollectionsCase{
+		{
+			ID:            uuid.Must(uuid.NewV4()).String(),
+			PaymentPlanID: uuid.Must(uuid.NewV4()).String(),
+			UserID:        uuid.Must(uuid.NewV4()).String(),
+			Status:        "open",
+			Stage:         "first_notice",
+			NextActionAt:  "2022-10-25T20:00:00Z",
+			OpenedAt:      "2022-10-18T20:00:00Z",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus string
+	}{
+		{
+			name:       "open by default",
+			target:     "/",
+			wantStatus: "open",
+		},
+		{
+			name:       "status query",
+			target:     "/?status=paused",
+			wantStatus: "paused",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+
+			collectionsService.EXPECT().
+				ListCollectionsCases(gomock.Eq(req.Context()), gomock.Eq(tt.wantStatus)).
+				Return(cases, nil)
+
+			resp, errRsp := listCollectionsCasesHandler(collectionsService)(req)
+			if errRsp != nil {
+				t.Errorf("returned unexpected error response: %v", errRsp)
+			}
+
+			wantResponse := &handlerwrap.Response{
+				StatusCode: http.StatusOK,
+				Body:       ListCollectionsCasesResponse{Cases: cases},
+			}
+			if !reflect.DeepEqual(resp, wantResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+			}
+		})
+	}
+}
+
+func Test_getCollectionsCaseHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		caseID          = uuid.Must(uuid.NewV4())
+		collectionsCase = service.CollectionsCase{
+			ID:            caseID.String(),
+			PaymentPlanID: uuid.Must(uuid.NewV4()).String(),
+			UserID:        uuid.Must(uuid.NewV4()).String(),
+			Status:        "open",
+			OpenedAt:      "2022-10-18T20:00:00Z",
+			History: []service.CollectionsCaseEvent{
+				{
+					ID:        uuid.Must(uuid.NewV4()).String(),
+					Kind:      "opened",
+					Actor:     "collections",
+					CreatedAt: "2022-10-18T20:00:00Z",
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       CollectionsCaseResponse{Case: collectionsCase},
+		}
+	)
+
+	collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	setURLParams(req, map[string]string{urlParamCaseUUID: caseID.String()})
+
+	collectionsService.EXPECT().
+		GetCollectionsCase(gomock.Eq(req.Context()), gomock.Eq(caseID)).
+		Return(&collectionsCase, nil)
+
+	resp, errRsp := getCollectionsCaseHandler(rest.ChiNamedURLParamsGetter, collectionsService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_pauseCollectionsCaseHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		caseID          = uuid.Must(uuid.NewV4())
+		collectionsCase = service.CollectionsCase{
+			ID:            caseID.String(),
+			PaymentPlanID: uuid.Must(uuid.NewV4()).String(),
+			UserID:        uuid.Must(uuid.NewV4()).String(),
+			Status:        "paused",
+			Stage:         "first_notice",
+			OpenedAt:      "2022-10-18T20:00:00Z",
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       CollectionsCaseResponse{Case: collectionsCase},
+		}
+		request = CollectionsCaseChangeRequest{
+			Change: service.CollectionsCaseChangeParams{Actor: "agent-7", Reason: "payment promised"},
+		}
+	)
+
+	collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{urlParamCaseUUID: caseID.String()})
+
+	collectionsService.EXPECT().PauseCollectionsCase(
+		gomock.Eq(req.Context()),
+		gomock.Eq(caseID),
+		gomock.Eq(&request.Change),
+	).Return(&collectionsCase, nil)
+
+	resp, errRsp := pauseCollectionsCaseHandler(rest.ChiNamedURLParamsGetter, collectionsService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_closeCollectionsCaseHandler_Closed(t *testing.T) {
+	t.Parallel()
+
+	caseID := uuid.Must(uuid.NewV4())
+	collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(`{"change": {"actor": "agent-7", "reason": "paid"}}`)))
+
+	setURLParams(req, map[string]string{urlParamCaseUUID: caseID.String()})
+
+	collectionsService.EXPECT().
+		CloseCollectionsCase(gomock.Eq(req.Context()), gomock.Eq(caseID), gomock.Any(), gomock.Any()).
+		Return(nil, service.InvalidStateTransitionError{})
+
+	_, errRsp := closeCollectionsCaseHandler(rest.ChiNamedURLParamsGetter, collectionsService)(req)
+	if errRsp == nil || errRsp.StatusCode != http.StatusConflict {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+}
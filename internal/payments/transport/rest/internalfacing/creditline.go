@@ -0,0 +1,251 @@
+package internalfacing
+
+import (
+	"context"
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/gofrs/uuid"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type CreateCreditLineRequest struct {
+	CreditLine service.CreateCreditLineParams `json:"credit_line"`
+}
+
+type UpdateCreditLineLimitRequest struct {
+	CreditLine service.UpdateCreditLineLimitParams `json:"credit_line"`
+}
+
+type CreditLineChangeRequest struct {
+	Change service.CreditLineChangeParams `json:"change"`
+}
+
+type CreditLineResponse struct {
+	CreditLine service.CreditLine `json:"credit_line"`
+}
+
+type ListCreditLineChangesResponse struct {
+	Changes []service.CreditLineChange `json:"changes"`
+}
+
+// createCreditLineHandler opens a credit line for a user
+// @Summary Creates a credit line
+// @Description opens an active credit line, a user has one credit line at most
+// @Tags credit_line
+// @Produce json
+// @Router /internal/v1/credit-lines/{user_uuid} [post]
+// @Param create_credit_line_request body CreateCreditLineRequest true "Create credit line reqBody"
+// @Param user_uuid path string true "User UUID"
+// @Param X-Internal-Caller header string true "Service calling the internal API, recorded as the actor of the change"
+// @Success 200 {object} CreditLineResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid limit or missing X-Internal-Caller or reason"
+// @Failure 409 {object} handlerwrap.ErrorResponse "the user already has a credit line"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func createCreditLineHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	creditLineService service.CreditLineService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request CreateCreditLineRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		userUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamUserUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		request.CreditLine.Actor = rest.InternalCaller(req)
+
+		creditLine, err := creditLineService.CreateCreditLine(req.Context(), *userUUID, &request.CreditLine)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       CreditLineResponse{CreditLine: *creditLine},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// updateCreditLineLimitHandler raises or lowers the limit of a credit line
+// @Summary Updates a credit line limit
+// @Description a limit lowered below what the user owes leaves nothing available, running payment plans are kept
+// @Tags credit_line
+// @Produce json
+// @Router /internal/v1/credit-lines/{user_uuid}/limit [post]
+// @Param update_credit_line_limit_request body UpdateCreditLineLimitRequest true "Update credit line limit reqBody"
+// @Param user_uuid path string true "User UUID"
+// @Param X-Internal-Caller header string true "Service calling the internal API, recorded as the actor of the change"
+// @Success 200 {object} CreditLineResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid limit or missing X-Internal-Caller or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "credit line not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "credit line is closed"
+// @Failure 422 {object} handlerwrap.ErrorResponse "currency mismatch"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func updateCreditLineLimitHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	creditLineService service.CreditLineService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request UpdateCreditLineLimitRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		userUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamUserUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		request.CreditLine.Actor = rest.InternalCaller(req)
+
+		creditLine, err := creditLineService.UpdateCreditLineLimit(req.Context(), *userUUID, &request.CreditLine)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       CreditLineResponse{CreditLine: *creditLine},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// freezeCreditLineHandler stops a credit line from taking new payment plans
+// @Summary Freezes a credit line
+// @Description a frozen credit line takes no new payment plans until it is unfrozen
+// @Tags credit_line
+// @Produce json
+// @Router /internal/v1/credit-lines/{user_uuid}/freeze [post]
+// @Param credit_line_change_request body CreditLineChangeRequest true "Credit line change reqBody"
+// @Param user_uuid path string true "User UUID"
+// @Param X-Internal-Caller header string true "Service calling the internal API, recorded as the actor of the change"
+// @Success 200 {object} CreditLineResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing X-Internal-Caller or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "credit line not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "credit line is not active"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func freezeCreditLineHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	creditLineService service.CreditLineService,
+) handlerwrap.TypedHandler {
+	return creditLineStatusHandler(paramsGetter, creditLineService.FreezeCreditLine)
+}
+
+// unfreezeCreditLineHandler makes a frozen credit line active again
+// @Summary Unfreezes a credit line
+// @Description a frozen credit line is active again
+// @Tags credit_line
+// @Produce json
+// @Router /internal/v1/credit-lines/{user_uuid}/unfreeze [post]
+// @Param credit_line_change_request body CreditLineChangeRequest true "Credit line change reqBody"
+// @Param user_uuid path string true "User UUID"
+// @Param X-Internal-Caller header string true "Service calling the internal API, recorded as the actor of the change"
+// @Success 200 {object} CreditLineResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing X-Internal-Caller or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "credit line not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "credit line is not frozen"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func unfreezeCreditLineHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	creditLineService service.CreditLineService,
+) handlerwrap.TypedHandler {
+	return creditLineStatusHandler(paramsGetter, creditLineService.UnfreezeCreditLine)
+}
+
+// closeCreditLineHandler closes a credit line for good
+// @Summary Closes a credit line
+// @Description a closed credit line takes no new payment plans and cannot be reopened,
+// @Description its running payment plans are left as they are
+// @Tags credit_line
+// @Produce json
+// @Router /internal/v1/credit-lines/{user_uuid}/close [post]
+// @Param credit_line_change_request body CreditLineChangeRequest true "Credit line change reqBody"
+// @Param user_uuid path string true "User UUID"
+// @Param X-Internal-Caller header string true "Service calling the internal API, recorded as the actor of the change"
+// @Success 200 {object} CreditLineResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing X-Internal-Caller or reason"
+// @Failure 404 {object} handlerwrap.ErrorResponse "credit line not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "credit line is already closed"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func closeCreditLineHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	creditLineService service.CreditLineService,
+) handlerwrap.TypedHandler {
+	return creditLineStatusHandler(paramsGetter, creditLineService.CloseCreditLine)
+}
+
+// listCreditLineChangesHandler lists who changed a credit line and why
+// @Summary Lists the changes of a credit line
+// @Description the oldest change comes first
+// @Tags credit_line
+// @Produce json
+// @Router /internal/v1/credit-lines/{user_uuid}/changes [get]
+// @Param user_uuid path string true "User UUID"
+// @Success 200 {object} ListCreditLineChangesResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid user uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "credit line not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func listCreditLineChangesHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	creditLineService service.CreditLineService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		userUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamUserUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		changes, err := creditLineService.ListCreditLineChanges(req.Context(), *userUUID)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       ListCreditLineChangesResponse{Changes: changes},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+func creditLineStatusHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	changeStatus func(
+		ctx context.Context,
+		userID uuid.UUID,
+		change *service.CreditLineChangeParams,
+	) (*service.CreditLine, error),
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request CreditLineChangeRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		userUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamUserUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		request.Change.Actor = rest.InternalCaller(req)
+
+		creditLine, err := changeStatus(req.Context(), *userUUID, &request.Change)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       CreditLineResponse{CreditLine: *creditLine},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
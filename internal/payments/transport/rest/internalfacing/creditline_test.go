@@ -0,0 +1,403 @@
+package internalfacing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_createCreditLineHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID     = uuid.Must(uuid.NewV4())
+		limit      = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		creditLine = service.CreditLine{
+			ID:              uuid.Must(uuid.NewV4()).String(),
+			UserID:          userID.String(),
+			TotalAmount:     limit,
+			AvailableAmount: limit,
+			Status:          "active",
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       CreditLineResponse{CreditLine: creditLine},
+		}
+		request = CreateCreditLineRequest{
+			CreditLine: service.CreateCreditLineParams{Limit: limit, Actor: "risk-ops", Reason: "onboarding"},
+		}
+	)
+
+	creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+	req.Header.Set(rest.HTTPHeaderKeyInternalCaller, "risk-ops")
+
+	setURLParams(req, map[string]string{urlParamUserUUID: userID.String()})
+
+	creditLineService.EXPECT().CreateCreditLine(
+		gomock.Eq(req.Context()),
+		gomock.Eq(userID),
+		gomock.Eq(&request.CreditLine),
+	).Return(&creditLine, nil)
+
+	resp, errRsp := createCreditLineHandler(rest.ChiNamedURLParamsGetter, creditLineService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_createCreditLineHandler_actorIsTheCaller(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.Must(uuid.NewV4())
+	reqBody := `{"credit_line": {"limit": {"value": "1000", "currency": "usdc"}, "actor": "spoofed", ` +
+		`"reason": "onboarding"}}`
+
+	creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(reqBody)))
+	req.Header.Set(rest.HTTPHeaderKeyInternalCaller, "risk-ops")
+
+	setURLParams(req, map[string]string{urlParamUserUUID: userID.String()})
+
+	creditLineService.EXPECT().CreateCreditLine(gomock.Eq(req.Context()), gomock.Eq(userID), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, params *service.CreateCreditLineParams) (
+			*service.CreditLine, error,
+		) {
+			if params.Actor != "risk-ops" {
+				t.Errorf("CreateCreditLine() actor = %q, want the caller %q", params.Actor, "risk-ops")
+			}
+
+			return &service.CreditLine{}, nil
+		})
+
+	if _, errRsp := createCreditLineHandler(rest.ChiNamedURLParamsGetter, creditLineService)(req); errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+}
+
+func Test_updateCreditLineLimitHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID     = uuid.Must(uuid.NewV4())
+		limit      = payments.MustNewMoney(decimal.New(500, 0), "usdc")
+		creditLine = service.CreditLine{
+			ID:              uuid.Must(uuid.NewV4()).String(),
+			UserID:          userID.String(),
+			TotalAmount:     limit,
+			AvailableAmount: limit,
+			Status:          "active",
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       CreditLineResponse{CreditLine: creditLine},
+		}
+		request = UpdateCreditLineLimitRequest{
+			CreditLine: service.UpdateCreditLineLimitParams{Limit: limit, Actor: "risk-ops", Reason: "missed payments"},
+		}
+	)
+
+	creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+	req.Header.Set(rest.HTTPHeaderKeyInternalCaller, "risk-ops")
+
+	setURLParams(req, map[string]string{urlParamUserUUID: userID.String()})
+
+	creditLineService.EXPECT().UpdateCreditLineLimit(
+		gomock.Eq(req.Context()),
+		gomock.Eq(userID),
+		gomock.Eq(&request.CreditLine),
+	).Return(&creditLine, nil)
+
+	resp, errRsp := updateCreditLineLimitHandler(rest.ChiNamedURLParamsGetter, creditLineService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_creditLineStatusHandlers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID  = uuid.Must(uuid.NewV4())
+		limit   = payments.MustNewMoney(decimal.New(1000, 0), "usdc")
+		request = CreditLineChangeRequest{
+			Change: service.CreditLineChangeParams{Actor: "risk-ops", Reason: "fraud review"},
+		}
+	)
+
+	tests := []struct {
+		name    string
+		handler func(handlerwrap.NamedURLParamsGetter, service.CreditLineService) handlerwrap.TypedHandler
+		prepare func(sm *servicemock.MockCreditLineService, creditLine *service.CreditLine)
+		status  string
+	}{
+		{
+			name:    "freeze",
+			handler: freezeCreditLineHandler,
+			prepare: func(sm *servicemock.MockCreditLineService, creditLine *service.CreditLine) {
+				sm.EXPECT().FreezeCreditLine(gomock.Any(), userID, &request.Change).Return(creditLine, nil)
+			},
+			status: "frozen",
+		},
+		{
+			name:    "unfreeze",
+			handler: unfreezeCreditLineHandler,
+			prepare: func(sm *servicemock.MockCreditLineService, creditLine *service.CreditLine) {
+				sm.EXPECT().UnfreezeCreditLine(gomock.Any(), userID, &request.Change).Return(creditLine, nil)
+			},
+			status: "active",
+		},
+		{
+			name:    "close",
+			handler: closeCreditLineHandler,
+			prepare: func(sm *servicemock.MockCreditLineService, creditLine *service.CreditLine) {
+				sm.EXPECT().CloseCreditLine(gomock.Any(), userID, &request.Change).Return(creditLine, nil)
+			},
+			status: "closed",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			creditLine := service.CreditLine{
+				UserID:          userID.String(),
+				TotalAmount:     limit,
+				AvailableAmount: limit,
+				Status:          tt.status,
+			}
+			wantResponse := &handlerwrap.Response{
+				StatusCode: http.StatusOK,
+				Body:       CreditLineResponse{CreditLine: creditLine},
+			}
+
+			creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+			tt.prepare(creditLineService, &creditLine)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+			req.Header.Set(rest.HTTPHeaderKeyInternalCaller, "risk-ops")
+
+			setURLParams(req, map[string]string{urlParamUserUUID: userID.String()})
+
+			resp, errRsp := tt.handler(rest.ChiNamedURLParamsGetter, creditLineService)(req)
+			if errRsp != nil {
+				t.Errorf("returned unexpected error response: %v", errRsp)
+			}
+
+			if !reflect.DeepEqual(resp, wantResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+			}
+		})
+	}
+}
+
+func Test_creditLineHandlersParamsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		handler  func(handlerwrap.NamedURLParamsGetter, service.CreditLineService) handlerwrap.TypedHandler
+		reqBody  string
+		userUUID string
+	}{
+		{
+			name:     "returns 400 if passing a broken reqBody to create",
+			handler:  createCreditLineHandler,
+			reqBody:  `{"credit_line":`,
+			userUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+		{
+			name:     "returns 400 if passing a invalid user uuid to update the limit",
+			handler:  updateCreditLineLimitHandler,
+			reqBody:  `{"credit_line":{}}`,
+			userUUID: "x",
+		},
+		{
+			name:     "returns 400 if passing a broken reqBody to freeze",
+			handler:  freezeCreditLineHandler,
+			reqBody:  `{"change":`,
+			userUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+		{
+			name:     "returns 400 if passing a invalid user uuid to list the changes",
+			handler:  listCreditLineChangesHandler,
+			userUUID: "x",
+		},
+	}
+
+	creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(tt.reqBody)))
+
+			setURLParams(req, map[string]string{urlParamUserUUID: tt.userUUID})
+
+			resp, errRsp := tt.handler(rest.ChiNamedURLParamsGetter, creditLineService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != http.StatusBadRequest {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func Test_creditLineHandlersServiceError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID  = uuid.Must(uuid.NewV4())
+		request = CreditLineChangeRequest{
+			Change: service.CreditLineChangeParams{Actor: "risk-ops", Reason: "fraud review"},
+		}
+	)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "credit line not found error",
+			err:  service.CreditLineNotFoundError{},
+		},
+		{
+			name: "invalid state transition error",
+			err:  service.InvalidStateTransitionError{},
+		},
+		{
+			name: "missing actor error",
+			err:  service.MissingActorError{},
+		},
+		{
+			name: "update credit line status error",
+			err:  service.UpdateCreditLineStatusError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantResponse := rest.ServiceErrorToErrorResp(tt.err)
+
+			creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+			creditLineService.EXPECT().FreezeCreditLine(
+				gomock.Any(),
+				gomock.Eq(userID),
+				gomock.Eq(&request.Change),
+			).Return(nil, tt.err)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+			req.Header.Set(rest.HTTPHeaderKeyInternalCaller, "risk-ops")
+
+			setURLParams(req, map[string]string{urlParamUserUUID: userID.String()})
+
+			resp, errRsp := freezeCreditLineHandler(rest.ChiNamedURLParamsGetter, creditLineService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if !reflect.DeepEqual(errRsp, wantResponse) {
+				t.Errorf("returned unexpected error response. expected: %v, actual: %v", wantResponse, errRsp)
+			}
+		})
+	}
+}
+
+func Test_listCreditLineChangesHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID  = uuid.Must(uuid.NewV4())
+		changes = []service.CreditLineChange{
+			{
+				ID:        uuid.Must(uuid.NewV4()).String(),
+				Action:    "freeze",
+				Actor:     "risk-ops",
+				Reason:    "fraud review",
+				Limit:     payments.MustNewMoney(decimal.New(1000, 0), "usdc"),
+				Status:    "frozen",
+				CreatedAt: "2022-10-18T20:00:00Z",
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       ListCreditLineChangesResponse{Changes: changes},
+		}
+	)
+
+	creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	setURLParams(req, map[string]string{urlParamUserUUID: userID.String()})
+
+	creditLineService.EXPECT().ListCreditLineChanges(gomock.Eq(req.Context()), gomock.Eq(userID)).Return(changes, nil)
+
+	resp, errRsp := listCreditLineChangesHandler(rest.ChiNamedURLParamsGetter, creditLineService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
@@ -0,0 +1,71 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type RecordInstallmentPaymentRequest struct {
+	Payment service.InstallmentPaymentParams `json:"payment"`
+}
+
+type RecordInstallmentPaymentResponse struct {
+	Payment service.InstallmentPayment `json:"payment"`
+}
+
+// recordInstallmentPaymentHandler records a payment against an installment
+// @Summary Records an installment payment
+// @Description records a full or partial payment of an installment, the installment is paid once fully covered
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/installments/{installment_uuid}/payments [post]
+// @Param record_installment_payment_request body RecordInstallmentPaymentRequest true "Record installment payment reqBody"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Param installment_uuid path string true "Payment Installment UUID"
+// @Success 200 {object} RecordInstallmentPaymentResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid amount or missing processor reference"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment installment not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment installment is already paid"
+// @Failure 422 {object} handlerwrap.ErrorResponse "currency mismatch or amount exceeds the outstanding amount"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func recordInstallmentPaymentHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request RecordInstallmentPaymentRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		installmentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamInstallmentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		payment, err := paymentService.RecordInstallmentPayment(
+			req.Context(),
+			*paymentUUID,
+			*installmentUUID,
+			&request.Payment,
+		)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       RecordInstallmentPaymentResponse{Payment: *payment},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
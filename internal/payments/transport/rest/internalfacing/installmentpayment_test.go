@@ -0,0 +1,219 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_recordInstallmentPaymentHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		installmentID = uuid.Must(uuid.NewV4())
+		paidAt        = time.Now().UTC().Truncate(time.Second)
+		paramsGetter  = rest.ChiNamedURLParamsGetter
+		payment       = service.InstallmentPayment{
+			ID:                 uuid.Must(uuid.NewV4()).String(),
+			InstallmentID:      installmentID.String(),
+			Amount:             payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt.Format(common.TimeFormat),
+			InstallmentStatus:  "pending",
+			OutstandingAmount:  payments.MustNewMoney(decimal.New(10, 0), "usdc"),
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       RecordInstallmentPaymentResponse{Payment: payment},
+		}
+		request = RecordInstallmentPaymentRequest{
+			Payment: service.InstallmentPaymentParams{
+				Amount:             payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             paidAt,
+			},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{
+		urlParamPaymentUUID:     paymentPlanID.String(),
+		urlParamInstallmentUUID: installmentID.String(),
+	})
+
+	gomock.InOrder(
+		paymentService.EXPECT().RecordInstallmentPayment(
+			gomock.Eq(req.Context()),
+			gomock.Eq(paymentPlanID),
+			gomock.Eq(installmentID),
+			gomock.Eq(&request.Payment),
+		).Return(&payment, nil),
+	)
+
+	resp, errRsp := recordInstallmentPaymentHandler(paramsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_recordInstallmentPaymentHandlerParamsError(t *testing.T) {
+	t.Parallel()
+
+	validUUID := uuid.Must(uuid.NewV4()).String()
+
+	tests := []struct {
+		name            string
+		reqBody         string
+		paymentUUID     string
+		installmentUUID string
+	}{
+		{
+			name:            "returns 400 if passing a broken reqBody",
+			reqBody:         `{"payment":`,
+			paymentUUID:     validUUID,
+			installmentUUID: validUUID,
+		},
+		{
+			name:            "returns 400 if passing a invalid payment uuid",
+			reqBody:         `{"payment":{}}`,
+			paymentUUID:     "x",
+			installmentUUID: validUUID,
+		},
+		{
+			name:            "returns 400 if passing a invalid installment uuid",
+			reqBody:         `{"payment":{}}`,
+			paymentUUID:     validUUID,
+			installmentUUID: "x",
+		},
+	}
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(tt.reqBody)))
+
+			setURLParams(req, map[string]string{
+				urlParamPaymentUUID:     tt.paymentUUID,
+				urlParamInstallmentUUID: tt.installmentUUID,
+			})
+
+			resp, errRsp := recordInstallmentPaymentHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != http.StatusBadRequest {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func Test_recordInstallmentPaymentHandlerServiceError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		installmentID = uuid.Must(uuid.NewV4())
+		request       = RecordInstallmentPaymentRequest{
+			Payment: service.InstallmentPaymentParams{
+				Amount:             payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+				ProcessorReference: "psp-ref-1",
+			},
+		}
+	)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "installment not found error",
+			err:  service.InstallmentNotFoundError{},
+		},
+		{
+			name: "installment already paid error",
+			err:  service.InstallmentAlreadyPaidError{},
+		},
+		{
+			name: "overpayment error",
+			err:  service.OverpaymentError{},
+		},
+		{
+			name: "create payment transaction error",
+			err:  service.CreatePaymentTransactionError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantResponse := rest.ServiceErrorToErrorResp(tt.err)
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().RecordInstallmentPayment(
+				gomock.Any(),
+				gomock.Eq(paymentPlanID),
+				gomock.Eq(installmentID),
+				gomock.Eq(&request.Payment),
+			).Return(nil, tt.err)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+			setURLParams(req, map[string]string{
+				urlParamPaymentUUID:     paymentPlanID.String(),
+				urlParamInstallmentUUID: installmentID.String(),
+			})
+
+			resp, errRsp := recordInstallmentPaymentHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if !reflect.DeepEqual(wantResponse, errRsp) { // nolint: deepequalerrors // linter bug these are responses, not errors
+				t.Errorf("returned unexpected err. expected: %v, actual: %v", wantResponse, errRsp)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type GetPaymentPlanLedgerResponse struct {
+	Ledger service.PaymentPlanLedger `json:"ledger"`
+}
+
+// getPaymentPlanLedgerHandler renders the journal entries of a payment plan and the balances they add up to
+// @Summary Gets the ledger of a payment plan
+// @Description returns every journal entry posted for a payment plan, oldest first, and the balance of each account
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/ledger [get]
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} GetPaymentPlanLedgerResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid payment uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func getPaymentPlanLedgerHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		planLedger, err := paymentService.GetPaymentPlanLedger(req.Context(), *paymentUUID)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       GetPaymentPlanLedgerResponse{Ledger: *planLedger},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
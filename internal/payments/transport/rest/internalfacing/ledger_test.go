@@ -0,0 +1,125 @@
+package internalfacing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/ledger"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_getPaymentPlanLedgerHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		amount        = payments.MustNewMoney(decimal.New(100, 0), "usdc")
+		planLedger    = service.PaymentPlanLedger{
+			PaymentPlanID: paymentPlanID.String(),
+			Balances: []ledger.Balance{
+				{Account: ledger.AccountUserReceivable, Amount: amount},
+				{Account: ledger.AccountMerchantPayable, Amount: amount},
+			},
+			Entries: []service.JournalEntry{
+				{
+					ID:   uuid.Must(uuid.NewV4()).String(),
+					Kind: ledger.EntryPlanCreated,
+					Postings: []service.JournalPosting{
+						{Account: ledger.AccountUserReceivable, Direction: ledger.Debit, Amount: amount},
+						{Account: ledger.AccountMerchantPayable, Direction: ledger.Credit, Amount: amount},
+					},
+					CreatedAt: "2022-07-01T10:00:00Z",
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       GetPaymentPlanLedgerResponse{Ledger: planLedger},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+	paymentService.EXPECT().GetPaymentPlanLedger(
+		gomock.Eq(req.Context()),
+		gomock.Eq(paymentPlanID),
+	).Return(&planLedger, nil)
+
+	resp, errRsp := getPaymentPlanLedgerHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_getPaymentPlanLedgerHandlerError(t *testing.T) {
+	t.Parallel()
+
+	paymentPlanID := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name           string
+		paymentUUID    string
+		prepare        func(sm *servicemock.MockPaymentPlanService)
+		wantStatusCode int
+	}{
+		{
+			name:           "returns 400 if passing a invalid payment uuid",
+			paymentUUID:    "x",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "returns 500 if the service fails",
+			paymentUUID: paymentPlanID.String(),
+			prepare: func(sm *servicemock.MockPaymentPlanService) {
+				sm.EXPECT().GetPaymentPlanLedger(gomock.Any(), paymentPlanID).Return(nil, errors.New("dummyErr"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+			if tt.prepare != nil {
+				tt.prepare(paymentService)
+			}
+
+			req := httptest.NewRequest("GET", "/", nil)
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := getPaymentPlanLedgerHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != tt.wantStatusCode {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, tt.wantStatusCode)
+			}
+		})
+	}
+}
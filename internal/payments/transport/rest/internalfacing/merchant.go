@@ -0,0 +1,197 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+const (
+	merchantPaymentPlansDefaultLimit = 50
+)
+
+type CreateMerchantRequest struct {
+	Merchant service.CreateMerchantParams `json:"merchant"`
+}
+
+type MerchantResponse struct {
+	Merchant service.Merchant `json:"merchant"`
+}
+
+type ListMerchantPaymentPlansResponse struct {
+	Payments []service.PaymentPlans `json:"payments"`
+}
+
+type MerchantOrderPaymentPlanResponse struct {
+	Payment service.PaymentPlans `json:"payment"`
+}
+
+type MerchantTotalsResponse struct {
+	Totals service.MerchantTotals `json:"totals"`
+}
+
+// createMerchantHandler records a merchant, the payment plans and webhook endpoints are then created for it
+// @Summary Creates a merchant
+// @Description creates a merchant the payment plans can be created for
+// @Tags merchant
+// @Produce json
+// @Router /internal/v1/merchants [post]
+// @Param create_merchant_request body CreateMerchantRequest true "Create merchant reqBody"
+// @Success 200 {object} MerchantResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or missing name"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func createMerchantHandler(merchantService service.MerchantService) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request CreateMerchantRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		merchant, err := merchantService.CreateMerchant(req.Context(), &request.Merchant)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       MerchantResponse{Merchant: *merchant},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// listMerchantPaymentPlansHandler lists a page of the payment plans of a merchant
+// @Summary Lists the payment plans of a merchant
+// @Description the plans of the merchant with their current schedule, the latest first by default
+// @Tags merchant
+// @Produce json
+// @Router /internal/v1/merchants/{merchant_uuid}/payment-plans [get]
+// @Param merchant_uuid path string true "Merchant UUID"
+// @Param offset query int false "number of plans to skip"
+// @Param limit query int false "number of plans to return, at most 50"
+// @Param created_at_order query string false "asc or desc"
+// @Success 200 {object} ListMerchantPaymentPlansResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid pagination"
+// @Failure 404 {object} handlerwrap.ErrorResponse "merchant not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func listMerchantPaymentPlansHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	merchantService service.MerchantService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		merchantUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamMerchantUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		pagination, respErr := rest.ParsePaginationURLQuery(
+			req.URL, merchantPaymentPlansDefaultLimit, rest.PaymentPlansCreatedAtOrderDESC,
+		)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		page := &service.ListMerchantPaymentPlansParams{
+			Offset:      pagination.Offset,
+			Limit:       pagination.Limit,
+			OldestFirst: pagination.CreatedAtOrder == rest.PaymentPlansCreatedAtOrderASC,
+		}
+
+		plans, err := merchantService.ListMerchantPaymentPlans(req.Context(), *merchantUUID, page)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       ListMerchantPaymentPlansResponse{Payments: plans},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// getMerchantOrderPaymentPlanHandler looks up the payment plan a merchant created for one of its orders
+// @Summary Gets the payment plan of a merchant order
+// @Description the plan created with the order reference, its line items and its current schedule
+// @Tags merchant
+// @Produce json
+// @Router /internal/v1/merchants/{merchant_uuid}/orders/{order_reference}/payment-plan [get]
+// @Param merchant_uuid path string true "Merchant UUID"
+// @Param order_reference path string true "Order reference of the merchant"
+// @Success 200 {object} MerchantOrderPaymentPlanResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid merchant uuid or order reference"
+// @Failure 404 {object} handlerwrap.ErrorResponse "merchant or order not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func getMerchantOrderPaymentPlanHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	merchantService service.MerchantService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		merchantUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamMerchantUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		orderReference, respErr := paramsGetter(req.Context(), urlParamOrderReference)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		plan, err := merchantService.GetPaymentPlanByOrderReference(req.Context(), *merchantUUID, orderReference)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       MerchantOrderPaymentPlanResponse{Payment: *plan},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// getMerchantTotalsHandler sums the payment plans of a merchant created in a date range
+// @Summary Gets the totals of a merchant
+// @Description per currency, the originated, outstanding and refunded amounts of the plans created from from to to
+// @Tags merchant
+// @Produce json
+// @Router /internal/v1/merchants/{merchant_uuid}/totals [get]
+// @Param merchant_uuid path string true "Merchant UUID"
+// @Param from query string true "RFC3339 start of the range, included"
+// @Param to query string true "RFC3339 end of the range, excluded"
+// @Success 200 {object} MerchantTotalsResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "missing or invalid date range"
+// @Failure 404 {object} handlerwrap.ErrorResponse "merchant not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func getMerchantTotalsHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	merchantService service.MerchantService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		merchantUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamMerchantUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		from, respErr := parseTimeFormatQuery(req.URL, queryParamFrom)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		to, respErr := parseTimeFormatQuery(req.URL, queryParamTo)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		totals, err := merchantService.GetMerchantTotals(req.Context(), *merchantUUID, from, to)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       MerchantTotalsResponse{Totals: *totals},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
@@ -0,0 +1,277 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_createMerchantHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		merchant = service.Merchant{
+			ID:        uuid.Must(uuid.NewV4()).String(),
+			Name:      "Acme",
+			CreatedAt: "2022-10-19T02:00:00Z",
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       MerchantResponse{Merchant: merchant},
+		}
+		request = CreateMerchantRequest{Merchant: service.CreateMerchantParams{Name: "Acme"}}
+	)
+
+	merchantService := servicemock.NewMockMerchantService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	merchantService.EXPECT().CreateMerchant(gomock.Eq(req.Context()), gomock.Eq(&request.Merchant)).Return(&merchant, nil)
+
+	resp, errRsp := createMerchantHandler(merchantService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_listMerchantPaymentPlansHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		merchantID = uuid.Must(uuid.NewV4())
+		plans      = []service.PaymentPlans{
+			{
+				ID:          uuid.Must(uuid.NewV4()).String(),
+				UserID:      uuid.Must(uuid.NewV4()).String(),
+				MerchantID:  merchantID.String(),
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+				Status:      "complete",
+				CreatedAt:   "2022-10-19T02:00:00Z",
+			},
+		}
+	)
+
+	tests := []struct {
+		name     string
+		target   string
+		wantPage *service.ListMerchantPaymentPlansParams
+	}{
+		{
+			name:     "latest first by default",
+			target:   "/",
+			wantPage: &service.ListMerchantPaymentPlansParams{Limit: merchantPaymentPlansDefaultLimit},
+		},
+		{
+			name:     "pagination query",
+			target:   "/?offset=20&limit=10&created_at_order=asc",
+			wantPage: &service.ListMerchantPaymentPlansParams{Offset: 20, Limit: 10, OldestFirst: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			merchantService := servicemock.NewMockMerchantService(gomock.NewController(t))
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+
+			setURLParams(req, map[string]string{urlParamMerchantUUID: merchantID.String()})
+
+			merchantService.EXPECT().
+				ListMerchantPaymentPlans(gomock.Eq(req.Context()), gomock.Eq(merchantID), gomock.Eq(tt.wantPage)).
+				Return(plans, nil)
+
+			resp, errRsp := listMerchantPaymentPlansHandler(rest.ChiNamedURLParamsGetter, merchantService)(req)
+			if errRsp != nil {
+				t.Errorf("returned unexpected error response: %v", errRsp)
+			}
+
+			wantResponse := &handlerwrap.Response{
+				StatusCode: http.StatusOK,
+				Body:       ListMerchantPaymentPlansResponse{Payments: plans},
+			}
+
+			if !reflect.DeepEqual(resp, wantResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+			}
+		})
+	}
+}
+
+func Test_getMerchantOrderPaymentPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		merchantID = uuid.Must(uuid.NewV4())
+		plan       = service.PaymentPlans{
+			ID:             uuid.Must(uuid.NewV4()).String(),
+			UserID:         uuid.Must(uuid.NewV4()).String(),
+			MerchantID:     merchantID.String(),
+			OrderReference: "order-42",
+			TotalAmount:    payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			Status:         "pending",
+			CreatedAt:      "2022-10-19T02:00:00Z",
+			LineItems: []service.PaymentPlanLineItem{
+				{Name: "Chair", Quantity: 1, UnitPrice: payments.MustNewMoney(decimal.New(100, 0), "usdc")},
+			},
+		}
+	)
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		prepare      func(merchantService *servicemock.MockMerchantService)
+		wantResponse *handlerwrap.Response
+		wantErrResp  *handlerwrap.ErrorResponse
+	}{
+		{
+			name:      "happy path",
+			urlParams: map[string]string{urlParamMerchantUUID: merchantID.String(), urlParamOrderReference: "order-42"},
+			prepare: func(merchantService *servicemock.MockMerchantService) {
+				merchantService.EXPECT().
+					GetPaymentPlanByOrderReference(gomock.Any(), gomock.Eq(merchantID), gomock.Eq("order-42")).
+					Return(&plan, nil)
+			},
+			wantResponse: &handlerwrap.Response{
+				StatusCode: http.StatusOK,
+				Body:       MerchantOrderPaymentPlanResponse{Payment: plan},
+			},
+		},
+		{
+			name:        "missing order reference",
+			urlParams:   map[string]string{urlParamMerchantUUID: merchantID.String()},
+			prepare:     func(merchantService *servicemock.MockMerchantService) {},
+			wantErrResp: handlerwrap.MissingParamError{Name: urlParamOrderReference}.ToErrorResponse(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			merchantService := servicemock.NewMockMerchantService(gomock.NewController(t))
+			tt.prepare(merchantService)
+
+			req := httptest.NewRequest("GET", "/", nil)
+
+			setURLParams(req, tt.urlParams)
+
+			resp, errRsp := getMerchantOrderPaymentPlanHandler(rest.ChiNamedURLParamsGetter, merchantService)(req)
+			if !reflect.DeepEqual(errRsp, tt.wantErrResp) {
+				t.Errorf("returned unexpected error response. expected: %v, actual: %v", tt.wantErrResp, errRsp)
+			}
+
+			if !reflect.DeepEqual(resp, tt.wantResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", tt.wantResponse, resp)
+			}
+		})
+	}
+}
+
+func Test_getMerchantTotalsHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		merchantID = uuid.Must(uuid.NewV4())
+		from       = time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+		to         = time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+		totals     = service.MerchantTotals{
+			MerchantID: merchantID.String(),
+			From:       "2022-10-01T00:00:00Z",
+			To:         "2022-11-01T00:00:00Z",
+			Totals: []service.MerchantCurrencyTotals{
+				{
+					Originated:  payments.MustNewMoney(decimal.New(400, 0), "usdc"),
+					Outstanding: payments.MustNewMoney(decimal.New(130, 0), "usdc"),
+					Refunded:    payments.MustNewMoney(decimal.New(60, 0), "usdc"),
+				},
+			},
+		}
+	)
+
+	tests := []struct {
+		name         string
+		target       string
+		prepare      func(merchantService *servicemock.MockMerchantService)
+		wantResponse *handlerwrap.Response
+		wantErrResp  *handlerwrap.ErrorResponse
+	}{
+		{
+			name:   "happy path",
+			target: "/?from=2022-10-01T00:00:00Z&to=2022-11-01T00:00:00Z",
+			prepare: func(merchantService *servicemock.MockMerchantService) {
+				merchantService.EXPECT().
+					GetMerchantTotals(gomock.Any(), gomock.Eq(merchantID), gomock.Eq(from), gomock.Eq(to)).
+					Return(&totals, nil)
+			},
+			wantResponse: &handlerwrap.Response{
+				StatusCode: http.StatusOK,
+				Body:       MerchantTotalsResponse{Totals: totals},
+			},
+		},
+		{
+			name:        "missing from",
+			target:      "/?to=2022-11-01T00:00:00Z",
+			prepare:     func(merchantService *servicemock.MockMerchantService) {},
+			wantErrResp: handlerwrap.MissingParamError{Name: queryParamFrom}.ToErrorResponse(),
+		},
+		{
+			name:        "invalid to",
+			target:      "/?from=2022-10-01T00:00:00Z&to=2022-11-01",
+			prepare:     func(merchantService *servicemock.MockMerchantService) {},
+			wantErrResp: handlerwrap.ParsingParamError{Name: queryParamTo, Value: "2022-11-01"}.ToErrorResponse(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			merchantService := servicemock.NewMockMerchantService(gomock.NewController(t))
+			tt.prepare(merchantService)
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+
+			setURLParams(req, map[string]string{urlParamMerchantUUID: merchantID.String()})
+
+			resp, errRsp := getMerchantTotalsHandler(rest.ChiNamedURLParamsGetter, merchantService)(req)
+			if !reflect.DeepEqual(errRsp, tt.wantErrResp) {
+				t.Errorf("returned unexpected error response. expected: %v, actual: %v", tt.wantErrResp, errRsp)
+			}
+
+			if !reflect.DeepEqual(resp, tt.wantResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", tt.wantResponse, resp)
+			}
+		})
+	}
+}
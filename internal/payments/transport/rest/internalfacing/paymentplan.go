@@ -25,19 +25,31 @@ type CompletePaymentPlanResponse struct {
 	Payment service.PaymentPlans `json:"payment"`
 }
 
+type CancelPaymentPlanRequest struct {
+	Payment service.CancelPaymentPlanParams `json:"payment"`
+}
+
+type CancelPaymentPlanResponse struct {
+	Payment service.PaymentPlans `json:"payment"`
+}
+
 type ListPaymentPlanResponse struct {
 	Payment service.PaymentPlans `json:"payment"`
 }
 
-// createPendingPaymentPlanHandler creates a pending payment plan
+// createPendingPaymentPlanHandler creates a pending payment plan against the credit line of the user,
+// replaying a request with the same payment id returns the plan created by the first one
 // @Summary Creates a pending a payment plan
-// @Description pre creates a payment plan
+// @Description pre creates a payment plan, the installments are either listed or generated from a schedule
 // @Tags payment_plan
 // @Produce json
 // @Router /internal/v1/payment_plans [post]
 // @Param pre_create_payment_plan_request body CreatePendingPaymentPlanRequest true "Pre create payment plan reqBody"
 // @Success 200 {object} CreatePendingPaymentPlanResponse
-// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody"
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid amount, invalid schedule or invalid order"
+// @Failure 404 {object} handlerwrap.ErrorResponse "the user has no credit line"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan id or order reference already used"
+// @Failure 422 {object} handlerwrap.ErrorResponse "installments do not add up to a valid payment plan or exceed the available credit"
 // @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
 func createPendingPaymentPlanHandler(
 	paymentService service.PaymentPlanService,
@@ -76,8 +88,8 @@ func createPendingPaymentPlanHandler(
 // @Success 200 {object} CompletePaymentPlanResponse
 // @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody"
 // @Failure 401 {object} handlerwrap.ErrorResponse "payment plan not belongs to user"
-// @Failure 403 {object} handlerwrap.ErrorResponse "payment plan is not in pending"
 // @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is not pending"
 // @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
 func completePaymentPlanHandler(
 	paramsGetter handlerwrap.NamedURLParamsGetter,
@@ -94,7 +106,7 @@ func completePaymentPlanHandler(
 			return nil, errResp
 		}
 
-		paymentUUID, respErr = parseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		paymentUUID, respErr = rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
 		if respErr != nil {
 			return nil, respErr
 		}
@@ -110,3 +122,44 @@ func completePaymentPlanHandler(
 		}, nil
 	}
 }
+
+// cancelPaymentPlanHandler cancels a pending payment plan
+// @Summary Cancels a payment plan
+// @Description cancels a payment plan which is not completed yet, its installments are voided
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/cancel [post]
+// @Param cancel_payment_plan_request body CancelPaymentPlanRequest true "Cancel payment plan reqBody"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} CancelPaymentPlanResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is not pending"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func cancelPaymentPlanHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request CancelPaymentPlanRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		payment, err := paymentService.CancelPaymentPlan(req.Context(), *paymentUUID, &request.Payment)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       CancelPaymentPlanResponse{Payment: *payment},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
@@ -13,10 +13,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ericlagergren/decimal"
 	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
 
+	"golangreferenceapi/internal/payments"
 	"golangreferenceapi/internal/payments/common"
 	"golangreferenceapi/internal/payments/mock/servicemock"
 	"golangreferenceapi/internal/payments/service"
@@ -129,13 +131,11 @@ func Test_createPendingPaymentPlanHandler(t *testing.T) {
 			PendingPayment: service.CreatePaymentPlanParams{
 				ID:          planUUID,
 				UserID:      userUUID,
-				Currency:    "usdc",
-				TotalAmount: "100",
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
 				Installments: []service.PaymentPlanInstallmentParams{
 					{
-						Amount:   "100",
-						Currency: "usdc",
-						DueAt:    time.Now().UTC().Round(time.Second),
+						Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+						DueAt:  time.Now().UTC().Round(time.Second),
 					},
 				},
 			},
@@ -143,19 +143,16 @@ func Test_createPendingPaymentPlanHandler(t *testing.T) {
 		response = CreatePendingPaymentPlanResponse{
 			PendingPayment: service.PaymentPlans{
 				ID:          "03baa9e6-6ed6-4868-9ef9-b99c8452f270",
-				Currency:    "usdc",
-				TotalAmount: "200.0",
+				TotalAmount: payments.MustNewMoney(decimal.New(2000, 1), "usdc"),
 				Status:      service.PaymentInstallmentStatusPending,
 				Installments: []service.PaymentPlanInstallment{
 					{
-						DueAt:    "2022-06-01T14:02:03.000Z",
-						Amount:   "50",
-						Currency: "usdc",
+						DueAt:  "2022-06-01T14:02:03.000Z",
+						Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
 					},
 					{
-						DueAt:    "2022-06-01T14:02:03.000Z",
-						Amount:   "50",
-						Currency: "usdc",
+						DueAt:  "2022-06-01T14:02:03.000Z",
+						Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
 					},
 				},
 			},
@@ -203,10 +200,10 @@ func Test_createPendingPaymentPlanHandler(t *testing.T) {
 		)
 	}
 
-	if pendingPlanActual.PendingPayment.Currency != pendingPlanExpect.PendingPayment.Currency {
+	if !pendingPlanActual.PendingPayment.TotalAmount.Equal(pendingPlanExpect.PendingPayment.TotalAmount) {
 		t.Errorf("expect: %v, actual: %v",
-			pendingPlanExpect.PendingPayment.Currency,
-			pendingPlanActual.PendingPayment.Currency,
+			pendingPlanExpect.PendingPayment.TotalAmount,
+			pendingPlanActual.PendingPayment.TotalAmount,
 		)
 	}
 
@@ -230,14 +227,7 @@ func Test_createPendingPaymentPlanHandler(t *testing.T) {
 		actualInstallment := pendingPlanActual.PendingPayment.Installments[idx]
 		expectedInstallment := pendingPlanExpect.PendingPayment.Installments[idx]
 
-		if actualInstallment.Currency != expectedInstallment.Currency {
-			t.Errorf("expect: %v, actual: %v",
-				actualInstallment.Currency,
-				expectedInstallment.Currency,
-			)
-		}
-
-		if actualInstallment.Amount != expectedInstallment.Amount {
+		if !actualInstallment.Amount.Equal(expectedInstallment.Amount) {
 			t.Errorf("expect: %v, actual: %v",
 				actualInstallment.Amount,
 				expectedInstallment.Amount,
@@ -256,13 +246,11 @@ func Test_createPendingPaymentPlanHandlerServiceError(t *testing.T) {
 			PendingPayment: service.CreatePaymentPlanParams{
 				ID:          planUUID,
 				UserID:      userUUID,
-				Currency:    "usdc",
-				TotalAmount: "100",
+				TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
 				Installments: []service.PaymentPlanInstallmentParams{
 					{
-						Amount:   "100",
-						Currency: "usdc",
-						DueAt:    time.Now().UTC().Round(time.Second),
+						Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+						DueAt:  time.Now().UTC().Round(time.Second),
 					},
 				},
 			},
@@ -289,6 +277,12 @@ func Test_createPendingPaymentPlanHandlerServiceError(t *testing.T) {
 				err: service.CreatePaymentInstallmentError{},
 			},
 		},
+		{
+			name: "payment plan replayed with a different payload",
+			args: args{
+				err: service.PaymentPlanConflictError{},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -350,8 +344,7 @@ func Test_completePaymentPlanHandler(t *testing.T) {
 		paramsGetter  = rest.ChiNamedURLParamsGetter
 		payment       = service.PaymentPlans{
 			ID:           paymentPlanID.String(),
-			Currency:     "usdc",
-			TotalAmount:  "100",
+			TotalAmount:  payments.MustNewMoney(decimal.New(100, 0), "usdc"),
 			Status:       "pending",
 			CreatedAt:    time.Now().Format(common.TimeFormat),
 			Installments: nil,
@@ -505,8 +498,12 @@ func Test_completePaymentPlanHandlerServiceError(t *testing.T) {
 		args args
 	}{
 		{
-			name: "list payment plans by user error",
-			args: args{err: service.ListPaymentPlansByUserIDError{}},
+			name: "lock payment plan error",
+			args: args{err: service.LockPaymentPlanError{}},
+		},
+		{
+			name: "invalid state transition error",
+			args: args{err: service.InvalidStateTransitionError{}},
 		},
 		{
 			name: "list payment installment by plan error",
@@ -573,3 +570,129 @@ func Benchmark_completePaymentPlanHandler(b *testing.B) {
 		_, _ = h(req)
 	}
 }
+
+func Test_cancelPaymentPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userUUID      = uuid.Must(uuid.NewV4())
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		paramsGetter  = rest.ChiNamedURLParamsGetter
+		payment       = service.PaymentPlans{
+			ID:           paymentPlanID.String(),
+			UserID:       userUUID.String(),
+			TotalAmount:  payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			Status:       "cancelled",
+			CreatedAt:    time.Now().Format(common.TimeFormat),
+			Installments: nil,
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       CancelPaymentPlanResponse{Payment: payment},
+		}
+		request = CancelPaymentPlanRequest{
+			Payment: service.CancelPaymentPlanParams{
+				UserID: userUUID,
+			},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{
+		urlParamPaymentUUID: paymentPlanID.String(),
+	})
+
+	gomock.InOrder(
+		paymentService.EXPECT().CancelPaymentPlan(
+			gomock.Eq(req.Context()),
+			gomock.Eq(paymentPlanID),
+			gomock.Eq(&request.Payment),
+		).Return(&payment, nil),
+	)
+
+	resp, errRsp := cancelPaymentPlanHandler(paramsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected err. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_cancelPaymentPlanHandlerServiceError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		paramsGetter  = rest.ChiNamedURLParamsGetter
+		request       = CancelPaymentPlanRequest{
+			Payment: service.CancelPaymentPlanParams{
+				UserID: uuid.Must(uuid.NewV4()),
+			},
+		}
+	)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "payment plan not found error",
+			err:  service.PaymentRecordNotFoundError{},
+		},
+		{
+			name: "invalid state transition error",
+			err:  service.InvalidStateTransitionError{},
+		},
+		{
+			name: "lock payment plan error",
+			err:  service.LockPaymentPlanError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantResponse := rest.ServiceErrorToErrorResp(tt.err)
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().CancelPaymentPlan(
+				gomock.Any(),
+				gomock.Eq(paymentPlanID),
+				gomock.Eq(&request.Payment),
+			).Return(nil, tt.err)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+			setURLParams(req, map[string]string{
+				urlParamPaymentUUID: paymentPlanID.String(),
+			})
+
+			resp, errRsp := cancelPaymentPlanHandler(paramsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if !reflect.DeepEqual(wantResponse, errRsp) { // nolint: deepequalerrors // linter bug these are responses, not errors
+				t.Errorf("returned unexpected err. expected: %v, actual: %v", wantResponse, errRsp)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type QuotePaymentPlanPayoffResponse struct {
+	Payoff service.PaymentPlanPayoffQuote `json:"payoff"`
+}
+
+type PayOffPaymentPlanRequest struct {
+	Payoff service.PaymentPlanPayoffParams `json:"payoff"`
+}
+
+type PayOffPaymentPlanResponse struct {
+	Payoff service.PaymentPlanPayoff `json:"payoff"`
+}
+
+// quotePaymentPlanPayoffHandler renders the amount due now to pay off a payment plan
+// @Summary Quotes a payment plan payoff
+// @Description returns the outstanding amount of every unpaid installment of a payment plan, late fees included
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/payoff [get]
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Param user_id query string true "User UUID"
+// @Success 200 {object} QuotePaymentPlanPayoffResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid payment or user uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is closed or already paid"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func quotePaymentPlanPayoffHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		userUUID, respErr := parseUUIDFormatQuery(req.URL, queryParamUserID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		quote, err := paymentService.QuotePaymentPlanPayoff(req.Context(), *paymentUUID, *userUUID)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       QuotePaymentPlanPayoffResponse{Payoff: *quote},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// payOffPaymentPlanHandler settles every unpaid installment of a payment plan at once
+// @Summary Pays off a payment plan
+// @Description records one settlement of the quoted amount, every unpaid installment is marked paid
+// @Description and a pending payment plan is completed
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/payoff [post]
+// @Param pay_off_payment_plan_request body PayOffPaymentPlanRequest true "Pay off payment plan reqBody"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} PayOffPaymentPlanResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid amount or missing processor reference"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is closed or already paid"
+// @Failure 422 {object} handlerwrap.ErrorResponse "currency mismatch or amount does not match the outstanding amount"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func payOffPaymentPlanHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request PayOffPaymentPlanRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		payoff, err := paymentService.PayOffPaymentPlan(req.Context(), *paymentUUID, &request.Payoff)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       PayOffPaymentPlanResponse{Payoff: *payoff},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
@@ -0,0 +1,299 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_quotePaymentPlanPayoffHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		userID        = uuid.Must(uuid.NewV4())
+		quote         = service.PaymentPlanPayoffQuote{
+			PaymentPlanID: paymentPlanID.String(),
+			Amount:        payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+			Installments: []service.PaymentPlanInstallment{
+				{
+					ID:     uuid.Must(uuid.NewV4()).String(),
+					Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+					DueAt:  time.Now().UTC().Format(common.TimeFormat),
+					Status: "overdue",
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       QuotePaymentPlanPayoffResponse{Payoff: quote},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	req := httptest.NewRequest("GET", "/?user_id="+userID.String(), nil)
+
+	setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+	paymentService.EXPECT().QuotePaymentPlanPayoff(
+		gomock.Eq(req.Context()),
+		gomock.Eq(paymentPlanID),
+		gomock.Eq(userID),
+	).Return(&quote, nil)
+
+	resp, errRsp := quotePaymentPlanPayoffHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_quotePaymentPlanPayoffHandlerParamsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		query       string
+		paymentUUID string
+	}{
+		{
+			name:        "returns 400 if passing a invalid payment uuid",
+			query:       "?user_id=" + uuid.Must(uuid.NewV4()).String(),
+			paymentUUID: "x",
+		},
+		{
+			name:        "returns 400 if passing a invalid user uuid",
+			query:       "?user_id=x",
+			paymentUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+		{
+			name:        "returns 400 if missing the user uuid",
+			paymentUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+	}
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("GET", "/"+tt.query, nil)
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := quotePaymentPlanPayoffHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != http.StatusBadRequest {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func Test_payOffPaymentPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		paidAt        = time.Now().UTC().Truncate(time.Second)
+		payoff        = service.PaymentPlanPayoff{
+			ID:                 uuid.Must(uuid.NewV4()).String(),
+			PaymentPlanID:      paymentPlanID.String(),
+			Amount:             payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt.Format(common.TimeFormat),
+			Status:             "complete",
+			Installments: []service.PaymentPlanInstallment{
+				{
+					ID:     uuid.Must(uuid.NewV4()).String(),
+					Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+					DueAt:  paidAt.Format(common.TimeFormat),
+					Status: "paid",
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       PayOffPaymentPlanResponse{Payoff: payoff},
+		}
+		request = PayOffPaymentPlanRequest{
+			Payoff: service.PaymentPlanPayoffParams{
+				UserID:             uuid.Must(uuid.NewV4()),
+				Amount:             payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             paidAt,
+			},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+	paymentService.EXPECT().PayOffPaymentPlan(
+		gomock.Eq(req.Context()),
+		gomock.Eq(paymentPlanID),
+		gomock.Eq(&request.Payoff),
+	).Return(&payoff, nil)
+
+	resp, errRsp := payOffPaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_payOffPaymentPlanHandlerParamsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		reqBody     string
+		paymentUUID string
+	}{
+		{
+			name:        "returns 400 if passing a broken reqBody",
+			reqBody:     `{"payoff":`,
+			paymentUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+		{
+			name:        "returns 400 if passing a invalid payment uuid",
+			reqBody:     `{"payoff":{}}`,
+			paymentUUID: "x",
+		},
+	}
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(tt.reqBody)))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := payOffPaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != http.StatusBadRequest {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func Test_payOffPaymentPlanHandlerServiceError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		request       = PayOffPaymentPlanRequest{
+			Payoff: service.PaymentPlanPayoffParams{
+				UserID:             uuid.Must(uuid.NewV4()),
+				Amount:             payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+				ProcessorReference: "psp-ref-1",
+			},
+		}
+	)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "payment record not found error",
+			err:  service.PaymentRecordNotFoundError{},
+		},
+		{
+			name: "payment plan not payable error",
+			err:  service.PaymentPlanNotPayableError{},
+		},
+		{
+			name: "payment plan already paid error",
+			err:  service.PaymentPlanAlreadyPaidError{},
+		},
+		{
+			name: "payoff amount mismatch error",
+			err:  service.PayoffAmountMismatchError{},
+		},
+		{
+			name: "create payment settlement error",
+			err:  service.CreatePaymentSettlementError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantResponse := rest.ServiceErrorToErrorResp(tt.err)
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().PayOffPaymentPlan(
+				gomock.Any(),
+				gomock.Eq(paymentPlanID),
+				gomock.Eq(&request.Payoff),
+			).Return(nil, tt.err)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+			resp, errRsp := payOffPaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if !reflect.DeepEqual(wantResponse, errRsp) { // nolint: deepequalerrors // linter bug these are responses, not errors
+				t.Errorf("returned unexpected err. expected: %v, actual: %v", wantResponse, errRsp)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type RefundPaymentPlanRequest struct {
+	Refund service.RefundPaymentPlanParams `json:"refund"`
+}
+
+type RefundPaymentPlanResponse struct {
+	Refund service.PaymentPlanRefund `json:"refund"`
+}
+
+// refundPaymentPlanHandler refunds a completed payment plan
+// @Summary Refunds a payment plan
+// @Description refunds a completed payment plan fully when no amount is given or partially otherwise,
+// @Description a partial refund lowers what is left to pay, the latest installments first, and gives back
+// @Description what was paid for the rest. A full refund voids the unpaid installments.
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/refunds [post]
+// @Param refund_payment_plan_request body RefundPaymentPlanRequest true "Refund payment plan reqBody"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} RefundPaymentPlanResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or invalid amount"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is not complete"
+// @Failure 422 {object} handlerwrap.ErrorResponse "currency mismatch or amount exceeds the refundable amount"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func refundPaymentPlanHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request RefundPaymentPlanRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		refund, err := paymentService.RefundPaymentPlan(req.Context(), *paymentUUID, &request.Refund)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       RefundPaymentPlanResponse{Refund: *refund},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
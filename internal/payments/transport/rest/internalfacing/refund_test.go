@@ -0,0 +1,211 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_refundPaymentPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		installmentID = uuid.Must(uuid.NewV4())
+		refundedAt    = time.Now().UTC().Truncate(time.Second)
+		paramsGetter  = rest.ChiNamedURLParamsGetter
+		refundAmount  = payments.MustNewMoney(decimal.New(40, 0), "usdc")
+		refund        = service.PaymentPlanRefund{
+			PaymentPlanID: paymentPlanID.String(),
+			Status:        "refunded",
+			Amount:        payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+			Reason:        "purchase returned",
+			Refunds: []service.PaymentInstallmentRefund{
+				{
+					ID:            uuid.Must(uuid.NewV4()).String(),
+					InstallmentID: installmentID.String(),
+					Amount:        payments.MustNewMoney(decimal.New(40, 0), "usdc"),
+					RefundedAt:    refundedAt.Format(common.TimeFormat),
+				},
+			},
+			Installments: []service.PaymentPlanInstallment{
+				{
+					ID:     installmentID.String(),
+					Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+					DueAt:  refundedAt.Format(common.TimeFormat),
+					Status: "paid",
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       RefundPaymentPlanResponse{Refund: refund},
+		}
+		request = RefundPaymentPlanRequest{
+			Refund: service.RefundPaymentPlanParams{
+				UserID: uuid.Must(uuid.NewV4()),
+				Amount: &refundAmount,
+				Reason: "purchase returned",
+			},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+	gomock.InOrder(
+		paymentService.EXPECT().RefundPaymentPlan(
+			gomock.Eq(req.Context()),
+			gomock.Eq(paymentPlanID),
+			gomock.Eq(&request.Refund),
+		).Return(&refund, nil),
+	)
+
+	resp, errRsp := refundPaymentPlanHandler(paramsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_refundPaymentPlanHandlerParamsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		reqBody     string
+		paymentUUID string
+	}{
+		{
+			name:        "returns 400 if passing a broken reqBody",
+			reqBody:     `{"refund":`,
+			paymentUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+		{
+			name:        "returns 400 if passing a invalid payment uuid",
+			reqBody:     `{"refund":{}}`,
+			paymentUUID: "x",
+		},
+	}
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(tt.reqBody)))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := refundPaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != http.StatusBadRequest {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func Test_refundPaymentPlanHandlerServiceError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		refundAmount  = payments.MustNewMoney(decimal.New(40, 0), "usdc")
+		request       = RefundPaymentPlanRequest{
+			Refund: service.RefundPaymentPlanParams{
+				UserID: uuid.Must(uuid.NewV4()),
+				Amount: &refundAmount,
+			},
+		}
+	)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "payment record not found error",
+			err:  service.PaymentRecordNotFoundError{},
+		},
+		{
+			name: "invalid state transition error",
+			err:  service.InvalidStateTransitionError{},
+		},
+		{
+			name: "refund exceeds paid amount error",
+			err:  service.RefundExceedsPaidAmountError{},
+		},
+		{
+			name: "create payment refund error",
+			err:  service.CreatePaymentRefundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantResponse := rest.ServiceErrorToErrorResp(tt.err)
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().RefundPaymentPlan(
+				gomock.Any(),
+				gomock.Eq(paymentPlanID),
+				gomock.Eq(&request.Refund),
+			).Return(nil, tt.err)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+			resp, errRsp := refundPaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if !reflect.DeepEqual(wantResponse, errRsp) { // nolint: deepequalerrors // linter bug these are responses, not errors
+				t.Errorf("returned unexpected err. expected: %v, actual: %v", wantResponse, errRsp)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package internalfacing
+
+import (
+	"net/http"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type ReschedulePaymentPlanRequest struct {
+	Reschedule service.ReschedulePaymentPlanParams `json:"reschedule"`
+}
+
+type ReschedulePaymentPlanResponse struct {
+	Payment service.PaymentPlans `json:"payment"`
+}
+
+// reschedulePaymentPlanHandler restructures the unpaid installments of a payment plan
+// @Summary Reschedules a payment plan
+// @Description replaces the unpaid installments of a completed payment plan with new ones covering
+// @Description the outstanding amount, late fees included. The installments can be listed or generated from a schedule,
+// @Description the replaced installments are kept as superseded history
+// @Tags payment_plan
+// @Produce json
+// @Router /internal/v1/payment-plans/{payment_uuid}/reschedule [post]
+// @Param reschedule_payment_plan_request body ReschedulePaymentPlanRequest true "Reschedule payment plan reqBody"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} ReschedulePaymentPlanResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid installments or schedule"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is not complete or already paid"
+// @Failure 422 {object} handlerwrap.ErrorResponse "installments do not add up to the outstanding amount"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func reschedulePaymentPlanHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request ReschedulePaymentPlanRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		paymentPlan, err := paymentService.ReschedulePaymentPlan(req.Context(), *paymentUUID, &request.Reschedule)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       ReschedulePaymentPlanResponse{Payment: *paymentPlan},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
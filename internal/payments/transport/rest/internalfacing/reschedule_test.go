@@ -0,0 +1,217 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_reschedulePaymentPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		userID        = uuid.Must(uuid.NewV4())
+		dueAt         = time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+		paymentPlan   = service.PaymentPlans{
+			ID:          paymentPlanID.String(),
+			UserID:      userID.String(),
+			TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			Status:      "complete",
+			Installments: []service.PaymentPlanInstallment{
+				{
+					ID:      uuid.Must(uuid.NewV4()).String(),
+					Amount:  payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+					DueAt:   dueAt.Format(common.TimeFormat),
+					Status:  "pending",
+					Version: 2,
+				},
+			},
+			History: []service.PaymentPlanInstallment{
+				{
+					ID:      uuid.Must(uuid.NewV4()).String(),
+					Amount:  payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+					DueAt:   dueAt.Format(common.TimeFormat),
+					Status:  "superseded",
+					Version: 1,
+				},
+			},
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       ReschedulePaymentPlanResponse{Payment: paymentPlan},
+		}
+		request = ReschedulePaymentPlanRequest{
+			Reschedule: service.ReschedulePaymentPlanParams{
+				UserID: userID,
+				Installments: []service.PaymentPlanInstallmentParams{
+					{Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"), DueAt: dueAt},
+				},
+			},
+		}
+	)
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+	paymentService.EXPECT().ReschedulePaymentPlan(
+		gomock.Eq(req.Context()),
+		gomock.Eq(paymentPlanID),
+		gomock.Eq(&request.Reschedule),
+	).Return(&paymentPlan, nil)
+
+	resp, errRsp := reschedulePaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_reschedulePaymentPlanHandlerParamsError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		reqBody     string
+		paymentUUID string
+	}{
+		{
+			name:        "returns 400 if passing a broken reqBody",
+			reqBody:     `{"reschedule":`,
+			paymentUUID: uuid.Must(uuid.NewV4()).String(),
+		},
+		{
+			name:        "returns 400 if passing a invalid payment uuid",
+			reqBody:     `{"reschedule":{}}`,
+			paymentUUID: "x",
+		},
+	}
+
+	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte(tt.reqBody)))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := reschedulePaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if errRsp == nil || errRsp.StatusCode != http.StatusBadRequest {
+				t.Errorf("returned unexpected error response: got %v want status %v", errRsp, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func Test_reschedulePaymentPlanHandlerServiceError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		request       = ReschedulePaymentPlanRequest{
+			Reschedule: service.ReschedulePaymentPlanParams{
+				UserID: uuid.Must(uuid.NewV4()),
+				Schedule: &service.PaymentPlanScheduleParams{
+					Count:     3,
+					Frequency: "monthly",
+					StartAt:   time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second),
+				},
+			},
+		}
+	)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "payment record not found error",
+			err:  service.PaymentRecordNotFoundError{},
+		},
+		{
+			name: "payment plan not reschedulable error",
+			err:  service.PaymentPlanNotReschedulableError{},
+		},
+		{
+			name: "payment plan already paid error",
+			err:  service.PaymentPlanAlreadyPaidError{},
+		},
+		{
+			name: "invalid schedule error",
+			err:  service.InvalidScheduleError{},
+		},
+		{
+			name: "installment sum mismatch error",
+			err:  service.InstallmentSumMismatchError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wantResponse := rest.ServiceErrorToErrorResp(tt.err)
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+			paymentService.EXPECT().ReschedulePaymentPlan(
+				gomock.Any(),
+				gomock.Eq(paymentPlanID),
+				gomock.Eq(&request.Reschedule),
+			).Return(nil, tt.err)
+
+			reqBody, err := json.Marshal(request)
+			if err != nil {
+				t.Errorf("failed to unmarshal json")
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: paymentPlanID.String()})
+
+			resp, errRsp := reschedulePaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if resp != nil {
+				t.Errorf("returned unexpected response: %v", resp)
+			}
+
+			if !reflect.DeepEqual(wantResponse, errRsp) { // nolint: deepequalerrors // linter bug these are responses, not errors
+				t.Errorf("returned unexpected err. expected: %v, actual: %v", wantResponse, errRsp)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package internalfacing
 
 import (
 	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
@@ -12,12 +13,69 @@ func AddRoutes(
 	router chi.Router, log *zerolog.Logger,
 	paramsGetter handlerwrap.NamedURLParamsGetter,
 	paymentService service.PaymentPlanService,
+	creditLineService service.CreditLineService,
+	webhookService service.WebhookService,
+	collectionsService service.CollectionsService,
+	merchantService service.MerchantService,
 	version string,
 ) {
 	router.Route("/internal/"+version, func(rtr chi.Router) {
+		rtr.Use(rest.InternalAuditActor)
 		rtr.Post("/payment-plans",
 			handlerwrap.Wrapper(log, createPendingPaymentPlanHandler(paymentService)))
 		rtr.Post("/payment-plans/{payment_uuid}/complete",
 			handlerwrap.Wrapper(log, completePaymentPlanHandler(paramsGetter, paymentService)))
+		rtr.Post("/payment-plans/{payment_uuid}/cancel",
+			handlerwrap.Wrapper(log, cancelPaymentPlanHandler(paramsGetter, paymentService)))
+		rtr.Post("/payment-plans/{payment_uuid}/refunds",
+			handlerwrap.Wrapper(log, refundPaymentPlanHandler(paramsGetter, paymentService)))
+		rtr.Get("/payment-plans/{payment_uuid}/payoff",
+			handlerwrap.Wrapper(log, quotePaymentPlanPayoffHandler(paramsGetter, paymentService)))
+		rtr.Post("/payment-plans/{payment_uuid}/payoff",
+			handlerwrap.Wrapper(log, payOffPaymentPlanHandler(paramsGetter, paymentService)))
+		rtr.Get("/payment-plans/{payment_uuid}/ledger",
+			handlerwrap.Wrapper(log, getPaymentPlanLedgerHandler(paramsGetter, paymentService)))
+		rtr.Get("/payment-plans/{payment_uuid}/audit",
+			handlerwrap.Wrapper(log, getPaymentPlanAuditHandler(paramsGetter, paymentService)))
+		rtr.Post("/payment-plans/{payment_uuid}/reschedule",
+			handlerwrap.Wrapper(log, reschedulePaymentPlanHandler(paramsGetter, paymentService)))
+		rtr.Post("/payment-plans/{payment_uuid}/installments/{installment_uuid}/payments",
+			handlerwrap.Wrapper(log, recordInstallmentPaymentHandler(paramsGetter, paymentService)))
+		rtr.Post("/credit-lines/{user_uuid}",
+			handlerwrap.Wrapper(log, createCreditLineHandler(paramsGetter, creditLineService)))
+		rtr.Post("/credit-lines/{user_uuid}/limit",
+			handlerwrap.Wrapper(log, updateCreditLineLimitHandler(paramsGetter, creditLineService)))
+		rtr.Post("/credit-lines/{user_uuid}/freeze",
+			handlerwrap.Wrapper(log, freezeCreditLineHandler(paramsGetter, creditLineService)))
+		rtr.Post("/credit-lines/{user_uuid}/unfreeze",
+			handlerwrap.Wrapper(log, unfreezeCreditLineHandler(paramsGetter, creditLineService)))
+		rtr.Post("/credit-lines/{user_uuid}/close",
+			handlerwrap.Wrapper(log, closeCreditLineHandler(paramsGetter, creditLineService)))
+		rtr.Get("/credit-lines/{user_uuid}/changes",
+			handlerwrap.Wrapper(log, listCreditLineChangesHandler(paramsGetter, creditLineService)))
+		rtr.Post("/merchants",
+			handlerwrap.Wrapper(log, createMerchantHandler(merchantService)))
+		rtr.Get("/merchants/{merchant_uuid}/payment-plans",
+			handlerwrap.Wrapper(log, listMerchantPaymentPlansHandler(paramsGetter, merchantService)))
+		rtr.Get("/merchants/{merchant_uuid}/orders/{order_reference}/payment-plan",
+			handlerwrap.Wrapper(log, getMerchantOrderPaymentPlanHandler(paramsGetter, merchantService)))
+		rtr.Get("/merchants/{merchant_uuid}/totals",
+			handlerwrap.Wrapper(log, getMerchantTotalsHandler(paramsGetter, merchantService)))
+		rtr.Post("/merchants/{merchant_uuid}/webhook-endpoints",
+			handlerwrap.Wrapper(log, registerWebhookEndpointHandler(paramsGetter, webhookService)))
+		rtr.Get("/webhook-deliveries",
+			handlerwrap.Wrapper(log, listWebhookDeliveriesHandler(webhookService)))
+		rtr.Post("/webhook-deliveries/{delivery_uuid}/replay",
+			handlerwrap.Wrapper(log, replayWebhookDeliveryHandler(paramsGetter, webhookService)))
+		rtr.Get("/collections-cases",
+			handlerwrap.Wrapper(log, listCollectionsCasesHandler(collectionsService)))
+		rtr.Get("/collections-cases/{case_uuid}",
+			handlerwrap.Wrapper(log, getCollectionsCaseHandler(paramsGetter, collectionsService)))
+		rtr.Post("/collections-cases/{case_uuid}/pause",
+			handlerwrap.Wrapper(log, pauseCollectionsCaseHandler(paramsGetter, collectionsService)))
+		rtr.Post("/collections-cases/{case_uuid}/resume",
+			handlerwrap.Wrapper(log, resumeCollectionsCaseHandler(paramsGetter, collectionsService)))
+		rtr.Post("/collections-cases/{case_uuid}/close",
+			handlerwrap.Wrapper(log, closeCollectionsCaseHandler(paramsGetter, collectionsService)))
 	})
 }
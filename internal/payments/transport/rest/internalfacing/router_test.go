@@ -1,15 +1,18 @@
 package internalfacing
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
 
+	"golangreferenceapi/internal/payments/audit"
 	"golangreferenceapi/internal/payments/mock/servicemock"
 	"golangreferenceapi/internal/payments/service"
 	"golangreferenceapi/internal/payments/transport/rest"
@@ -35,11 +38,11 @@ func TestAddRoutes(t *testing.T) {
 					"payment": {
 						"id": "03baa9e6-6ed6-4868-9ef9-b99c8452f270",
 						"user_id": "03baa9e6-6ed6-4868-9ef9-b99c8452f270",
-						"currency": "usdc",
-						"total_amount": "100.0",
+						"merchant_id": "03baa9e6-6ed6-4868-9ef9-b99c8452f270",
+						"total_amount": {"value": "100.0", "currency": "usdc"},
 						"installments": [
-							{ "due_at": "2022-06-01T14:02:03.000Z", "amount": "50", "currency": "usdc"},
-							{ "due_at": "2022-06-15T14:02:03.000Z", "amount": "50", "currency": "usdc"}
+							{ "due_at": "2022-06-01T14:02:03.000Z", "amount": {"value": "50", "currency": "usdc"}},
+							{ "due_at": "2022-06-15T14:02:03.000Z", "amount": {"value": "50", "currency": "usdc"}}
 						]
 				  }
 				}`,
@@ -56,6 +59,202 @@ func TestAddRoutes(t *testing.T) {
 					}`,
 			expectedHTTPStatusCode: http.StatusOK,
 		},
+		{
+			name:       "happy path for recording an installment payment",
+			httpMethod: "POST",
+			urlPath: "/internal/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270" +
+				"/installments/13baa9e6-6ed6-4868-9ef9-b99c8452f270/payments",
+			reqBody: `{
+						"payment": {
+							"amount": {"value": "50", "currency": "usdc"},
+							"processor_reference": "psp-ref-1"
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for cancelling payment plan",
+			httpMethod: "POST",
+			urlPath:    "/internal/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/cancel",
+			reqBody: `{
+						"payment": {
+							"user_id": "03baa9e6-6ed6-4868-9ef9-b99c8452f270"
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for refunding payment plan",
+			httpMethod: "POST",
+			urlPath:    "/internal/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/refunds",
+			reqBody: `{
+						"refund": {
+							"user_id": "03baa9e6-6ed6-4868-9ef9-b99c8452f270",
+							"amount": {"value": "50", "currency": "usdc"},
+							"reason": "purchase returned"
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for rescheduling payment plan",
+			httpMethod: "POST",
+			urlPath:    "/internal/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/reschedule",
+			reqBody: `{
+						"reschedule": {
+							"user_id": "03baa9e6-6ed6-4868-9ef9-b99c8452f270",
+							"schedule": {
+								"count": 3,
+								"frequency": "monthly",
+								"start_at": "2022-06-01T14:02:03.000Z"
+							}
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for getting the ledger of a payment plan",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/ledger",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for getting the audit trail of a payment plan",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/audit",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for creating a credit line",
+			httpMethod: "POST",
+			urlPath:    "/internal/v1/credit-lines/03baa9e6-6ed6-4868-9ef9-b99c8452f270",
+			reqBody: `{
+						"credit_line": {
+							"limit": {"value": "1000", "currency": "usdc"},
+							"actor": "risk-ops",
+							"reason": "onboarding"
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for updating a credit line limit",
+			httpMethod: "POST",
+			urlPath:    "/internal/v1/credit-lines/03baa9e6-6ed6-4868-9ef9-b99c8452f270/limit",
+			reqBody: `{
+						"credit_line": {
+							"limit": {"value": "500", "currency": "usdc"},
+							"actor": "risk-ops",
+							"reason": "missed payments"
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for freezing a credit line",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/credit-lines/03baa9e6-6ed6-4868-9ef9-b99c8452f270/freeze",
+			reqBody:                `{"change": {"actor": "risk-ops", "reason": "fraud review"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for unfreezing a credit line",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/credit-lines/03baa9e6-6ed6-4868-9ef9-b99c8452f270/unfreeze",
+			reqBody:                `{"change": {"actor": "risk-ops", "reason": "review cleared"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for closing a credit line",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/credit-lines/03baa9e6-6ed6-4868-9ef9-b99c8452f270/close",
+			reqBody:                `{"change": {"actor": "risk-ops", "reason": "user request"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for listing the changes of a credit line",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/credit-lines/03baa9e6-6ed6-4868-9ef9-b99c8452f270/changes",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for creating a merchant",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/merchants",
+			reqBody:                `{"merchant": {"name": "Acme"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for listing the payment plans of a merchant",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/merchants/03baa9e6-6ed6-4868-9ef9-b99c8452f270/payment-plans?limit=10",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for getting the payment plan of a merchant order",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/merchants/03baa9e6-6ed6-4868-9ef9-b99c8452f270/orders/order-42/payment-plan",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for getting the totals of a merchant",
+			httpMethod: "GET",
+			urlPath: "/internal/v1/merchants/03baa9e6-6ed6-4868-9ef9-b99c8452f270/totals" +
+				"?from=2022-10-01T00:00:00Z&to=2022-11-01T00:00:00Z",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for registering a webhook endpoint",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/merchants/03baa9e6-6ed6-4868-9ef9-b99c8452f270/webhook-endpoints",
+			reqBody:                `{"endpoint": {"url": "https://merchant.example.com/webhooks"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for listing the dead webhook deliveries",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/webhook-deliveries?status=dead",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for replaying a webhook delivery",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/webhook-deliveries/03baa9e6-6ed6-4868-9ef9-b99c8452f270/replay",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for listing the paused collections cases",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/collections-cases?status=paused",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for getting a collections case",
+			httpMethod:             "GET",
+			urlPath:                "/internal/v1/collections-cases/03baa9e6-6ed6-4868-9ef9-b99c8452f270",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for pausing a collections case",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/collections-cases/03baa9e6-6ed6-4868-9ef9-b99c8452f270/pause",
+			reqBody:                `{"change": {"actor": "agent-7", "reason": "payment promised"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for resuming a collections case",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/collections-cases/03baa9e6-6ed6-4868-9ef9-b99c8452f270/resume",
+			reqBody:                `{"change": {"actor": "agent-7", "reason": "promise broken"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:                   "happy path for closing a collections case",
+			httpMethod:             "POST",
+			urlPath:                "/internal/v1/collections-cases/03baa9e6-6ed6-4868-9ef9-b99c8452f270/close",
+			reqBody:                `{"change": {"actor": "agent-7", "reason": "written off"}}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
 	}
 
 	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
@@ -67,12 +266,122 @@ func TestAddRoutes(t *testing.T) {
 		CompletePaymentPlanCreation(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(&service.PaymentPlans{}, nil)
 
+	paymentService.EXPECT().
+		RecordInstallmentPayment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.InstallmentPayment{}, nil)
+
+	// the changes made through the internal API are attributed to its caller in the audit trail
+	paymentService.EXPECT().
+		CancelPaymentPlan(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ uuid.UUID, _ *service.CancelPaymentPlanParams) (*service.PaymentPlans, error) {
+			if actor := audit.ActorFromContext(ctx); actor != audit.InternalActor("internal") {
+				t.Errorf("unexpected audit actor: %v", actor)
+			}
+
+			return &service.PaymentPlans{}, nil
+		})
+
+	paymentService.EXPECT().
+		RefundPaymentPlan(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.PaymentPlanRefund{}, nil)
+
+	paymentService.EXPECT().
+		ReschedulePaymentPlan(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.PaymentPlans{}, nil)
+
+	paymentService.EXPECT().
+		GetPaymentPlanLedger(gomock.Any(), gomock.Any()).
+		Return(&service.PaymentPlanLedger{}, nil)
+
+	paymentService.EXPECT().
+		GetPaymentPlanAudit(gomock.Any(), gomock.Any()).
+		Return(&service.PaymentPlanAudit{}, nil)
+
+	creditLineService := servicemock.NewMockCreditLineService(gomock.NewController(t))
+	creditLineService.EXPECT().
+		CreateCreditLine(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CreditLine{}, nil)
+
+	creditLineService.EXPECT().
+		UpdateCreditLineLimit(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CreditLine{}, nil)
+
+	creditLineService.EXPECT().
+		FreezeCreditLine(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CreditLine{}, nil)
+
+	creditLineService.EXPECT().
+		UnfreezeCreditLine(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CreditLine{}, nil)
+
+	creditLineService.EXPECT().
+		CloseCreditLine(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CreditLine{}, nil)
+
+	creditLineService.EXPECT().
+		ListCreditLineChanges(gomock.Any(), gomock.Any()).
+		Return([]service.CreditLineChange{}, nil)
+
+	webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+	webhookService.EXPECT().
+		RegisterWebhookEndpoint(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.WebhookEndpoint{}, nil)
+
+	webhookService.EXPECT().
+		ListWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Return([]service.WebhookDelivery{}, nil)
+
+	webhookService.EXPECT().
+		ReplayWebhookDelivery(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.WebhookDelivery{}, nil)
+
+	collectionsService := servicemock.NewMockCollectionsService(gomock.NewController(t))
+	collectionsService.EXPECT().
+		ListCollectionsCases(gomock.Any(), gomock.Any()).
+		Return([]service.CollectionsCase{}, nil)
+
+	collectionsService.EXPECT().
+		GetCollectionsCase(gomock.Any(), gomock.Any()).
+		Return(&service.CollectionsCase{}, nil)
+
+	collectionsService.EXPECT().
+		PauseCollectionsCase(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CollectionsCase{}, nil)
+
+	collectionsService.EXPECT().
+		ResumeCollectionsCase(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CollectionsCase{}, nil)
+
+	collectionsService.EXPECT().
+		CloseCollectionsCase(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.CollectionsCase{}, nil)
+
+	merchantService := servicemock.NewMockMerchantService(gomock.NewController(t))
+	merchantService.EXPECT().
+		CreateMerchant(gomock.Any(), gomock.Any()).
+		Return(&service.Merchant{}, nil)
+
+	merchantService.EXPECT().
+		ListMerchantPaymentPlans(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]service.PaymentPlans{}, nil)
+
+	merchantService.EXPECT().
+		GetPaymentPlanByOrderReference(gomock.Any(), gomock.Any(), gomock.Eq("order-42")).
+		Return(&service.PaymentPlans{}, nil)
+
+	merchantService.EXPECT().
+		GetMerchantTotals(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.MerchantTotals{}, nil)
+
 	for _, tt := range tests { //nolint: paralleltest // the integration test have strict order
 		tt := tt
 
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
-			AddRoutes(r, &log, rest.ChiNamedURLParamsGetter, paymentService, "v1")
+			AddRoutes(
+				r, &log, rest.ChiNamedURLParamsGetter,
+				paymentService, creditLineService, webhookService, collectionsService, merchantService, "v1",
+			)
 
 			srv := httptest.NewServer(r)
 			defer srv.Close()
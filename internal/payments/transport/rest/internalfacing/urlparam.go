@@ -1,14 +1,25 @@
 package internalfacing
 
 import (
-	"context"
+	"net/url"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
 )
 
 const (
-	urlParamPaymentUUID = "payment_uuid"
+	urlParamPaymentUUID     = "payment_uuid"
+	urlParamInstallmentUUID = "installment_uuid"
+	urlParamUserUUID        = "user_uuid"
+	urlParamMerchantUUID    = "merchant_uuid"
+	urlParamDeliveryUUID    = "delivery_uuid"
+	urlParamCaseUUID        = "case_uuid"
+	urlParamOrderReference  = "order_reference"
+	queryParamUserID        = "user_id"
+	queryParamStatus        = "status"
+	queryParamFrom          = "from"
+	queryParamTo            = "to"
 )
 
 type PaymentPlanParam struct {
@@ -16,16 +27,14 @@ type PaymentPlanParam struct {
 	UserUUID *uuid.UUID `json:"user_uuid"`
 }
 
-func parseUUIDFormatParam(
-	ctx context.Context, paramsGetter handlerwrap.NamedURLParamsGetter, name string,
-) (*uuid.UUID, *handlerwrap.ErrorResponse) {
-	val, err := paramsGetter(ctx, name)
-	if err != nil {
-		return nil, err
+func parseUUIDFormatQuery(u *url.URL, name string) (*uuid.UUID, *handlerwrap.ErrorResponse) {
+	val := u.Query().Get(name)
+	if val == "" {
+		return nil, handlerwrap.MissingParamError{Name: name}.ToErrorResponse()
 	}
 
-	uuidVal, parseErr := uuid.FromString(val)
-	if parseErr != nil {
+	uuidVal, err := uuid.FromString(val)
+	if err != nil {
 		return nil, handlerwrap.ParsingParamError{
 			Name:  name,
 			Value: val,
@@ -34,3 +43,20 @@ func parseUUIDFormatParam(
 
 	return &uuidVal, nil
 }
+
+func parseTimeFormatQuery(u *url.URL, name string) (time.Time, *handlerwrap.ErrorResponse) {
+	val := u.Query().Get(name)
+	if val == "" {
+		return time.Time{}, handlerwrap.MissingParamError{Name: name}.ToErrorResponse()
+	}
+
+	timeVal, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, handlerwrap.ParsingParamError{
+			Name:  name,
+			Value: val,
+		}.ToErrorResponse()
+	}
+
+	return timeVal, nil
+}
@@ -0,0 +1,131 @@
+package internalfacing
+
+import (
+	"net/http"
+	"time"
+
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+	"golangreferenceapi/internal/payments/webhook"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+type RegisterWebhookEndpointRequest struct {
+	Endpoint service.RegisterWebhookEndpointParams `json:"endpoint"`
+}
+
+type WebhookEndpointResponse struct {
+	Endpoint service.WebhookEndpoint `json:"endpoint"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []service.WebhookDelivery `json:"deliveries"`
+}
+
+type WebhookDeliveryResponse struct {
+	Delivery service.WebhookDelivery `json:"delivery"`
+}
+
+// registerWebhookEndpointHandler adds a webhook endpoint to a merchant
+// @Summary Registers a webhook endpoint
+// @Description the events of the plans of the merchant are posted to the url, signed with the returned secret
+// @Tags webhook
+// @Produce json
+// @Router /internal/v1/merchants/{merchant_uuid}/webhook-endpoints [post]
+// @Param register_webhook_endpoint_request body RegisterWebhookEndpointRequest true "Register webhook endpoint reqBody"
+// @Param merchant_uuid path string true "Merchant UUID"
+// @Success 200 {object} WebhookEndpointResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody or invalid url"
+// @Failure 404 {object} handlerwrap.ErrorResponse "merchant not found"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func registerWebhookEndpointHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	webhookService service.WebhookService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request RegisterWebhookEndpointRequest
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		merchantUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamMerchantUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		endpoint, err := webhookService.RegisterWebhookEndpoint(req.Context(), *merchantUUID, &request.Endpoint)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       WebhookEndpointResponse{Endpoint: *endpoint},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// listWebhookDeliveriesHandler lists the webhook deliveries in a status
+// @Summary Lists webhook deliveries
+// @Description the deliveries in status, dead by default, the latest first
+// @Tags webhook
+// @Produce json
+// @Router /internal/v1/webhook-deliveries [get]
+// @Param status query string false "pending, delivered or dead"
+// @Success 200 {object} ListWebhookDeliveriesResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid status"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func listWebhookDeliveriesHandler(webhookService service.WebhookService) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		status := req.URL.Query().Get(queryParamStatus)
+		if status == "" {
+			status = webhook.DeliveryStatusDead
+		}
+
+		deliveries, err := webhookService.ListWebhookDeliveries(req.Context(), status)
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       ListWebhookDeliveriesResponse{Deliveries: deliveries},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// replayWebhookDeliveryHandler sends a dead webhook delivery again
+// @Summary Replays a webhook delivery
+// @Description the dead delivery is pending again with all of its attempts, it is sent by the next dispatch
+// @Tags webhook
+// @Produce json
+// @Router /internal/v1/webhook-deliveries/{delivery_uuid}/replay [post]
+// @Param delivery_uuid path string true "Delivery UUID"
+// @Success 200 {object} WebhookDeliveryResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid delivery uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "webhook delivery not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "webhook delivery is not dead"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func replayWebhookDeliveryHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	webhookService service.WebhookService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		deliveryUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamDeliveryUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		delivery, err := webhookService.ReplayWebhookDelivery(req.Context(), *deliveryUUID, time.Now().UTC())
+		if err != nil {
+			return nil, rest.ServiceErrorToErrorResp(err)
+		}
+
+		return &handlerwrap.Response{
+			Body:       WebhookDeliveryResponse{Delivery: *delivery},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
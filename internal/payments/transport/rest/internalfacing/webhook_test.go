@@ -0,0 +1,191 @@
+package internalfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+)
+
+func Test_registerWebhookEndpointHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		merchantID = uuid.Must(uuid.NewV4())
+		endpoint   = service.WebhookEndpoint{
+			ID:         uuid.Must(uuid.NewV4()).String(),
+			MerchantID: merchantID.String(),
+			URL:        "https://merchant.example.com/webhooks",
+			Secret:     "whsec_test",
+			CreatedAt:  "2022-10-18T20:00:00Z",
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       WebhookEndpointResponse{Endpoint: endpoint},
+		}
+		request = RegisterWebhookEndpointRequest{
+			Endpoint: service.RegisterWebhookEndpointParams{URL: "https://merchant.example.com/webhooks"},
+		}
+	)
+
+	webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("failed to unmarshal json")
+	}
+
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(reqBody))
+
+	setURLParams(req, map[string]string{urlParamMerchantUUID: merchantID.String()})
+
+	webhookService.EXPECT().RegisterWebhookEndpoint(
+		gomock.Eq(req.Context()),
+		gomock.Eq(merchantID),
+		gomock.Eq(&request.Endpoint),
+	).Return(&endpoint, nil)
+
+	resp, errRsp := registerWebhookEndpointHandler(rest.ChiNamedURLParamsGetter, webhookService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_listWebhookDeliveriesHandler(t *testing.T) {
+	t.Parallel()
+
+	deliveries := []service.WebhookDelivery{
+		{
+			ID:            uuid.Must(uuid.NewV4()).String(),
+			EndpointID:    uuid.Must(uuid.NewV4()).String(),
+			EventID:       uuid.Must(uuid.NewV4()).String(),
+			EventType:     "plan_completed",
+			Status:        "dead",
+			Attempts:      8,
+			NextAttemptAt: "2022-10-18T20:00:00Z",
+			LastError:     "webhook endpoint answered with status 500",
+			CreatedAt:     "2022-10-18T19:00:00Z",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus string
+	}{
+		{
+			name:       "dead by default",
+			target:     "/",
+			wantStatus: "dead",
+		},
+		{
+			name:       "status query",
+			target:     "/?status=pending",
+			wantStatus: "pending",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+
+			webhookService.EXPECT().
+				ListWebhookDeliveries(gomock.Eq(req.Context()), gomock.Eq(tt.wantStatus)).
+				Return(deliveries, nil)
+
+			resp, errRsp := listWebhookDeliveriesHandler(webhookService)(req)
+			if errRsp != nil {
+				t.Errorf("returned unexpected error response: %v", errRsp)
+			}
+
+			wantResponse := &handlerwrap.Response{
+				StatusCode: http.StatusOK,
+				Body:       ListWebhookDeliveriesResponse{Deliveries: deliveries},
+			}
+			if !reflect.DeepEqual(resp, wantResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+			}
+		})
+	}
+}
+
+func Test_replayWebhookDeliveryHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		deliveryID = uuid.Must(uuid.NewV4())
+		delivery   = service.WebhookDelivery{
+			ID:            deliveryID.String(),
+			EndpointID:    uuid.Must(uuid.NewV4()).String(),
+			EventID:       uuid.Must(uuid.NewV4()).String(),
+			EventType:     "plan_refunded",
+			Status:        "pending",
+			NextAttemptAt: "2022-10-18T20:00:00Z",
+			LastError:     "webhook endpoint answered with status 500",
+			CreatedAt:     "2022-10-18T19:00:00Z",
+		}
+		wantResponse = &handlerwrap.Response{
+			StatusCode: http.StatusOK,
+			Body:       WebhookDeliveryResponse{Delivery: delivery},
+		}
+	)
+
+	webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+
+	req := httptest.NewRequest("POST", "/", nil)
+
+	setURLParams(req, map[string]string{urlParamDeliveryUUID: deliveryID.String()})
+
+	webhookService.EXPECT().
+		ReplayWebhookDelivery(gomock.Eq(req.Context()), gomock.Eq(deliveryID), gomock.Any()).
+		Return(&delivery, nil)
+
+	resp, errRsp := replayWebhookDeliveryHandler(rest.ChiNamedURLParamsGetter, webhookService)(req)
+	if errRsp != nil {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+
+	if !reflect.DeepEqual(resp, wantResponse) {
+		t.Errorf("returned unexpected response. expected: %v, actual: %v", wantResponse, resp)
+	}
+}
+
+func Test_replayWebhookDeliveryHandler_NotDead(t *testing.T) {
+	t.Parallel()
+
+	deliveryID := uuid.Must(uuid.NewV4())
+	webhookService := servicemock.NewMockWebhookService(gomock.NewController(t))
+
+	req := httptest.NewRequest("POST", "/", nil)
+
+	setURLParams(req, map[string]string{urlParamDeliveryUUID: deliveryID.String()})
+
+	webhookService.EXPECT().
+		ReplayWebhookDelivery(gomock.Eq(req.Context()), gomock.Eq(deliveryID), gomock.Any()).
+		Return(nil, service.WebhookDeliveryNotDeadError{})
+
+	_, errRsp := replayWebhookDeliveryHandler(rest.ChiNamedURLParamsGetter, webhookService)(req)
+	if errRsp == nil || errRsp.StatusCode != http.StatusConflict {
+		t.Errorf("returned unexpected error response: %v", errRsp)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
 	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
 )
 
@@ -15,3 +16,23 @@ func ChiNamedURLParamsGetter(ctx context.Context, key string) (string, *handlerw
 
 	return v, nil
 }
+
+// ParseUUIDFormatParam reads the named URL param with paramsGetter, a value which is not a uuid is a bad request
+func ParseUUIDFormatParam(
+	ctx context.Context, paramsGetter handlerwrap.NamedURLParamsGetter, name string,
+) (*uuid.UUID, *handlerwrap.ErrorResponse) {
+	val, err := paramsGetter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	uuidVal, parseErr := uuid.FromString(val)
+	if parseErr != nil {
+		return nil, handlerwrap.ParsingParamError{
+			Name:  name,
+			Value: val,
+		}.ToErrorResponse()
+	}
+
+	return &uuidVal, nil
+}
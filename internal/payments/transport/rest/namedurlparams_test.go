@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gofrs/uuid"
 )
 
 func TestChiNamedURLParamsGetter(t *testing.T) {
@@ -39,3 +40,63 @@ func TestChiNamedURLParamsGetter(t *testing.T) {
 		)
 	}
 }
+
+func TestParseUUIDFormatParam(t *testing.T) {
+	t.Parallel()
+
+	validUUID := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name           string
+		value          string
+		wantUUID       *uuid.UUID
+		wantStatusCode int
+	}{
+		{
+			name:     "valid uuid",
+			value:    validUUID.String(),
+			wantUUID: &validUUID,
+		},
+		{
+			name:           "invalid uuid",
+			value:          "x",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "missing param",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			routerCtx := chi.NewRouteContext()
+			if tt.value != "" {
+				routerCtx.URLParams.Add("key", tt.value)
+			}
+
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routerCtx)
+
+			got, err := ParseUUIDFormatParam(ctx, ChiNamedURLParamsGetter, "key")
+			if tt.wantStatusCode != 0 {
+				if err == nil || err.StatusCode != tt.wantStatusCode {
+					t.Errorf("unexpected error: got %v, want status %v", err, tt.wantStatusCode)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if *got != *tt.wantUUID {
+				t.Errorf("expected: %v, actual: %v", tt.wantUUID, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package userfacing
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"golangreferenceapi/internal/payments/service"
 	"golangreferenceapi/internal/payments/transport/rest"
@@ -13,6 +15,7 @@ import (
 
 const (
 	paymentPlansDefaultLimit = 10
+	queryParamHistory        = "history"
 )
 
 // PaymentPlanResponse represents a specific payment plan
@@ -38,6 +41,7 @@ type Installments struct {
 // @Param offset query int64 false "Start index in the list" minimum(0)
 // @Param limit query int64 false "Number of items displayed" minimum(0) maximum(10)
 // @Param created_at_order query string false "Order by payment.created_at asc  OR desc" Enums(asc, desc) default(desc)
+// @Param history query bool false "Also list the installments superseded by reschedules" default(false)
 // @Success 200 {object} PaymentPlanResponse
 func listPaymentPlansHandler(paymentService service.PaymentPlanService) handlerwrap.TypedHandler {
 	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
@@ -52,7 +56,12 @@ func listPaymentPlansHandler(paymentService service.PaymentPlanService) handlerw
 			return nil, paginateErr
 		}
 
-		paymentPlans, serviceErr := paymentService.GetPaymentPlanByUserID(req.Context(), *uid)
+		withHistory, historyErr := parseHistoryURLQuery(req.URL)
+		if historyErr != nil {
+			return nil, historyErr
+		}
+
+		paymentPlans, serviceErr := paymentService.GetPaymentPlanByUserID(req.Context(), *uid, withHistory)
 		if serviceErr != nil {
 			return nil, rest.ServiceErrorToErrorResp(serviceErr)
 		}
@@ -62,3 +71,21 @@ func listPaymentPlansHandler(paymentService service.PaymentPlanService) handlerw
 		return resp, nil
 	}
 }
+
+// parseHistoryURLQuery the current schedule only is listed by default
+func parseHistoryURLQuery(u *url.URL) (bool, *handlerwrap.ErrorResponse) {
+	val := u.Query().Get(queryParamHistory)
+	if val == "" {
+		return false, nil
+	}
+
+	withHistory, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, handlerwrap.ParsingParamError{
+			Name:  queryParamHistory,
+			Value: val,
+		}.ToErrorResponse()
+	}
+
+	return withHistory, nil
+}
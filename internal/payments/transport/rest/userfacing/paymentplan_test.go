@@ -8,11 +8,13 @@ import (
 	"testing"
 	"time"
 
+	"golangreferenceapi/internal/payments"
 	"golangreferenceapi/internal/payments/common"
 	"golangreferenceapi/internal/payments/mock/servicemock"
 	"golangreferenceapi/internal/payments/service"
 	"golangreferenceapi/internal/payments/transport/rest"
 
+	"github.com/ericlagergren/decimal"
 	"github.com/monacohq/golang-common/transport/http/middleware/cryptouseruuid"
 
 	"github.com/gofrs/uuid"
@@ -27,22 +29,21 @@ func Test_listPaymentPlansHandler_SuccessCase(t *testing.T) {
 	installmentID := uuid.Must(uuid.NewV4())
 	expectedResult := []service.PaymentPlans{{
 		ID:          userID.String(),
-		Currency:    "usdc",
-		TotalAmount: "100",
+		TotalAmount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
 		Status:      "pending",
 		CreatedAt:   time.Now().Format(common.TimeFormat),
 		Installments: []service.PaymentPlanInstallment{{
-			ID:       installmentID.String(),
-			Amount:   "100",
-			Currency: "usdc",
-			DueAt:    time.Now().Format(common.TimeFormat),
-			Status:   "pending",
+			ID:     installmentID.String(),
+			Amount: payments.MustNewMoney(decimal.New(100, 0), "usdc"),
+			DueAt:  time.Now().Format(common.TimeFormat),
+			Status: "pending",
 		}},
 	}}
 	resultBody := PaymentPlanResponse{Payments: expectedResult}
 	tests := []struct {
 		name                  string
 		query                 string
+		withHistory           bool
 		expectedResponse      *handlerwrap.Response
 		expectedErrorResponse *handlerwrap.ErrorResponse
 	}{
@@ -54,6 +55,15 @@ func Test_listPaymentPlansHandler_SuccessCase(t *testing.T) {
 				StatusCode: http.StatusOK,
 			},
 		},
+		{
+			name:        "happy path with history",
+			query:       "history=true",
+			withHistory: true,
+			expectedResponse: &handlerwrap.Response{
+				Body:       resultBody,
+				StatusCode: http.StatusOK,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -66,7 +76,7 @@ func Test_listPaymentPlansHandler_SuccessCase(t *testing.T) {
 			paymentService := servicemock.NewMockPaymentPlanService(mockCtrl)
 
 			gomock.InOrder(
-				paymentService.EXPECT().GetPaymentPlanByUserID(gomock.Any(), userID).
+				paymentService.EXPECT().GetPaymentPlanByUserID(gomock.Any(), userID, tt.withHistory).
 					Return(expectedResult, nil).AnyTimes(),
 			)
 
@@ -105,6 +115,11 @@ func Test_listPaymentPlansHandler_ParamsError(t *testing.T) {
 			query:                 "offset=x&limit=x&created_at_order=x",
 			expectedErrorResponse: handlerwrap.ParsingParamError{}.ToErrorResponse(),
 		},
+		{
+			name:                  "invalid history query param",
+			query:                 "history=x",
+			expectedErrorResponse: handlerwrap.ParsingParamError{}.ToErrorResponse(),
+		},
 	}
 
 	mockCtrl := gomock.NewController(t)
@@ -168,7 +183,7 @@ func Test_listPaymentPlansHandler_InternalError(t *testing.T) {
 
 				gomock.InOrder(
 					paymentService.EXPECT().
-						GetPaymentPlanByUserID(gomock.Any(), userID).
+						GetPaymentPlanByUserID(gomock.Any(), userID, false).
 						Return(nil, tt.err).AnyTimes(),
 				)
 
@@ -0,0 +1,130 @@
+package userfacing
+
+import (
+	"net/http"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+	"github.com/monacohq/golang-common/transport/http/middleware/cryptouseruuid"
+)
+
+const (
+	urlParamPaymentUUID = "payment_uuid"
+)
+
+type QuotePaymentPlanPayoffResponse struct {
+	Payoff service.PaymentPlanPayoffQuote `json:"payoff"`
+}
+
+// PayOffPaymentPlanParams the paying user is the one of the request
+type PayOffPaymentPlanParams struct {
+	Amount             payments.Money `json:"amount"`
+	ProcessorReference string         `json:"processor_reference"`
+	PaidAt             time.Time      `json:"paid_at"`
+}
+
+type PayOffPaymentPlanRequest struct {
+	Payoff PayOffPaymentPlanParams `json:"payoff"`
+}
+
+type PayOffPaymentPlanResponse struct {
+	Payoff service.PaymentPlanPayoff `json:"payoff"`
+}
+
+// quotePaymentPlanPayoffHandler renders the amount due now to pay off one of the user's payment plans
+// @Summary Quotes a payment plan payoff
+// @Description returns the outstanding amount of every unpaid installment of a payment plan, late fees included
+// @Tags payment_plan
+// @Produce json
+// @Router /api/v1/payment-plans/{payment_uuid}/payoff [get]
+// @Param X-CRYPTO-USER-UUID header string true "User UUID"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} QuotePaymentPlanPayoffResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "invalid payment uuid"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is closed or already paid"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func quotePaymentPlanPayoffHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		uid, err := cryptouseruuid.GetUserUUID(req.Context())
+		if err != nil {
+			return nil, handlerwrap.NewErrorResponseFromCryptoUserUUIDError(err)
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		quote, serviceErr := paymentService.QuotePaymentPlanPayoff(req.Context(), *paymentUUID, *uid)
+		if serviceErr != nil {
+			return nil, rest.ServiceErrorToErrorResp(serviceErr)
+		}
+
+		return &handlerwrap.Response{
+			Body:       QuotePaymentPlanPayoffResponse{Payoff: *quote},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
+
+// payOffPaymentPlanHandler settles every unpaid installment of one of the user's payment plans at once
+// @Summary Pays off a payment plan
+// @Description records one settlement of the quoted amount, every unpaid installment is marked paid
+// @Description and a pending payment plan is completed
+// @Tags payment_plan
+// @Produce json
+// @Router /api/v1/payment-plans/{payment_uuid}/payoff [post]
+// @Param X-CRYPTO-USER-UUID header string true "User UUID"
+// @Param pay_off_payment_plan_request body PayOffPaymentPlanRequest true "Pay off payment plan reqBody"
+// @Param payment_uuid path string true "Payment Plan UUID"
+// @Success 200 {object} PayOffPaymentPlanResponse
+// @Failure 400 {object} handlerwrap.ErrorResponse "bad reqBody, invalid amount or missing processor reference"
+// @Failure 404 {object} handlerwrap.ErrorResponse "payment plan not found"
+// @Failure 409 {object} handlerwrap.ErrorResponse "payment plan is closed or already paid"
+// @Failure 422 {object} handlerwrap.ErrorResponse "currency mismatch or amount does not match the outstanding amount"
+// @Failure 500 {object} handlerwrap.ErrorResponse "internal error"
+func payOffPaymentPlanHandler(
+	paramsGetter handlerwrap.NamedURLParamsGetter,
+	paymentService service.PaymentPlanService,
+) handlerwrap.TypedHandler {
+	return func(req *http.Request) (*handlerwrap.Response, *handlerwrap.ErrorResponse) {
+		var request PayOffPaymentPlanRequest
+
+		uid, err := cryptouseruuid.GetUserUUID(req.Context())
+		if err != nil {
+			return nil, handlerwrap.NewErrorResponseFromCryptoUserUUIDError(err)
+		}
+
+		if errResp := handlerwrap.BindBody(req, &request); errResp != nil {
+			return nil, errResp
+		}
+
+		paymentUUID, respErr := rest.ParseUUIDFormatParam(req.Context(), paramsGetter, urlParamPaymentUUID)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		payoff, serviceErr := paymentService.PayOffPaymentPlan(req.Context(), *paymentUUID, &service.PaymentPlanPayoffParams{
+			UserID:             *uid,
+			Amount:             request.Payoff.Amount,
+			ProcessorReference: request.Payoff.ProcessorReference,
+			PaidAt:             request.Payoff.PaidAt,
+		})
+		if serviceErr != nil {
+			return nil, rest.ServiceErrorToErrorResp(serviceErr)
+		}
+
+		return &handlerwrap.Response{
+			Body:       PayOffPaymentPlanResponse{Payoff: *payoff},
+			StatusCode: http.StatusOK,
+		}, nil
+	}
+}
@@ -0,0 +1,232 @@
+package userfacing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golangreferenceapi/internal/payments"
+	"golangreferenceapi/internal/payments/common"
+	"golangreferenceapi/internal/payments/mock/servicemock"
+	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
+
+	"github.com/ericlagergren/decimal"
+	"github.com/monacohq/golang-common/transport/http/middleware/cryptouseruuid"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
+)
+
+func Test_quotePaymentPlanPayoffHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		userID        = uuid.Must(uuid.NewV4())
+		quote         = service.PaymentPlanPayoffQuote{
+			PaymentPlanID: paymentPlanID.String(),
+			Amount:        payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+			Installments: []service.PaymentPlanInstallment{
+				{
+					ID:     uuid.Must(uuid.NewV4()).String(),
+					Amount: payments.MustNewMoney(decimal.New(50, 0), "usdc"),
+					DueAt:  time.Now().UTC().Format(common.TimeFormat),
+					Status: "overdue",
+				},
+			},
+		}
+	)
+
+	tests := []struct {
+		name             string
+		paymentUUID      string
+		serviceErr       error
+		expectedResponse *handlerwrap.Response
+		expectedStatus   int
+	}{
+		{
+			name:        "happy path",
+			paymentUUID: paymentPlanID.String(),
+			expectedResponse: &handlerwrap.Response{
+				Body:       QuotePaymentPlanPayoffResponse{Payoff: quote},
+				StatusCode: http.StatusOK,
+			},
+		},
+		{
+			name:           "returns 400 if passing a invalid payment uuid",
+			paymentUUID:    "x",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "returns 404 if the payment plan is not found",
+			paymentUUID:    paymentPlanID.String(),
+			serviceErr:     service.PaymentRecordNotFoundError{},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "returns 409 if the payment plan is already paid",
+			paymentUUID:    paymentPlanID.String(),
+			serviceErr:     service.PaymentPlanAlreadyPaidError{},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+			if tt.serviceErr != nil {
+				paymentService.EXPECT().QuotePaymentPlanPayoff(gomock.Any(), paymentPlanID, userID).Return(nil, tt.serviceErr)
+			} else {
+				paymentService.EXPECT().QuotePaymentPlanPayoff(gomock.Any(), paymentPlanID, userID).
+					Return(&quote, nil).AnyTimes()
+			}
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req = req.WithContext(cryptouseruuid.SetUserUUID(req.Context(), &userID))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := quotePaymentPlanPayoffHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if tt.expectedStatus != 0 {
+				if errRsp == nil || errRsp.StatusCode != tt.expectedStatus {
+					t.Errorf("returned unexpected error response: got %v want status %v", errRsp, tt.expectedStatus)
+				}
+
+				return
+			}
+
+			if errRsp != nil {
+				t.Errorf("returned unexpected error response: %v", errRsp)
+			}
+
+			if !reflect.DeepEqual(resp, tt.expectedResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", tt.expectedResponse, resp)
+			}
+		})
+	}
+}
+
+func Test_payOffPaymentPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentPlanID = uuid.Must(uuid.NewV4())
+		userID        = uuid.Must(uuid.NewV4())
+		paidAt        = time.Now().UTC().Truncate(time.Second)
+		payoff        = service.PaymentPlanPayoff{
+			ID:                 uuid.Must(uuid.NewV4()).String(),
+			PaymentPlanID:      paymentPlanID.String(),
+			Amount:             payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt.Format(common.TimeFormat),
+			Status:             "complete",
+		}
+		request = PayOffPaymentPlanRequest{
+			Payoff: PayOffPaymentPlanParams{
+				Amount:             payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+				ProcessorReference: "psp-ref-1",
+				PaidAt:             paidAt,
+			},
+		}
+		// the paying user is taken from the request, never from the body
+		wantParams = &service.PaymentPlanPayoffParams{
+			UserID:             userID,
+			Amount:             payments.MustNewMoney(decimal.New(55, 0), "usdc"),
+			ProcessorReference: "psp-ref-1",
+			PaidAt:             paidAt,
+		}
+	)
+
+	validBody, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("failed to marshal json")
+	}
+
+	tests := []struct {
+		name             string
+		reqBody          []byte
+		paymentUUID      string
+		serviceErr       error
+		expectedResponse *handlerwrap.Response
+		expectedStatus   int
+	}{
+		{
+			name:        "happy path",
+			reqBody:     validBody,
+			paymentUUID: paymentPlanID.String(),
+			expectedResponse: &handlerwrap.Response{
+				Body:       PayOffPaymentPlanResponse{Payoff: payoff},
+				StatusCode: http.StatusOK,
+			},
+		},
+		{
+			name:           "returns 400 if passing a broken reqBody",
+			reqBody:        []byte(`{"payoff":`),
+			paymentUUID:    paymentPlanID.String(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "returns 400 if passing a invalid payment uuid",
+			reqBody:        validBody,
+			paymentUUID:    "x",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "returns 422 if the amount does not match the outstanding amount",
+			reqBody:        validBody,
+			paymentUUID:    paymentPlanID.String(),
+			serviceErr:     service.PayoffAmountMismatchError{},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
+
+			if tt.serviceErr != nil {
+				paymentService.EXPECT().PayOffPaymentPlan(gomock.Any(), paymentPlanID, wantParams).Return(nil, tt.serviceErr)
+			} else {
+				paymentService.EXPECT().PayOffPaymentPlan(gomock.Any(), paymentPlanID, wantParams).
+					Return(&payoff, nil).AnyTimes()
+			}
+
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(tt.reqBody))
+			req = req.WithContext(cryptouseruuid.SetUserUUID(req.Context(), &userID))
+
+			setURLParams(req, map[string]string{urlParamPaymentUUID: tt.paymentUUID})
+
+			resp, errRsp := payOffPaymentPlanHandler(rest.ChiNamedURLParamsGetter, paymentService)(req)
+			if tt.expectedStatus != 0 {
+				if errRsp == nil || errRsp.StatusCode != tt.expectedStatus {
+					t.Errorf("returned unexpected error response: got %v want status %v", errRsp, tt.expectedStatus)
+				}
+
+				return
+			}
+
+			if errRsp != nil {
+				t.Errorf("returned unexpected error response: %v", errRsp)
+			}
+
+			if !reflect.DeepEqual(resp, tt.expectedResponse) {
+				t.Errorf("returned unexpected response. expected: %v, actual: %v", tt.expectedResponse, resp)
+			}
+		})
+	}
+}
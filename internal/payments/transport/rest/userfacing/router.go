@@ -2,6 +2,7 @@ package userfacing
 
 import (
 	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/monacohq/golang-common/transport/http/handlerwrap/v3"
@@ -12,11 +13,17 @@ import (
 func AddRoutes(
 	router chi.Router,
 	log *zerolog.Logger,
+	paramsGetter handlerwrap.NamedURLParamsGetter,
 	paymentService service.PaymentPlanService,
 	version string,
 ) {
 	router.Route("/api/"+version, func(r chi.Router) {
 		r.Use(cryptouseruuid.UserUUID(log))
+		r.Use(rest.UserAuditActor)
 		r.Get("/payment-plans", handlerwrap.Wrapper(log, listPaymentPlansHandler(paymentService)))
+		r.Get("/payment-plans/{payment_uuid}/payoff",
+			handlerwrap.Wrapper(log, quotePaymentPlanPayoffHandler(paramsGetter, paymentService)))
+		r.Post("/payment-plans/{payment_uuid}/payoff",
+			handlerwrap.Wrapper(log, payOffPaymentPlanHandler(paramsGetter, paymentService)))
 	})
 }
@@ -3,12 +3,14 @@ package userfacing
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/monacohq/golang-common/transport/http/middleware/cryptouseruuid"
 
 	"golangreferenceapi/internal/payments/mock/servicemock"
 	"golangreferenceapi/internal/payments/service"
+	"golangreferenceapi/internal/payments/transport/rest"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gofrs/uuid"
@@ -25,6 +27,7 @@ func TestAddRoutes(t *testing.T) {
 		name                   string
 		httpMethod             string
 		urlPath                string
+		reqBody                string
 		expectedHTTPStatusCode int
 	}{
 		{
@@ -33,12 +36,36 @@ func TestAddRoutes(t *testing.T) {
 			urlPath:                "/api/v1/payment-plans",
 			expectedHTTPStatusCode: http.StatusOK,
 		},
+		{
+			name:                   "happy path for quoting a payoff",
+			httpMethod:             "GET",
+			urlPath:                "/api/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/payoff",
+			expectedHTTPStatusCode: http.StatusOK,
+		},
+		{
+			name:       "happy path for paying off",
+			httpMethod: "POST",
+			urlPath:    "/api/v1/payment-plans/03baa9e6-6ed6-4868-9ef9-b99c8452f270/payoff",
+			reqBody: `{
+						"payoff": {
+							"amount": {"value": "100", "currency": "usdc"},
+							"processor_reference": "psp-ref-1"
+						}
+					}`,
+			expectedHTTPStatusCode: http.StatusOK,
+		},
 	}
 
 	userID := uuid.Must(uuid.NewV4())
 
 	paymentService := servicemock.NewMockPaymentPlanService(gomock.NewController(t))
-	paymentService.EXPECT().GetPaymentPlanByUserID(gomock.Any(), userID).Return([]service.PaymentPlans{}, nil)
+	paymentService.EXPECT().GetPaymentPlanByUserID(gomock.Any(), userID, false).Return([]service.PaymentPlans{}, nil)
+	paymentService.EXPECT().
+		QuotePaymentPlanPayoff(gomock.Any(), gomock.Any(), userID).
+		Return(&service.PaymentPlanPayoffQuote{}, nil)
+	paymentService.EXPECT().
+		PayOffPaymentPlan(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&service.PaymentPlanPayoff{}, nil)
 
 	for _, tt := range tests {
 		tt := tt
@@ -47,12 +74,12 @@ func TestAddRoutes(t *testing.T) {
 			t.Parallel()
 
 			r := chi.NewRouter()
-			AddRoutes(r, &log, paymentService, "v1")
+			AddRoutes(r, &log, rest.ChiNamedURLParamsGetter, paymentService, "v1")
 
 			srv := httptest.NewServer(r)
 			defer srv.Close()
 
-			req := httptest.NewRequest(tt.httpMethod, srv.URL+tt.urlPath, nil)
+			req := httptest.NewRequest(tt.httpMethod, srv.URL+tt.urlPath, strings.NewReader(tt.reqBody))
 			setRequestHeaderUserID(req, userID.String())
 			rr := httptest.NewRecorder()
 
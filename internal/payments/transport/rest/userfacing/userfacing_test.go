@@ -1,10 +1,13 @@
 package userfacing
 
 import (
+	"context"
 	"flag"
+	"net/http"
 	"os"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/goleak"
 )
 
@@ -20,3 +23,13 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func setURLParams(req *http.Request, params map[string]string) {
+	ctx := chi.NewRouteContext()
+
+	for k, v := range params {
+		ctx.URLParams.Add(k, v)
+	}
+
+	*req = *req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+}
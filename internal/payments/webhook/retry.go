@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const backoffFactor = 2
+
+var ErrInvalidRetryPolicy = errors.New("invalid webhook retry policy")
+
+// RetryPolicy waits BaseDelay after the first failed attempt and twice as long after each
+// following one, up to MaxDelay, a delivery is dead once MaxAttempts attempts failed
+type RetryPolicy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts int
+}
+
+func NewRetryPolicy(baseDelay, maxDelay time.Duration, maxAttempts int) (*RetryPolicy, error) {
+	if baseDelay <= 0 {
+		return nil, fmt.Errorf("%w: baseDelay %s", ErrInvalidRetryPolicy, baseDelay)
+	}
+
+	if maxDelay < baseDelay {
+		return nil, fmt.Errorf("%w: maxDelay %s", ErrInvalidRetryPolicy, maxDelay)
+	}
+
+	if maxAttempts <= 0 {
+		return nil, fmt.Errorf("%w: maxAttempts %d", ErrInvalidRetryPolicy, maxAttempts)
+	}
+
+	return &RetryPolicy{BaseDelay: baseDelay, MaxDelay: maxDelay, MaxAttempts: maxAttempts}, nil
+}
+
+// Exhausted tells whether a delivery which failed attempts times is dead
+func (rp *RetryPolicy) Exhausted(attempts int) bool {
+	return attempts >= rp.MaxAttempts
+}
+
+// Delay is how long to wait before attempting again a delivery which failed attempts times
+func (rp *RetryPolicy) Delay(attempts int) time.Duration {
+	delay := rp.BaseDelay
+
+	for i := 1; i < attempts; i++ {
+		delay *= backoffFactor
+
+		if delay >= rp.MaxDelay {
+			return rp.MaxDelay
+		}
+	}
+
+	return delay
+}
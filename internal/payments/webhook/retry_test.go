@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		baseDelay   time.Duration
+		maxDelay    time.Duration
+		maxAttempts int
+		wantErr     error
+	}{
+		{
+			name:        "happy path",
+			baseDelay:   time.Minute,
+			maxDelay:    time.Hour,
+			maxAttempts: 5,
+		},
+		{
+			name:        "no base delay",
+			maxDelay:    time.Hour,
+			maxAttempts: 5,
+			wantErr:     ErrInvalidRetryPolicy,
+		},
+		{
+			name:        "max delay below base delay",
+			baseDelay:   time.Hour,
+			maxDelay:    time.Minute,
+			maxAttempts: 5,
+			wantErr:     ErrInvalidRetryPolicy,
+		},
+		{
+			name:      "no attempts",
+			baseDelay: time.Minute,
+			maxDelay:  time.Hour,
+			wantErr:   ErrInvalidRetryPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewRetryPolicy(tt.baseDelay, tt.maxDelay, tt.maxAttempts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewRetryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && (got.BaseDelay != tt.baseDelay || got.MaxDelay != tt.maxDelay) {
+				t.Errorf("NewRetryPolicy() = %v", got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, MaxAttempts: 6}
+
+	tests := []struct {
+		attempts      int
+		want          time.Duration
+		wantExhausted bool
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 3, want: 4 * time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 5, want: 10 * time.Minute},
+		{attempts: 6, want: 10 * time.Minute, wantExhausted: true},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Delay(tt.attempts); got != tt.want {
+			t.Errorf("RetryPolicy.Delay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+
+		if got := policy.Exhausted(tt.attempts); got != tt.wantExhausted {
+			t.Errorf("RetryPolicy.Exhausted(%d) = %v, want %v", tt.attempts, got, tt.wantExhausted)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Sender sends a delivery to its endpoint, an error means the delivery must be attempted again
+type Sender interface {
+	Send(ctx context.Context, endpoint *Endpoint, delivery *Delivery, now time.Time) error
+}
+
+var _ Sender = (*HTTPSender)(nil)
+
+// Message is the body of a webhook request, Data is the payload of the event
+type Message struct {
+	ID   uuid.UUID       `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// StatusError the endpoint answered with a status other than 2xx
+type StatusError struct {
+	StatusCode int
+}
+
+func (se StatusError) Error() string {
+	return fmt.Sprintf("webhook endpoint answered with status %d", se.StatusCode)
+}
+
+// HTTPSender POSTs the deliveries as JSON, a request taking longer than its client timeout fails
+type HTTPSender struct {
+	client *http.Client
+}
+
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send the Webhook-Id header is the event ID so the receiver can drop the events it already processed
+func (hs *HTTPSender) Send(ctx context.Context, endpoint *Endpoint, delivery *Delivery, now time.Time) error {
+	body, err := json.Marshal(Message{ID: delivery.EventID, Type: delivery.EventType, Data: delivery.Payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, delivery.EventID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, now, body))
+
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// drained so the connection is reused
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Errorf("failed to read webhook response: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestHTTPSender_Send(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		now      = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		delivery = &Delivery{
+			ID:        uuid.Must(uuid.NewV4()),
+			EventID:   uuid.Must(uuid.NewV4()),
+			EventType: "plan_completed",
+			Payload:   json.RawMessage(`{"status":"completed"}`),
+		}
+	)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    error
+	}{
+		{
+			name:       "happy path",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusInternalServerError,
+			wantErr:    StatusError{StatusCode: http.StatusInternalServerError},
+		},
+		{
+			name:       "not modified",
+			statusCode: http.StatusNotModified,
+			wantErr:    StatusError{StatusCode: http.StatusNotModified},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			endpoint := &Endpoint{Secret: "whsec_test"}
+			received := make(chan Message, 1)
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					t.Errorf("failed to read request: %v", err)
+				}
+
+				timestamp, signature := req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature)
+				if err := Verify(endpoint.Secret, timestamp, signature, body, now, time.Minute); err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+
+				if req.Header.Get(HeaderID) != delivery.EventID.String() {
+					t.Errorf("%s header = %v, want %v", HeaderID, req.Header.Get(HeaderID), delivery.EventID)
+				}
+
+				var message Message
+				if err := json.Unmarshal(body, &message); err != nil {
+					t.Errorf("failed to decode message: %v", err)
+				}
+
+				received <- message
+
+				rw.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			endpoint.URL = server.URL
+
+			err := NewHTTPSender(time.Second).Send(ctx, endpoint, delivery, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HTTPSender.Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			message := <-received
+			if message.ID != delivery.EventID || message.Type != delivery.EventType ||
+				string(message.Data) != string(delivery.Payload) {
+				t.Errorf("received %v, want the event of the delivery", message)
+			}
+		})
+	}
+}
+
+func TestHTTPSender_SendUnreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := &Endpoint{URL: server.URL, Secret: "whsec_test"}
+	server.Close()
+
+	err := NewHTTPSender(time.Second).Send(context.Background(), endpoint, &Delivery{}, time.Now())
+	if err == nil {
+		t.Error("HTTPSender.Send() error = nil, want the endpoint unreachable")
+	}
+}
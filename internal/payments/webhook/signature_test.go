@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSecret(t *testing.T) {
+	t.Parallel()
+
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret() error = %v", err)
+	}
+
+	other, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret() error = %v", err)
+	}
+
+	if !strings.HasPrefix(secret, "whsec_") || len(secret) != len("whsec_")+2*secretSize {
+		t.Errorf("NewSecret() = %q, want whsec_ followed by %d hex encoded bytes", secret, secretSize)
+	}
+
+	if secret == other {
+		t.Error("NewSecret() generated the same secret twice")
+	}
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	got := Sign("whsec_test", time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC), []byte(`{"id":1}`))
+
+	want := "sha256=3a7cb1c21040b00af7569074d184a594afa438d38a44c42f69bfcb45213135a2"
+	if got != want {
+		t.Errorf("Sign() = %v, want %v", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		secret    = "whsec_test"
+		sentAt    = time.Date(2022, 7, 10, 0, 0, 0, 0, time.UTC)
+		body      = []byte(`{"id":1}`)
+		signature = Sign(secret, sentAt, body)
+		tolerance = 5 * time.Minute
+	)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   error
+	}{
+		{
+			name:      "happy path",
+			secret:    secret,
+			timestamp: "1657411200",
+			signature: signature,
+			body:      body,
+			now:       sentAt.Add(tolerance),
+		},
+		{
+			name:      "other secret",
+			secret:    "whsec_other",
+			timestamp: "1657411200",
+			signature: signature,
+			body:      body,
+			now:       sentAt,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "tampered body",
+			secret:    secret,
+			timestamp: "1657411200",
+			signature: signature,
+			body:      []byte(`{"id":2}`),
+			now:       sentAt,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "tampered timestamp",
+			secret:    secret,
+			timestamp: "1657411201",
+			signature: signature,
+			body:      body,
+			now:       sentAt,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "missing prefix",
+			secret:    secret,
+			timestamp: "1657411200",
+			signature: strings.TrimPrefix(signature, "sha256="),
+			body:      body,
+			now:       sentAt,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "timestamp too old",
+			secret:    secret,
+			timestamp: "1657411200",
+			signature: signature,
+			body:      body,
+			now:       sentAt.Add(tolerance + time.Second),
+			wantErr:   ErrInvalidTimestamp,
+		},
+		{
+			name:      "timestamp in the future",
+			secret:    secret,
+			timestamp: "1657411200",
+			signature: signature,
+			body:      body,
+			now:       sentAt.Add(-tolerance - time.Second),
+			wantErr:   ErrInvalidTimestamp,
+		},
+		{
+			name:      "timestamp not a number",
+			secret:    secret,
+			timestamp: "yesterday",
+			signature: signature,
+			body:      body,
+			now:       sentAt,
+			wantErr:   ErrInvalidTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, tt.now, tolerance)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
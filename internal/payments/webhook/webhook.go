@@ -0,0 +1,85 @@
+// Package webhook delivers the events of the payment domain to the endpoints merchants register.
+// Every event of a plan the merchant originated is delivered to each of its endpoints, signed with the
+// endpoint secret, and attempted again with an exponential backoff until it is delivered or runs out of attempts.
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"golangreferenceapi/internal/payments/outbox"
+
+	"github.com/gofrs/uuid"
+)
+
+// the statuses of a delivery, the webhook_delivery_status database enum
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+// Endpoint Secret signs the payloads sent to URL
+type Endpoint struct {
+	ID         uuid.UUID
+	MerchantID uuid.UUID
+	URL        string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// Delivery is an event to send to an endpoint, NextAttemptAt is when a pending one is attempted
+// and LastError why the latest attempt failed
+type Delivery struct {
+	ID            uuid.UUID
+	EndpointID    uuid.UUID
+	EventID       uuid.UUID
+	EventType     string
+	Payload       json.RawMessage
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type CreateEndpointParams struct {
+	MerchantID uuid.UUID
+	URL        string
+	Secret     string
+}
+
+type CreateDeliveryParams struct {
+	EndpointID    uuid.UUID
+	EventID       uuid.UUID
+	EventType     string
+	Payload       json.RawMessage
+	NextAttemptAt time.Time
+}
+
+type UpdateDeliveryParams struct {
+	ID            uuid.UUID
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DeliveredAt   *time.Time
+}
+
+// ListDueDeliveriesParams selects up to Limit pending deliveries to attempt at Before or earlier
+type ListDueDeliveriesParams struct {
+	Before time.Time
+	Limit  int32
+}
+
+// Subscribed tells whether the events of eventType are delivered to the merchants
+func Subscribed(eventType string) bool {
+	switch eventType {
+	case outbox.EventPlanCompleted, outbox.EventPlanPaidOff, outbox.EventPlanRefunded, outbox.EventInstallmentOverdue:
+		return true
+	default:
+		return false
+	}
+}